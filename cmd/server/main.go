@@ -2,32 +2,57 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/luno/luno-mcp/internal/alerts"
 	"github.com/luno/luno-mcp/internal/config"
 	"github.com/luno/luno-mcp/internal/logging"
+	"github.com/luno/luno-mcp/internal/orderwatch"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/luno/luno-mcp/internal/redact"
+	"github.com/luno/luno-mcp/internal/schedule"
 	"github.com/luno/luno-mcp/internal/server"
+	"github.com/luno/luno-mcp/internal/support"
+	"github.com/luno/luno-mcp/internal/tracing"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 )
 
 const (
 	appName    = "luno-mcp"
 	appVersion = "0.1.0"
+
+	// tracingFlushTimeout is how long exporting the remaining spans may take on exit
+	tracingFlushTimeout = 5 * time.Second
+
+	// Formats of console logs
+	logFormatText = "text"
+	logFormatJSON = "json"
 )
 
 // CliFlags holds command line flag values
 type CliFlags struct {
-	TransportType string
-	SSEAddr       string
-	LunoDomain    string
-	LogLevel      string
+	TransportType   string
+	SSEAddr         string
+	LunoDomain      string
+	Environment     string
+	Backend         string
+	RecordPath      string
+	ReplayPath      string
+	LogLevel        string
+	LogFormat       string
+	ShutdownTimeout time.Duration
+	ConfigPath      string
+	TLS             server.TLSOptions
 }
 
 // loadEnvFile attempts to load environment variables from various .env file locations
@@ -59,39 +84,100 @@ func parseFlags() CliFlags {
 	transportType := flag.String("transport", "stdio", "Transport type (stdio or sse)")
 	sseAddr := flag.String("sse-address", "localhost:8080", "Address for SSE transport")
 	lunoDomain := flag.String("domain", "", "Luno API domain (default: api.luno.com)")
+	environment := flag.String("env", "", "Luno environment (production or staging), which selects the API domain")
+	backend := flag.String("backend", "", "Backend to send Luno API calls to (live or fake), fake runs offline without credentials")
+	recordPath := flag.String("record", "", "File to record Luno API calls and responses to, for replaying later")
+	replayPath := flag.String("replay", "", "Recording made with -record to serve Luno API calls from, without calling Luno")
 	logLevel := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	logFormat := flag.String("log-format", logFormatText, "Format of console logs (text or json)")
+	configPath := flag.String("config", "", "Path to a YAML config file, environment variables override its settings")
+	shutdownTimeout := flag.Duration("shutdown-timeout", server.DefaultShutdownTimeout, "How long the SSE server waits for in-flight requests on shutdown")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate file for serving the SSE transport over HTTPS")
+	tlsKey := flag.String("tls-key", "", "PEM private key file for the -tls-cert certificate")
+	tlsSelfSigned := flag.Bool("tls-self-signed", false, "Serve the SSE transport over HTTPS with a generated certificate, for development only")
 	flag.Parse()
 
 	return CliFlags{
-		TransportType: *transportType,
-		SSEAddr:       *sseAddr,
-		LunoDomain:    *lunoDomain,
-		LogLevel:      *logLevel,
+		TransportType:   *transportType,
+		SSEAddr:         *sseAddr,
+		LunoDomain:      *lunoDomain,
+		Environment:     *environment,
+		Backend:         *backend,
+		RecordPath:      *recordPath,
+		ReplayPath:      *replayPath,
+		LogLevel:        *logLevel,
+		LogFormat:       *logFormat,
+		ShutdownTimeout: *shutdownTimeout,
+		ConfigPath:      *configPath,
+		TLS: server.TLSOptions{
+			CertFile:   *tlsCert,
+			KeyFile:    *tlsKey,
+			SelfSigned: *tlsSelfSigned,
+		},
+	}
+}
+
+// logWriter returns where console logs go for a transport. The stdio
+// transport uses stdout for the protocol, so logs must go to stderr.
+func logWriter(transport string) io.Writer {
+	if transport == "stdio" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// newConsoleHandler creates the handler for console logs in the given format,
+// which is text unless it is json
+func newConsoleHandler(format string, w io.Writer, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == logFormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// checkLogFormat returns an error if format is not a console log format
+func checkLogFormat(format string) error {
+	if format != logFormatText && format != logFormatJSON {
+		return fmt.Errorf("invalid log format: %s. Must be '%s' or '%s'", format, logFormatText, logFormatJSON)
 	}
+	return nil
 }
 
 // setupLogger creates and configures the basic console logger
-func setupLogger(logLevel string) *slog.Logger {
+func setupLogger(logLevel, logFormat string, w io.Writer) *slog.Logger {
 	level := parseLogLevel(logLevel)
-	consoleHandler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})
-	logger := slog.New(consoleHandler)
+	logger := slog.New(newConsoleHandler(logFormat, w, level))
 	slog.SetDefault(logger)
 	return logger
 }
 
-// setupEnhancedLogger creates an enhanced logger with MCP notification capability
-func setupEnhancedLogger(mcpServer *mcpserver.MCPServer, logLevel string) {
-	level := parseLogLevel(logLevel)
-	consoleHandler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+// setupEnhancedLogger creates an enhanced logger with MCP notification capability.
+// Console logs are written in format, like the basic logger's. The console
+// and MCP handlers share level, so changing it changes both.
+// Any extra handlers also receive every log record. Records are redacted
+// before any handler sees them, and labelled with the Luno environment if
+// it is known.
+func setupEnhancedLogger(mcpServer *mcpserver.MCPServer, level *slog.LevelVar, format string, w io.Writer, redactor *redact.Redactor, env config.Environment, extraHandlers ...slog.Handler) {
+	consoleHandler := newConsoleHandler(format, w, level)
 	mcpHandler := logging.NewMCPNotificationHandler(mcpServer, level)
-	multiHandler := logging.NewMultiHandler(consoleHandler, mcpHandler)
-	enhancedLogger := slog.New(multiHandler)
+	handlers := append([]slog.Handler{consoleHandler, mcpHandler}, extraHandlers...)
+	multiHandler := logging.NewMultiHandler(handlers...)
+	enhancedLogger := slog.New(redactor.Handler(multiHandler))
+	if env != "" {
+		enhancedLogger = enhancedLogger.With(slog.String("env", string(env)))
+	}
 	slog.SetDefault(enhancedLogger)
 }
 
-// createMCPServer creates and configures the MCP server
-func createMCPServer(cfg *config.Config) *mcpserver.MCPServer {
-	return server.NewMCPServer(appName, appVersion, cfg, logging.MCPHooks())
+// createMCPServer creates and configures the MCP server. Clients can
+// change logLevel with logging/setLevel.
+func createMCPServer(cfg *config.Config, logLevel *slog.LevelVar) *mcpserver.MCPServer {
+	hooks := logging.MCPHooks(logLevel)
+	if cfg.Support != nil {
+		cfg.Support.AddHooks(hooks)
+	}
+	return server.NewMCPServer(appName, appVersion, cfg, hooks)
 }
 
 // setupSignalHandling creates a context that will be cancelled on interrupt signals
@@ -109,15 +195,68 @@ func setupSignalHandling() (context.Context, context.CancelFunc) {
 	return ctx, cancel
 }
 
+// watchReloads reloads the settings that can change without a restart each
+// time the process receives SIGHUP, until ctx is done
+func watchReloads(ctx context.Context, mcpServer *mcpserver.MCPServer, cfg *config.Config, logLevel *slog.LevelVar) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigCh:
+				reloadSettings(mcpServer, cfg, logLevel)
+			}
+		}
+	}()
+}
+
+// reloadSettings reloads the configuration and registers the tools it now
+// allows. The current settings are kept if the new ones are invalid. A level
+// a client set with logging/setLevel is kept unless the configured level
+// changed.
+func reloadSettings(mcpServer *mcpserver.MCPServer, cfg *config.Config, logLevel *slog.LevelVar) {
+	prev := cfg.Settings()
+	settings, err := cfg.Reload()
+	if err != nil {
+		slog.Error("Failed to reload settings, keeping the current ones", slog.String("error", err.Error()))
+		return
+	}
+	if settings.LogLevel != prev.LogLevel {
+		logLevel.Set(settings.LogLevel)
+	}
+	server.ReloadTools(mcpServer, cfg, appName, appVersion)
+}
+
+// flagGiven reports whether the named flag was set on the command line
+func flagGiven(name string) bool {
+	given := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			given = true
+		}
+	})
+	return given
+}
+
 // startServer starts the appropriate server based on transport type
-func startServer(ctx context.Context, mcpServer *mcpserver.MCPServer, flags CliFlags) error {
+func startServer(ctx context.Context, mcpServer *mcpserver.MCPServer, cfg *config.Config, flags CliFlags) error {
 	switch flags.TransportType {
 	case "stdio":
 		slog.Info("Starting Luno MCP server using stdio transport")
 		return server.ServeStdio(ctx, mcpServer)
 	case "sse":
+		tlsConfig, err := flags.TLS.Config(flags.SSEAddr)
+		if err != nil {
+			return err
+		}
+		if flags.TLS.SelfSigned {
+			slog.Warn("Using a self-signed TLS certificate, which is only suitable for development")
+		}
 		slog.Info("Starting Luno MCP server using SSE transport", slog.String("address", flags.SSEAddr))
-		return server.ServeSSE(ctx, mcpServer, flags.SSEAddr)
+		return server.ServeSSE(ctx, mcpServer, cfg, flags.SSEAddr, tlsConfig, flags.ShutdownTimeout)
 	default:
 		return fmt.Errorf("invalid transport type: %s. Must be 'stdio' or 'sse'", flags.TransportType)
 	}
@@ -130,7 +269,46 @@ func main() {
 	flags := parseFlags()
 
 	// Set up basic logger first
-	setupLogger(flags.LogLevel)
+	if err := checkLogFormat(flags.LogFormat); err != nil {
+		log.Fatal(err)
+	}
+	setupLogger(flags.LogLevel, flags.LogFormat, logWriter(flags.TransportType))
+
+	// The -log-level flag takes precedence over LUNO_LOG_LEVEL and the config
+	// file, when it is given
+	if flagGiven("log-level") {
+		if err := os.Setenv(config.EnvLunoLogLevel, flags.LogLevel); err != nil {
+			log.Fatalf("Failed to set %s: %v", config.EnvLunoLogLevel, err)
+		}
+	}
+
+	// Settings from a config file fill in any environment variables that aren't set
+	if flags.ConfigPath != "" {
+		if err := config.ApplyFile(flags.ConfigPath); err != nil {
+			log.Fatalf("Failed to load config file: %v", err)
+		}
+	}
+
+	// The -env flag takes precedence over LUNO_ENV
+	if flags.Environment != "" {
+		if err := os.Setenv(config.EnvLunoEnv, flags.Environment); err != nil {
+			log.Fatalf("Failed to set %s: %v", config.EnvLunoEnv, err)
+		}
+	}
+
+	// The -backend, -record and -replay flags take precedence over their variables
+	for env, value := range map[string]string{
+		config.EnvLunoBackend:    flags.Backend,
+		config.EnvLunoRecordPath: flags.RecordPath,
+		config.EnvLunoReplayPath: flags.ReplayPath,
+	} {
+		if value == "" {
+			continue
+		}
+		if err := os.Setenv(env, value); err != nil {
+			log.Fatalf("Failed to set %s: %v", env, err)
+		}
+	}
 
 	// Load configuration
 	cfg, err := config.Load(flags.LunoDomain)
@@ -138,18 +316,68 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Create MCP server with logging hooks
-	mcpServer := createMCPServer(cfg)
+	cfg.Transport = flags.TransportType
+	if cfg.Audit != nil {
+		defer cfg.Audit.Close()
+	}
+	if cfg.Streams != nil {
+		defer cfg.Streams.Close()
+	}
+	if cfg.Recorder != nil {
+		defer cfg.Recorder.Close()
+	}
+
+	// Export traces when an OTLP endpoint is configured
+	if cfg.Tracing {
+		shutdownTracing, err := tracing.Setup(context.Background(), appVersion)
+		if err != nil {
+			log.Fatalf("Failed to set up tracing: %v", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				slog.Warn("Failed to flush traces", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	// Record recent activity for support bundles
+	cfg.Support = support.NewRecorder(appName, appVersion)
+
+	// Create MCP server with logging hooks, starting at the configured level
+	// until a client sets another
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(cfg.Settings().LogLevel)
+	mcpServer := createMCPServer(cfg, logLevel)
 
 	// Now enhance the logger with MCP notification capability
-	setupEnhancedLogger(mcpServer, flags.LogLevel)
+	setupEnhancedLogger(mcpServer, logLevel, flags.LogFormat, logWriter(flags.TransportType), cfg.Redactor, cfg.Environment, cfg.Support.LogHandler(slog.LevelDebug))
 
 	// Setup signal handling for graceful shutdown
 	ctx, cancel := setupSignalHandling()
 	defer cancel()
 
+	// Reload tool filters, allowed pairs, limits and the log level on SIGHUP
+	watchReloads(ctx, mcpServer, cfg, logLevel)
+
+	// Check price alerts, watched orders, schedules and account resources, and
+	// send undelivered notifications again, in the background until shutdown
+	alertsDone := server.WatchAlerts(ctx, mcpServer, cfg, alerts.DefaultPollInterval)
+	ordersDone := server.WatchOrders(ctx, mcpServer, cfg, orderwatch.DefaultPollInterval)
+	schedulesDone := server.WatchSchedules(ctx, mcpServer, cfg, schedule.DefaultPollInterval)
+	resourcesDone := server.WatchResources(ctx, mcpServer, cfg, cfg.ResourceRefresh)
+	outboxDone := server.WatchOutbox(ctx, mcpServer, cfg, outbox.DefaultRetryInterval)
+
 	// Start the server with the selected transport
-	if err := startServer(ctx, mcpServer, flags); err != nil {
+	err = startServer(ctx, mcpServer, cfg, flags)
+	cancel()
+	<-alertsDone
+	<-ordersDone
+	<-schedulesDone
+	<-resourcesDone
+	<-outboxDone
+	if err != nil && !errors.Is(err, context.Canceled) {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/server"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,40 +93,109 @@ func TestParseFlags(t *testing.T) {
 			name: "default flags",
 			args: []string{},
 			expected: CliFlags{
-				TransportType: testTransportStdio,
-				SSEAddr:       testDefaultSSEAddr,
-				LunoDomain:    "",
-				LogLevel:      testLogLevelInfo,
+				TransportType:   testTransportStdio,
+				SSEAddr:         testDefaultSSEAddr,
+				LunoDomain:      "",
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
 			},
 		},
 		{
 			name: "custom stdio flags",
 			args: []string{"-transport=stdio", "-log-level=debug"},
 			expected: CliFlags{
-				TransportType: testTransportStdio,
-				SSEAddr:       testDefaultSSEAddr,
-				LunoDomain:    "",
-				LogLevel:      testLogLevelDebug,
+				TransportType:   testTransportStdio,
+				SSEAddr:         testDefaultSSEAddr,
+				LunoDomain:      "",
+				LogLevel:        testLogLevelDebug,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
 			},
 		},
 		{
 			name: "sse transport with custom address",
 			args: []string{"-transport=sse", "-sse-address=" + testCustomSSEAddr, "-domain=" + testStagingDomain},
 			expected: CliFlags{
-				TransportType: testTransportSSE,
-				SSEAddr:       testCustomSSEAddr,
-				LunoDomain:    testStagingDomain,
-				LogLevel:      testLogLevelInfo,
+				TransportType:   testTransportSSE,
+				SSEAddr:         testCustomSSEAddr,
+				LunoDomain:      testStagingDomain,
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
 			},
 		},
 		{
 			name: "all custom flags",
-			args: []string{"-transport=sse", "-sse-address=" + testCustomSSEAddrAlt, "-domain=" + testCustomDomain, "-log-level=error"},
+			args: []string{"-transport=sse", "-sse-address=" + testCustomSSEAddrAlt, "-domain=" + testCustomDomain, "-log-level=error", "-shutdown-timeout=3s", "-config=luno.yaml"},
 			expected: CliFlags{
-				TransportType: testTransportSSE,
-				SSEAddr:       testCustomSSEAddrAlt,
-				LunoDomain:    testCustomDomain,
-				LogLevel:      testLogLevelError,
+				TransportType:   testTransportSSE,
+				SSEAddr:         testCustomSSEAddrAlt,
+				LunoDomain:      testCustomDomain,
+				LogLevel:        testLogLevelError,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: 3 * time.Second,
+				ConfigPath:      "luno.yaml",
+			},
+		},
+		{
+			name: "environment flag",
+			args: []string{"-env=staging"},
+			expected: CliFlags{
+				TransportType:   testTransportStdio,
+				SSEAddr:         testDefaultSSEAddr,
+				Environment:     "staging",
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
+			},
+		},
+		{
+			name: "backend flag",
+			args: []string{"-backend=fake"},
+			expected: CliFlags{
+				TransportType:   testTransportStdio,
+				SSEAddr:         testDefaultSSEAddr,
+				Backend:         "fake",
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
+			},
+		},
+		{
+			name: "record and replay flags",
+			args: []string{"-record=calls.jsonl", "-replay=replay.jsonl"},
+			expected: CliFlags{
+				TransportType:   testTransportStdio,
+				SSEAddr:         testDefaultSSEAddr,
+				RecordPath:      "calls.jsonl",
+				ReplayPath:      "replay.jsonl",
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
+			},
+		},
+		{
+			name: "json log format",
+			args: []string{"-log-format=json"},
+			expected: CliFlags{
+				TransportType:   testTransportStdio,
+				SSEAddr:         testDefaultSSEAddr,
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatJSON,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
+			},
+		},
+		{
+			name: "tls flags",
+			args: []string{"-transport=sse", "-tls-cert=cert.pem", "-tls-key=key.pem"},
+			expected: CliFlags{
+				TransportType:   testTransportSSE,
+				SSEAddr:         testDefaultSSEAddr,
+				LogLevel:        testLogLevelInfo,
+				LogFormat:       logFormatText,
+				ShutdownTimeout: server.DefaultShutdownTimeout,
+				TLS:             server.TLSOptions{CertFile: "cert.pem", KeyFile: "key.pem"},
 			},
 		},
 	}
@@ -238,7 +312,7 @@ func TestSetupLogger(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			logger := setupLogger(tt.logLevel)
+			logger := setupLogger(tt.logLevel, logFormatText, io.Discard)
 			assert.NotNil(t, logger)
 
 			// Verify the logger was set as default
@@ -248,6 +322,66 @@ func TestSetupLogger(t *testing.T) {
 	}
 }
 
+func TestLogFormat(t *testing.T) {
+	originalLogger := slog.Default()
+	defer slog.SetDefault(originalLogger)
+
+	tests := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, line string)
+	}{
+		{
+			name:   "text",
+			format: logFormatText,
+			check: func(t *testing.T, line string) {
+				assert.Contains(t, line, `msg="Test message"`)
+				assert.Contains(t, line, "tool=get_ticker")
+			},
+		},
+		{
+			name:   "json",
+			format: logFormatJSON,
+			check: func(t *testing.T, line string) {
+				var record map[string]any
+				require.NoError(t, json.Unmarshal([]byte(line), &record))
+				assert.Equal(t, "Test message", record["msg"])
+				assert.Equal(t, "get_ticker", record["tool"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, checkLogFormat(tt.format))
+
+			var basic bytes.Buffer
+			setupLogger(testLogLevelInfo, tt.format, &basic)
+			slog.Info("Test message", slog.String("tool", "get_ticker"))
+			tt.check(t, basic.String())
+
+			// The enhanced logger keeps the format
+			t.Setenv("LUNO_API_KEY_ID", "test_key")
+			t.Setenv("LUNO_API_SECRET", "test_secret")
+			cfg, err := config.Load("")
+			require.NoError(t, err)
+			level := new(slog.LevelVar)
+			var enhanced bytes.Buffer
+			setupEnhancedLogger(createMCPServer(cfg, level), level, tt.format, &enhanced, nil, config.EnvironmentStaging)
+			slog.Info("Test message", slog.String("tool", "get_ticker"))
+			tt.check(t, enhanced.String())
+		})
+	}
+
+	assert.Error(t, checkLogFormat("xml"))
+}
+
+func TestLogWriter(t *testing.T) {
+	// stdout carries the protocol under stdio, so logs must never go there
+	assert.Equal(t, os.Stderr, logWriter(testTransportStdio))
+	assert.Equal(t, os.Stdout, logWriter(testTransportSSE))
+}
+
 func TestCreateMCPServer(t *testing.T) {
 	// Mock configuration - we'll need to set environment variables for this test
 	t.Setenv("LUNO_API_KEY_ID", "test_key")
@@ -256,11 +390,44 @@ func TestCreateMCPServer(t *testing.T) {
 	cfg, err := config.Load("")
 	require.NoError(t, err)
 
-	server := createMCPServer(cfg)
+	server := createMCPServer(cfg, new(slog.LevelVar))
 	assert.NotNil(t, server)
 	assert.IsType(t, (*mcpserver.MCPServer)(nil), server)
 }
 
+func TestReloadSettings(t *testing.T) {
+	t.Setenv("LUNO_API_KEY_ID", "test_key")
+	t.Setenv("LUNO_API_SECRET", "test_secret")
+	t.Setenv(config.EnvLunoToolsDisabled, "")
+	t.Setenv(config.EnvLunoLogLevel, "")
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+	logLevel := new(slog.LevelVar)
+	server := createMCPServer(cfg, logLevel)
+	require.NotNil(t, server.GetTool("get_ticker"))
+
+	// A level set by a client is kept while the configured level is the same
+	logLevel.Set(slog.LevelError)
+	t.Setenv(config.EnvLunoToolsDisabled, "get_ticker")
+	reloadSettings(server, cfg, logLevel)
+	assert.Nil(t, server.GetTool("get_ticker"))
+	assert.Equal(t, slog.LevelError, logLevel.Level())
+
+	t.Setenv(config.EnvLunoToolsDisabled, "")
+	t.Setenv(config.EnvLunoLogLevel, "debug")
+	reloadSettings(server, cfg, logLevel)
+	assert.NotNil(t, server.GetTool("get_ticker"))
+	assert.Equal(t, slog.LevelDebug, logLevel.Level())
+
+	// Invalid settings leave everything as it was
+	t.Setenv(config.EnvLunoToolsDisabled, "get_ticker")
+	t.Setenv(config.EnvLunoLogLevel, "loud")
+	reloadSettings(server, cfg, logLevel)
+	assert.NotNil(t, server.GetTool("get_ticker"))
+	assert.Equal(t, slog.LevelDebug, logLevel.Level())
+}
+
 func TestSetupSignalHandling(t *testing.T) {
 	ctx, cancel := setupSignalHandling()
 	defer cancel()
@@ -370,10 +537,11 @@ func TestMainFunctionFlow(t *testing.T) {
 		assert.Equal(t, testDefaultSSEAddr, flags.SSEAddr)
 		assert.Equal(t, "", flags.LunoDomain)
 		assert.Equal(t, testLogLevelInfo, flags.LogLevel)
+		assert.Equal(t, logFormatText, flags.LogFormat)
 	})
 
 	t.Run("setup logger", func(t *testing.T) {
-		logger := setupLogger(testLogLevelInfo)
+		logger := setupLogger(testLogLevelInfo, logFormatText, io.Discard)
 		assert.NotNil(t, logger)
 	})
 
@@ -387,7 +555,7 @@ func TestMainFunctionFlow(t *testing.T) {
 		cfg, err := config.Load("")
 		require.NoError(t, err)
 
-		server := createMCPServer(cfg)
+		server := createMCPServer(cfg, new(slog.LevelVar))
 		assert.NotNil(t, server)
 		assert.IsType(t, (*mcpserver.MCPServer)(nil), server)
 	})
@@ -443,7 +611,7 @@ func TestSetupEnhancedLogger(t *testing.T) {
 			require.NoError(t, err)
 
 			// Create MCP server
-			mcpServer := createMCPServer(cfg)
+			mcpServer := createMCPServer(cfg, new(slog.LevelVar))
 			require.NotNil(t, mcpServer)
 
 			// Capture original logger to restore later
@@ -451,7 +619,9 @@ func TestSetupEnhancedLogger(t *testing.T) {
 			defer slog.SetDefault(originalLogger)
 
 			// Test setupEnhancedLogger - this function sets the default logger
-			setupEnhancedLogger(mcpServer, tt.logLevel)
+			level := new(slog.LevelVar)
+			level.Set(parseLogLevel(tt.logLevel))
+			setupEnhancedLogger(mcpServer, level, logFormatText, io.Discard, nil, config.EnvironmentStaging)
 
 			// Verify the logger was set as default
 			newLogger := slog.Default()
@@ -508,12 +678,12 @@ func TestStartServer(t *testing.T) {
 			require.NoError(t, err)
 
 			// Create MCP server
-			mcpServer := createMCPServer(cfg)
+			mcpServer := createMCPServer(cfg, new(slog.LevelVar))
 			require.NotNil(t, mcpServer)
 
 			ctx := context.Background()
 
-			err = startServer(ctx, mcpServer, tt.flags)
+			err = startServer(ctx, mcpServer, cfg, tt.flags)
 			if tt.expectError {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errorContains)
@@ -0,0 +1,403 @@
+// Package alerts watches trading pairs for price thresholds set by clients.
+//
+// Alerts are kept per MCP session and fire once: when the last trade price of
+// a pair crosses an alert's threshold, the alert is removed and reported to
+// the session that created it. A deleted alert is kept for a retention window
+// so that it can be restored. Alerts are held in memory, and also saved to a
+// state store when Persist is called.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/luno/luno-mcp/sdk"
+)
+
+const (
+	// DefaultPollInterval is how often prices are checked while there are alerts
+	DefaultPollInterval = 15 * time.Second
+
+	// DefaultMaxPerSession is the number of alerts a session can have at once
+	DefaultMaxPerSession = 20
+
+	// Retention is how long a deleted alert can be restored
+	Retention = 24 * time.Hour
+
+	// stateSection is the state store section alerts are saved in
+	stateSection = "alerts"
+)
+
+// Condition is the direction a price must cross for an alert to fire
+type Condition string
+
+const (
+	// ConditionAbove fires when the price is at or above the threshold
+	ConditionAbove Condition = "above"
+	// ConditionBelow fires when the price is at or below the threshold
+	ConditionBelow Condition = "below"
+)
+
+// ParseCondition converts a user supplied string into a Condition
+func ParseCondition(s string) (Condition, error) {
+	switch c := Condition(strings.ToLower(strings.TrimSpace(s))); c {
+	case ConditionAbove, ConditionBelow:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown condition %q, must be %q or %q", s, ConditionAbove, ConditionBelow)
+	}
+}
+
+// Alert is a price threshold on a pair
+type Alert struct {
+	ID        string          `json:"id"`
+	Pair      string          `json:"pair"`
+	Condition Condition       `json:"condition"`
+	Price     decimal.Decimal `json:"price"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// met reports whether a price satisfies the alert
+func (a Alert) met(price decimal.Decimal) bool {
+	if a.Condition == ConditionAbove {
+		return price.Cmp(a.Price) >= 0
+	}
+	return price.Cmp(a.Price) <= 0
+}
+
+// Trigger is an alert that fired
+type Trigger struct {
+	Alert
+	Session     string          `json:"-"`
+	LastTrade   decimal.Decimal `json:"last_trade"`
+	TriggeredAt time.Time       `json:"triggered_at"`
+}
+
+// Message describes the trigger for the client
+func (t Trigger) Message() string {
+	return fmt.Sprintf("Price alert %s: %s last traded at %s, %s the threshold of %s",
+		t.ID, t.Pair, t.LastTrade, t.Condition, t.Price)
+}
+
+// Deleted is a deleted alert that can be restored until it expires
+type Deleted struct {
+	Alert
+	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// saved is the layout of the alerts section of the state store
+type saved struct {
+	NextID  int64                `json:"next_id"`
+	Alerts  map[string][]Alert   `json:"alerts"`
+	Deleted map[string][]Deleted `json:"deleted,omitempty"`
+}
+
+// Registry is a concurrency-safe store of alerts keyed by session
+type Registry struct {
+	maxPerSession int
+	now           func() time.Time
+
+	mu      sync.Mutex
+	alerts  map[string][]Alert
+	deleted map[string][]Deleted
+	nextID  int64
+	store   *state.Store
+}
+
+// NewRegistry creates an empty registry that allows maxPerSession alerts per session
+func NewRegistry(maxPerSession int) *Registry {
+	return &Registry{
+		maxPerSession: maxPerSession,
+		now:           time.Now,
+		alerts:        make(map[string][]Alert),
+		deleted:       make(map[string][]Deleted),
+	}
+}
+
+// Persist restores the alerts saved in store of the sessions that keep
+// reports true, and saves every later change to store
+func (r *Registry) Persist(store *state.Store, keep func(session string) bool) error {
+	var saved saved
+	if _, err := store.Load(stateSection, &saved); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for session, alerts := range saved.Alerts {
+		if keep(session) {
+			r.alerts[session] = append(alerts, r.alerts[session]...)
+		}
+	}
+	for session, deleted := range saved.Deleted {
+		if keep(session) {
+			r.deleted[session] = append(deleted, r.deleted[session]...)
+		}
+	}
+	r.purgeLocked()
+	r.nextID = max(r.nextID, saved.NextID)
+	r.store = store
+	r.saveLocked()
+	return nil
+}
+
+// Add creates an alert for a session
+func (r *Registry) Add(session, pair string, condition Condition, price decimal.Decimal) (Alert, error) {
+	if price.Sign() <= 0 {
+		return Alert{}, fmt.Errorf("price must be greater than zero, got %s", price)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.alerts[session]) >= r.maxPerSession {
+		return Alert{}, fmt.Errorf("a session can have at most %d alerts, delete one first", r.maxPerSession)
+	}
+	r.nextID++
+	a := Alert{
+		ID:        strconv.FormatInt(r.nextID, 10),
+		Pair:      pair,
+		Condition: condition,
+		Price:     price,
+		CreatedAt: r.now().UTC(),
+	}
+	r.alerts[session] = append(r.alerts[session], a)
+	r.saveLocked()
+	return a, nil
+}
+
+// List returns a session's alerts in the order they were created
+func (r *Registry) List(session string) []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.alerts[session])
+}
+
+// Delete removes one of a session's alerts and reports whether it existed.
+// The alert can be restored until the retention window has passed.
+func (r *Registry) Delete(session, id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alerts := r.alerts[session]
+	i := slices.IndexFunc(alerts, func(a Alert) bool { return a.ID == id })
+	if i < 0 {
+		return false
+	}
+	now := r.now().UTC()
+	r.purgeLocked()
+	r.deleted[session] = append(r.deleted[session], Deleted{Alert: alerts[i], DeletedAt: now, ExpiresAt: now.Add(Retention)})
+	r.setLocked(session, slices.Delete(alerts, i, i+1))
+	r.saveLocked()
+	return true
+}
+
+// Deleted returns a session's deleted alerts that can still be restored, in
+// the order they were deleted
+func (r *Registry) Deleted(session string) []Deleted {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.purgeLocked()
+	return slices.Clone(r.deleted[session])
+}
+
+// Restore brings back one of a session's deleted alerts
+func (r *Registry) Restore(session, id string) (Alert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.purgeLocked()
+
+	deleted := r.deleted[session]
+	i := slices.IndexFunc(deleted, func(d Deleted) bool { return d.ID == id })
+	if i < 0 {
+		return Alert{}, fmt.Errorf("no deleted price alert with ID %s, alerts can only be restored for %.0f hours after they are deleted", id, Retention.Hours())
+	}
+	if len(r.alerts[session]) >= r.maxPerSession {
+		return Alert{}, fmt.Errorf("a session can have at most %d alerts, delete one first", r.maxPerSession)
+	}
+	a := deleted[i].Alert
+	r.alerts[session] = append(r.alerts[session], a)
+	if deleted = slices.Delete(deleted, i, i+1); len(deleted) == 0 {
+		delete(r.deleted, session)
+	} else {
+		r.deleted[session] = deleted
+	}
+	r.saveLocked()
+	return a, nil
+}
+
+// DeleteSession removes every alert of a session, for when it disconnects.
+// They can't be restored.
+func (r *Registry) DeleteSession(session string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, hasAlerts := r.alerts[session]
+	_, hasDeleted := r.deleted[session]
+	if hasAlerts || hasDeleted {
+		delete(r.alerts, session)
+		delete(r.deleted, session)
+		r.saveLocked()
+	}
+}
+
+// Pairs returns the pairs that have alerts
+func (r *Registry) Pairs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var pairs []string
+	for _, alerts := range r.alerts {
+		for _, a := range alerts {
+			if !slices.Contains(pairs, a.Pair) {
+				pairs = append(pairs, a.Pair)
+			}
+		}
+	}
+	slices.Sort(pairs)
+	return pairs
+}
+
+// Check removes and returns the alerts that the last trade prices satisfy
+func (r *Registry) Check(prices map[string]decimal.Decimal) []Trigger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().UTC()
+	var triggers []Trigger
+	for session, alerts := range r.alerts {
+		remaining := alerts[:0]
+		for _, a := range alerts {
+			price, ok := prices[a.Pair]
+			if ok && a.met(price) {
+				triggers = append(triggers, Trigger{Alert: a, Session: session, LastTrade: price, TriggeredAt: now})
+				continue
+			}
+			remaining = append(remaining, a)
+		}
+		r.setLocked(session, remaining)
+	}
+	if len(triggers) > 0 {
+		r.saveLocked()
+	}
+	slices.SortFunc(triggers, func(a, b Trigger) int { return strings.Compare(a.Session+a.ID, b.Session+b.ID) })
+	return triggers
+}
+
+func (r *Registry) setLocked(session string, alerts []Alert) {
+	if len(alerts) == 0 {
+		delete(r.alerts, session)
+		return
+	}
+	r.alerts[session] = alerts
+}
+
+// purgeLocked forgets deleted alerts whose retention window has passed
+func (r *Registry) purgeLocked() {
+	now := r.now()
+	for session, deleted := range r.deleted {
+		deleted = slices.DeleteFunc(deleted, func(d Deleted) bool { return !now.Before(d.ExpiresAt) })
+		if len(deleted) == 0 {
+			delete(r.deleted, session)
+		} else {
+			r.deleted[session] = deleted
+		}
+	}
+}
+
+// saveLocked saves the alerts to the state store, if there is one. A failed
+// save is logged rather than returned, as the alerts still work in memory.
+func (r *Registry) saveLocked() {
+	if r.store == nil {
+		return
+	}
+	if err := r.store.Save(stateSection, saved{NextID: r.nextID, Alerts: r.alerts, Deleted: r.deleted}); err != nil {
+		slog.Warn("Failed to save price alerts", slog.Any("error", err))
+	}
+}
+
+// Watch checks prices every interval until ctx is cancelled, calling notify
+// for each alert that fires. No requests are made while there are no alerts.
+// Prices are fetched with the client clientFor returns for each session, so
+// that a session using its own Luno credentials makes its own requests.
+// Sessions with the same client share a request.
+func (r *Registry) Watch(ctx context.Context, clientFor func(session string) sdk.LunoClient, interval time.Duration, notify func(Trigger)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		batches := r.batches(clientFor)
+		if len(batches) == 0 {
+			continue
+		}
+		prices := make(map[string]decimal.Decimal)
+		for _, b := range batches {
+			res, err := b.client.GetTickers(ctx, &luno.GetTickersRequest{Pair: b.pairs})
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Warn("Failed to check price alerts", slog.Any("error", err))
+				}
+				continue
+			}
+			for _, t := range res.Tickers {
+				prices[t.Pair] = t.LastTrade
+			}
+		}
+		for _, t := range r.Check(prices) {
+			notify(t)
+		}
+	}
+}
+
+// batch is the pairs whose prices are fetched with one client
+type batch struct {
+	client sdk.LunoClient
+	pairs  []string
+}
+
+// batches groups the pairs that have alerts by the client of the sessions
+// that set them
+func (r *Registry) batches(clientFor func(session string) sdk.LunoClient) []*batch {
+	r.mu.Lock()
+	sessions := make(map[string][]string, len(r.alerts))
+	for session, alerts := range r.alerts {
+		for _, a := range alerts {
+			sessions[session] = append(sessions[session], a.Pair)
+		}
+	}
+	r.mu.Unlock()
+
+	var batches []*batch
+	for _, session := range slices.Sorted(maps.Keys(sessions)) {
+		client := clientFor(session)
+		i := slices.IndexFunc(batches, func(b *batch) bool { return b.client == client })
+		if i < 0 {
+			batches = append(batches, &batch{client: client})
+			i = len(batches) - 1
+		}
+		for _, pair := range sessions[session] {
+			if !slices.Contains(batches[i].pairs, pair) {
+				batches[i].pairs = append(batches[i].pairs, pair)
+			}
+		}
+	}
+	for _, b := range batches {
+		slices.Sort(b.pairs)
+	}
+	return batches
+}
@@ -0,0 +1,225 @@
+package alerts
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func dec(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	require.NoError(t, err)
+	return d
+}
+
+func TestParseCondition(t *testing.T) {
+	c, err := ParseCondition(" Above ")
+	require.NoError(t, err)
+	assert.Equal(t, ConditionAbove, c)
+
+	_, err = ParseCondition("sideways")
+	assert.ErrorContains(t, err, `unknown condition "sideways"`)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(2)
+
+	a, err := r.Add("s1", "XBTZAR", ConditionAbove, dec(t, "1000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", a.ID)
+	_, err = r.Add("s1", "ETHZAR", ConditionBelow, dec(t, "30000"))
+	require.NoError(t, err)
+	_, err = r.Add("s2", "XBTZAR", ConditionBelow, dec(t, "900000"))
+	require.NoError(t, err)
+
+	_, err = r.Add("s1", "XBTEUR", ConditionAbove, dec(t, "50000"))
+	assert.ErrorContains(t, err, "at most 2 alerts")
+	_, err = r.Add("s2", "XBTEUR", ConditionAbove, decimal.Zero())
+	assert.ErrorContains(t, err, "greater than zero")
+
+	assert.Len(t, r.List("s1"), 2)
+	assert.Equal(t, []string{"ETHZAR", "XBTZAR"}, r.Pairs())
+
+	// Sessions can only delete their own alerts
+	assert.False(t, r.Delete("s2", a.ID))
+	assert.True(t, r.Delete("s1", a.ID))
+	assert.False(t, r.Delete("s1", a.ID))
+	assert.Len(t, r.List("s1"), 1)
+
+	r.DeleteSession("s1")
+	assert.Empty(t, r.List("s1"))
+	assert.Equal(t, []string{"XBTZAR"}, r.Pairs())
+}
+
+func TestPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := state.Open(path)
+	require.NoError(t, err)
+	keep := func(session string) bool { return session != "gone" }
+
+	r := NewRegistry(DefaultMaxPerSession)
+	require.NoError(t, r.Persist(store, keep))
+	_, err = r.Add("s1", "XBTZAR", ConditionAbove, dec(t, "1000000"))
+	require.NoError(t, err)
+	_, err = r.Add("s1", "XBTZAR", ConditionBelow, dec(t, "900000"))
+	require.NoError(t, err)
+	_, err = r.Add("gone", "ETHZAR", ConditionBelow, dec(t, "30000"))
+	require.NoError(t, err)
+	require.Len(t, r.Check(map[string]decimal.Decimal{"XBTZAR": dec(t, "1000000")}), 1)
+
+	// After a restart the alerts that haven't fired are back, for the sessions that are kept
+	store, err = state.Open(path)
+	require.NoError(t, err)
+	restored := NewRegistry(DefaultMaxPerSession)
+	require.NoError(t, restored.Persist(store, keep))
+	alerts := restored.List("s1")
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "2", alerts[0].ID)
+	assert.Equal(t, 0, dec(t, "900000").Cmp(alerts[0].Price), alerts[0].Price.String())
+	assert.Empty(t, restored.List("gone"))
+
+	a, err := restored.Add("s1", "XBTZAR", ConditionAbove, dec(t, "1100000"))
+	require.NoError(t, err)
+	assert.Equal(t, "4", a.ID, "IDs are not reused")
+}
+
+func TestCheck(t *testing.T) {
+	r := NewRegistry(DefaultMaxPerSession)
+	_, err := r.Add("s1", "XBTZAR", ConditionAbove, dec(t, "1000000"))
+	require.NoError(t, err)
+	_, err = r.Add("s1", "XBTZAR", ConditionBelow, dec(t, "900000"))
+	require.NoError(t, err)
+	_, err = r.Add("s2", "ETHZAR", ConditionBelow, dec(t, "30000"))
+	require.NoError(t, err)
+
+	// Nothing crosses
+	assert.Empty(t, r.Check(map[string]decimal.Decimal{"XBTZAR": dec(t, "950000"), "ETHZAR": dec(t, "31000")}))
+
+	// Reaching a threshold exactly fires the alert, once
+	triggers := r.Check(map[string]decimal.Decimal{"XBTZAR": dec(t, "1000000")})
+	require.Len(t, triggers, 1)
+	assert.Equal(t, "s1", triggers[0].Session)
+	assert.Equal(t, "1", triggers[0].ID)
+	assert.Equal(t, "Price alert 1: XBTZAR last traded at 1000000, above the threshold of 1000000", triggers[0].Message())
+	assert.Empty(t, r.Check(map[string]decimal.Decimal{"XBTZAR": dec(t, "1000000")}))
+
+	assert.Len(t, r.List("s1"), 1)
+	assert.Len(t, r.List("s2"), 1)
+}
+
+func TestWatch(t *testing.T) {
+	r := NewRegistry(DefaultMaxPerSession)
+	_, err := r.Add("s1", "XBTZAR", ConditionBelow, dec(t, "900000"))
+	require.NoError(t, err)
+
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetTickers(mock.Anything, &luno.GetTickersRequest{Pair: []string{"XBTZAR"}}).
+		Return(&luno.GetTickersResponse{Tickers: []luno.Ticker{{Pair: "XBTZAR", LastTrade: dec(t, "899000")}}}, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fired := make(chan Trigger, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Watch(ctx, func(string) sdk.LunoClient { return client }, 10*time.Millisecond, func(t Trigger) { fired <- t })
+	}()
+
+	select {
+	case trigger := <-fired:
+		assert.Equal(t, "s1", trigger.Session)
+		assert.Equal(t, "899000", trigger.LastTrade.String())
+	case <-time.After(time.Second):
+		t.Fatal("Expected the alert to fire")
+	}
+
+	// With no alerts left, no more tickers are requested
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watcher to stop when the context is cancelled")
+	}
+}
+
+func TestBatches(t *testing.T) {
+	r := NewRegistry(DefaultMaxPerSession)
+	for _, alert := range [][2]string{{"s1", "XBTZAR"}, {"s1", "ETHZAR"}, {"s2", "XBTZAR"}, {"s3", "XBTEUR"}, {"s3", "XBTZAR"}} {
+		_, err := r.Add(alert[0], alert[1], ConditionAbove, dec(t, "1"))
+		require.NoError(t, err)
+	}
+
+	// Sessions with the same client share a request, and a session with its
+	// own client gets its own
+	shared, own := sdk.NewMockLunoClient(t), sdk.NewMockLunoClient(t)
+	clients := map[string]sdk.LunoClient{"s1": shared, "s2": own, "s3": shared}
+	batches := r.batches(func(session string) sdk.LunoClient { return clients[session] })
+	require.Len(t, batches, 2)
+	assert.Equal(t, shared, batches[0].client)
+	assert.Equal(t, []string{"ETHZAR", "XBTEUR", "XBTZAR"}, batches[0].pairs)
+	assert.Equal(t, own, batches[1].client)
+	assert.Equal(t, []string{"XBTZAR"}, batches[1].pairs)
+
+	assert.Empty(t, NewRegistry(DefaultMaxPerSession).batches(func(string) sdk.LunoClient { return shared }))
+}
+
+func TestRestore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := state.Open(path)
+	require.NoError(t, err)
+	keep := func(string) bool { return true }
+
+	r := NewRegistry(1)
+	r.now = func() time.Time { return now }
+	require.NoError(t, r.Persist(store, keep))
+	a, err := r.Add("s1", "XBTZAR", ConditionAbove, dec(t, "1000000"))
+	require.NoError(t, err)
+	require.True(t, r.Delete("s1", a.ID))
+
+	deleted := r.Deleted("s1")
+	require.Len(t, deleted, 1)
+	assert.Equal(t, a.ID, deleted[0].ID)
+	assert.Equal(t, now.Add(Retention), deleted[0].ExpiresAt)
+	assert.Empty(t, r.Deleted("s2"))
+
+	// Only the session that deleted an alert can restore it, within the
+	// session's alert limit
+	_, err = r.Restore("s2", a.ID)
+	assert.ErrorContains(t, err, "no deleted price alert with ID 1")
+	_, err = r.Add("s1", "ETHZAR", ConditionBelow, dec(t, "30000"))
+	require.NoError(t, err)
+	_, err = r.Restore("s1", a.ID)
+	assert.ErrorContains(t, err, "at most 1 alerts")
+	require.True(t, r.Delete("s1", "2"))
+
+	// Deleted alerts survive a restart
+	store, err = state.Open(path)
+	require.NoError(t, err)
+	r = NewRegistry(1)
+	r.now = func() time.Time { return now }
+	require.NoError(t, r.Persist(store, keep))
+	assert.Len(t, r.Deleted("s1"), 2)
+
+	restored, err := r.Restore("s1", a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, a, restored)
+	assert.Equal(t, []Alert{a}, r.List("s1"))
+	assert.Equal(t, []string{"XBTZAR"}, r.Pairs())
+
+	// Deleted alerts are forgotten after the retention window
+	now = now.Add(Retention)
+	assert.Empty(t, r.Deleted("s1"))
+	_, err = r.Restore("s1", "2")
+	assert.ErrorContains(t, err, "only be restored for 24 hours")
+}
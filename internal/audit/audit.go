@@ -0,0 +1,206 @@
+// Package audit keeps a tamper-evident record of actions taken on the account.
+//
+// Entries are appended to a JSON Lines file, separate from the application
+// logs. Each entry includes the SHA-256 hash of the previous entry and its own
+// hash, so editing or removing an entry breaks the chain and is detected by
+// Verify.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// redactedValue replaces the values of sensitive arguments
+const redactedValue = "[REDACTED]"
+
+// sensitiveArguments are tool arguments whose values are never written
+var sensitiveArguments = []string{"confirm_token"}
+
+// Entry is a single audited action
+type Entry struct {
+	Seq       int64          `json:"seq"`
+	Time      time.Time      `json:"time"`
+	Session   string         `json:"session,omitempty"`
+	Caller    string         `json:"caller,omitempty"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	IsError   bool           `json:"is_error"`
+	Result    string         `json:"result"`
+	PrevHash  string         `json:"prev_hash"`
+	Hash      string         `json:"hash"`
+}
+
+// Log appends entries to an audit file
+type Log struct {
+	path string
+	now  func() time.Time
+
+	mu       sync.Mutex
+	f        *os.File
+	seq      int64
+	lastHash string
+}
+
+// Open opens the audit file at path, creating it if needed. An existing file
+// is verified first and new entries continue its hash chain.
+func Open(path string) (*Log, error) {
+	l := &Log{path: path, now: time.Now}
+
+	existing, err := os.Open(path)
+	switch {
+	case err == nil:
+		last, verr := verify(existing)
+		existing.Close()
+		if verr != nil {
+			return nil, fmt.Errorf("existing audit log %s failed verification: %w", path, verr)
+		}
+		if last != nil {
+			l.seq, l.lastHash = last.Seq, last.Hash
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	l.f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Path returns the location of the audit file
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends an entry, filling in its sequence number, time and hashes.
+// The entry is synced to disk before Record returns.
+func (l *Log) Record(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.f == nil {
+		return errors.New("audit log is closed")
+	}
+
+	e.Seq = l.seq + 1
+	e.Time = l.now().UTC()
+	e.Arguments = RedactArguments(e.Arguments)
+	e.PrevHash = l.lastHash
+	hash, err := hashEntry(e)
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	l.seq, l.lastHash = e.Seq, e.Hash
+	return nil
+}
+
+// Close closes the audit file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// Verify checks the hash chain of an audit file and returns the number of entries
+func Verify(r io.Reader) (int64, error) {
+	last, err := verify(r)
+	if err != nil || last == nil {
+		return 0, err
+	}
+	return last.Seq, nil
+}
+
+// verify checks the hash chain and returns the last entry, or nil for an empty file
+func verify(r io.Reader) (*Entry, error) {
+	var (
+		last     *Entry
+		prevHash string
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		// Keep numbers as written so they hash the same as when recorded
+		var e Entry
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.UseNumber()
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if e.Seq != int64(line) {
+			return nil, fmt.Errorf("line %d: expected sequence %d, got %d", line, line, e.Seq)
+		}
+		if e.PrevHash != prevHash {
+			return nil, fmt.Errorf("line %d: previous hash does not match, an entry was changed or removed", line)
+		}
+		hash, err := hashEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		if e.Hash != hash {
+			return nil, fmt.Errorf("line %d: hash does not match its contents", line)
+		}
+		prevHash = e.Hash
+		last = &e
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// hashEntry hashes an entry's JSON encoding with the Hash field empty
+func hashEntry(e Entry) (string, error) {
+	e.Hash = ""
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RedactArguments returns a copy of args with sensitive values replaced, so
+// that they can be written to logs
+func RedactArguments(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		out[k] = v
+	}
+	for _, k := range sensitiveArguments {
+		if _, ok := out[k]; ok {
+			out[k] = redactedValue
+		}
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRecordAndVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	l, err := Open(path)
+	require.NoError(t, err)
+	l.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	require.NoError(t, l.Record(Entry{
+		Tool:      "create_order",
+		Session:   "session-1",
+		Arguments: map[string]any{"pair": "XBTZAR", "volume": "0.01", "confirm_token": "secret"},
+		Result:    "Order created successfully!",
+	}))
+	require.NoError(t, l.Record(Entry{Tool: "cancel_order", Arguments: map[string]any{"order_id": "123"}, IsError: true}))
+	require.NoError(t, l.Close())
+	assert.Error(t, l.Record(Entry{Tool: "cancel_order"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.Contains(t, string(data), redactedValue)
+
+	n, err := Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// Reopening continues the chain
+	l, err = Open(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Record(Entry{Tool: "create_account", Arguments: map[string]any{"count": 1.5}}))
+	require.NoError(t, l.Close())
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	n, err = Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	require.NoError(t, err)
+	for _, tool := range []string{"create_order", "cancel_order", "create_order"} {
+		require.NoError(t, l.Record(Entry{Tool: tool, Arguments: map[string]any{"volume": "0.01"}}))
+	}
+	require.NoError(t, l.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{"edited entry", strings.Replace(string(data), `"volume":"0.01"`, `"volume":"10"`, 1), "hash does not match"},
+		{"removed entry", lines[0] + lines[2], "expected sequence 2"},
+		{"not JSON", string(data) + "garbage\n", "line 4"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tc.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errorContains)
+
+			tampered := filepath.Join(t.TempDir(), "audit.jsonl")
+			require.NoError(t, os.WriteFile(tampered, []byte(tc.content), 0o600))
+			_, err = Open(tampered)
+			assert.ErrorContains(t, err, "failed verification")
+		})
+	}
+}
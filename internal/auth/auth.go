@@ -0,0 +1,151 @@
+// Package auth verifies the bearer tokens of requests to the HTTP
+// transports, so that the server can be exposed beyond localhost.
+//
+// Tokens are either checked against a static list or validated as JWTs
+// issued by an OpenID Connect provider. Requests without a valid token are
+// rejected before they reach the MCP layer.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/security"
+)
+
+// Modes of authorization
+const (
+	ModeNone  = "none"
+	ModeToken = "token"
+	ModeOIDC  = "oidc"
+)
+
+var (
+	// ErrMissingToken is returned for requests without a bearer token
+	ErrMissingToken = errors.New("missing bearer token")
+	// ErrInvalidToken is returned for tokens that are unknown, expired or
+	// fail validation
+	ErrInvalidToken = errors.New("invalid bearer token")
+)
+
+// Principal is the caller a token was issued to
+type Principal struct {
+	// Subject identifies the caller, such as the sub claim of a JWT
+	Subject string
+	// Mode is how the caller was authenticated
+	Mode string
+}
+
+// Authenticator verifies bearer tokens
+type Authenticator interface {
+	// Mode returns the authorization mode, for display
+	Mode() string
+	// Authenticate returns the caller a token belongs to. Tokens that fail
+	// validation return an error wrapping ErrInvalidToken, other errors mean
+	// the token couldn't be checked.
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
+
+// StaticTokens accepts a fixed list of tokens
+type StaticTokens struct {
+	tokens []string
+}
+
+var _ Authenticator = (*StaticTokens)(nil)
+
+// NewStaticTokens creates an authenticator accepting any of tokens
+func NewStaticTokens(tokens []string) (*StaticTokens, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("at least one token is required")
+	}
+	return &StaticTokens{tokens: tokens}, nil
+}
+
+// Mode returns ModeToken
+func (s *StaticTokens) Mode() string {
+	return ModeToken
+}
+
+// Count returns the number of accepted tokens
+func (s *StaticTokens) Count() int {
+	return len(s.tokens)
+}
+
+// Authenticate checks token against every configured token in constant
+// time. The subject is the token's position in the list, so that logs can
+// tell callers apart without showing the token.
+func (s *StaticTokens) Authenticate(_ context.Context, token string) (Principal, error) {
+	match := -1
+	for i, t := range s.tokens {
+		if security.Equal(token, t) && match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return Principal{}, ErrInvalidToken
+	}
+	return Principal{Subject: fmt.Sprintf("token-%d", match+1), Mode: ModeToken}, nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the authenticated caller of a request, if any
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Middleware rejects requests without a valid bearer token with 401
+// Unauthorized, or 503 Service Unavailable when the token couldn't be
+// checked, and passes the others on with the caller on their context
+func Middleware(a Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := bearerToken(r)
+		var p Principal
+		if err == nil {
+			p, err = a.Authenticate(r.Context(), token)
+		}
+		if err != nil {
+			slog.Warn("Rejected unauthenticated request",
+				slog.String("path", r.URL.Path),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.Any("error", err))
+			if !errors.Is(err, ErrMissingToken) && !errors.Is(err, ErrInvalidToken) {
+				http.Error(w, "Unable to verify the bearer token", http.StatusServiceUnavailable)
+				return
+			}
+			unauthorized(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+// bearerToken returns the token of a request's Authorization header
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", ErrMissingToken
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// unauthorized writes a 401 response as described by RFC 6750
+func unauthorized(w http.ResponseWriter, err error) {
+	challenge := `Bearer realm="luno-mcp"`
+	if !errors.Is(err, ErrMissingToken) {
+		challenge += `, error="invalid_token"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
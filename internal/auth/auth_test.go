@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticTokens(t *testing.T) {
+	_, err := NewStaticTokens(nil)
+	require.Error(t, err)
+
+	a, err := NewStaticTokens([]string{"first-token-0123456", "second-token-012345"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, a.Count())
+
+	p, err := a.Authenticate(context.Background(), "second-token-012345")
+	require.NoError(t, err)
+	assert.Equal(t, Principal{Subject: "token-2", Mode: ModeToken}, p)
+
+	_, err = a.Authenticate(context.Background(), "second-token")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// failingAuthenticator can't check tokens, like an unreachable provider
+type failingAuthenticator struct{}
+
+func (failingAuthenticator) Mode() string { return ModeOIDC }
+
+func (failingAuthenticator) Authenticate(context.Context, string) (Principal, error) {
+	return Principal{}, errors.New("connection refused")
+}
+
+func TestMiddleware(t *testing.T) {
+	a, err := NewStaticTokens([]string{"first-token-0123456"})
+	require.NoError(t, err)
+
+	var caller Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = FromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name           string
+		authenticator  Authenticator
+		header         string
+		expectedStatus int
+		expectedAuth   string
+	}{
+		{"valid token", a, "Bearer first-token-0123456", http.StatusNoContent, ""},
+		{"scheme is case insensitive", a, "bearer first-token-0123456", http.StatusNoContent, ""},
+		{"missing header", a, "", http.StatusUnauthorized, `Bearer realm="luno-mcp"`},
+		{"basic auth", a, "Basic Zmlyc3Q6dG9rZW4=", http.StatusUnauthorized, `Bearer realm="luno-mcp"`},
+		{"wrong token", a, "Bearer other-token-0123456", http.StatusUnauthorized, `Bearer realm="luno-mcp", error="invalid_token"`},
+		{"unverifiable", failingAuthenticator{}, "Bearer first-token-0123456", http.StatusServiceUnavailable, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			caller = Principal{}
+			req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			Middleware(tc.authenticator, next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Equal(t, tc.expectedAuth, rec.Header().Get("WWW-Authenticate"))
+			if tc.expectedStatus == http.StatusNoContent {
+				assert.Equal(t, "token-1", caller.Subject)
+			} else {
+				assert.Empty(t, caller.Subject)
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// clockLeeway allows for clock differences when checking token times
+	clockLeeway = time.Minute
+	// keyRefreshInterval is the least time between fetches of the signing
+	// keys, so that tokens with unknown key IDs can't flood the provider
+	keyRefreshInterval = time.Minute
+	// fetchTimeout bounds each request to the provider
+	fetchTimeout = 10 * time.Second
+	// maxDocumentSize bounds the discovery and key documents
+	maxDocumentSize = 1 << 20
+	// minRSABits is the smallest RSA signing key that is trusted
+	minRSABits = 2048
+)
+
+// signatureAlgorithms are the algorithms tokens may be signed with. Only
+// asymmetric algorithms are accepted, so "none" and HMAC tokens are always
+// rejected.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+}
+
+// OIDC validates JWTs signed by an OpenID Connect provider. The provider's
+// signing keys are found through its discovery document on first use and
+// fetched again when a token names a key that isn't known yet.
+type OIDC struct {
+	issuer   string
+	audience string
+	client   *http.Client
+	now      func() time.Time
+
+	// fetches makes concurrent requests for unknown keys share one fetch,
+	// which is made without holding mu
+	fetches singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]jose.JSONWebKey
+	fetchedAt time.Time
+}
+
+var _ Authenticator = (*OIDC)(nil)
+
+// NewOIDC creates an authenticator for tokens from issuer. Tokens must name
+// audience in their aud claim unless it is empty. The issuer must be an
+// https URL, unless it is on this machine.
+func NewOIDC(issuer, audience string) (*OIDC, error) {
+	issuer = strings.TrimRight(strings.TrimSpace(issuer), "/")
+	if err := checkProviderURL(issuer); err != nil {
+		return nil, fmt.Errorf("issuer %w", err)
+	}
+	return &OIDC{
+		issuer:   issuer,
+		audience: strings.TrimSpace(audience),
+		client:   &http.Client{Timeout: fetchTimeout},
+		now:      time.Now,
+	}, nil
+}
+
+// checkProviderURL checks that u is an https URL, or an http one on a
+// loopback address, so that keys can't be swapped in transit
+func checkProviderURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("%q must be an https URL", u)
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if isLoopback(parsed.Hostname()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q must be an https URL, or http on localhost", u)
+}
+
+// isLoopback reports whether host names this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Mode returns ModeOIDC
+func (o *OIDC) Mode() string {
+	return ModeOIDC
+}
+
+// Issuer returns the URL of the provider
+func (o *OIDC) Issuer() string {
+	return o.issuer
+}
+
+// Audience returns the audience tokens must be issued for, if any
+func (o *OIDC) Audience() string {
+	return o.audience
+}
+
+// Authenticate validates token's signature, issuer, audience and times
+func (o *OIDC) Authenticate(ctx context.Context, token string) (Principal, error) {
+	if strings.Count(token, ".") != 2 {
+		return Principal{}, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	keys, err := o.signingKeys(ctx, parsed.Headers[0].KeyID)
+	if err != nil {
+		return Principal{}, err
+	}
+	var claims jwt.Claims
+	verified := false
+	for _, key := range keys {
+		if err = parsed.Claims(key.Key, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err := o.checkClaims(claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return Principal{Subject: claims.Subject, Mode: ModeOIDC}, nil
+}
+
+// checkClaims checks the issuer, audience and validity period of a token
+func (o *OIDC) checkClaims(c jwt.Claims) error {
+	if strings.TrimRight(c.Issuer, "/") != o.issuer {
+		return fmt.Errorf("issued by %q, not %q", c.Issuer, o.issuer)
+	}
+	if o.audience != "" && !c.Audience.Contains(o.audience) {
+		return fmt.Errorf("not issued for audience %q", o.audience)
+	}
+	now := o.now()
+	if c.Expiry == nil {
+		return errors.New("no expiry time")
+	}
+	if now.Add(-clockLeeway).After(c.Expiry.Time()) {
+		return errors.New("token has expired")
+	}
+	if c.NotBefore != nil && now.Add(clockLeeway).Before(c.NotBefore.Time()) {
+		return errors.New("token is not valid yet")
+	}
+	return nil
+}
+
+// signingKeys returns the keys that may have signed a token with key ID kid,
+// fetching the provider's keys if they haven't been or kid is unknown
+func (o *OIDC) signingKeys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	o.mu.Lock()
+	keys, fetchedAt := o.matchKeys(kid), o.fetchedAt
+	o.mu.Unlock()
+	if len(keys) > 0 {
+		return keys, nil
+	}
+	if !fetchedAt.IsZero() && o.now().Sub(fetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+	}
+
+	// The fetch is shared with other callers, so it carries on when this
+	// one gives up
+	fetch := o.fetches.DoChan("keys", func() (any, error) {
+		return nil, o.refreshKeys(context.WithoutCancel(ctx))
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-fetch:
+		if res.Err != nil {
+			return nil, fmt.Errorf("fetching signing keys from %s: %w", o.issuer, res.Err)
+		}
+	}
+
+	o.mu.Lock()
+	keys = o.matchKeys(kid)
+	o.mu.Unlock()
+	if len(keys) > 0 {
+		return keys, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+}
+
+// refreshKeys fetches the provider's keys, unless they were fetched within
+// the refresh interval
+func (o *OIDC) refreshKeys(ctx context.Context) error {
+	o.mu.Lock()
+	fetchedAt := o.fetchedAt
+	o.mu.Unlock()
+	if !fetchedAt.IsZero() && o.now().Sub(fetchedAt) < keyRefreshInterval {
+		return nil
+	}
+
+	keys, err := o.fetchKeys(ctx)
+	if err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.keys = keys
+	o.fetchedAt = o.now()
+	return nil
+}
+
+// matchKeys returns the known key with ID kid, or every key when the token
+// doesn't name one. o.mu must be held.
+func (o *OIDC) matchKeys(kid string) []jose.JSONWebKey {
+	if kid != "" {
+		if key, ok := o.keys[kid]; ok {
+			return []jose.JSONWebKey{key}
+		}
+		return nil
+	}
+	return slices.Collect(maps.Values(o.keys))
+}
+
+// fetchKeys reads the provider's discovery document and then its key set
+func (o *OIDC) fetchKeys(ctx context.Context) (map[string]jose.JSONWebKey, error) {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := o.getJSON(ctx, o.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("discovery document has no jwks_uri")
+	}
+	if err := checkProviderURL(discovery.JWKSURI); err != nil {
+		return nil, fmt.Errorf("jwks_uri %w", err)
+	}
+
+	// Keys are decoded one at a time, so that one of a type we don't
+	// support is skipped rather than failing every token
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := o.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]jose.JSONWebKey)
+	for i, raw := range set.Keys {
+		var key jose.JSONWebKey
+		if err := key.UnmarshalJSON(raw); err != nil || !usableKey(key) {
+			continue
+		}
+		if key.KeyID == "" {
+			key.KeyID = fmt.Sprintf("#%d", i)
+		}
+		keys[key.KeyID] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("key set has no usable signing keys")
+	}
+	return keys, nil
+}
+
+// usableKey reports whether key may verify tokens: a public RSA key of at
+// least minRSABits or an elliptic curve key, that isn't meant only for
+// encryption
+func usableKey(key jose.JSONWebKey) bool {
+	if key.Use != "" && key.Use != "sig" {
+		return false
+	}
+	if !key.Valid() || !key.IsPublic() {
+		return false
+	}
+	switch k := key.Key.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen() >= minRSABits
+	case *ecdsa.PublicKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// getJSON fetches and decodes a JSON document
+func (o *OIDC) getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: %w", u, err)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProvider is an OpenID Connect provider serving one RSA and one EC key
+type testProvider struct {
+	server   *httptest.Server
+	rsaKey   *rsa.PrivateKey
+	shortKey *rsa.PrivateKey
+	ecKey    *ecdsa.PrivateKey
+	jwksURI  string
+	keyFetch atomic.Int32
+	// keysServed, when set, is waited on before the keys are served
+	keysServed chan struct{}
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	shortKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p := &testProvider{rsaKey: rsaKey, shortKey: shortKey, ecKey: ecKey}
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		jwksURI := p.server.URL + "/keys"
+		if p.jwksURI != "" {
+			jwksURI = p.jwksURI
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   p.server.URL,
+			"jwks_uri": jwksURI,
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.keyFetch.Add(1)
+		if p.keysServed != nil {
+			<-p.keysServed
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "RSA", "kid": "rsa-short", "n": b64(shortKey.N.Bytes()), "e": b64(big.NewInt(int64(shortKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+			{"kty": "oct", "kid": "hmac-1", "k": b64([]byte("shared secret"))},
+		}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// sign returns a JWT with claims signed by the provider's key for alg
+func (p *testProvider) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + enc(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	var err error
+	switch alg {
+	case "RS256":
+		key := p.rsaKey
+		if kid == "rsa-short" {
+			key = p.shortKey
+		}
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		if err == nil {
+			sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	}
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestOIDC(t *testing.T) {
+	p := newTestProvider(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	o, err := NewOIDC(p.server.URL+"/", "luno-mcp")
+	require.NoError(t, err)
+	o.now = func() time.Time { return now }
+
+	claims := func(changes map[string]any) map[string]any {
+		c := map[string]any{
+			"iss": p.server.URL,
+			"sub": "ops-agent",
+			"aud": []string{"other", "luno-mcp"},
+			"exp": now.Add(time.Hour).Unix(),
+			"nbf": now.Add(-time.Minute).Unix(),
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError string
+	}{
+		{"RSA key", p.sign(t, "RS256", "rsa-1", claims(nil)), ""},
+		{"EC key", p.sign(t, "ES256", "ec-1", claims(map[string]any{"aud": "luno-mcp"})), ""},
+		{"no key ID", p.sign(t, "ES256", "", claims(nil)), ""},
+		{"expired", p.sign(t, "RS256", "rsa-1", claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()})), "token has expired"},
+		{"within leeway", p.sign(t, "RS256", "rsa-1", claims(map[string]any{"exp": now.Add(-30 * time.Second).Unix()})), ""},
+		{"no expiry", p.sign(t, "RS256", "rsa-1", claims(map[string]any{"exp": nil})), "no expiry time"},
+		{"not valid yet", p.sign(t, "RS256", "rsa-1", claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})), "not valid yet"},
+		{"other issuer", p.sign(t, "RS256", "rsa-1", claims(map[string]any{"iss": "https://evil.example.com"})), "issued by"},
+		{"other audience", p.sign(t, "RS256", "rsa-1", claims(map[string]any{"aud": "other"})), "not issued for audience"},
+		{"wrong key type", p.sign(t, "RS256", "ec-1", claims(nil)), "error in cryptographic primitive"},
+		{"symmetric key", p.sign(t, "RS256", "hmac-1", claims(nil)), "unknown signing key"},
+		{"short RSA key", p.sign(t, "RS256", "rsa-short", claims(nil)), "unknown signing key"},
+		{"not a JWT", "opaque-token", "not a JWT"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := o.Authenticate(context.Background(), tc.token)
+			if tc.expectedError != "" {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Principal{Subject: "ops-agent", Mode: ModeOIDC}, principal)
+		})
+	}
+
+	// Keys are fetched once, and again for an unknown key ID only after the refresh interval
+	assert.Equal(t, int32(1), p.keyFetch.Load())
+	now = now.Add(keyRefreshInterval)
+	_, err = o.Authenticate(context.Background(), p.sign(t, "RS256", "rsa-2", claims(nil)))
+	require.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, int32(2), p.keyFetch.Load())
+}
+
+func TestOIDCTamperedToken(t *testing.T) {
+	p := newTestProvider(t)
+	o, err := NewOIDC(p.server.URL, "")
+	require.NoError(t, err)
+
+	token := p.sign(t, "RS256", "rsa-1", map[string]any{"iss": p.server.URL, "sub": "ops-agent", "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = o.Authenticate(context.Background(), token)
+	require.NoError(t, err)
+
+	// Swapping in other claims breaks the signature
+	other, _ := json.Marshal(map[string]any{"iss": p.server.URL, "sub": "admin", "exp": time.Now().Add(time.Hour).Unix()})
+	parts := strings.Split(token, ".")
+	_, err = o.Authenticate(context.Background(), parts[0]+"."+base64.RawURLEncoding.EncodeToString(other)+"."+parts[2])
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Unsigned tokens are never accepted
+	none, _ := json.Marshal(map[string]string{"alg": "none", "kid": "rsa-1"})
+	_, err = o.Authenticate(context.Background(), base64.RawURLEncoding.EncodeToString(none)+"."+base64.RawURLEncoding.EncodeToString(other)+".")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestOIDCProviderDown(t *testing.T) {
+	p := newTestProvider(t)
+	o, err := NewOIDC(p.server.URL, "")
+	require.NoError(t, err)
+	token := p.sign(t, "RS256", "rsa-1", map[string]any{"iss": p.server.URL, "exp": time.Now().Add(time.Hour).Unix()})
+	p.server.Close()
+
+	_, err = o.Authenticate(context.Background(), token)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewOIDC(t *testing.T) {
+	tests := []struct {
+		issuer        string
+		expectedError string
+	}{
+		{"https://auth.example.com/", ""},
+		{"http://localhost:8080", ""},
+		{"http://127.0.0.1:8080", ""},
+		{"http://[::1]:8080", ""},
+		{"http://auth.example.com", "must be an https URL, or http on localhost"},
+		{"ftp://auth.example.com", "must be an https URL"},
+		{"auth.example.com", "must be an https URL"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.issuer, func(t *testing.T) {
+			o, err := NewOIDC(tc.issuer, "")
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, strings.TrimRight(tc.issuer, "/"), o.Issuer())
+		})
+	}
+}
+
+func TestOIDCPlainHTTPKeys(t *testing.T) {
+	p := newTestProvider(t)
+	p.jwksURI = "http://auth.example.com/keys"
+	o, err := NewOIDC(p.server.URL, "")
+	require.NoError(t, err)
+
+	_, err = o.Authenticate(context.Background(), p.sign(t, "RS256", "rsa-1", map[string]any{"iss": p.server.URL, "exp": time.Now().Add(time.Hour).Unix()}))
+	require.ErrorContains(t, err, "jwks_uri \"http://auth.example.com/keys\" must be an https URL")
+	assert.Equal(t, int32(0), p.keyFetch.Load())
+}
+
+func TestOIDCConcurrentKeyFetch(t *testing.T) {
+	p := newTestProvider(t)
+	p.keysServed = make(chan struct{})
+	o, err := NewOIDC(p.server.URL, "")
+	require.NoError(t, err)
+	token := p.sign(t, "RS256", "rsa-1", map[string]any{"iss": p.server.URL, "exp": time.Now().Add(time.Hour).Unix()})
+
+	errs := make(chan error, 5)
+	for range cap(errs) {
+		go func() {
+			_, err := o.Authenticate(context.Background(), token)
+			errs <- err
+		}()
+	}
+
+	// While the keys are being fetched, a caller that gives up isn't held
+	// up by it
+	require.Eventually(t, func() bool { return p.keyFetch.Load() == 1 }, time.Second, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = o.Authenticate(ctx, token)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// Every waiting caller shares the one fetch
+	close(p.keysServed)
+	for range cap(errs) {
+		require.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(1), p.keyFetch.Load())
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/auth"
+)
+
+// Environment variables for authorizing requests to the SSE transport
+const (
+	// EnvMCPAuthMode is how clients are authorized: "none", "token" or "oidc"
+	EnvMCPAuthMode = "MCP_AUTH_MODE"
+	// EnvMCPAuthTokens is a comma-separated list of accepted bearer tokens, for the token mode
+	EnvMCPAuthTokens = "MCP_AUTH_TOKENS"
+	// EnvMCPAuthIssuer is the URL of the OpenID Connect provider, for the oidc mode
+	EnvMCPAuthIssuer = "MCP_AUTH_ISSUER"
+	// EnvMCPAuthAudience is the audience tokens must be issued for, for the oidc mode
+	EnvMCPAuthAudience = "MCP_AUTH_AUDIENCE"
+)
+
+// minAuthTokenLength keeps static tokens long enough not to be guessed
+const minAuthTokenLength = 16
+
+// LoadAuth reads how clients are authorized from the environment.
+// It returns nil when authorization is off.
+func LoadAuth(getenv func(string) string) (auth.Authenticator, error) {
+	mode := strings.ToLower(strings.TrimSpace(getenv(EnvMCPAuthMode)))
+	tokens := splitList(getenv(EnvMCPAuthTokens))
+	issuer := strings.TrimSpace(getenv(EnvMCPAuthIssuer))
+
+	switch mode {
+	case "", auth.ModeNone:
+		// Settings for another mode most likely mean the mode was forgotten,
+		// so don't quietly leave the server open
+		if len(tokens) > 0 || issuer != "" {
+			return nil, fmt.Errorf("%s must be %q or %q when %s or %s is set",
+				EnvMCPAuthMode, auth.ModeToken, auth.ModeOIDC, EnvMCPAuthTokens, EnvMCPAuthIssuer)
+		}
+		return nil, nil
+	case auth.ModeToken:
+		for _, t := range tokens {
+			if len(t) < minAuthTokenLength {
+				return nil, fmt.Errorf("invalid %s: tokens must be at least %d characters", EnvMCPAuthTokens, minAuthTokenLength)
+			}
+		}
+		a, err := auth.NewStaticTokens(tokens)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvMCPAuthTokens, err)
+		}
+		return a, nil
+	case auth.ModeOIDC:
+		if issuer == "" {
+			return nil, fmt.Errorf("%s is required for the %q mode", EnvMCPAuthIssuer, auth.ModeOIDC)
+		}
+		a, err := auth.NewOIDC(issuer, getenv(EnvMCPAuthAudience))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvMCPAuthIssuer, err)
+		}
+		return a, nil
+	default:
+		return nil, fmt.Errorf("invalid %s: must be %q, %q or %q", EnvMCPAuthMode, auth.ModeNone, auth.ModeToken, auth.ModeOIDC)
+	}
+}
+
+// authInfo describes how clients are authorized, without the tokens
+func (c *Config) authInfo() map[string]any {
+	switch a := c.Auth.(type) {
+	case nil:
+		return map[string]any{"mode": auth.ModeNone}
+	case *auth.StaticTokens:
+		return map[string]any{"mode": a.Mode(), "tokens": a.Count()}
+	case *auth.OIDC:
+		return map[string]any{"mode": a.Mode(), "issuer": a.Issuer(), "audience": a.Audience()}
+	default:
+		return map[string]any{"mode": a.Mode()}
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/auth"
+)
+
+func TestLoadAuth(t *testing.T) {
+	const token = "0123456789abcdefXYZ"
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedMode  string
+		expectedError string
+	}{
+		{"off", map[string]string{}, auth.ModeNone, ""},
+		{"none", map[string]string{EnvMCPAuthMode: "None"}, auth.ModeNone, ""},
+		{"tokens", map[string]string{EnvMCPAuthMode: "token", EnvMCPAuthTokens: token + ", " + token + "2"}, auth.ModeToken, ""},
+		{"oidc", map[string]string{EnvMCPAuthMode: "oidc", EnvMCPAuthIssuer: "https://auth.example.com/", EnvMCPAuthAudience: "luno-mcp"}, auth.ModeOIDC, ""},
+		{"tokens without mode", map[string]string{EnvMCPAuthTokens: token}, "", "MCP_AUTH_MODE must be"},
+		{"no tokens", map[string]string{EnvMCPAuthMode: "token"}, "", "at least one token"},
+		{"short token", map[string]string{EnvMCPAuthMode: "token", EnvMCPAuthTokens: "secret"}, "", "at least 16 characters"},
+		{"no issuer", map[string]string{EnvMCPAuthMode: "oidc"}, "", "MCP_AUTH_ISSUER is required"},
+		{"invalid issuer", map[string]string{EnvMCPAuthMode: "oidc", EnvMCPAuthIssuer: "auth.example.com"}, "", "must be an https URL"},
+		{"unknown mode", map[string]string{EnvMCPAuthMode: "basic"}, "", "invalid MCP_AUTH_MODE"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := LoadAuth(func(k string) string { return tc.env[k] })
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			cfg := &Config{Auth: a}
+			if mode := cfg.authInfo()["mode"]; mode != tc.expectedMode {
+				t.Errorf("Expected mode %q, got %v", tc.expectedMode, mode)
+			}
+		})
+	}
+}
+
+func TestAuthInfoHidesTokens(t *testing.T) {
+	a, err := auth.NewStaticTokens([]string{"0123456789abcdefXYZ"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info := (&Config{Auth: a}).authInfo()
+	if info["tokens"] != 1 {
+		t.Errorf("Expected a token count of 1, got %v", info["tokens"])
+	}
+	for k, v := range info {
+		if s, ok := v.(string); ok && strings.Contains(s, "0123456789") {
+			t.Errorf("Token leaked in %q", k)
+		}
+	}
+}
@@ -3,29 +3,225 @@ package config
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/luno/luno-go"
 	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/audit"
+	"github.com/luno/luno-mcp/internal/auth"
+	"github.com/luno/luno-mcp/internal/exports"
+	"github.com/luno/luno-mcp/internal/idempotency"
+	"github.com/luno/luno-mcp/internal/notes"
+	"github.com/luno/luno-mcp/internal/orderwatch"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/luno/luno-mcp/internal/redact"
+	"github.com/luno/luno-mcp/internal/schedule"
+	"github.com/luno/luno-mcp/internal/security"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/luno/luno-mcp/internal/stream"
+	"github.com/luno/luno-mcp/internal/support"
+	"github.com/luno/luno-mcp/internal/toolmw"
+	"github.com/luno/luno-mcp/internal/tracing"
 	"github.com/luno/luno-mcp/sdk"
 )
 
 const (
 	// Environment variables
-	EnvLunoAPIKeyID     = "LUNO_API_KEY_ID"
-	EnvLunoAPIKeySecret = "LUNO_API_SECRET"
-	EnvLunoAPIDomain    = "LUNO_API_DOMAIN"
-	EnvLunoAPIDebug     = "LUNO_API_DEBUG"
+	EnvLunoAPIKeyID      = "LUNO_API_KEY_ID"
+	EnvLunoAPIKeySecret  = "LUNO_API_SECRET"
+	EnvLunoAPIDomain     = "LUNO_API_DOMAIN"
+	EnvLunoAPIDebug      = "LUNO_API_DEBUG"
+	EnvLunoPermissions   = "LUNO_PERMISSIONS"
+	EnvLunoCacheTTL      = "LUNO_CACHE_TTL"
+	EnvLunoConfirmWrite  = "LUNO_CONFIRM_WRITES"
+	EnvLunoDryRun        = "LUNO_DRY_RUN"
+	EnvLunoAuditLogPath  = "LUNO_AUDIT_LOG_PATH"
+	EnvLunoToolsEnabled  = "LUNO_TOOLS_ENABLED"
+	EnvLunoToolsDisabled = "LUNO_TOOLS_DISABLED"
+	EnvLunoAllowedPairs  = "LUNO_ALLOWED_TRADING_PAIRS"
+	EnvLunoRefreshPeriod = "LUNO_RESOURCE_REFRESH_INTERVAL"
+	EnvLunoTxnsPerAcct   = "LUNO_TRANSACTIONS_PER_ACCOUNT"
+	EnvLunoCallTimeout   = "LUNO_CALL_TIMEOUT"
+	EnvLunoDedupWindow   = "LUNO_DUPLICATE_ORDER_WINDOW"
+	EnvLunoSessionLimit  = "LUNO_SESSION_CALLS_PER_MINUTE"
+	EnvLunoMaxResultRows = "LUNO_MAX_RESULT_ROWS"
+	EnvLunoStatePath     = "LUNO_STATE_PATH"
+	EnvLunoPaperTrading  = "LUNO_PAPER_TRADING"
+
+	// EnvLunoSessionCredentials lets SSE clients send their own Luno API
+	// credentials in the session.HeaderAPIKeyID and HeaderAPIKeySecret headers
+	EnvLunoSessionCredentials = "LUNO_SESSION_CREDENTIALS"
+
+	// EnvLunoPaperBalances seeds the paper trading portfolio, as a list of
+	// ASSET:AMOUNT entries (e.g. "ZAR:100000,XBT:0.5")
+	EnvLunoPaperBalances = "LUNO_PAPER_BALANCES"
+
+	// EnvLunoSchedulesPath is the older name of EnvLunoStatePath, from when
+	// the file only held schedules. It is read when EnvLunoStatePath is unset.
+	EnvLunoSchedulesPath = "LUNO_SCHEDULES_PATH"
 
 	// Default Luno API domain
 	DefaultLunoDomain = "api.luno.com"
+
+	// DefaultResourceRefresh is how often account resources are checked for changes
+	DefaultResourceRefresh = 30 * time.Second
+
+	// DefaultTransactionsPerAccount is how many recent transactions of each
+	// account the transactions resource returns
+	DefaultTransactionsPerAccount = 20
+
+	// DefaultMaxResultRows is how many rows of a list a tool returns at once
+	DefaultMaxResultRows = 100
+
+	// DefaultCallTimeout bounds each Luno call a tool makes alongside others
+	DefaultCallTimeout = 10 * time.Second
+
+	// maxTransactionsPerAccount is the most rows the Luno API returns per request
+	maxTransactionsPerAccount = 1000
+
+	// clientTimeout matches the luno-go default, which is lost when replacing its HTTP client
+	clientTimeout = 10 * time.Second
 )
 
 // Config holds the configuration for the application
 type Config struct {
-	// Luno client
+	// Luno client. With several profiles, calls use the profile set on
+	// their context by sdk.WithProfile.
 	LunoClient sdk.LunoClient
+
+	// Profiles are the configured credential sets, including the default
+	Profiles []Profile
+
+	// Cache holds recent market data responses, nil disables caching
+	Cache *sdk.Cache
+
+	// ClockSkew tracks the difference between the local and Luno server clocks
+	ClockSkew *sdk.ClockSkewTracker
+
+	// Notes holds user-declared notes about accounts and pairs
+	Notes *notes.Store
+
+	// Alerts holds the price alerts of each session
+	Alerts *alerts.Registry
+
+	// OrderWatch holds the orders each session is watching
+	OrderWatch *orderwatch.Watcher
+
+	// Outbox holds the notifications to clients that haven't been delivered
+	Outbox *outbox.Outbox
+
+	// Exports holds results too large to return from a tool, such as long
+	// statements, for each session to read as resources
+	Exports *exports.Store
+
+	// State keeps alerts, watched orders and schedules across restarts, nil
+	// keeps them in memory only
+	State *state.Store
+
+	// Schedules holds the recurring buys, nil disables scheduling
+	Schedules *schedule.Scheduler
+
+	// Metrics counts the calls and latency of each tool, nil disables metrics
+	Metrics *toolmw.Metrics
+
+	// Redactor scrubs secrets and identifying details from logs and
+	// notifications
+	Redactor *redact.Redactor
+
+	// Support records recent logs and tool calls for support bundles
+	Support *support.Recorder
+
+	// Confirmations issues tokens for two-phase write tools, nil disables confirmation
+	Confirmations *security.TokenStore
+
+	// DryRun is true when write tools only validate and report what they would submit
+	DryRun bool
+
+	// PaperTrading is true when orders and balances are simulated by an
+	// in-memory portfolio rather than sent to Luno
+	PaperTrading bool
+
+	// WritesRefused is true when production was selected with LUNO_ENV
+	// without allowing writes, so tools that change the account are disabled
+	WritesRefused bool
+
+	// Auth verifies the bearer tokens of requests to the SSE transport, nil
+	// leaves it open
+	Auth auth.Authenticator
+
+	// CORS is which browser origins may call the SSE transport
+	CORS CORS
+
+	// Sessions holds the state and rate limit of each connected client
+	Sessions *session.Manager
+
+	// Submissions remembers recent orders to reject duplicate submissions,
+	// nil disables duplicate detection
+	Submissions *idempotency.Store
+
+	// ResourceRefresh is how often the wallet and transaction resources are
+	// checked for changes, zero disables update notifications
+	ResourceRefresh time.Duration
+
+	// TransactionsPerAccount is how many recent transactions of each account
+	// the transactions resource returns
+	TransactionsPerAccount int
+
+	// MaxResultRows is how many rows of a list a tool returns before
+	// truncating it, zero returns whole lists
+	MaxResultRows int
+
+	// Timeouts bounds how long each tool call may run
+	Timeouts ToolTimeouts
+
+	// CallTimeout bounds each Luno call that tools run in parallel, zero
+	// leaves them bounded only by the request
+	CallTimeout time.Duration
+
+	// Streams keeps live order books over the Luno streaming API, nil when streaming is unavailable
+	Streams *stream.Manager
+
+	// Audit records every call to a tool that changes the account, nil disables auditing
+	Audit *audit.Log
+
+	// Domain is the Luno API domain the client talks to
+	Domain string
+
+	// Environment is the Luno environment of Domain, empty for other domains
+	Environment Environment
+
+	// Backend is what Luno API calls are sent to
+	Backend Backend
+
+	// Recorder records Luno API calls to a file, nil when not recording
+	Recorder *sdk.RecordingClient
+
+	// Tracing is true when tool calls and Luno API calls are exported as
+	// OpenTelemetry traces
+	Tracing bool
+
+	// Transport is the MCP transport the server is running on, set by the caller
+	Transport string
+
+	// Debug is true when Luno API debug mode is enabled
+	Debug bool
+
+	// Permissions are the tool tiers the server exposes
+	Permissions []Permission
+
+	// settings are the tool filters, allowed pairs, risk limits and log
+	// level, which can be reloaded while the server runs
+	settings atomic.Pointer[Settings]
+
+	maskedAPIKeyID string
 }
 
 // Mask a string to show only the first 4 characters and replace the rest with asterisks
@@ -38,39 +234,74 @@ func maskValue(s string) string {
 
 // Load loads the configuration from environment variables
 func Load(domainOverride string) (*Config, error) {
+	backend, err := ParseBackend(os.Getenv(EnvLunoBackend))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoBackend, err)
+	}
+
+	// Replaying a recording takes the place of a backend
+	recordPath := strings.TrimSpace(os.Getenv(EnvLunoRecordPath))
+	replayPath := strings.TrimSpace(os.Getenv(EnvLunoReplayPath))
+	if replayPath != "" {
+		if backend == BackendFake {
+			return nil, fmt.Errorf("%s can't be used with %s=%s", EnvLunoReplayPath, EnvLunoBackend, BackendFake)
+		}
+		if recordPath != "" {
+			return nil, fmt.Errorf("%s can't be used with %s", EnvLunoRecordPath, EnvLunoReplayPath)
+		}
+		backend = BackendReplay
+	}
+
+	// Only the live backend needs credentials
 	apiKeyID := os.Getenv(strings.TrimSpace(EnvLunoAPIKeyID))
 	apiKeySecret := os.Getenv(strings.TrimSpace(EnvLunoAPIKeySecret))
 
-	fmt.Printf("LUNO_API_KEY_ID value: %s (length: %d)\n", maskValue(apiKeyID), len(apiKeyID))
-	fmt.Printf("LUNO_API_SECRET value: %s (length: %d)\n", maskValue(apiKeySecret), len(apiKeySecret))
-
-	if apiKeyID == "" || apiKeySecret == "" {
+	if backend == BackendLive && (apiKeyID == "" || apiKeySecret == "") {
 		return nil, errors.New("luno API credentials not found, please set LUNO_API_KEY_ID and LUNO_API_SECRET environment variables")
 	}
+	slog.Debug("Loaded Luno API credentials", slog.String("api_key_id", maskValue(apiKeyID)))
 
 	// Set domain - first check command line override, then env var, then default
 	domain := DefaultLunoDomain
+	domainSet := false
 
 	// Check for environment variable override
 	if envDomain := os.Getenv(strings.TrimSpace(EnvLunoAPIDomain)); envDomain != "" {
 		domain = envDomain
-		fmt.Printf("Using domain from environment variable: %s\n", domain)
+		domainSet = true
+		slog.Info("Using domain from environment variable", slog.String("domain", domain))
 	}
 
 	// Command line override takes precedence if provided
 	if domainOverride != "" {
 		domain = domainOverride
-		fmt.Printf("Using domain from command line: %s\n", domain)
+		domainSet = true
+		slog.Info("Using domain from command line", slog.String("domain", domain))
 	}
 
-	// Create Luno client
-	client := luno.NewClient()
-	if domain != DefaultLunoDomain {
-		client.SetBaseURL(fmt.Sprintf("https://%s", domain))
-	}
-	err := client.SetAuth(apiKeyID, apiKeySecret)
+	// A selected environment sets the domain, which must then agree with any domain set as well
+	environment, err := ParseEnvironment(os.Getenv(EnvLunoEnv))
 	if err != nil {
-		return nil, fmt.Errorf("failed to set Luno API credentials: %w", err)
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoEnv, err)
+	}
+	environmentSelected := environment != ""
+	if environmentSelected {
+		if domainSet && domain != environment.Domain() {
+			return nil, fmt.Errorf("%s=%s uses domain %s, but the domain is set to %s", EnvLunoEnv, environment, environment.Domain(), domain)
+		}
+		domain = environment.Domain()
+		slog.Info("Using Luno environment", slog.String("environment", string(environment)), slog.String("domain", domain))
+	} else {
+		environment = environmentOf(domain)
+	}
+
+	// Other backends are offline, so they have no domain or environment
+	if backend != BackendLive {
+		if environmentSelected {
+			return nil, fmt.Errorf("%s can't be used with the %s backend", EnvLunoEnv, backend)
+		}
+		domain, environment = "", ""
+		slog.Info("Using an offline backend, no calls will reach Luno", slog.String("backend", string(backend)))
 	}
 
 	// Check if debug mode is enabled via environment variable
@@ -82,14 +313,588 @@ func Load(domainOverride string) (*Config, error) {
 			strings.ToLower(debugEnv) == "yes"
 
 		if debugMode {
-			fmt.Println("Debug mode enabled via environment variable")
+			slog.Info("Debug mode enabled via environment variable")
 		}
 	}
 
-	client.SetDebug(debugMode)
-	return &Config{
-		LunoClient: client,
-	}, nil
+	var (
+		lunoClient sdk.LunoClient
+		profiles   []Profile
+		secrets    []string
+		clockSkew  *sdk.ClockSkewTracker
+	)
+	switch backend {
+	case BackendFake:
+		lunoClient, err = newFakeClient(os.Getenv(EnvLunoFakeSeed), os.Getenv(EnvLunoPaperBalances))
+		profiles = []Profile{{Name: DefaultProfile, Default: true}}
+	case BackendReplay:
+		lunoClient, err = newReplayClient(replayPath)
+		profiles = []Profile{{Name: DefaultProfile, Default: true}}
+	default:
+		// Track clock skew so auth failures can be explained
+		clockSkew = sdk.NewClockSkewTracker(nil)
+		lunoClient, profiles, secrets, err = newLiveClient(domain, apiKeyID, apiKeySecret, clockSkew, debugMode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Recording sits under the cache, so it only sees calls that reach the backend
+	var recorder *sdk.RecordingClient
+	if recordPath != "" {
+		if recorder, err = sdk.NewRecordingClient(lunoClient, recordPath); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvLunoRecordPath, err)
+		}
+		lunoClient = recorder
+		slog.Warn("Recording Luno API calls, the recording holds account details", slog.String("path", recordPath))
+	}
+
+	// Traced calls are the ones that reach the backend, after the cache
+	tracingEnabled := tracing.Enabled(os.Getenv)
+	if tracingEnabled {
+		lunoClient = sdk.NewTracingClient(lunoClient, tracing.Tracer())
+		slog.Info("OpenTelemetry tracing enabled")
+	}
+
+	permissions, err := ParsePermissions(os.Getenv(EnvLunoPermissions))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoPermissions, err)
+	}
+
+	settings, err := LoadSettings(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings.AllowedPairs) > 0 {
+		slog.Info("Trading restricted to allowed pairs", slog.Any("pairs", settings.AllowedPairs))
+	}
+
+	cacheTTLs, err := parseCacheTTLs(os.Getenv(EnvLunoCacheTTL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoCacheTTL, err)
+	}
+	cache := sdk.NewCache(cacheTTLs)
+
+	resourceRefresh, err := parseRefreshInterval(os.Getenv(EnvLunoRefreshPeriod))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoRefreshPeriod, err)
+	}
+
+	transactionsPerAccount, err := parseTransactionsPerAccount(os.Getenv(EnvLunoTxnsPerAcct))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoTxnsPerAcct, err)
+	}
+
+	callTimeout, err := parseCallTimeout(os.Getenv(EnvLunoCallTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoCallTimeout, err)
+	}
+
+	maxResultRows, err := parseMaxResultRows(os.Getenv(EnvLunoMaxResultRows))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoMaxResultRows, err)
+	}
+
+	var submissions *idempotency.Store
+	dedupWindow, err := parseDedupWindow(os.Getenv(EnvLunoDedupWindow))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoDedupWindow, err)
+	}
+	if dedupWindow > 0 {
+		submissions = idempotency.NewStore(dedupWindow)
+	}
+
+	sessionCalls, err := parseSessionCallsPerMinute(os.Getenv(EnvLunoSessionLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoSessionLimit, err)
+	}
+
+	alertRegistry := alerts.NewRegistry(alerts.DefaultMaxPerSession)
+	orderWatch := orderwatch.NewWatcher(orderwatch.DefaultMaxPerSession)
+	notifications := outbox.New()
+	store, schedules, err := loadState(alertRegistry, orderWatch, notifications)
+	if err != nil {
+		return nil, err
+	}
+
+	// Per-session state is dropped along with the session. The stdio session
+	// only ends when the server stops and is the same one when it starts
+	// again, so its state is kept when there is a state file.
+	exportStore := exports.NewStore(exports.DefaultTTL)
+	sessions := session.NewManager(sessionCalls)
+	sessions.OnEnd(func(id string) {
+		exportStore.DeleteSession(id)
+		if store != nil && id == session.StdioID {
+			return
+		}
+		alertRegistry.DeleteSession(id)
+		orderWatch.DeleteSession(id)
+		notifications.DeleteSession(id)
+	})
+	if isEnabled(os.Getenv(EnvLunoSessionCredentials)) {
+		if backend != BackendLive {
+			return nil, fmt.Errorf("%s needs the live backend, not %s", EnvLunoSessionCredentials, backend)
+		}
+		sessions.SetClientFactory(func(c session.Credentials) (sdk.LunoClient, error) {
+			client, err := newLunoClient(domain, c.KeyID, c.Secret, clockSkew, debugMode)
+			if err != nil {
+				return nil, err
+			}
+			return sdk.NewRetryingClient(client), nil
+		})
+		slog.Info("Sessions may use their own Luno API credentials")
+	}
+
+	timeouts, err := LoadToolTimeouts(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmations *security.TokenStore
+	if isEnabled(os.Getenv(EnvLunoConfirmWrite)) {
+		confirmations = security.NewTokenStore(security.DefaultTokenTTL)
+		slog.Info("Write confirmation enabled via environment variable")
+	}
+
+	authenticator, err := LoadAuth(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	cors, err := LoadCORS(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := LoadRedactor(os.Getenv, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var auditLog *audit.Log
+	if path := strings.TrimSpace(os.Getenv(EnvLunoAuditLogPath)); path != "" {
+		if auditLog, err = audit.Open(path); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvLunoAuditLogPath, err)
+		}
+		slog.Info("Audit log enabled", slog.String("path", path))
+	}
+
+	// The streaming API is only served by the production domain
+	var streams *stream.Manager
+	if backend == BackendLive && domain == DefaultLunoDomain {
+		streams = stream.NewManager(stream.LunoDialer(apiKeyID, apiKeySecret), stream.DefaultMaxStreams)
+	}
+
+	dryRun := isEnabled(os.Getenv(EnvLunoDryRun))
+	if dryRun {
+		slog.Info("Dry-run mode enabled via environment variable, no orders will be submitted")
+	}
+
+	// Paper trading wraps the cache, so the order books it fills against are
+	// cached too. The fake backend always simulates trading itself.
+	lunoClient = sdk.NewCachingClient(lunoClient, cache)
+	paperTrading := backend == BackendFake || isEnabled(os.Getenv(EnvLunoPaperTrading))
+	if paperTrading && backend != BackendFake {
+		balances, err := parseLimits(os.Getenv(EnvLunoPaperBalances))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvLunoPaperBalances, err)
+		}
+		lunoClient = sdk.NewPaperClient(lunoClient, balances)
+		slog.Info("Paper trading enabled via environment variable, orders will be simulated", slog.Int("assets", len(balances)))
+	}
+
+	// Choosing production explicitly refuses real writes unless they are allowed as well
+	writesRefused := environmentSelected && environment == EnvironmentProduction &&
+		!isEnabled(os.Getenv(EnvLunoAllowProductionWrites)) && !dryRun && !paperTrading
+	if writesRefused {
+		slog.Warn("Write tools are disabled in production", slog.String("allow_with", EnvLunoAllowProductionWrites+"=true"))
+	}
+
+	cfg := &Config{
+		LunoClient:             lunoClient,
+		Profiles:               profiles,
+		Cache:                  cache,
+		ClockSkew:              clockSkew,
+		Notes:                  notes.NewStore(),
+		Metrics:                toolmw.NewMetrics(),
+		Alerts:                 alertRegistry,
+		OrderWatch:             orderWatch,
+		Outbox:                 notifications,
+		Exports:                exportStore,
+		State:                  store,
+		Schedules:              schedules,
+		Sessions:               sessions,
+		Auth:                   authenticator,
+		CORS:                   cors,
+		Redactor:               redactor,
+		Confirmations:          confirmations,
+		DryRun:                 dryRun,
+		PaperTrading:           paperTrading,
+		WritesRefused:          writesRefused,
+		Environment:            environment,
+		Backend:                backend,
+		Recorder:               recorder,
+		Tracing:                tracingEnabled,
+		Submissions:            submissions,
+		Audit:                  auditLog,
+		Streams:                streams,
+		ResourceRefresh:        resourceRefresh,
+		TransactionsPerAccount: transactionsPerAccount,
+		MaxResultRows:          maxResultRows,
+		Timeouts:               timeouts,
+		CallTimeout:            callTimeout,
+		Domain:                 domain,
+		Debug:                  debugMode,
+		Permissions:            permissions,
+		maskedAPIKeyID:         maskValue(apiKeyID),
+	}
+	cfg.SetSettings(settings)
+	return cfg, nil
+}
+
+// newLiveClient creates the Luno API client for the default credentials and
+// any profiles, returning the profiles and the secrets to redact
+func newLiveClient(domain, apiKeyID, apiKeySecret string, clockSkew *sdk.ClockSkewTracker, debug bool) (sdk.LunoClient, []Profile, []string, error) {
+	client, err := newLunoClient(domain, apiKeyID, apiKeySecret, clockSkew, debug)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	extraProfiles, err := loadProfileCredentials(os.Environ())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	profiles := []Profile{{Name: DefaultProfile, APIKeyID: maskValue(apiKeyID), Default: true}}
+	secrets := []string{apiKeyID, apiKeySecret}
+	// Each profile gets its own retrying client, since rate limits are per API key.
+	// The profile client also routes calls to a session's own client.
+	clients := map[string]sdk.LunoClient{DefaultProfile: sdk.NewRetryingClient(client)}
+	for _, p := range extraProfiles {
+		c, err := newLunoClient(domain, p.keyID, p.secret, clockSkew, debug)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("profile %q: %w", p.name, err)
+		}
+		clients[p.name] = sdk.NewRetryingClient(c)
+		profiles = append(profiles, Profile{Name: p.name, APIKeyID: maskValue(p.keyID)})
+		secrets = append(secrets, p.keyID, p.secret)
+	}
+	if len(extraProfiles) > 0 {
+		slog.Info("Loaded credential profiles", slog.Int("count", len(profiles)))
+	}
+	return sdk.NewProfileClient(DefaultProfile, clients), profiles, secrets, nil
+}
+
+// newLunoClient creates an authenticated Luno API client
+func newLunoClient(domain, keyID, secret string, transport http.RoundTripper, debug bool) (*luno.Client, error) {
+	client := luno.NewClient()
+	client.SetHTTPClient(&http.Client{Timeout: clientTimeout, Transport: transport})
+	if domain != DefaultLunoDomain {
+		client.SetBaseURL(fmt.Sprintf("https://%s", domain))
+	}
+	if err := client.SetAuth(keyID, secret); err != nil {
+		return nil, fmt.Errorf("failed to set Luno API credentials: %w", err)
+	}
+	client.SetDebug(debug)
+	return client, nil
+}
+
+// isEnabled reports whether an environment variable value turns a feature on
+func isEnabled(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "1", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// Redacted returns a view of the configuration that is safe to share,
+// with credentials masked
+func (c *Config) Redacted() map[string]any {
+	permissions := c.Permissions
+	if permissions == nil {
+		permissions = DefaultPermissions
+	}
+	settings := c.Settings()
+	return map[string]any{
+		"transport":   c.Transport,
+		"environment": c.Environment,
+		"backend":     c.Backend,
+		"domain":      c.Domain,
+		"debug":       c.Debug,
+		"permissions": permissions,
+		"tools": map[string][]string{
+			"enabled":  settings.EnabledTools,
+			"disabled": settings.DisabledTools,
+		},
+		"profiles": c.Profiles,
+		"auth":     c.authInfo(),
+		"cors": map[string][]string{
+			"allowed_origins": c.CORS.AllowedOrigins,
+			"allowed_headers": c.CORS.AllowedHeaders,
+			"allowed_methods": c.CORS.AllowedMethods,
+		},
+		"log_redaction": c.Redactor.Categories(),
+		"guardrails": map[string]any{
+			"order_validation":   true,
+			"write_confirmation": c.Confirmations != nil,
+			"dry_run":            c.DryRun,
+			"writes_refused":     c.WritesRefused,
+			"risk_limits":        limitsInfo(settings.Limits),
+			"allowed_pairs":      settings.AllowedPairs,
+			"duplicate_orders":   c.dedupInfo(),
+			"retries":            sdk.DefaultMaxRetries,
+		},
+		"cache":                    c.cacheInfo(),
+		"resource_refresh":         c.ResourceRefresh.String(),
+		"transactions_per_account": c.TransactionsPerAccount,
+		"max_result_rows":          c.MaxResultRows,
+		"log_level":                settings.LogLevel.String(),
+		"tool_timeouts":            c.Timeouts.info(),
+		"call_timeout":             c.CallTimeout.String(),
+		"streams":                  c.streamsInfo(),
+		"tool_metrics":             c.metricsInfo(),
+		"sessions":                 c.sessionsInfo(),
+		"features": map[string]bool{
+			"notes":                c.Notes != nil,
+			"price_alerts":         c.Alerts != nil,
+			"order_watch":          c.OrderWatch != nil,
+			"scheduled_orders":     c.Schedules != nil,
+			"persistent_state":     c.State != nil,
+			"paper_trading":        c.PaperTrading,
+			"support_bundles":      c.Support != nil,
+			"clock_skew_detection": c.ClockSkew != nil,
+			"audit_log":            c.Audit != nil,
+			"live_order_books":     c.Streams != nil,
+			"recording":            c.Recorder != nil,
+			"tracing":              c.Tracing,
+		},
+		"api_key_id": c.maskedAPIKeyID,
+		"api_secret": "********",
+	}
+}
+
+// limitsInfo describes the risk limits and today's placed order value
+func limitsInfo(limits *RiskLimits) map[string]any {
+	if limits == nil {
+		return map[string]any{"enabled": false}
+	}
+	info := limits.summary()
+	info["enabled"] = true
+	return info
+}
+
+// dedupInfo describes duplicate order detection
+func (c *Config) dedupInfo() map[string]any {
+	if c.Submissions == nil {
+		return map[string]any{"enabled": false}
+	}
+	return map[string]any{"enabled": true, "window": c.Submissions.Window().String()}
+}
+
+// sessionsInfo describes the connected sessions and their rate limit
+func (c *Config) sessionsInfo() map[string]any {
+	if c.Sessions == nil {
+		return map[string]any{"enabled": false}
+	}
+	return map[string]any{
+		"enabled":          true,
+		"active":           c.Sessions.Count(),
+		"calls_per_minute": c.Sessions.CallsPerMinute(),
+		"own_credentials":  c.Sessions.AcceptsCredentials(),
+		"using_own":        c.Sessions.CredentialSessions(),
+	}
+}
+
+// streamsInfo describes the streamed order books
+func (c *Config) streamsInfo() map[string]any {
+	if c.Streams == nil {
+		return map[string]any{"enabled": false}
+	}
+	return map[string]any{"enabled": true, "pairs": c.Streams.Pairs()}
+}
+
+// metricsInfo returns the call counts of each tool that has been called
+func (c *Config) metricsInfo() map[string]toolmw.ToolStats {
+	if c.Metrics == nil {
+		return nil
+	}
+	return c.Metrics.Snapshot()
+}
+
+// cacheInfo describes the market data cache TTLs and hit counts
+func (c *Config) cacheInfo() map[string]any {
+	if c.Cache == nil {
+		return map[string]any{"enabled": false}
+	}
+	ttls := c.Cache.TTLs()
+	return map[string]any{
+		"enabled": true,
+		"ttls": map[string]string{
+			"ticker":     ttls.Ticker.String(),
+			"order_book": ttls.OrderBook.String(),
+			"trades":     ttls.Trades.String(),
+			"markets":    ttls.Markets.String(),
+		},
+		"stats": c.Cache.Stats(),
+	}
+}
+
+// parseCacheTTLs parses a single duration applied to all cached market data,
+// such as "3s". An empty string returns sdk.DefaultCacheTTLs and "0" disables caching.
+func parseCacheTTLs(s string) (sdk.CacheTTLs, error) {
+	if strings.TrimSpace(s) == "" {
+		return sdk.DefaultCacheTTLs, nil
+	}
+	ttl, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return sdk.CacheTTLs{}, err
+	}
+	if ttl < 0 {
+		return sdk.CacheTTLs{}, errors.New("cache TTL cannot be negative")
+	}
+	if ttl == 0 {
+		return sdk.CacheTTLs{}, nil
+	}
+	// The market list changes far less often than market data, so it keeps its own TTL
+	return sdk.CacheTTLs{Ticker: ttl, OrderBook: ttl, Trades: ttl, Markets: sdk.DefaultCacheTTLs.Markets}, nil
+}
+
+// parseRefreshInterval parses the resource refresh interval. An empty string
+// returns DefaultResourceRefresh and "0" disables refreshing.
+func parseRefreshInterval(s string) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultResourceRefresh, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("refresh interval cannot be negative")
+	}
+	return d, nil
+}
+
+// parseTransactionsPerAccount parses the number of transactions the
+// transactions resource returns per account. An empty string returns
+// DefaultTransactionsPerAccount.
+func parseTransactionsPerAccount(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultTransactionsPerAccount, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if n < 1 || n > maxTransactionsPerAccount {
+		return 0, fmt.Errorf("must be between 1 and %d", maxTransactionsPerAccount)
+	}
+	return n, nil
+}
+
+// parseCallTimeout parses the timeout of parallel Luno calls. An empty string
+// returns DefaultCallTimeout and "0" disables the timeout.
+func parseCallTimeout(s string) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultCallTimeout, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("call timeout cannot be negative")
+	}
+	return d, nil
+}
+
+// parseDedupWindow parses how long submitted orders are remembered to reject
+// duplicates. An empty string returns idempotency.DefaultWindow and "0"
+// disables duplicate detection.
+func parseDedupWindow(s string) (time.Duration, error) {
+	if strings.TrimSpace(s) == "" {
+		return idempotency.DefaultWindow, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("duplicate order window cannot be negative")
+	}
+	return d, nil
+}
+
+// parseMaxResultRows parses how many rows of a list a tool returns. An
+// empty string returns DefaultMaxResultRows and "0" removes the limit.
+func parseMaxResultRows(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultMaxResultRows, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("max result rows cannot be negative")
+	}
+	return n, nil
+}
+
+// loadState opens the state file, if one is set, restoring the alerts,
+// watched orders and undelivered notifications kept in it and the schedules. Scheduling needs a state file,
+// so without one there is no scheduler.
+func loadState(alertRegistry *alerts.Registry, orderWatch *orderwatch.Watcher, notifications *outbox.Outbox) (*state.Store, *schedule.Scheduler, error) {
+	env := EnvLunoStatePath
+	path := strings.TrimSpace(os.Getenv(env))
+	if path == "" {
+		env = EnvLunoSchedulesPath
+		path = strings.TrimSpace(os.Getenv(env))
+	}
+	if path == "" {
+		return nil, nil, nil
+	}
+
+	store, err := state.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", env, err)
+	}
+	// Other sessions get new IDs when clients reconnect, so nothing could
+	// receive their alerts and watches
+	keep := func(id string) bool { return id == session.StdioID }
+	if err := alertRegistry.Persist(store, keep); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", env, err)
+	}
+	if err := orderWatch.Persist(store, keep); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", env, err)
+	}
+	if err := notifications.Persist(store, keep); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", env, err)
+	}
+	schedules, err := schedule.Open(store, schedule.DefaultMaxSchedules)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", env, err)
+	}
+	slog.Info("State kept in file", slog.String("path", path))
+	return store, schedules, nil
+}
+
+// parseSessionCallsPerMinute parses how many tool calls each session may make
+// a minute. An empty string returns session.DefaultCallsPerMinute and "0"
+// removes the limit.
+func parseSessionCallsPerMinute(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return session.DefaultCallsPerMinute, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("session calls per minute cannot be negative")
+	}
+	return n, nil
 }
 
 // FormatCurrency formats a decimal amount with the currency code
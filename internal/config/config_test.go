@@ -1,11 +1,23 @@
 package config
 
 import (
+	"context"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/luno/luno-go"
 	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/idempotency"
+	"github.com/luno/luno-mcp/internal/orderwatch"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/internal/tracing"
+	"github.com/luno/luno-mcp/sdk"
 )
 
 func TestMaskValue(t *testing.T) {
@@ -68,6 +80,8 @@ func TestLoad(t *testing.T) {
 	originalAPISecret := os.Getenv(EnvLunoAPIKeySecret)
 	originalAPIDomain := os.Getenv(EnvLunoAPIDomain)
 	originalAPIDebug := os.Getenv(EnvLunoAPIDebug)
+	originalPermissions := os.Getenv(EnvLunoPermissions)
+	originalCacheTTL := os.Getenv(EnvLunoCacheTTL)
 
 	defer func() {
 		// Restore original environment
@@ -75,6 +89,8 @@ func TestLoad(t *testing.T) {
 		setEnvVar(EnvLunoAPIKeySecret, originalAPISecret)
 		setEnvVar(EnvLunoAPIDomain, originalAPIDomain)
 		setEnvVar(EnvLunoAPIDebug, originalAPIDebug)
+		setEnvVar(EnvLunoPermissions, originalPermissions)
+		setEnvVar(EnvLunoCacheTTL, originalCacheTTL)
 	}()
 
 	tests := []struct {
@@ -84,6 +100,8 @@ func TestLoad(t *testing.T) {
 		domainEnv      string
 		domainOverride string
 		debugEnv       string
+		permissionsEnv string
+		cacheTTLEnv    string
 		expectedError  string
 		expectedDomain string
 	}{
@@ -144,6 +162,26 @@ func TestLoad(t *testing.T) {
 			apiSecret: "test_secret",
 			debugEnv:  "false",
 		},
+		{
+			name:           "permissions from environment",
+			apiKeyID:       "test_key_id",
+			apiSecret:      "test_secret",
+			permissionsEnv: "read,withdraw",
+		},
+		{
+			name:           "invalid permissions",
+			apiKeyID:       "test_key_id",
+			apiSecret:      "test_secret",
+			permissionsEnv: "read,admin",
+			expectedError:  "invalid LUNO_PERMISSIONS",
+		},
+		{
+			name:          "invalid cache ttl",
+			apiKeyID:      "test_key_id",
+			apiSecret:     "test_secret",
+			cacheTTLEnv:   "soon",
+			expectedError: "invalid LUNO_CACHE_TTL",
+		},
 	}
 
 	for _, tc := range tests {
@@ -153,6 +191,8 @@ func TestLoad(t *testing.T) {
 			setEnvVar(EnvLunoAPIKeySecret, tc.apiSecret)
 			setEnvVar(EnvLunoAPIDomain, tc.domainEnv)
 			setEnvVar(EnvLunoAPIDebug, tc.debugEnv)
+			setEnvVar(EnvLunoPermissions, tc.permissionsEnv)
+			setEnvVar(EnvLunoCacheTTL, tc.cacheTTLEnv)
 
 			cfg, err := Load(tc.domainOverride)
 
@@ -184,6 +224,75 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestRedacted(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "test_key_id")
+	t.Setenv(EnvLunoAPIKeySecret, "test_secret")
+	t.Setenv(EnvLunoAPIDomain, "")
+	t.Setenv(EnvLunoAPIDebug, "")
+	t.Setenv(EnvLunoPermissions, "")
+	t.Setenv(EnvLunoCacheTTL, "")
+	t.Setenv(EnvLunoConfirmWrite, "true")
+	t.Setenv(EnvLunoDryRun, "yes")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Confirmations == nil {
+		t.Error("Expected write confirmation to be enabled")
+	}
+
+	redacted := cfg.Redacted()
+	if guardrails, _ := redacted["guardrails"].(map[string]any); guardrails["write_confirmation"] != true {
+		t.Errorf("Expected write_confirmation guardrail, got %v", redacted["guardrails"])
+	}
+	if !cfg.DryRun {
+		t.Error("Expected dry-run mode to be enabled")
+	}
+	if redacted["domain"] != DefaultLunoDomain {
+		t.Errorf("Expected domain %q, got %v", DefaultLunoDomain, redacted["domain"])
+	}
+	if redacted["api_key_id"] != "test*******" {
+		t.Errorf("Expected masked key id, got %v", redacted["api_key_id"])
+	}
+	for k, v := range redacted {
+		if s, ok := v.(string); ok && strings.Contains(s, "test_secret") {
+			t.Errorf("Secret leaked in %q", k)
+		}
+	}
+}
+
+func TestLoadWritesNothingToStdout(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "test_key_id")
+	t.Setenv(EnvLunoAPIKeySecret, "test_secret")
+	t.Setenv(EnvLunoAPIDomain, "staging.api.luno.com")
+	t.Setenv(EnvLunoAPIDebug, "true")
+	t.Setenv(EnvLunoConfirmWrite, "true")
+	t.Setenv(EnvLunoDryRun, "true")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, loadErr := Load("")
+	os.Stdout = stdout
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadErr != nil {
+		t.Fatalf("Unexpected error: %v", loadErr)
+	}
+	// Under the stdio transport anything on stdout corrupts the protocol
+	if len(out) > 0 {
+		t.Errorf("Load wrote to stdout: %q", out)
+	}
+}
+
 // Helper function to set environment variable, handling empty values
 func setEnvVar(key, value string) {
 	if value == "" {
@@ -192,3 +301,642 @@ func setEnvVar(key, value string) {
 		os.Setenv(key, value)
 	}
 }
+
+func TestParseCacheTTLs(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      sdk.CacheTTLs
+		expectedError bool
+	}{
+		{"empty uses defaults", "", sdk.DefaultCacheTTLs, false},
+		{"single ttl for market data", "3s", sdk.CacheTTLs{Ticker: 3 * time.Second, OrderBook: 3 * time.Second, Trades: 3 * time.Second, Markets: time.Minute}, false},
+		{"zero disables", "0", sdk.CacheTTLs{}, false},
+		{"negative", "-1s", sdk.CacheTTLs{}, true},
+		{"invalid", "soon", sdk.CacheTTLs{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseCacheTTLs(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseCacheTTLs(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseCacheTTLs(%q) = %+v, want %+v", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      time.Duration
+		expectedError bool
+	}{
+		{"empty uses default", "", DefaultResourceRefresh, false},
+		{"duration", "1m", time.Minute, false},
+		{"zero disables", "0", 0, false},
+		{"negative", "-1s", 0, true},
+		{"invalid", "often", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseRefreshInterval(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseRefreshInterval(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseRefreshInterval(%q) = %v, want %v", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseCallTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      time.Duration
+		expectedError bool
+	}{
+		{"empty uses default", "", DefaultCallTimeout, false},
+		{"duration", " 5s ", 5 * time.Second, false},
+		{"zero disables", "0", 0, false},
+		{"negative", "-1s", 0, true},
+		{"invalid", "soon", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseCallTimeout(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseCallTimeout(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseCallTimeout(%q) = %v, want %v", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseDedupWindow(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      time.Duration
+		expectedError bool
+	}{
+		{"empty uses default", "", idempotency.DefaultWindow, false},
+		{"duration", " 10m ", 10 * time.Minute, false},
+		{"zero disables", "0", 0, false},
+		{"negative", "-1m", 0, true},
+		{"invalid", "forever", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseDedupWindow(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseDedupWindow(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseDedupWindow(%q) = %v, want %v", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseSessionCallsPerMinute(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      int
+		expectedError bool
+	}{
+		{"empty uses default", "", session.DefaultCallsPerMinute, false},
+		{"number", " 60 ", 60, false},
+		{"zero removes the limit", "0", 0, false},
+		{"negative", "-1", 0, true},
+		{"invalid", "lots", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseSessionCallsPerMinute(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseSessionCallsPerMinute(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseSessionCallsPerMinute(%q) = %d, want %d", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	t.Setenv(EnvLunoStatePath, "")
+	t.Setenv(EnvLunoSchedulesPath, "")
+	store, schedules, err := loadState(alerts.NewRegistry(1), orderwatch.NewWatcher(1), outbox.New())
+	if err != nil || store != nil || schedules != nil {
+		t.Fatalf("loadState() without a path = %v, %v, %v, want nothing", store, schedules, err)
+	}
+
+	// The older variable is still read
+	path := filepath.Join(t.TempDir(), "state.json")
+	t.Setenv(EnvLunoSchedulesPath, path)
+	store, _, err = loadState(alerts.NewRegistry(1), orderwatch.NewWatcher(1), outbox.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.Path() != path {
+		t.Errorf("state path = %q, want %q", store.Path(), path)
+	}
+
+	registry := alerts.NewRegistry(1)
+	if _, _, err := loadState(registry, orderwatch.NewWatcher(1), outbox.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, id := range []string{session.StdioID, "sse-session"} {
+		if _, err := registry.Add(id, "XBTZAR", alerts.ConditionAbove, decimal.NewFromInt64(1000000)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Only the stdio session is the same after a restart
+	registry = alerts.NewRegistry(1)
+	if _, _, err := loadState(registry, orderwatch.NewWatcher(1), outbox.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := len(registry.List(session.StdioID)); got != 1 {
+		t.Errorf("stdio session has %d alerts after a restart, want 1", got)
+	}
+	if got := len(registry.List("sse-session")); got != 0 {
+		t.Errorf("SSE session has %d alerts after a restart, want 0", got)
+	}
+
+	t.Setenv(EnvLunoStatePath, filepath.Join(t.TempDir(), "corrupt.json"))
+	if err := os.WriteFile(os.Getenv(EnvLunoStatePath), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadState(alerts.NewRegistry(1), orderwatch.NewWatcher(1), outbox.New()); err == nil || !strings.Contains(err.Error(), EnvLunoStatePath) {
+		t.Errorf("loadState() with a corrupt file = %v, want an error naming %s", err, EnvLunoStatePath)
+	}
+}
+
+func TestLoadPaperTrading(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "test_key_id")
+	t.Setenv(EnvLunoAPIKeySecret, "test_secret")
+	t.Setenv(EnvLunoPaperTrading, "true")
+	t.Setenv(EnvLunoPaperBalances, "zar:100000, XBT:0.5")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cfg.PaperTrading {
+		t.Error("Expected paper trading to be enabled")
+	}
+	// Balances come from the simulated portfolio without calling Luno
+	res, err := cfg.LunoClient.GetBalances(context.Background(), &luno.GetBalancesRequest{Assets: []string{"ZAR"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res.Balance) != 1 || res.Balance[0].Balance.Cmp(decimal.NewFromInt64(100000)) != 0 {
+		t.Errorf("paper balances = %+v, want ZAR 100000", res.Balance)
+	}
+
+	t.Setenv(EnvLunoPaperBalances, "ZAR:lots")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "invalid "+EnvLunoPaperBalances) {
+		t.Errorf("Load() with invalid paper balances = %v, want an error naming %s", err, EnvLunoPaperBalances)
+	}
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "test_key_id")
+	t.Setenv(EnvLunoAPIKeySecret, "test_secret")
+	t.Setenv(EnvLunoAPIDomain, "")
+	t.Setenv(EnvLunoDryRun, "")
+	t.Setenv(EnvLunoPaperTrading, "")
+
+	tests := []struct {
+		name           string
+		env            string
+		domainEnv      string
+		allowWrites    string
+		dryRun         string
+		expectedError  string
+		expectedDomain string
+		expectedEnv    Environment
+		writesRefused  bool
+	}{
+		{
+			name:           "default domain is production",
+			expectedDomain: DefaultLunoDomain,
+			expectedEnv:    EnvironmentProduction,
+		},
+		{
+			name:           "staging",
+			env:            "Staging",
+			expectedDomain: StagingLunoDomain,
+			expectedEnv:    EnvironmentStaging,
+		},
+		{
+			name:           "staging domain without an environment",
+			domainEnv:      StagingLunoDomain,
+			expectedDomain: StagingLunoDomain,
+			expectedEnv:    EnvironmentStaging,
+		},
+		{
+			name:           "custom domain has no environment",
+			domainEnv:      "luno.internal",
+			expectedDomain: "luno.internal",
+		},
+		{
+			name:           "production refuses writes",
+			env:            "production",
+			expectedDomain: DefaultLunoDomain,
+			expectedEnv:    EnvironmentProduction,
+			writesRefused:  true,
+		},
+		{
+			name:           "production with writes allowed",
+			env:            "production",
+			allowWrites:    "true",
+			expectedDomain: DefaultLunoDomain,
+			expectedEnv:    EnvironmentProduction,
+		},
+		{
+			name:           "production in dry-run mode",
+			env:            "production",
+			dryRun:         "true",
+			expectedDomain: DefaultLunoDomain,
+			expectedEnv:    EnvironmentProduction,
+		},
+		{
+			name:          "unknown environment",
+			env:           "qa",
+			expectedError: "invalid LUNO_ENV",
+		},
+		{
+			name:          "domain disagrees with environment",
+			env:           "staging",
+			domainEnv:     DefaultLunoDomain,
+			expectedError: "LUNO_ENV=staging uses domain api.staging.luno.com, but the domain is set to api.luno.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvLunoEnv, tc.env)
+			t.Setenv(EnvLunoAPIDomain, tc.domainEnv)
+			t.Setenv(EnvLunoAllowProductionWrites, tc.allowWrites)
+			t.Setenv(EnvLunoDryRun, tc.dryRun)
+
+			cfg, err := Load("")
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.Domain != tc.expectedDomain {
+				t.Errorf("domain = %q, want %q", cfg.Domain, tc.expectedDomain)
+			}
+			if cfg.Environment != tc.expectedEnv {
+				t.Errorf("environment = %q, want %q", cfg.Environment, tc.expectedEnv)
+			}
+			if cfg.WritesRefused != tc.writesRefused {
+				t.Errorf("writes refused = %v, want %v", cfg.WritesRefused, tc.writesRefused)
+			}
+			if refused := cfg.WritesRefusedReason(PermissionTrade) != ""; refused != tc.writesRefused {
+				t.Errorf("trade tools refused = %v, want %v", refused, tc.writesRefused)
+			}
+			if cfg.WritesRefusedReason(PermissionRead) != "" {
+				t.Error("Read tools should never be refused")
+			}
+		})
+	}
+}
+
+func TestLoadFakeBackend(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "")
+	t.Setenv(EnvLunoAPIKeySecret, "")
+	t.Setenv(EnvLunoAPIDomain, "")
+	t.Setenv(EnvLunoEnv, "")
+	t.Setenv(EnvLunoPaperTrading, "")
+	t.Setenv(EnvLunoPaperBalances, "")
+	t.Setenv(EnvLunoFakeSeed, "7")
+	t.Setenv(EnvLunoBackend, "Fake")
+
+	// The fake backend runs without credentials or calls to Luno
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Backend != BackendFake {
+		t.Errorf("backend = %q, want %q", cfg.Backend, BackendFake)
+	}
+	if !cfg.PaperTrading {
+		t.Error("Expected the fake backend to simulate trading")
+	}
+	if cfg.Domain != "" || cfg.Environment != "" || cfg.Streams != nil {
+		t.Errorf("fake backend has domain %q, environment %q and streams %v, want none", cfg.Domain, cfg.Environment, cfg.Streams)
+	}
+	res, err := cfg.LunoClient.GetBalances(context.Background(), &luno.GetBalancesRequest{Assets: []string{"ZAR"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res.Balance) != 1 || res.Balance[0].Balance.Cmp(decimal.NewFromInt64(100000)) != 0 {
+		t.Errorf("fake balances = %+v, want the default ZAR 100000", res.Balance)
+	}
+
+	tests := []struct {
+		name          string
+		env           string
+		value         string
+		expectedError string
+	}{
+		{name: "invalid seed", env: EnvLunoFakeSeed, value: "lucky", expectedError: "invalid " + EnvLunoFakeSeed},
+		{name: "invalid balances", env: EnvLunoPaperBalances, value: "ZAR:lots", expectedError: "invalid " + EnvLunoPaperBalances},
+		{name: "environment", env: EnvLunoEnv, value: "staging", expectedError: "LUNO_ENV can't be used with the fake backend"},
+		{name: "unknown backend", env: EnvLunoBackend, value: "mock", expectedError: "invalid " + EnvLunoBackend},
+		{name: "session credentials", env: EnvLunoSessionCredentials, value: "true", expectedError: EnvLunoSessionCredentials + " needs the live backend"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.env, tc.value)
+			if _, err := Load(""); err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLoadSessionCredentials(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "test_key_id")
+	t.Setenv(EnvLunoAPIKeySecret, "test_key_secret")
+	t.Setenv(EnvLunoBackend, "")
+	t.Setenv(EnvLunoEnv, "")
+	t.Setenv(EnvLunoReplayPath, "")
+	t.Setenv(EnvLunoRecordPath, "")
+
+	t.Setenv(EnvLunoSessionCredentials, "")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Sessions.AcceptsCredentials() {
+		t.Error("Expected sessions to use the server's credentials by default")
+	}
+
+	t.Setenv(EnvLunoSessionCredentials, "true")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := cfg.Sessions.Get("s1")
+	if err := cfg.Sessions.UseCredentials(s, session.Credentials{KeyID: "own_key_id", Secret: "own_secret"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.Client() == nil {
+		t.Error("Expected the session to get its own client")
+	}
+}
+
+func TestLoadTracing(t *testing.T) {
+	t.Setenv(EnvLunoBackend, string(BackendFake))
+	t.Setenv(EnvLunoEnv, "")
+	t.Setenv(EnvLunoReplayPath, "")
+	t.Setenv(EnvLunoRecordPath, "")
+	t.Setenv(tracing.EnvSDKDisabled, "")
+	t.Setenv(tracing.EnvOTLPTracesEndpoint, "")
+
+	t.Setenv(tracing.EnvOTLPEndpoint, "")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Tracing {
+		t.Error("Expected tracing to be off without an OTLP endpoint")
+	}
+
+	t.Setenv(tracing.EnvOTLPEndpoint, "http://localhost:4318")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cfg.Tracing {
+		t.Error("Expected tracing to be on with an OTLP endpoint")
+	}
+}
+
+func TestLoadRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	t.Setenv(EnvLunoAPIKeyID, "")
+	t.Setenv(EnvLunoAPIKeySecret, "")
+	t.Setenv(EnvLunoAPIDomain, "")
+	t.Setenv(EnvLunoEnv, "")
+	t.Setenv(EnvLunoPaperTrading, "")
+	t.Setenv(EnvLunoPaperBalances, "ZAR:2500")
+	t.Setenv(EnvLunoFakeSeed, "")
+	t.Setenv(EnvLunoReplayPath, "")
+	t.Setenv(EnvLunoBackend, string(BackendFake))
+	t.Setenv(EnvLunoRecordPath, path)
+
+	ctx := context.Background()
+	req := &luno.GetBalancesRequest{Assets: []string{"ZAR"}}
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Recorder == nil {
+		t.Fatal("Expected calls to be recorded")
+	}
+	if _, err := cfg.LunoClient.GetBalances(ctx, req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cfg.Recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The recording is served back without credentials or a backend
+	t.Setenv(EnvLunoBackend, "")
+	t.Setenv(EnvLunoRecordPath, "")
+	t.Setenv(EnvLunoPaperBalances, "")
+	t.Setenv(EnvLunoReplayPath, path)
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Backend != BackendReplay || cfg.Streams != nil {
+		t.Errorf("backend = %q with streams %v, want %q without streams", cfg.Backend, cfg.Streams, BackendReplay)
+	}
+	res, err := cfg.LunoClient.GetBalances(ctx, req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res.Balance) != 1 || res.Balance[0].Balance.Cmp(decimal.NewFromInt64(2500)) != 0 {
+		t.Errorf("replayed balances = %+v, want the recorded ZAR 2500", res.Balance)
+	}
+
+	tests := []struct {
+		name          string
+		env           string
+		value         string
+		expectedError string
+	}{
+		{name: "fake backend", env: EnvLunoBackend, value: "fake", expectedError: "LUNO_REPLAY_PATH can't be used with LUNO_BACKEND=fake"},
+		{name: "recording", env: EnvLunoRecordPath, value: path, expectedError: "LUNO_RECORD_PATH can't be used with LUNO_REPLAY_PATH"},
+		{name: "missing recording", env: EnvLunoReplayPath, value: path + ".missing", expectedError: "invalid " + EnvLunoReplayPath},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.env, tc.value)
+			if _, err := Load(""); err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestParseMaxResultRows(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      int
+		expectedError bool
+	}{
+		{"empty uses default", "", DefaultMaxResultRows, false},
+		{"number", " 50 ", 50, false},
+		{"zero removes the limit", "0", 0, false},
+		{"negative", "-1", 0, true},
+		{"invalid", "lots", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseMaxResultRows(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseMaxResultRows(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseMaxResultRows(%q) = %d, want %d", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseTransactionsPerAccount(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      int
+		expectedError bool
+	}{
+		{"empty uses default", "", DefaultTransactionsPerAccount, false},
+		{"number", " 50 ", 50, false},
+		{"zero", "0", 0, true},
+		{"too many", "1001", 0, true},
+		{"invalid", "lots", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseTransactionsPerAccount(tc.input)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("parseTransactionsPerAccount(%q) expected error, got nil", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("parseTransactionsPerAccount(%q) = %d, want %d", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLoadToolTimeouts(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		expectedDefault time.Duration
+		expectedPnL     time.Duration
+		expectedError   string
+	}{
+		{"defaults", map[string]string{}, DefaultToolTimeout, DefaultToolTimeout, ""},
+		{"default override", map[string]string{EnvLunoToolTimeout: "30s"}, 30 * time.Second, 30 * time.Second, ""},
+		{"zero disables", map[string]string{EnvLunoToolTimeout: "0"}, 0, 0, ""},
+		{"per tool", map[string]string{EnvLunoToolTimeouts: " Calculate_PnL:2m , get_ticker:5s"}, DefaultToolTimeout, 2 * time.Minute, ""},
+		{"negative default", map[string]string{EnvLunoToolTimeout: "-1s"}, 0, 0, "cannot be negative"},
+		{"missing duration", map[string]string{EnvLunoToolTimeouts: "calculate_pnl"}, 0, 0, "must look like TOOL:DURATION"},
+		{"invalid duration", map[string]string{EnvLunoToolTimeouts: "calculate_pnl:long"}, 0, 0, "invalid duration"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			timeouts, err := LoadToolTimeouts(func(k string) string { return tc.env[k] })
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if timeouts.Default != tc.expectedDefault {
+				t.Errorf("Default = %v, want %v", timeouts.Default, tc.expectedDefault)
+			}
+			if got := timeouts.For("calculate_pnl"); got != tc.expectedPnL {
+				t.Errorf("For(calculate_pnl) = %v, want %v", got, tc.expectedPnL)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Environment variables for cross-origin requests to the SSE transport
+const (
+	// EnvMCPCORSOrigins is a comma-separated list of origins browsers may
+	// connect from (e.g. "https://app.example.com,http://localhost:*"), "*" allows any
+	EnvMCPCORSOrigins = "MCP_CORS_ALLOWED_ORIGINS"
+	// EnvMCPCORSHeaders is a comma-separated list of request headers browsers may send
+	EnvMCPCORSHeaders = "MCP_CORS_ALLOWED_HEADERS"
+	// EnvMCPCORSMethods is a comma-separated list of methods browsers may use
+	EnvMCPCORSMethods = "MCP_CORS_ALLOWED_METHODS"
+)
+
+// corsAnyOrigin allows every origin and must be set explicitly
+const corsAnyOrigin = "*"
+
+var (
+	// DefaultCORSOrigins allow browser clients served from this machine
+	DefaultCORSOrigins = []string{"http://localhost:*", "http://127.0.0.1:*", "http://[::1]:*",
+		"https://localhost:*", "https://127.0.0.1:*", "https://[::1]:*"}
+	// DefaultCORSHeaders are the request headers MCP clients send
+	DefaultCORSHeaders = []string{"Authorization", "Content-Type", "Last-Event-Id", "Mcp-Session-Id"}
+	// DefaultCORSMethods are the methods of the SSE and message endpoints
+	DefaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodOptions}
+)
+
+// CORS is which browser origins may call the SSE transport, and with what
+type CORS struct {
+	// AllowedOrigins are scheme://host[:port] origins, where a port of "*"
+	// matches any port and an origin of "*" matches every origin
+	AllowedOrigins []string
+	// AllowedHeaders are the request headers allowed in preflight requests
+	AllowedHeaders []string
+	// AllowedMethods are the methods allowed in preflight requests
+	AllowedMethods []string
+}
+
+// LoadCORS reads the cross-origin settings from the environment, using the
+// localhost defaults for those that aren't set
+func LoadCORS(getenv func(string) string) (CORS, error) {
+	c := CORS{
+		AllowedOrigins: DefaultCORSOrigins,
+		AllowedHeaders: DefaultCORSHeaders,
+		AllowedMethods: DefaultCORSMethods,
+	}
+	if origins := splitList(getenv(EnvMCPCORSOrigins)); len(origins) > 0 {
+		for i, o := range origins {
+			origin, err := parseOrigin(o)
+			if err != nil {
+				return CORS{}, fmt.Errorf("invalid %s: %w", EnvMCPCORSOrigins, err)
+			}
+			origins[i] = origin
+		}
+		c.AllowedOrigins = origins
+	}
+	if headers := splitList(getenv(EnvMCPCORSHeaders)); len(headers) > 0 {
+		for i, h := range headers {
+			headers[i] = http.CanonicalHeaderKey(h)
+		}
+		c.AllowedHeaders = headers
+	}
+	if methods := splitList(getenv(EnvMCPCORSMethods)); len(methods) > 0 {
+		for i, m := range methods {
+			methods[i] = strings.ToUpper(m)
+		}
+		c.AllowedMethods = methods
+	}
+	return c, nil
+}
+
+// AllowsAnyOrigin reports whether every origin was explicitly allowed
+func (c CORS) AllowsAnyOrigin() bool {
+	return slices.Contains(c.AllowedOrigins, corsAnyOrigin)
+}
+
+// AllowsOrigin reports whether a browser at origin may call the server
+func (c CORS) AllowsOrigin(origin string) bool {
+	if c.AllowsAnyOrigin() {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+		// A port of "*" matches the host on any port, or none
+		if prefix, ok := strings.CutSuffix(allowed, ":*"); ok {
+			if origin == prefix {
+				return true
+			}
+			if port, ok := strings.CutPrefix(origin, prefix+":"); ok && port != "" && strings.Trim(port, "0123456789") == "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseOrigin checks that s is "*" or an http or https origin with no path,
+// and returns it in lower case
+func parseOrigin(s string) (string, error) {
+	if s == corsAnyOrigin {
+		return s, nil
+	}
+	s = strings.ToLower(strings.TrimSuffix(s, "/"))
+	// A wildcard port isn't a valid URL port, so check the origin without it
+	u, err := url.Parse(strings.TrimSuffix(s, ":*"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+		return "", fmt.Errorf("origin %q must look like https://host[:port]", s)
+	}
+	return s, nil
+}
+
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if item := strings.TrimSpace(part); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadCORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		expectedOrigins []string
+		expectedHeaders []string
+		expectedError   string
+	}{
+		{"defaults", map[string]string{}, DefaultCORSOrigins, DefaultCORSHeaders, ""},
+		{"origins", map[string]string{EnvMCPCORSOrigins: "https://App.example.com/, http://localhost:*"}, []string{"https://app.example.com", "http://localhost:*"}, DefaultCORSHeaders, ""},
+		{"any origin", map[string]string{EnvMCPCORSOrigins: "*"}, []string{"*"}, DefaultCORSHeaders, ""},
+		{"headers", map[string]string{EnvMCPCORSHeaders: "authorization, x-request-id"}, DefaultCORSOrigins, []string{"Authorization", "X-Request-Id"}, ""},
+		{"path", map[string]string{EnvMCPCORSOrigins: "https://app.example.com/mcp"}, nil, nil, "must look like https://host[:port]"},
+		{"no scheme", map[string]string{EnvMCPCORSOrigins: "app.example.com"}, nil, nil, "must look like https://host[:port]"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cors, err := LoadCORS(func(k string) string { return tc.env[k] })
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(cors.AllowedOrigins, tc.expectedOrigins) {
+				t.Errorf("Expected origins %v, got %v", tc.expectedOrigins, cors.AllowedOrigins)
+			}
+			if !slices.Equal(cors.AllowedHeaders, tc.expectedHeaders) {
+				t.Errorf("Expected headers %v, got %v", tc.expectedHeaders, cors.AllowedHeaders)
+			}
+		})
+	}
+}
+
+func TestCORSAllowsOrigin(t *testing.T) {
+	cors := CORS{AllowedOrigins: DefaultCORSOrigins}
+	tests := []struct {
+		origin   string
+		expected bool
+	}{
+		{"http://localhost:3000", true},
+		{"http://LOCALHOST:3000", true},
+		{"http://localhost", true},
+		{"https://127.0.0.1:8443", true},
+		{"http://[::1]:8080", true},
+		{"http://localhost:3000.evil.com", false},
+		{"http://localhost.evil.com", false},
+		{"https://app.example.com", false},
+		{"null", false},
+	}
+	for _, tc := range tests {
+		if got := cors.AllowsOrigin(tc.origin); got != tc.expected {
+			t.Errorf("AllowsOrigin(%q) = %v, want %v", tc.origin, got, tc.expected)
+		}
+	}
+	if !(CORS{AllowedOrigins: []string{"*"}}).AllowsOrigin("https://anywhere.example.com") {
+		t.Error("Expected * to allow any origin")
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Environment variables for selecting a Luno environment
+const (
+	// EnvLunoEnv selects the Luno environment, which sets the API domain
+	EnvLunoEnv = "LUNO_ENV"
+	// EnvLunoAllowProductionWrites allows write tools when LUNO_ENV is production
+	EnvLunoAllowProductionWrites = "LUNO_ALLOW_PRODUCTION_WRITES"
+)
+
+// StagingLunoDomain is the Luno API domain of the staging environment
+const StagingLunoDomain = "api.staging.luno.com"
+
+// Environment is a Luno deployment the server talks to
+type Environment string
+
+const (
+	// EnvironmentProduction is Luno's live exchange
+	EnvironmentProduction Environment = "production"
+	// EnvironmentStaging is Luno's staging exchange, for testing
+	EnvironmentStaging Environment = "staging"
+)
+
+// ParseEnvironment parses an environment name. An empty string returns an
+// empty environment, which leaves the domain to the other settings.
+func ParseEnvironment(s string) (Environment, error) {
+	switch env := Environment(strings.ToLower(strings.TrimSpace(s))); env {
+	case "", EnvironmentProduction, EnvironmentStaging:
+		return env, nil
+	default:
+		return "", fmt.Errorf("unknown environment %q, must be production or staging", s)
+	}
+}
+
+// Domain returns the Luno API domain of the environment
+func (e Environment) Domain() string {
+	if e == EnvironmentStaging {
+		return StagingLunoDomain
+	}
+	return DefaultLunoDomain
+}
+
+// environmentOf returns the environment a domain belongs to, or an empty
+// environment for any other domain
+func environmentOf(domain string) Environment {
+	switch domain {
+	case DefaultLunoDomain:
+		return EnvironmentProduction
+	case StagingLunoDomain:
+		return EnvironmentStaging
+	default:
+		return ""
+	}
+}
+
+// WritesRefusedReason explains why tools that need p are unavailable in the
+// environment, or returns an empty string if they are available
+func (c *Config) WritesRefusedReason(p Permission) string {
+	if !c.WritesRefused || p == PermissionRead {
+		return ""
+	}
+	return fmt.Sprintf("writes are refused when %s is production, set %s=true to allow them",
+		EnvLunoEnv, EnvLunoAllowProductionWrites)
+}
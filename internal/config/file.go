@@ -0,0 +1,306 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig is the structure of a YAML config file. Every setting maps to
+// an environment variable, and environment variables that are already set
+// take precedence over the file.
+type FileConfig struct {
+	Credentials   FileCredentials            `yaml:"credentials"`
+	Backend       string                     `yaml:"backend"`
+	FakeSeed      *int64                     `yaml:"fake_seed"`
+	RecordPath    string                     `yaml:"record_path"`
+	ReplayPath    string                     `yaml:"replay_path"`
+	Environment   string                     `yaml:"environment"`
+	Domain        string                     `yaml:"domain"`
+	Debug         *bool                      `yaml:"debug"`
+	Permissions   []string                   `yaml:"permissions"`
+	Tools         FileTools                  `yaml:"tools"`
+	CacheTTL      string                     `yaml:"cache_ttl"`
+	Refresh       string                     `yaml:"resource_refresh_interval"`
+	TxPerAccount  *int                       `yaml:"transactions_per_account"`
+	CallTimeout   string                     `yaml:"call_timeout"`
+	DedupWindow   string                     `yaml:"duplicate_order_window"`
+	SessionCalls  *int                       `yaml:"session_calls_per_minute"`
+	MaxResultRows *int                       `yaml:"max_result_rows"`
+	ToolTimeout   string                     `yaml:"tool_timeout"`
+	ToolTimeouts  map[string]string          `yaml:"tool_timeouts"`
+	ConfirmWrites *bool                      `yaml:"confirm_writes"`
+	DryRun        *bool                      `yaml:"dry_run"`
+	ProdWrites    *bool                      `yaml:"allow_production_writes"`
+	PaperTrading  *bool                      `yaml:"paper_trading"`
+	PaperBalances map[string]string          `yaml:"paper_balances"`
+	AuditLogPath  string                     `yaml:"audit_log_path"`
+	StatePath     string                     `yaml:"state_path"`
+	SchedulesPath string                     `yaml:"schedules_path"`
+	Limits        FileLimits                 `yaml:"limits"`
+	Auth          FileAuth                   `yaml:"auth"`
+	CORS          FileCORS                   `yaml:"cors"`
+	LogRedact     []string                   `yaml:"log_redact"`
+	LogLevel      string                     `yaml:"log_level"`
+	AllowedPairs  []string                   `yaml:"allowed_trading_pairs"`
+	Profiles      map[string]FileCredentials `yaml:"profiles"`
+}
+
+// FileCredentials are an API key. To keep the secret out of the config file,
+// it can be read from another environment variable or file instead.
+type FileCredentials struct {
+	APIKeyID      string `yaml:"api_key_id"`
+	APISecret     string `yaml:"api_secret"`
+	APISecretEnv  string `yaml:"api_secret_env"`
+	APISecretFile string `yaml:"api_secret_file"`
+}
+
+// FileTools are the tool allow and deny lists
+type FileTools struct {
+	Enabled  []string `yaml:"enabled"`
+	Disabled []string `yaml:"disabled"`
+}
+
+// FileLimits are risk limits, keyed by counter currency or pair
+type FileLimits struct {
+	MaxOrderValue             map[string]string `yaml:"max_order_value"`
+	MaxPairOrderValue         map[string]string `yaml:"max_pair_order_value"`
+	MaxDailyTradeValue        map[string]string `yaml:"max_daily_trade_value"`
+	MaxSessionDailyTradeValue map[string]string `yaml:"max_session_daily_trade_value"`
+}
+
+// FileAuth is how clients of the SSE transport are authorized
+type FileAuth struct {
+	Mode     string   `yaml:"mode"`
+	Tokens   []string `yaml:"tokens"`
+	Issuer   string   `yaml:"issuer"`
+	Audience string   `yaml:"audience"`
+}
+
+// FileCORS is which browser origins may call the SSE transport
+type FileCORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+}
+
+// loadedFile is the config file ApplyFile loaded and the environment
+// variables it set, which reloading it replaces
+var loadedFile struct {
+	sync.Mutex
+	path    string
+	applied []string
+}
+
+// ApplyFile reads a YAML config file and sets every environment variable
+// it configures that is not already set, so that Load picks them up
+func ApplyFile(path string) error {
+	env, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	loadedFile.Lock()
+	defer loadedFile.Unlock()
+	applied, err := setUnsetEnv(env)
+	if err != nil {
+		return err
+	}
+	loadedFile.path, loadedFile.applied = path, applied
+	slog.Info("Loaded config file", slog.String("path", path), slog.Any("settings", applied))
+	return nil
+}
+
+// reloadFile reads the config file ApplyFile loaded again, replacing the
+// environment variables it set before. Variables that were set some other
+// way still take precedence. It does nothing if no file was loaded.
+func reloadFile() error {
+	loadedFile.Lock()
+	defer loadedFile.Unlock()
+	if loadedFile.path == "" {
+		return nil
+	}
+	env, err := readFile(loadedFile.path)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range loadedFile.applied {
+		if err := os.Unsetenv(k); err != nil {
+			return err
+		}
+	}
+	applied, err := setUnsetEnv(env)
+	loadedFile.applied = applied
+	if err != nil {
+		return err
+	}
+	slog.Info("Reloaded config file", slog.String("path", loadedFile.path), slog.Any("settings", applied))
+	return nil
+}
+
+// readFile reads a config file and converts it to the environment variables
+// it sets
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	env, err := parseFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return env, nil
+}
+
+// setUnsetEnv sets the environment variables in env that are not already
+// set and returns their names in order
+func setUnsetEnv(env map[string]string) ([]string, error) {
+	var applied []string
+	for k, v := range env {
+		if _, ok := os.LookupEnv(k); ok {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return applied, err
+		}
+		applied = append(applied, k)
+	}
+	slices.Sort(applied)
+	return applied, nil
+}
+
+// parseFile converts a config file to the environment variables it sets
+func parseFile(data []byte) (map[string]string, error) {
+	var f FileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	set := func(k, v string) {
+		if v != "" {
+			env[k] = v
+		}
+	}
+	setBool := func(k string, v *bool) {
+		if v != nil {
+			env[k] = fmt.Sprint(*v)
+		}
+	}
+
+	if err := f.Credentials.apply(env, EnvLunoAPIKeyID, EnvLunoAPIKeySecret); err != nil {
+		return nil, fmt.Errorf("credentials: %w", err)
+	}
+	for name, creds := range f.Profiles {
+		prefix := envProfilePrefix + strings.ToUpper(name)
+		if err := creds.apply(env, prefix+envProfileKeyIDSuffix, prefix+envProfileSecretSuffix); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+
+	set(EnvLunoBackend, f.Backend)
+	if f.FakeSeed != nil {
+		set(EnvLunoFakeSeed, strconv.FormatInt(*f.FakeSeed, 10))
+	}
+	set(EnvLunoRecordPath, f.RecordPath)
+	set(EnvLunoReplayPath, f.ReplayPath)
+	set(EnvLunoEnv, f.Environment)
+	set(EnvLunoAPIDomain, f.Domain)
+	setBool(EnvLunoAPIDebug, f.Debug)
+	set(EnvLunoPermissions, strings.Join(f.Permissions, ","))
+	set(EnvLunoToolsEnabled, strings.Join(f.Tools.Enabled, ","))
+	set(EnvLunoToolsDisabled, strings.Join(f.Tools.Disabled, ","))
+	set(EnvLunoCacheTTL, f.CacheTTL)
+	set(EnvLunoRefreshPeriod, f.Refresh)
+	if f.TxPerAccount != nil {
+		set(EnvLunoTxnsPerAcct, strconv.Itoa(*f.TxPerAccount))
+	}
+	set(EnvLunoCallTimeout, f.CallTimeout)
+	set(EnvLunoDedupWindow, f.DedupWindow)
+	if f.SessionCalls != nil {
+		set(EnvLunoSessionLimit, strconv.Itoa(*f.SessionCalls))
+	}
+	if f.MaxResultRows != nil {
+		set(EnvLunoMaxResultRows, strconv.Itoa(*f.MaxResultRows))
+	}
+	set(EnvLunoToolTimeout, f.ToolTimeout)
+	set(EnvLunoToolTimeouts, formatLimits(f.ToolTimeouts))
+	setBool(EnvLunoConfirmWrite, f.ConfirmWrites)
+	setBool(EnvLunoDryRun, f.DryRun)
+	setBool(EnvLunoAllowProductionWrites, f.ProdWrites)
+	setBool(EnvLunoPaperTrading, f.PaperTrading)
+	set(EnvLunoPaperBalances, formatLimits(f.PaperBalances))
+	set(EnvLunoAuditLogPath, f.AuditLogPath)
+	set(EnvLunoStatePath, f.StatePath)
+	set(EnvLunoSchedulesPath, f.SchedulesPath)
+	set(EnvLunoAllowedPairs, strings.Join(f.AllowedPairs, ","))
+	set(EnvLunoMaxOrderValue, formatLimits(f.Limits.MaxOrderValue))
+	set(EnvLunoMaxPairOrderValue, formatLimits(f.Limits.MaxPairOrderValue))
+	set(EnvLunoMaxDailyTradeValue, formatLimits(f.Limits.MaxDailyTradeValue))
+	set(EnvLunoMaxSessionDailyTradeValue, formatLimits(f.Limits.MaxSessionDailyTradeValue))
+	set(EnvMCPAuthMode, f.Auth.Mode)
+	set(EnvMCPAuthTokens, strings.Join(f.Auth.Tokens, ","))
+	set(EnvMCPAuthIssuer, f.Auth.Issuer)
+	set(EnvMCPAuthAudience, f.Auth.Audience)
+	set(EnvMCPCORSOrigins, strings.Join(f.CORS.AllowedOrigins, ","))
+	set(EnvMCPCORSHeaders, strings.Join(f.CORS.AllowedHeaders, ","))
+	set(EnvMCPCORSMethods, strings.Join(f.CORS.AllowedMethods, ","))
+	set(EnvLunoLogRedact, strings.Join(f.LogRedact, ","))
+	set(EnvLunoLogLevel, f.LogLevel)
+	return env, nil
+}
+
+// apply sets the environment variables for a set of credentials
+func (c FileCredentials) apply(env map[string]string, keyIDEnv, secretEnv string) error {
+	sources := 0
+	for _, s := range []string{c.APISecret, c.APISecretEnv, c.APISecretFile} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return errors.New("use only one of api_secret, api_secret_env and api_secret_file")
+	}
+
+	if c.APIKeyID != "" {
+		env[keyIDEnv] = c.APIKeyID
+	}
+	switch {
+	case c.APISecret != "":
+		env[secretEnv] = c.APISecret
+	case c.APISecretEnv != "":
+		if v := os.Getenv(c.APISecretEnv); v != "" {
+			env[secretEnv] = v
+		}
+	case c.APISecretFile != "":
+		b, err := os.ReadFile(c.APISecretFile)
+		if err != nil {
+			return fmt.Errorf("reading api_secret_file: %w", err)
+		}
+		env[secretEnv] = strings.TrimSpace(string(b))
+	}
+	return nil
+}
+
+// formatLimits formats limits in the KEY:AMOUNT list form of the limit
+// environment variables, which tool timeouts and paper balances share
+func formatLimits(limits map[string]string) string {
+	entries := make([]string, 0, len(limits))
+	for k, v := range limits {
+		entries = append(entries, k+":"+v)
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("file_secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_LUNO_SECRET", "env_secret")
+
+	tests := []struct {
+		name          string
+		yaml          string
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			name:     "empty file",
+			yaml:     "",
+			expected: map[string]string{},
+		},
+		{
+			name: "all settings",
+			yaml: `
+credentials:
+  api_key_id: key_id
+  api_secret_file: ` + secretFile + `
+backend: live
+fake_seed: 42
+record_path: /tmp/luno-calls.jsonl
+replay_path: /tmp/luno-replay.jsonl
+environment: staging
+domain: staging.api.luno.com
+debug: false
+permissions: [read, trade]
+tools:
+  enabled: [get_ticker, get_order_book]
+  disabled: [create_order]
+cache_ttl: 5s
+resource_refresh_interval: 1m
+transactions_per_account: 50
+call_timeout: 5s
+duplicate_order_window: 10m
+session_calls_per_minute: 60
+max_result_rows: 50
+tool_timeout: 20s
+tool_timeouts:
+  calculate_pnl: 2m
+  generate_statement: 1m
+confirm_writes: true
+dry_run: true
+allow_production_writes: false
+paper_trading: true
+paper_balances:
+  ZAR: 100000
+  XBT: "0.5"
+audit_log_path: /var/log/luno-audit.jsonl
+state_path: /var/lib/luno-mcp/state.json
+allowed_trading_pairs: [XBTZAR, ETHZAR]
+limits:
+  max_order_value:
+    ZAR: 50000
+    EUR: "2500.50"
+  max_daily_trade_value:
+    ZAR: 100000
+  max_session_daily_trade_value:
+    ZAR: 25000
+cors:
+  allowed_origins: [https://app.example.com, "http://localhost:*"]
+log_redact: [addresses]
+auth:
+  mode: oidc
+  issuer: https://auth.example.com
+  audience: luno-mcp
+profiles:
+  savings:
+    api_key_id: savings_id
+    api_secret_env: TEST_LUNO_SECRET
+`,
+			expected: map[string]string{
+				EnvLunoAPIKeyID:                   "key_id",
+				EnvLunoAPIKeySecret:               "file_secret",
+				EnvLunoBackend:                    "live",
+				EnvLunoFakeSeed:                   "42",
+				EnvLunoRecordPath:                 "/tmp/luno-calls.jsonl",
+				EnvLunoReplayPath:                 "/tmp/luno-replay.jsonl",
+				EnvLunoEnv:                        "staging",
+				EnvLunoAPIDomain:                  "staging.api.luno.com",
+				EnvLunoAPIDebug:                   "false",
+				EnvLunoPermissions:                "read,trade",
+				EnvLunoToolsEnabled:               "get_ticker,get_order_book",
+				EnvLunoToolsDisabled:              "create_order",
+				EnvLunoCacheTTL:                   "5s",
+				EnvLunoRefreshPeriod:              "1m",
+				EnvLunoTxnsPerAcct:                "50",
+				EnvLunoCallTimeout:                "5s",
+				EnvLunoDedupWindow:                "10m",
+				EnvLunoSessionLimit:               "60",
+				EnvLunoMaxResultRows:              "50",
+				EnvLunoToolTimeout:                "20s",
+				EnvLunoToolTimeouts:               "calculate_pnl:2m,generate_statement:1m",
+				EnvLunoConfirmWrite:               "true",
+				EnvLunoDryRun:                     "true",
+				EnvLunoAllowProductionWrites:      "false",
+				EnvLunoPaperTrading:               "true",
+				EnvLunoPaperBalances:              "XBT:0.5,ZAR:100000",
+				EnvLunoAuditLogPath:               "/var/log/luno-audit.jsonl",
+				EnvLunoStatePath:                  "/var/lib/luno-mcp/state.json",
+				EnvLunoAllowedPairs:               "XBTZAR,ETHZAR",
+				EnvLunoMaxOrderValue:              "EUR:2500.50,ZAR:50000",
+				EnvLunoMaxDailyTradeValue:         "ZAR:100000",
+				EnvLunoMaxSessionDailyTradeValue:  "ZAR:25000",
+				EnvMCPCORSOrigins:                 "https://app.example.com,http://localhost:*",
+				EnvLunoLogRedact:                  "addresses",
+				EnvMCPAuthMode:                    "oidc",
+				EnvMCPAuthIssuer:                  "https://auth.example.com",
+				EnvMCPAuthAudience:                "luno-mcp",
+				"LUNO_PROFILE_SAVINGS_API_KEY_ID": "savings_id",
+				"LUNO_PROFILE_SAVINGS_API_SECRET": "env_secret",
+			},
+		},
+		{
+			name:          "unknown setting",
+			yaml:          "permisions: [read]",
+			expectedError: "field permisions not found",
+		},
+		{
+			name:          "several secret sources",
+			yaml:          "credentials:\n  api_secret: a\n  api_secret_env: B",
+			expectedError: "use only one of",
+		},
+		{
+			name:          "missing secret file",
+			yaml:          "credentials:\n  api_secret_file: /does/not/exist",
+			expectedError: "reading api_secret_file",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := parseFile([]byte(tc.yaml))
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(env) != len(tc.expected) {
+				t.Errorf("Expected %d settings, got %d: %v", len(tc.expected), len(env), env)
+			}
+			for k, v := range tc.expected {
+				if env[k] != v {
+					t.Errorf("%s = %q, want %q", k, env[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyFileEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "luno.yaml")
+	data := "domain: file.api.luno.com\ncache_ttl: 9s\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvLunoAPIDomain, "env.api.luno.com")
+	// Register the variable with t.Setenv so it is restored, then unset it
+	t.Setenv(EnvLunoCacheTTL, "")
+	os.Unsetenv(EnvLunoCacheTTL)
+
+	if err := ApplyFile(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := os.Getenv(EnvLunoAPIDomain); got != "env.api.luno.com" {
+		t.Errorf("Expected the environment to override the file, got %s", got)
+	}
+	if got := os.Getenv(EnvLunoCacheTTL); got != "9s" {
+		t.Errorf("Expected the file to fill in %s, got %s", EnvLunoCacheTTL, got)
+	}
+
+	if err := ApplyFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
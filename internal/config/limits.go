@@ -0,0 +1,274 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+)
+
+// Environment variables for risk limits. Each is a comma-separated list of
+// KEY:AMOUNT entries, with amounts in the counter currency of the order.
+const (
+	// EnvLunoMaxOrderValue caps the value of a single order, keyed by counter currency (e.g. "ZAR:50000,EUR:2500")
+	EnvLunoMaxOrderValue = "LUNO_MAX_ORDER_VALUE"
+	// EnvLunoMaxPairOrderValue caps the value of a single order, keyed by pair (e.g. "XBTZAR:20000")
+	EnvLunoMaxPairOrderValue = "LUNO_MAX_PAIR_ORDER_VALUE"
+	// EnvLunoMaxDailyTradeValue caps the value of orders placed per UTC day, keyed by counter currency
+	EnvLunoMaxDailyTradeValue = "LUNO_MAX_DAILY_TRADE_VALUE"
+	// EnvLunoMaxSessionDailyTradeValue caps the value of orders each MCP session places per UTC day, keyed by counter currency
+	EnvLunoMaxSessionDailyTradeValue = "LUNO_MAX_SESSION_DAILY_TRADE_VALUE"
+)
+
+// limitsStateSection is the state store section daily totals are saved in
+const limitsStateSection = "risk_limits"
+
+// savedLimits is the layout of the risk limits section of the state store
+type savedLimits struct {
+	Day   string                     `json:"day"`
+	Total map[string]decimal.Decimal `json:"total"`
+}
+
+// RiskLimits caps the value of orders the server will submit. The daily
+// limit applies to every order the server places, whichever session places
+// it, so reconnecting doesn't reset it. The session limit also caps each MCP
+// session's own total, so one agent can't use up the whole day's limit.
+// Totals reset at midnight UTC. The server's total is saved in the state
+// store, when there is one, so a restart doesn't reset it either.
+type RiskLimits struct {
+	// MaxOrderValue is the largest order value per counter currency
+	MaxOrderValue map[string]decimal.Decimal
+	// MaxPairOrderValue is the largest order value per pair
+	MaxPairOrderValue map[string]decimal.Decimal
+	// MaxDailyValue is the largest total order value per counter currency per day
+	MaxDailyValue map[string]decimal.Decimal
+	// MaxSessionDailyValue is the largest total order value per counter
+	// currency one session can place per day
+	MaxSessionDailyValue map[string]decimal.Decimal
+
+	mu     sync.Mutex
+	day    string
+	total  map[string]decimal.Decimal
+	placed map[string]map[string]decimal.Decimal
+	now    func() time.Time
+	store  *state.Store
+}
+
+// LoadRiskLimits reads risk limits from the environment.
+// It returns nil when no limits are set.
+func LoadRiskLimits(getenv func(string) string) (*RiskLimits, error) {
+	l := &RiskLimits{now: time.Now}
+	var err error
+	if l.MaxOrderValue, err = parseLimits(getenv(EnvLunoMaxOrderValue)); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoMaxOrderValue, err)
+	}
+	if l.MaxPairOrderValue, err = parseLimits(getenv(EnvLunoMaxPairOrderValue)); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoMaxPairOrderValue, err)
+	}
+	if l.MaxDailyValue, err = parseLimits(getenv(EnvLunoMaxDailyTradeValue)); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoMaxDailyTradeValue, err)
+	}
+	if l.MaxSessionDailyValue, err = parseLimits(getenv(EnvLunoMaxSessionDailyTradeValue)); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoMaxSessionDailyTradeValue, err)
+	}
+	if len(l.MaxOrderValue)+len(l.MaxPairOrderValue)+len(l.MaxDailyValue)+len(l.MaxSessionDailyValue) == 0 {
+		return nil, nil
+	}
+	return l, nil
+}
+
+// parseLimits parses entries such as "ZAR:50000,XBTZAR:20000"
+func parseLimits(s string) (map[string]decimal.Decimal, error) {
+	limits := make(map[string]decimal.Decimal)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, amount, ok := strings.Cut(part, ":")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("entry %q must look like CURRENCY:AMOUNT", part)
+		}
+		d, err := decimal.NewFromString(strings.TrimSpace(amount))
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount in %q: %w", part, err)
+		}
+		if d.Sign() < 0 {
+			return nil, fmt.Errorf("amount in %q cannot be negative", part)
+		}
+		limits[key] = d
+	}
+	return limits, nil
+}
+
+// Reserve checks an order a session places against the limits and, if it
+// is allowed, adds its value to today's totals. The returned function
+// removes the value again and must be called if the order is not placed.
+func (l *RiskLimits) Reserve(session, pair, counter string, value decimal.Decimal) (func(), error) {
+	pair, counter = strings.ToUpper(pair), strings.ToUpper(counter)
+
+	if limit, ok := l.MaxOrderValue[counter]; ok && value.Cmp(limit) > 0 {
+		return nil, fmt.Errorf("order value %s %s exceeds the maximum order value of %s %s set by %s",
+			value, counter, limit, counter, EnvLunoMaxOrderValue)
+	}
+	if limit, ok := l.MaxPairOrderValue[pair]; ok && value.Cmp(limit) > 0 {
+		return nil, fmt.Errorf("order value %s %s exceeds the maximum order value of %s %s for %s set by %s",
+			value, counter, limit, counter, pair, EnvLunoMaxPairOrderValue)
+	}
+
+	dailyLimit, daily := l.MaxDailyValue[counter]
+	sessionLimit, perSession := l.MaxSessionDailyValue[counter]
+	if !daily && !perSession {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	day := l.rolloverLocked()
+	if daily {
+		placed := l.total[counter]
+		if total := placed.Add(value); total.Cmp(dailyLimit) > 0 {
+			return nil, fmt.Errorf("order value %s %s would bring the total placed today to %s %s, over the daily limit of %s %s set by %s (%s %s remaining)",
+				value, counter, total, counter, dailyLimit, counter, EnvLunoMaxDailyTradeValue, dailyLimit.Sub(placed), counter)
+		}
+	}
+	if perSession {
+		placed := l.placed[session][counter]
+		if total := placed.Add(value); total.Cmp(sessionLimit) > 0 {
+			return nil, fmt.Errorf("order value %s %s would bring this session's total today to %s %s, over the session daily limit of %s %s set by %s (%s %s remaining)",
+				value, counter, total, counter, sessionLimit, counter, EnvLunoMaxSessionDailyTradeValue, sessionLimit.Sub(placed), counter)
+		}
+	}
+	l.addLocked(session, counter, value)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			// Nothing to undo once the day has rolled over
+			if l.rolloverLocked() == day {
+				l.addLocked(session, counter, value.Neg())
+			}
+		})
+	}, nil
+}
+
+// PlacedToday returns the total order value a session placed today per
+// counter currency
+func (l *RiskLimits) PlacedToday(session string) map[string]decimal.Decimal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rolloverLocked()
+	return maps.Clone(l.placed[session])
+}
+
+// Persist restores today's total saved in store, and saves every later
+// change to store. Session totals aren't saved, as sessions don't outlive
+// the server.
+func (l *RiskLimits) Persist(store *state.Store) error {
+	var saved savedLimits
+	if _, err := store.Load(limitsStateSection, &saved); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rolloverLocked() == saved.Day {
+		for counter, value := range saved.Total {
+			l.total[counter] = l.total[counter].Add(value)
+		}
+	}
+	l.store = store
+	l.saveLocked()
+	return nil
+}
+
+// rolloverLocked resets the daily totals when the UTC day changes and returns the current day
+func (l *RiskLimits) rolloverLocked() string {
+	now := time.Now
+	if l.now != nil {
+		now = l.now
+	}
+	day := now().UTC().Format(time.DateOnly)
+	if day != l.day || l.total == nil {
+		l.day = day
+		l.total = make(map[string]decimal.Decimal)
+		l.placed = make(map[string]map[string]decimal.Decimal)
+	}
+	return day
+}
+
+// carryOver continues the daily totals of the limits l replaces, so that
+// reloading the limits doesn't reset what has been placed today
+func (l *RiskLimits) carryOver(prev *RiskLimits) {
+	prev.mu.Lock()
+	day := prev.rolloverLocked()
+	total := maps.Clone(prev.total)
+	placed := make(map[string]map[string]decimal.Decimal, len(prev.placed))
+	for session, totals := range prev.placed {
+		placed[session] = maps.Clone(totals)
+	}
+	store := prev.store
+	prev.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.day = day
+	l.total = total
+	l.placed = placed
+	l.store = store
+}
+
+// addLocked adds value to today's total and the session's, and saves the
+// total
+func (l *RiskLimits) addLocked(session, counter string, value decimal.Decimal) {
+	l.total[counter] = l.total[counter].Add(value)
+	if l.placed[session] == nil {
+		l.placed[session] = make(map[string]decimal.Decimal)
+	}
+	l.placed[session][counter] = l.placed[session][counter].Add(value)
+	l.saveLocked()
+}
+
+func (l *RiskLimits) saveLocked() {
+	if l.store == nil {
+		return
+	}
+	if err := l.store.Save(limitsStateSection, savedLimits{Day: l.day, Total: l.total}); err != nil {
+		slog.Warn("Failed to save daily order totals", slog.Any("error", err))
+	}
+}
+
+// placedTotal returns the total order value placed today per counter
+// currency
+func (l *RiskLimits) placedTotal() map[string]decimal.Decimal {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rolloverLocked()
+	return maps.Clone(l.total)
+}
+
+// summary describes the limits for the redacted configuration
+func (l *RiskLimits) summary() map[string]any {
+	format := func(m map[string]decimal.Decimal) map[string]string {
+		out := make(map[string]string, len(m))
+		for k, v := range m {
+			out[k] = v.String()
+		}
+		return out
+	}
+	return map[string]any{
+		"max_order_value":               format(l.MaxOrderValue),
+		"max_pair_order_value":          format(l.MaxPairOrderValue),
+		"max_daily_trade_value":         format(l.MaxDailyValue),
+		"max_session_daily_trade_value": format(l.MaxSessionDailyValue),
+		"placed_today":                  format(l.placedTotal()),
+	}
+}
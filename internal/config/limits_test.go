@@ -0,0 +1,201 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+)
+
+func TestLoadRiskLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectNil     bool
+		expectedError string
+	}{
+		{"no limits", map[string]string{}, true, ""},
+		{"empty entries", map[string]string{EnvLunoMaxOrderValue: " , "}, true, ""},
+		{"order value", map[string]string{EnvLunoMaxOrderValue: "zar:50000, EUR:2500"}, false, ""},
+		{"pair and daily", map[string]string{EnvLunoMaxPairOrderValue: "XBTZAR:100", EnvLunoMaxDailyTradeValue: "ZAR:1000"}, false, ""},
+		{"session daily", map[string]string{EnvLunoMaxSessionDailyTradeValue: "ZAR:1000"}, false, ""},
+		{"missing amount", map[string]string{EnvLunoMaxOrderValue: "ZAR"}, false, "must look like CURRENCY:AMOUNT"},
+		{"invalid amount", map[string]string{EnvLunoMaxDailyTradeValue: "ZAR:lots"}, false, "invalid amount"},
+		{"negative amount", map[string]string{EnvLunoMaxPairOrderValue: "XBTZAR:-1"}, false, "cannot be negative"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limits, err := LoadRiskLimits(func(k string) string { return tc.env[k] })
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (limits == nil) != tc.expectNil {
+				t.Errorf("Expected nil limits: %v, got %v", tc.expectNil, limits)
+			}
+		})
+	}
+}
+
+func TestRiskLimitsReserve(t *testing.T) {
+	d := func(s string) decimal.Decimal {
+		v, err := decimal.NewFromString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	limits, err := LoadRiskLimits(func(k string) string {
+		return map[string]string{
+			EnvLunoMaxOrderValue:      "ZAR:1000",
+			EnvLunoMaxPairOrderValue:  "ETHZAR:500",
+			EnvLunoMaxDailyTradeValue: "ZAR:1500",
+		}[k]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits.now = func() time.Time { return now }
+
+	reserve := func(pair, value string) (func(), error) {
+		return limits.Reserve("stdio", pair, "ZAR", d(value))
+	}
+
+	if _, err := reserve("XBTZAR", "1001"); err == nil || !strings.Contains(err.Error(), EnvLunoMaxOrderValue) {
+		t.Errorf("Expected order value limit error, got %v", err)
+	}
+	if _, err := reserve("ETHZAR", "600"); err == nil || !strings.Contains(err.Error(), EnvLunoMaxPairOrderValue) {
+		t.Errorf("Expected pair limit error, got %v", err)
+	}
+
+	if _, err := reserve("XBTZAR", "1000"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release, err := reserve("XBTZAR", "400")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := reserve("XBTZAR", "200"); err == nil || !strings.Contains(err.Error(), "100 ZAR remaining") {
+		t.Errorf("Expected daily limit error, got %v", err)
+	}
+
+	// Releasing frees the value for another order, but only once
+	release()
+	release()
+	if got := limits.PlacedToday("stdio")["ZAR"]; got.Cmp(d("1000")) != 0 {
+		t.Errorf("Expected 1000 placed today, got %s", got)
+	}
+	if _, err := reserve("XBTZAR", "500"); err != nil {
+		t.Errorf("Unexpected error after release: %v", err)
+	}
+
+	// The daily limit covers every session, so a new one can't reset it
+	if _, err := limits.Reserve("other", "XBTZAR", "ZAR", d("100")); err == nil || !strings.Contains(err.Error(), "0 ZAR remaining") {
+		t.Errorf("Expected daily limit error for another session, got %v", err)
+	}
+	if got := limits.placedTotal()["ZAR"]; got.Cmp(d("1500")) != 0 {
+		t.Errorf("Expected 1500 placed today in total, got %s", got)
+	}
+
+	// Currencies without a daily limit aren't tracked
+	if _, err := limits.Reserve("stdio", "XBTEUR", "EUR", d("1000000")); err != nil {
+		t.Errorf("Unexpected error for unlimited currency: %v", err)
+	}
+
+	// Totals reset at midnight UTC
+	now = now.Add(2 * time.Hour)
+	if _, err := reserve("XBTZAR", "1000"); err != nil {
+		t.Errorf("Unexpected error on a new day: %v", err)
+	}
+}
+
+func TestRiskLimitsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	load := func() *RiskLimits {
+		store, err := state.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		limits, err := LoadRiskLimits(func(k string) string {
+			if k == EnvLunoMaxDailyTradeValue {
+				return "ZAR:1000"
+			}
+			return ""
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		limits.now = func() time.Time { return now }
+		if err := limits.Persist(store); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return limits
+	}
+
+	limits := load()
+	if _, err := limits.Reserve("stdio", "XBTZAR", "ZAR", decimal.NewFromInt64(600)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release, err := limits.Reserve("stdio", "XBTZAR", "ZAR", decimal.NewFromInt64(100))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release()
+
+	// A restart on the same day keeps the total, even for new sessions
+	limits = load()
+	if got := limits.placedTotal()["ZAR"]; got.Cmp(decimal.NewFromInt64(600)) != 0 {
+		t.Errorf("Expected 600 placed today after a restart, got %s", got)
+	}
+	if _, err := limits.Reserve("new", "XBTZAR", "ZAR", decimal.NewFromInt64(500)); err == nil {
+		t.Error("Expected the restored total to count towards the daily limit")
+	}
+
+	// Totals saved on an earlier day are dropped
+	now = now.Add(24 * time.Hour)
+	limits = load()
+	if got := limits.placedTotal()["ZAR"]; got.Sign() != 0 {
+		t.Errorf("Expected nothing placed on a new day, got %s", got)
+	}
+}
+
+func TestRiskLimitsSessionDailyLimit(t *testing.T) {
+	limits, err := LoadRiskLimits(func(k string) string {
+		return map[string]string{
+			EnvLunoMaxDailyTradeValue:        "ZAR:1500",
+			EnvLunoMaxSessionDailyTradeValue: "ZAR:1000",
+		}[k]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := limits.Reserve("a", "XBTZAR", "ZAR", decimal.NewFromInt64(800)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := limits.Reserve("a", "XBTZAR", "ZAR", decimal.NewFromInt64(300)); err == nil || !strings.Contains(err.Error(), EnvLunoMaxSessionDailyTradeValue) {
+		t.Errorf("Expected session daily limit error, got %v", err)
+	}
+
+	// Another session has its own session total, but shares the daily one
+	if _, err := limits.Reserve("b", "XBTZAR", "ZAR", decimal.NewFromInt64(600)); err != nil {
+		t.Errorf("Unexpected error for another session: %v", err)
+	}
+	if _, err := limits.Reserve("c", "XBTZAR", "ZAR", decimal.NewFromInt64(200)); err == nil || !strings.Contains(err.Error(), EnvLunoMaxDailyTradeValue+" (100 ZAR remaining)") {
+		t.Errorf("Expected daily limit error, got %v", err)
+	}
+	if got := limits.PlacedToday("a")["ZAR"]; got.Cmp(decimal.NewFromInt64(800)) != 0 {
+		t.Errorf("Expected 800 placed today by the first session, got %s", got)
+	}
+}
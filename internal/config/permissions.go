@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Permission is a tier of tools the server may expose
+type Permission string
+
+const (
+	// PermissionRead allows tools that only read account and market data
+	PermissionRead Permission = "read"
+	// PermissionTrade allows tools that place or cancel orders, create accounts or
+	// move funds between your own accounts
+	PermissionTrade Permission = "trade"
+	// PermissionWithdraw allows tools that move funds out of the account
+	PermissionWithdraw Permission = "withdraw"
+)
+
+// DefaultPermissions are used when LUNO_PERMISSIONS is not set
+var DefaultPermissions = []Permission{PermissionRead, PermissionTrade}
+
+// allPermissions lists every known permission, in order
+var allPermissions = []Permission{PermissionRead, PermissionTrade, PermissionWithdraw}
+
+// ParsePermissions parses a comma-separated list of permissions such as "read,trade".
+// An empty string returns DefaultPermissions.
+func ParsePermissions(s string) ([]Permission, error) {
+	if strings.TrimSpace(s) == "" {
+		return slices.Clone(DefaultPermissions), nil
+	}
+
+	var perms []Permission
+	for _, part := range strings.Split(s, ",") {
+		p := Permission(strings.ToLower(strings.TrimSpace(part)))
+		if p == "" {
+			continue
+		}
+		if !slices.Contains(allPermissions, p) {
+			return nil, fmt.Errorf("unknown permission %q, must be one of read, trade or withdraw", part)
+		}
+		if !slices.Contains(perms, p) {
+			perms = append(perms, p)
+		}
+	}
+	return perms, nil
+}
+
+// Allows reports whether the configuration grants the permission.
+// A nil Permissions field means DefaultPermissions.
+func (c *Config) Allows(p Permission) bool {
+	if c.Permissions == nil {
+		return slices.Contains(DefaultPermissions, p)
+	}
+	return slices.Contains(c.Permissions, p)
+}
+
+// ParseToolNames parses a comma-separated list of tool names such as
+// "get_ticker,get_order_book", dropping empty entries and duplicates
+func ParseToolNames(s string) []string {
+	var names []string
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ToolFilterReason returns why the tool allow and deny lists exclude a tool,
+// or an empty string if they don't. The deny list wins when a tool is in both.
+func (c *Config) ToolFilterReason(name string) string {
+	settings := c.Settings()
+	if slices.Contains(settings.DisabledTools, name) {
+		return fmt.Sprintf("disabled by %s", EnvLunoToolsDisabled)
+	}
+	if len(settings.EnabledTools) > 0 && !slices.Contains(settings.EnabledTools, name) {
+		return fmt.Sprintf("not listed in %s", EnvLunoToolsEnabled)
+	}
+	return ""
+}
+
+// ParsePairs parses a comma-separated list of trading pairs such as
+// "XBTZAR,ETHZAR", upper casing them and dropping empty entries and duplicates
+func ParsePairs(s string) []string {
+	var pairs []string
+	for _, part := range strings.Split(s, ",") {
+		pair := strings.ToUpper(strings.TrimSpace(part))
+		if pair != "" && !slices.Contains(pairs, pair) {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
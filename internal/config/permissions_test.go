@@ -0,0 +1,129 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      []Permission
+		expectedError string
+	}{
+		{"empty uses defaults", "", DefaultPermissions, ""},
+		{"read only", "read", []Permission{PermissionRead}, ""},
+		{"all tiers", "read,trade,withdraw", []Permission{PermissionRead, PermissionTrade, PermissionWithdraw}, ""},
+		{"whitespace and case", " Read , TRADE ", []Permission{PermissionRead, PermissionTrade}, ""},
+		{"duplicates and empty entries", "read,,read", []Permission{PermissionRead}, ""},
+		{"unknown permission", "read,admin", nil, `unknown permission "admin"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParsePermissions(tc.input)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("ParsePermissions(%q) error = %v, want error containing %q", tc.input, err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(result, tc.expected) {
+				t.Errorf("ParsePermissions(%q) = %v, want %v", tc.input, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []Permission
+		permission  Permission
+		expected    bool
+	}{
+		{"nil uses defaults for read", nil, PermissionRead, true},
+		{"nil uses defaults for trade", nil, PermissionTrade, true},
+		{"nil uses defaults for withdraw", nil, PermissionWithdraw, false},
+		{"read only denies trade", []Permission{PermissionRead}, PermissionTrade, false},
+		{"withdraw granted", []Permission{PermissionRead, PermissionWithdraw}, PermissionWithdraw, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{Permissions: tc.permissions}
+			if got := cfg.Allows(tc.permission); got != tc.expected {
+				t.Errorf("Allows(%q) = %v, want %v", tc.permission, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseToolNames(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"get_ticker", []string{"get_ticker"}},
+		{" get_ticker , GET_ORDER_BOOK,,get_ticker ", []string{"get_ticker", "get_order_book"}},
+	}
+
+	for _, tc := range tests {
+		if got := ParseToolNames(tc.input); !slices.Equal(got, tc.expected) {
+			t.Errorf("ParseToolNames(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestToolFilterReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  []string
+		disabled []string
+		tool     string
+		expected string
+	}{
+		{"no lists", nil, nil, "create_order", ""},
+		{"enabled", []string{"get_ticker"}, nil, "get_ticker", ""},
+		{"not enabled", []string{"get_ticker"}, nil, "create_order", EnvLunoToolsEnabled},
+		{"disabled", nil, []string{"create_order"}, "create_order", EnvLunoToolsDisabled},
+		{"disabled wins", []string{"create_order"}, []string{"create_order"}, "create_order", EnvLunoToolsDisabled},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.SetSettings(&Settings{EnabledTools: tc.enabled, DisabledTools: tc.disabled})
+			got := cfg.ToolFilterReason(tc.tool)
+			if tc.expected == "" && got != "" {
+				t.Errorf("Expected %s to be allowed, got %q", tc.tool, got)
+			}
+			if !strings.Contains(got, tc.expected) {
+				t.Errorf("ToolFilterReason(%q) = %q, want it to mention %s", tc.tool, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParsePairs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"XBTZAR", []string{"XBTZAR"}},
+		{" xbtzar, ETHZAR ,,XBTZAR", []string{"XBTZAR", "ETHZAR"}},
+	}
+
+	for _, tc := range tests {
+		if got := ParsePairs(tc.input); !slices.Equal(got, tc.expected) {
+			t.Errorf("ParsePairs(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultProfile is the name of the profile using LUNO_API_KEY_ID and LUNO_API_SECRET
+const DefaultProfile = "default"
+
+// Extra profiles are configured with LUNO_PROFILE_<NAME>_API_KEY_ID and
+// LUNO_PROFILE_<NAME>_API_SECRET, e.g. LUNO_PROFILE_SAVINGS_API_KEY_ID
+const (
+	envProfilePrefix       = "LUNO_PROFILE_"
+	envProfileKeyIDSuffix  = "_API_KEY_ID"
+	envProfileSecretSuffix = "_API_SECRET"
+)
+
+// profileNamePattern restricts profile names to what is easy to type in a tool call
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+
+// Profile is a named set of Luno API credentials
+type Profile struct {
+	Name string `json:"name"`
+	// APIKeyID is masked
+	APIKeyID string `json:"api_key_id"`
+	Default  bool   `json:"default"`
+}
+
+// profileCredentials are the credentials of an extra profile
+type profileCredentials struct {
+	name, keyID, secret string
+}
+
+// loadProfileCredentials finds extra profiles in environment entries of the
+// form KEY=VALUE, sorted by name
+func loadProfileCredentials(environ []string) ([]profileCredentials, error) {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+
+	var profiles []profileCredentials
+	seen := make(map[string]bool)
+	for k, keyID := range env {
+		if !strings.HasPrefix(k, envProfilePrefix) || !strings.HasSuffix(k, envProfileKeyIDSuffix) {
+			continue
+		}
+		envName := strings.TrimSuffix(strings.TrimPrefix(k, envProfilePrefix), envProfileKeyIDSuffix)
+		name := strings.ToLower(envName)
+		if !profileNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid profile name in %s, use letters, digits and underscores", k)
+		}
+		if name == DefaultProfile {
+			return nil, fmt.Errorf("profile name %q is reserved for %s and %s", DefaultProfile, EnvLunoAPIKeyID, EnvLunoAPIKeySecret)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("profile %q is configured more than once", name)
+		}
+		seen[name] = true
+		secretKey := envProfilePrefix + envName + envProfileSecretSuffix
+		secret := env[secretKey]
+		if strings.TrimSpace(keyID) == "" || strings.TrimSpace(secret) == "" {
+			return nil, fmt.Errorf("profile %q needs both %s and %s", name, k, secretKey)
+		}
+		profiles = append(profiles, profileCredentials{name: name, keyID: keyID, secret: secret})
+	}
+	slices.SortFunc(profiles, func(a, b profileCredentials) int { return strings.Compare(a.name, b.name) })
+	return profiles, nil
+}
+
+// HasProfile reports whether a profile with the name is configured
+func (c *Config) HasProfile(name string) bool {
+	return slices.ContainsFunc(c.Profiles, func(p Profile) bool { return p.Name == name })
+}
+
+// ProfileNames returns the names of the configured profiles
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for _, p := range c.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadProfileCredentials(t *testing.T) {
+	tests := []struct {
+		name          string
+		environ       []string
+		expected      []string
+		expectedError string
+	}{
+		{"no profiles", []string{"LUNO_API_KEY_ID=abc", "PATH=/bin"}, nil, ""},
+		{
+			"sorted by name",
+			[]string{
+				"LUNO_PROFILE_TRADING_API_KEY_ID=t", "LUNO_PROFILE_TRADING_API_SECRET=ts",
+				"LUNO_PROFILE_CORP_2_API_KEY_ID=c", "LUNO_PROFILE_CORP_2_API_SECRET=cs",
+			},
+			[]string{"corp_2", "trading"},
+			"",
+		},
+		{"missing secret", []string{"LUNO_PROFILE_SAVINGS_API_KEY_ID=s"}, nil, "needs both"},
+		{"empty key id", []string{"LUNO_PROFILE_SAVINGS_API_KEY_ID=", "LUNO_PROFILE_SAVINGS_API_SECRET=s"}, nil, "needs both"},
+		{"reserved name", []string{"LUNO_PROFILE_DEFAULT_API_KEY_ID=d", "LUNO_PROFILE_DEFAULT_API_SECRET=ds"}, nil, "reserved"},
+		{"invalid name", []string{"LUNO_PROFILE_MY-ACCOUNT_API_KEY_ID=d", "LUNO_PROFILE_MY-ACCOUNT_API_SECRET=ds"}, nil, "invalid profile name"},
+		{
+			"duplicate name",
+			[]string{
+				"LUNO_PROFILE_SAVINGS_API_KEY_ID=a", "LUNO_PROFILE_SAVINGS_API_SECRET=as",
+				"LUNO_PROFILE_Savings_API_KEY_ID=b", "LUNO_PROFILE_Savings_API_SECRET=bs",
+			},
+			nil,
+			"more than once",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profiles, err := loadProfileCredentials(tc.environ)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var names []string
+			for _, p := range profiles {
+				names = append(names, p.name)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected profiles %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestLoadWithProfiles(t *testing.T) {
+	t.Setenv(EnvLunoAPIKeyID, "default_key_id")
+	t.Setenv(EnvLunoAPIKeySecret, "default_secret")
+	t.Setenv("LUNO_PROFILE_SAVINGS_API_KEY_ID", "savings_key_id")
+	t.Setenv("LUNO_PROFILE_SAVINGS_API_SECRET", "savings_secret")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(cfg.ProfileNames(), ","); got != "default,savings" {
+		t.Errorf("Expected profiles default,savings, got %s", got)
+	}
+	if !cfg.HasProfile("savings") || cfg.HasProfile("corporate") {
+		t.Error("HasProfile does not match the configured profiles")
+	}
+	if cfg.Profiles[1].APIKeyID != "savi**********" {
+		t.Errorf("Expected masked key id, got %s", cfg.Profiles[1].APIKeyID)
+	}
+}
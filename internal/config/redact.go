@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/redact"
+)
+
+// EnvLunoLogRedact is a comma-separated list of the details redacted from
+// logs and notifications besides secrets: "addresses" and "account_ids",
+// or "none". Both are redacted by default.
+const EnvLunoLogRedact = "LUNO_LOG_REDACT"
+
+// redactNone turns off the optional categories, secrets are still redacted
+const redactNone = "none"
+
+// LoadRedactor creates the redactor for logs and notifications, which always
+// replaces secrets and replaces the categories set in the environment
+func LoadRedactor(getenv func(string) string, secrets []string) (*redact.Redactor, error) {
+	categories, err := parseRedactCategories(getenv(EnvLunoLogRedact))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoLogRedact, err)
+	}
+	// Bearer tokens for the SSE transport are secrets too
+	secrets = append(slices.Clone(secrets), splitList(getenv(EnvMCPAuthTokens))...)
+	return redact.New(secrets, categories), nil
+}
+
+// parseRedactCategories parses a list of redaction categories, defaulting to all
+func parseRedactCategories(s string) ([]redact.Category, error) {
+	names := splitList(strings.ToLower(s))
+	if len(names) == 0 {
+		return redact.Categories, nil
+	}
+	if len(names) == 1 && names[0] == redactNone {
+		return nil, nil
+	}
+	categories := make([]redact.Category, 0, len(names))
+	for _, name := range names {
+		c := redact.Category(name)
+		if !slices.Contains(redact.Categories, c) {
+			return nil, fmt.Errorf("unknown category %q, use %q, %q or %q", name, redact.Addresses, redact.AccountIDs, redactNone)
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
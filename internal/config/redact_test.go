@@ -0,0 +1,54 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/redact"
+)
+
+func TestLoadRedactor(t *testing.T) {
+	tests := []struct {
+		name               string
+		env                map[string]string
+		expectedCategories []redact.Category
+		expectedError      string
+	}{
+		{"defaults", map[string]string{}, redact.Categories, ""},
+		{"addresses only", map[string]string{EnvLunoLogRedact: "Addresses"}, []redact.Category{redact.Addresses}, ""},
+		{"both", map[string]string{EnvLunoLogRedact: "account_ids, addresses"}, redact.Categories, ""},
+		{"none", map[string]string{EnvLunoLogRedact: "none"}, nil, ""},
+		{"unknown", map[string]string{EnvLunoLogRedact: "emails"}, nil, `unknown category "emails"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := LoadRedactor(func(k string) string { return tc.env[k] }, nil)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(r.Categories(), tc.expectedCategories) {
+				t.Errorf("Expected categories %v, got %v", tc.expectedCategories, r.Categories())
+			}
+		})
+	}
+}
+
+func TestLoadRedactorSecrets(t *testing.T) {
+	env := map[string]string{EnvMCPAuthTokens: "token-0123456789abcdef", EnvLunoLogRedact: "none"}
+	r, err := LoadRedactor(func(k string) string { return env[k] }, []string{"api-secret-value"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := r.String("secret api-secret-value and token token-0123456789abcdef")
+	if expected := "secret [REDACTED] and token [REDACTED]"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
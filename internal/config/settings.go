@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// EnvLunoLogLevel is the level of console logs and log notifications (debug,
+// info, warn or error). The -log-level flag takes precedence over it.
+const EnvLunoLogLevel = "LUNO_LOG_LEVEL"
+
+// Settings are the parts of the configuration that can change while the
+// server runs, without restarting it and dropping sessions. Config holds
+// them behind an atomic pointer, so a reload replaces them all at once and
+// readers never see a mix of old and new settings. Settings must not be
+// changed once they are set.
+type Settings struct {
+	// EnabledTools, when not empty, are the only tools the server exposes
+	EnabledTools []string
+
+	// DisabledTools are tools the server never exposes
+	DisabledTools []string
+
+	// AllowedPairs, when not empty, are the only pairs write tools may trade
+	AllowedPairs []string
+
+	// Limits caps the value of orders, nil means no limits
+	Limits *RiskLimits
+
+	// LogLevel is the level of console logs and log notifications
+	LogLevel slog.Level
+}
+
+// LoadSettings reads the settings that can be reloaded from the environment
+func LoadSettings(getenv func(string) string) (*Settings, error) {
+	limits, err := LoadRiskLimits(getenv)
+	if err != nil {
+		return nil, err
+	}
+	logLevel, err := parseLogLevel(getenv(EnvLunoLogLevel))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoLogLevel, err)
+	}
+	return &Settings{
+		EnabledTools:  ParseToolNames(getenv(EnvLunoToolsEnabled)),
+		DisabledTools: ParseToolNames(getenv(EnvLunoToolsDisabled)),
+		AllowedPairs:  ParsePairs(getenv(EnvLunoAllowedPairs)),
+		Limits:        limits,
+		LogLevel:      logLevel,
+	}, nil
+}
+
+// parseLogLevel parses a log level, defaulting to info
+func parseLogLevel(s string) (slog.Level, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+// Settings returns the current settings that can be reloaded
+func (c *Config) Settings() *Settings {
+	if s := c.settings.Load(); s != nil {
+		return s
+	}
+	return &Settings{}
+}
+
+// SetSettings replaces the settings that can be reloaded. Order value placed
+// today still counts towards the daily limits of the new settings, whether
+// it is carried over from the previous limits or restored from the state
+// store.
+func (c *Config) SetSettings(s *Settings) {
+	prev := c.settings.Load()
+	switch {
+	case s.Limits == nil:
+	case prev != nil && prev.Limits != nil:
+		if prev.Limits != s.Limits {
+			s.Limits.carryOver(prev.Limits)
+		}
+	case c.State != nil:
+		if err := s.Limits.Persist(c.State); err != nil {
+			slog.Warn("Could not restore daily order totals, starting from zero", slog.Any("error", err))
+		}
+	}
+	c.settings.Store(s)
+}
+
+// Reload reads the settings that can be reloaded again, along with the
+// session rate limit, from the environment and the config file the server
+// started with. The current settings are kept if any of them is invalid.
+// Other settings, such as credentials, only change on restart.
+func (c *Config) Reload() (*Settings, error) {
+	if err := reloadFile(); err != nil {
+		return nil, err
+	}
+
+	s, err := LoadSettings(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	sessionCalls, err := parseSessionCallsPerMinute(os.Getenv(EnvLunoSessionLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvLunoSessionLimit, err)
+	}
+
+	c.SetSettings(s)
+	if c.Sessions != nil {
+		c.Sessions.SetCallsPerMinute(sessionCalls)
+	}
+	slog.Info("Reloaded settings",
+		slog.Any("enabled_tools", s.EnabledTools),
+		slog.Any("disabled_tools", s.DisabledTools),
+		slog.Any("allowed_pairs", s.AllowedPairs),
+		slog.Bool("risk_limits", s.Limits != nil),
+		slog.Int("session_calls_per_minute", sessionCalls),
+		slog.String("log_level", s.LogLevel.String()))
+	return s, nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/session"
+)
+
+func TestLoadSettings(t *testing.T) {
+	env := map[string]string{
+		EnvLunoToolsEnabled:       "get_ticker, create_order",
+		EnvLunoToolsDisabled:      "create_order",
+		EnvLunoAllowedPairs:       "xbtzar",
+		EnvLunoMaxDailyTradeValue: "ZAR:1000",
+		EnvLunoLogLevel:           "debug",
+	}
+	s, err := LoadSettings(func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(s.EnabledTools, []string{"get_ticker", "create_order"}) {
+		t.Errorf("Unexpected enabled tools %v", s.EnabledTools)
+	}
+	if !slices.Equal(s.AllowedPairs, []string{"XBTZAR"}) {
+		t.Errorf("Unexpected allowed pairs %v", s.AllowedPairs)
+	}
+	if s.Limits == nil {
+		t.Error("Expected risk limits")
+	}
+	if s.LogLevel != slog.LevelDebug {
+		t.Errorf("Expected debug logs, got %s", s.LogLevel)
+	}
+
+	s, err = LoadSettings(func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.LogLevel != slog.LevelInfo {
+		t.Errorf("Expected info logs by default, got %s", s.LogLevel)
+	}
+
+	if _, err := LoadSettings(func(k string) string {
+		if k == EnvLunoLogLevel {
+			return "loud"
+		}
+		return ""
+	}); err == nil {
+		t.Error("Expected an error for an invalid log level")
+	}
+}
+
+func TestSetSettingsKeepsPlacedToday(t *testing.T) {
+	load := func(limit string) *RiskLimits {
+		limits, err := LoadRiskLimits(func(k string) string {
+			if k == EnvLunoMaxDailyTradeValue {
+				return limit
+			}
+			return ""
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return limits
+	}
+
+	cfg := &Config{}
+	cfg.SetSettings(&Settings{Limits: load("ZAR:1000")})
+	if _, err := cfg.Settings().Limits.Reserve("", "XBTZAR", "ZAR", decimal.NewFromInt64(600)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Raising the limit still counts what was placed under the old one
+	cfg.SetSettings(&Settings{Limits: load("ZAR:1500")})
+	if _, err := cfg.Settings().Limits.Reserve("", "XBTZAR", "ZAR", decimal.NewFromInt64(1000)); err == nil {
+		t.Error("Expected the reloaded daily limit to include today's orders")
+	}
+	if _, err := cfg.Settings().Limits.Reserve("", "XBTZAR", "ZAR", decimal.NewFromInt64(900)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "luno.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Register the variables with t.Setenv so they are restored, then unset
+	// the ones the file sets
+	for _, k := range []string{EnvLunoToolsDisabled, EnvLunoAllowedPairs, EnvLunoLogLevel, EnvLunoSessionLimit} {
+		t.Setenv(k, "")
+		os.Unsetenv(k)
+	}
+	t.Setenv(EnvLunoToolsEnabled, "get_ticker,create_order")
+	t.Cleanup(func() { loadedFile.path, loadedFile.applied = "", nil })
+
+	write("tools:\n  disabled: [create_order]\nallowed_trading_pairs: [XBTZAR]\n")
+	if err := ApplyFile(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	settings, err := LoadSettings(os.Getenv)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg := &Config{Sessions: session.NewManager(session.DefaultCallsPerMinute)}
+	cfg.SetSettings(settings)
+
+	write("tools:\n  enabled: [list_orders]\nallowed_trading_pairs: [ETHZAR]\nlog_level: warn\nsession_calls_per_minute: 5\n")
+	if _, err := cfg.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := cfg.Settings()
+	if len(s.DisabledTools) != 0 {
+		t.Errorf("Expected tools removed from the file to be enabled again, got %v", s.DisabledTools)
+	}
+	if !slices.Equal(s.EnabledTools, []string{"get_ticker", "create_order"}) {
+		t.Errorf("Expected the environment to override the file, got %v", s.EnabledTools)
+	}
+	if !slices.Equal(s.AllowedPairs, []string{"ETHZAR"}) {
+		t.Errorf("Expected the reloaded pairs, got %v", s.AllowedPairs)
+	}
+	if s.LogLevel != slog.LevelWarn {
+		t.Errorf("Expected the reloaded log level, got %s", s.LogLevel)
+	}
+	if got := cfg.Sessions.CallsPerMinute(); got != 5 {
+		t.Errorf("Expected the reloaded session limit, got %d", got)
+	}
+
+	// An invalid file keeps the current settings
+	write("log_level: loud\n")
+	if _, err := cfg.Reload(); err == nil {
+		t.Error("Expected an error for an invalid log level")
+	}
+	if cfg.Settings() != s {
+		t.Error("Expected the current settings to be kept")
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Environment variables for tool timeouts
+const (
+	// EnvLunoToolTimeout is how long any tool call may run (e.g. "15s"), "0" disables the timeout
+	EnvLunoToolTimeout = "LUNO_TOOL_TIMEOUT"
+	// EnvLunoToolTimeouts overrides the timeout of individual tools (e.g. "calculate_pnl:2m,get_ticker:5s")
+	EnvLunoToolTimeouts = "LUNO_TOOL_TIMEOUTS"
+)
+
+// DefaultToolTimeout is how long a tool call may run when LUNO_TOOL_TIMEOUT is not set
+const DefaultToolTimeout = 15 * time.Second
+
+// ToolTimeouts bounds how long tool calls may run, so that a hung Luno API
+// call can't stall a session
+type ToolTimeouts struct {
+	// Default applies to tools without their own timeout, zero disables it
+	Default time.Duration
+	// PerTool overrides Default by tool name
+	PerTool map[string]time.Duration
+}
+
+// LoadToolTimeouts reads tool timeouts from the environment
+func LoadToolTimeouts(getenv func(string) string) (ToolTimeouts, error) {
+	t := ToolTimeouts{Default: DefaultToolTimeout}
+	if s := strings.TrimSpace(getenv(EnvLunoToolTimeout)); s != "" {
+		d, err := parseTimeout(s)
+		if err != nil {
+			return ToolTimeouts{}, fmt.Errorf("invalid %s: %w", EnvLunoToolTimeout, err)
+		}
+		t.Default = d
+	}
+
+	perTool, err := parseToolTimeouts(getenv(EnvLunoToolTimeouts))
+	if err != nil {
+		return ToolTimeouts{}, fmt.Errorf("invalid %s: %w", EnvLunoToolTimeouts, err)
+	}
+	t.PerTool = perTool
+	return t, nil
+}
+
+// For returns the timeout of a tool, zero means no timeout
+func (t ToolTimeouts) For(tool string) time.Duration {
+	if d, ok := t.PerTool[tool]; ok {
+		return d
+	}
+	return t.Default
+}
+
+// info describes the timeouts for the config resource
+func (t ToolTimeouts) info() map[string]any {
+	perTool := make(map[string]string, len(t.PerTool))
+	for name, d := range t.PerTool {
+		perTool[name] = d.String()
+	}
+	return map[string]any{
+		"default":  t.Default.String(),
+		"per_tool": perTool,
+	}
+}
+
+// parseToolTimeouts parses entries such as "calculate_pnl:2m,get_ticker:5s"
+func parseToolTimeouts(s string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("entry %q must look like TOOL:DURATION", part)
+		}
+		d, err := parseTimeout(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in %q: %w", part, err)
+		}
+		timeouts[name] = d
+	}
+	return timeouts, nil
+}
+
+// parseTimeout parses a duration that may be zero but not negative
+func parseTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("timeout cannot be negative")
+	}
+	return d, nil
+}
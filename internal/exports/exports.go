@@ -0,0 +1,151 @@
+// Package exports keeps tool results that are too large to return in full,
+// such as long statements, so that clients can read them as MCP resources.
+//
+// Exports are held in memory for the session that created them and expire
+// after a time to live. They are not saved to the state store, and are
+// dropped when the session ends.
+package exports
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/security"
+)
+
+const (
+	// DefaultTTL is how long an export can be read
+	DefaultTTL = time.Hour
+
+	// MaxPerSession is the number of exports a session keeps at once. The
+	// oldest is dropped to make room for a new one.
+	MaxPerSession = 10
+
+	// TemplateURI is the URI template of the export resources
+	TemplateURI = "luno://exports/{id}"
+)
+
+// Export describes a stored result
+type Export struct {
+	ID        string    `json:"id"`
+	URI       string    `json:"uri"`
+	MIMEType  string    `json:"mime_type"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type entry struct {
+	Export
+	session string
+	data    string
+}
+
+// Store is a concurrency-safe set of exports keyed by ID
+type Store struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	exports map[string]entry
+}
+
+// NewStore creates a store. A zero ttl uses DefaultTTL.
+func NewStore(ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Store{ttl: ttl, now: time.Now, exports: make(map[string]entry)}
+}
+
+// URI returns the resource URI of an export
+func URI(id string) string {
+	return strings.Replace(TemplateURI, "{id}", id, 1)
+}
+
+// ParseURI returns the export ID of a resource URI, or "" if it isn't one
+func ParseURI(uri string) string {
+	id, ok := strings.CutPrefix(uri, "luno://exports/")
+	if !ok || strings.Contains(id, "/") {
+		return ""
+	}
+	return id
+}
+
+// Add stores data for a session and returns where it can be read
+func (s *Store) Add(session, mimeType, data string) (Export, error) {
+	id, err := security.NewToken()
+	if err != nil {
+		return Export{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+
+	var own []entry
+	for _, e := range s.exports {
+		if e.session == session {
+			own = append(own, e)
+		}
+	}
+	if len(own) >= MaxPerSession {
+		slices.SortFunc(own, func(a, b entry) int { return a.CreatedAt.Compare(b.CreatedAt) })
+		for _, e := range own[:len(own)-MaxPerSession+1] {
+			delete(s.exports, e.ID)
+		}
+	}
+
+	now := s.now().UTC()
+	e := entry{
+		Export: Export{
+			ID:        id,
+			URI:       URI(id),
+			MIMEType:  mimeType,
+			Size:      len(data),
+			CreatedAt: now,
+			ExpiresAt: now.Add(s.ttl),
+		},
+		session: session,
+		data:    data,
+	}
+	s.exports[id] = e
+	return e.Export, nil
+}
+
+// Get returns one of a session's exports and its data
+func (s *Store) Get(session, id string) (Export, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+
+	e, ok := s.exports[id]
+	if !ok || e.session != session {
+		return Export{}, "", fmt.Errorf("export %s was not found, it may have expired", id)
+	}
+	return e.Export, e.data, nil
+}
+
+// DeleteSession removes every export of a session, for when it disconnects
+func (s *Store) DeleteSession(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, e := range s.exports {
+		if e.session == session {
+			delete(s.exports, id)
+		}
+	}
+}
+
+// purgeLocked removes the exports that have expired
+func (s *Store) purgeLocked() {
+	now := s.now()
+	for id, e := range s.exports {
+		if !now.Before(e.ExpiresAt) {
+			delete(s.exports, id)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package exports
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	s := NewStore(time.Hour)
+	s.now = func() time.Time { return now }
+
+	e, err := s.Add("s1", "text/csv", "a,b\n1,2\n")
+	require.NoError(t, err)
+	assert.Equal(t, "luno://exports/"+e.ID, e.URI)
+	assert.Equal(t, e.ID, ParseURI(e.URI))
+	assert.Equal(t, 8, e.Size)
+	assert.Equal(t, now.Add(time.Hour), e.ExpiresAt)
+
+	got, data, err := s.Get("s1", e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, e, got)
+	assert.Equal(t, "a,b\n1,2\n", data)
+
+	// Other sessions can't read it
+	_, _, err = s.Get("s2", e.ID)
+	assert.ErrorContains(t, err, "was not found")
+
+	// Exports expire
+	now = now.Add(time.Hour)
+	_, _, err = s.Get("s1", e.ID)
+	assert.ErrorContains(t, err, "may have expired")
+}
+
+func TestStoreKeepsNewestPerSession(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	s := NewStore(0)
+	s.now = func() time.Time { return now }
+
+	var ids []string
+	for i := range MaxPerSession + 1 {
+		now = now.Add(time.Second)
+		e, err := s.Add("s1", "application/json", strconv.Itoa(i))
+		require.NoError(t, err)
+		ids = append(ids, e.ID)
+	}
+	other, err := s.Add("s2", "application/json", "other")
+	require.NoError(t, err)
+
+	_, _, err = s.Get("s1", ids[0])
+	assert.Error(t, err, "the oldest export is dropped")
+	_, data, err := s.Get("s1", ids[MaxPerSession])
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(MaxPerSession), data)
+
+	s.DeleteSession("s1")
+	_, _, err = s.Get("s1", ids[MaxPerSession])
+	assert.Error(t, err)
+	_, _, err = s.Get("s2", other.ID)
+	assert.NoError(t, err)
+}
+
+func TestParseURI(t *testing.T) {
+	assert.Equal(t, "abc", ParseURI("luno://exports/abc"))
+	assert.Empty(t, ParseURI("luno://exports/abc/def"))
+	assert.Empty(t, ParseURI("luno://results/abc"))
+}
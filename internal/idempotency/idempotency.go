@@ -0,0 +1,116 @@
+// Package idempotency remembers recently submitted orders so that a retried
+// submission is rejected instead of placing the order twice.
+//
+// Submissions are keyed by their client order ID, or by a fingerprint of the
+// session and order when the caller didn't give one. Keys are kept in memory
+// for a time window and forgotten once it has passed.
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultWindow is how long a submitted order is remembered
+const DefaultWindow = 5 * time.Minute
+
+// ErrDuplicate is returned for a submission already made within the window
+var ErrDuplicate = errors.New("duplicate order submission")
+
+// Submission is a remembered order submission
+type Submission struct {
+	ClientOrderID string
+	OrderID       string
+	SubmittedAt   time.Time
+}
+
+// DuplicateError describes the earlier submission a duplicate matches
+type DuplicateError struct {
+	Submission
+	Age time.Duration
+}
+
+func (e *DuplicateError) Error() string {
+	if e.OrderID == "" {
+		return fmt.Sprintf("%v: client order ID %s was submitted %s ago and may still be in flight",
+			ErrDuplicate, e.ClientOrderID, e.Age.Round(time.Second))
+	}
+	return fmt.Sprintf("%v: client order ID %s was submitted %s ago and placed as order %s",
+		ErrDuplicate, e.ClientOrderID, e.Age.Round(time.Second), e.OrderID)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// Store remembers order submissions for a window
+type Store struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex
+	submissions map[string]Submission
+}
+
+// NewStore creates a store. A zero window uses DefaultWindow.
+func NewStore(window time.Duration) *Store {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Store{
+		window:      window,
+		now:         time.Now,
+		submissions: make(map[string]Submission),
+	}
+}
+
+// Window returns how long submissions are remembered
+func (s *Store) Window() time.Duration {
+	return s.window
+}
+
+// Begin records a submission under key before it is sent. If key was
+// submitted within the window a *DuplicateError is returned instead.
+func (s *Store) Begin(key, clientOrderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+	if prev, ok := s.submissions[key]; ok {
+		return &DuplicateError{Submission: prev, Age: now.Sub(prev.SubmittedAt)}
+	}
+	s.submissions[key] = Submission{ClientOrderID: clientOrderID, SubmittedAt: now}
+	return nil
+}
+
+// Complete records the order a submission placed
+func (s *Store) Complete(key, orderID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sub, ok := s.submissions[key]; ok {
+		sub.OrderID = orderID
+		s.submissions[key] = sub
+	}
+}
+
+// Forget removes a submission that definitely wasn't placed, so it can be
+// retried straight away
+func (s *Store) Forget(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.submissions, key)
+}
+
+// expire removes submissions older than the window. The caller must hold mu.
+func (s *Store) expire(now time.Time) {
+	for key, sub := range s.submissions {
+		if now.Sub(sub.SubmittedAt) >= s.window {
+			delete(s.submissions, key)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package idempotency
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := NewStore(time.Minute)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Begin("a", "mcp-a"))
+
+	// A retry while the first submission is in flight is rejected
+	err := s.Begin("a", "mcp-a")
+	require.ErrorIs(t, err, ErrDuplicate)
+	assert.Contains(t, err.Error(), "client order ID mcp-a was submitted 0s ago and may still be in flight")
+
+	// Once placed, the duplicate names the order
+	s.Complete("a", "BXMC2SEAS4KF5S2")
+	now = now.Add(30 * time.Second)
+	err = s.Begin("a", "mcp-a")
+	var dup *DuplicateError
+	require.True(t, errors.As(err, &dup))
+	assert.Equal(t, "BXMC2SEAS4KF5S2", dup.OrderID)
+	assert.Equal(t, 30*time.Second, dup.Age)
+
+	// Other keys are unaffected
+	require.NoError(t, s.Begin("b", "mcp-b"))
+
+	// A submission that wasn't placed can be retried straight away
+	s.Forget("b")
+	require.NoError(t, s.Begin("b", "mcp-b"))
+
+	// Submissions are forgotten after the window
+	now = now.Add(30 * time.Second)
+	require.NoError(t, s.Begin("a", "mcp-a"))
+}
+
+func TestNewStoreDefaultWindow(t *testing.T) {
+	assert.Equal(t, DefaultWindow, NewStore(0).Window())
+	assert.Equal(t, time.Second, NewStore(time.Second).Window())
+}
@@ -24,6 +24,8 @@ package logging
 import (
 	"context"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -86,14 +88,28 @@ type NotificationSender interface {
 	SendNotificationToAllClients(method string, params map[string]any)
 }
 
-// MCPNotificationHandler is a handler that sends logs as MCP notifications
+// MCPNotificationHandler is a handler that sends logs as MCP notifications.
+// The data of each notification is an object with the log message under
+// "message" and the record's attributes as fields, nested by group.
 type MCPNotificationHandler struct {
 	s     NotificationSender
-	level slog.Level
+	level slog.Leveler
+	// attrs are the attributes added with WithAttrs, each under the groups
+	// that were open when it was added
+	attrs []groupedAttr
+	// groups are the groups opened with WithGroup, outermost first
+	groups []string
 }
 
-// NewMCPNotificationHandler creates a new handler that forwards logs to MCP clients
-func NewMCPNotificationHandler(s NotificationSender, level slog.Level) *MCPNotificationHandler {
+// groupedAttr is an attribute and the groups it belongs to
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+// NewMCPNotificationHandler creates a new handler that forwards logs to MCP clients.
+// Pass a *slog.LevelVar as the level to change it while the server runs.
+func NewMCPNotificationHandler(s NotificationSender, level slog.Leveler) *MCPNotificationHandler {
 	return &MCPNotificationHandler{
 		s:     s,
 		level: level,
@@ -102,7 +118,7 @@ func NewMCPNotificationHandler(s NotificationSender, level slog.Level) *MCPNotif
 
 // Enabled implements slog.Handler
 func (h *MCPNotificationHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.level
+	return level >= h.level.Level()
 }
 
 // Handle implements slog.Handler
@@ -115,17 +131,25 @@ func (h *MCPNotificationHandler) Handle(ctx context.Context, record slog.Record)
 	// Convert slog level to MCP logging level
 	level := slogLevelToMCPLevel(record.Level)
 
-	// Extract the message
-	message := record.Message
+	// Collect the attributes of the handler and then the record
+	data := make(map[string]any)
+	for _, ga := range h.attrs {
+		addAttr(data, ga.groups, ga.attr)
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(data, h.groups, a)
+		return true
+	})
+	data["message"] = record.Message
 
 	// Create a logging message notification using the MCP helper function
-	notification := mcp.NewLoggingMessageNotification(level, "luno-mcp", message)
+	notification := mcp.NewLoggingMessageNotification(level, "luno-mcp", data)
 
 	// Send the notification to all clients - need to create a map to pass the params correctly
 	h.s.SendNotificationToAllClients(notification.Method, map[string]any{
 		"level":  string(level),
 		"logger": "luno-mcp",
-		"data":   message,
+		"data":   data,
 	})
 
 	return nil
@@ -133,14 +157,83 @@ func (h *MCPNotificationHandler) Handle(ctx context.Context, record slog.Record)
 
 // WithAttrs implements slog.Handler
 func (h *MCPNotificationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	// For simplicity, we ignore attrs in this implementation
-	return h
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = append(h2.attrs, groupedAttr{groups: h.groups, attr: a})
+	}
+	return &h2
 }
 
 // WithGroup implements slog.Handler
 func (h *MCPNotificationHandler) WithGroup(name string) slog.Handler {
-	// For simplicity, we ignore group in this implementation
-	return h
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+// addAttr sets a in data under groups, creating the group objects it needs.
+// Empty attributes are dropped and groups without a key are inlined, as
+// slog.Handler requires.
+func addAttr(data map[string]any, groups []string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if v.Kind() == slog.KindGroup {
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, ga := range attrs {
+			addAttr(data, groups, ga)
+		}
+		return
+	}
+	for _, g := range groups {
+		group, ok := data[g].(map[string]any)
+		if !ok {
+			group = make(map[string]any)
+			data[g] = group
+		}
+		data = group
+	}
+	data[a.Key] = attrValue(v)
+}
+
+// attrValue converts a resolved value to one that encodes as JSON
+func attrValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	default:
+		// Errors would encode as empty objects
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	}
 }
 
 // slogLevelToMCPLevel converts a slog.Level to an MCP LoggingLevel
@@ -182,8 +275,50 @@ func LogErrorHook(ctx context.Context, id any, method mcp.MCPMethod, message any
 		slog.Any("error", err))
 }
 
-// MCPHooks returns hooks for the MCP server that handle logging
-func MCPHooks() *server.Hooks {
+// mcpLevelToSlogLevel converts an MCP LoggingLevel to the lowest slog.Level
+// logged at it. The levels above error have no slog equivalent and only let
+// through records above error.
+func mcpLevelToSlogLevel(level mcp.LoggingLevel) (slog.Level, bool) {
+	switch level {
+	case mcp.LoggingLevelDebug:
+		return slog.LevelDebug, true
+	case mcp.LoggingLevelInfo:
+		return slog.LevelInfo, true
+	case mcp.LoggingLevelNotice:
+		return slog.LevelInfo + 2, true
+	case mcp.LoggingLevelWarning:
+		return slog.LevelWarn, true
+	case mcp.LoggingLevelError:
+		return slog.LevelError, true
+	case mcp.LoggingLevelCritical, mcp.LoggingLevelAlert, mcp.LoggingLevelEmergency:
+		return slog.LevelError + 4, true
+	default:
+		return 0, false
+	}
+}
+
+// SetLevelHook returns the function registered for the AfterSetLevel hook.
+// It applies the level a client asks for with logging/setLevel to level,
+// which is shared by every log handler, so it changes console logs as well
+// as notifications to all clients.
+func SetLevelHook(level *slog.LevelVar) server.OnAfterSetLevelFunc {
+	return func(ctx context.Context, id any, message *mcp.SetLevelRequest, result *mcp.EmptyResult) {
+		l, ok := mcpLevelToSlogLevel(message.Params.Level)
+		if !ok {
+			return
+		}
+		previous := level.Level()
+		level.Set(l)
+		// Logged at the higher of the two levels so that it is seen either way
+		slog.Log(ctx, max(previous, l), "Log level changed",
+			slog.String("from", previous.String()),
+			slog.String("to", l.String()))
+	}
+}
+
+// MCPHooks returns hooks for the MCP server that handle logging. When level
+// isn't nil, clients can change it with logging/setLevel.
+func MCPHooks(level *slog.LevelVar) *server.Hooks {
 	hooks := &server.Hooks{}
 
 	hooks.AddBeforeAny(LogRequestHook)
@@ -192,5 +327,9 @@ func MCPHooks() *server.Hooks {
 
 	hooks.AddOnError(LogErrorHook)
 
+	if level != nil {
+		hooks.AddAfterSetLevel(SetLevelHook(level))
+	}
+
 	return hooks
 }
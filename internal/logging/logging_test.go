@@ -12,6 +12,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 const (
@@ -62,6 +63,60 @@ func TestMCPNotificationHandlerEnabled(t *testing.T) {
 	assert.False(t, handler.Enabled(context.Background(), slog.LevelDebug), "Debug level should be disabled")
 }
 
+func TestMCPLevelToSlogLevel(t *testing.T) {
+	testCases := []struct {
+		mcpLevel  mcp.LoggingLevel
+		slogLevel slog.Level
+		ok        bool
+	}{
+		{mcp.LoggingLevelDebug, slog.LevelDebug, true},
+		{mcp.LoggingLevelInfo, slog.LevelInfo, true},
+		{mcp.LoggingLevelNotice, slog.LevelInfo + 2, true},
+		{mcp.LoggingLevelWarning, slog.LevelWarn, true},
+		{mcp.LoggingLevelError, slog.LevelError, true},
+		{mcp.LoggingLevelEmergency, slog.LevelError + 4, true},
+		{"verbose", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.mcpLevel), func(t *testing.T) {
+			level, ok := mcpLevelToSlogLevel(tc.mcpLevel)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.slogLevel, level)
+		})
+	}
+}
+
+func TestSetLevelHook(t *testing.T) {
+	var consoleBuffer bytes.Buffer
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+
+	mockNotifier := NewMockNotificationSender(t)
+	consoleHandler := slog.NewTextHandler(&consoleBuffer, &slog.HandlerOptions{Level: level})
+	mcpNotificationHandler := NewMCPNotificationHandler(mockNotifier, level)
+	originalLogger := slog.Default()
+	slog.SetDefault(slog.New(NewMultiHandler(consoleHandler, mcpNotificationHandler)))
+	defer slog.SetDefault(originalLogger)
+
+	ctx := context.Background()
+	assert.False(t, mcpNotificationHandler.Enabled(ctx, slog.LevelDebug))
+
+	// The change is logged at the higher of the old and new levels
+	mockNotifier.On("SendNotificationToAllClients", mock.Anything, mock.Anything).Once()
+	setLevel := SetLevelHook(level)
+	setLevel(ctx, 1, &mcp.SetLevelRequest{Params: mcp.SetLevelParams{Level: mcp.LoggingLevelDebug}}, &mcp.EmptyResult{})
+
+	assert.Equal(t, slog.LevelDebug, level.Level())
+	assert.True(t, mcpNotificationHandler.Enabled(ctx, slog.LevelDebug), "Notifications should follow the new level")
+	assert.True(t, consoleHandler.Enabled(ctx, slog.LevelDebug), "Console logs should follow the new level")
+	assert.Contains(t, consoleBuffer.String(), "Log level changed")
+
+	// Unknown levels leave the level unchanged
+	setLevel(ctx, 2, &mcp.SetLevelRequest{Params: mcp.SetLevelParams{Level: "verbose"}}, &mcp.EmptyResult{})
+	assert.Equal(t, slog.LevelDebug, level.Level())
+}
+
 func TestMCPNotificationHandlerHandleNotificationFormat(t *testing.T) {
 	mockS := new(MockNotificationSender)
 	handler := NewMCPNotificationHandler(mockS, slog.LevelDebug) // Enable all levels for this test
@@ -72,7 +127,7 @@ func TestMCPNotificationHandlerHandleNotificationFormat(t *testing.T) {
 	expectedParams := map[string]any{
 		"level":  string(mcpLevel),
 		"logger": loggerName,
-		"data":   testMessageDefault,
+		"data":   map[string]any{"message": testMessageDefault},
 	}
 	expectedMethod := mcp.NewLoggingMessageNotification(mcpLevel, loggerName, testMessageDefault).Method
 
@@ -86,14 +141,55 @@ func TestMCPNotificationHandlerHandleNotificationFormat(t *testing.T) {
 }
 
 func TestMCPNotificationHandlerWithAttrsAndGroup(t *testing.T) {
-	handler := NewMCPNotificationHandler(&MockNotificationSender{}, slog.LevelInfo)
+	mockS := NewMockNotificationSender(t)
+	handler := NewMCPNotificationHandler(mockS, slog.LevelInfo)
+	method := mcp.NewLoggingMessageNotification(mcp.LoggingLevelInfo, loggerName, nil).Method
 
-	attrs := []slog.Attr{slog.String("key", "value")}
-	handlerWithAttrs := handler.WithAttrs(attrs)
-	assert.Equal(t, handler, handlerWithAttrs, "WithAttrs should return the same handler instance for simplicity")
+	var logged slog.Handler = handler.
+		WithAttrs([]slog.Attr{slog.String("component", "test")}).
+		WithGroup("call").
+		WithAttrs([]slog.Attr{slog.String("tool", "get_ticker")}).
+		WithGroup("unused")
 
-	handlerWithGroup := handler.WithGroup("testGroup")
-	assert.Equal(t, handler, handlerWithGroup, "WithGroup should return the same handler instance for simplicity")
+	expectedParams := map[string]any{
+		"level":  string(mcp.LoggingLevelWarning),
+		"logger": loggerName,
+		"data": map[string]any{
+			"message":   testMessageDefault,
+			"component": "test",
+			"call": map[string]any{
+				"tool": "get_ticker",
+				"unused": map[string]any{
+					"attempt":  int64(2),
+					"delay":    "1.5s",
+					"error":    "timed out",
+					"response": map[string]any{"status": int64(504)},
+					"inlined":  true,
+				},
+			},
+		},
+	}
+	mockS.On("SendNotificationToAllClients", method, expectedParams).Once()
+
+	record := slog.NewRecord(time.Now(), slog.LevelWarn, testMessageDefault, 0)
+	record.AddAttrs(
+		slog.Int("attempt", 2),
+		slog.Duration("delay", 1500*time.Millisecond),
+		slog.Any("error", errors.New("timed out")),
+		slog.Group("response", slog.Int("status", 504)),
+		slog.Group("", slog.Bool("inlined", true)),
+		slog.Group("empty"),
+		slog.Attr{},
+	)
+	assert.NoError(t, logged.Handle(context.Background(), record))
+
+	// The original handler is unchanged
+	mockS.On("SendNotificationToAllClients", method, map[string]any{
+		"level":  string(mcp.LoggingLevelInfo),
+		"logger": loggerName,
+		"data":   map[string]any{"message": testIntegrationMsg},
+	}).Once()
+	assert.NoError(t, handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, testIntegrationMsg, 0)))
 }
 
 func TestMultiHandlerEnabled(t *testing.T) {
@@ -259,7 +355,11 @@ func TestIntegrationHooksWithNotificationHandler(t *testing.T) {
 		expectedNotificationParams := map[string]any{
 			"level":  string(mcp.LoggingLevelDebug),
 			"logger": loggerName,
-			"data":   logMsgMCPRequest,
+			"data": map[string]any{
+				"message":    logMsgMCPRequest,
+				logKeyMethod: string(reqMethod),
+				logKeyID:     reqID,
+			},
 		}
 		notification := mcp.NewLoggingMessageNotification(mcp.LoggingLevelDebug, loggerName, logMsgMCPRequest)
 		mockNotifier.On("SendNotificationToAllClients", notification.Method, expectedNotificationParams).Once()
@@ -285,7 +385,12 @@ func TestIntegrationHooksWithNotificationHandler(t *testing.T) {
 		expectedErrorNotificationParams := map[string]any{
 			"level":  string(mcp.LoggingLevelError),
 			"logger": loggerName,
-			"data":   logMsgMCPError,
+			"data": map[string]any{
+				"message":    logMsgMCPError,
+				logKeyID:     errID,
+				logKeyMethod: string(errMethod),
+				logKeyError:  "integration error",
+			},
 		}
 		errorNotification := mcp.NewLoggingMessageNotification(mcp.LoggingLevelError, loggerName, logMsgMCPError)
 		mockNotifier.On("SendNotificationToAllClients", errorNotification.Method, expectedErrorNotificationParams).Once()
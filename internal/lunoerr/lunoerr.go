@@ -0,0 +1,215 @@
+// Package lunoerr sorts errors from the Luno API and from the tools into a
+// small set of stable, machine-readable codes, so that agents can branch on
+// the kind of failure rather than parse messages.
+//
+// Errors created with New or Wrap carry their code. Other errors are
+// classified by their Luno API error code when they have one, and otherwise
+// by well-known fragments of their message, which also lets a code be found
+// for a tool result that only has text.
+package lunoerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/luno/luno-go"
+)
+
+// Code identifies a kind of failure. Codes are part of the tool interface
+// and must not change once published.
+type Code string
+
+const (
+	// InsufficientBalance means an account doesn't hold enough funds
+	InsufficientBalance Code = "insufficient_balance"
+	// InvalidPair means a currency pair isn't a Luno market
+	InvalidPair Code = "invalid_pair"
+	// MarketUnavailable means a market exists but isn't open for trading
+	MarketUnavailable Code = "market_unavailable"
+	// AuthFailed means the API credentials were rejected
+	AuthFailed Code = "auth_failed"
+	// PermissionDenied means the credentials or server settings don't allow the call
+	PermissionDenied Code = "permission_denied"
+	// RateLimited means too many calls were made, and the call can be retried later
+	RateLimited Code = "rate_limited"
+	// InvalidArgument means an argument was missing or malformed
+	InvalidArgument Code = "invalid_argument"
+	// NotFound means an order, account or other object doesn't exist
+	NotFound Code = "not_found"
+	// DuplicateOrder means an order submission repeats a recent one
+	DuplicateOrder Code = "duplicate_order"
+	// Timeout means the call took too long
+	Timeout Code = "timeout"
+	// Unavailable means the Luno API couldn't be reached or failed
+	Unavailable Code = "unavailable"
+	// Internal means the server failed unexpectedly
+	Internal Code = "internal"
+	// Unknown is any other failure
+	Unknown Code = "unknown"
+)
+
+// Error is an error with a code
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error with code and a formatted message
+func New(code Code, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap gives err a code, keeping its message. A nil err stays nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// apiCodes maps Luno API error codes to codes
+var apiCodes = map[string]Code{
+	"ErrInsufficientBalance": InsufficientBalance,
+	"ErrInsufficientFunds":   InsufficientBalance,
+	"ErrInvalidMarketPair":   InvalidPair,
+	"ErrInvalidPair":         InvalidPair,
+	"ErrMarketNotFound":      InvalidPair,
+	"ErrMarketUnavailable":   MarketUnavailable,
+	"ErrMarketNotActive":     MarketUnavailable,
+	"ErrUnauthorised":        AuthFailed,
+	"ErrUnauthorized":        AuthFailed,
+	"ErrApiKeyRevoked":       AuthFailed,
+	"ErrAPIKeyRevoked":       AuthFailed,
+	"ErrInvalidApiKey":       AuthFailed,
+	"ErrInsufficientPerms":   PermissionDenied,
+	"ErrPermissionDenied":    PermissionDenied,
+	"ErrTooManyRequests":     RateLimited,
+	"ErrRateLimitExceeded":   RateLimited,
+	"ErrOrderNotFound":       NotFound,
+	"ErrAccountNotFound":     NotFound,
+	"ErrNotFound":            NotFound,
+	"ErrDuplicateClientId":   DuplicateOrder,
+	"ErrAmountTooSmall":      InvalidArgument,
+	"ErrAmountTooBig":        InvalidArgument,
+	"ErrInvalidArguments":    InvalidArgument,
+	"ErrInvalidParameter":    InvalidArgument,
+}
+
+// apiCode finds the code of a Luno API error code, falling back to the
+// words in it for codes that aren't listed
+func apiCode(errCode string) Code {
+	if code, ok := apiCodes[errCode]; ok {
+		return code
+	}
+	switch {
+	case strings.Contains(errCode, "Insufficient"):
+		return InsufficientBalance
+	case strings.Contains(errCode, "NotFound"):
+		return NotFound
+	case strings.Contains(errCode, "Invalid"), strings.Contains(errCode, "TooSmall"), strings.Contains(errCode, "TooBig"):
+		return InvalidArgument
+	default:
+		return Unknown
+	}
+}
+
+// Classify returns the code of err, or Unknown
+func Classify(err error) Code {
+	if err == nil {
+		return Unknown
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	var apiErr luno.Error
+	if errors.As(err, &apiErr) {
+		if code := apiCode(apiErr.Code); code != Unknown {
+			return code
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+	if code := FromMessage(err.Error()); code != Unknown {
+		return code
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Unavailable
+	}
+	return Unknown
+}
+
+// apiCodePattern finds a Luno API error code in a message, as formatted by
+// luno.Error
+var apiCodePattern = regexp.MustCompile(`\((Err[A-Za-z0-9]+)\)`)
+
+// messageCodes are lower case message fragments and the codes they mean,
+// most specific first
+var messageCodes = []struct {
+	fragment string
+	code     Code
+}{
+	{"failed unexpectedly", Internal},
+	{"duplicate order submission", DuplicateOrder},
+	{"timed out", Timeout},
+	{"did not finish within", Timeout},
+	{"deadline exceeded", Timeout},
+	{"too many requests", RateLimited},
+	{"too many tool calls", RateLimited},
+	{"rate limit", RateLimited},
+	{"error decoding response (401", AuthFailed},
+	{"error decoding response (403", PermissionDenied},
+	{"error decoding response (5", Unavailable},
+	{"unauthori", AuthFailed},
+	{"authenticat", AuthFailed},
+	{"api key", AuthFailed},
+	{"apikey", AuthFailed},
+	{"credentials", AuthFailed},
+	{"forbidden", PermissionDenied},
+	{"insufficient", InsufficientBalance},
+	{"is not a valid luno market", InvalidPair},
+	{"is not open for trading", MarketUnavailable},
+	{"is not available:", PermissionDenied},
+	{"is not allowed", PermissionDenied},
+	{"required argument", InvalidArgument},
+	{"from request", InvalidArgument},
+	{"not found", NotFound},
+	{"connection refused", Unavailable},
+	{"no such host", Unavailable},
+	{"invalid", InvalidArgument},
+	{"must be", InvalidArgument},
+	{"below the minimum", InvalidArgument},
+	{"decimal places", InvalidArgument},
+	{"above the maximum", InvalidArgument},
+}
+
+// FromMessage returns the code of an error message, such as the text of a
+// tool error result, or Unknown
+func FromMessage(msg string) Code {
+	if m := apiCodePattern.FindStringSubmatch(msg); m != nil {
+		if code := apiCode(m[1]); code != Unknown {
+			return code
+		}
+	}
+	lower := strings.ToLower(msg)
+	for _, mc := range messageCodes {
+		if strings.Contains(lower, mc.fragment) {
+			return mc.code
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,68 @@
+package lunoerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{"nil", nil, Unknown},
+		{"coded", New(InvalidPair, "%s is not a valid Luno market", "XBTXYZ"), InvalidPair},
+		{"wrapped coded", fmt.Errorf("placing order: %w", Wrap(InsufficientBalance, errors.New("not enough ZAR"))), InsufficientBalance},
+		{"api error", luno.Error{Code: "ErrInsufficientBalance", Message: "Insufficient balance"}, InsufficientBalance},
+		{"wrapped api error", fmt.Errorf("get order: %w", luno.Error{Code: "ErrOrderNotFound", Message: "Order not found"}), NotFound},
+		{"unlisted api error", luno.Error{Code: "ErrInvalidVolume", Message: "Volume is not valid"}, InvalidArgument},
+		{"revoked key", luno.Error{Code: "ErrApiKeyRevoked", Message: "API key revoked"}, AuthFailed},
+		{"deadline", fmt.Errorf("get ticker: %w", context.DeadlineExceeded), Timeout},
+		{"rate limited", errors.New("luno: too many requests"), RateLimited},
+		{"server error", errors.New("error decoding response (502 Bad Gateway)"), Unavailable},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("refused")}, Unavailable},
+		{"other", errors.New("something odd"), Unknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Classify(tc.err))
+		})
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected Code
+	}{
+		{"Failed to create order: Insufficient balance (ErrInsufficientBalance)", InsufficientBalance},
+		{"Failed to get balances: Unauthorized (ErrUnauthorised)", AuthFailed},
+		{"insufficient ZAR balance: the order needs 1010 ZAR but only 5 ZAR is available", InsufficientBalance},
+		{"XBTZARR is not a valid Luno market, did you mean one of: XBTZAR", InvalidPair},
+		{"market ETHZAR is not open for trading, current status is POST_ONLY", MarketUnavailable},
+		{"trading ETHZAR is not allowed, this server only trades XBTZAR", PermissionDenied},
+		{"create_order is not available: requires the \"trade\" permission", PermissionDenied},
+		{"getting pair from request: required argument \"pair\" not found", InvalidArgument},
+		{"volume 0.0000001 has more than 6 decimal places, which XBTZAR does not allow", InvalidArgument},
+		{"duplicate order submission: client order ID a was submitted 3s ago and placed as order B", DuplicateOrder},
+		{"Too many tool calls from this session, the limit is 120 a minute. Try again shortly.", RateLimited},
+		{`{"error": "timeout", "message": "get_ticker did not finish within 15s"}`, Timeout},
+		{"create_order failed unexpectedly, please report this issue", Internal},
+		{"Failed to get order: order not found", NotFound},
+		{"Something else went wrong", Unknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.msg, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FromMessage(tc.msg))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package market holds what the tools, resources and prompts share about
+// Luno's assets and markets: the supported assets and their aliases, the
+// normalization of pairs and assets given in arguments and URIs, and the
+// validation of pairs against the list of markets.
+package market
+
+import "strings"
+
+// Asset describes a currency Luno supports
+type Asset struct {
+	// Code is the asset's code on Luno, e.g. XBT
+	Code string `json:"code"`
+	// Symbol is the ticker the asset is commonly known by, e.g. BTC
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	// Decimals is the number of decimal places Luno keeps balances in
+	Decimals int  `json:"decimals"`
+	Fiat     bool `json:"fiat"`
+	// Aliases are the other names, in upper case, that tool arguments may
+	// use for the asset
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Assets are the assets Luno supports. Their aliases are what NormalizePair
+// and NormalizeAsset accept in place of the Luno code.
+var Assets = []Asset{
+	{Code: "XBT", Symbol: "BTC", Name: "Bitcoin", Decimals: 8, Aliases: []string{"BTC", "BITCOIN"}},
+	{Code: "ETH", Symbol: "ETH", Name: "Ethereum", Decimals: 8, Aliases: []string{"ETHER", "ETHEREUM"}},
+	{Code: "XRP", Symbol: "XRP", Name: "XRP", Decimals: 6, Aliases: []string{"RIPPLE"}},
+	{Code: "LTC", Symbol: "LTC", Name: "Litecoin", Decimals: 8, Aliases: []string{"LITECOIN"}},
+	{Code: "BCH", Symbol: "BCH", Name: "Bitcoin Cash", Decimals: 8},
+	{Code: "USDT", Symbol: "USDT", Name: "Tether", Decimals: 6, Aliases: []string{"TETHER"}},
+	{Code: "USDC", Symbol: "USDC", Name: "USD Coin", Decimals: 6},
+	{Code: "SOL", Symbol: "SOL", Name: "Solana", Decimals: 8, Aliases: []string{"SOLANA"}},
+	{Code: "ADA", Symbol: "ADA", Name: "Cardano", Decimals: 6, Aliases: []string{"CARDANO"}},
+	{Code: "DOGE", Symbol: "DOGE", Name: "Dogecoin", Decimals: 8, Aliases: []string{"DOGECOIN", "XDG"}},
+	{Code: "TRX", Symbol: "TRX", Name: "TRON", Decimals: 6},
+	{Code: "LINK", Symbol: "LINK", Name: "Chainlink", Decimals: 8},
+	{Code: "DOT", Symbol: "DOT", Name: "Polkadot", Decimals: 8},
+	{Code: "ZAR", Symbol: "ZAR", Name: "South African Rand", Decimals: 2, Fiat: true, Aliases: []string{"RAND", "RANDS"}},
+	{Code: "NGN", Symbol: "NGN", Name: "Nigerian Naira", Decimals: 2, Fiat: true, Aliases: []string{"NAIRA"}},
+	{Code: "EUR", Symbol: "EUR", Name: "Euro", Decimals: 2, Fiat: true, Aliases: []string{"EURO", "EUROS"}},
+	{Code: "GBP", Symbol: "GBP", Name: "British Pound", Decimals: 2, Fiat: true},
+	{Code: "MYR", Symbol: "MYR", Name: "Malaysian Ringgit", Decimals: 2, Fiat: true},
+	{Code: "IDR", Symbol: "IDR", Name: "Indonesian Rupiah", Decimals: 2, Fiat: true},
+	{Code: "UGX", Symbol: "UGX", Name: "Ugandan Shilling", Decimals: 2, Fiat: true},
+}
+
+// assetAliases maps common names and tickers of assets, in upper case, to
+// their Luno codes, e.g. BTC to XBT. It is built from Assets.
+var assetAliases = aliasCodes(Assets)
+
+// NormalizeAsset returns the Luno code of an asset or its alias, e.g. XBT
+// for BTC
+func NormalizeAsset(asset string) string {
+	asset = strings.ToUpper(strings.TrimSpace(asset))
+	if code, ok := assetAliases[asset]; ok {
+		return code
+	}
+	return asset
+}
+
+// FindAsset looks up an asset by its code, symbol, name or alias
+func FindAsset(name string) (Asset, bool) {
+	code := NormalizeAsset(name)
+	for _, a := range Assets {
+		if a.Code == code || a.Symbol == code || strings.EqualFold(a.Name, strings.TrimSpace(name)) {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// aliasCodes maps every alias of the assets to its Luno code
+func aliasCodes(assets []Asset) map[string]string {
+	aliases := make(map[string]string)
+	for _, a := range assets {
+		for _, alias := range a.Aliases {
+			aliases[alias] = a.Code
+		}
+	}
+	return aliases
+}
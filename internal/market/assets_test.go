@@ -0,0 +1,95 @@
+package market
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssets(t *testing.T) {
+	codes := make(map[string]bool)
+	for _, a := range Assets {
+		assert.False(t, codes[a.Code], "%s is listed twice", a.Code)
+		codes[a.Code] = true
+	}
+	aliases := make(map[string]string)
+	for _, a := range Assets {
+		for _, alias := range a.Aliases {
+			assert.Equal(t, strings.ToUpper(alias), alias, "alias %s of %s must be upper case", alias, a.Code)
+			assert.False(t, codes[alias], "alias %s of %s is the code of another asset", alias, a.Code)
+			other, ok := aliases[alias]
+			assert.False(t, ok, "alias %s of %s is also an alias of %s", alias, a.Code, other)
+			aliases[alias] = a.Code
+		}
+		if a.Symbol != a.Code {
+			assert.Contains(t, a.Aliases, a.Symbol, "the symbol of %s must be one of its aliases", a.Code)
+		}
+	}
+	assert.Equal(t, aliases, assetAliases)
+}
+
+func TestNormalizeAsset(t *testing.T) {
+	tests := []struct {
+		asset    string
+		expected string
+	}{
+		{asset: "XBT", expected: "XBT"},
+		{asset: "btc", expected: "XBT"},
+		{asset: " Bitcoin ", expected: "XBT"},
+		{asset: "ether", expected: "ETH"},
+		{asset: "ETHEREUM", expected: "ETH"},
+		{asset: "xdg", expected: "DOGE"},
+		{asset: "rands", expected: "ZAR"},
+		{asset: "naira", expected: "NGN"},
+		{asset: "usd", expected: "USD"},
+		{asset: "", expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.asset, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeAsset(tc.asset))
+		})
+	}
+
+	// Every code and alias normalizes to the asset's code
+	for _, a := range Assets {
+		assert.Equal(t, a.Code, NormalizeAsset(strings.ToLower(a.Code)))
+		for _, alias := range a.Aliases {
+			assert.Equal(t, a.Code, NormalizeAsset(alias), "alias %s", alias)
+		}
+	}
+}
+
+func TestFindAsset(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		ok       bool
+	}{
+		{name: "XBT", expected: "XBT", ok: true},
+		{name: "btc", expected: "XBT", ok: true},
+		{name: " Ethereum ", expected: "ETH", ok: true},
+		{name: "usd coin", expected: "USDC", ok: true},
+		{name: "rands", expected: "ZAR", ok: true},
+		{name: "NOPE"},
+		{name: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			asset, ok := FindAsset(tc.name)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, asset.Code)
+		})
+	}
+
+	// Every asset can be found by its code, symbol and name
+	for _, a := range Assets {
+		for _, name := range []string{a.Code, a.Symbol, a.Name} {
+			asset, ok := FindAsset(name)
+			assert.True(t, ok, "%s of %s", name, a.Code)
+			assert.Equal(t, a.Code, asset.Code, "%s of %s", name, a.Code)
+		}
+	}
+}
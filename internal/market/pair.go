@@ -0,0 +1,60 @@
+package market
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// NormalizePair converts common currency pair formats to Luno's, e.g.
+// btc/zar to XBTZAR. It also accepts a single asset, so it normalizes
+// currency arguments too.
+func NormalizePair(pair string) string {
+	originalPair := pair
+
+	// Separated assets are normalized one at a time, so an alias can't
+	// match across the two halves of the pair
+	parts := strings.FieldsFunc(strings.ToUpper(pair), isPairSeparator)
+	if len(parts) > 1 {
+		for i, part := range parts {
+			parts[i] = NormalizeAsset(part)
+		}
+		pair = strings.Join(parts, "")
+	} else {
+		pair = replaceAliases(strings.Join(parts, ""))
+	}
+
+	slog.Debug("Currency pair normalization", "original", originalPair, "normalized", pair)
+	return pair
+}
+
+// replaceAliases replaces an alias at the start and an alias at the end of
+// an unseparated pair, preferring the longest so ETHEREUM isn't read as ETHER
+func replaceAliases(pair string) string {
+	if code, ok := assetAliases[pair]; ok {
+		return code
+	}
+	base, counter := "", pair
+	if alias := longestAlias(pair, strings.HasPrefix); alias != "" {
+		base, counter = assetAliases[alias], strings.TrimPrefix(pair, alias)
+	}
+	if alias := longestAlias(counter, strings.HasSuffix); alias != "" {
+		counter = strings.TrimSuffix(counter, alias) + assetAliases[alias]
+	}
+	return base + counter
+}
+
+// longestAlias returns the longest alias that matches s, or "" if none does
+func longestAlias(s string, matches func(s, alias string) bool) string {
+	var longest string
+	for alias := range assetAliases {
+		if len(alias) > len(longest) && matches(s, alias) {
+			longest = alias
+		}
+	}
+	return longest
+}
+
+// isPairSeparator reports whether r separates the assets of a pair
+func isPairSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '/' || r == ' '
+}
@@ -0,0 +1,65 @@
+package market
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePair(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Simple BTC to XBT", "BTC", "XBT"},
+		{"BTC in pair", "BTCGBP", "XBTGBP"},
+		{"BTC with hyphen separator", "BTC-GBP", "XBTGBP"},
+		{"BTC with slash separator", "BTC/GBP", "XBTGBP"},
+		{"BTC with underscore separator", "BTC_GBP", "XBTGBP"},
+		{"Lowercase input", "btcgbp", "XBTGBP"},
+		{"Mixed case input", "xbTGbP", "XBTGBP"},
+		{"Non-BTC pair", "ETHZAR", "ETHZAR"},
+		{"Non-BTC pair with separator", "ETH-ZAR", "ETHZAR"},
+		{"BITCOIN text conversion", "BITCOIN", "XBT"},
+		{"BITCOIN in pair", "BITCOINUSD", "XBTUSD"},
+		{"Multiple separators", "BTC-_/GBP", "XBTGBP"},
+		{"Combo of mappings", "BITCOIN/GBP", "XBTGBP"},
+		{"Asset name", "ethereum", "ETH"},
+		{"Asset names in pair", "ethereum/rands", "ETHZAR"},
+		{"Asset names without separator", "RIPPLENAIRA", "XRPNGN"},
+		{"Longest alias wins", "ETHEREUMZAR", "ETHZAR"},
+		{"Alias counter", "XBTTETHER", "XBTUSDT"},
+		{"Alias after separator", "XBT-RAND", "XBTZAR"},
+		{"Space separator", "doge zar", "DOGEZAR"},
+		{"Surrounding separators", " /XBTZAR- ", "XBTZAR"},
+		{"Aliases not split across halves", "ET-HER", "ETHER"},
+		{"Unknown assets", "foo-bar", "FOOBAR"},
+		{"Empty", "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizePair(tc.input))
+		})
+	}
+}
+
+func TestNormalizePairCodesAndAliases(t *testing.T) {
+	// Every pair of assets normalizes the same whether it is given by code,
+	// symbol or alias, with or without a separator
+	for _, base := range Assets {
+		for _, counter := range Assets {
+			if base.Code == counter.Code {
+				continue
+			}
+			expected := base.Code + counter.Code
+			for _, b := range append([]string{base.Code}, base.Aliases...) {
+				for _, c := range append([]string{counter.Code}, counter.Aliases...) {
+					assert.Equal(t, expected, NormalizePair(b+"/"+c), "%s/%s", b, c)
+				}
+			}
+			assert.Equal(t, expected, NormalizePair(base.Code+counter.Code))
+		}
+	}
+}
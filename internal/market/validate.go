@@ -0,0 +1,100 @@
+package market
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+)
+
+// maxPairSuggestions is the number of alternative markets suggested for an unknown pair
+const maxPairSuggestions = 5
+
+// maxSuggestionDistance is the most single character edits, counting a swap
+// of neighbouring characters as one, between a pair and a suggested market
+const maxSuggestionDistance = 2
+
+// ValidatePair checks that the pair, in Luno's format as NormalizePair
+// returns it, is one of the markets and open for trading, returning its
+// metadata. For unknown pairs the error suggests similar markets.
+func ValidatePair(markets []luno.MarketInfo, pair string) (*luno.MarketInfo, error) {
+	for i := range markets {
+		if markets[i].MarketId != pair {
+			continue
+		}
+		market := &markets[i]
+		if market.TradingStatus != luno.TradingStatusActive {
+			return market, lunoerr.New(lunoerr.MarketUnavailable, "market %s is not open for trading, current status is %s", pair, market.TradingStatus)
+		}
+		return market, nil
+	}
+
+	if suggestions := similarPairs(markets, pair); len(suggestions) > 0 {
+		return nil, lunoerr.New(lunoerr.InvalidPair, "%s is not a valid Luno market, did you mean one of: %s", pair, strings.Join(suggestions, ", "))
+	}
+	return nil, lunoerr.New(lunoerr.InvalidPair, "%s is not a valid Luno market", pair)
+}
+
+// suggestion is a market with how far it is from the requested pair
+type suggestion struct {
+	pair     string
+	distance int
+}
+
+// similarPairs returns markets the pair may have been meant as, closest first:
+// markets within a couple of typos of the pair or of its normalized spelling,
+// then markets that share its base or counter currency
+func similarPairs(markets []luno.MarketInfo, pair string) []string {
+	candidates := []string{pair}
+	if normalized := NormalizePair(pair); normalized != pair {
+		candidates = append(candidates, normalized)
+	}
+
+	var found []suggestion
+	for _, m := range markets {
+		distance := len(pair) + len(m.MarketId)
+		shares := false
+		for _, c := range candidates {
+			distance = min(distance, editDistance(c, m.MarketId))
+			shares = shares || strings.HasPrefix(c, m.BaseCurrency) || strings.HasSuffix(c, m.CounterCurrency)
+		}
+		if distance <= maxSuggestionDistance || shares {
+			found = append(found, suggestion{pair: m.MarketId, distance: distance})
+		}
+	}
+	slices.SortStableFunc(found, func(a, b suggestion) int { return a.distance - b.distance })
+
+	suggestions := make([]string, 0, min(len(found), maxPairSuggestions))
+	for _, s := range found[:min(len(found), maxPairSuggestions)] {
+		suggestions = append(suggestions, s.pair)
+	}
+	return suggestions
+}
+
+// editDistance is the number of insertions, deletions, substitutions and
+// swaps of neighbouring characters needed to turn a into b
+func editDistance(a, b string) int {
+	// rows[i][j] is the distance between a[:i] and b[:j]
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
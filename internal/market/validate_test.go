@@ -0,0 +1,135 @@
+package market
+
+import (
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMarkets are an open, a suspended and another open market
+func testMarkets() []luno.MarketInfo {
+	return []luno.MarketInfo{
+		{MarketId: "XBTZAR", BaseCurrency: "XBT", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive},
+		{MarketId: "ETHZAR", BaseCurrency: "ETH", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusSuspended},
+		{MarketId: "XBTEUR", BaseCurrency: "XBT", CounterCurrency: "EUR", TradingStatus: luno.TradingStatusActive},
+	}
+}
+
+func TestValidatePair(t *testing.T) {
+	tests := []struct {
+		name          string
+		pair          string
+		expectedID    string
+		expectedCode  lunoerr.Code
+		errorContains string
+	}{
+		{name: "active market", pair: "XBTZAR", expectedID: "XBTZAR"},
+		{name: "another active market", pair: "XBTEUR", expectedID: "XBTEUR"},
+		{
+			name:          "suspended market",
+			pair:          "ETHZAR",
+			expectedID:    "ETHZAR",
+			expectedCode:  lunoerr.MarketUnavailable,
+			errorContains: "not open for trading, current status is SUSPENDED",
+		},
+		{
+			name:          "typo",
+			pair:          "XTBZAR",
+			expectedCode:  lunoerr.InvalidPair,
+			errorContains: "did you mean one of: XBTZAR, ETHZAR",
+		},
+		{
+			name:          "alias",
+			pair:          "BTCEUR",
+			expectedCode:  lunoerr.InvalidPair,
+			errorContains: "did you mean one of: XBTEUR",
+		},
+		{
+			name:          "nothing similar",
+			pair:          "DOGEUSD",
+			expectedCode:  lunoerr.InvalidPair,
+			errorContains: "DOGEUSD is not a valid Luno market",
+		},
+		{
+			name:          "not normalized",
+			pair:          "xbtzar",
+			expectedCode:  lunoerr.InvalidPair,
+			errorContains: "did you mean one of: XBTZAR",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			market, err := ValidatePair(testMarkets(), tc.pair)
+			if tc.expectedID != "" {
+				require.NotNil(t, market)
+				assert.Equal(t, tc.expectedID, market.MarketId)
+			} else {
+				assert.Nil(t, market)
+			}
+			if tc.errorContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errorContains)
+			assert.Equal(t, tc.expectedCode, lunoerr.Classify(err))
+		})
+	}
+
+	_, err := ValidatePair(nil, "XBTZAR")
+	assert.EqualError(t, err, "XBTZAR is not a valid Luno market")
+}
+
+func TestSimilarPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		pair     string
+		expected []string
+	}{
+		{name: "swapped letters", pair: "XTBZAR", expected: []string{"XBTZAR", "ETHZAR"}},
+		{name: "mistyped counter", pair: "ETHSAR", expected: []string{"ETHZAR"}},
+		{name: "unknown counter", pair: "XBTUSD", expected: []string{"XBTZAR", "XBTEUR"}},
+		{name: "asset names", pair: "ETHEREUMRAND", expected: []string{"ETHZAR", "XBTZAR"}},
+		{name: "alias with a typo", pair: "BITCOINEUT", expected: []string{"XBTEUR", "XBTZAR"}},
+		{name: "nothing similar", pair: "DOGEUSD", expected: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, similarPairs(testMarkets(), tc.pair))
+		})
+	}
+}
+
+func TestSimilarPairsLimit(t *testing.T) {
+	var markets []luno.MarketInfo
+	for _, counter := range []string{"ZAR", "EUR", "GBP", "NGN", "MYR", "IDR", "UGX"} {
+		markets = append(markets, luno.MarketInfo{MarketId: "XBT" + counter, BaseCurrency: "XBT", CounterCurrency: counter})
+	}
+	assert.Len(t, similarPairs(markets, "XBTUSD"), maxPairSuggestions)
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "XBTZAR", b: "XBTZAR", expected: 0},
+		{a: "XTBZAR", b: "XBTZAR", expected: 1},
+		{a: "XBTZA", b: "XBTZAR", expected: 1},
+		{a: "ETHSAR", b: "ETHZAR", expected: 1},
+		{a: "XBTUSD", b: "XBTZAR", expected: 3},
+		{a: "", b: "ETH", expected: 3},
+		{a: "ETH", b: "", expected: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.expected, editDistance(tc.a, tc.b))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package notes stores user-declared notes about accounts and trading pairs.
+//
+// Notes let users tell the assistant about their intent (e.g. "this account is
+// for tax savings") so that read tools can surface that intent alongside the
+// data they return.
+package notes
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Kind identifies what a note is attached to
+type Kind string
+
+const (
+	KindAccount Kind = "account"
+	KindPair    Kind = "pair"
+)
+
+// ParseKind converts a user supplied string into a Kind
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(strings.TrimSpace(s))); k {
+	case KindAccount, KindPair:
+		return k, nil
+	default:
+		return "", fmt.Errorf("unknown note target type %q, must be %q or %q", s, KindAccount, KindPair)
+	}
+}
+
+type key struct {
+	kind   Kind
+	target string
+}
+
+// Store is a concurrency-safe in-memory store of notes
+type Store struct {
+	mu    sync.RWMutex
+	notes map[key]string
+}
+
+// NewStore creates an empty note store
+func NewStore() *Store {
+	return &Store{notes: make(map[key]string)}
+}
+
+// Set attaches a note to a target, replacing any existing note.
+// An empty note removes the existing one.
+func (s *Store) Set(kind Kind, target, note string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{kind: kind, target: target}
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(s.notes, k)
+		return
+	}
+	s.notes[k] = note
+}
+
+// Get returns the note attached to a target, or an empty string if there is none.
+// It is safe to call on a nil Store.
+func (s *Store) Get(kind Kind, target string) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.notes[key{kind: kind, target: target}]
+}
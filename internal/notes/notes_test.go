@@ -0,0 +1,100 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKind(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Kind
+		expectError bool
+	}{
+		{name: "account", input: "account", expected: KindAccount},
+		{name: "pair", input: "pair", expected: KindPair},
+		{name: "mixed case with spaces", input: " Pair ", expected: KindPair},
+		{name: "unknown kind", input: "wallet", expectError: true},
+		{name: "empty", input: "", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kind, err := ParseKind(tc.input)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, kind)
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(s *Store)
+		kind     Kind
+		target   string
+		expected string
+	}{
+		{
+			name:     "missing note",
+			setup:    func(s *Store) {},
+			kind:     KindAccount,
+			target:   "123",
+			expected: "",
+		},
+		{
+			name:     "set and get",
+			setup:    func(s *Store) { s.Set(KindAccount, "123", "tax savings") },
+			kind:     KindAccount,
+			target:   "123",
+			expected: "tax savings",
+		},
+		{
+			name:     "kinds are separate",
+			setup:    func(s *Store) { s.Set(KindPair, "123", "not an account") },
+			kind:     KindAccount,
+			target:   "123",
+			expected: "",
+		},
+		{
+			name: "overwrite existing note",
+			setup: func(s *Store) {
+				s.Set(KindPair, "XBTZAR", "first")
+				s.Set(KindPair, "XBTZAR", "second")
+			},
+			kind:     KindPair,
+			target:   "XBTZAR",
+			expected: "second",
+		},
+		{
+			name: "empty note clears",
+			setup: func(s *Store) {
+				s.Set(KindPair, "XBTZAR", "first")
+				s.Set(KindPair, "XBTZAR", "  ")
+			},
+			kind:     KindPair,
+			target:   "XBTZAR",
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewStore()
+			tc.setup(s)
+			assert.Equal(t, tc.expected, s.Get(tc.kind, tc.target))
+		})
+	}
+}
+
+func TestNilStoreGet(t *testing.T) {
+	var s *Store
+	assert.Equal(t, "", s.Get(KindAccount, "123"))
+}
@@ -0,0 +1,314 @@
+// Package orderwatch follows orders until they complete and reports fills
+// and cancellations.
+//
+// Watches are kept per MCP session, in memory and also in a state store when
+// Persist is called. Each poll looks up every watched order once, even when
+// several sessions watch it, and a watch is removed when its order completes.
+package orderwatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/luno/luno-mcp/sdk"
+)
+
+const (
+	// DefaultPollInterval is how often watched orders are looked up
+	DefaultPollInterval = 10 * time.Second
+
+	// DefaultMaxPerSession is the number of orders a session can watch at once
+	DefaultMaxPerSession = 20
+
+	// stateSection is the state store section watches are saved in
+	stateSection = "order_watch"
+)
+
+// EventKind is what happened to a watched order
+type EventKind string
+
+const (
+	// EventPartiallyFilled is sent when more of an open order has been filled
+	EventPartiallyFilled EventKind = "partially_filled"
+	// EventFilled is sent when an order has been filled completely
+	EventFilled EventKind = "filled"
+	// EventCancelled is sent when an order completes without being filled completely
+	EventCancelled EventKind = "cancelled"
+)
+
+// Watch is an order being followed
+type Watch struct {
+	OrderID   string          `json:"order_id"`
+	Pair      string          `json:"pair,omitempty"`
+	Status    luno.Status     `json:"status,omitempty"`
+	Filled    decimal.Decimal `json:"filled"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Event is a change to a watched order
+type Event struct {
+	Kind        EventKind       `json:"event"`
+	OrderID     string          `json:"order_id"`
+	Pair        string          `json:"pair"`
+	Side        luno.Side       `json:"side,omitempty"`
+	Status      luno.Status     `json:"status"`
+	Filled      decimal.Decimal `json:"filled"`
+	LimitVolume decimal.Decimal `json:"limit_volume"`
+	LimitPrice  decimal.Decimal `json:"limit_price"`
+	Session     string          `json:"-"`
+	Time        time.Time       `json:"time"`
+}
+
+// Message describes the event for the client
+func (e Event) Message() string {
+	switch e.Kind {
+	case EventFilled:
+		return fmt.Sprintf("Order %s on %s was filled: %s at %s", e.OrderID, e.Pair, e.Filled, e.LimitPrice)
+	case EventCancelled:
+		return fmt.Sprintf("Order %s on %s was cancelled with %s of %s filled", e.OrderID, e.Pair, e.Filled, e.LimitVolume)
+	default:
+		return fmt.Sprintf("Order %s on %s was partially filled: %s of %s so far", e.OrderID, e.Pair, e.Filled, e.LimitVolume)
+	}
+}
+
+// Watcher is a concurrency-safe set of watched orders keyed by session
+type Watcher struct {
+	maxPerSession int
+	now           func() time.Time
+
+	mu      sync.Mutex
+	watches map[string][]Watch
+	store   *state.Store
+}
+
+// NewWatcher creates an empty watcher that allows maxPerSession watches per session
+func NewWatcher(maxPerSession int) *Watcher {
+	return &Watcher{
+		maxPerSession: maxPerSession,
+		now:           time.Now,
+		watches:       make(map[string][]Watch),
+	}
+}
+
+// Persist restores the watches saved in store of the sessions that keep
+// reports true, and saves every later change to store
+func (w *Watcher) Persist(store *state.Store, keep func(session string) bool) error {
+	var saved map[string][]Watch
+	if _, err := store.Load(stateSection, &saved); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for session, watches := range saved {
+		if keep(session) {
+			w.watches[session] = append(watches, w.watches[session]...)
+		}
+	}
+	w.store = store
+	w.saveLocked()
+	return nil
+}
+
+// Add starts watching an order for a session. Watching an order twice has no effect.
+func (w *Watcher) Add(session, orderID string) (Watch, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watches := w.watches[session]
+	if i := slices.IndexFunc(watches, func(wa Watch) bool { return wa.OrderID == orderID }); i >= 0 {
+		return watches[i], nil
+	}
+	if len(watches) >= w.maxPerSession {
+		return Watch{}, fmt.Errorf("a session can watch at most %d orders, unwatch one first", w.maxPerSession)
+	}
+	wa := Watch{OrderID: orderID, Filled: decimal.Zero(), CreatedAt: w.now().UTC()}
+	w.watches[session] = append(watches, wa)
+	w.saveLocked()
+	return wa, nil
+}
+
+// Remove stops watching an order for a session and reports whether it was watched
+func (w *Watcher) Remove(session, orderID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	watches := w.watches[session]
+	i := slices.IndexFunc(watches, func(wa Watch) bool { return wa.OrderID == orderID })
+	if i < 0 {
+		return false
+	}
+	w.setLocked(session, slices.Delete(watches, i, i+1))
+	w.saveLocked()
+	return true
+}
+
+// List returns a session's watched orders in the order they were added
+func (w *Watcher) List(session string) []Watch {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.watches[session])
+}
+
+// DeleteSession removes every watch of a session, for when it disconnects
+func (w *Watcher) DeleteSession(session string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.watches[session]; ok {
+		delete(w.watches, session)
+		w.saveLocked()
+	}
+}
+
+// orderIDs returns every watched order ID once
+func (w *Watcher) orderIDs() []string {
+	return slices.Sorted(maps.Keys(w.orderSessions()))
+}
+
+// orderSessions returns the first session, in order of ID, that watches each
+// watched order
+func (w *Watcher) orderSessions() map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	orders := make(map[string]string)
+	for session, watches := range w.watches {
+		for _, wa := range watches {
+			if first, ok := orders[wa.OrderID]; !ok || session < first {
+				orders[wa.OrderID] = session
+			}
+		}
+	}
+	return orders
+}
+
+// Poll looks up every watched order and returns the events since the last poll.
+// Each order is looked up with the client clientFor returns for a session
+// watching it, so that orders placed with a session's own Luno credentials
+// are found. Orders that can't be looked up are skipped until the next poll.
+func (w *Watcher) Poll(ctx context.Context, clientFor func(session string) sdk.LunoClient) []Event {
+	var events []Event
+	orders := w.orderSessions()
+	for _, id := range slices.Sorted(maps.Keys(orders)) {
+		order, err := clientFor(orders[id]).GetOrderV3(ctx, &luno.GetOrderV3Request{Id: id})
+		if err != nil {
+			if ctx.Err() != nil {
+				return events
+			}
+			slog.Warn("Failed to look up watched order", slog.String("order_id", id), slog.Any("error", err))
+			continue
+		}
+		events = append(events, w.update(order)...)
+	}
+	return events
+}
+
+// update applies an order's latest state to its watches
+func (w *Watcher) update(order *luno.GetOrderV3Response) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now().UTC()
+	var events []Event
+	changed := false
+	for session, watches := range w.watches {
+		remaining := watches[:0]
+		for _, wa := range watches {
+			if wa.OrderID != order.OrderId {
+				remaining = append(remaining, wa)
+				continue
+			}
+			kind, done := classify(wa, order)
+			if kind != "" {
+				events = append(events, Event{
+					Kind:        kind,
+					OrderID:     order.OrderId,
+					Pair:        order.Pair,
+					Side:        order.Side,
+					Status:      order.Status,
+					Filled:      order.Base,
+					LimitVolume: order.LimitVolume,
+					LimitPrice:  order.LimitPrice,
+					Session:     session,
+					Time:        now,
+				})
+			}
+			if done {
+				changed = true
+				continue
+			}
+			if wa.Pair != order.Pair || wa.Status != order.Status || wa.Filled.Cmp(order.Base) != 0 {
+				wa.Pair, wa.Status, wa.Filled = order.Pair, order.Status, order.Base
+				changed = true
+			}
+			remaining = append(remaining, wa)
+		}
+		w.setLocked(session, remaining)
+	}
+	if changed {
+		w.saveLocked()
+	}
+	slices.SortFunc(events, func(a, b Event) int { return strings.Compare(a.Session, b.Session) })
+	return events
+}
+
+// classify returns the event for an order's latest state, if any, and
+// whether the order has completed
+func classify(wa Watch, order *luno.GetOrderV3Response) (EventKind, bool) {
+	if order.Status == luno.StatusComplete {
+		limit := order.LimitVolume
+		if order.Base.Sign() > 0 && (limit.Sign() == 0 || order.Base.Cmp(limit) >= 0) {
+			return EventFilled, true
+		}
+		return EventCancelled, true
+	}
+	if order.Base.Cmp(wa.Filled) > 0 {
+		return EventPartiallyFilled, false
+	}
+	return "", false
+}
+
+func (w *Watcher) setLocked(session string, watches []Watch) {
+	if len(watches) == 0 {
+		delete(w.watches, session)
+		return
+	}
+	w.watches[session] = watches
+}
+
+// saveLocked saves the watches to the state store, if there is one. A failed
+// save is logged rather than returned, as the watches still work in memory.
+func (w *Watcher) saveLocked() {
+	if w.store == nil {
+		return
+	}
+	if err := w.store.Save(stateSection, w.watches); err != nil {
+		slog.Warn("Failed to save watched orders", slog.Any("error", err))
+	}
+}
+
+// Run polls watched orders every interval until ctx is cancelled, calling
+// notify for each event. No requests are made while nothing is watched.
+func (w *Watcher) Run(ctx context.Context, clientFor func(session string) sdk.LunoClient, interval time.Duration, notify func(Event)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, e := range w.Poll(ctx, clientFor) {
+			notify(e)
+		}
+	}
+}
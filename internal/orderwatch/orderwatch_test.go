@@ -0,0 +1,240 @@
+package orderwatch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func dec(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	require.NoError(t, err)
+	return d
+}
+
+// only returns a client lookup that gives client for every session
+func only(client sdk.LunoClient) func(string) sdk.LunoClient {
+	return func(string) sdk.LunoClient { return client }
+}
+
+func testOrder(t *testing.T, id string, status luno.Status, filled string) *luno.GetOrderV3Response {
+	return &luno.GetOrderV3Response{
+		OrderId:     id,
+		Pair:        "XBTZAR",
+		Side:        luno.SideBuy,
+		Status:      status,
+		Base:        dec(t, filled),
+		LimitVolume: dec(t, "0.1"),
+		LimitPrice:  dec(t, "1000000"),
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	w := NewWatcher(2)
+
+	_, err := w.Add("s1", "A")
+	require.NoError(t, err)
+	_, err = w.Add("s1", "A")
+	require.NoError(t, err, "watching an order twice has no effect")
+	_, err = w.Add("s1", "B")
+	require.NoError(t, err)
+	_, err = w.Add("s1", "C")
+	assert.ErrorContains(t, err, "at most 2 orders")
+	_, err = w.Add("s2", "A")
+	require.NoError(t, err)
+
+	assert.Len(t, w.List("s1"), 2)
+	assert.Equal(t, []string{"A", "B"}, w.orderIDs())
+
+	assert.False(t, w.Remove("s2", "B"))
+	assert.True(t, w.Remove("s1", "B"))
+	assert.Equal(t, []string{"A"}, w.orderIDs())
+
+	w.DeleteSession("s1")
+	assert.Empty(t, w.List("s1"))
+	assert.Len(t, w.List("s2"), 1)
+}
+
+func TestPoll(t *testing.T) {
+	tests := []struct {
+		name      string
+		updates   []*luno.GetOrderV3Response
+		expected  []EventKind
+		completes bool
+	}{
+		{
+			name:     "no change",
+			updates:  []*luno.GetOrderV3Response{testOrder(t, "A", luno.StatusPending, "0")},
+			expected: nil,
+		},
+		{
+			name: "partial fills are reported as they grow",
+			updates: []*luno.GetOrderV3Response{
+				testOrder(t, "A", luno.StatusPending, "0.02"),
+				testOrder(t, "A", luno.StatusPending, "0.02"),
+				testOrder(t, "A", luno.StatusPending, "0.05"),
+			},
+			expected: []EventKind{EventPartiallyFilled, EventPartiallyFilled},
+		},
+		{
+			name: "filled",
+			updates: []*luno.GetOrderV3Response{
+				testOrder(t, "A", luno.StatusPending, "0.05"),
+				testOrder(t, "A", luno.StatusComplete, "0.1"),
+			},
+			expected:  []EventKind{EventPartiallyFilled, EventFilled},
+			completes: true,
+		},
+		{
+			name:      "cancelled after a partial fill",
+			updates:   []*luno.GetOrderV3Response{testOrder(t, "A", luno.StatusComplete, "0.05")},
+			expected:  []EventKind{EventCancelled},
+			completes: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWatcher(DefaultMaxPerSession)
+			_, err := w.Add("s1", "A")
+			require.NoError(t, err)
+
+			client := sdk.NewMockLunoClient(t)
+			var kinds []EventKind
+			for _, update := range tc.updates {
+				client.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "A"}).Return(update, nil).Once()
+				for _, e := range w.Poll(context.Background(), only(client)) {
+					assert.Equal(t, "s1", e.Session)
+					assert.Equal(t, "A", e.OrderID)
+					kinds = append(kinds, e.Kind)
+				}
+			}
+			assert.Equal(t, tc.expected, kinds)
+			if tc.completes {
+				assert.Empty(t, w.List("s1"))
+			} else {
+				require.Len(t, w.List("s1"), 1)
+				assert.Equal(t, tc.updates[len(tc.updates)-1].Base, w.List("s1")[0].Filled)
+			}
+		})
+	}
+}
+
+func TestPollSharedOrder(t *testing.T) {
+	w := NewWatcher(DefaultMaxPerSession)
+	for _, session := range []string{"s2", "s1"} {
+		_, err := w.Add(session, "A")
+		require.NoError(t, err)
+	}
+	_, err := w.Add("s1", "B")
+	require.NoError(t, err)
+
+	// Each order is looked up once, and a failed lookup is retried on the next poll
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "A"}).
+		Return(testOrder(t, "A", luno.StatusComplete, "0.1"), nil).Once()
+	client.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "B"}).
+		Return(nil, errors.New("unavailable")).Once()
+
+	events := w.Poll(context.Background(), only(client))
+	require.Len(t, events, 2)
+	assert.Equal(t, "s1", events[0].Session)
+	assert.Equal(t, "s2", events[1].Session)
+	assert.Equal(t, "Order A on XBTZAR was filled: 0.1 at 1000000", events[0].Message())
+	assert.Equal(t, []string{"B"}, w.orderIDs())
+}
+
+func TestPollSessionClients(t *testing.T) {
+	w := NewWatcher(DefaultMaxPerSession)
+	for _, watch := range [][2]string{{"s2", "A"}, {"s1", "A"}, {"s2", "B"}} {
+		_, err := w.Add(watch[0], watch[1])
+		require.NoError(t, err)
+	}
+
+	// Each order is looked up with the client of the first session watching it
+	client1, client2 := sdk.NewMockLunoClient(t), sdk.NewMockLunoClient(t)
+	client1.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "A"}).
+		Return(testOrder(t, "A", luno.StatusPending, "0"), nil).Once()
+	client2.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "B"}).
+		Return(testOrder(t, "B", luno.StatusPending, "0"), nil).Once()
+	clients := map[string]sdk.LunoClient{"s1": client1, "s2": client2}
+
+	assert.Empty(t, w.Poll(context.Background(), func(session string) sdk.LunoClient { return clients[session] }))
+}
+
+func TestPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := state.Open(path)
+	require.NoError(t, err)
+	keep := func(session string) bool { return session != "gone" }
+
+	w := NewWatcher(DefaultMaxPerSession)
+	require.NoError(t, w.Persist(store, keep))
+	_, err = w.Add("s1", "A")
+	require.NoError(t, err)
+	_, err = w.Add("gone", "B")
+	require.NoError(t, err)
+
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "A"}).
+		Return(testOrder(t, "A", luno.StatusPending, "0.04"), nil).Once()
+	client.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "B"}).
+		Return(testOrder(t, "B", luno.StatusPending, "0"), nil).Once()
+	require.Len(t, w.Poll(context.Background(), only(client)), 1)
+
+	// After a restart the fill seen so far is remembered, so it isn't reported again
+	store, err = state.Open(path)
+	require.NoError(t, err)
+	restored := NewWatcher(DefaultMaxPerSession)
+	require.NoError(t, restored.Persist(store, keep))
+	assert.Equal(t, []string{"A"}, restored.orderIDs())
+
+	client = sdk.NewMockLunoClient(t)
+	client.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "A"}).
+		Return(testOrder(t, "A", luno.StatusPending, "0.04"), nil).Once()
+	assert.Empty(t, restored.Poll(context.Background(), only(client)))
+}
+
+func TestRun(t *testing.T) {
+	w := NewWatcher(DefaultMaxPerSession)
+	_, err := w.Add("s1", "A")
+	require.NoError(t, err)
+
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(testOrder(t, "A", luno.StatusComplete, "0"), nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan Event, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx, only(client), 10*time.Millisecond, func(e Event) { events <- e })
+	}()
+
+	select {
+	case e := <-events:
+		assert.Equal(t, EventCancelled, e.Kind)
+	case <-time.After(time.Second):
+		t.Fatal("Expected an event for the cancelled order")
+	}
+
+	// Nothing is looked up once the order has completed
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watcher to stop when the context is cancelled")
+	}
+}
@@ -0,0 +1,231 @@
+// Package outbox keeps the notifications the server sends to clients, such as
+// fired price alerts, watched order changes and the outcomes of scheduled
+// buys, until they are delivered.
+//
+// An event is sent as soon as it is added and then again every retry interval
+// until it reaches its client or expires, so a client that is briefly
+// unreachable, or a stdio client that reconnects after a restart, still gets
+// it. Delivery is at least once: an event may be sent again if the server
+// stops just after sending it, so every event carries an ID that clients can
+// ignore repeats by. Events are held in memory, and also saved to a state
+// store when Persist is called.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/state"
+)
+
+const (
+	// DefaultRetryInterval is how often undelivered events are sent again
+	DefaultRetryInterval = 10 * time.Second
+
+	// Retention is how long an event is kept while it can't be delivered
+	Retention = 24 * time.Hour
+
+	// MaxEvents is the number of undelivered events kept at once. The oldest
+	// is dropped to make room for a new one.
+	MaxEvents = 1000
+
+	// stateSection is the state store section events are saved in
+	stateSection = "outbox"
+)
+
+// Event is a notification waiting to be delivered
+type Event struct {
+	ID string `json:"id"`
+	// Session is the session the event is for, or "" for every client
+	Session   string          `json:"session,omitempty"`
+	Logger    string          `json:"logger"`
+	Message   string          `json:"message"`
+	Key       string          `json:"key"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+}
+
+// saved is the layout of the outbox section of the state store
+type saved struct {
+	NextID int64   `json:"next_id"`
+	Events []Event `json:"events"`
+}
+
+// Outbox is a concurrency-safe queue of events waiting to be delivered
+type Outbox struct {
+	now func() time.Time
+
+	// delivering is held while events are sent, so an event isn't sent by
+	// two deliveries at once
+	delivering sync.Mutex
+
+	mu     sync.Mutex
+	events []Event
+	nextID int64
+	store  *state.Store
+}
+
+// New creates an empty outbox
+func New() *Outbox {
+	return &Outbox{now: time.Now}
+}
+
+// Persist restores the events saved in store that are for every client or
+// for a session that keep reports true, and saves every later change to store
+func (o *Outbox) Persist(store *state.Store, keep func(session string) bool) error {
+	var saved saved
+	if _, err := store.Load(stateSection, &saved); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	restored := slices.DeleteFunc(saved.Events, func(e Event) bool { return e.Session != "" && !keep(e.Session) })
+	o.events = append(restored, o.events...)
+	o.purgeLocked()
+	o.nextID = max(o.nextID, saved.NextID)
+	o.store = store
+	o.saveLocked()
+	return nil
+}
+
+// Add queues an event for a session, or for every client when session is "".
+// details are kept as JSON, so they must already be redacted.
+func (o *Outbox) Add(session, logger, message, key string, details any) (Event, error) {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return Event{}, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.purgeLocked()
+	o.nextID++
+	now := o.now()
+	e := Event{
+		ID:        strconv.FormatInt(o.nextID, 10),
+		Session:   session,
+		Logger:    logger,
+		Message:   message,
+		Key:       key,
+		Details:   raw,
+		CreatedAt: now,
+		ExpiresAt: now.Add(Retention),
+	}
+	if len(o.events) >= MaxEvents {
+		o.events = slices.Delete(o.events, 0, len(o.events)-MaxEvents+1)
+	}
+	o.events = append(o.events, e)
+	o.saveLocked()
+	return e, nil
+}
+
+// Undelivered returns the events for a session and for every client that
+// haven't been delivered yet, oldest first
+func (o *Outbox) Undelivered(session string) []Event {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.purgeLocked()
+
+	var events []Event
+	for _, e := range o.events {
+		if e.Session == "" || e.Session == session {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// DeleteSession drops the events for a session
+func (o *Outbox) DeleteSession(session string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := len(o.events)
+	o.events = slices.DeleteFunc(o.events, func(e Event) bool { return e.Session == session })
+	if len(o.events) != n {
+		o.saveLocked()
+	}
+}
+
+// Deliver sends every undelivered event with send, oldest first. Events that
+// are sent are removed, and the others are kept with the error to be sent
+// again. It returns the number of events delivered.
+func (o *Outbox) Deliver(send func(Event) error) int {
+	o.delivering.Lock()
+	defer o.delivering.Unlock()
+
+	o.mu.Lock()
+	o.purgeLocked()
+	pending := slices.Clone(o.events)
+	o.mu.Unlock()
+	if len(pending) == 0 {
+		return 0
+	}
+
+	delivered := make(map[string]bool, len(pending))
+	failed := make(map[string]string)
+	for _, e := range pending {
+		if err := send(e); err != nil {
+			failed[e.ID] = err.Error()
+			continue
+		}
+		delivered[e.ID] = true
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = slices.DeleteFunc(o.events, func(e Event) bool { return delivered[e.ID] })
+	for i, e := range o.events {
+		if msg, ok := failed[e.ID]; ok {
+			o.events[i].Attempts++
+			o.events[i].LastError = msg
+		}
+	}
+	o.saveLocked()
+	return len(delivered)
+}
+
+// Run sends undelivered events again every interval until ctx is cancelled
+func (o *Outbox) Run(ctx context.Context, interval time.Duration, send func(Event) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		o.Deliver(send)
+	}
+}
+
+// purgeLocked drops the events that have expired without being delivered
+func (o *Outbox) purgeLocked() {
+	now := o.now()
+	n := len(o.events)
+	o.events = slices.DeleteFunc(o.events, func(e Event) bool { return !now.Before(e.ExpiresAt) })
+	if dropped := n - len(o.events); dropped > 0 {
+		slog.Warn("Dropped notifications that could not be delivered", slog.Int("count", dropped))
+	}
+}
+
+// saveLocked saves the events to the state store, if there is one. A failed
+// save is logged rather than returned, as the events are still delivered
+// from memory.
+func (o *Outbox) saveLocked() {
+	if o.store == nil {
+		return
+	}
+	if err := o.store.Save(stateSection, saved{NextID: o.nextID, Events: o.events}); err != nil {
+		slog.Warn("Failed to save undelivered notifications", slog.Any("error", err))
+	}
+}
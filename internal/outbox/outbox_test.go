@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a send function that fails for the sessions in down
+type recorder struct {
+	down map[string]bool
+	sent []string
+}
+
+func (r *recorder) send(e Event) error {
+	if r.down[e.Session] {
+		return errors.New("session not found")
+	}
+	r.sent = append(r.sent, e.ID)
+	return nil
+}
+
+func TestDeliver(t *testing.T) {
+	o := New()
+	r := &recorder{down: map[string]bool{"s2": true}}
+
+	_, err := o.Add("s1", "price-alerts", "Price alert 1", "alert", map[string]any{"pair": "XBTZAR"})
+	require.NoError(t, err)
+	_, err = o.Add("s2", "price-alerts", "Price alert 2", "alert", map[string]any{"pair": "ETHZAR"})
+	require.NoError(t, err)
+	_, err = o.Add("", "scheduled-orders", "Scheduled buy 1", "schedule", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, o.Deliver(r.send))
+	assert.Equal(t, []string{"1", "3"}, r.sent)
+
+	// The event that couldn't be delivered is kept with the error
+	undelivered := o.Undelivered("s2")
+	require.Len(t, undelivered, 1)
+	assert.Equal(t, "2", undelivered[0].ID)
+	assert.Equal(t, 1, undelivered[0].Attempts)
+	assert.Equal(t, "session not found", undelivered[0].LastError)
+	assert.JSONEq(t, `{"pair":"ETHZAR"}`, string(undelivered[0].Details))
+	assert.Empty(t, o.Undelivered("s1"))
+
+	// and sent again once the session is back
+	r.down = nil
+	assert.Equal(t, 1, o.Deliver(r.send))
+	assert.Equal(t, []string{"1", "3", "2"}, r.sent)
+	assert.Empty(t, o.Undelivered("s2"))
+	assert.Zero(t, o.Deliver(r.send))
+}
+
+func TestUndelivered(t *testing.T) {
+	o := New()
+	_, err := o.Add("s1", "order-watch", "Order filled", "order", nil)
+	require.NoError(t, err)
+	_, err = o.Add("", "scheduled-orders", "Scheduled buy 1", "schedule", nil)
+	require.NoError(t, err)
+	_, err = o.Add("s2", "order-watch", "Order cancelled", "order", nil)
+	require.NoError(t, err)
+
+	ids := func(events []Event) []string {
+		var ids []string
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"1", "2"}, ids(o.Undelivered("s1")))
+	assert.Equal(t, []string{"2", "3"}, ids(o.Undelivered("s2")))
+
+	o.DeleteSession("s1")
+	assert.Equal(t, []string{"2"}, ids(o.Undelivered("s1")))
+}
+
+func TestExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	o := New()
+	o.now = func() time.Time { return now }
+
+	e, err := o.Add("s1", "price-alerts", "Price alert 1", "alert", nil)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(Retention), e.ExpiresAt)
+
+	now = now.Add(Retention - time.Second)
+	assert.Len(t, o.Undelivered("s1"), 1)
+	now = now.Add(time.Second)
+	assert.Empty(t, o.Undelivered("s1"))
+}
+
+func TestMaxEvents(t *testing.T) {
+	o := New()
+	for range MaxEvents + 1 {
+		_, err := o.Add("s1", "price-alerts", "Price alert", "alert", nil)
+		require.NoError(t, err)
+	}
+	undelivered := o.Undelivered("s1")
+	require.Len(t, undelivered, MaxEvents)
+	assert.Equal(t, "2", undelivered[0].ID, "the oldest event is dropped")
+}
+
+func TestPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := state.Open(path)
+	require.NoError(t, err)
+	keep := func(session string) bool { return session != "gone" }
+
+	o := New()
+	require.NoError(t, o.Persist(store, keep))
+	_, err = o.Add("s1", "price-alerts", "Price alert 1", "alert", nil)
+	require.NoError(t, err)
+	_, err = o.Add("gone", "price-alerts", "Price alert 2", "alert", nil)
+	require.NoError(t, err)
+	_, err = o.Add("", "scheduled-orders", "Scheduled buy 1", "schedule", nil)
+	require.NoError(t, err)
+	r := &recorder{down: map[string]bool{"s1": true, "gone": true, "": true}}
+	assert.Zero(t, o.Deliver(r.send))
+
+	// After a restart the events that weren't delivered are back, for every
+	// client and for the sessions that are kept
+	store, err = state.Open(path)
+	require.NoError(t, err)
+	restored := New()
+	require.NoError(t, restored.Persist(store, keep))
+	undelivered := restored.Undelivered("s1")
+	require.Len(t, undelivered, 2)
+	assert.Equal(t, "1", undelivered[0].ID)
+	assert.Equal(t, 1, undelivered[0].Attempts)
+	assert.Equal(t, "3", undelivered[1].ID)
+	assert.Len(t, restored.Undelivered("gone"), 1, "only the event for every client")
+
+	e, err := restored.Add("s1", "price-alerts", "Price alert 4", "alert", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "4", e.ID, "IDs are not reused")
+}
+
+func TestRun(t *testing.T) {
+	o := New()
+	_, err := o.Add("s1", "price-alerts", "Price alert 1", "alert", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := make(chan Event, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Run(ctx, time.Millisecond, func(e Event) error {
+			sent <- e
+			return nil
+		})
+	}()
+
+	select {
+	case e := <-sent:
+		assert.Equal(t, "1", e.ID)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the event to be sent again")
+	}
+	cancel()
+	<-done
+	assert.Empty(t, o.Undelivered("s1"))
+}
@@ -0,0 +1,180 @@
+// Package pnl computes realized profit and loss from a history of trades.
+//
+// Buys add lots of the base asset at their cost in the counter currency,
+// including fees, and sells dispose of them. The cost basis of a sale is
+// taken from the oldest lots first (FIFO) or from the average cost of the
+// holding (weighted average). Only sales inside the reporting window count
+// towards the realized result, but every earlier trade is needed to know
+// what was held when the window started.
+package pnl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+)
+
+// scale is the number of decimal places used when apportioning cost
+const scale = 8
+
+// Method is how the cost basis of a sale is chosen
+type Method string
+
+const (
+	// MethodFIFO matches sales against the oldest holdings first
+	MethodFIFO Method = "fifo"
+	// MethodAverage uses the average cost of the holding at the time of the sale
+	MethodAverage Method = "average"
+)
+
+// ParseMethod converts a user supplied string into a Method
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToLower(strings.TrimSpace(s))); m {
+	case MethodFIFO, MethodAverage:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown method %q, must be %q or %q", s, MethodFIFO, MethodAverage)
+	}
+}
+
+// Trade is a fill of one of your orders
+type Trade struct {
+	Time time.Time
+	Buy  bool
+	// Base and Counter are the amounts exchanged, before fees
+	Base    decimal.Decimal
+	Counter decimal.Decimal
+	// FeeBase and FeeCounter are the fees charged in each currency
+	FeeBase    decimal.Decimal
+	FeeCounter decimal.Decimal
+}
+
+// Disposal is a sale matched against earlier purchases
+type Disposal struct {
+	Time      time.Time       `json:"time"`
+	Volume    decimal.Decimal `json:"volume"`
+	Proceeds  decimal.Decimal `json:"proceeds"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	Gain      decimal.Decimal `json:"gain"`
+}
+
+// Report is the realized result of the sales in a window and the holding left at its end
+type Report struct {
+	Method    Method          `json:"method"`
+	Sold      decimal.Decimal `json:"sold"`
+	Proceeds  decimal.Decimal `json:"proceeds"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	Realized  decimal.Decimal `json:"realized"`
+	// Holding and HoldingCost are what was left at the end of the window
+	Holding     decimal.Decimal `json:"holding"`
+	HoldingCost decimal.Decimal `json:"holding_cost"`
+	// UnmatchedVolume is sold volume with no earlier purchase to match,
+	// such as coins that were deposited. Its proceeds are left out of the
+	// realized result because their cost is unknown.
+	UnmatchedVolume   decimal.Decimal `json:"unmatched_volume"`
+	UnmatchedProceeds decimal.Decimal `json:"unmatched_proceeds"`
+	Disposals         []Disposal      `json:"disposals"`
+}
+
+// lot is a purchase that hasn't been sold yet
+type lot struct {
+	volume decimal.Decimal
+	cost   decimal.Decimal
+}
+
+// Calculate computes the realized result of the sales between from and to.
+// Trades must be sorted oldest first, and trades after to are ignored. A zero
+// from includes every sale.
+func Calculate(trades []Trade, method Method, from, to time.Time) Report {
+	r := Report{
+		Method:            method,
+		Sold:              decimal.Zero(),
+		Proceeds:          decimal.Zero(),
+		CostBasis:         decimal.Zero(),
+		Realized:          decimal.Zero(),
+		UnmatchedVolume:   decimal.Zero(),
+		UnmatchedProceeds: decimal.Zero(),
+		Disposals:         []Disposal{},
+	}
+
+	var lots []lot
+	for _, t := range trades {
+		if t.Time.After(to) {
+			break
+		}
+		if t.Buy {
+			lots = buy(lots, method, lot{volume: t.Base.Sub(t.FeeBase), cost: t.Counter.Add(t.FeeCounter)})
+			continue
+		}
+
+		volume := t.Base.Add(t.FeeBase)
+		proceeds := t.Counter.Sub(t.FeeCounter)
+		var matched, basis decimal.Decimal
+		lots, matched, basis = sell(lots, volume)
+		if t.Time.Before(from) || volume.Sign() <= 0 {
+			continue
+		}
+
+		if unmatched := volume.Sub(matched); unmatched.Sign() > 0 {
+			unmatchedProceeds := proceeds.Mul(unmatched).Div(volume, scale)
+			r.UnmatchedVolume = r.UnmatchedVolume.Add(unmatched)
+			r.UnmatchedProceeds = r.UnmatchedProceeds.Add(unmatchedProceeds)
+			proceeds = proceeds.Sub(unmatchedProceeds)
+		}
+		if matched.Sign() == 0 {
+			continue
+		}
+		gain := proceeds.Sub(basis)
+		r.Sold = r.Sold.Add(matched)
+		r.Proceeds = r.Proceeds.Add(proceeds)
+		r.CostBasis = r.CostBasis.Add(basis)
+		r.Realized = r.Realized.Add(gain)
+		r.Disposals = append(r.Disposals, Disposal{Time: t.Time, Volume: matched, Proceeds: proceeds, CostBasis: basis, Gain: gain})
+	}
+
+	r.Holding, r.HoldingCost = decimal.Zero(), decimal.Zero()
+	for _, l := range lots {
+		r.Holding = r.Holding.Add(l.volume)
+		r.HoldingCost = r.HoldingCost.Add(l.cost)
+	}
+	return r
+}
+
+// buy adds a purchase to the holding. With the weighted average method the
+// holding is a single lot.
+func buy(lots []lot, method Method, l lot) []lot {
+	if l.volume.Sign() <= 0 {
+		return lots
+	}
+	if method == MethodAverage && len(lots) > 0 {
+		lots[0].volume = lots[0].volume.Add(l.volume)
+		lots[0].cost = lots[0].cost.Add(l.cost)
+		return lots
+	}
+	return append(lots, l)
+}
+
+// sell removes volume from the holding, oldest lots first, and returns the
+// volume that could be matched and its cost
+func sell(lots []lot, volume decimal.Decimal) ([]lot, decimal.Decimal, decimal.Decimal) {
+	matched, basis := decimal.Zero(), decimal.Zero()
+	for len(lots) > 0 && volume.Sign() > 0 {
+		l := &lots[0]
+		if volume.Cmp(l.volume) >= 0 {
+			matched = matched.Add(l.volume)
+			basis = basis.Add(l.cost)
+			volume = volume.Sub(l.volume)
+			lots = lots[1:]
+			continue
+		}
+		cost := l.cost.Mul(volume).Div(l.volume, scale)
+		matched = matched.Add(volume)
+		basis = basis.Add(cost)
+		l.volume = l.volume.Sub(volume)
+		l.cost = l.cost.Sub(cost)
+		volume = decimal.Zero()
+	}
+	return lots, matched, basis
+}
@@ -0,0 +1,163 @@
+package pnl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(d int) time.Time {
+	return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+}
+
+func trade(d int, buy bool, base, counter int64) Trade {
+	return Trade{
+		Time:       day(d),
+		Buy:        buy,
+		Base:       decimal.NewFromInt64(base),
+		Counter:    decimal.NewFromInt64(counter),
+		FeeBase:    decimal.Zero(),
+		FeeCounter: decimal.Zero(),
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	m, err := ParseMethod(" FIFO ")
+	require.NoError(t, err)
+	assert.Equal(t, MethodFIFO, m)
+
+	m, err = ParseMethod("average")
+	require.NoError(t, err)
+	assert.Equal(t, MethodAverage, m)
+
+	_, err = ParseMethod("lifo")
+	assert.ErrorContains(t, err, `unknown method "lifo"`)
+}
+
+func TestCalculate(t *testing.T) {
+	history := []Trade{
+		trade(1, true, 1, 100),
+		trade(2, true, 1, 200),
+		trade(3, false, 1, 250),
+		trade(4, false, 1, 300),
+	}
+
+	tests := []struct {
+		name              string
+		trades            []Trade
+		method            Method
+		from, to          time.Time
+		expectedRealized  string
+		expectedBasis     string
+		expectedHolding   string
+		expectedCost      string
+		expectedUnmatched string
+		expectedDisposals int
+	}{
+		{
+			name:              "fifo",
+			trades:            history,
+			method:            MethodFIFO,
+			to:                day(31),
+			expectedRealized:  "250",
+			expectedBasis:     "300",
+			expectedHolding:   "0",
+			expectedCost:      "0",
+			expectedUnmatched: "0",
+			expectedDisposals: 2,
+		},
+		{
+			name:              "fifo partial sale keeps the newer lot",
+			trades:            history[:3],
+			method:            MethodFIFO,
+			to:                day(31),
+			expectedRealized:  "150",
+			expectedBasis:     "100",
+			expectedHolding:   "1",
+			expectedCost:      "200",
+			expectedUnmatched: "0",
+			expectedDisposals: 1,
+		},
+		{
+			name:              "weighted average",
+			trades:            history[:3],
+			method:            MethodAverage,
+			to:                day(31),
+			expectedRealized:  "100.00000000",
+			expectedBasis:     "150.00000000",
+			expectedHolding:   "1",
+			expectedCost:      "150.00000000",
+			expectedUnmatched: "0",
+			expectedDisposals: 1,
+		},
+		{
+			name:              "window only counts later sales but uses earlier lots",
+			trades:            history,
+			method:            MethodFIFO,
+			from:              day(4),
+			to:                day(31),
+			expectedRealized:  "100",
+			expectedBasis:     "200",
+			expectedHolding:   "0",
+			expectedCost:      "0",
+			expectedUnmatched: "0",
+			expectedDisposals: 1,
+		},
+		{
+			name:              "trades after the window are ignored",
+			trades:            history,
+			method:            MethodFIFO,
+			to:                day(3),
+			expectedRealized:  "150",
+			expectedBasis:     "100",
+			expectedHolding:   "1",
+			expectedCost:      "200",
+			expectedUnmatched: "0",
+			expectedDisposals: 1,
+		},
+		{
+			name:              "selling more than was bought",
+			trades:            []Trade{trade(1, true, 1, 100), trade(2, false, 2, 400)},
+			method:            MethodFIFO,
+			to:                day(31),
+			expectedRealized:  "100.00000000",
+			expectedBasis:     "100",
+			expectedHolding:   "0",
+			expectedCost:      "0",
+			expectedUnmatched: "1",
+			expectedDisposals: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Calculate(tc.trades, tc.method, tc.from, tc.to)
+			assert.Equal(t, tc.method, r.Method)
+			assert.Equal(t, tc.expectedRealized, r.Realized.String())
+			assert.Equal(t, tc.expectedBasis, r.CostBasis.String())
+			assert.Equal(t, tc.expectedHolding, r.Holding.String())
+			assert.Equal(t, tc.expectedCost, r.HoldingCost.String())
+			assert.Equal(t, tc.expectedUnmatched, r.UnmatchedVolume.String())
+			assert.Len(t, r.Disposals, tc.expectedDisposals)
+		})
+	}
+}
+
+func TestCalculateFees(t *testing.T) {
+	// Buying 1 with a base fee of 0.01 and selling it all with a counter fee of 2
+	trades := []Trade{
+		{Time: day(1), Buy: true, Base: decimal.NewFromInt64(1), Counter: decimal.NewFromInt64(100),
+			FeeBase: decimal.NewFromFloat64(0.01, 2), FeeCounter: decimal.Zero()},
+		{Time: day(2), Buy: false, Base: decimal.NewFromFloat64(0.99, 2), Counter: decimal.NewFromInt64(198),
+			FeeBase: decimal.Zero(), FeeCounter: decimal.NewFromInt64(2)},
+	}
+
+	r := Calculate(trades, MethodFIFO, time.Time{}, day(31))
+	assert.Equal(t, "0.99", r.Sold.String())
+	assert.Equal(t, "196", r.Proceeds.String())
+	assert.Equal(t, "96", r.Realized.String())
+	assert.Equal(t, "0", r.Holding.String())
+}
@@ -0,0 +1,179 @@
+// Package prompts provides MCP prompts that guide clients through common
+// Luno workflows using the server's tools.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/luno/luno-mcp/internal/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Prompt names
+const (
+	PortfolioReviewPrompt       = "portfolio_review"
+	PlaceLimitOrderSafelyPrompt = "place_limit_order_safely"
+	MarketOverviewPrompt        = "market_overview"
+)
+
+// NewPortfolioReviewPrompt creates a prompt for reviewing account balances and activity
+func NewPortfolioReviewPrompt() mcp.Prompt {
+	return mcp.NewPrompt(
+		PortfolioReviewPrompt,
+		mcp.WithPromptDescription("Review your Luno portfolio: balances, their current value, open orders and recent activity"),
+		mcp.WithArgument(
+			"quote_currency",
+			mcp.ArgumentDescription("Currency to value the portfolio in (default: ZAR)"),
+		),
+	)
+}
+
+// HandlePortfolioReviewPrompt returns a handler for the portfolio review prompt
+func HandlePortfolioReviewPrompt() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		quote := market.NormalizeAsset(argument(request, "quote_currency", "ZAR"))
+
+		text := fmt.Sprintf(`Please review my Luno portfolio.
+
+1. Call %s to list my accounts and balances.
+2. For each asset that is not %s, call %s with the pair for that asset against %s to value the balance. Skip assets without a market.
+3. Call %s to list my open orders and note how much of each balance is reserved by them.
+4. Summarise the total value in %s, the share of each asset, and anything that looks unusual, such as large reserved amounts or unconfirmed balances.
+
+Do not place or cancel any orders.`,
+			tools.GetBalancesToolID, quote, tools.GetTickerToolID, quote,
+			tools.ListOrdersToolID, quote)
+
+		return mcp.NewGetPromptResult(
+			"Review your Luno portfolio",
+			[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+		), nil
+	}
+}
+
+// NewPlaceLimitOrderSafelyPrompt creates a prompt for placing a limit order with checks
+func NewPlaceLimitOrderSafelyPrompt() mcp.Prompt {
+	return mcp.NewPrompt(
+		PlaceLimitOrderSafelyPrompt,
+		mcp.WithPromptDescription("Place a limit order after checking the market, your balance and the order size limits"),
+		mcp.WithArgument(
+			"pair",
+			mcp.ArgumentDescription("Trading pair (e.g., XBTZAR)"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument(
+			"side",
+			mcp.ArgumentDescription("BUY or SELL"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument(
+			"volume",
+			mcp.ArgumentDescription("Order volume in the base currency"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument(
+			"price",
+			mcp.ArgumentDescription("Limit price in the counter currency. If empty, suggest one from the order book."),
+		),
+	)
+}
+
+// HandlePlaceLimitOrderSafelyPrompt returns a handler for the safe limit order prompt
+func HandlePlaceLimitOrderSafelyPrompt() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		pair := market.NormalizePair(argument(request, "pair", ""))
+		side := strings.ToUpper(argument(request, "side", ""))
+		volume := argument(request, "volume", "")
+		if pair == "" || side == "" || volume == "" {
+			return nil, fmt.Errorf("pair, side and volume are required")
+		}
+
+		price := argument(request, "price", "")
+		priceStep := fmt.Sprintf("Use a limit price of %s.", price)
+		if price == "" {
+			priceStep = "Suggest a limit price based on the best bid and ask, and ask me to confirm it."
+		}
+
+		text := fmt.Sprintf(`I want to place a %s limit order for %s on %s.
+
+1. Call %s for %s and check the market is ACTIVE and the volume and price fit its precision and size limits.
+2. Call %s for %s and %s to see the current spread. %s
+3. Call %s and check I have enough available balance, taking reserved amounts into account.
+4. Show me the order details and the total cost or proceeds, and wait for my explicit confirmation.
+5. Only after I confirm, call %s. Then call %s with the returned order ID and report its status.
+
+If any check fails, explain why and do not place the order.`,
+			side, volume, pair,
+			tools.ListMarketsToolID, pair,
+			tools.GetTickerToolID, tools.GetOrderBookToolID, pair, priceStep,
+			tools.GetBalancesToolID,
+			tools.CreateOrderToolID, tools.GetOrderToolID)
+
+		return mcp.NewGetPromptResult(
+			fmt.Sprintf("Place a %s limit order on %s safely", side, pair),
+			[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+		), nil
+	}
+}
+
+// NewMarketOverviewPrompt creates a prompt for summarising market conditions
+func NewMarketOverviewPrompt() mcp.Prompt {
+	return mcp.NewPrompt(
+		MarketOverviewPrompt,
+		mcp.WithPromptDescription("Summarise current conditions for one or more Luno markets"),
+		mcp.WithArgument(
+			"pairs",
+			mcp.ArgumentDescription("Comma separated trading pairs (e.g., XBTZAR,ETHZAR). If empty, use all active markets in ZAR."),
+		),
+	)
+}
+
+// HandleMarketOverviewPrompt returns a handler for the market overview prompt
+func HandleMarketOverviewPrompt() server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		pairs := normalizePairs(argument(request, "pairs", ""))
+		marketStep := fmt.Sprintf("Call %s with pairs %s to confirm they are open for trading.", tools.ListMarketsToolID, pairs)
+		if pairs == "" {
+			marketStep = fmt.Sprintf("Call %s with currency ZAR and use the markets that are ACTIVE.", tools.ListMarketsToolID)
+		}
+
+		text := fmt.Sprintf(`Give me an overview of the market.
+
+1. %s
+2. For each market, call %s for the last price, bid, ask and 24 hour volume.
+3. Call %s for each market to see recent trades and describe the short term direction.
+4. Summarise each market in a short table with price, spread as a percentage and 24 hour volume, followed by any notable observations.
+
+This is for information only, do not place any orders.`,
+			marketStep, tools.GetTickerToolID, tools.ListTradesToolID)
+
+		return mcp.NewGetPromptResult(
+			"Luno market overview",
+			[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+		), nil
+	}
+}
+
+// argument returns a trimmed prompt argument, or def if it is empty
+func argument(request mcp.GetPromptRequest, name, def string) string {
+	if v := strings.TrimSpace(request.Params.Arguments[name]); v != "" {
+		return v
+	}
+	return def
+}
+
+// normalizePairs puts each pair of a comma separated list in Luno's format,
+// dropping empty entries
+func normalizePairs(s string) string {
+	var pairs []string
+	for _, p := range strings.Split(s, ",") {
+		if p = market.NormalizePair(strings.TrimSpace(p)); p != "" {
+			pairs = append(pairs, p)
+		}
+	}
+	return strings.Join(pairs, ",")
+}
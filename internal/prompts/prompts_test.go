@@ -0,0 +1,139 @@
+package prompts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createPromptRequest(args map[string]string) mcp.GetPromptRequest {
+	var req mcp.GetPromptRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestPromptDefinitions(t *testing.T) {
+	tests := []struct {
+		name         string
+		prompt       mcp.Prompt
+		expectedName string
+		required     []string
+	}{
+		{"portfolio review", NewPortfolioReviewPrompt(), PortfolioReviewPrompt, nil},
+		{"place limit order safely", NewPlaceLimitOrderSafelyPrompt(), PlaceLimitOrderSafelyPrompt, []string{"pair", "side", "volume"}},
+		{"market overview", NewMarketOverviewPrompt(), MarketOverviewPrompt, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedName, tc.prompt.Name)
+			assert.NotEmpty(t, tc.prompt.Description)
+
+			var required []string
+			for _, arg := range tc.prompt.Arguments {
+				if arg.Required {
+					required = append(required, arg.Name)
+				}
+			}
+			assert.Equal(t, tc.required, required)
+		})
+	}
+}
+
+func TestPromptHandlers(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       server.PromptHandlerFunc
+		args          map[string]string
+		expectedError bool
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:     "portfolio review defaults to ZAR",
+			handler:  HandlePortfolioReviewPrompt(),
+			contains: []string{tools.GetBalancesToolID, tools.ListOrdersToolID, "total value in ZAR"},
+		},
+		{
+			name:     "portfolio review with quote currency",
+			handler:  HandlePortfolioReviewPrompt(),
+			args:     map[string]string{"quote_currency": "EUR"},
+			contains: []string{"total value in EUR"},
+		},
+		{
+			name:     "portfolio review with quote currency alias",
+			handler:  HandlePortfolioReviewPrompt(),
+			args:     map[string]string{"quote_currency": "rands"},
+			contains: []string{"total value in ZAR"},
+		},
+		{
+			name:        "place order with price",
+			handler:     HandlePlaceLimitOrderSafelyPrompt(),
+			args:        map[string]string{"pair": "XBTZAR", "side": "buy", "volume": "0.01", "price": "1000000"},
+			contains:    []string{"BUY limit order for 0.01 on XBTZAR", "limit price of 1000000", tools.CreateOrderToolID, "explicit confirmation"},
+			notContains: []string{"Suggest a limit price"},
+		},
+		{
+			name:     "place order without price suggests one",
+			handler:  HandlePlaceLimitOrderSafelyPrompt(),
+			args:     map[string]string{"pair": "XBTZAR", "side": "SELL", "volume": "0.01"},
+			contains: []string{"Suggest a limit price"},
+		},
+		{
+			name:     "place order normalizes the pair",
+			handler:  HandlePlaceLimitOrderSafelyPrompt(),
+			args:     map[string]string{"pair": "btc/zar", "side": "buy", "volume": "0.01"},
+			contains: []string{"BUY limit order for 0.01 on XBTZAR"},
+		},
+		{
+			name:          "place order missing arguments",
+			handler:       HandlePlaceLimitOrderSafelyPrompt(),
+			args:          map[string]string{"pair": "XBTZAR"},
+			expectedError: true,
+		},
+		{
+			name:     "market overview for pairs",
+			handler:  HandleMarketOverviewPrompt(),
+			args:     map[string]string{"pairs": "XBTZAR,ETHZAR"},
+			contains: []string{"pairs XBTZAR,ETHZAR", tools.GetTickerToolID, tools.ListTradesToolID},
+		},
+		{
+			name:     "market overview normalizes pairs",
+			handler:  HandleMarketOverviewPrompt(),
+			args:     map[string]string{"pairs": "btc-zar, ethereum/rands,"},
+			contains: []string{"pairs XBTZAR,ETHZAR"},
+		},
+		{
+			name:     "market overview defaults to ZAR markets",
+			handler:  HandleMarketOverviewPrompt(),
+			contains: []string{"currency ZAR"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.handler(context.Background(), createPromptRequest(tc.args))
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Messages, 1)
+			assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+
+			text, ok := result.Messages[0].Content.(mcp.TextContent)
+			require.True(t, ok)
+			for _, s := range tc.contains {
+				assert.Contains(t, text.Text, s)
+			}
+			for _, s := range tc.notContains {
+				assert.NotContains(t, text.Text, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package redact scrubs credentials and identifying details from text
+// before it leaves the process, in logs or in notifications to clients.
+//
+// Known secrets, such as the configured API keys, are always replaced.
+// Crypto addresses and account IDs are found by their shape and by the
+// names of the fields holding them, and each can be turned off.
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Placeholder replaces every redacted value
+const Placeholder = "[REDACTED]"
+
+// Category is a kind of identifying detail that can be redacted
+type Category string
+
+const (
+	// Addresses are crypto wallet addresses
+	Addresses Category = "addresses"
+	// AccountIDs are Luno account IDs
+	AccountIDs Category = "account_ids"
+)
+
+// Categories are all the categories, in the order they are applied
+var Categories = []Category{Addresses, AccountIDs}
+
+// minSecretLength stops short values, which would match ordinary text,
+// from being treated as secrets
+const minSecretLength = 8
+
+// secretKeys are fragments of field names whose values are always redacted
+var secretKeys = []string{"secret", "password", "authorization", "token"}
+
+// accountKeys are field names holding account IDs
+var accountKeys = []string{"account_id", "accountid", "account"}
+
+var (
+	// addressPatterns match Bitcoin style base58 and bech32 addresses and
+	// Ethereum style hex addresses
+	addressPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:bc1|tb1|ltc1)[02-9ac-hj-np-z]{11,87}\b`),
+		regexp.MustCompile(`\b[13][1-9A-HJ-NP-Za-km-z]{25,34}\b`),
+		regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`),
+	}
+	// accountIDPattern matches an account ID named in text, such as
+	// account_id=123 or "account_id":"123"
+	accountIDPattern = regexp.MustCompile(`(?i)(account_?id"?\s*[:=]\s*"?)\d+`)
+)
+
+// Redactor replaces secrets and identifying details in text. A nil Redactor
+// leaves everything unchanged.
+type Redactor struct {
+	secrets    *strings.Replacer
+	categories []Category
+}
+
+// New creates a redactor for secrets and the categories of details.
+// Secrets shorter than 8 characters are ignored.
+func New(secrets []string, categories []Category) *Redactor {
+	var known []string
+	for _, s := range secrets {
+		if len(s) >= minSecretLength && !slices.Contains(known, s) {
+			known = append(known, s)
+		}
+	}
+	// The replacer tries secrets in order, so longer ones must come first
+	// for a secret containing another to be replaced whole
+	slices.SortFunc(known, func(a, b string) int { return len(b) - len(a) })
+
+	r := &Redactor{}
+	if len(known) > 0 {
+		pairs := make([]string, 0, 2*len(known))
+		for _, s := range known {
+			pairs = append(pairs, s, Placeholder)
+		}
+		r.secrets = strings.NewReplacer(pairs...)
+	}
+	for _, c := range Categories {
+		if slices.Contains(categories, c) {
+			r.categories = append(r.categories, c)
+		}
+	}
+	return r
+}
+
+// Categories returns the categories of details the redactor replaces
+func (r *Redactor) Categories() []Category {
+	if r == nil {
+		return nil
+	}
+	return slices.Clone(r.categories)
+}
+
+func (r *Redactor) has(c Category) bool {
+	return slices.Contains(r.categories, c)
+}
+
+// String returns s with secrets and identifying details replaced
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	if r.secrets != nil {
+		s = r.secrets.Replace(s)
+	}
+	if r.has(Addresses) {
+		for _, p := range addressPatterns {
+			s = p.ReplaceAllString(s, Placeholder)
+		}
+	}
+	if r.has(AccountIDs) {
+		s = accountIDPattern.ReplaceAllString(s, "${1}"+Placeholder)
+	}
+	return s
+}
+
+// redactsKey reports whether every value of the field key is replaced
+func (r *Redactor) redactsKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range secretKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return r.has(AccountIDs) && (slices.Contains(accountKeys, key) || strings.HasSuffix(key, "_account_id"))
+}
+
+// Value returns v with secrets and identifying details replaced in its
+// strings, for the maps and slices that make up notification data. Other
+// values that need redacting are replaced by their redacted JSON form.
+func (r *Redactor) Value(v any) any {
+	if r == nil {
+		return v
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.String(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			if r.redactsKey(k) {
+				out[k] = Placeholder
+				continue
+			}
+			out[k] = r.Value(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = r.Value(val)
+		}
+		return out
+	case error:
+		return r.String(v.Error())
+	case bool, int, int64, float64:
+		return v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return r.String(fmt.Sprintf("%+v", v))
+		}
+		var generic any
+		if err := json.Unmarshal(b, &generic); err != nil {
+			return v
+		}
+		redacted := r.Value(generic)
+		if reflect.DeepEqual(redacted, generic) {
+			return v
+		}
+		return redacted
+	}
+}
+
+// Attr returns a with secrets and identifying details replaced in its
+// value, and in the values of a group
+func (r *Redactor) Attr(a slog.Attr) slog.Attr {
+	if r == nil {
+		return a
+	}
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		attrs := v.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, ga := range attrs {
+			redacted[i] = r.Attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	}
+	if r.redactsKey(a.Key) {
+		return slog.String(a.Key, Placeholder)
+	}
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.String(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, r.String(err.Error()))
+		}
+		s := fmt.Sprintf("%+v", v.Any())
+		if redacted := r.String(s); redacted != s {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// Handler returns a handler that redacts the message and attributes of
+// every record before passing it to next
+func (r *Redactor) Handler(next slog.Handler) slog.Handler {
+	if r == nil {
+		return next
+	}
+	return &handler{r: r, next: next}
+}
+
+type handler struct {
+	r    *Redactor
+	next slog.Handler
+}
+
+// Enabled implements slog.Handler
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.r.String(record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.r.Attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.r.Attr(a)
+	}
+	return &handler{r: h.r, next: h.next.WithAttrs(redacted)}
+}
+
+// WithGroup implements slog.Handler
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{r: h.r, next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,119 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeyID  = "abcd1234efgh"
+	testSecret = "s3cr3t-value-0123456789"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []Category
+		in         string
+		expected   string
+	}{
+		{"secret", nil, "signing with " + testSecret, "signing with [REDACTED]"},
+		{"key id", nil, "key " + testKeyID + " was revoked", "key [REDACTED] was revoked"},
+		{"short value kept", nil, "short", "short"},
+		{"bech32 address", Categories, "send to bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq now", "send to [REDACTED] now"},
+		{"base58 address", Categories, "address 1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "address [REDACTED]"},
+		{"hex address", Categories, "to 0x52908400098527886E0F7030069857D2E4169EE7", "to [REDACTED]"},
+		{"address kept", []Category{AccountIDs}, "to 0x52908400098527886E0F7030069857D2E4169EE7", "to 0x52908400098527886E0F7030069857D2E4169EE7"},
+		{"account id", Categories, `{"account_id":"1224342323","balance":"1.5"}`, `{"account_id":"[REDACTED]","balance":"1.5"}`},
+		{"account id in text", Categories, "accountId=1224342323 not found", "accountId=[REDACTED] not found"},
+		{"account id kept", []Category{Addresses}, "account_id=1224342323", "account_id=1224342323"},
+		{"order id kept", Categories, "order BXMC2CJ7HNB88U4 placed", "order BXMC2CJ7HNB88U4 placed"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := New([]string{testKeyID, testSecret, "short"}, tc.categories)
+			assert.Equal(t, tc.expected, r.String(tc.in))
+		})
+	}
+}
+
+func TestNilRedactor(t *testing.T) {
+	var r *Redactor
+	assert.Equal(t, "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", r.String("bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"))
+	assert.Equal(t, map[string]any{"token": "x"}, r.Value(map[string]any{"token": "x"}))
+	assert.Nil(t, r.Categories())
+}
+
+func TestNewOrdersSecrets(t *testing.T) {
+	// A secret that contains another is still replaced whole
+	r := New([]string{"12345678", "12345678-and-more"}, nil)
+	assert.Equal(t, "[REDACTED]", r.String("12345678-and-more"))
+}
+
+func TestValue(t *testing.T) {
+	r := New([]string{testSecret}, Categories)
+	in := map[string]any{
+		"message":    "Order watch for 1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		"token":      "anything",
+		"account_id": int64(1224342323),
+		"price":      1.5,
+		"details":    []any{"key " + testSecret, true},
+		"error":      errors.New("bad " + testSecret),
+	}
+	expected := map[string]any{
+		"message":    "Order watch for [REDACTED]",
+		"token":      Placeholder,
+		"account_id": Placeholder,
+		"price":      1.5,
+		"details":    []any{"key [REDACTED]", true},
+		"error":      "bad [REDACTED]",
+	}
+	assert.Equal(t, expected, r.Value(in))
+}
+
+func TestValueStruct(t *testing.T) {
+	type event struct {
+		OrderID string `json:"order_id"`
+		Address string `json:"address"`
+	}
+	r := New(nil, Categories)
+
+	unchanged := event{OrderID: "BXMC2CJ7HNB88U4", Address: "none"}
+	assert.Equal(t, unchanged, r.Value(unchanged))
+
+	redacted := r.Value(event{OrderID: "BXMC2CJ7HNB88U4", Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"})
+	assert.Equal(t, map[string]any{"order_id": "BXMC2CJ7HNB88U4", "address": Placeholder}, redacted)
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	r := New([]string{testSecret}, Categories)
+	logger := slog.New(r.Handler(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	logger.With(slog.String("api_secret", "plain")).
+		WithGroup("call").
+		Info("Calling with "+testSecret,
+			slog.Int64("account_id", 1224342323),
+			slog.Any("arguments", map[string]any{"address": "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"}),
+			slog.Any("error", errors.New("rejected "+testSecret)),
+			slog.Group("request", slog.String("Authorization", "Bearer abc")),
+			slog.String("pair", "XBTZAR"))
+
+	out := buf.String()
+	require.NotContains(t, out, testSecret)
+	assert.Equal(t, `level=INFO msg="Calling with [REDACTED]" api_secret=[REDACTED] call.account_id=[REDACTED] `+
+		`call.arguments=map[address:[REDACTED]] call.error="rejected [REDACTED]" call.request.Authorization=[REDACTED] call.pair=XBTZAR`+"\n", out)
+}
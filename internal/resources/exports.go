@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/exports"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewExportTemplate creates a new resource template for exported results
+func NewExportTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		ExportTemplateURI,
+		"Luno Export",
+		mcp.WithTemplateDescription("Returns a result that was too large for a tool to return in full, such as a long statement. "+
+			"Tools give the URI of the export, which only the session that created it can read, until it expires."),
+	)
+}
+
+// HandleExportTemplate returns a handler for the export resource template
+func HandleExportTemplate(cfg *config.Config) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+		if cfg.Exports == nil {
+			return nil, fmt.Errorf("exports are not enabled")
+		}
+
+		id := exports.ParseURI(request.Params.URI)
+		if id == "" {
+			return nil, fmt.Errorf("invalid export URI format, expected %s", ExportTemplateURI)
+		}
+		var session string
+		if s := server.ClientSessionFromContext(ctx); s != nil {
+			session = s.SessionID()
+		}
+		export, data, err := cfg.Exports.Get(session, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      export.URI,
+				MIMEType: export.MIMEType,
+				Text:     data,
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,74 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/exports"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleExportTemplate(t *testing.T) {
+	store := exports.NewStore(0)
+	export, err := store.Add("", "text/csv", "row,amount\n1,100\n")
+	require.NoError(t, err)
+	other, err := store.Add("other-session", "text/csv", "row,amount\n")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		cfg           *config.Config
+		uri           string
+		expectedText  string
+		expectedError string
+	}{
+		{
+			name:         "export of the session",
+			cfg:          &config.Config{Exports: store},
+			uri:          export.URI,
+			expectedText: "1,100",
+		},
+		{
+			name:          "export of another session",
+			cfg:           &config.Config{Exports: store},
+			uri:           other.URI,
+			expectedError: "was not found",
+		},
+		{
+			name:          "invalid URI",
+			cfg:           &config.Config{Exports: store},
+			uri:           "luno://exports/a/b",
+			expectedError: "invalid export URI format",
+		},
+		{
+			name:          "exports not enabled",
+			cfg:           &config.Config{},
+			uri:           export.URI,
+			expectedError: "exports are not enabled",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = tc.uri
+
+			result, err := HandleExportTemplate(tc.cfg)(context.Background(), request)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			contents := result[0].(mcp.TextResourceContents)
+			assert.Equal(t, export.URI, contents.URI)
+			assert.Equal(t, "text/csv", contents.MIMEType)
+			assert.Equal(t, "row,amount\n1,100\n", contents.Text)
+			assert.Contains(t, contents.Text, tc.expectedText)
+		})
+	}
+}
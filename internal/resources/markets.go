@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// marketOrderBookDepth is the number of price levels returned per side
+const marketOrderBookDepth = 50
+
+// NewMarketTickerTemplate creates a new resource template for the ticker of a pair
+func NewMarketTickerTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		MarketTickerTemplateURI,
+		"Luno Market Ticker",
+		mcp.WithTemplateDescription("Returns the ticker for a trading pair (e.g. luno://markets/XBTZAR/ticker)"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+// NewMarketOrderBookTemplate creates a new resource template for the order book of a pair
+func NewMarketOrderBookTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		MarketOrderBookTemplateURI,
+		"Luno Market Order Book",
+		mcp.WithTemplateDescription("Returns the order book for a trading pair (e.g. luno://markets/XBTZAR/orderbook). "+
+			"Shows the top 50 price levels per side."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+// HandleMarketTickerTemplate returns a handler for the market ticker resource template
+func HandleMarketTickerTemplate(cfg *config.Config) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+		if cfg.LunoClient == nil {
+			return nil, fmt.Errorf("Luno client is not configured")
+		}
+
+		pair := extractMarketPair(request.Params.URI, "ticker")
+		if pair == "" {
+			return nil, fmt.Errorf("invalid market ticker URI format, expected %s", MarketTickerTemplateURI)
+		}
+
+		ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: pair})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticker: %w", err)
+		}
+
+		tickerJSON, err := json.MarshalIndent(ticker, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal ticker: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      MarketResourceURI(pair, "ticker"),
+				MIMEType: "application/json",
+				Text:     string(tickerJSON),
+			},
+		}, nil
+	}
+}
+
+// HandleMarketOrderBookTemplate returns a handler for the market order book resource template
+func HandleMarketOrderBookTemplate(cfg *config.Config) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+		if cfg.LunoClient == nil {
+			return nil, fmt.Errorf("Luno client is not configured")
+		}
+
+		pair := extractMarketPair(request.Params.URI, "orderbook")
+		if pair == "" {
+			return nil, fmt.Errorf("invalid market order book URI format, expected %s", MarketOrderBookTemplateURI)
+		}
+
+		book, err := cfg.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: pair})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order book: %w", err)
+		}
+		book.Bids = book.Bids[:min(len(book.Bids), marketOrderBookDepth)]
+		book.Asks = book.Asks[:min(len(book.Asks), marketOrderBookDepth)]
+
+		bookJSON, err := json.MarshalIndent(book, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal order book: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      MarketResourceURI(pair, "orderbook"),
+				MIMEType: "application/json",
+				Text:     string(bookJSON),
+			},
+		}, nil
+	}
+}
+
+// MarketResourceURI returns the URI of a market resource of a pair, such as its ticker
+func MarketResourceURI(pair, name string) string {
+	return "luno://markets/" + pair + "/" + name
+}
+
+// extractMarketPair extracts the pair from a URI like
+// "luno://markets/XBTZAR/ticker", in Luno's format
+func extractMarketPair(uri, name string) string {
+	rest, ok := strings.CutPrefix(uri, "luno://markets/")
+	if !ok {
+		return ""
+	}
+	pair, ok := strings.CutSuffix(rest, "/"+name)
+	if !ok || pair == "" || strings.Contains(pair, "/") {
+		return ""
+	}
+	return market.NormalizePair(pair)
+}
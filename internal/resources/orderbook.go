@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// liveOrderBookDepth is the number of price levels returned per side
+const liveOrderBookDepth = 50
+
+// NewLiveOrderBookTemplate creates a new resource template for streamed order books
+func NewLiveOrderBookTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		LiveOrderBookTemplateURI,
+		"Luno Live Order Book",
+		mcp.WithTemplateDescription("Returns the live order book for a trading pair (e.g. luno://orderbook/XBTZAR/live), "+
+			"kept up to date over the Luno streaming API. Reading a pair starts streaming it, and the server then sends "+
+			"resource updated notifications when it changes. Shows the top 50 price levels per side. "+
+			"While the stream is disconnected the book is marked stale and misses updates until it reconnects."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+// LiveOrderBookURI returns the live order book resource URI of a pair
+func LiveOrderBookURI(pair string) string {
+	return strings.Replace(LiveOrderBookTemplateURI, "{pair}", pair, 1)
+}
+
+// HandleLiveOrderBookTemplate returns a handler for the live order book resource template
+func HandleLiveOrderBookTemplate(cfg *config.Config) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+		if cfg.Streams == nil {
+			return nil, fmt.Errorf("live order books are only available from %s", config.DefaultLunoDomain)
+		}
+
+		pair := extractLiveOrderBookPair(request.Params.URI)
+		if pair == "" {
+			return nil, fmt.Errorf("invalid live order book URI format, expected %s", LiveOrderBookTemplateURI)
+		}
+
+		book, err := cfg.Streams.Snapshot(ctx, pair)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get live order book: %w", err)
+		}
+		book.Bids = book.Bids[:min(len(book.Bids), liveOrderBookDepth)]
+		book.Asks = book.Asks[:min(len(book.Asks), liveOrderBookDepth)]
+
+		bookJSON, err := json.MarshalIndent(book, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal live order book: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      LiveOrderBookURI(pair),
+				MIMEType: "application/json",
+				Text:     string(bookJSON),
+			},
+		}, nil
+	}
+}
+
+// extractLiveOrderBookPair extracts the pair from a URI like
+// "luno://orderbook/XBTZAR/live", in Luno's format
+func extractLiveOrderBookPair(uri string) string {
+	rest, ok := strings.CutPrefix(uri, "luno://orderbook/")
+	if !ok {
+		return ""
+	}
+	pair, ok := strings.CutSuffix(rest, "/live")
+	if !ok || pair == "" || strings.Contains(pair, "/") {
+		return ""
+	}
+	return market.NormalizePair(pair)
+}
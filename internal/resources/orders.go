@@ -0,0 +1,55 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewOpenOrdersResource creates a new resource for the open orders
+func NewOpenOrdersResource() mcp.Resource {
+	return mcp.NewResource(
+		OpenOrdersResourceURI,
+		"Luno Open Orders",
+		mcp.WithResourceDescription("Returns your open orders on every pair. Clients are notified when it changes."),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+// HandleOpenOrdersResource returns a handler for the open orders resource
+func HandleOpenOrdersResource(cfg *config.Config) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+		if cfg.LunoClient == nil {
+			return nil, fmt.Errorf("Luno client is not configured")
+		}
+
+		orders, err := cfg.LunoClient.ListOrders(ctx, &luno.ListOrdersRequest{State: luno.OrderStatePending})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list orders: %w", err)
+		}
+		if orders.Orders == nil {
+			orders.Orders = []luno.Order{}
+		}
+
+		ordersJSON, err := json.MarshalIndent(orders, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal orders: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      OpenOrdersResourceURI,
+				MIMEType: "application/json",
+				Text:     string(ordersJSON),
+			},
+		}, nil
+	}
+}
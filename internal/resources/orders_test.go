@@ -0,0 +1,67 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOpenOrdersResource(t *testing.T) {
+	resource := NewOpenOrdersResource()
+
+	assert.Equal(t, OpenOrdersResourceURI, resource.URI)
+	assert.Equal(t, "Luno Open Orders", resource.Name)
+	assert.Equal(t, expectedMIMEType, resource.MIMEType)
+}
+
+func TestHandleOpenOrdersResource(t *testing.T) {
+	tests := []struct {
+		name          string
+		orders        *luno.ListOrdersResponse
+		err           error
+		expectedText  string
+		expectedError string
+	}{
+		{
+			name:         "open orders",
+			orders:       &luno.ListOrdersResponse{Orders: []luno.Order{{OrderId: "BXMC2CJ7HNB88U4", Pair: "XBTZAR", State: luno.OrderStatePending}}},
+			expectedText: `"order_id": "BXMC2CJ7HNB88U4"`,
+		},
+		{
+			name:         "no open orders",
+			orders:       &luno.ListOrdersResponse{},
+			expectedText: `"orders": []`,
+		},
+		{
+			name:          "API error",
+			err:           errors.New("boom"),
+			expectedError: "failed to list orders: boom",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := sdk.NewMockLunoClient(t)
+			client.EXPECT().ListOrders(mock.Anything, &luno.ListOrdersRequest{State: luno.OrderStatePending}).Return(tc.orders, tc.err)
+
+			result, err := HandleOpenOrdersResource(&config.Config{LunoClient: client})(context.Background(), mcp.ReadResourceRequest{})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			contents := result[0].(mcp.TextResourceContents)
+			assert.Equal(t, OpenOrdersResourceURI, contents.URI)
+			assert.Contains(t, contents.Text, tc.expectedText)
+		})
+	}
+}
@@ -9,15 +9,25 @@ import (
 
 	"github.com/luno/luno-go"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/exports"
+	"github.com/luno/luno-mcp/internal/tools"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // Resource URIs
 const (
-	WalletResourceURI       = "luno://wallets"
-	TransactionsResourceURI = "luno://transactions"
-	AccountTemplateURI      = "luno://accounts/{id}"
+	WalletResourceURI          = "luno://wallets"
+	WalletTemplateURI          = "luno://wallets/{asset}"
+	TransactionsResourceURI    = "luno://transactions"
+	OpenOrdersResourceURI      = "luno://orders/open"
+	AccountTemplateURI         = "luno://accounts/{id}"
+	ConfigResourceURI          = "luno://config"
+	ServerInfoResourceURI      = "luno://server/info"
+	LiveOrderBookTemplateURI   = "luno://orderbook/{pair}/live"
+	MarketTickerTemplateURI    = "luno://markets/{pair}/ticker"
+	MarketOrderBookTemplateURI = "luno://markets/{pair}/orderbook"
+	ExportTemplateURI          = exports.TemplateURI
 )
 
 // NewWalletResource creates a new resource for Luno wallets
@@ -80,53 +90,21 @@ func HandleTransactionsResource(cfg *config.Config) server.ResourceHandlerFunc {
 			return nil, fmt.Errorf("Luno client is not configured")
 		}
 
-		// Get transactions for the first account that has them
 		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
 		if err != nil {
 			return nil, fmt.Errorf("failed to get balances: %w", err)
 		}
 
-		if len(balances.Balance) == 0 {
-			return []mcp.ResourceContents{
-				mcp.TextResourceContents{
-					URI:      TransactionsResourceURI,
-					MIMEType: "application/json",
-					Text:     "[]",
-				},
-			}, nil
+		limit := cfg.TransactionsPerAccount
+		if limit <= 0 {
+			limit = config.DefaultTransactionsPerAccount
 		}
-
-		// Take the first account with non-zero balance
-		var accountID string
-		for _, balance := range balances.Balance {
-			if balance.Balance.Sign() != 0 {
-				accountID = balance.AccountId
-				break
-			}
-		}
-
-		// If no account with non-zero balance is found, use the first one
-		if accountID == "" && len(balances.Balance) > 0 {
-			accountID = balances.Balance[0].AccountId
-		}
-		// Get transactions for the selected account
-		accountIDInt, err := strconv.ParseInt(accountID, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse account ID: %w", err)
-		}
-
-		txnReq := &luno.ListTransactionsRequest{
-			Id:     accountIDInt,
-			MinRow: 0,  // Start from the first transaction
-			MaxRow: 20, // Get up to 20 transactions
-		}
-
-		transactions, err := cfg.LunoClient.ListTransactions(ctx, txnReq)
+		result, err := recentTransactions(ctx, cfg.LunoClient, balances.Balance, limit)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get transactions: %w", err)
+			return nil, err
 		}
 
-		transactionsJSON, err := json.MarshalIndent(transactions, "", "  ")
+		transactionsJSON, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal transactions: %w", err)
 		}
@@ -225,6 +203,66 @@ func HandleAccountTemplate(cfg *config.Config) server.ResourceTemplateHandlerFun
 	}
 }
 
+// NewConfigResource creates a new resource for the server configuration
+func NewConfigResource() mcp.Resource {
+	return mcp.NewResource(
+		ConfigResourceURI,
+		"Luno MCP Configuration",
+		mcp.WithResourceDescription("Returns the effective server configuration, including enabled permissions and features, with credentials redacted"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+// HandleConfigResource returns a handler for the configuration resource
+func HandleConfigResource(cfg *config.Config) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+
+		configJSON, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      ConfigResourceURI,
+				MIMEType: "application/json",
+				Text:     string(configJSON),
+			},
+		}, nil
+	}
+}
+
+// NewServerInfoResource creates a new resource describing this server
+func NewServerInfoResource() mcp.Resource {
+	return mcp.NewResource(
+		ServerInfoResourceURI,
+		"Luno MCP Server Info",
+		mcp.WithResourceDescription("Returns the server version, transport, backend, environment, write status, enabled tools and rate limits"),
+		mcp.WithMIMEType("application/json"),
+	)
+}
+
+// HandleServerInfoResource returns a handler for the server info resource
+func HandleServerInfoResource(info tools.ServerInfo) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		infoJSON, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal server info: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      ServerInfoResourceURI,
+				MIMEType: "application/json",
+				Text:     string(infoJSON),
+			},
+		}, nil
+	}
+}
+
 // extractAccountID extracts the account ID from a URI like "luno://accounts/{id}"
 func extractAccountID(uri string) string {
 	// Simple extraction assuming the URI is in the format "luno://accounts/123"
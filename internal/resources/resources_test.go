@@ -5,9 +5,14 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -31,6 +36,14 @@ func TestNewTransactionsResource(t *testing.T) {
 	assert.Equal(t, expectedMIMEType, resource.MIMEType)
 }
 
+func TestNewConfigResource(t *testing.T) {
+	resource := NewConfigResource()
+
+	assert.Equal(t, ConfigResourceURI, resource.URI)
+	assert.Equal(t, "Luno MCP Configuration", resource.Name)
+	assert.Equal(t, expectedMIMEType, resource.MIMEType)
+}
+
 func TestNewAccountTemplate(t *testing.T) {
 	expectedJSON := `{
 		"uriTemplate": "luno://accounts/{id}",
@@ -263,3 +276,160 @@ func TestHandleAccountTemplateIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleConfigResource(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *config.Config
+		expectError bool
+	}{
+		{
+			name:        "nil config",
+			config:      nil,
+			expectError: true,
+		},
+		{
+			name: "read only config",
+			config: &config.Config{
+				Domain:      config.DefaultLunoDomain,
+				Transport:   "stdio",
+				Permissions: []config.Permission{config.PermissionRead},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := HandleConfigResource(tc.config)
+
+			result, err := handler(context.Background(), mcp.ReadResourceRequest{})
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			contents, ok := result[0].(mcp.TextResourceContents)
+			require.True(t, ok)
+			assert.Equal(t, ConfigResourceURI, contents.URI)
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal([]byte(contents.Text), &got))
+			assert.Equal(t, "stdio", got["transport"])
+			assert.Equal(t, config.DefaultLunoDomain, got["domain"])
+			assert.Equal(t, []any{"read"}, got["permissions"])
+			assert.Equal(t, "********", got["api_secret"])
+		})
+	}
+}
+
+func TestExtractLiveOrderBookPair(t *testing.T) {
+	tests := []struct {
+		uri      string
+		expected string
+	}{
+		{"luno://orderbook/XBTZAR/live", "XBTZAR"},
+		{"luno://orderbook/ethzar/live", "ETHZAR"},
+		{"luno://orderbook/BTC-ZAR/live", "XBTZAR"},
+		{"luno://orderbook//live", ""},
+		{"luno://orderbook/XBTZAR", ""},
+		{"luno://orderbook/XBT/ZAR/live", ""},
+		{"luno://accounts/123", ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, extractLiveOrderBookPair(tc.uri), tc.uri)
+	}
+	assert.Equal(t, "luno://orderbook/XBTZAR/live", LiveOrderBookURI("XBTZAR"))
+}
+
+func TestHandleLiveOrderBookTemplateUnavailable(t *testing.T) {
+	handler := HandleLiveOrderBookTemplate(&config.Config{})
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "luno://orderbook/XBTZAR/live"
+
+	_, err := handler(context.Background(), request)
+	assert.ErrorContains(t, err, "only available from api.luno.com")
+}
+
+func TestExtractMarketPair(t *testing.T) {
+	tests := []struct {
+		uri      string
+		name     string
+		expected string
+	}{
+		{"luno://markets/XBTZAR/ticker", "ticker", "XBTZAR"},
+		{"luno://markets/ethzar/orderbook", "orderbook", "ETHZAR"},
+		{"luno://markets/btczar/ticker", "ticker", "XBTZAR"},
+		{"luno://markets/XBTZAR/orderbook", "ticker", ""},
+		{"luno://markets//ticker", "ticker", ""},
+		{"luno://markets/XBT/ZAR/ticker", "ticker", ""},
+		{"luno://orderbook/XBTZAR/live", "orderbook", ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, extractMarketPair(tc.uri, tc.name), tc.uri)
+	}
+	assert.Equal(t, "luno://markets/XBTZAR/ticker", MarketResourceURI("XBTZAR", "ticker"))
+}
+
+func TestHandleMarketTickerTemplate(t *testing.T) {
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
+		Return(&luno.GetTickerResponse{Pair: "XBTZAR", LastTrade: decimal.NewFromInt64(1000000)}, nil)
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "luno://markets/xbtzar/ticker"
+
+	result, err := HandleMarketTickerTemplate(&config.Config{LunoClient: client})(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	contents := result[0].(mcp.TextResourceContents)
+	assert.Equal(t, "luno://markets/XBTZAR/ticker", contents.URI)
+	assert.Contains(t, contents.Text, `"last_trade": "1000000"`)
+}
+
+func TestHandleMarketOrderBookTemplate(t *testing.T) {
+	var bids []luno.OrderBookEntry
+	for i := range marketOrderBookDepth + 5 {
+		bids = append(bids, luno.OrderBookEntry{Price: decimal.NewFromInt64(int64(1000 - i)), Volume: decimal.NewFromInt64(1)})
+	}
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).
+		Return(&luno.GetOrderBookResponse{Bids: bids}, nil)
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "luno://markets/XBTZAR/orderbook"
+
+	result, err := HandleMarketOrderBookTemplate(&config.Config{LunoClient: client})(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	var book luno.GetOrderBookResponse
+	require.NoError(t, json.Unmarshal([]byte(result[0].(mcp.TextResourceContents).Text), &book))
+	assert.Len(t, book.Bids, marketOrderBookDepth)
+	assert.Empty(t, book.Asks)
+}
+
+func TestHandleMarketTemplatesInvalidURI(t *testing.T) {
+	cfg := &config.Config{LunoClient: sdk.NewMockLunoClient(t)}
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "luno://markets/XBTZAR"
+
+	_, err := HandleMarketTickerTemplate(cfg)(context.Background(), request)
+	assert.ErrorContains(t, err, "invalid market ticker URI")
+
+	_, err = HandleMarketOrderBookTemplate(cfg)(context.Background(), request)
+	assert.ErrorContains(t, err, "invalid market order book URI")
+}
+
+func TestNewServerInfoResource(t *testing.T) {
+	resource := NewServerInfoResource()
+
+	assert.Equal(t, ServerInfoResourceURI, resource.URI)
+	assert.Equal(t, "Luno MCP Server Info", resource.Name)
+	assert.Equal(t, expectedMIMEType, resource.MIMEType)
+}
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/toolmw"
+	"github.com/luno/luno-mcp/sdk"
+)
+
+// maxConcurrentAccounts is the number of accounts whose transactions are
+// fetched at the same time
+const maxConcurrentAccounts = 4
+
+// accountTransaction is a transaction with the account it belongs to
+type accountTransaction struct {
+	AccountID string `json:"account_id"`
+	Asset     string `json:"asset"`
+	luno.Transaction
+}
+
+// accountError is an account whose transactions couldn't be fetched
+type accountError struct {
+	AccountID string `json:"account_id"`
+	Asset     string `json:"asset"`
+	Error     string `json:"error"`
+}
+
+// transactionsResult is the content of the transactions resource
+type transactionsResult struct {
+	Transactions []accountTransaction `json:"transactions"`
+	Errors       []accountError       `json:"errors,omitempty"`
+}
+
+// recentTransactions fetches the latest limit transactions of every account
+// concurrently and merges them, newest first. Accounts that fail are reported
+// in the result, and an error is only returned when every account fails.
+func recentTransactions(ctx context.Context, client sdk.LunoClient, balances []luno.AccountBalance, limit int) (transactionsResult, error) {
+	rows := make([][]accountTransaction, len(balances))
+	errs := make([]error, len(balances))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentAccounts)
+	for i, bal := range balances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			rows[i], errs[i] = toolmw.Recovered("transactions of account "+bal.AccountId, func() ([]accountTransaction, error) {
+				return accountTransactions(ctx, client, bal, limit)
+			})
+		}()
+	}
+	wg.Wait()
+
+	result := transactionsResult{Transactions: []accountTransaction{}}
+	for i, bal := range balances {
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, accountError{AccountID: bal.AccountId, Asset: bal.Asset, Error: errs[i].Error()})
+			continue
+		}
+		result.Transactions = append(result.Transactions, rows[i]...)
+	}
+	if len(balances) > 0 && len(result.Errors) == len(balances) {
+		return transactionsResult{}, fmt.Errorf("failed to get transactions: %w", errs[0])
+	}
+
+	slices.SortStableFunc(result.Transactions, func(a, b accountTransaction) int {
+		return time.Time(b.Timestamp).Compare(time.Time(a.Timestamp))
+	})
+	return result, nil
+}
+
+// accountTransactions fetches the latest limit transactions of an account
+func accountTransactions(ctx context.Context, client sdk.LunoClient, bal luno.AccountBalance, limit int) ([]accountTransaction, error) {
+	id, err := strconv.ParseInt(bal.AccountId, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse account ID: %w", err)
+	}
+
+	// A negative min row counts back from the newest transaction
+	res, err := client.ListTransactions(ctx, &luno.ListTransactionsRequest{Id: id, MinRow: -int64(limit), MaxRow: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]accountTransaction, 0, len(res.Transactions))
+	for _, txn := range res.Transactions {
+		rows = append(rows, accountTransaction{AccountID: bal.AccountId, Asset: bal.Asset, Transaction: txn})
+	}
+	return rows, nil
+}
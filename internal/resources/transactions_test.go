@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func txn(row int64, minute int) luno.Transaction {
+	return luno.Transaction{RowIndex: row, Timestamp: luno.Time(time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC))}
+}
+
+func TestRecentTransactions(t *testing.T) {
+	balances := []luno.AccountBalance{
+		{AccountId: "1", Asset: "XBT"},
+		{AccountId: "2", Asset: "ZAR"},
+	}
+
+	tests := []struct {
+		name          string
+		responses     map[int64][]luno.Transaction
+		errs          map[int64]error
+		expectedRows  []string
+		expectedErrs  []string
+		expectedError bool
+	}{
+		{
+			name: "merged newest first",
+			responses: map[int64][]luno.Transaction{
+				1: {txn(1, 1), txn(2, 5)},
+				2: {txn(7, 3)},
+			},
+			expectedRows: []string{"1/XBT", "2/ZAR", "1/XBT"},
+		},
+		{
+			name: "failed accounts are reported",
+			responses: map[int64][]luno.Transaction{
+				1: {txn(1, 1)},
+			},
+			errs:         map[int64]error{2: errors.New("forbidden")},
+			expectedRows: []string{"1/XBT"},
+			expectedErrs: []string{"2"},
+		},
+		{
+			name:          "every account failing is an error",
+			errs:          map[int64]error{1: errors.New("forbidden"), 2: errors.New("forbidden")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := sdk.NewMockLunoClient(t)
+			for _, id := range []int64{1, 2} {
+				req := &luno.ListTransactionsRequest{Id: id, MinRow: -5, MaxRow: 0}
+				if err := tc.errs[id]; err != nil {
+					client.EXPECT().ListTransactions(mock.Anything, req).Return(nil, err)
+					continue
+				}
+				client.EXPECT().ListTransactions(mock.Anything, req).
+					Return(&luno.ListTransactionsResponse{Transactions: tc.responses[id]}, nil)
+			}
+
+			result, err := recentTransactions(context.Background(), client, balances, 5)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var rows []string
+			for i, r := range result.Transactions {
+				rows = append(rows, r.AccountID+"/"+r.Asset)
+				if i > 0 {
+					assert.False(t, time.Time(r.Timestamp).After(time.Time(result.Transactions[i-1].Timestamp)))
+				}
+			}
+			assert.Equal(t, tc.expectedRows, rows)
+
+			var failed []string
+			for _, e := range result.Errors {
+				failed = append(failed, e.AccountID)
+			}
+			assert.Equal(t, tc.expectedErrs, failed)
+		})
+	}
+}
+
+func TestRecentTransactionsNoAccounts(t *testing.T) {
+	result, err := recentTransactions(context.Background(), sdk.NewMockLunoClient(t), nil, 5)
+	require.NoError(t, err)
+	assert.Empty(t, result.Transactions)
+	assert.NotNil(t, result.Transactions)
+}
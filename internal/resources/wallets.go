@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// walletResult is the content of a wallet resource: the accounts holding an
+// asset, their combined balance and their recent transactions
+type walletResult struct {
+	Asset       string                `json:"asset"`
+	Balance     decimal.Decimal       `json:"balance"`
+	Reserved    decimal.Decimal       `json:"reserved"`
+	Unconfirmed decimal.Decimal       `json:"unconfirmed"`
+	Accounts    []luno.AccountBalance `json:"accounts"`
+	transactionsResult
+}
+
+// NewWalletTemplate creates a new resource template for the wallet of an asset
+func NewWalletTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate(
+		WalletTemplateURI,
+		"Luno Wallet",
+		mcp.WithTemplateDescription("Returns the balance, reserved amount and recent transactions of one asset (e.g. luno://wallets/XBT). "+
+			"Common names such as BTC are accepted."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+}
+
+// HandleWalletTemplate returns a handler for the wallet resource template
+func HandleWalletTemplate(cfg *config.Config) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg == nil {
+			return nil, fmt.Errorf("configuration is nil")
+		}
+		if cfg.LunoClient == nil {
+			return nil, fmt.Errorf("Luno client is not configured")
+		}
+
+		asset := extractWalletAsset(request.Params.URI)
+		if asset == "" {
+			return nil, fmt.Errorf("invalid wallet URI format, expected %s", WalletTemplateURI)
+		}
+
+		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balances: %w", err)
+		}
+
+		result := walletResult{Asset: asset, Accounts: []luno.AccountBalance{}}
+		var assets []string
+		for _, bal := range balances.Balance {
+			if !slices.Contains(assets, bal.Asset) {
+				assets = append(assets, bal.Asset)
+			}
+			if bal.Asset != asset {
+				continue
+			}
+			result.Accounts = append(result.Accounts, bal)
+			result.Balance = result.Balance.Add(bal.Balance)
+			result.Reserved = result.Reserved.Add(bal.Reserved)
+			result.Unconfirmed = result.Unconfirmed.Add(bal.Unconfirmed)
+		}
+		if len(result.Accounts) == 0 {
+			slices.Sort(assets)
+			return nil, fmt.Errorf("no %s wallet, you have wallets for: %s", asset, strings.Join(assets, ", "))
+		}
+
+		limit := cfg.TransactionsPerAccount
+		if limit <= 0 {
+			limit = config.DefaultTransactionsPerAccount
+		}
+		result.transactionsResult, err = recentTransactions(ctx, cfg.LunoClient, result.Accounts, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		walletJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal wallet: %w", err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      WalletURI(asset),
+				MIMEType: "application/json",
+				Text:     string(walletJSON),
+			},
+		}, nil
+	}
+}
+
+// WalletURI returns the URI of the wallet resource of an asset
+func WalletURI(asset string) string {
+	return WalletResourceURI + "/" + asset
+}
+
+// extractWalletAsset extracts the asset from a URI like "luno://wallets/XBT",
+// replacing an alias with its Luno code
+func extractWalletAsset(uri string) string {
+	asset, ok := strings.CutPrefix(uri, WalletResourceURI+"/")
+	if !ok || strings.TrimSpace(asset) == "" || strings.Contains(asset, "/") {
+		return ""
+	}
+	return market.NormalizeAsset(asset)
+}
@@ -0,0 +1,91 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractWalletAsset(t *testing.T) {
+	tests := []struct {
+		uri      string
+		expected string
+	}{
+		{"luno://wallets/XBT", "XBT"},
+		{"luno://wallets/btc", "XBT"},
+		{"luno://wallets/rand", "ZAR"},
+		{"luno://wallets/", ""},
+		{"luno://wallets", ""},
+		{"luno://wallets/XBT/transactions", ""},
+		{"luno://accounts/XBT", ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, extractWalletAsset(tc.uri), tc.uri)
+	}
+	assert.Equal(t, "luno://wallets/XBT", WalletURI("XBT"))
+}
+
+func TestHandleWalletTemplate(t *testing.T) {
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{AccountId: "1", Asset: "XBT", Balance: decimal.NewFromFloat64(0.5, 8), Reserved: decimal.NewFromFloat64(0.1, 8)},
+		{AccountId: "2", Asset: "ZAR", Balance: decimal.NewFromInt64(1000)},
+		{AccountId: "3", Asset: "XBT", Balance: decimal.NewFromFloat64(0.25, 8), Name: "Savings"},
+	}}, nil)
+	client.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 1, MinRow: -5, MaxRow: 0}).
+		Return(&luno.ListTransactionsResponse{Transactions: []luno.Transaction{{RowIndex: 7, Description: "Bought"}}}, nil)
+	client.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 3, MinRow: -5, MaxRow: 0}).
+		Return(&luno.ListTransactionsResponse{}, nil)
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "luno://wallets/bitcoin"
+
+	result, err := HandleWalletTemplate(&config.Config{LunoClient: client, TransactionsPerAccount: 5})(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	contents := result[0].(mcp.TextResourceContents)
+	assert.Equal(t, "luno://wallets/XBT", contents.URI)
+	var wallet struct {
+		Asset        string                `json:"asset"`
+		Balance      string                `json:"balance"`
+		Reserved     string                `json:"reserved"`
+		Accounts     []luno.AccountBalance `json:"accounts"`
+		Transactions []accountTransaction  `json:"transactions"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &wallet))
+	assert.Equal(t, "XBT", wallet.Asset)
+	assert.Equal(t, "0.75000000", wallet.Balance)
+	assert.Equal(t, "0.10000000", wallet.Reserved)
+	assert.Len(t, wallet.Accounts, 2)
+	require.Len(t, wallet.Transactions, 1)
+	assert.Equal(t, "1", wallet.Transactions[0].AccountID)
+}
+
+func TestHandleWalletTemplateErrors(t *testing.T) {
+	client := sdk.NewMockLunoClient(t)
+	client.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{AccountId: "2", Asset: "ZAR"},
+		{AccountId: "1", Asset: "XBT"},
+	}}, nil).Once()
+	handler := HandleWalletTemplate(&config.Config{LunoClient: client})
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "luno://wallets/ETH"
+	_, err := handler(context.Background(), request)
+	assert.EqualError(t, err, "no ETH wallet, you have wallets for: XBT, ZAR")
+
+	request.Params.URI = "luno://wallets/"
+	_, err = handler(context.Background(), request)
+	assert.ErrorContains(t, err, "invalid wallet URI format")
+}
@@ -0,0 +1,365 @@
+// Package schedule places recurring buy orders, such as a weekly dollar cost
+// average into a market.
+//
+// Schedules are not tied to an MCP session. They are saved to the state store
+// on every change, so they survive restarts. A run that was missed while the
+// server was down is made once when it is next checked, and the runs missed
+// before it are skipped. A cancelled schedule is kept for a retention window
+// so that it can be restored.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+)
+
+const (
+	// DefaultPollInterval is how often schedules are checked for runs that are due
+	DefaultPollInterval = time.Minute
+
+	// DefaultMaxSchedules is the number of schedules that can exist at once
+	DefaultMaxSchedules = 20
+
+	// Retention is how long a cancelled schedule can be restored
+	Retention = 24 * time.Hour
+
+	// stateSection is the state store section schedules are saved in
+	stateSection = "schedules"
+)
+
+// Frequency is how often a schedule runs
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+)
+
+// ParseFrequency converts a user supplied string into a Frequency
+func ParseFrequency(s string) (Frequency, error) {
+	switch f := Frequency(strings.ToLower(strings.TrimSpace(s))); f {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown frequency %q, must be %q, %q or %q", s, FrequencyDaily, FrequencyWeekly, FrequencyMonthly)
+	}
+}
+
+// occurrence returns the nth run of a schedule that starts at start. Monthly
+// runs keep the start's day of the month, or the last day of shorter months.
+func (f Frequency) occurrence(start time.Time, n int) time.Time {
+	switch f {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	default:
+		first := time.Date(start.Year(), start.Month()+time.Month(n), 1,
+			start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		lastDay := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(start.Day(), lastDay)-1)
+	}
+}
+
+// nextAfter returns the first run of a schedule starting at start that is
+// after t
+func (f Frequency) nextAfter(start, t time.Time) time.Time {
+	if start.After(t) {
+		return start
+	}
+	// Daily and weekly runs are evenly spaced, so skip straight to the
+	// right one. Monthly runs are few enough to step through.
+	n := 1
+	switch f {
+	case FrequencyDaily:
+		n = int(t.Sub(start)/(24*time.Hour)) + 1
+	case FrequencyWeekly:
+		n = int(t.Sub(start)/(7*24*time.Hour)) + 1
+	}
+	for !f.occurrence(start, n).After(t) {
+		n++
+	}
+	return f.occurrence(start, n)
+}
+
+// Schedule is a recurring buy of a fixed value of a market's base currency
+type Schedule struct {
+	ID   string `json:"id"`
+	Pair string `json:"pair"`
+	// Amount is the value to buy each run, in the pair's counter currency
+	Amount    decimal.Decimal `json:"amount"`
+	Frequency Frequency       `json:"frequency"`
+	// MaxSlippagePercent is how far above the best ask each order may be priced
+	MaxSlippagePercent decimal.Decimal `json:"max_slippage_percent"`
+	Start              time.Time       `json:"start"`
+	NextRun            time.Time       `json:"next_run"`
+	CreatedAt          time.Time       `json:"created_at"`
+	Runs               int             `json:"runs"`
+	LastRun            *Run            `json:"last_run,omitempty"`
+}
+
+// Run is the outcome of one run of a schedule
+type Run struct {
+	At      time.Time `json:"at"`
+	OrderID string    `json:"order_id,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Event is a run of a schedule, for notifying clients
+type Event struct {
+	Schedule Schedule `json:"schedule"`
+	Run      Run      `json:"run"`
+}
+
+// Message describes the run for the client
+func (e Event) Message() string {
+	s := e.Schedule
+	if e.Run.Error != "" {
+		return fmt.Sprintf("Scheduled buy %s of %s on %s failed: %s. The next run is at %s",
+			s.ID, s.Amount, s.Pair, e.Run.Error, s.NextRun.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Scheduled buy %s of %s on %s placed order %s. The next run is at %s",
+		s.ID, s.Amount, s.Pair, e.Run.OrderID, s.NextRun.Format(time.RFC3339))
+}
+
+// PlaceFunc places the order for a run of a schedule, returning its order ID
+type PlaceFunc func(ctx context.Context, s Schedule) (orderID string, err error)
+
+// Cancelled is a cancelled schedule that can be restored until it expires
+type Cancelled struct {
+	Schedule
+	CancelledAt time.Time `json:"cancelled_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// saved is the layout of the schedules section of the state store
+type saved struct {
+	NextID    int64       `json:"next_id"`
+	Schedules []Schedule  `json:"schedules"`
+	Cancelled []Cancelled `json:"cancelled,omitempty"`
+}
+
+// Scheduler is a concurrency-safe set of schedules saved to a state store
+type Scheduler struct {
+	store        *state.Store
+	maxSchedules int
+	now          func() time.Time
+
+	mu        sync.Mutex
+	schedules []Schedule
+	cancelled []Cancelled
+	nextID    int64
+}
+
+// Open loads the schedules saved in store, starting with none if there are
+// none yet
+func Open(store *state.Store, maxSchedules int) (*Scheduler, error) {
+	var saved saved
+	if _, err := store.Load(stateSection, &saved); err != nil {
+		return nil, err
+	}
+	return &Scheduler{
+		store:        store,
+		maxSchedules: maxSchedules,
+		now:          time.Now,
+		schedules:    saved.Schedules,
+		cancelled:    saved.Cancelled,
+		nextID:       saved.NextID,
+	}, nil
+}
+
+// Add creates a schedule whose first run is at start, or as soon as it is
+// checked if start is zero or has passed
+func (s *Scheduler) Add(pair string, amount decimal.Decimal, frequency Frequency, maxSlippagePercent decimal.Decimal, start time.Time) (Schedule, error) {
+	if amount.Sign() <= 0 {
+		return Schedule{}, fmt.Errorf("amount must be greater than zero, got %s", amount)
+	}
+	if maxSlippagePercent.Sign() < 0 {
+		return Schedule{}, fmt.Errorf("max slippage can't be negative, got %s", maxSlippagePercent)
+	}
+	if _, err := ParseFrequency(string(frequency)); err != nil {
+		return Schedule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.schedules) >= s.maxSchedules {
+		return Schedule{}, fmt.Errorf("there can be at most %d schedules, cancel one first", s.maxSchedules)
+	}
+	now := s.now().UTC()
+	if start.IsZero() || start.Before(now) {
+		start = now
+	}
+	s.nextID++
+	sch := Schedule{
+		ID:                 strconv.FormatInt(s.nextID, 10),
+		Pair:               pair,
+		Amount:             amount,
+		Frequency:          frequency,
+		MaxSlippagePercent: maxSlippagePercent,
+		Start:              start.UTC(),
+		NextRun:            start.UTC(),
+		CreatedAt:          now,
+	}
+	s.schedules = append(s.schedules, sch)
+	if err := s.saveLocked(); err != nil {
+		s.schedules = s.schedules[:len(s.schedules)-1]
+		s.nextID--
+		return Schedule{}, fmt.Errorf("saving schedule: %w", err)
+	}
+	return sch, nil
+}
+
+// List returns the schedules in the order they were created
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.schedules)
+}
+
+// Cancel removes a schedule and reports whether it existed. The schedule
+// can be restored until the retention window has passed.
+func (s *Scheduler) Cancel(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.schedules, func(sch Schedule) bool { return sch.ID == id })
+	if i < 0 {
+		return false, nil
+	}
+	previous, previousCancelled := slices.Clone(s.schedules), slices.Clone(s.cancelled)
+	now := s.now().UTC()
+	s.purgeLocked()
+	s.cancelled = append(s.cancelled, Cancelled{Schedule: s.schedules[i], CancelledAt: now, ExpiresAt: now.Add(Retention)})
+	s.schedules = slices.Delete(s.schedules, i, i+1)
+	if err := s.saveLocked(); err != nil {
+		s.schedules, s.cancelled = previous, previousCancelled
+		return false, fmt.Errorf("saving schedules: %w", err)
+	}
+	return true, nil
+}
+
+// Cancelled returns the cancelled schedules that can still be restored, in
+// the order they were cancelled
+func (s *Scheduler) Cancelled() []Cancelled {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+	return slices.Clone(s.cancelled)
+}
+
+// Restore brings back a cancelled schedule. Runs missed while it was
+// cancelled are skipped.
+func (s *Scheduler) Restore(id string) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+
+	i := slices.IndexFunc(s.cancelled, func(c Cancelled) bool { return c.ID == id })
+	if i < 0 {
+		return Schedule{}, fmt.Errorf("no cancelled schedule with ID %s, schedules can only be restored for %.0f hours after they are cancelled", id, Retention.Hours())
+	}
+	if len(s.schedules) >= s.maxSchedules {
+		return Schedule{}, fmt.Errorf("there can be at most %d schedules, cancel one first", s.maxSchedules)
+	}
+	sch := s.cancelled[i].Schedule
+	if now := s.now().UTC(); sch.NextRun.Before(now) {
+		sch.NextRun = sch.Frequency.nextAfter(sch.Start, now)
+	}
+	previous, previousCancelled := slices.Clone(s.schedules), slices.Clone(s.cancelled)
+	s.schedules = append(s.schedules, sch)
+	s.cancelled = slices.Delete(s.cancelled, i, i+1)
+	if err := s.saveLocked(); err != nil {
+		s.schedules, s.cancelled = previous, previousCancelled
+		return Schedule{}, fmt.Errorf("saving schedules: %w", err)
+	}
+	return sch, nil
+}
+
+// purgeLocked forgets cancelled schedules whose retention window has passed
+func (s *Scheduler) purgeLocked() {
+	now := s.now()
+	s.cancelled = slices.DeleteFunc(s.cancelled, func(c Cancelled) bool { return !now.Before(c.ExpiresAt) })
+}
+
+// due returns the schedules whose next run has come
+func (s *Scheduler) due() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	var due []Schedule
+	for _, sch := range s.schedules {
+		if !sch.NextRun.After(now) {
+			due = append(due, sch)
+		}
+	}
+	return due
+}
+
+// finish records a run of a schedule and moves it to its next run. It
+// returns the updated schedule, or false if it was cancelled while running.
+func (s *Scheduler) finish(id string, run Run) (Schedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.schedules, func(sch Schedule) bool { return sch.ID == id })
+	if i < 0 {
+		return Schedule{}, false
+	}
+	sch := &s.schedules[i]
+	sch.Runs++
+	sch.LastRun = &run
+	sch.NextRun = sch.Frequency.nextAfter(sch.Start, s.now().UTC())
+	if err := s.saveLocked(); err != nil {
+		// The run is still recorded in memory, and saved with the next change
+		slog.Warn("Failed to save schedules", slog.Any("error", err))
+	}
+	return *sch, true
+}
+
+// saveLocked saves the schedules to the state store
+func (s *Scheduler) saveLocked() error {
+	return s.store.Save(stateSection, saved{NextID: s.nextID, Schedules: s.schedules, Cancelled: s.cancelled})
+}
+
+// Run checks for due schedules every interval until ctx is cancelled,
+// placing an order for each with place and calling notify with the outcome
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, place PlaceFunc, notify func(Event)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, sch := range s.due() {
+			run := Run{At: s.now().UTC()}
+			orderID, err := place(ctx, sch)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				run.Error = err.Error()
+			} else {
+				run.OrderID = orderID
+			}
+			if updated, ok := s.finish(sch.ID, run); ok {
+				notify(Event{Schedule: updated, Run: run})
+			}
+		}
+	}
+}
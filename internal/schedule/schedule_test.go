@@ -0,0 +1,271 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse(time.RFC3339, s)
+	require.NoError(t, err)
+	return d
+}
+
+// openStore opens the state store at path, as the server does when it starts
+func openStore(t *testing.T, path string) *state.Store {
+	t.Helper()
+	store, err := state.Open(path)
+	require.NoError(t, err)
+	return store
+}
+
+func TestParseFrequency(t *testing.T) {
+	f, err := ParseFrequency(" Weekly ")
+	require.NoError(t, err)
+	assert.Equal(t, FrequencyWeekly, f)
+
+	_, err = ParseFrequency("hourly")
+	assert.ErrorContains(t, err, `unknown frequency "hourly"`)
+}
+
+func TestNextAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency Frequency
+		start     string
+		after     string
+		expected  string
+	}{
+		{
+			name:      "not started yet",
+			frequency: FrequencyDaily,
+			start:     "2025-01-10T08:00:00Z",
+			after:     "2025-01-01T00:00:00Z",
+			expected:  "2025-01-10T08:00:00Z",
+		},
+		{
+			name:      "daily",
+			frequency: FrequencyDaily,
+			start:     "2025-01-10T08:00:00Z",
+			after:     "2025-01-10T08:00:00Z",
+			expected:  "2025-01-11T08:00:00Z",
+		},
+		{
+			name:      "daily skips missed runs",
+			frequency: FrequencyDaily,
+			start:     "2025-01-10T08:00:00Z",
+			after:     "2025-01-15T09:30:00Z",
+			expected:  "2025-01-16T08:00:00Z",
+		},
+		{
+			name:      "weekly",
+			frequency: FrequencyWeekly,
+			start:     "2025-01-06T08:00:00Z",
+			after:     "2025-01-20T07:00:00Z",
+			expected:  "2025-01-20T08:00:00Z",
+		},
+		{
+			name:      "monthly",
+			frequency: FrequencyMonthly,
+			start:     "2025-01-15T08:00:00Z",
+			after:     "2025-01-15T08:00:01Z",
+			expected:  "2025-02-15T08:00:00Z",
+		},
+		{
+			name:      "monthly on a day short months don't have",
+			frequency: FrequencyMonthly,
+			start:     "2025-01-31T08:00:00Z",
+			after:     "2025-02-01T00:00:00Z",
+			expected:  "2025-02-28T08:00:00Z",
+		},
+		{
+			name:      "monthly goes back to the start day",
+			frequency: FrequencyMonthly,
+			start:     "2025-01-31T08:00:00Z",
+			after:     "2025-02-28T08:00:00Z",
+			expected:  "2025-03-31T08:00:00Z",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.frequency.nextAfter(date(t, tc.start), date(t, tc.after))
+			assert.Equal(t, date(t, tc.expected), got)
+		})
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(openStore(t, path), 2)
+	require.NoError(t, err)
+	now := date(t, "2025-01-10T08:00:00Z")
+	s.now = func() time.Time { return now }
+
+	amount := decimal.NewFromInt64(500)
+	slippage := decimal.NewFromInt64(1)
+
+	_, err = s.Add("XBTZAR", decimal.Zero(), FrequencyDaily, slippage, time.Time{})
+	assert.ErrorContains(t, err, "amount must be greater than zero")
+	_, err = s.Add("XBTZAR", amount, "hourly", slippage, time.Time{})
+	assert.ErrorContains(t, err, "unknown frequency")
+
+	first, err := s.Add("XBTZAR", amount, FrequencyDaily, slippage, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "1", first.ID)
+	assert.Equal(t, now, first.NextRun, "a schedule without a start runs straight away")
+
+	start := date(t, "2025-01-13T06:00:00Z")
+	second, err := s.Add("ETHZAR", amount, FrequencyWeekly, slippage, start)
+	require.NoError(t, err)
+	assert.Equal(t, start, second.NextRun)
+
+	_, err = s.Add("XBTZAR", amount, FrequencyDaily, slippage, time.Time{})
+	assert.ErrorContains(t, err, "at most 2 schedules")
+
+	// Schedules are loaded again after a restart
+	reopened, err := Open(openStore(t, path), 2)
+	require.NoError(t, err)
+	assert.Len(t, reopened.List(), 2)
+
+	found, err := reopened.Cancel("1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = reopened.Cancel("1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	reopened, err = Open(openStore(t, path), 2)
+	require.NoError(t, err)
+	require.Len(t, reopened.List(), 1)
+	third, err := reopened.Add("XBTZAR", amount, FrequencyDaily, slippage, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "3", third.ID, "IDs are not reused")
+}
+
+func TestRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(openStore(t, path), 1)
+	require.NoError(t, err)
+	now := date(t, "2025-01-10T08:00:00Z")
+	s.now = func() time.Time { return now }
+
+	amount := decimal.NewFromInt64(500)
+	sch, err := s.Add("XBTZAR", amount, FrequencyDaily, decimal.Zero(), time.Time{})
+	require.NoError(t, err)
+	found, err := s.Cancel(sch.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	cancelled := s.Cancelled()
+	require.Len(t, cancelled, 1)
+	assert.Equal(t, sch.ID, cancelled[0].ID)
+	assert.Equal(t, now.Add(Retention), cancelled[0].ExpiresAt)
+
+	// Restoring counts towards the schedule limit
+	other, err := s.Add("ETHZAR", amount, FrequencyDaily, decimal.Zero(), time.Time{})
+	require.NoError(t, err)
+	_, err = s.Restore(sch.ID)
+	assert.ErrorContains(t, err, "at most 1 schedules")
+	_, err = s.Cancel(other.ID)
+	require.NoError(t, err)
+
+	// Cancelled schedules survive a restart, and runs missed while a schedule
+	// was cancelled are skipped
+	now = now.Add(20 * time.Hour)
+	reopened, err := Open(openStore(t, path), 1)
+	require.NoError(t, err)
+	reopened.now = func() time.Time { return now }
+	assert.Len(t, reopened.Cancelled(), 2)
+	restored, err := reopened.Restore(sch.ID)
+	require.NoError(t, err)
+	assert.Equal(t, date(t, "2025-01-11T08:00:00Z"), restored.NextRun)
+	assert.Equal(t, []Schedule{restored}, reopened.List())
+	_, err = reopened.Restore(sch.ID)
+	assert.ErrorContains(t, err, "no cancelled schedule with ID 1")
+
+	// Cancelled schedules are forgotten after the retention window
+	now = now.Add(Retention)
+	assert.Empty(t, reopened.Cancelled())
+	_, err = reopened.Restore(other.ID)
+	assert.ErrorContains(t, err, "only be restored for 24 hours")
+}
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Open(openStore(t, path), 5)
+	require.NoError(t, err)
+	now := date(t, "2025-01-10T08:00:00Z")
+	s.now = func() time.Time { return now }
+
+	_, err = s.Add("XBTZAR", decimal.NewFromInt64(500), FrequencyDaily, decimal.Zero(), time.Time{})
+	require.NoError(t, err)
+	_, err = s.Add("ETHZAR", decimal.NewFromInt64(500), FrequencyDaily, decimal.Zero(), time.Time{})
+	require.NoError(t, err)
+	_, err = s.Add("XBTZAR", decimal.NewFromInt64(500), FrequencyDaily, decimal.Zero(), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	// The server was down for three days, so the due schedules run once each
+	now = now.Add(3*24*time.Hour + time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	place := func(_ context.Context, sch Schedule) (string, error) {
+		if sch.Pair == "ETHZAR" {
+			return "", errors.New("insufficient ZAR balance")
+		}
+		return "BX" + sch.ID, nil
+	}
+	events := make(chan Event, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, time.Millisecond, place, func(e Event) { events <- e })
+	}()
+
+	var got []Event
+	for len(got) < 3 {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 runs, got %d", len(got))
+		}
+	}
+	cancel()
+	<-done
+
+	byID := map[string]Event{}
+	for _, e := range got {
+		byID[e.Schedule.ID] = e
+	}
+	require.Len(t, byID, 3, "each schedule runs once")
+
+	assert.Equal(t, "BX1", byID["1"].Run.OrderID)
+	assert.Equal(t, date(t, "2025-01-14T08:00:00Z"), byID["1"].Schedule.NextRun)
+	assert.Equal(t, 1, byID["1"].Schedule.Runs)
+	assert.Contains(t, byID["1"].Message(), "placed order BX1")
+
+	assert.Equal(t, "insufficient ZAR balance", byID["2"].Run.Error)
+	assert.Contains(t, byID["2"].Message(), "failed: insufficient ZAR balance")
+
+	assert.Equal(t, date(t, "2025-01-13T09:00:00Z"), byID["3"].Schedule.NextRun)
+
+	// The outcome of each run is saved
+	reopened, err := Open(openStore(t, path), 5)
+	require.NoError(t, err)
+	list := reopened.List()
+	require.Len(t, list, 3)
+	require.NotNil(t, list[0].LastRun)
+	assert.Equal(t, "BX1", list[0].LastRun.OrderID)
+	assert.Equal(t, date(t, "2025-01-14T08:00:00Z"), list[0].NextRun)
+}
@@ -0,0 +1,142 @@
+// Package security provides helpers for handling secrets and short-lived
+// tokens, such as authorization and confirmation tokens.
+//
+// Tokens are compared in constant time, only their hashes are kept in memory,
+// and random material is wiped once it has been encoded.
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTokenTTL is how long an issued token is valid for by default.
+// Tokens guard actions on real funds so this is kept short.
+const DefaultTokenTTL = 2 * time.Minute
+
+// tokenBytes is the amount of random material in a token
+const tokenBytes = 32
+
+var (
+	// ErrTokenInvalid is returned for unknown, used or mismatched tokens
+	ErrTokenInvalid = errors.New("token is invalid or has already been used")
+	// ErrTokenExpired is returned for tokens used after their TTL
+	ErrTokenExpired = errors.New("token has expired")
+)
+
+// Equal reports whether a and b are equal in constant time. Both values are
+// hashed first so that the comparison does not leak their lengths either.
+func Equal(a, b string) bool {
+	ha := sha256.Sum256([]byte(a))
+	hb := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// Wipe overwrites b with zeros. Use it on byte slices that held secret
+// material once they are no longer needed. Go strings are immutable and
+// can't be wiped, so keep secrets in byte slices where possible.
+func Wipe(b []byte) {
+	clear(b)
+}
+
+// NewToken returns a random URL safe token
+func NewToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	defer Wipe(b)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type issuedToken struct {
+	binding [sha256.Size]byte
+	expires time.Time
+}
+
+// TokenStore issues single use tokens bound to a value, for example a
+// description of the action a confirmation token approves. Only hashes of
+// tokens and bindings are stored.
+type TokenStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	tokens map[[sha256.Size]byte]issuedToken
+}
+
+// NewTokenStore creates a token store. A zero ttl uses DefaultTokenTTL.
+func NewTokenStore(ttl time.Duration) *TokenStore {
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	return &TokenStore{
+		ttl:    ttl,
+		now:    time.Now,
+		tokens: make(map[[sha256.Size]byte]issuedToken),
+	}
+}
+
+// TTL returns how long issued tokens are valid for
+func (s *TokenStore) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue creates a token bound to binding, returning it and when it expires
+func (s *TokenStore) Issue(binding string) (string, time.Time, error) {
+	token, err := NewToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.pruneLocked(now)
+	expires := now.Add(s.ttl)
+	s.tokens[sha256.Sum256([]byte(token))] = issuedToken{
+		binding: sha256.Sum256([]byte(binding)),
+		expires: expires,
+	}
+	return token, expires, nil
+}
+
+// Consume checks that token was issued for binding and has not expired.
+// A token can only be consumed once, whether or not the check succeeds, so
+// a mismatched binding can't be retried with the same token.
+func (s *TokenStore) Consume(token, binding string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sha256.Sum256([]byte(token))
+	issued, ok := s.tokens[key]
+	if !ok {
+		return ErrTokenInvalid
+	}
+	delete(s.tokens, key)
+
+	if !s.now().Before(issued.expires) {
+		return ErrTokenExpired
+	}
+	want := sha256.Sum256([]byte(binding))
+	if subtle.ConstantTimeCompare(issued.binding[:], want[:]) != 1 {
+		return ErrTokenInvalid
+	}
+	return nil
+}
+
+// pruneLocked removes expired tokens. s.mu must be held.
+func (s *TokenStore) pruneLocked(now time.Time) {
+	for k, t := range s.tokens {
+		if !now.Before(t.expires) {
+			delete(s.tokens, k)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{"equal", "secret-token", "secret-token", true},
+		{"different", "secret-token", "secret-tokeN", false},
+		{"different length", "secret", "secret-token", false},
+		{"both empty", "", "", true},
+		{"one empty", "", "secret", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Equal(tc.a, tc.b))
+		})
+	}
+}
+
+func TestWipe(t *testing.T) {
+	b := []byte("secret")
+	Wipe(b)
+	assert.Equal(t, make([]byte, len("secret")), b)
+}
+
+func TestNewToken(t *testing.T) {
+	a, err := NewToken()
+	require.NoError(t, err)
+	b, err := NewToken()
+	require.NoError(t, err)
+
+	assert.Len(t, a, 43)
+	assert.NotEqual(t, a, b)
+}
+
+func TestTokenStore(t *testing.T) {
+	tests := []struct {
+		name          string
+		consumeAfter  time.Duration
+		binding       string
+		token         func(issued string) string
+		expectedError error
+	}{
+		{
+			name:    "valid token",
+			binding: "create_order XBTZAR",
+		},
+		{
+			name:          "wrong binding",
+			binding:       "create_order ETHZAR",
+			expectedError: ErrTokenInvalid,
+		},
+		{
+			name:          "unknown token",
+			binding:       "create_order XBTZAR",
+			token:         func(string) string { return "unknown" },
+			expectedError: ErrTokenInvalid,
+		},
+		{
+			name:          "expired token",
+			binding:       "create_order XBTZAR",
+			consumeAfter:  time.Minute,
+			expectedError: ErrTokenExpired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Unix(1700000000, 0)
+			s := NewTokenStore(time.Minute)
+			s.now = func() time.Time { return now }
+
+			issued, expires, err := s.Issue("create_order XBTZAR")
+			require.NoError(t, err)
+			assert.Equal(t, now.Add(time.Minute), expires)
+
+			token := issued
+			if tc.token != nil {
+				token = tc.token(issued)
+			}
+			now = now.Add(tc.consumeAfter)
+			assert.Equal(t, tc.expectedError, s.Consume(token, tc.binding))
+		})
+	}
+}
+
+func TestTokenStoreSingleUse(t *testing.T) {
+	s := NewTokenStore(0)
+	assert.Equal(t, DefaultTokenTTL, s.TTL())
+
+	token, _, err := s.Issue("cancel_order 123")
+	require.NoError(t, err)
+
+	require.NoError(t, s.Consume(token, "cancel_order 123"))
+	assert.Equal(t, ErrTokenInvalid, s.Consume(token, "cancel_order 123"))
+
+	// A failed check also uses up the token
+	token, _, err = s.Issue("cancel_order 123")
+	require.NoError(t, err)
+	assert.Equal(t, ErrTokenInvalid, s.Consume(token, "cancel_order 456"))
+	assert.Equal(t, ErrTokenInvalid, s.Consume(token, "cancel_order 123"))
+}
+
+func TestTokenStorePrunesExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewTokenStore(time.Minute)
+	s.now = func() time.Time { return now }
+
+	_, _, err := s.Issue("a")
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, _, err = s.Issue("b")
+	require.NoError(t, err)
+
+	assert.Len(t, s.tokens, 1)
+}
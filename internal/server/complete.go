@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/resources"
+	"github.com/luno/luno-mcp/internal/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// completionProvider answers completion/complete requests for prompt and
+// resource template arguments, which share their names with tool arguments
+type completionProvider struct {
+	cfg *config.Config
+}
+
+// CompletePromptArgument implements server.PromptCompletionProvider
+func (p completionProvider) CompletePromptArgument(ctx context.Context, _ string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	return tools.CompleteArgument(ctx, p.cfg, argument.Name, argument.Value)
+}
+
+// CompleteResourceArgument implements server.ResourceCompletionProvider
+func (p completionProvider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, _ mcp.CompleteContext) (*mcp.Completion, error) {
+	name := argument.Name
+	if uri == resources.AccountTemplateURI && name == "id" {
+		name = tools.AccountIDArgument
+	}
+	return tools.CompleteArgument(ctx, p.cfg, name, argument.Value)
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/config"
+)
+
+// corsMaxAge is how long browsers may cache a preflight response, in seconds
+const corsMaxAge = "600"
+
+// withCORS lets browsers at the allowed origins call next and rejects
+// requests from other origins with 403 Forbidden, so that a web page can't
+// use a browser to reach a server on localhost. Requests without an Origin
+// header, which browsers always send cross-origin, are passed through.
+func withCORS(cors config.CORS, next http.Handler) http.Handler {
+	if cors.AllowsAnyOrigin() {
+		slog.Warn("SSE server allows requests from any browser origin")
+	}
+	methods := strings.Join(cors.AllowedMethods, ", ")
+	headers := strings.Join(cors.AllowedHeaders, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		if !cors.AllowsOrigin(origin) {
+			slog.Warn("Rejected request from a disallowed origin",
+				slog.String("origin", origin),
+				slog.String("path", r.URL.Path))
+			http.Error(w, "Origin not allowed", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+
+		// Answer preflight requests here, since they carry no credentials
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
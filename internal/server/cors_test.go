@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCORS(t *testing.T) {
+	cors, err := config.LoadCORS(func(k string) string {
+		if k == config.EnvMCPCORSOrigins {
+			return "https://app.example.com, http://localhost:*"
+		}
+		return ""
+	})
+	require.NoError(t, err)
+
+	var reached bool
+	handler := withCORS(cors, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name            string
+		method          string
+		origin          string
+		preflight       bool
+		expectedStatus  int
+		expectedOrigin  string
+		expectedMethods string
+		expectReached   bool
+	}{
+		{name: "no origin", method: http.MethodPost, expectedStatus: http.StatusOK, expectReached: true},
+		{name: "allowed origin", method: http.MethodGet, origin: "https://app.example.com", expectedStatus: http.StatusOK, expectedOrigin: "https://app.example.com", expectReached: true},
+		{name: "localhost on any port", method: http.MethodPost, origin: "http://localhost:5173", expectedStatus: http.StatusOK, expectedOrigin: "http://localhost:5173", expectReached: true},
+		{name: "other scheme", method: http.MethodGet, origin: "http://app.example.com", expectedStatus: http.StatusForbidden},
+		{name: "lookalike host", method: http.MethodGet, origin: "http://localhost.evil.com", expectedStatus: http.StatusForbidden},
+		{name: "disallowed origin", method: http.MethodPost, origin: "https://evil.example.com", expectedStatus: http.StatusForbidden},
+		{name: "preflight", method: http.MethodOptions, origin: "https://app.example.com", preflight: true, expectedStatus: http.StatusNoContent, expectedOrigin: "https://app.example.com", expectedMethods: "GET, POST, OPTIONS"},
+		{name: "disallowed preflight", method: http.MethodOptions, origin: "https://evil.example.com", preflight: true, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reached = false
+			req := httptest.NewRequest(tc.method, "/message", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			require.Equal(t, tc.expectedOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			require.Equal(t, tc.expectedMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+			require.Equal(t, tc.expectReached, reached)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/sdk"
+)
+
+const (
+	// readinessTTL is how long a readiness result is reused, so that frequent
+	// probes don't use up the API rate limit
+	readinessTTL = 10 * time.Second
+	// readinessTimeout bounds the API call made by a readiness check
+	readinessTimeout = 5 * time.Second
+)
+
+// healthStatus is the body of health and readiness responses
+type healthStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// readinessChecker verifies that the Luno API is reachable and the
+// credentials are valid, caching the result for readinessTTL
+type readinessChecker struct {
+	client sdk.LunoClient
+	now    func() time.Time
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// check returns the cached result or makes a balance request, which needs
+// valid credentials but has no side effects
+func (r *readinessChecker) check(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.checkedAt.IsZero() && r.now().Sub(r.checkedAt) < readinessTTL {
+		return r.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	_, r.err = r.client.GetBalances(ctx, &luno.GetBalancesRequest{})
+	r.checkedAt = r.now()
+	if r.err != nil {
+		slog.Warn("Readiness check failed", slog.Any("error", r.err))
+	}
+	return r.err
+}
+
+// withHealthChecks serves /healthz and /readyz for orchestrators and passes
+// every other request to next. /healthz only reports that the process is
+// serving requests, /readyz also checks the Luno API and credentials.
+func withHealthChecks(client sdk.LunoClient, next http.Handler) http.Handler {
+	readiness := &readinessChecker{client: client, now: time.Now}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeHealth(w, http.StatusOK, healthStatus{Status: "ok"})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := readiness.check(r.Context()); err != nil {
+			writeHealth(w, http.StatusServiceUnavailable, healthStatus{Status: "unavailable", Error: err.Error()})
+			return
+		}
+		writeHealth(w, http.StatusOK, healthStatus{Status: "ok"})
+	})
+	mux.Handle("/", next)
+	return mux
+}
+
+func writeHealth(w http.ResponseWriter, code int, status healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		slog.Debug("Failed to write health response", slog.Any("error", err))
+	}
+}
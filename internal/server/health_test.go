@@ -0,0 +1,95 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHealthChecks(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*sdk.MockLunoClient)
+		expectedStatus int
+		expectedBody   healthStatus
+	}{
+		{
+			name:           "healthz does not call the API",
+			path:           "/healthz",
+			mockSetup:      func(*sdk.MockLunoClient) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   healthStatus{Status: "ok"},
+		},
+		{
+			name: "readyz with valid credentials",
+			path: "/readyz",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   healthStatus{Status: "ok"},
+		},
+		{
+			name: "readyz with invalid credentials",
+			path: "/readyz",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errors.New("Unauthorized (ErrUnauthorised)")).Once()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   healthStatus{Status: "unavailable", Error: "Unauthorized (ErrUnauthorised)"},
+		},
+		{
+			name:           "other paths go to the next handler",
+			path:           "/sse",
+			mockSetup:      func(*sdk.MockLunoClient) {},
+			expectedStatus: http.StatusTeapot,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			})
+			handler := withHealthChecks(mockClient, next)
+
+			// Probes within the TTL reuse the first result
+			for range 2 {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+				require.Equal(t, tc.expectedStatus, rec.Code)
+				if tc.expectedBody.Status == "" {
+					continue
+				}
+				var body healthStatus
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				require.Equal(t, tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestReadinessCheckerExpires(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{}, nil).Once()
+
+	now := time.Now()
+	r := &readinessChecker{client: mockClient, now: func() time.Time { return now }}
+	require.Error(t, r.check(t.Context()))
+	require.Error(t, r.check(t.Context()))
+
+	now = now.Add(readinessTTL)
+	require.NoError(t, r.check(t.Context()))
+}
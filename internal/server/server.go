@@ -2,12 +2,33 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
+	"net"
+	"net/http"
 	"os"
+	"runtime/debug"
+	"slices"
+	"time"
 
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/auth"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/orderwatch"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/luno/luno-mcp/internal/prompts"
 	"github.com/luno/luno-mcp/internal/resources"
+	"github.com/luno/luno-mcp/internal/schedule"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/internal/toolmw"
 	"github.com/luno/luno-mcp/internal/tools"
+	"github.com/luno/luno-mcp/internal/tracing"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
 	mcpserver "github.com/mark3labs/mcp-go/server"
 )
 
@@ -17,7 +38,23 @@ func NewMCPServer(name, version string, cfg *config.Config, hooks ...*mcpserver.
 	options := []mcpserver.ServerOption{
 		mcpserver.WithResourceCapabilities(true, true),
 		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithPromptCapabilities(true),
 		mcpserver.WithLogging(),
+		mcpserver.WithCompletions(),
+		mcpserver.WithPromptCompletionProvider(completionProvider{cfg: cfg}),
+		mcpserver.WithResourceCompletionProvider(completionProvider{cfg: cfg}),
+	}
+
+	// Keep per-client state for each session, dropping it when the client disconnects
+	if cfg.Sessions != nil {
+		sessionHooks := &mcpserver.Hooks{}
+		sessionHooks.AddOnRegisterSession(func(ctx context.Context, session mcpserver.ClientSession) {
+			cfg.Sessions.Get(session.SessionID())
+		})
+		sessionHooks.AddOnUnregisterSession(func(ctx context.Context, session mcpserver.ClientSession) {
+			cfg.Sessions.End(session.SessionID())
+		})
+		hooks = append(hooks, sessionHooks)
 	}
 
 	// Add hooks if provided
@@ -35,62 +72,495 @@ func NewMCPServer(name, version string, cfg *config.Config, hooks ...*mcpserver.
 	// Register resources
 	registerResources(server, cfg)
 
-	// Register tools
-	registerTools(server, cfg)
+	// Register tools, then describe the instance they make up
+	ReloadTools(server, cfg, name, version)
+
+	// Register prompts
+	registerPrompts(server, cfg)
 
 	return server
 }
 
+// ReloadTools registers the tools the current settings allow, in place of
+// any registered before, and updates the server info to match. Clients are
+// told the tool list changed, so tool filters can change without dropping
+// sessions.
+func ReloadTools(server *mcpserver.MCPServer, cfg *config.Config, name, version string) {
+	statuses := registerTools(server, cfg, name, version)
+	infoResource := resources.NewServerInfoResource()
+	server.AddResource(infoResource, recoverResource(cfg, infoResource.URI,
+		resources.HandleServerInfoResource(tools.NewServerInfo(cfg, name, version, statuses))))
+}
+
 // registerResources registers all resources with the MCP server
 func registerResources(server *mcpserver.MCPServer, cfg *config.Config) {
 	// Add balance resources
 	walletResource := resources.NewWalletResource()
-	server.AddResource(walletResource, resources.HandleWalletResource(cfg))
+	server.AddResource(walletResource, recoverResource(cfg, walletResource.URI, resources.HandleWalletResource(cfg)))
+	walletTemplate := resources.NewWalletTemplate()
+	server.AddResourceTemplate(walletTemplate, recoverResource(cfg, walletTemplate.URITemplate.Raw(), resources.HandleWalletTemplate(cfg)))
 
 	// Add transactions resource
 	transactionsResource := resources.NewTransactionsResource()
-	server.AddResource(transactionsResource, resources.HandleTransactionsResource(cfg))
+	server.AddResource(transactionsResource, recoverResource(cfg, transactionsResource.URI, resources.HandleTransactionsResource(cfg)))
+
+	// Add open orders resource
+	openOrdersResource := resources.NewOpenOrdersResource()
+	server.AddResource(openOrdersResource, recoverResource(cfg, openOrdersResource.URI, resources.HandleOpenOrdersResource(cfg)))
+
+	// Add configuration resource
+	configResource := resources.NewConfigResource()
+	server.AddResource(configResource, recoverResource(cfg, configResource.URI, resources.HandleConfigResource(cfg)))
 
 	// Add account resource template
 	accountTemplate := resources.NewAccountTemplate()
-	server.AddResourceTemplate(accountTemplate, resources.HandleAccountTemplate(cfg))
+	server.AddResourceTemplate(accountTemplate, recoverResource(cfg, accountTemplate.URITemplate.Raw(), resources.HandleAccountTemplate(cfg)))
+
+	// Add market data templates
+	server.AddResourceTemplate(resources.NewMarketTickerTemplate(),
+		recoverResource(cfg, resources.MarketTickerTemplateURI, resources.HandleMarketTickerTemplate(cfg)))
+	server.AddResourceTemplate(resources.NewMarketOrderBookTemplate(),
+		recoverResource(cfg, resources.MarketOrderBookTemplateURI, resources.HandleMarketOrderBookTemplate(cfg)))
+
+	// Add export template, for results too large to return from a tool
+	exportTemplate := resources.NewExportTemplate()
+	server.AddResourceTemplate(exportTemplate, recoverResource(cfg, exportTemplate.URITemplate.Raw(), resources.HandleExportTemplate(cfg)))
+
+	// Add live order book template, notifying clients as streamed books change
+	// and warning them when a stream drops and its book goes stale
+	if cfg.Streams != nil {
+		liveOrderBookTemplate := resources.NewLiveOrderBookTemplate()
+		server.AddResourceTemplate(liveOrderBookTemplate, recoverResource(cfg, liveOrderBookTemplate.URITemplate.Raw(), resources.HandleLiveOrderBookTemplate(cfg)))
+		cfg.Streams.SetNotifier(func(pair string) {
+			server.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{
+				"uri": resources.LiveOrderBookURI(pair),
+			})
+		})
+		cfg.Streams.SetDisconnectNotifier(func(pair string) {
+			slog.Warn("Live order book stream disconnected", slog.String("pair", pair))
+			method, params := logMessage(mcp.LoggingLevelWarning, "live-order-books", map[string]any{
+				"message": fmt.Sprintf("The %s order book stream disconnected. The book is stale and misses updates until it reconnects.", pair),
+				"pair":    pair,
+				"uri":     resources.LiveOrderBookURI(pair),
+			})
+			server.SendNotificationToAllClients(method, params)
+		})
+	}
+}
+
+// registerPrompts registers all prompts with the MCP server
+func registerPrompts(server *mcpserver.MCPServer, cfg *config.Config) {
+	addPrompt(server, prompts.NewPortfolioReviewPrompt(), prompts.HandlePortfolioReviewPrompt())
+	addPrompt(server, prompts.NewMarketOverviewPrompt(), prompts.HandleMarketOverviewPrompt())
+
+	// The order prompt is only useful when the order tools are registered
+	if cfg.Allows(config.PermissionTrade) && !cfg.WritesRefused {
+		addPrompt(server, prompts.NewPlaceLimitOrderSafelyPrompt(), prompts.HandlePlaceLimitOrderSafelyPrompt())
+	}
+}
+
+// addPrompt registers a prompt whose handler can't take down the server by panicking
+func addPrompt(server *mcpserver.MCPServer, prompt mcp.Prompt, handler mcpserver.PromptHandlerFunc) {
+	server.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return toolmw.Recovered(prompt.Name, func() (*mcp.GetPromptResult, error) {
+			return handler(ctx, request)
+		})
+	})
+}
+
+// recoverResource returns a resource handler that reports a panic as an
+// error instead of taking down the server. The handler reads with the Luno
+// client of the session, like tools do, so a session using its own
+// credentials sees its own account. It suits both resources and resource
+// templates.
+func recoverResource(cfg *config.Config, uri string, handler func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)) func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if cfg.Sessions != nil {
+			var err error
+			if ctx, err = toolmw.SessionClient(ctx, cfg.Sessions); err != nil {
+				return nil, err
+			}
+		}
+		return toolmw.Recovered(uri, func() ([]mcp.ResourceContents, error) {
+			return handler(ctx, request)
+		})
+	}
+}
+
+// logPanic logs a panic in a background watcher, which then stops on its own
+// rather than taking down the server. It must be deferred directly.
+func logPanic(name string) {
+	if p := recover(); p != nil {
+		slog.Error("Background watcher stopped after a panic",
+			slog.String("watcher", name),
+			slog.Any("panic", p),
+			slog.String("stack", string(debug.Stack())))
+	}
+}
+
+// toolEntry pairs a tool definition with its handler and the permission it needs
+type toolEntry struct {
+	tool       mcp.Tool
+	handler    mcpserver.ToolHandlerFunc
+	permission config.Permission
 }
 
-// registerTools registers all tools with the MCP server
-func registerTools(server *mcpserver.MCPServer, cfg *config.Config) {
-	// Add balance tools
-	balancesTool := tools.NewGetBalancesTool()
-	server.AddTool(balancesTool, tools.HandleGetBalances(cfg))
+// knownTools returns every tool this server knows about, in registration order
+func knownTools(cfg *config.Config) []toolEntry {
+	return []toolEntry{
+		// Balance tools
+		{tools.NewGetBalancesTool(), tools.HandleGetBalances(cfg), config.PermissionRead},
 
-	// Add market tools
-	tickerTool := tools.NewGetTickerTool()
-	server.AddTool(tickerTool, tools.HandleGetTicker(cfg))
+		// Market tools
+		{tools.NewGetTickerTool(), tools.HandleGetTicker(cfg), config.PermissionRead},
+		{tools.NewGetAllTickersTool(), tools.HandleGetAllTickers(cfg), config.PermissionRead},
+		{tools.NewCompareMarketsTool(), tools.HandleCompareMarkets(cfg), config.PermissionRead},
+		{tools.NewGetOrderBookTool(), tools.HandleGetOrderBook(cfg), config.PermissionRead},
+		{tools.NewAnalyzeOrderBookTool(), tools.HandleAnalyzeOrderBook(cfg), config.PermissionRead},
+		{tools.NewEstimateOrderCostTool(), tools.HandleEstimateOrderCost(cfg), config.PermissionRead},
+		{tools.NewListMarketsTool(), tools.HandleListMarkets(cfg), config.PermissionRead},
+		{tools.NewListAssetsTool(), tools.HandleListAssets(cfg), config.PermissionRead},
+		{tools.NewGetAssetCapabilitiesTool(), tools.HandleGetAssetCapabilities(cfg), config.PermissionRead},
+		{tools.NewValidateAddressTool(), tools.HandleValidateAddress(cfg), config.PermissionRead},
+		{tools.NewConvertAmountTool(), tools.HandleConvertAmount(cfg), config.PermissionRead},
+		{tools.NewGetBriefingTool(), tools.HandleGetBriefing(cfg), config.PermissionRead},
+		{tools.NewGetHistoricalPriceTool(), tools.HandleGetHistoricalPrice(cfg), config.PermissionRead},
 
-	orderBookTool := tools.NewGetOrderBookTool()
-	server.AddTool(orderBookTool, tools.HandleGetOrderBook(cfg))
+		// Trading tools
+		{tools.NewCreateOrderTool(), tools.HandleCreateOrder(cfg), config.PermissionTrade},
+		{tools.NewCancelOrderTool(), tools.HandleCancelOrder(cfg), config.PermissionTrade},
+		{tools.NewReplaceOrderTool(), tools.HandleReplaceOrder(cfg), config.PermissionTrade},
+		{tools.NewListOrdersTool(), tools.HandleListOrders(cfg), config.PermissionRead},
+		{tools.NewGetOrderTool(), tools.HandleGetOrder(cfg), config.PermissionRead},
+		{tools.NewWatchOrderTool(), tools.HandleWatchOrder(cfg), config.PermissionRead},
+		{tools.NewUnwatchOrderTool(), tools.HandleUnwatchOrder(cfg), config.PermissionRead},
+		{tools.NewPlaceOrderSetTool(), tools.HandlePlaceOrderSet(cfg), config.PermissionTrade},
+		{tools.NewCreateAccountTool(), tools.HandleCreateAccount(cfg), config.PermissionTrade},
+		{tools.NewMoveFundsTool(), tools.HandleMoveFunds(cfg), config.PermissionTrade},
 
-	// Add trading tools
-	createOrderTool := tools.NewCreateOrderTool()
-	server.AddTool(createOrderTool, tools.HandleCreateOrder(cfg))
+		// Transaction tools
+		{tools.NewListTransactionsTool(), tools.HandleListTransactions(cfg), config.PermissionRead},
+		{tools.NewGetTransactionTool(), tools.HandleGetTransaction(cfg), config.PermissionRead},
+		{tools.NewListPendingTransactionsTool(), tools.HandleListPendingTransactions(cfg), config.PermissionRead},
+		{tools.NewGenerateStatementTool(), tools.HandleGenerateStatement(cfg), config.PermissionRead},
 
-	cancelOrderTool := tools.NewCancelOrderTool()
-	server.AddTool(cancelOrderTool, tools.HandleCancelOrder(cfg))
+		// Trades tools
+		{tools.NewListTradesTool(), tools.HandleListTrades(cfg), config.PermissionRead},
+		{tools.NewListUserTradesTool(), tools.HandleListUserTrades(cfg), config.PermissionRead},
+		{tools.NewCalculatePnLTool(), tools.HandleCalculatePnL(cfg), config.PermissionRead},
 
-	listOrdersTool := tools.NewListOrdersTool()
-	server.AddTool(listOrdersTool, tools.HandleListOrders(cfg))
+		// Scheduled order tools
+		{tools.NewScheduleRecurringBuyTool(), tools.HandleScheduleRecurringBuy(cfg), config.PermissionTrade},
+		{tools.NewListSchedulesTool(), tools.HandleListSchedules(cfg), config.PermissionRead},
+		{tools.NewCancelScheduleTool(), tools.HandleCancelSchedule(cfg), config.PermissionTrade},
 
-	// Add transaction tools
-	listTransactionsTool := tools.NewListTransactionsTool()
-	server.AddTool(listTransactionsTool, tools.HandleListTransactions(cfg))
+		// Price alert tools
+		{tools.NewCreatePriceAlertTool(), tools.HandleCreatePriceAlert(cfg), config.PermissionRead},
+		{tools.NewListPriceAlertsTool(), tools.HandleListPriceAlerts(cfg), config.PermissionRead},
+		{tools.NewDeletePriceAlertTool(), tools.HandleDeletePriceAlert(cfg), config.PermissionRead},
 
-	getTransactionTool := tools.NewGetTransactionTool()
-	server.AddTool(getTransactionTool, tools.HandleGetTransaction(cfg))
+		// Restore tools
+		{tools.NewRestoreTool(), tools.HandleRestore(cfg), config.PermissionRead},
 
-	// Add trades tools
-	listTradesTool := tools.NewListTradesTool()
-	server.AddTool(listTradesTool, tools.HandleListTrades(cfg))
+		// Notification tools
+		{tools.NewListUndeliveredEventsTool(), tools.HandleListUndeliveredEvents(cfg), config.PermissionRead},
+
+		// Note tools
+		{tools.NewSetNoteTool(), tools.HandleSetNote(cfg), config.PermissionRead},
+
+		// Profile tools
+		{tools.NewListProfilesTool(), tools.HandleListProfiles(cfg), config.PermissionRead},
+
+		// Support tools
+		{tools.NewCreateSupportBundleTool(), tools.HandleCreateSupportBundle(cfg), config.PermissionRead},
+	}
 }
 
+// toolExclusionReason returns why a tool should not be registered,
+// or an empty string if it should be
+func toolExclusionReason(cfg *config.Config, entry toolEntry) string {
+	if !cfg.Allows(entry.permission) {
+		return fmt.Sprintf("requires the %q permission, add it to %s to enable", entry.permission, config.EnvLunoPermissions)
+	}
+	if reason := cfg.WritesRefusedReason(entry.permission); reason != "" {
+		return reason
+	}
+	return cfg.ToolFilterReason(entry.tool.Name)
+}
+
+// registerTools registers all tools with the MCP server and returns the status
+// of every known tool. The name and version are reported by server_info.
+func registerTools(server *mcpserver.MCPServer, cfg *config.Config, name, version string) []tools.ToolStatus {
+	var (
+		statuses    []tools.ToolStatus
+		serverTools []mcpserver.ServerTool
+	)
+	known := knownTools(cfg)
+	settings := cfg.Settings()
+	warnUnknownTools(known, config.EnvLunoToolsEnabled, settings.EnabledTools)
+	warnUnknownTools(known, config.EnvLunoToolsDisabled, settings.DisabledTools)
+	warnUnknownTools(known, config.EnvLunoToolTimeouts, slices.Collect(maps.Keys(cfg.Timeouts.PerTool)))
+	for _, entry := range known {
+		if reason := toolExclusionReason(cfg, entry); reason != "" {
+			slog.Info("Skipping tool", slog.String("tool", entry.tool.Name), slog.String("reason", reason))
+			statuses = append(statuses, tools.ToolStatus{Name: entry.tool.Name, Reason: reason})
+			continue
+		}
+		if len(cfg.Profiles) > 1 && entry.tool.Name != tools.ListProfilesToolID {
+			addProfileArgument(&entry.tool, cfg.ProfileNames())
+		}
+		handler := toolmw.Chain(entry.handler, toolMiddleware(cfg, entry)...)
+		serverTools = append(serverTools, mcpserver.ServerTool{Tool: entry.tool, Handler: handler})
+		statuses = append(statuses, tools.ToolStatus{Name: entry.tool.Name, Registered: true})
+	}
+
+	// The info and status tools are always available so users can see what
+	// this server may do and why other tools are missing
+	infoTool := tools.NewServerInfoTool()
+	statusTool := tools.NewListToolsStatusTool()
+	statuses = append(statuses,
+		tools.ToolStatus{Name: infoTool.Name, Registered: true},
+		tools.ToolStatus{Name: statusTool.Name, Registered: true})
+	info := tools.NewServerInfo(cfg, name, version, statuses)
+	serverTools = append(serverTools,
+		mcpserver.ServerTool{Tool: infoTool, Handler: toolmw.Chain(tools.HandleServerInfo(info), toolmw.Recovery(infoTool.Name))},
+		mcpserver.ServerTool{Tool: statusTool, Handler: toolmw.Chain(tools.HandleListToolsStatus(statuses), toolmw.Recovery(statusTool.Name))})
+
+	// Setting every tool at once replaces the ones a previous call registered
+	server.SetTools(serverTools...)
+	return statuses
+}
+
+// toolMiddleware returns the middleware around a tool's handler, outermost
+// first. The timeout runs the rest of the chain in its own goroutine, so
+// recovery has to come after it to catch panics in the handler. So does the
+// audit log, since a call that times out keeps running and may still change
+// the account: its entry records what the call did once it finishes, rather
+// than the timeout the client was given. Error codes are added near the
+// outside so that errors from every layer get one, and so is the environment
+// label. Tracing sits just outside error codes, so that spans carry them.
+func toolMiddleware(cfg *config.Config, entry toolEntry) []toolmw.Middleware {
+	name := entry.tool.Name
+	middleware := []toolmw.Middleware{toolmw.Logging(name)}
+	if cfg.Tracing {
+		middleware = append(middleware, toolmw.Tracing(tracing.Tracer(), name))
+	}
+	middleware = append(middleware, toolmw.ErrorCode())
+	if cfg.Environment != "" {
+		middleware = append(middleware, toolmw.Environment(string(cfg.Environment)))
+	}
+	if cfg.Metrics != nil {
+		middleware = append(middleware, cfg.Metrics.Middleware(name))
+	}
+	if cfg.Sessions != nil {
+		middleware = append(middleware, toolmw.Session(cfg.Sessions))
+	}
+	// Explain auth failures caused by a wrong local clock
+	if cfg.ClockSkew != nil {
+		middleware = append(middleware, toolmw.ClockSkew(cfg.ClockSkew))
+	}
+	if timeout := cfg.Timeouts.For(name); timeout > 0 {
+		hint := fmt.Sprintf("raise the timeout with %s", config.EnvLunoToolTimeouts)
+		middleware = append(middleware, toolmw.Timeout(name, timeout, entry.permission != config.PermissionRead, hint))
+	}
+	if cfg.Audit != nil && entry.permission != config.PermissionRead {
+		middleware = append(middleware, toolmw.Audit(cfg.Audit, name))
+	}
+	middleware = append(middleware, toolmw.Permission(func() error {
+		if reason := toolExclusionReason(cfg, entry); reason != "" {
+			return fmt.Errorf("%s is not available: %s", name, reason)
+		}
+		return nil
+	}))
+	middleware = append(middleware, toolmw.Recovery(name))
+	if len(cfg.Profiles) > 1 && name != tools.ListProfilesToolID {
+		middleware = append(middleware, toolmw.Profile(cfg.ProfileNames()))
+	}
+	return middleware
+}
+
+// warnUnknownTools logs names in a tool list that don't match any known tool,
+// which are most likely typos
+func warnUnknownTools(known []toolEntry, env string, names []string) {
+	for _, name := range names {
+		if !slices.ContainsFunc(known, func(e toolEntry) bool { return e.tool.Name == name }) {
+			slog.Warn("Unknown tool name in "+env, slog.String("tool", name))
+		}
+	}
+}
+
+// addProfileArgument adds the optional profile argument to a tool's schema
+func addProfileArgument(tool *mcp.Tool, names []string) {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[toolmw.ProfileParam] = map[string]any{
+		"type":        "string",
+		"enum":        names,
+		"description": fmt.Sprintf("Credential profile to use (default: %s). See list_profiles.", config.DefaultProfile),
+	}
+}
+
+// WatchAlerts checks price alerts until ctx is cancelled, sending a log
+// message notification to the session of each alert that fires. It returns a
+// channel that is closed when the watcher has stopped.
+func WatchAlerts(ctx context.Context, s *mcpserver.MCPServer, cfg *config.Config, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if cfg.Alerts == nil {
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		defer logPanic("price alerts")
+		cfg.Alerts.Watch(ctx, sessionClient(cfg), interval, func(t alerts.Trigger) {
+			slog.Debug("Price alert fired", slog.String("id", t.ID), slog.String("pair", t.Pair))
+			notify(s, cfg, t.Session, "price-alerts", t.Message(), "alert", t)
+		})
+	}()
+	return done
+}
+
+// WatchOrders polls watched orders until ctx is cancelled, sending a log
+// message notification to the watching session when an order is filled or
+// cancelled. It returns a channel that is closed when the watcher has stopped.
+func WatchOrders(ctx context.Context, s *mcpserver.MCPServer, cfg *config.Config, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if cfg.OrderWatch == nil {
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		defer logPanic("order watch")
+		cfg.OrderWatch.Run(ctx, sessionClient(cfg), interval, func(e orderwatch.Event) {
+			slog.Debug("Watched order changed", slog.String("order_id", e.OrderID), slog.String("event", string(e.Kind)))
+			notify(s, cfg, e.Session, "order-watch", e.Message(), "order", e)
+		})
+	}()
+	return done
+}
+
+// WatchSchedules places the orders of recurring buys as they come due until
+// ctx is cancelled, sending a log message notification to every client with
+// the outcome of each run. It returns a channel that is closed when the
+// scheduler has stopped.
+func WatchSchedules(ctx context.Context, s *mcpserver.MCPServer, cfg *config.Config, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if cfg.Schedules == nil {
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		defer logPanic("scheduled orders")
+		cfg.Schedules.Run(ctx, interval, tools.PlaceScheduledBuy(cfg), func(e schedule.Event) {
+			slog.Info("Scheduled buy ran", slog.String("id", e.Schedule.ID), slog.String("order_id", e.Run.OrderID), slog.String("error", e.Run.Error))
+			// Schedules outlive sessions, so every connected client is told
+			notify(s, cfg, "", "scheduled-orders", e.Message(), "schedule", e)
+		})
+	}()
+	return done
+}
+
+// WatchOutbox sends the notifications that couldn't be delivered again every
+// interval until ctx is cancelled. It returns a channel that is closed when
+// it has stopped.
+func WatchOutbox(ctx context.Context, s *mcpserver.MCPServer, cfg *config.Config, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if cfg.Outbox == nil {
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		defer logPanic("notification outbox")
+		cfg.Outbox.Run(ctx, interval, sendEvent(s, cfg.Sessions))
+	}()
+	return done
+}
+
+// errNoClients is the delivery error of an event for every client while no
+// client is connected
+var errNoClients = errors.New("no client is connected")
+
+// sessionClient returns a function that gives the Luno client of a session:
+// its own when it sent credentials, otherwise the server's
+func sessionClient(cfg *config.Config) func(string) sdk.LunoClient {
+	return func(id string) sdk.LunoClient {
+		if cfg.Sessions != nil {
+			if client := cfg.Sessions.Client(id); client != nil {
+				return client
+			}
+		}
+		return cfg.LunoClient
+	}
+}
+
+// notify sends a log message notification to a session, or to every client
+// when session is "", with details attached under key, redacting both. The
+// notification goes through the outbox, so it is sent again until it is
+// delivered.
+func notify(s *mcpserver.MCPServer, cfg *config.Config, session, logger, message, key string, details any) {
+	message, details = cfg.Redactor.String(message), cfg.Redactor.Value(details)
+	send := sendEvent(s, cfg.Sessions)
+	if cfg.Outbox == nil {
+		raw, err := json.Marshal(details)
+		if err == nil {
+			err = send(outbox.Event{Session: session, Logger: logger, Message: message, Key: key, Details: raw})
+		}
+		if err != nil {
+			slog.Warn("Failed to send notification", slog.String("logger", logger), slog.Any("error", err))
+		}
+		return
+	}
+	if _, err := cfg.Outbox.Add(session, logger, message, key, details); err != nil {
+		slog.Warn("Failed to queue notification", slog.String("logger", logger), slog.Any("error", err))
+		return
+	}
+	cfg.Outbox.Deliver(send)
+}
+
+// sendEvent returns a function that sends an event as a log message
+// notification to its session, or to every connected client
+func sendEvent(s *mcpserver.MCPServer, sessions *session.Manager) func(outbox.Event) error {
+	return func(e outbox.Event) error {
+		method, params := logNotification(e)
+		if e.Session != "" {
+			return s.SendNotificationToSpecificClient(e.Session, method, params)
+		}
+		if sessions != nil && sessions.Count() == 0 {
+			return errNoClients
+		}
+		s.SendNotificationToAllClients(method, params)
+		return nil
+	}
+}
+
+// logNotification returns the method and params of a log message
+// notification of an event, with its details attached under its key and its
+// ID under event_id
+func logNotification(e outbox.Event) (string, map[string]any) {
+	data := map[string]any{"message": e.Message, e.Key: e.Details}
+	if e.ID != "" {
+		data["event_id"] = e.ID
+	}
+	return logMessage(mcp.LoggingLevelNotice, e.Logger, data)
+}
+
+// logMessage returns the method and params of a log message notification
+func logMessage(level mcp.LoggingLevel, logger string, data map[string]any) (string, map[string]any) {
+	notification := mcp.NewLoggingMessageNotification(level, logger, data)
+	return notification.Method, map[string]any{
+		"level":  string(notification.Params.Level),
+		"logger": logger,
+		"data":   data,
+	}
+}
+
+// DefaultShutdownTimeout is how long the SSE server waits for in-flight requests when shutting down
+const DefaultShutdownTimeout = 10 * time.Second
+
 // ServeStdio starts the server using the Stdio transport
 func ServeStdio(ctx context.Context, s *mcpserver.MCPServer) error {
 	stdioServer := mcpserver.NewStdioServer(s)
@@ -106,11 +576,79 @@ func ServeStdio(ctx context.Context, s *mcpserver.MCPServer) error {
 	return stdioServer.Listen(ctx, os.Stdin, os.Stdout)
 }
 
-// ServeSSE starts the server using the SSE transport
-func ServeSSE(ctx context.Context, s *mcpserver.MCPServer, addr string) error {
-	sseServer := mcpserver.NewSSEServer(s)
+// ServeSSE starts the server using the SSE transport, with health and
+// readiness endpoints at /healthz and /readyz. It serves HTTPS when tlsConfig
+// is set. Browsers may only call the MCP endpoints from the origins in
+// cfg.CORS, and when cfg.Auth is set they need a bearer token. The health
+// endpoints stay open. It runs until ctx is cancelled, then stops accepting
+// connections and waits up to shutdownTimeout for in-flight requests to
+// finish.
+func ServeSSE(ctx context.Context, s *mcpserver.MCPServer, cfg *config.Config, addr string, tlsConfig *tls.Config, shutdownTimeout time.Duration) error {
+	httpServer := &http.Server{Addr: addr, TLSConfig: tlsConfig}
+	// Clients may send their own Luno credentials with every request
+	sseServer := mcpserver.NewSSEServer(s, mcpserver.WithHTTPServer(httpServer),
+		mcpserver.WithSSEContextFunc(session.CredentialsContext))
+	var handler http.Handler = sseServer
+	if cfg.Auth != nil {
+		handler = auth.Middleware(cfg.Auth, sseServer)
+		slog.Info("Bearer token authorization enabled", slog.String("mode", cfg.Auth.Mode()))
+	} else if !isLoopback(addr) {
+		slog.Warn("SSE server is listening beyond localhost without authorization, set "+config.EnvMCPAuthMode+" to require bearer tokens",
+			slog.String("address", addr))
+	}
+	if tlsConfig == nil && !isLoopback(addr) {
+		slog.Warn("SSE server is listening beyond localhost over plain HTTP, use -tls-cert and -tls-key to serve HTTPS",
+			slog.String("address", addr))
+	}
+	// CORS goes outside authorization, since preflight requests carry no token
+	handler = withCORS(cfg.CORS, handler)
+	if cfg.Tracing {
+		handler = tracing.Handler(handler)
+	}
+	httpServer.Handler = withHealthChecks(cfg.LunoClient, handler)
 
 	// Start the server
-	slog.Info("SSE server listening on " + addr)
-	return sseServer.Start(addr)
+	errCh := make(chan error, 1)
+	if tlsConfig != nil {
+		slog.Info("SSE server listening with TLS on " + addr)
+		go func() {
+			// The certificate is already in the TLS config
+			errCh <- httpServer.ListenAndServeTLS("", "")
+		}()
+	} else {
+		slog.Info("SSE server listening on " + addr)
+		go func() {
+			errCh <- sseServer.Start(addr)
+		}()
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down SSE server", slog.Duration("drain_timeout", shutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := sseServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down SSE server: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// isLoopback reports whether addr only accepts connections from this host
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
@@ -2,12 +2,28 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/audit"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/orderwatch"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/luno/luno-mcp/internal/resources"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/internal/toolmw"
+	"github.com/luno/luno-mcp/internal/tools"
+	"github.com/luno/luno-mcp/sdk"
 	"github.com/mark3labs/mcp-go/mcp" // Added import
 	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -96,12 +112,16 @@ func TestNewMCPServer(t *testing.T) {
 
 			// These should not panic
 			registerResources(server, cfg)
-			registerTools(server, cfg)
+			registerTools(server, cfg, tc.srvName, tc.version)
 		})
 	}
 }
 
 func TestServeSSEIntegration(t *testing.T) {
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { used.Close() })
+
 	tests := []struct {
 		name     string
 		address  string
@@ -119,8 +139,8 @@ func TestServeSSEIntegration(t *testing.T) {
 		},
 		{
 			name:     "bind to used port",
-			address:  "localhost:80", // Typically requires root privileges
-			errorMsg: "bind: permission denied",
+			address:  used.Addr().String(),
+			errorMsg: "address already in use",
 		},
 	}
 
@@ -134,7 +154,7 @@ func TestServeSSEIntegration(t *testing.T) {
 			// Set up context with or without timeout
 			ctx := context.Background()
 			// Test ServeSSE functionality
-			err := ServeSSE(ctx, server, tc.address)
+			err := ServeSSE(ctx, server, cfg, tc.address, nil, DefaultShutdownTimeout)
 
 			if tc.errorMsg != "" {
 				require.Error(t, err)
@@ -145,3 +165,360 @@ func TestServeSSEIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestServeSSEShutdown(t *testing.T) {
+	cfg := &config.Config{LunoClient: luno.NewClient()}
+	server := NewMCPServer("test-sse-server", "1.0.0", cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ServeSSE(ctx, server, cfg, "127.0.0.1:0", nil, time.Second)
+	}()
+
+	// Give the listener a moment to start, then stop it like a signal would
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeSSE did not return after the context was cancelled")
+	}
+}
+
+func TestRegisterToolsStatuses(t *testing.T) {
+	tests := []struct {
+		name          string
+		permissions   []config.Permission
+		enabledTools  []string
+		disabledTools []string
+		writesRefused bool
+		excluded      []string
+		reason        string
+	}{
+		{
+			name: "default permissions register everything",
+		},
+		{
+			name:        "read only excludes trading tools",
+			permissions: []config.Permission{config.PermissionRead},
+			excluded: []string{
+				tools.CreateOrderToolID, tools.CancelOrderToolID, tools.ReplaceOrderToolID, tools.PlaceOrderSetToolID, tools.CreateAccountToolID, tools.MoveFundsToolID,
+				tools.ScheduleRecurringBuyToolID, tools.CancelScheduleToolID,
+			},
+		},
+		{
+			name:        "trade only excludes read tools",
+			permissions: []config.Permission{config.PermissionTrade},
+			excluded: []string{
+				tools.GetBalancesToolID, tools.GetTickerToolID, tools.GetAllTickersToolID, tools.CompareMarketsToolID, tools.GetOrderBookToolID, tools.AnalyzeOrderBookToolID,
+				tools.EstimateOrderCostToolID, tools.ListMarketsToolID,
+				tools.ListAssetsToolID, tools.GetAssetCapabilitiesToolID, tools.ValidateAddressToolID, tools.ConvertAmountToolID, tools.GetBriefingToolID,
+				tools.GetHistoricalPriceToolID,
+				tools.ListOrdersToolID, tools.GetOrderToolID, tools.WatchOrderToolID, tools.UnwatchOrderToolID,
+				tools.ListTransactionsToolID, tools.GetTransactionToolID,
+				tools.ListPendingTransactionsToolID, tools.GenerateStatementToolID, tools.ListTradesToolID, tools.ListUserTradesToolID,
+				tools.CalculatePnLToolID, tools.ListSchedulesToolID,
+				tools.CreatePriceAlertToolID, tools.ListPriceAlertsToolID, tools.DeletePriceAlertToolID, tools.RestoreToolID, tools.ListUndeliveredEventsToolID,
+				tools.SetNoteToolID, tools.ListProfilesToolID, tools.CreateSupportBundleToolID,
+			},
+		},
+		{
+			name:         "allow list excludes unlisted tools",
+			enabledTools: []string{tools.GetTickerToolID, tools.GetOrderBookToolID, "get_tickr"},
+			excluded: []string{
+				tools.GetBalancesToolID, tools.GetAllTickersToolID, tools.CompareMarketsToolID, tools.AnalyzeOrderBookToolID,
+				tools.EstimateOrderCostToolID, tools.ListMarketsToolID,
+				tools.ListAssetsToolID, tools.GetAssetCapabilitiesToolID, tools.ValidateAddressToolID, tools.ConvertAmountToolID, tools.GetBriefingToolID,
+				tools.GetHistoricalPriceToolID,
+				tools.CreateOrderToolID, tools.CancelOrderToolID, tools.ReplaceOrderToolID,
+				tools.ListOrdersToolID, tools.GetOrderToolID, tools.WatchOrderToolID, tools.UnwatchOrderToolID,
+				tools.PlaceOrderSetToolID, tools.CreateAccountToolID, tools.MoveFundsToolID,
+				tools.ListTransactionsToolID, tools.GetTransactionToolID,
+				tools.ListPendingTransactionsToolID, tools.GenerateStatementToolID, tools.ListTradesToolID, tools.ListUserTradesToolID,
+				tools.CalculatePnLToolID,
+				tools.ScheduleRecurringBuyToolID, tools.ListSchedulesToolID, tools.CancelScheduleToolID,
+				tools.CreatePriceAlertToolID, tools.ListPriceAlertsToolID, tools.DeletePriceAlertToolID, tools.RestoreToolID, tools.ListUndeliveredEventsToolID,
+				tools.SetNoteToolID, tools.ListProfilesToolID, tools.CreateSupportBundleToolID,
+			},
+			reason: config.EnvLunoToolsEnabled,
+		},
+		{
+			name:          "deny list excludes listed tools",
+			disabledTools: []string{tools.CreateOrderToolID, tools.PlaceOrderSetToolID},
+			excluded:      []string{tools.CreateOrderToolID, tools.PlaceOrderSetToolID},
+			reason:        config.EnvLunoToolsDisabled,
+		},
+		{
+			name:          "production without writes excludes trading tools",
+			writesRefused: true,
+			excluded: []string{
+				tools.CreateOrderToolID, tools.CancelOrderToolID, tools.ReplaceOrderToolID, tools.PlaceOrderSetToolID, tools.CreateAccountToolID, tools.MoveFundsToolID,
+				tools.ScheduleRecurringBuyToolID, tools.CancelScheduleToolID,
+			},
+			reason: config.EnvLunoAllowProductionWrites,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{
+				LunoClient:    luno.NewClient(),
+				Permissions:   tc.permissions,
+				WritesRefused: tc.writesRefused,
+			}
+			cfg.SetSettings(&config.Settings{EnabledTools: tc.enabledTools, DisabledTools: tc.disabledTools})
+			server := mcpserver.NewMCPServer(testServerName, testVersion1)
+			reason := tc.reason
+			if reason == "" {
+				reason = config.EnvLunoPermissions
+			}
+
+			statuses := registerTools(server, cfg, testServerName, testVersion1)
+
+			known := knownTools(cfg)
+			require.Len(t, statuses, len(known)+2)
+			var excluded []string
+			for i, entry := range known {
+				require.Equal(t, entry.tool.Name, statuses[i].Name)
+				if !statuses[i].Registered {
+					excluded = append(excluded, statuses[i].Name)
+					require.Contains(t, statuses[i].Reason, reason)
+				}
+			}
+			require.Equal(t, tc.excluded, excluded)
+
+			// The info and status tools are always registered
+			require.Equal(t, tools.ServerInfoToolID, statuses[len(statuses)-2].Name)
+			require.True(t, statuses[len(statuses)-2].Registered)
+			require.Equal(t, tools.ListToolsStatusToolID, statuses[len(statuses)-1].Name)
+			require.True(t, statuses[len(statuses)-1].Registered)
+		})
+	}
+}
+
+func TestRecoverResource(t *testing.T) {
+	handler := recoverResource(&config.Config{}, resources.WalletResourceURI, func(context.Context, mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		panic("nil map")
+	})
+
+	_, err := handler(context.Background(), mcp.ReadResourceRequest{})
+	require.ErrorContains(t, err, "luno://wallets panicked: nil map")
+}
+
+// testSession is a client session with a fixed ID
+type testSession string
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return string(s) }
+
+func TestResourceSessionCredentials(t *testing.T) {
+	own := sdk.NewMockLunoClient(t)
+	own.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{
+		Balance: []luno.AccountBalance{{AccountId: "1001", Asset: "XBT"}},
+	}, nil).Once()
+	cfg := &config.Config{
+		// The server's own client must not be used
+		LunoClient: sdk.NewProfileClient(config.DefaultProfile, map[string]sdk.LunoClient{config.DefaultProfile: sdk.NewMockLunoClient(t)}),
+		Sessions:   session.NewManager(0),
+	}
+	cfg.Sessions.SetClientFactory(func(session.Credentials) (sdk.LunoClient, error) { return own, nil })
+	server := NewMCPServer(testServerName, testVersion1, cfg)
+
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/read",
+		"params":  map[string]any{"uri": resources.WalletResourceURI},
+	})
+	require.NoError(t, err)
+	ctx := session.WithCredentials(server.WithContext(context.Background(), testSession("s1")), session.Credentials{KeyID: "key", Secret: "secret"})
+
+	response, ok := server.HandleMessage(ctx, message).(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a result, got %#v", response)
+	result, ok := response.Result.(mcp.ReadResourceResult)
+	require.True(t, ok)
+	require.Len(t, result.Contents, 1)
+	assert.Contains(t, result.Contents[0].(mcp.TextResourceContents).Text, `"account_id": "1001"`)
+	assert.Equal(t, own, cfg.Sessions.Client("s1"))
+}
+
+func TestSessionClient(t *testing.T) {
+	server, own := sdk.NewMockLunoClient(t), sdk.NewMockLunoClient(t)
+	cfg := &config.Config{LunoClient: server, Sessions: session.NewManager(0)}
+	cfg.Sessions.Get("s2").SetClient(own)
+
+	clientFor := sessionClient(cfg)
+	assert.Equal(t, server, clientFor("s1"))
+	assert.Equal(t, own, clientFor("s2"))
+	assert.Equal(t, server, sessionClient(&config.Config{LunoClient: server})("s2"))
+}
+
+func TestAddProfileArgument(t *testing.T) {
+	tool := tools.NewGetBalancesTool()
+	addProfileArgument(&tool, []string{config.DefaultProfile, "savings"})
+
+	prop, ok := tool.InputSchema.Properties[toolmw.ProfileParam].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, []string{config.DefaultProfile, "savings"}, prop["enum"])
+	require.NotContains(t, tool.InputSchema.Required, toolmw.ProfileParam)
+}
+
+func TestWatchersStop(t *testing.T) {
+	server := mcpserver.NewMCPServer(testServerName, testVersion1)
+
+	// Without alerts there is nothing to watch
+	select {
+	case <-WatchAlerts(context.Background(), server, &config.Config{}, time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watcher to stop straight away without alerts")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := &config.Config{
+		LunoClient: sdk.NewMockLunoClient(t),
+		Alerts:     alerts.NewRegistry(alerts.DefaultMaxPerSession),
+		OrderWatch: orderwatch.NewWatcher(orderwatch.DefaultMaxPerSession),
+	}
+	alertsDone := WatchAlerts(ctx, server, cfg, time.Millisecond)
+	ordersDone := WatchOrders(ctx, server, cfg, time.Millisecond)
+	cancel()
+	for _, done := range []<-chan struct{}{alertsDone, ordersDone} {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the watchers to stop when the context is cancelled")
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	server := mcpserver.NewMCPServer(testServerName, testVersion1)
+	cfg := &config.Config{Outbox: outbox.New(), Sessions: session.NewManager(0)}
+
+	// Nothing is connected, so both notifications wait in the outbox
+	notify(server, cfg, "s1", "price-alerts", "Price alert 1", "alert", map[string]any{"pair": "XBTZAR"})
+	notify(server, cfg, "", "scheduled-orders", "Scheduled buy 1", "schedule", nil)
+	undelivered := cfg.Outbox.Undelivered("s1")
+	require.Len(t, undelivered, 2)
+	require.Equal(t, mcpserver.ErrSessionNotFound.Error(), undelivered[0].LastError)
+	require.Equal(t, errNoClients.Error(), undelivered[1].LastError)
+
+	// Without an outbox a notification that can't be sent is dropped
+	notify(server, &config.Config{}, "s1", "price-alerts", "Price alert 2", "alert", nil)
+}
+
+func TestToolMiddlewareAuditsTimedOutCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { auditLog.Close() })
+	cfg := &config.Config{
+		Audit:    auditLog,
+		Timeouts: config.ToolTimeouts{Default: 10 * time.Millisecond},
+	}
+
+	// The order is placed after the client has been told the call timed out
+	placed := make(chan struct{})
+	entry := toolEntry{
+		tool: mcp.NewTool("slow_order"),
+		handler: func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			<-ctx.Done()
+			defer close(placed)
+			return mcp.NewToolResultText("Order placed"), nil
+		},
+		permission: config.PermissionTrade,
+	}
+	handler := toolmw.Chain(entry.handler, toolMiddleware(cfg, entry)...)
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, `"error": "timeout"`)
+
+	<-placed
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && len(data) > 0
+	}, time.Second, time.Millisecond)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var recorded audit.Entry
+	require.NoError(t, json.Unmarshal(data, &recorded))
+	assert.Equal(t, "slow_order", recorded.Tool)
+	assert.False(t, recorded.IsError)
+	assert.Equal(t, "Order placed", recorded.Result)
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"localhost:8080", true},
+		{"127.0.0.1:9000", true},
+		{"[::1]:8080", true},
+		{"0.0.0.0:8888", false},
+		{":8080", false},
+		{"mcp.example.com:443", false},
+		{"localhost", false},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, isLoopback(tc.addr), tc.addr)
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{
+		Balance: []luno.AccountBalance{{AccountId: "1001", Asset: "XBT"}, {AccountId: "2001", Asset: "ZAR"}},
+	}, nil)
+	server := NewMCPServer(testServerName, testVersion1, &config.Config{LunoClient: mockClient})
+
+	tests := []struct {
+		name           string
+		ref            map[string]string
+		argument       string
+		value          string
+		expectedValues []string
+	}{
+		{
+			name:           "account template id",
+			ref:            map[string]string{"type": "ref/resource", "uri": resources.AccountTemplateURI},
+			argument:       "id",
+			value:          "XBT",
+			expectedValues: []string{"1001"},
+		},
+		{
+			name:           "prompt currency",
+			ref:            map[string]string{"type": "ref/prompt", "name": "portfolio_review"},
+			argument:       "quote_currency",
+			value:          "eur",
+			expectedValues: []string{"EUR"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			message, err := json.Marshal(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"method":  "completion/complete",
+				"params": map[string]any{
+					"ref":      tc.ref,
+					"argument": map[string]string{"name": tc.argument, "value": tc.value},
+				},
+			})
+			require.NoError(t, err)
+
+			response, ok := server.HandleMessage(context.Background(), message).(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected a result, got %#v", response)
+			result, ok := response.Result.(mcp.CompleteResult)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedValues, result.Completion.Values)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// selfSignedValidity is how long a generated development certificate is valid
+const selfSignedValidity = 30 * 24 * time.Hour
+
+// TLSOptions configures HTTPS for the SSE transport
+type TLSOptions struct {
+	// CertFile and KeyFile are PEM encoded files of a certificate chain and
+	// its private key
+	CertFile string
+	KeyFile  string
+	// SelfSigned generates a certificate for local development when no
+	// files are given. Clients won't trust it without extra setup.
+	SelfSigned bool
+}
+
+// Config returns the TLS configuration for a server listening on addr, or
+// nil when TLS isn't configured
+func (o TLSOptions) Config(addr string) (*tls.Config, error) {
+	var cert tls.Certificate
+	var err error
+	switch {
+	case o.CertFile != "" || o.KeyFile != "":
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, errors.New("both a TLS certificate and key are required")
+		}
+		if o.SelfSigned {
+			return nil, errors.New("a self-signed certificate can't be used with a certificate file")
+		}
+		cert, err = tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading TLS certificate: %w", err)
+		}
+	case o.SelfSigned:
+		cert, err = selfSignedCertificate(certificateHosts(addr), time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("generating self-signed certificate: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// certificateHosts returns the names a development certificate for addr
+// covers: localhost and the listen host when it names one
+func certificateHosts(addr string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" || host == "localhost" {
+		return hosts
+	}
+	if ip := net.ParseIP(host); ip != nil && (ip.IsUnspecified() || ip.IsLoopback()) {
+		return hosts
+	}
+	return append(hosts, host)
+}
+
+// selfSignedCertificate creates a certificate for hosts signed by its own key
+func selfSignedCertificate(hosts []string, now time.Time) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"luno-mcp development"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSOptionsConfig(t *testing.T) {
+	// Write a certificate and key like an operator would provide
+	dir := t.TempDir()
+	cert, err := selfSignedCertificate([]string{"mcp.example.com"}, time.Now())
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	tests := []struct {
+		name          string
+		options       TLSOptions
+		expectNil     bool
+		expectedError string
+	}{
+		{name: "not configured", expectNil: true},
+		{name: "certificate files", options: TLSOptions{CertFile: certFile, KeyFile: keyFile}},
+		{name: "self-signed", options: TLSOptions{SelfSigned: true}},
+		{name: "missing key", options: TLSOptions{CertFile: certFile}, expectedError: "both a TLS certificate and key are required"},
+		{name: "files and self-signed", options: TLSOptions{CertFile: certFile, KeyFile: keyFile, SelfSigned: true}, expectedError: "can't be used with a certificate file"},
+		{name: "unreadable file", options: TLSOptions{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: keyFile}, expectedError: "loading TLS certificate"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := tc.options.Config("0.0.0.0:8443")
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectNil {
+				require.Nil(t, cfg)
+				return
+			}
+			require.Len(t, cfg.Certificates, 1)
+		})
+	}
+}
+
+func TestSelfSignedCertificate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cert, err := selfSignedCertificate(certificateHosts("10.0.0.5:8443"), now)
+	require.NoError(t, err)
+
+	leaf := cert.Leaf
+	require.Equal(t, []string{"localhost"}, leaf.DNSNames)
+	require.Len(t, leaf.IPAddresses, 3)
+	require.True(t, leaf.IPAddresses[2].Equal(net.ParseIP("10.0.0.5")))
+	require.NoError(t, leaf.VerifyHostname("localhost"))
+	require.True(t, leaf.NotAfter.Equal(now.Add(selfSignedValidity)))
+
+	require.Equal(t, []string{"localhost", "127.0.0.1", "::1"}, certificateHosts("0.0.0.0:8443"))
+	require.Equal(t, []string{"localhost", "127.0.0.1", "::1", "mcp.example.com"}, certificateHosts("mcp.example.com:443"))
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/resources"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// accountResourceURIs are the resources that change when a balance changes.
+// Every transaction moves a balance, and placing, filling or cancelling an
+// order moves its reserved funds, so balances are enough to detect all three.
+var accountResourceURIs = []string{
+	resources.WalletResourceURI,
+	resources.TransactionsResourceURI,
+	resources.OpenOrdersResourceURI,
+}
+
+// WatchResources checks account balances every interval until ctx is
+// cancelled, sending a resources/updated notification for the wallet,
+// transaction and open order resources when they change. A zero interval
+// disables the check.
+// It returns a channel that is closed when the watcher has stopped.
+func WatchResources(ctx context.Context, s *mcpserver.MCPServer, cfg *config.Config, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 {
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		defer logPanic("resource updates")
+		watchBalances(ctx, cfg.LunoClient, interval, func() {
+			for _, uri := range accountResourceURIs {
+				notifyResourceUpdated(s, uri)
+			}
+		})
+	}()
+	return done
+}
+
+// watchBalances polls balances every interval until ctx is cancelled, calling
+// changed when they differ from the previous poll. The first successful poll
+// only sets the baseline.
+func watchBalances(ctx context.Context, client sdk.LunoClient, interval time.Duration, changed func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last [sha256.Size]byte
+	var seen bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		res, err := client.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("Failed to check balances for resource updates", slog.Any("error", err))
+			}
+			continue
+		}
+		data, err := json.Marshal(res.Balance)
+		if err != nil {
+			slog.Warn("Failed to fingerprint balances", slog.Any("error", err))
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if seen && sum != last {
+			changed()
+		}
+		last, seen = sum, true
+	}
+}
+
+// notifyResourceUpdated tells connected clients that a resource has changed
+func notifyResourceUpdated(s *mcpserver.MCPServer, uri string) {
+	slog.Debug("Resource changed", slog.String("uri", uri))
+	s.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func balances(xbt int64) *luno.GetBalancesResponse {
+	return &luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{AccountId: "1", Asset: "XBT", Balance: decimal.NewFromInt64(xbt)},
+		{AccountId: "2", Asset: "ZAR", Balance: decimal.NewFromInt64(100)},
+	}}
+}
+
+func TestWatchBalances(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []*luno.GetBalancesResponse
+		errs      []error
+		expected  int32
+	}{
+		{
+			name:      "first poll only sets the baseline",
+			responses: []*luno.GetBalancesResponse{balances(1)},
+			errs:      []error{nil},
+			expected:  0,
+		},
+		{
+			name:      "unchanged balances",
+			responses: []*luno.GetBalancesResponse{balances(1), balances(1), balances(1)},
+			errs:      []error{nil, nil, nil},
+			expected:  0,
+		},
+		{
+			name:      "each change is reported",
+			responses: []*luno.GetBalancesResponse{balances(1), balances(2), balances(2), balances(4)},
+			errs:      []error{nil, nil, nil, nil},
+			expected:  2,
+		},
+		{
+			name:      "errors keep the previous baseline",
+			responses: []*luno.GetBalancesResponse{balances(1), nil, balances(1), balances(3)},
+			errs:      []error{nil, errors.New("boom"), nil, nil},
+			expected:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client := sdk.NewMockLunoClient(t)
+			for i := range tc.responses {
+				call := client.EXPECT().GetBalances(mock.Anything, mock.Anything)
+				if i == len(tc.responses)-1 {
+					call.Run(func(context.Context, *luno.GetBalancesRequest) { cancel() })
+				}
+				call.Return(tc.responses[i], tc.errs[i]).Once()
+			}
+
+			var changes atomic.Int32
+			watchBalances(ctx, client, time.Millisecond, func() { changes.Add(1) })
+			require.Equal(t, tc.expected, changes.Load())
+		})
+	}
+}
+
+func TestWatchResourcesDisabled(t *testing.T) {
+	server := mcpserver.NewMCPServer(testServerName, testVersion1)
+	select {
+	case <-WatchResources(context.Background(), server, &config.Config{}, 0):
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watcher to stop straight away when disabled")
+	}
+}
@@ -0,0 +1,157 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/luno/luno-mcp/sdk"
+)
+
+// Headers an SSE client sends its own Luno API credentials in
+const (
+	HeaderAPIKeyID     = "X-Luno-Api-Key-Id"
+	HeaderAPIKeySecret = "X-Luno-Api-Key-Secret"
+)
+
+// ErrCredentialsNotAccepted is returned for a session that sent credentials
+// to a server that doesn't use them, so its calls don't silently go to the
+// server's account instead
+var ErrCredentialsNotAccepted = errors.New("this server doesn't accept Luno API credentials from clients")
+
+// Credentials are the Luno API credentials a client sent with a request
+type Credentials struct {
+	KeyID  string
+	Secret string
+}
+
+// key identifies credentials without holding the secret
+func (c Credentials) key() [sha256.Size]byte {
+	return sha256.Sum256([]byte(c.KeyID + "\x00" + c.Secret))
+}
+
+// ClientFactory creates a Luno client for the credentials of a session
+type ClientFactory func(Credentials) (sdk.LunoClient, error)
+
+type credentialsKey struct{}
+
+// WithCredentials returns a context carrying the credentials of a request
+func WithCredentials(ctx context.Context, c Credentials) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, c)
+}
+
+// CredentialsFromContext returns the credentials set by WithCredentials
+func CredentialsFromContext(ctx context.Context) (Credentials, bool) {
+	c, ok := ctx.Value(credentialsKey{}).(Credentials)
+	return c, ok
+}
+
+// CredentialsContext adds the credentials in the headers of r, if it has
+// both, to ctx. It is meant as the context function of the SSE server.
+func CredentialsContext(ctx context.Context, r *http.Request) context.Context {
+	c := Credentials{
+		KeyID:  strings.TrimSpace(r.Header.Get(HeaderAPIKeyID)),
+		Secret: strings.TrimSpace(r.Header.Get(HeaderAPIKeySecret)),
+	}
+	if c.KeyID == "" || c.Secret == "" {
+		return ctx
+	}
+	return WithCredentials(ctx, c)
+}
+
+// sharedClient is a client made for one set of credentials, shared by the
+// sessions that sent them
+type sharedClient struct {
+	client   sdk.LunoClient
+	sessions int
+}
+
+// SetClientFactory lets sessions that send credentials use their own Luno
+// client, made by factory. Sessions with the same credentials share a client,
+// and with it the rate limit of their API key.
+func (m *Manager) SetClientFactory(factory ClientFactory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.factory = factory
+}
+
+// AcceptsCredentials reports whether sessions may use their own credentials
+func (m *Manager) AcceptsCredentials() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.factory != nil
+}
+
+// UseCredentials gives a session the client of c, creating it the first time
+// any session sends them. It fails with ErrCredentialsNotAccepted when there
+// is no client factory.
+func (m *Manager) UseCredentials(s *Session, c Credentials) error {
+	key := c.key()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.factory == nil {
+		return ErrCredentialsNotAccepted
+	}
+	if s.credentials == key && s.Client() != nil {
+		return nil
+	}
+
+	shared, ok := m.clients[key]
+	if !ok {
+		client, err := m.factory(c)
+		if err != nil {
+			return err
+		}
+		shared = &sharedClient{client: client}
+		m.clients[key] = shared
+	}
+	shared.sessions++
+	m.releaseLocked(s)
+	s.credentials = key
+	s.SetClient(shared.client)
+	return nil
+}
+
+// Client returns the Luno client of the session with id, or nil if there
+// is no such session or it uses the server's client. Unlike Get, it doesn't
+// start the session, so background work can look up sessions that have
+// ended.
+func (m *Manager) Client(id string) sdk.LunoClient {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.Client()
+}
+
+// CredentialSessions returns the number of sessions using their own
+// credentials
+func (m *Manager) CredentialSessions() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int
+	for _, shared := range m.clients {
+		n += shared.sessions
+	}
+	return n
+}
+
+// releaseLocked drops a session's hold on the client of its credentials,
+// forgetting the client once no session uses it
+func (m *Manager) releaseLocked(s *Session) {
+	if s.Client() == nil {
+		return
+	}
+	shared, ok := m.clients[s.credentials]
+	if !ok {
+		return
+	}
+	if shared.sessions--; shared.sessions <= 0 {
+		delete(m.clients, s.credentials)
+	}
+}
@@ -0,0 +1,162 @@
+// Package session keeps the state of each connected MCP client.
+//
+// With the SSE transport one server is shared by several clients, so state
+// that belongs to a client, such as its rate limit or the Luno client of the
+// credentials it sent, is held per MCP session ID rather than globally. A session's state is
+// created on first use and dropped, along with anything registered with
+// OnEnd, when the session ends.
+package session
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-mcp/sdk"
+	"golang.org/x/time/rate"
+)
+
+// DefaultCallsPerMinute is how many tool calls a session may make per minute
+const DefaultCallsPerMinute = 120
+
+// StdioID is the ID of the only session of the stdio transport, which is the
+// same every time the server runs
+const StdioID = "stdio"
+
+// Session is the state of one MCP client
+type Session struct {
+	ID        string
+	StartedAt time.Time
+
+	mu      sync.Mutex
+	limiter *rate.Limiter
+	client  sdk.LunoClient
+
+	// credentials identifies the credentials the client was made for,
+	// guarded by the manager
+	credentials [sha256.Size]byte
+}
+
+// Allow reports whether the session may make another tool call now
+func (s *Session) Allow() bool {
+	s.mu.Lock()
+	limiter := s.limiter
+	s.mu.Unlock()
+	return limiter == nil || limiter.Allow()
+}
+
+// setLimit changes how many tool calls a minute the session may make, zero
+// for no limit
+func (s *Session) setLimit(callsPerMinute int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case callsPerMinute <= 0:
+		s.limiter = nil
+	case s.limiter == nil:
+		s.limiter = rate.NewLimiter(rate.Limit(float64(callsPerMinute)/60), callsPerMinute)
+	default:
+		s.limiter.SetLimit(rate.Limit(float64(callsPerMinute) / 60))
+		s.limiter.SetBurst(callsPerMinute)
+	}
+}
+
+// Client returns the session's own Luno client, or nil if it uses the
+// server's
+func (s *Session) Client() sdk.LunoClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// SetClient gives the session its own Luno client, such as one made from
+// credentials the client sent
+func (s *Session) SetClient(client sdk.LunoClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// Manager holds the sessions of a server
+type Manager struct {
+	now func() time.Time
+
+	mu             sync.Mutex
+	callsPerMinute int
+	sessions       map[string]*Session
+	onEnd          []func(id string)
+	factory        ClientFactory
+	clients        map[[sha256.Size]byte]*sharedClient
+}
+
+// NewManager creates a manager whose sessions may make callsPerMinute tool
+// calls a minute, zero for no limit
+func NewManager(callsPerMinute int) *Manager {
+	return &Manager{
+		callsPerMinute: callsPerMinute,
+		now:            time.Now,
+		sessions:       make(map[string]*Session),
+		clients:        make(map[[sha256.Size]byte]*sharedClient),
+	}
+}
+
+// CallsPerMinute returns the tool call limit of each session, zero for none
+func (m *Manager) CallsPerMinute() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.callsPerMinute
+}
+
+// SetCallsPerMinute changes the tool call limit of every session, zero for
+// none. Sessions that are already connected keep the calls they have left.
+func (m *Manager) SetCallsPerMinute(callsPerMinute int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callsPerMinute = callsPerMinute
+	for _, s := range m.sessions {
+		s.setLimit(callsPerMinute)
+	}
+}
+
+// Get returns the session with id, starting it if it is new
+func (m *Manager) Get(id string) *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.sessions[id]; ok {
+		return s
+	}
+	s := &Session{ID: id, StartedAt: m.now()}
+	s.setLimit(m.callsPerMinute)
+	m.sessions[id] = s
+	return s
+}
+
+// OnEnd registers fn to clean up other state of a session when it ends
+func (m *Manager) OnEnd(fn func(id string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEnd = append(m.onEnd, fn)
+}
+
+// End drops a session and runs the OnEnd functions for it
+func (m *Manager) End(id string) {
+	m.mu.Lock()
+	if s, ok := m.sessions[id]; ok {
+		m.releaseLocked(s)
+	}
+	delete(m.sessions, id)
+	onEnd := m.onEnd
+	m.mu.Unlock()
+
+	for _, fn := range onEnd {
+		fn(id)
+	}
+}
+
+// Count returns the number of active sessions
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
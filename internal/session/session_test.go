@@ -0,0 +1,123 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	m := NewManager(1)
+	var ended []string
+	m.OnEnd(func(id string) { ended = append(ended, id) })
+
+	s1 := m.Get("s1")
+	require.Same(t, s1, m.Get("s1"))
+	assert.Equal(t, "s1", s1.ID)
+	assert.Equal(t, 1, m.Count())
+
+	// Each session has its own limit
+	assert.True(t, s1.Allow())
+	assert.False(t, s1.Allow())
+	assert.True(t, m.Get("s2").Allow())
+	assert.Equal(t, 2, m.Count())
+
+	client := sdk.NewMockLunoClient(t)
+	s1.SetClient(client)
+	assert.Equal(t, client, m.Get("s1").Client())
+	assert.Nil(t, m.Get("s2").Client())
+
+	m.End("s1")
+	assert.Equal(t, []string{"s1"}, ended)
+	assert.Equal(t, 1, m.Count())
+	assert.NotSame(t, s1, m.Get("s1"))
+}
+
+func TestManagerNoLimit(t *testing.T) {
+	m := NewManager(0)
+	s := m.Get("s1")
+	for i := 0; i < 1000; i++ {
+		require.True(t, s.Allow())
+	}
+	assert.Zero(t, m.CallsPerMinute())
+}
+
+func TestManagerSetCallsPerMinute(t *testing.T) {
+	m := NewManager(1)
+	s := m.Get("s1")
+	require.True(t, s.Allow())
+	require.False(t, s.Allow())
+
+	// Lifting the limit applies to connected sessions
+	m.SetCallsPerMinute(0)
+	assert.Zero(t, m.CallsPerMinute())
+	for i := 0; i < 100; i++ {
+		require.True(t, s.Allow())
+	}
+
+	// And so does setting one again, as well as to new sessions
+	m.SetCallsPerMinute(2)
+	assert.Equal(t, 2, m.CallsPerMinute())
+	for _, s := range []*Session{s, m.Get("s2")} {
+		assert.True(t, s.Allow())
+		assert.True(t, s.Allow())
+		assert.False(t, s.Allow())
+	}
+}
+
+func TestUseCredentials(t *testing.T) {
+	m := NewManager(0)
+	creds := Credentials{KeyID: "key", Secret: "secret"}
+	require.ErrorIs(t, m.UseCredentials(m.Get("s1"), creds), ErrCredentialsNotAccepted)
+	assert.False(t, m.AcceptsCredentials())
+
+	made := 0
+	m.SetClientFactory(func(Credentials) (sdk.LunoClient, error) {
+		made++
+		return sdk.NewMockLunoClient(t), nil
+	})
+	assert.True(t, m.AcceptsCredentials())
+
+	// Sessions with the same credentials share a client, made once
+	require.NoError(t, m.UseCredentials(m.Get("s1"), creds))
+	require.NoError(t, m.UseCredentials(m.Get("s1"), creds))
+	require.NoError(t, m.UseCredentials(m.Get("s2"), creds))
+	assert.Equal(t, 1, made)
+	assert.Same(t, m.Get("s1").Client(), m.Get("s2").Client())
+	assert.Equal(t, 2, m.CredentialSessions())
+	assert.Same(t, m.Get("s1").Client(), m.Client("s1"))
+	assert.Nil(t, m.Client("unknown"))
+	assert.Equal(t, 2, m.Count(), "looking up a client must not start a session")
+
+	// Other credentials get their own client
+	require.NoError(t, m.UseCredentials(m.Get("s3"), Credentials{KeyID: "key", Secret: "other"}))
+	assert.Equal(t, 2, made)
+	assert.NotSame(t, m.Get("s1").Client(), m.Get("s3").Client())
+
+	// The client is dropped once no session uses it
+	m.End("s1")
+	m.End("s2")
+	assert.Equal(t, 1, m.CredentialSessions())
+	require.NoError(t, m.UseCredentials(m.Get("s1"), creds))
+	assert.Equal(t, 3, made)
+}
+
+func TestCredentialsContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/message", nil)
+	_, ok := CredentialsFromContext(CredentialsContext(context.Background(), r))
+	assert.False(t, ok)
+
+	r.Header.Set(HeaderAPIKeyID, "key")
+	_, ok = CredentialsFromContext(CredentialsContext(context.Background(), r))
+	assert.False(t, ok, "both headers are needed")
+
+	r.Header.Set(HeaderAPIKeySecret, " secret ")
+	creds, ok := CredentialsFromContext(CredentialsContext(context.Background(), r))
+	require.True(t, ok)
+	assert.Equal(t, Credentials{KeyID: "key", Secret: "secret"}, creds)
+}
@@ -0,0 +1,185 @@
+// Package state keeps the state of background features, such as price
+// alerts, watched orders and schedules, in a local file so that it survives
+// restarts.
+//
+// The file is a JSON document with a format version and a section for each
+// feature. Every save rewrites the whole file through a temporary file and a
+// rename, so it is never left half written. Files written by older versions
+// are upgraded when opened, keeping a copy of the original.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Version is the version of the file format written by this package
+const Version = 2
+
+// migrations upgrade a document one version at a time: migrations[i]
+// upgrades a version i+1 document to version i+2
+var migrations = []func(doc map[string]json.RawMessage) (map[string]json.RawMessage, error){
+	migrateSchedulesFile,
+}
+
+// document is the layout of the state file
+type document struct {
+	Version  int                        `json:"version"`
+	Sections map[string]json.RawMessage `json:"sections"`
+}
+
+// Store is a concurrency-safe set of sections saved to a file
+type Store struct {
+	path string
+
+	mu       sync.Mutex
+	sections map[string]json.RawMessage
+}
+
+// Open loads the state saved at path, starting empty if the file doesn't
+// exist yet. A file from an older version is upgraded and saved, and the
+// original is kept alongside it with the old version as a suffix.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, sections: make(map[string]json.RawMessage)}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("reading state file %s: %w", path, err)
+	}
+	var version int
+	if err := json.Unmarshal(doc["version"], &version); err != nil || version < 1 {
+		return nil, fmt.Errorf("state file %s has no valid version", path)
+	}
+	if version > Version {
+		return nil, fmt.Errorf("state file %s has version %d, which is newer than this server supports (%d)", path, version, Version)
+	}
+
+	original := version
+	for ; version < Version; version++ {
+		if doc, err = migrations[version-1](doc); err != nil {
+			return nil, fmt.Errorf("upgrading state file %s from version %d: %w", path, version, err)
+		}
+	}
+	if sections := doc["sections"]; sections != nil {
+		if err := json.Unmarshal(sections, &s.sections); err != nil {
+			return nil, fmt.Errorf("reading state file %s: %w", path, err)
+		}
+	}
+
+	if original < Version {
+		if err := os.WriteFile(fmt.Sprintf("%s.v%d", path, original), data, 0o600); err != nil {
+			return nil, fmt.Errorf("keeping a copy of state file %s: %w", path, err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err := s.saveLocked(); err != nil {
+			return nil, fmt.Errorf("saving upgraded state file %s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+// Path returns the location of the state file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load decodes a section into v and reports whether it was saved before
+func (s *Store) Load(section string, v any) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, ok := s.sections[section]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("reading %s state: %w", section, err)
+	}
+	return true, nil
+}
+
+// Save replaces a section with v and writes the file
+func (s *Store) Save(section string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.sections[section]
+	s.sections[section] = raw
+	if err := s.saveLocked(); err != nil {
+		if existed {
+			s.sections[section] = previous
+		} else {
+			delete(s.sections, section)
+		}
+		return err
+	}
+	return nil
+}
+
+// saveLocked writes the sections to a temporary file and renames it over
+// the state file
+func (s *Store) saveLocked() error {
+	data, err := json.MarshalIndent(document{Version: Version, Sections: s.sections}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// migrateSchedulesFile upgrades version 1, the file that only held
+// schedules, by moving its contents into the schedules section
+func migrateSchedulesFile(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	schedules, err := json.Marshal(map[string]json.RawMessage{
+		"next_id":   doc["next_id"],
+		"schedules": doc["schedules"],
+	})
+	if err != nil {
+		return nil, err
+	}
+	sections, err := json.Marshal(map[string]json.RawMessage{"schedules": schedules})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]json.RawMessage{"sections": sections}, nil
+}
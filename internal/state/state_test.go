@@ -0,0 +1,121 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSection struct {
+	Values []string `json:"values"`
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	var got testSection
+	found, err := s.Load("test", &got)
+	require.NoError(t, err)
+	assert.False(t, found)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist, "nothing is written until something is saved")
+
+	require.NoError(t, s.Save("test", testSection{Values: []string{"a", "b"}}))
+	require.NoError(t, s.Save("other", map[string]int{"n": 1}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	found, err = reopened.Load("test", &got)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"a", "b"}, got.Values)
+
+	var other map[string]int
+	found, err = reopened.Load("other", &other)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1, other["n"])
+
+	var wrong []int
+	_, err = reopened.Load("test", &wrong)
+	assert.ErrorContains(t, err, "reading test state")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are cleaned up")
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		errMsg string
+	}{
+		{
+			name:   "corrupt",
+			data:   "{",
+			errMsg: "reading state file",
+		},
+		{
+			name:   "no version",
+			data:   `{"sections": {}}`,
+			errMsg: "has no valid version",
+		},
+		{
+			name:   "newer version",
+			data:   `{"version": 99, "sections": {}}`,
+			errMsg: "has version 99, which is newer than this server supports",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.data), 0o600))
+			_, err := Open(path)
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestMigrateSchedulesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	original := `{"version": 1, "next_id": 4, "schedules": [{"id": "3", "pair": "XBTZAR"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o600))
+
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	var schedules struct {
+		NextID    int64            `json:"next_id"`
+		Schedules []map[string]any `json:"schedules"`
+	}
+	found, err := s.Load("schedules", &schedules)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, int64(4), schedules.NextID)
+	require.Len(t, schedules.Schedules, 1)
+	assert.Equal(t, "XBTZAR", schedules.Schedules[0]["pair"])
+
+	// The upgraded file is saved, and the original kept
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var doc document
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, Version, doc.Version)
+	assert.Contains(t, doc.Sections, "schedules")
+
+	kept, err := os.ReadFile(path + ".v1")
+	require.NoError(t, err)
+	assert.Equal(t, original, string(kept))
+}
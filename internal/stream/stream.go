@@ -0,0 +1,298 @@
+// Package stream keeps live order books using the Luno streaming API.
+//
+// A pair is streamed from the first time its order book is requested until
+// the manager is closed. Each stream holds a websocket connection that
+// reconnects on its own, so the number of streams is capped. While a stream
+// is disconnected its book is stale, as the updates it misses can't be
+// replayed, until the full order book has been loaded again.
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/streaming"
+)
+
+const (
+	// DefaultMaxStreams is the default number of pairs that can be streamed at once
+	DefaultMaxStreams = 10
+
+	// notifyInterval is the shortest time between update notifications for a pair,
+	// so that a busy market doesn't flood clients
+	notifyInterval = time.Second
+
+	// readyTimeout is how long a first request waits for the initial order book
+	readyTimeout = 5 * time.Second
+
+	// reconnectAttemptReset is how long a stream has to stay connected for
+	// the delay before reconnecting to start again from the shortest
+	reconnectAttemptReset = 30 * time.Minute
+)
+
+// ErrClosed is returned after the manager has been closed
+var ErrClosed = errors.New("order book streaming has stopped")
+
+// OrderBook is a live order book connection
+type OrderBook interface {
+	Snapshot() streaming.Snapshot
+	Close()
+}
+
+// Dialer connects to the live order book of a pair. onReady is called each
+// time the full order book has been loaded, including after a reconnect,
+// onUpdate after each update has been applied, and onDisconnect each time the
+// connection drops or a reconnect fails.
+type Dialer func(pair string, onReady, onUpdate, onDisconnect func()) (OrderBook, error)
+
+// LunoDialer returns a Dialer for the Luno streaming API
+func LunoDialer(keyID, keySecret string) Dialer {
+	return func(pair string, onReady, onUpdate, onDisconnect func()) (OrderBook, error) {
+		return streaming.Dial(keyID, keySecret, pair,
+			streaming.WithConnectCallback(func(*streaming.Conn) { onReady() }),
+			streaming.WithUpdateCallback(func(streaming.Update) { onUpdate() }),
+			// The streaming client asks for the backoff after every dropped
+			// connection and failed reconnect, and has no other hook for them
+			streaming.WithBackoffHandler(func(attempts int) time.Duration {
+				onDisconnect()
+				return reconnectDelay(attempts)
+			}, reconnectAttemptReset),
+		)
+	}
+}
+
+// reconnectDelay is the streaming client's own backoff: doubling from two
+// seconds up to a minute, give or take 100ms
+func reconnectDelay(attempts int) time.Duration {
+	delay := min(time.Second<<min(attempts, 6), time.Minute)
+	return delay + time.Duration(rand.Int64N(200)-100)*time.Millisecond
+}
+
+// Book is a snapshot of a live order book
+type Book struct {
+	Pair string `json:"pair"`
+	// Ready is false until the initial order book has been received
+	Ready     bool        `json:"ready"`
+	Sequence  int64       `json:"sequence"`
+	Status    luno.Status `json:"status,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at,omitzero"`
+	// Stale is true while the stream is disconnected, since StaleSince. The
+	// updates missed meanwhile are only caught up on once it reconnects.
+	Stale      bool      `json:"stale,omitempty"`
+	StaleSince time.Time `json:"stale_since,omitzero"`
+	// Disconnects counts the times the stream has dropped, so a change
+	// between two reads shows that updates were missed in between
+	Disconnects int                    `json:"disconnects"`
+	Bids        []luno.OrderBookEntry  `json:"bids"`
+	Asks        []luno.OrderBookEntry  `json:"asks"`
+	LastTrade   *streaming.TradeUpdate `json:"last_trade,omitempty"`
+}
+
+// Manager streams the order books of the pairs that have been requested
+type Manager struct {
+	dial       Dialer
+	maxStreams int
+	now        func() time.Time
+
+	mu           sync.Mutex
+	streams      map[string]*liveBook
+	notify       func(pair string)
+	onDisconnect func(pair string)
+	closed       bool
+}
+
+// liveBook is a single streamed pair
+type liveBook struct {
+	book      OrderBook
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	mu          sync.Mutex
+	updatedAt   time.Time
+	lastNotify  time.Time
+	pending     bool
+	connected   bool
+	staleSince  time.Time
+	disconnects int
+}
+
+// NewManager creates a manager that opens at most maxStreams streams
+func NewManager(dial Dialer, maxStreams int) *Manager {
+	return &Manager{
+		dial:       dial,
+		maxStreams: maxStreams,
+		now:        time.Now,
+		streams:    make(map[string]*liveBook),
+	}
+}
+
+// SetNotifier sets the function called when a streamed order book changes.
+// Calls are coalesced to at most one per pair per second.
+func (m *Manager) SetNotifier(fn func(pair string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notify = fn
+}
+
+// SetDisconnectNotifier sets the function called straight away when the
+// stream of a pair drops, once per disconnection however many reconnects
+// fail
+func (m *Manager) SetDisconnectNotifier(fn func(pair string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onDisconnect = fn
+}
+
+// Snapshot returns the live order book of a pair, starting a stream if there
+// isn't one. A new stream waits briefly for the initial order book; if it
+// doesn't arrive in time the returned book is not ready.
+func (m *Manager) Snapshot(ctx context.Context, pair string) (Book, error) {
+	lb, err := m.subscribe(pair)
+	if err != nil {
+		return Book{}, err
+	}
+
+	timer := time.NewTimer(readyTimeout)
+	defer timer.Stop()
+	select {
+	case <-lb.ready:
+	case <-timer.C:
+	case <-ctx.Done():
+		return Book{}, ctx.Err()
+	}
+
+	snap := lb.book.Snapshot()
+	book := Book{
+		Pair:     pair,
+		Ready:    snap.Sequence > 0,
+		Sequence: snap.Sequence,
+		Status:   snap.Status,
+		Bids:     snap.Bids,
+		Asks:     snap.Asks,
+	}
+	lb.mu.Lock()
+	book.UpdatedAt = lb.updatedAt
+	book.Stale = !lb.staleSince.IsZero()
+	book.StaleSince = lb.staleSince
+	book.Disconnects = lb.disconnects
+	lb.mu.Unlock()
+	if snap.LastTrade.Sequence > 0 {
+		book.LastTrade = &snap.LastTrade
+	}
+	return book, nil
+}
+
+// Pairs returns the pairs being streamed
+func (m *Manager) Pairs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pairs := make([]string, 0, len(m.streams))
+	for pair := range m.streams {
+		pairs = append(pairs, pair)
+	}
+	slices.Sort(pairs)
+	return pairs
+}
+
+// Close stops every stream
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for pair, lb := range m.streams {
+		lb.book.Close()
+		delete(m.streams, pair)
+	}
+}
+
+// subscribe returns the stream of a pair, dialling it if needed
+func (m *Manager) subscribe(pair string) (*liveBook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+	if lb, ok := m.streams[pair]; ok {
+		return lb, nil
+	}
+	if len(m.streams) >= m.maxStreams {
+		return nil, fmt.Errorf("already streaming the maximum of %d pairs", m.maxStreams)
+	}
+
+	lb := &liveBook{ready: make(chan struct{})}
+	book, err := m.dial(pair,
+		func() {
+			lb.readyOnce.Do(func() { close(lb.ready) })
+			lb.mu.Lock()
+			lb.connected = true
+			lb.staleSince = time.Time{}
+			lb.mu.Unlock()
+			m.changed(pair, lb)
+		},
+		func() { m.changed(pair, lb) },
+		func() { m.disconnected(pair, lb) },
+	)
+	if err != nil {
+		return nil, fmt.Errorf("streaming %s: %w", pair, err)
+	}
+	lb.book = book
+	m.streams[pair] = lb
+	return lb, nil
+}
+
+// disconnected marks the book of a stream that dropped as stale and calls the
+// disconnect notifier. Failed reconnects, and failures before the book was
+// ever loaded, change nothing.
+func (m *Manager) disconnected(pair string, lb *liveBook) {
+	lb.mu.Lock()
+	if !lb.connected {
+		lb.mu.Unlock()
+		return
+	}
+	lb.connected = false
+	lb.staleSince = m.now()
+	lb.disconnects++
+	lb.mu.Unlock()
+
+	m.mu.Lock()
+	onDisconnect, closed := m.onDisconnect, m.closed
+	m.mu.Unlock()
+	if onDisconnect != nil && !closed {
+		onDisconnect(pair)
+	}
+	m.changed(pair, lb)
+}
+
+// changed records an order book change and schedules a notification
+func (m *Manager) changed(pair string, lb *liveBook) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	now := m.now()
+	lb.updatedAt = now
+	if lb.pending {
+		return
+	}
+	lb.pending = true
+	delay := max(lb.lastNotify.Add(notifyInterval).Sub(now), 0)
+	time.AfterFunc(delay, func() {
+		lb.mu.Lock()
+		lb.pending = false
+		lb.lastNotify = m.now()
+		lb.mu.Unlock()
+
+		m.mu.Lock()
+		notify, closed := m.notify, m.closed
+		m.mu.Unlock()
+		if notify != nil && !closed {
+			notify(pair)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-go/streaming"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBook is an order book stream controlled by the test
+type fakeBook struct {
+	mu           sync.Mutex
+	snap         streaming.Snapshot
+	closed       bool
+	onReady      func()
+	onUpdate     func()
+	onDisconnect func()
+}
+
+func (b *fakeBook) Snapshot() streaming.Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.snap
+}
+
+func (b *fakeBook) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+}
+
+// drop clears the order book, as when the connection is lost
+func (b *fakeBook) drop() {
+	b.mu.Lock()
+	b.snap = streaming.Snapshot{}
+	b.mu.Unlock()
+	b.onDisconnect()
+}
+
+// load sets the full order book, as when the stream connects
+func (b *fakeBook) load(seq int64) {
+	b.mu.Lock()
+	b.snap = streaming.Snapshot{
+		Sequence: seq,
+		Status:   luno.StatusActive,
+		Bids:     []luno.OrderBookEntry{{Price: decimal.NewFromInt64(100), Volume: decimal.NewFromInt64(1)}},
+		Asks:     []luno.OrderBookEntry{{Price: decimal.NewFromInt64(101), Volume: decimal.NewFromInt64(2)}},
+	}
+	b.mu.Unlock()
+	b.onReady()
+}
+
+type fakeDialer struct {
+	mu    sync.Mutex
+	books map[string]*fakeBook
+	err   error
+}
+
+func (d *fakeDialer) dial(pair string, onReady, onUpdate, onDisconnect func()) (OrderBook, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return nil, d.err
+	}
+	b := &fakeBook{onReady: onReady, onUpdate: onUpdate, onDisconnect: onDisconnect}
+	d.books[pair] = b
+	return b, nil
+}
+
+func (d *fakeDialer) book(pair string) *fakeBook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.books[pair]
+}
+
+func newTestManager(maxStreams int) (*Manager, *fakeDialer) {
+	d := &fakeDialer{books: make(map[string]*fakeBook)}
+	return NewManager(d.dial, maxStreams), d
+}
+
+func TestSnapshot(t *testing.T) {
+	m, d := newTestManager(2)
+
+	// Load the initial order book once the stream has been dialled
+	go func() {
+		for d.book("XBTZAR") == nil {
+			time.Sleep(time.Millisecond)
+		}
+		d.book("XBTZAR").load(42)
+	}()
+
+	book, err := m.Snapshot(context.Background(), "XBTZAR")
+	require.NoError(t, err)
+	assert.True(t, book.Ready)
+	assert.Equal(t, "XBTZAR", book.Pair)
+	assert.Equal(t, int64(42), book.Sequence)
+	assert.Equal(t, luno.StatusActive, book.Status)
+	require.Len(t, book.Bids, 1)
+	assert.Equal(t, "100", book.Bids[0].Price.String())
+	assert.False(t, book.UpdatedAt.IsZero())
+	assert.Nil(t, book.LastTrade)
+
+	// A second read reuses the stream
+	_, err = m.Snapshot(context.Background(), "XBTZAR")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"XBTZAR"}, m.Pairs())
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	t.Run("stream limit", func(t *testing.T) {
+		m, d := newTestManager(1)
+		_, err := m.subscribe("XBTZAR")
+		require.NoError(t, err)
+		d.book("XBTZAR").load(1)
+
+		_, err = m.Snapshot(context.Background(), "ETHZAR")
+		assert.ErrorContains(t, err, "maximum of 1 pairs")
+	})
+
+	t.Run("dial error", func(t *testing.T) {
+		m, d := newTestManager(1)
+		d.err = errors.New("no credentials")
+
+		_, err := m.Snapshot(context.Background(), "XBTZAR")
+		assert.ErrorContains(t, err, "streaming XBTZAR: no credentials")
+		assert.Empty(t, m.Pairs())
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		m, _ := newTestManager(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := m.Snapshot(ctx, "XBTZAR")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		m, d := newTestManager(1)
+		_, err := m.subscribe("XBTZAR")
+		require.NoError(t, err)
+
+		m.Close()
+		assert.True(t, d.book("XBTZAR").closed)
+		_, err = m.Snapshot(context.Background(), "XBTZAR")
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+}
+
+func TestNotifications(t *testing.T) {
+	m, d := newTestManager(1)
+	notified := make(chan string, 10)
+	m.SetNotifier(func(pair string) { notified <- pair })
+
+	_, err := m.subscribe("XBTZAR")
+	require.NoError(t, err)
+	b := d.book("XBTZAR")
+	b.load(1)
+
+	select {
+	case pair := <-notified:
+		assert.Equal(t, "XBTZAR", pair)
+	case <-time.After(time.Second):
+		t.Fatal("Expected a notification for the initial order book")
+	}
+
+	// A burst of updates is coalesced into one notification
+	for range 5 {
+		b.onUpdate()
+	}
+	select {
+	case <-notified:
+	case <-time.After(2 * notifyInterval):
+		t.Fatal("Expected a notification for the updates")
+	}
+	select {
+	case <-notified:
+		t.Fatal("Expected updates to be coalesced")
+	case <-time.After(notifyInterval / 2):
+	}
+}
+
+func TestDisconnect(t *testing.T) {
+	now := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	m, d := newTestManager(1)
+	m.now = func() time.Time { return now }
+	disconnected := make(chan string, 10)
+	m.SetDisconnectNotifier(func(pair string) { disconnected <- pair })
+
+	_, err := m.subscribe("XBTZAR")
+	require.NoError(t, err)
+	b := d.book("XBTZAR")
+
+	// Failing to connect before the book was ever loaded isn't a gap
+	b.onDisconnect()
+	assert.Empty(t, disconnected)
+	b.load(1)
+
+	// A dropped stream is stale, and the notifier is told once however
+	// many reconnects fail
+	now = now.Add(time.Minute)
+	b.drop()
+	b.onDisconnect()
+	require.Len(t, disconnected, 1)
+	assert.Equal(t, "XBTZAR", <-disconnected)
+
+	book, err := m.Snapshot(context.Background(), "XBTZAR")
+	require.NoError(t, err)
+	assert.True(t, book.Stale)
+	assert.Equal(t, now, book.StaleSince)
+	assert.Equal(t, 1, book.Disconnects)
+	assert.False(t, book.Ready)
+
+	// Loading the order book again clears the flag, but keeps the count
+	b.load(5)
+	book, err = m.Snapshot(context.Background(), "XBTZAR")
+	require.NoError(t, err)
+	assert.False(t, book.Stale)
+	assert.True(t, book.StaleSince.IsZero())
+	assert.Equal(t, 1, book.Disconnects)
+	assert.True(t, book.Ready)
+
+	// Nothing is reported once the manager is closed
+	m.Close()
+	b.drop()
+	assert.Empty(t, disconnected)
+}
+
+func TestReconnectDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{5, 32 * time.Second},
+		{6, time.Minute},
+		{20, time.Minute},
+	}
+	for _, tc := range tests {
+		assert.InDelta(t, tc.expected, reconnectDelay(tc.attempts), float64(100*time.Millisecond), "attempt %d", tc.attempts)
+	}
+}
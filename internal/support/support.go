@@ -0,0 +1,302 @@
+// Package support collects diagnostic information for bug reports.
+//
+// A Recorder keeps a bounded history of recent log records and tool call
+// metadata. On request it writes them, together with version information and a
+// redacted view of the configuration, into a single zip archive that users can
+// attach to GitHub issues.
+package support
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultMaxLogs is the number of log records kept for a bundle
+	DefaultMaxLogs = 500
+	// DefaultMaxToolCalls is the number of tool calls kept for a bundle
+	DefaultMaxToolCalls = 50
+	// DefaultBundleInterval is the minimum time between two bundles
+	DefaultBundleInterval = time.Minute
+
+	redactedValue = "[REDACTED]"
+)
+
+// ErrRateLimited is returned when bundles are requested too frequently
+var ErrRateLimited = errors.New("support bundle was created too recently")
+
+// sensitiveKeys are substrings of log attribute keys whose values are never captured
+var sensitiveKeys = []string{"secret", "key", "token", "password", "auth"}
+
+// LogEntry is a captured log record
+type LogEntry struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
+
+// ToolCall is metadata about a tool invocation. Argument values are
+// deliberately not captured, only their names.
+type ToolCall struct {
+	Tool      string    `json:"tool"`
+	Arguments []string  `json:"arguments,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration,omitempty"`
+	IsError   bool      `json:"is_error"`
+}
+
+// Recorder captures recent logs and tool calls
+type Recorder struct {
+	name    string
+	version string
+
+	maxLogs      int
+	maxToolCalls int
+	interval     time.Duration
+	now          func() time.Time
+
+	mu         sync.Mutex
+	logs       []LogEntry
+	toolCalls  []ToolCall
+	pending    map[any]time.Time
+	lastBundle time.Time
+}
+
+// NewRecorder creates a Recorder for the named application
+func NewRecorder(name, version string) *Recorder {
+	return &Recorder{
+		name:         name,
+		version:      version,
+		maxLogs:      DefaultMaxLogs,
+		maxToolCalls: DefaultMaxToolCalls,
+		interval:     DefaultBundleInterval,
+		now:          time.Now,
+		pending:      make(map[any]time.Time),
+	}
+}
+
+// LogHandler returns a slog.Handler that records log records at or above level
+func (r *Recorder) LogHandler(level slog.Leveler) slog.Handler {
+	return &logHandler{r: r, level: level}
+}
+
+// AddHooks registers the tool call hooks on an existing set of server hooks
+func (r *Recorder) AddHooks(hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(r.beforeCallTool)
+	hooks.AddAfterCallTool(r.afterCallTool)
+}
+
+func (r *Recorder) beforeCallTool(_ context.Context, id any, _ *mcp.CallToolRequest) {
+	if id == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[id] = r.now()
+}
+
+func (r *Recorder) afterCallTool(_ context.Context, id any, message *mcp.CallToolRequest, result any) {
+	toolResult, _ := result.(*mcp.CallToolResult)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	call := ToolCall{
+		Tool:      message.Params.Name,
+		Arguments: argumentNames(message),
+		StartedAt: now,
+		IsError:   toolResult != nil && toolResult.IsError,
+	}
+	if start, ok := r.pending[id]; ok {
+		call.StartedAt = start
+		call.Duration = now.Sub(start).String()
+		delete(r.pending, id)
+	}
+	r.toolCalls = appendBounded(r.toolCalls, call, r.maxToolCalls)
+}
+
+func (r *Recorder) recordLog(entry LogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = appendBounded(r.logs, entry, r.maxLogs)
+}
+
+// WriteBundle writes a support bundle zip archive into dir and returns its path.
+// The configuration should already be redacted by the caller.
+// Bundles are rate limited, ErrRateLimited is returned if one was created too recently.
+func (r *Recorder) WriteBundle(dir string, redactedConfig map[string]any) (string, error) {
+	r.mu.Lock()
+	now := r.now()
+	if !r.lastBundle.IsZero() && now.Sub(r.lastBundle) < r.interval {
+		retryIn := r.interval - now.Sub(r.lastBundle)
+		r.mu.Unlock()
+		return "", fmt.Errorf("%w, try again in %s", ErrRateLimited, retryIn.Round(time.Second))
+	}
+	r.lastBundle = now
+	logs := slices.Clone(r.logs)
+	toolCalls := slices.Clone(r.toolCalls)
+	r.mu.Unlock()
+
+	files := []struct {
+		name    string
+		content any
+	}{
+		{name: "version.json", content: r.versionInfo()},
+		{name: "config.json", content: redactedConfig},
+		{name: "logs.json", content: logs},
+		{name: "tool_calls.json", content: toolCalls},
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-support-%s.zip", r.name, now.UTC().Format("20060102T150405Z")))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("creating bundle file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	zw := zip.NewWriter(f)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return "", fmt.Errorf("adding %s to bundle: %w", file.name, err)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.content); err != nil {
+			return "", fmt.Errorf("writing %s to bundle: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("finalising bundle: %w", err)
+	}
+
+	return path, nil
+}
+
+func (r *Recorder) versionInfo() map[string]string {
+	return map[string]string{
+		"name":       r.name,
+		"version":    r.version,
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}
+}
+
+func argumentNames(message *mcp.CallToolRequest) []string {
+	args := message.GetArguments()
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func appendBounded[T any](s []T, v T, limit int) []T {
+	s = append(s, v)
+	if len(s) > limit {
+		s = slices.Delete(s, 0, len(s)-limit)
+	}
+	return s
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// logHandler is a slog.Handler that feeds a Recorder
+type logHandler struct {
+	r      *Recorder
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string
+}
+
+// Enabled implements slog.Handler
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *logHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]string)
+	for _, a := range h.attrs {
+		addAttr(attrs, "", a)
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	entry := LogEntry{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: record.Message,
+	}
+	if len(attrs) > 0 {
+		entry.Attrs = attrs
+	}
+	h.r.recordLog(entry)
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+func addAttr(dst map[string]string, prefix string, a slog.Attr) {
+	key := prefix + a.Key
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addAttr(dst, groupPrefix, ga)
+		}
+		return
+	}
+	if isSensitiveKey(key) {
+		dst[key] = redactedValue
+		return
+	}
+	dst[key] = a.Value.Resolve().String()
+}
@@ -0,0 +1,158 @@
+package support
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readBundle(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer func() { _ = zr.Close() }()
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+		files[f.Name] = b
+	}
+	return files
+}
+
+func TestLogHandlerRedaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(l *slog.Logger)
+		expected map[string]string
+	}{
+		{
+			name:     "plain attributes are kept",
+			log:      func(l *slog.Logger) { l.Info("hello", "pair", "XBTZAR") },
+			expected: map[string]string{"pair": "XBTZAR"},
+		},
+		{
+			name:     "sensitive attributes are redacted",
+			log:      func(l *slog.Logger) { l.Info("hello", "api_key_id", "abc", "apiSecret", "def") },
+			expected: map[string]string{"api_key_id": redactedValue, "apiSecret": redactedValue},
+		},
+		{
+			name:     "groups are flattened",
+			log:      func(l *slog.Logger) { l.WithGroup("req").Info("hello", "pair", "XBTZAR", "token", "t") },
+			expected: map[string]string{"req.pair": "XBTZAR", "req.token": redactedValue},
+		},
+		{
+			name:     "with attrs are included",
+			log:      func(l *slog.Logger) { l.With("session", "s1").Info("hello") },
+			expected: map[string]string{"session": "s1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRecorder("test", "1.0.0")
+			tc.log(slog.New(r.LogHandler(slog.LevelInfo)))
+
+			require.Len(t, r.logs, 1)
+			assert.Equal(t, "hello", r.logs[0].Message)
+			assert.Equal(t, tc.expected, r.logs[0].Attrs)
+		})
+	}
+}
+
+func TestLogHandlerLevelAndBound(t *testing.T) {
+	r := NewRecorder("test", "1.0.0")
+	r.maxLogs = 2
+	logger := slog.New(r.LogHandler(slog.LevelInfo))
+
+	logger.Debug("ignored")
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+
+	require.Len(t, r.logs, 2)
+	assert.Equal(t, "two", r.logs[0].Message)
+	assert.Equal(t, "three", r.logs[1].Message)
+}
+
+func TestToolCallHooks(t *testing.T) {
+	r := NewRecorder("test", "1.0.0")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	r.now = func() time.Time { return now }
+
+	hooks := &server.Hooks{}
+	r.AddHooks(hooks)
+
+	req := &mcp.CallToolRequest{}
+	req.Params.Name = "get_ticker"
+	req.Params.Arguments = map[string]any{"pair": "XBTZAR", "enrich": true}
+
+	for _, h := range hooks.OnBeforeCallTool {
+		h(context.Background(), 1, req)
+	}
+	now = start.Add(250 * time.Millisecond)
+	for _, h := range hooks.OnAfterCallTool {
+		h(context.Background(), 1, req, mcp.NewToolResultError("boom"))
+	}
+
+	require.Len(t, r.toolCalls, 1)
+	call := r.toolCalls[0]
+	assert.Equal(t, "get_ticker", call.Tool)
+	assert.Equal(t, []string{"enrich", "pair"}, call.Arguments)
+	assert.Equal(t, start, call.StartedAt)
+	assert.Equal(t, "250ms", call.Duration)
+	assert.True(t, call.IsError)
+	assert.Empty(t, r.pending)
+}
+
+func TestWriteBundle(t *testing.T) {
+	r := NewRecorder("test", "1.0.0")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	slog.New(r.LogHandler(slog.LevelInfo)).Info("started", "secret", "shh")
+
+	dir := t.TempDir()
+	path, err := r.WriteBundle(dir, map[string]any{"domain": "api.luno.com"})
+	require.NoError(t, err)
+
+	files := readBundle(t, path)
+	require.Contains(t, files, "version.json")
+	require.Contains(t, files, "config.json")
+	require.Contains(t, files, "logs.json")
+	require.Contains(t, files, "tool_calls.json")
+	assert.NotContains(t, string(files["logs.json"]), "shh")
+
+	var version map[string]string
+	require.NoError(t, json.Unmarshal(files["version.json"], &version))
+	assert.Equal(t, "1.0.0", version["version"])
+
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal(files["config.json"], &cfg))
+	assert.Equal(t, "api.luno.com", cfg["domain"])
+
+	t.Run("rate limited", func(t *testing.T) {
+		now = now.Add(10 * time.Second)
+		_, err := r.WriteBundle(dir, nil)
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("allowed after interval", func(t *testing.T) {
+		now = now.Add(DefaultBundleInterval)
+		_, err := r.WriteBundle(dir, nil)
+		assert.NoError(t, err)
+	})
+}
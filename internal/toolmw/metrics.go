@@ -0,0 +1,85 @@
+package toolmw
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolStats are the call counts and latencies of one tool
+type ToolStats struct {
+	Calls     int64 `json:"calls"`
+	Errors    int64 `json:"errors"`
+	AverageMS int64 `json:"average_ms"`
+	MaxMS     int64 `json:"max_ms"`
+}
+
+// toolStats accumulates ToolStats
+type toolStats struct {
+	calls  int64
+	errors int64
+	total  time.Duration
+	max    time.Duration
+}
+
+// Metrics counts the calls, errors and latency of each tool for the life of
+// the process
+type Metrics struct {
+	mu    sync.Mutex
+	tools map[string]*toolStats
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{tools: make(map[string]*toolStats)}
+}
+
+// Middleware records the calls of a tool. Error results count as errors.
+func (m *Metrics) Middleware(name string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+			m.record(name, time.Since(start), err != nil || (result != nil && result.IsError))
+			return result, err
+		}
+	}
+}
+
+// record adds a call to the stats of a tool
+func (m *Metrics) record(name string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.tools[name]
+	if !ok {
+		s = &toolStats{}
+		m.tools[name] = s
+	}
+	s.calls++
+	if failed {
+		s.errors++
+	}
+	s.total += d
+	s.max = max(s.max, d)
+}
+
+// Snapshot returns the stats of every tool that has been called
+func (m *Metrics) Snapshot() map[string]ToolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make(map[string]ToolStats, len(m.tools))
+	for name, s := range m.tools {
+		out[name] = ToolStats{
+			Calls:     s.calls,
+			Errors:    s.errors,
+			AverageMS: (s.total / time.Duration(s.calls)).Milliseconds(),
+			MaxMS:     s.max.Milliseconds(),
+		}
+	}
+	return out
+}
@@ -0,0 +1,441 @@
+// Package toolmw provides middleware for MCP tool handlers.
+//
+// Cross-cutting concerns such as panic recovery, logging, metrics,
+// per-session limits, permissions, timeouts, auditing and tracing are written once here and composed
+// around every tool handler with Chain, rather than repeated in each handler.
+package toolmw
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/audit"
+	"github.com/luno/luno-mcp/internal/auth"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware wraps a tool handler
+type Middleware = server.ToolHandlerMiddleware
+
+// Chain wraps handler in middleware. The first middleware is the outermost,
+// so it sees the call first and the result last.
+func Chain(handler server.ToolHandlerFunc, middleware ...Middleware) server.ToolHandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// Recovery turns a panicking tool call into an error result, so that one
+// broken handler can't take down the session. Handlers run in the goroutine
+// of the middleware above them, so Recovery must sit below Timeout.
+func Recovery(name string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := Recovered(name, func() (*mcp.CallToolResult, error) {
+				return next(ctx, request)
+			})
+			var p *PanicError
+			if errors.As(err, &p) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s failed unexpectedly, please report this issue", name)), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// PanicError is returned by Recovered when its function panics
+type PanicError struct {
+	Name  string
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Name, e.Value)
+}
+
+// Recovered calls fn and turns a panic into a *PanicError, logging the stack
+// trace. It guards handlers and goroutines outside a tool chain, such as
+// resources, prompts and the parallel calls of a tool.
+func Recovered[T any](name string, fn func() (T, error)) (result T, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("Recovered from panic",
+				slog.String("name", name),
+				slog.Any("panic", p),
+				slog.String("stack", string(debug.Stack())))
+			var zero T
+			result, err = zero, &PanicError{Name: name, Value: p}
+		}
+	}()
+	return fn()
+}
+
+// Logging logs each call with its redacted arguments and how it finished.
+// Successful calls are logged at debug level and failed calls at info level.
+func Logging(name string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			slog.DebugContext(ctx, "Tool call started",
+				slog.String("tool", name),
+				slog.Any("arguments", audit.RedactArguments(request.GetArguments())))
+
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			attrs := []any{slog.String("tool", name), slog.Duration("duration", time.Since(start))}
+			switch {
+			case err != nil:
+				slog.WarnContext(ctx, "Tool call failed", append(attrs, slog.Any("error", err))...)
+			case result != nil && result.IsError:
+				slog.InfoContext(ctx, "Tool call returned an error", append(attrs, slog.String("error", resultText(result)))...)
+			default:
+				slog.DebugContext(ctx, "Tool call finished", append(attrs, slog.Int("size", len(resultText(result))))...)
+			}
+			return result, err
+		}
+	}
+}
+
+// Permission rejects calls while check returns an error, such as when the
+// permissions a tool needs have been withdrawn
+func Permission(check func() error) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := check(); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// ProfileParam is the argument that selects a credential profile
+const ProfileParam = "profile"
+
+// Profile runs a tool with the Luno client of the profile named in its
+// profile argument, which must be one of names
+func Profile(names []string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := strings.ToLower(strings.TrimSpace(request.GetString(ProfileParam, "")))
+			if name != "" {
+				if !slices.Contains(names, name) {
+					return mcp.NewToolResultError(fmt.Sprintf("Unknown profile %q, must be one of %s",
+						name, strings.Join(names, ", "))), nil
+				}
+				ctx = sdk.WithProfile(ctx, name)
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// Session rate limits the calls of each MCP session and runs them with the
+// Luno client of the credentials the session sent, if it sent any. Calls
+// outside a session, such as in tests, are passed through.
+func Session(sessions *session.Manager) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			cs := server.ClientSessionFromContext(ctx)
+			if cs == nil {
+				return next(ctx, request)
+			}
+			s := sessions.Get(cs.SessionID())
+			if !s.Allow() {
+				return mcp.NewToolResultError(fmt.Sprintf("Too many tool calls from this session, the limit is %d a minute. Try again shortly.",
+					sessions.CallsPerMinute())), nil
+			}
+			ctx, err := withSessionClient(ctx, sessions, s)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Can't use the Luno API credentials sent by this client: %v", err)), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// SessionClient returns ctx with the Luno client of the MCP session it
+// belongs to, if the session uses its own, as Session does for tool calls.
+// Resource reads use it so that they see the same account as tools.
+func SessionClient(ctx context.Context, sessions *session.Manager) (context.Context, error) {
+	cs := server.ClientSessionFromContext(ctx)
+	if cs == nil {
+		return ctx, nil
+	}
+	ctx, err := withSessionClient(ctx, sessions, sessions.Get(cs.SessionID()))
+	if err != nil {
+		return nil, fmt.Errorf("can't use the Luno API credentials sent by this client: %w", err)
+	}
+	return ctx, nil
+}
+
+// withSessionClient gives s the client of any credentials sent with the
+// request, then sets the client s uses on ctx
+func withSessionClient(ctx context.Context, sessions *session.Manager, s *session.Session) (context.Context, error) {
+	if creds, ok := session.CredentialsFromContext(ctx); ok {
+		if err := sessions.UseCredentials(s, creds); err != nil {
+			return nil, err
+		}
+	}
+	if client := s.Client(); client != nil {
+		ctx = sdk.WithClient(ctx, client)
+	}
+	return ctx, nil
+}
+
+// Audit records every call of a tool that changes the account, including
+// calls that fail
+func Audit(log *audit.Log, name string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+
+			entry := audit.Entry{
+				Tool:      name,
+				Arguments: request.GetArguments(),
+			}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				entry.Session = session.SessionID()
+			}
+			if caller, ok := auth.FromContext(ctx); ok {
+				entry.Caller = caller.Subject
+			}
+			switch {
+			case err != nil:
+				entry.IsError = true
+				entry.Result = err.Error()
+			case result != nil:
+				entry.IsError = result.IsError
+				entry.Result = resultText(result)
+			}
+
+			if auditErr := log.Record(entry); auditErr != nil {
+				slog.Error("Failed to write audit log entry", slog.String("tool", name), slog.Any("error", auditErr))
+				if result != nil {
+					result.Content = append(result.Content, mcp.NewTextContent(
+						"Warning: this call could not be written to the audit log."))
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// TimeoutError is the result of a tool call that ran out of time
+type TimeoutError struct {
+	Error   string `json:"error"`
+	Tool    string `json:"tool"`
+	Timeout string `json:"timeout"`
+	Message string `json:"message"`
+}
+
+// Timeout stops waiting for a tool call after timeout and returns a timeout
+// error result instead. The call's context is cancelled so that pending Luno
+// API requests are abandoned, but a call that returns before the deadline
+// fires always gets its own result. write marks tools that change the account,
+// which may still have done so, and hint tells the user how to raise the
+// timeout.
+func Timeout(name string, timeout time.Duration, write bool, hint string) Middleware {
+	type response struct {
+		result *mcp.CallToolResult
+		err    error
+	}
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan response, 1)
+			go func() {
+				result, err := next(ctx, request)
+				done <- response{result, err}
+			}()
+
+			select {
+			case r := <-done:
+				return r.result, r.err
+			case <-ctx.Done():
+			}
+			// The handler may have finished just as the deadline fired
+			select {
+			case r := <-done:
+				return r.result, r.err
+			default:
+			}
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ctx.Err()
+			}
+
+			slog.Warn("Tool call timed out", slog.String("tool", name), slog.Duration("timeout", timeout))
+			return timeoutResult(name, timeout, write, hint), nil
+		}
+	}
+}
+
+// timeoutResult is the error result of a call that ran out of time
+func timeoutResult(name string, timeout time.Duration, write bool, hint string) *mcp.CallToolResult {
+	message := fmt.Sprintf("%s did not finish within %s, the Luno API may be slow. Try again, or %s.", name, timeout, hint)
+	if write {
+		// Retrying a write that is still running could make the change twice
+		message = fmt.Sprintf("%s did not finish within %s, the Luno API may be slow. "+
+			"It may still have changed your account, so check its outcome before doing it again. "+
+			"To wait longer, %s.", name, timeout, hint)
+	}
+	b, err := json.MarshalIndent(TimeoutError{
+		Error:   "timeout",
+		Tool:    name,
+		Timeout: timeout.String(),
+		Message: message,
+	}, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s", name, timeout))
+	}
+	return mcp.NewToolResultError(string(b))
+}
+
+// ClockSkew appends a clock skew hint to authentication error results when
+// the local clock differs significantly from Luno's
+func ClockSkew(tracker *sdk.ClockSkewTracker) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || !result.IsError || !isAuthError(result) {
+				return result, err
+			}
+			if hint := tracker.Hint(); hint != "" {
+				result.Content = append(result.Content, mcp.NewTextContent(hint))
+			}
+			return result, nil
+		}
+	}
+}
+
+// isAuthError reports whether an error result looks like an authentication failure
+func isAuthError(result *mcp.CallToolResult) bool {
+	return lunoerr.FromMessage(resultText(result)) == lunoerr.AuthFailed
+}
+
+// ErrorCodeKey is the result metadata key holding the code of an error result
+const ErrorCodeKey = "error_code"
+
+// ErrorCode adds a machine-readable lunoerr code to error results, both in
+// the result metadata and as a final "error_code: <code>" line of content,
+// since not every client shows metadata to the model
+func ErrorCode() Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || !result.IsError {
+				return result, err
+			}
+			if metaValue(result, ErrorCodeKey) != nil {
+				return result, nil
+			}
+			code := lunoerr.FromMessage(resultText(result))
+			setMeta(result, ErrorCodeKey, string(code))
+			result.Content = append(result.Content, mcp.NewTextContent(ErrorCodeKey+": "+string(code)))
+			return result, nil
+		}
+	}
+}
+
+// EnvironmentKey is the result metadata key holding the Luno environment
+const EnvironmentKey = "environment"
+
+// Environment labels every result with the Luno environment the call ran
+// against, in the result metadata and as an "environment: <name>" line of
+// content, so that staging results aren't mistaken for production ones
+func Environment(env string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil {
+				return result, err
+			}
+			setMeta(result, EnvironmentKey, env)
+			result.Content = append(result.Content, mcp.NewTextContent(EnvironmentKey+": "+env))
+			return result, nil
+		}
+	}
+}
+
+// TraceToolKey is the span attribute holding the name of a traced tool
+const TraceToolKey = attribute.Key("mcp.tool.name")
+
+// Tracing records each call as an OpenTelemetry span, with the tool name,
+// its pair argument and the error code of an error result. Luno API calls
+// made by the tool are traced as children of the span.
+func Tracing(tracer trace.Tracer, name string) Middleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx, span := tracer.Start(ctx, "tool "+name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(TraceToolKey.String(name)))
+			defer span.End()
+			if pair := request.GetString("pair", ""); pair != "" {
+				span.SetAttributes(sdk.TracePairKey.String(strings.ToUpper(pair)))
+			}
+
+			result, err := next(ctx, request)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				if code, ok := metaValue(result, ErrorCodeKey).(string); ok {
+					span.SetAttributes(sdk.TraceErrorCodeKey.String(code))
+				}
+				span.SetStatus(codes.Error, resultText(result))
+			}
+			return result, err
+		}
+	}
+}
+
+// metaValue returns a field of the result metadata, or nil if it isn't set
+func metaValue(result *mcp.CallToolResult, key string) any {
+	if result.Meta == nil {
+		return nil
+	}
+	return result.Meta.AdditionalFields[key]
+}
+
+// setMeta sets a field of the result metadata
+func setMeta(result *mcp.CallToolResult, key string, value any) {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[key] = value
+}
+
+// resultText joins the text content of a result
+func resultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
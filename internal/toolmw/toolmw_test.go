@@ -0,0 +1,507 @@
+package toolmw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/audit"
+	"github.com/luno/luno-mcp/internal/auth"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// textHandler returns a handler with a fixed text result
+func textHandler(text string) server.ToolHandlerFunc {
+	return func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				calls = append(calls, name)
+				return next(ctx, request)
+			}
+		}
+	}
+
+	handler := Chain(textHandler("ok"), record("outer"), record("inner"))
+	_, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"outer", "inner"}, calls)
+}
+
+func TestRecovery(t *testing.T) {
+	handler := Recovery("get_ticker")(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("nil map")
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "get_ticker failed unexpectedly")
+}
+
+func TestRecovered(t *testing.T) {
+	n, err := Recovered("count", func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = Recovered("count", func() (int, error) {
+		var m map[string]int
+		m["x"]++
+		return 2, nil
+	})
+	var p *PanicError
+	require.ErrorAs(t, err, &p)
+	require.Equal(t, "count", p.Name)
+	require.Zero(t, n)
+}
+
+func TestPermission(t *testing.T) {
+	var allowed error
+	handler := Permission(func() error { return allowed })(textHandler("ok"))
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	allowed = errors.New("create_order is not available")
+	result, err = handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Equal(t, "create_order is not available", result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	ok := m.Middleware("get_ticker")(textHandler("ok"))
+	failing := m.Middleware("get_ticker")(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("market not found"), nil
+	})
+
+	for _, h := range []server.ToolHandlerFunc{ok, ok, failing} {
+		_, err := h(context.Background(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+	}
+
+	stats := m.Snapshot()
+	require.Len(t, stats, 1)
+	require.Equal(t, int64(3), stats["get_ticker"].Calls)
+	require.Equal(t, int64(1), stats["get_ticker"].Errors)
+}
+
+func TestClockSkew(t *testing.T) {
+	skewed := sdk.NewClockSkewTracker(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		res := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}
+		res.Header.Set("Date", time.Now().Add(time.Hour).Format(http.TimeFormat))
+		return res, nil
+	}))
+	req, err := http.NewRequest(http.MethodGet, "https://api.luno.com/api/1/balance", nil)
+	require.NoError(t, err)
+	_, err = skewed.RoundTrip(req)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		tracker      *sdk.ClockSkewTracker
+		result       *mcp.CallToolResult
+		expectedHint bool
+	}{
+		{
+			name:         "auth error with skew gets hint",
+			tracker:      skewed,
+			result:       mcp.NewToolResultError("Failed to get balances: Unauthorized (ErrUnauthorised)"),
+			expectedHint: true,
+		},
+		{
+			name:    "auth error without skew",
+			tracker: sdk.NewClockSkewTracker(nil),
+			result:  mcp.NewToolResultError("Failed to get balances: Unauthorized (ErrUnauthorised)"),
+		},
+		{
+			name:    "other error with skew",
+			tracker: skewed,
+			result:  mcp.NewToolResultError("Failed to get ticker: market not found"),
+		},
+		{
+			name:    "success with skew",
+			tracker: skewed,
+			result:  mcp.NewToolResultText("ok"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := ClockSkew(tc.tracker)(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tc.result, nil
+			})
+
+			result, err := handler(context.Background(), mcp.CallToolRequest{})
+			require.NoError(t, err)
+			if tc.expectedHint {
+				require.Len(t, result.Content, 2)
+				require.Contains(t, result.Content[1].(mcp.TextContent).Text, "Your system clock is off by")
+				return
+			}
+			require.Len(t, result.Content, 1)
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name            string
+		arguments       map[string]any
+		expectedProfile string
+		errorContains   string
+	}{
+		{name: "no profile", arguments: map[string]any{}},
+		{name: "named profile", arguments: map[string]any{"profile": " Savings "}, expectedProfile: "savings"},
+		{name: "unknown profile", arguments: map[string]any{"profile": "corporate"}, errorContains: `Unknown profile "corporate", must be one of default, savings`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotProfile string
+			handler := Profile([]string{"default", "savings"})(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				gotProfile = sdk.ProfileFromContext(ctx)
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tc.arguments}})
+			require.NoError(t, err)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				require.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError)
+			require.Equal(t, tc.expectedProfile, gotProfile)
+		})
+	}
+}
+
+func TestAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { auditLog.Close() })
+
+	handler := Audit(auditLog, "cancel_order")(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("getting order_id from request: required argument not found"), nil
+	})
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "ops-agent", Mode: auth.ModeOIDC})
+	result, err := handler(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{
+		Name:      "cancel_order",
+		Arguments: map[string]any{"confirm_token": "one-time-token"},
+	}})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	n, err := audit.Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.Contains(t, string(data), `"tool":"cancel_order"`)
+	require.Contains(t, string(data), `"is_error":true`)
+	require.Contains(t, string(data), `"caller":"ops-agent"`)
+	require.Contains(t, string(data), "getting order_id from request")
+	require.NotContains(t, string(data), "one-time-token")
+
+	// A closed log doesn't hide the result, but the caller is warned
+	require.NoError(t, auditLog.Close())
+	result, err = handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 2)
+	require.Contains(t, result.Content[1].(mcp.TextContent).Text, "could not be written to the audit log")
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		handler         server.ToolHandlerFunc
+		write           bool
+		expectedText    string
+		expectedError   bool
+		expectedTimeout bool
+		expectedAdvice  string
+	}{
+		{
+			name: "finishes in time",
+			handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			},
+			expectedText: "ok",
+		},
+		{
+			name: "hangs",
+			handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				time.Sleep(time.Second)
+				return mcp.NewToolResultText("too late"), nil
+			},
+			expectedTimeout: true,
+			expectedAdvice:  "Try again, or raise it.",
+		},
+		{
+			name:  "write tool hangs",
+			write: true,
+			handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				time.Sleep(time.Second)
+				return mcp.NewToolResultText("too late"), nil
+			},
+			expectedTimeout: true,
+			expectedAdvice:  "check its outcome before doing it again. To wait longer, raise it.",
+		},
+		{
+			name: "fails in time",
+			handler: func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultError("getting ticker: invalid pair"), nil
+			},
+			expectedText:  "getting ticker: invalid pair",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := Timeout("get_ticker", 20*time.Millisecond, tc.write, "raise it")(tc.handler)
+
+			result, err := handler(context.Background(), mcp.CallToolRequest{})
+			require.NoError(t, err)
+			text := result.Content[0].(mcp.TextContent).Text
+			if !tc.expectedTimeout {
+				require.Equal(t, tc.expectedError, result.IsError)
+				require.Equal(t, tc.expectedText, text)
+				return
+			}
+			require.True(t, result.IsError)
+			var timeoutErr TimeoutError
+			require.NoError(t, json.Unmarshal([]byte(text), &timeoutErr))
+			require.Equal(t, TimeoutError{
+				Error:   "timeout",
+				Tool:    "get_ticker",
+				Timeout: "20ms",
+				Message: timeoutErr.Message,
+			}, timeoutErr)
+			require.Contains(t, timeoutErr.Message, tc.expectedAdvice)
+			if tc.write {
+				require.NotContains(t, timeoutErr.Message, "Try again")
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// testSession is a client session with a fixed ID
+type testSession string
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return string(s) }
+
+func TestSession(t *testing.T) {
+	sessions := session.NewManager(2)
+	own := sdk.NewMockLunoClient(t)
+	sessions.Get("s2").SetClient(own)
+
+	var gotClient sdk.LunoClient
+	handler := Session(sessions)(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		gotClient = sdk.ClientFromContext(ctx)
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv := server.NewMCPServer("test", "1.0.0")
+	call := func(ctx context.Context) *mcp.CallToolResult {
+		result, err := handler(ctx, mcp.CallToolRequest{})
+		require.NoError(t, err)
+		return result
+	}
+
+	// Calls outside a session are not limited
+	for i := 0; i < 3; i++ {
+		require.False(t, call(context.Background()).IsError)
+	}
+
+	s1 := srv.WithContext(context.Background(), testSession("s1"))
+	require.False(t, call(s1).IsError)
+	require.Nil(t, gotClient)
+	require.False(t, call(s1).IsError)
+	result := call(s1)
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "the limit is 2 a minute")
+
+	// Other sessions have their own limit and client
+	s2 := srv.WithContext(context.Background(), testSession("s2"))
+	require.False(t, call(s2).IsError)
+	require.Equal(t, own, gotClient)
+}
+
+func TestSessionCredentials(t *testing.T) {
+	sessions := session.NewManager(0)
+	var made []session.Credentials
+	sessions.SetClientFactory(func(c session.Credentials) (sdk.LunoClient, error) {
+		made = append(made, c)
+		return sdk.NewMockLunoClient(t), nil
+	})
+
+	var gotClient sdk.LunoClient
+	handler := Session(sessions)(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		gotClient = sdk.ClientFromContext(ctx)
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv := server.NewMCPServer("test", "1.0.0")
+	creds := session.Credentials{KeyID: "key", Secret: "secret"}
+
+	// Sessions that send the same credentials share their client
+	s1 := session.WithCredentials(srv.WithContext(context.Background(), testSession("s1")), creds)
+	result, err := handler(s1, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	first := gotClient
+	require.NotNil(t, first)
+
+	s2 := session.WithCredentials(srv.WithContext(context.Background(), testSession("s2")), creds)
+	_, err = handler(s2, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.Same(t, first, gotClient)
+	require.Equal(t, []session.Credentials{creds}, made)
+
+	// A server that doesn't accept credentials refuses the call
+	result, err = Session(session.NewManager(0))(handler)(s1, mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	require.Contains(t, result.Content[0].(mcp.TextContent).Text, "doesn't accept Luno API credentials")
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       *mcp.CallToolResult
+		expectedCode string
+	}{
+		{"api error", mcp.NewToolResultError("Failed to create order: Insufficient balance (ErrInsufficientBalance)"), "insufficient_balance"},
+		{"unknown error", mcp.NewToolResultError("Something else went wrong"), "unknown"},
+		{"success", mcp.NewToolResultText("ok"), ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := ErrorCode()(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tc.result, nil
+			})
+			result, err := handler(context.Background(), mcp.CallToolRequest{})
+			require.NoError(t, err)
+			if tc.expectedCode == "" {
+				require.Nil(t, result.Meta)
+				require.Len(t, result.Content, 1)
+				return
+			}
+			require.Equal(t, tc.expectedCode, result.Meta.AdditionalFields[ErrorCodeKey])
+			require.Len(t, result.Content, 2)
+			require.Equal(t, "error_code: "+tc.expectedCode, result.Content[1].(mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResult
+	}{
+		{"success", mcp.NewToolResultText("ok")},
+		{"error", mcp.NewToolResultError("Failed to create order")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := Chain(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tc.result, nil
+			}, ErrorCode(), Environment("staging"))
+			result, err := handler(context.Background(), mcp.CallToolRequest{})
+			require.NoError(t, err)
+			require.Equal(t, "staging", result.Meta.AdditionalFields[EnvironmentKey])
+			require.Equal(t, "environment: staging", result.Content[1].(mcp.TextContent).Text)
+			if result.IsError {
+				// The error code stays the last line
+				require.Len(t, result.Content, 3)
+			}
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       *mcp.CallToolResult
+		err          error
+		expectedCode codes.Code
+		expectedErr  string
+	}{
+		{name: "success", result: mcp.NewToolResultText("ok")},
+		{
+			name:         "error result",
+			result:       mcp.NewToolResultError("Failed to get ticker: market unavailable (ErrMarketUnavailable)"),
+			expectedCode: codes.Error,
+			expectedErr:  "market_unavailable",
+		},
+		{name: "handler error", err: errors.New("boom"), expectedCode: codes.Error},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+			handler := Chain(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				// Work done by the tool is traced under the tool's span
+				_, span := tracer.Start(ctx, "child")
+				span.End()
+				return tc.result, tc.err
+			}, Tracing(tracer, "get_ticker"), ErrorCode())
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]any{"pair": "xbtzar"}
+			_, _ = handler(context.Background(), request)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 2)
+			child, span := spans[0], spans[1]
+			require.Equal(t, "tool get_ticker", span.Name())
+			require.Equal(t, span.SpanContext().SpanID(), child.Parent().SpanID())
+			require.Equal(t, tc.expectedCode, span.Status().Code)
+
+			attrs := attribute.NewSet(span.Attributes()...)
+			tool, _ := attrs.Value(TraceToolKey)
+			require.Equal(t, "get_ticker", tool.AsString())
+			pair, _ := attrs.Value(sdk.TracePairKey)
+			require.Equal(t, "XBTZAR", pair.AsString())
+			code, _ := attrs.Value(sdk.TraceErrorCodeKey)
+			require.Equal(t, tc.expectedErr, code.AsString())
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// accountAssetParam is the argument that picks an account by its asset,
+// instead of by its ID
+const accountAssetParam = "asset"
+
+// withAccount adds the account_id argument, and the asset argument that may
+// be given instead of it
+func withAccount() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString(
+			"account_id",
+			mcp.Description("Account ID, from get_balances. Either this or asset is required."),
+		)(t)
+		mcp.WithString(
+			accountAssetParam,
+			mcp.Description("Asset of the account (e.g., ZAR or BTC), instead of account_id. "+
+				"Only works when you have a single account in the asset."),
+		)(t)
+	}
+}
+
+// resolveAccountID reads the account_id argument or, when that is empty,
+// looks up the account holding the asset argument in the balances
+func resolveAccountID(ctx context.Context, cfg *config.Config, request mcp.CallToolRequest) (int64, error) {
+	if accountIDStr := request.GetString("account_id", ""); accountIDStr != "" {
+		accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid account ID format: %v. Please provide a valid numeric account ID.", err)
+		}
+		return accountID, nil
+	}
+
+	asset := request.GetString(accountAssetParam, "")
+	if strings.TrimSpace(asset) == "" {
+		return 0, lunoerr.New(lunoerr.InvalidArgument, "either account_id or %s is required", accountAssetParam)
+	}
+	balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get balances: %w", err)
+	}
+	return accountForAsset(balances.Balance, asset)
+}
+
+// accountForAsset returns the ID of the only account holding the asset.
+// When there is no such account the error lists the assets there are
+// accounts for, and when there are several it lists those accounts.
+func accountForAsset(balances []luno.AccountBalance, asset string) (int64, error) {
+	code := market.NormalizeAsset(asset)
+	var (
+		matches []luno.AccountBalance
+		assets  []string
+	)
+	for _, balance := range balances {
+		if balance.Asset == code {
+			matches = append(matches, balance)
+		}
+		if !slices.Contains(assets, balance.Asset) {
+			assets = append(assets, balance.Asset)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		slices.Sort(assets)
+		return 0, lunoerr.New(lunoerr.InvalidArgument, "no account holds %s, you have accounts for: %s", code, strings.Join(assets, ", "))
+	case 1:
+		accountID, err := strconv.ParseInt(matches[0].AccountId, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid account ID %q for %s: %w", matches[0].AccountId, code, err)
+		}
+		return accountID, nil
+	default:
+		accounts := make([]string, 0, len(matches))
+		for _, match := range matches {
+			if match.Name != "" {
+				accounts = append(accounts, fmt.Sprintf("%s (%s)", match.AccountId, match.Name))
+			} else {
+				accounts = append(accounts, match.AccountId)
+			}
+		}
+		return 0, lunoerr.New(lunoerr.InvalidArgument, "you have %d %s accounts, give one of their IDs as account_id: %s",
+			len(matches), code, strings.Join(accounts, ", "))
+	}
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountForAsset(t *testing.T) {
+	balances := []luno.AccountBalance{
+		{AccountId: "100", Asset: "ZAR"},
+		{AccountId: "200", Asset: "XBT"},
+		{AccountId: "201", Asset: "XBT", Name: "Savings"},
+		{AccountId: "300", Asset: "ETH"},
+	}
+
+	tests := []struct {
+		name          string
+		asset         string
+		expectedID    int64
+		expectedError string
+	}{
+		{name: "single account", asset: "ZAR", expectedID: 100},
+		{name: "alias", asset: "ethereum", expectedID: 300},
+		{name: "several accounts", asset: "BTC", expectedError: "you have 2 XBT accounts, give one of their IDs as account_id: 200, 201 (Savings)"},
+		{name: "no account", asset: "SOL", expectedError: "no account holds SOL, you have accounts for: ETH, XBT, ZAR"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := accountForAsset(balances, tc.asset)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Equal(t, lunoerr.InvalidArgument, lunoerr.Classify(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedID, id)
+		})
+	}
+}
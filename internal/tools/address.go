@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ValidateAddressToolID is the ID of the address validation tool
+const ValidateAddressToolID = "validate_address"
+
+// AddressValidation is the outcome of checking an address before a send
+type AddressValidation struct {
+	Currency string `json:"currency"`
+	Address  string `json:"address"`
+	// Valid is set when no check found a problem with the address
+	Valid bool `json:"valid"`
+	// Format is the address format matched by the local check
+	Format string `json:"format,omitempty"`
+	// LocalCheck and LunoCheck are passed, failed, skipped or unavailable
+	LocalCheck string   `json:"local_check"`
+	LunoCheck  string   `json:"luno_check"`
+	Problems   []string `json:"problems,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// Address check outcomes
+const (
+	checkPassed      = "passed"
+	checkFailed      = "failed"
+	checkSkipped     = "skipped"
+	checkUnavailable = "unavailable"
+)
+
+// NewValidateAddressTool creates a new tool for checking a crypto address
+func NewValidateAddressTool() mcp.Tool {
+	return mcp.NewTool(
+		ValidateAddressToolID,
+		mcp.WithDescription("Check that a crypto address, and any destination tag or memo it needs, is valid before sending to it. "+
+			"The address is checked locally against the chain's format and checksum, then by Luno. Nothing is sent."),
+		mcp.WithString(
+			"currency",
+			mcp.Required(),
+			mcp.Description("Currency to send (e.g., XBT, ETH, XRP)"),
+		),
+		mcp.WithString(
+			"address",
+			mcp.Required(),
+			mcp.Description("Destination address"),
+		),
+		mcp.WithNumber(
+			"destination_tag",
+			mcp.Description("XRP destination tag, required by most exchanges"),
+		),
+		mcp.WithString(
+			"memo",
+			mcp.Description("Memo for chains that identify the receiving account with one"),
+		),
+		mcp.WithBoolean(
+			"is_self_send",
+			mcp.Description("Whether the address belongs to you, e.g. your wallet at another exchange. "+
+				"Luno may ask for details of the owner of other addresses."),
+		),
+		mcp.WithBoolean(
+			"is_private_wallet",
+			mcp.Description("Whether the address is a private wallet rather than one held at an exchange"),
+		),
+	)
+}
+
+// HandleValidateAddress handles the validate_address tool
+func HandleValidateAddress(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		currency, err := request.RequireString("currency")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting currency from request", err), nil
+		}
+		address, err := request.RequireString("address")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting address from request", err), nil
+		}
+		currency = market.NormalizePair(strings.TrimSpace(currency))
+		address = strings.TrimSpace(address)
+		if address == "" {
+			return mcp.NewToolResultError("address must not be empty"), nil
+		}
+		if asset, ok := market.FindAsset(currency); ok && asset.Fiat {
+			return mcp.NewToolResultError(fmt.Sprintf("%s is a fiat currency and has no crypto addresses", currency)), nil
+		}
+
+		req := &luno.ValidateRequest{
+			Address:         address,
+			Currency:        currency,
+			Memo:            strings.TrimSpace(request.GetString("memo", "")),
+			IsSelfSend:      request.GetBool("is_self_send", false),
+			IsPrivateWallet: request.GetBool("is_private_wallet", false),
+		}
+		if _, ok := request.GetArguments()["destination_tag"]; ok {
+			tag := request.GetFloat("destination_tag", -1)
+			if tag < 0 || tag > 4294967295 || tag != float64(int64(tag)) {
+				return mcp.NewToolResultError("destination_tag must be a whole number from 0 to 4294967295"), nil
+			}
+			req.DestinationTag, req.HasDestinationTag = int64(tag), true
+		}
+
+		validation := validateAddress(ctx, cfg, req)
+
+		resultJSON, err := json.MarshalIndent(validation, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal address validation: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// validateAddress checks the address locally and, if that finds no problem,
+// with Luno
+func validateAddress(ctx context.Context, cfg *config.Config, req *luno.ValidateRequest) AddressValidation {
+	v := AddressValidation{Currency: req.Currency, Address: req.Address}
+
+	format, ok, err := checkAddressFormat(req.Currency, req.Address)
+	switch {
+	case !ok:
+		v.LocalCheck = checkUnavailable
+		v.Warnings = append(v.Warnings, fmt.Sprintf("%s addresses can't be checked locally, only by Luno", req.Currency))
+	case err != nil:
+		v.LocalCheck = checkFailed
+		v.Problems = append(v.Problems, err.Error())
+	default:
+		v.LocalCheck = checkPassed
+		v.Format = format
+	}
+
+	if slices.Contains(tagAssets, req.Currency) && !req.HasDestinationTag {
+		v.Warnings = append(v.Warnings, "no destination_tag given, sends to an exchange are lost without the tag it gave you")
+	}
+	if req.Currency == "ETH" && format == hexFormat.name {
+		if hex := req.Address[2:]; hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+			v.Warnings = append(v.Warnings, "the address has no EIP-55 checksum, Luno only accepts checksummed Ethereum addresses")
+		}
+	}
+
+	if v.LocalCheck == checkFailed {
+		v.LunoCheck = checkSkipped
+		return v
+	}
+	res, err := cfg.LunoClient.Validate(ctx, req)
+	switch {
+	case err == nil && res.Success:
+		v.LunoCheck = checkPassed
+	case err == nil:
+		v.LunoCheck = checkFailed
+		v.Problems = append(v.Problems, "Luno did not accept the address")
+	case lunoerr.Classify(err) == lunoerr.InvalidArgument:
+		v.LunoCheck = checkFailed
+		v.Problems = append(v.Problems, fmt.Sprintf("Luno rejected the address: %v", err))
+	default:
+		// The address may be fine, e.g. the API key lacks the send permission
+		v.LunoCheck = checkUnavailable
+		v.Warnings = append(v.Warnings, fmt.Sprintf("Luno could not check the address: %v", err))
+	}
+
+	v.Valid = len(v.Problems) == 0
+	return v
+}
+
+// Base58 alphabets. XRP uses its own ordering of the same characters.
+const (
+	bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	rippleAlphabet  = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+)
+
+// bech32Charset maps 5 bit values to bech32 characters
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Checksum constants of bech32, used by segwit version 0, and bech32m,
+// used by later versions
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// addressFormat is one way of writing an address on a chain
+type addressFormat struct {
+	name  string
+	check func(address string) error
+}
+
+// addressFormats are the address formats of each asset whose addresses can
+// be checked locally. An address is valid if it passes one of them.
+var addressFormats = map[string][]addressFormat{
+	"XBT":  {segwitFormat("bc"), base58CheckFormat(bitcoinAlphabet, 0x00, 0x05)},
+	"LTC":  {segwitFormat("ltc"), base58CheckFormat(bitcoinAlphabet, 0x30, 0x32, 0x05)},
+	"DOGE": {base58CheckFormat(bitcoinAlphabet, 0x1e, 0x16)},
+	"XRP":  {base58CheckFormat(rippleAlphabet, 0x00)},
+	"TRX":  {base58CheckFormat(bitcoinAlphabet, 0x41)},
+	"ETH":  {hexFormat},
+	"SOL":  {{name: "base58", check: checkSolanaAddress}},
+}
+
+// tagAssets need a destination tag or memo when sending to an exchange or
+// other shared address
+var tagAssets = []string{"XRP"}
+
+// hexAddressPattern matches an Ethereum style address
+var hexAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// hexFormat checks the shape of an Ethereum style address. Its EIP-55
+// checksum needs keccak, so it is left to Luno.
+var hexFormat = addressFormat{name: "hex", check: func(address string) error {
+	if !hexAddressPattern.MatchString(address) {
+		return errors.New("not 0x followed by 40 hex digits")
+	}
+	return nil
+}}
+
+// checkAddressFormat checks an address against the formats of its asset. It
+// returns the name of the format it matched, or ok false when the asset's
+// addresses can't be checked locally.
+func checkAddressFormat(currency, address string) (format string, ok bool, err error) {
+	formats, ok := addressFormats[currency]
+	if !ok {
+		return "", false, nil
+	}
+	var problems []string
+	for _, f := range formats {
+		err := f.check(address)
+		if err == nil {
+			return f.name, true, nil
+		}
+		problems = append(problems, fmt.Sprintf("%s: %v", f.name, err))
+	}
+	return "", true, fmt.Errorf("not a valid %s address (%s)", currency, strings.Join(problems, "; "))
+}
+
+// base58CheckFormat accepts base58check addresses with one of the version bytes
+func base58CheckFormat(alphabet string, versions ...byte) addressFormat {
+	return addressFormat{name: "base58", check: func(address string) error {
+		payload, err := base58Decode(address, alphabet)
+		if err != nil {
+			return err
+		}
+		if len(payload) != 25 {
+			return fmt.Errorf("decodes to %d bytes instead of 25", len(payload))
+		}
+		body, checksum := payload[:21], payload[21:]
+		first := sha256.Sum256(body)
+		second := sha256.Sum256(first[:])
+		if !bytes.Equal(second[:4], checksum) {
+			return errors.New("checksum does not match, a character may be mistyped")
+		}
+		if !slices.Contains(versions, body[0]) {
+			return fmt.Errorf("version byte 0x%02x is not used on this chain", body[0])
+		}
+		return nil
+	}}
+}
+
+// base58Decode decodes s in the alphabet, keeping leading zero bytes
+func base58Decode(s, alphabet string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty")
+	}
+	n := new(big.Int)
+	base := big.NewInt(58)
+	for _, r := range s {
+		i := strings.IndexRune(alphabet, r)
+		if i < 0 {
+			return nil, fmt.Errorf("%q is not a base58 character", r)
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// checkSolanaAddress accepts a base58 encoded 32 byte public key
+func checkSolanaAddress(address string) error {
+	key, err := base58Decode(address, bitcoinAlphabet)
+	if err != nil {
+		return err
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("decodes to %d bytes instead of 32", len(key))
+	}
+	return nil
+}
+
+// segwitFormat accepts bech32 segwit addresses with the human readable part
+func segwitFormat(hrp string) addressFormat {
+	return addressFormat{name: "bech32", check: func(address string) error {
+		lower := strings.ToLower(address)
+		sep := strings.LastIndexByte(lower, '1')
+		if sep < 1 || lower[:sep] != hrp {
+			return fmt.Errorf("does not start with %s1", hrp)
+		}
+		if address != lower && address != strings.ToUpper(address) {
+			return errors.New("mixes upper and lower case")
+		}
+		address = lower
+		data := make([]byte, 0, len(address)-sep-1)
+		for _, r := range address[sep+1:] {
+			i := strings.IndexRune(bech32Charset, r)
+			if i < 0 {
+				return fmt.Errorf("%q is not a bech32 character", r)
+			}
+			data = append(data, byte(i))
+		}
+		if len(data) < 7 {
+			return errors.New("too short")
+		}
+		// Version 0 programs use bech32 and later versions bech32m
+		want := bech32Const
+		if data[0] > 0 {
+			want = bech32mConst
+		}
+		if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != want {
+			return errors.New("checksum does not match, a character may be mistyped")
+		}
+		return nil
+	}}
+}
+
+// bech32Polymod computes the bech32 checksum of values
+func bech32Polymod(values []byte) int {
+	gen := [5]int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := 1
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ int(v)
+		for i := range gen {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human readable part for the checksum
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := range len(hrp) {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := range len(hrp) {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAddressFormat(t *testing.T) {
+	tests := []struct {
+		currency string
+		address  string
+		format   string
+		ok       bool
+		errMsg   string
+	}{
+		{"XBT", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "bech32", true, ""},
+		{"XBT", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", "bech32", true, ""},
+		{"XBT", "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297", "bech32", true, ""},
+		{"XBT", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "base58", true, ""},
+		{"XBT", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", "base58", true, ""},
+		{"XBT", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", "", true, "checksum does not match"},
+		{"XBT", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdr", "", true, "checksum does not match"},
+		{"XBT", "bc1qAr0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "", true, "mixes upper and lower case"},
+		{"XBT", "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz", "", true, "version byte 0x30 is not used on this chain"},
+		{"LTC", "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz", "base58", true, ""},
+		{"DOGE", "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", "base58", true, ""},
+		{"XRP", "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "base58", true, ""},
+		{"XRP", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "", true, "not a valid XRP address"},
+		{"TRX", "TLa2f6VPqDgRE67v1736s7bJ8Ray5wYjU7", "base58", true, ""},
+		{"SOL", "So11111111111111111111111111111111111111112", "base58", true, ""},
+		{"SOL", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "", true, "decodes to 25 bytes instead of 32"},
+		{"ETH", "0x52908400098527886E0F7030069857D2E4169EE7", "hex", true, ""},
+		{"ETH", "0x52908400098527886E0F7030069857D2E4169EE", "", true, "not 0x followed by 40 hex digits"},
+		{"ADA", "addr1anything", "", false, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.currency+" "+tc.address, func(t *testing.T) {
+			format, ok, err := checkAddressFormat(tc.currency, tc.address)
+			assert.Equal(t, tc.ok, ok)
+			if tc.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.format, format)
+		})
+	}
+}
+
+func TestHandleValidateAddress(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expected      AddressValidation
+		warning       string
+		errorContains string
+	}{
+		{
+			name:   "valid",
+			params: map[string]any{"currency": "btc", "address": "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", "is_self_send": true},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Validate(mock.Anything, &luno.ValidateRequest{
+					Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", Currency: "XBT", IsSelfSend: true,
+				}).Return(&luno.ValidateResponse{Success: true}, nil)
+			},
+			expected: AddressValidation{
+				Currency: "XBT", Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", Valid: true,
+				Format: "bech32", LocalCheck: checkPassed, LunoCheck: checkPassed,
+			},
+		},
+		{
+			name:      "mistyped address is not sent to Luno",
+			params:    map[string]any{"currency": "XBT", "address": "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3"},
+			mockSetup: func(m *sdk.MockLunoClient) {},
+			expected: AddressValidation{
+				Currency: "XBT", Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3",
+				LocalCheck: checkFailed, LunoCheck: checkSkipped,
+			},
+		},
+		{
+			name:   "destination tag",
+			params: map[string]any{"currency": "XRP", "address": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "destination_tag": float64(0)},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Validate(mock.Anything, &luno.ValidateRequest{
+					Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Currency: "XRP", HasDestinationTag: true,
+				}).Return(&luno.ValidateResponse{Success: true}, nil)
+			},
+			expected: AddressValidation{
+				Currency: "XRP", Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Valid: true,
+				Format: "base58", LocalCheck: checkPassed, LunoCheck: checkPassed,
+			},
+		},
+		{
+			name:   "missing destination tag",
+			params: map[string]any{"currency": "XRP", "address": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Validate(mock.Anything, mock.Anything).Return(&luno.ValidateResponse{Success: true}, nil)
+			},
+			expected: AddressValidation{
+				Currency: "XRP", Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Valid: true,
+				Format: "base58", LocalCheck: checkPassed, LunoCheck: checkPassed,
+			},
+			warning: "no destination_tag given",
+		},
+		{
+			name:   "Luno rejects",
+			params: map[string]any{"currency": "ETH", "address": "0x52908400098527886E0F7030069857D2E4169EE7"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Validate(mock.Anything, mock.Anything).Return(nil, luno.Error{Code: "ErrInvalidAddress", Message: "Invalid address"})
+			},
+			expected: AddressValidation{
+				Currency: "ETH", Address: "0x52908400098527886E0F7030069857D2E4169EE7",
+				Format: "hex", LocalCheck: checkPassed, LunoCheck: checkFailed,
+			},
+		},
+		{
+			name:   "Luno unavailable",
+			params: map[string]any{"currency": "ETH", "address": "0x52908400098527886e0f7030069857d2e4169ee7"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Validate(mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			expected: AddressValidation{
+				Currency: "ETH", Address: "0x52908400098527886e0f7030069857d2e4169ee7", Valid: true,
+				Format: "hex", LocalCheck: checkPassed, LunoCheck: checkUnavailable,
+			},
+			warning: "no EIP-55 checksum",
+		},
+		{
+			name:   "no local check",
+			params: map[string]any{"currency": "ADA", "address": "addr1example"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Validate(mock.Anything, mock.Anything).Return(&luno.ValidateResponse{Success: true}, nil)
+			},
+			expected: AddressValidation{
+				Currency: "ADA", Address: "addr1example", Valid: true,
+				LocalCheck: checkUnavailable, LunoCheck: checkPassed,
+			},
+			warning: "ADA addresses can't be checked locally",
+		},
+		{
+			name:          "fiat currency",
+			params:        map[string]any{"currency": "ZAR", "address": "123"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "ZAR is a fiat currency",
+		},
+		{
+			name:          "invalid destination tag",
+			params:        map[string]any{"currency": "XRP", "address": "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "destination_tag": 1.5},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "destination_tag must be a whole number",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleValidateAddress(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError, text)
+
+			var got AddressValidation
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			assert.Equal(t, tc.expected.Valid, len(got.Problems) == 0)
+			if tc.warning != "" {
+				assert.Contains(t, strings.Join(got.Warnings, "\n"), tc.warning)
+			}
+			got.Problems, got.Warnings = nil, nil
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Price alert tool IDs
+const (
+	CreatePriceAlertToolID = "create_price_alert"
+	ListPriceAlertsToolID  = "list_price_alerts"
+	DeletePriceAlertToolID = "delete_price_alert"
+)
+
+// NewCreatePriceAlertTool creates a new tool for creating price alerts
+func NewCreatePriceAlertTool() mcp.Tool {
+	return mcp.NewTool(
+		CreatePriceAlertToolID,
+		mcp.WithDescription("Create an alert that notifies this session when a pair's last trade price crosses a threshold. "+
+			"Prices are checked every few seconds, and an alert fires once and is then removed. "+
+			"Notifications arrive as MCP log messages while the session is connected."),
+		mcp.WithString(
+			"pair",
+			mcp.Required(),
+			mcp.Description("Trading pair (e.g., XBTZAR)"),
+		),
+		mcp.WithString(
+			"condition",
+			mcp.Required(),
+			mcp.Description("Fire when the price is at or above the threshold, or at or below it"),
+			mcp.Enum(string(alerts.ConditionAbove), string(alerts.ConditionBelow)),
+		),
+		mcp.WithString(
+			"price",
+			mcp.Required(),
+			mcp.Description("Threshold price in the counter currency as a decimal string"),
+		),
+	)
+}
+
+// HandleCreatePriceAlert handles the create_price_alert tool
+func HandleCreatePriceAlert(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Alerts == nil {
+			return mcp.NewToolResultError("Price alerts are not enabled"), nil
+		}
+
+		pair, err := request.RequireString("pair")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pair from request", err), nil
+		}
+		pair = market.NormalizePair(pair)
+
+		conditionStr, err := request.RequireString("condition")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting condition from request", err), nil
+		}
+		condition, err := alerts.ParseCondition(conditionStr)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid condition", err), nil
+		}
+
+		priceStr, err := request.RequireString("price")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting price from request", err), nil
+		}
+		price, err := decimal.NewFromString(priceStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid price format: %v", err)), nil
+		}
+
+		if _, err := ValidatePair(ctx, cfg, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create alert: %v", err)), nil
+		}
+
+		alert, err := cfg.Alerts.Add(sessionID(ctx), pair, condition, price)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create alert: %v", err)), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(alert, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal alert: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// NewListPriceAlertsTool creates a new tool for listing price alerts
+func NewListPriceAlertsTool() mcp.Tool {
+	return mcp.NewTool(
+		ListPriceAlertsToolID,
+		mcp.WithDescription("List this session's price alerts that have not fired yet"),
+	)
+}
+
+// HandleListPriceAlerts handles the list_price_alerts tool
+func HandleListPriceAlerts(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Alerts == nil {
+			return mcp.NewToolResultError("Price alerts are not enabled"), nil
+		}
+
+		list := cfg.Alerts.List(sessionID(ctx))
+		if list == nil {
+			list = []alerts.Alert{}
+		}
+		resultJSON, err := json.MarshalIndent(map[string]any{"alerts": list}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal alerts: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// NewDeletePriceAlertTool creates a new tool for deleting price alerts
+func NewDeletePriceAlertTool() mcp.Tool {
+	return mcp.NewTool(
+		DeletePriceAlertToolID,
+		mcp.WithDescription(fmt.Sprintf("Delete one of this session's price alerts. It can be brought back with restore for %.0f hours.", alerts.Retention.Hours())),
+		mcp.WithString(
+			"id",
+			mcp.Required(),
+			mcp.Description("Alert ID from create_price_alert or list_price_alerts"),
+		),
+	)
+}
+
+// HandleDeletePriceAlert handles the delete_price_alert tool
+func HandleDeletePriceAlert(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Alerts == nil {
+			return mcp.NewToolResultError("Price alerts are not enabled"), nil
+		}
+
+		id, err := request.RequireString("id")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting id from request", err), nil
+		}
+		if !cfg.Alerts.Delete(sessionID(ctx), id) {
+			return mcp.NewToolResultError(fmt.Sprintf("No price alert with ID %s, it may have already fired", id)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Price alert %s deleted. Restore it with the restore tool within %.0f hours if this was a mistake.", id, alerts.Retention.Hours())), nil
+	}
+}
+
+// sessionID returns the ID of the MCP session making a request, or an empty
+// string when there is none
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
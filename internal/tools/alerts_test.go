@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreatePriceAlert(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		errorContains string
+	}{
+		{
+			name:   "alert created",
+			params: map[string]any{"pair": "btczar", "condition": "above", "price": "1000000"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+		},
+		{
+			name:          "invalid condition",
+			params:        map[string]any{"pair": "XBTZAR", "condition": "near", "price": "1000000"},
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			errorContains: "invalid condition",
+		},
+		{
+			name:          "invalid price",
+			params:        map[string]any{"pair": "XBTZAR", "condition": "below", "price": "cheap"},
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			errorContains: "Invalid price format",
+		},
+		{
+			name:   "unknown pair",
+			params: map[string]any{"pair": "DOGEZAR", "condition": "below", "price": "1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			errorContains: "Unable to create alert",
+		},
+		{
+			name:   "price must be positive",
+			params: map[string]any{"pair": "XBTZAR", "condition": "below", "price": "0"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			errorContains: "greater than zero",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+			cfg := &config.Config{LunoClient: mockClient, Alerts: alerts.NewRegistry(alerts.DefaultMaxPerSession)}
+
+			result, err := HandleCreatePriceAlert(cfg)(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				assert.Empty(t, cfg.Alerts.List(""))
+				return
+			}
+			require.False(t, result.IsError, text)
+			var alert alerts.Alert
+			require.NoError(t, json.Unmarshal([]byte(text), &alert))
+			assert.Equal(t, "XBTZAR", alert.Pair)
+			assert.Equal(t, alerts.ConditionAbove, alert.Condition)
+			assert.Len(t, cfg.Alerts.List(""), 1)
+		})
+	}
+}
+
+func TestListAndDeletePriceAlerts(t *testing.T) {
+	cfg := &config.Config{Alerts: alerts.NewRegistry(alerts.DefaultMaxPerSession)}
+	ctx := context.Background()
+
+	list := func() []alerts.Alert {
+		result, err := HandleListPriceAlerts(cfg)(ctx, createMockRequest(nil))
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		var got struct {
+			Alerts []alerts.Alert `json:"alerts"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+		return got.Alerts
+	}
+
+	assert.Empty(t, list())
+
+	alert, err := cfg.Alerts.Add("", "XBTZAR", alerts.ConditionBelow, NewFromString(t, "900000"))
+	require.NoError(t, err)
+	require.Len(t, list(), 1)
+
+	result, err := HandleDeletePriceAlert(cfg)(ctx, createMockRequest(map[string]any{"id": alert.ID}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Empty(t, list())
+
+	result, err = HandleDeletePriceAlert(cfg)(ctx, createMockRequest(map[string]any{"id": alert.ID}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, getTextContentFromResult(t, result), "No price alert with ID")
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/market"
+)
+
+// amountPattern splits a human readable amount into its number, multiplier
+// suffix and currency code, e.g. "1.5k ZAR" or "0.5 BTC"
+var amountPattern = regexp.MustCompile(`^(-?[0-9][0-9,_ ]*(?:\.[0-9]+)?|-?\.[0-9]+)\s*(?:((?i:k|mn|m|bn))\b)?\s*([A-Za-z]+)?$`)
+
+// amountMultipliers are the accepted multiplier suffixes, in lower case
+var amountMultipliers = map[string]int64{
+	"k":  1_000,
+	"m":  1_000_000,
+	"mn": 1_000_000,
+	"bn": 1_000_000_000,
+}
+
+// currencySymbols maps the symbols an amount may start with to the Luno
+// code of their currency. Longer symbols come first so RM isn't read as R.
+var currencySymbols = []struct{ symbol, currency string }{
+	{"RM", "MYR"},
+	{"R", "ZAR"},
+	{"$", "USD"},
+	{"€", "EUR"},
+	{"£", "GBP"},
+	{"₦", "NGN"},
+	{"₿", "XBT"},
+}
+
+// amountDesc describes the accepted amount formats in tool parameters
+const amountDesc = "A number such as 5000, 5,000, 1.5k or 1m, optionally with the currency as in R5000 or 0.5 BTC"
+
+// amountFormatHint is added to errors about unreadable amounts
+const amountFormatHint = "use a number like 5000, 5,000, 1.5k or 0.5 XBT"
+
+// parseAmount parses an amount the way people and agents write it, such as
+// "R5,000", "0.5 BTC" or "1m". It returns the number and the Luno code of
+// the currency the amount was given in, or "" if it has none.
+func parseAmount(s string) (decimal.Decimal, string, error) {
+	text := strings.TrimSpace(s)
+	var currency string
+	for _, cs := range currencySymbols {
+		if rest, ok := strings.CutPrefix(text, cs.symbol); ok && rest != "" && strings.ContainsRune("0123456789. ", rune(rest[0])) {
+			text, currency = strings.TrimSpace(rest), cs.currency
+			break
+		}
+	}
+
+	m := amountPattern.FindStringSubmatch(text)
+	if m == nil {
+		return decimal.Decimal{}, "", fmt.Errorf("%q is not an amount, %s", s, amountFormatHint)
+	}
+	number, multiplier, code := strings.TrimSpace(m[1]), strings.ToLower(m[2]), m[3]
+
+	if code != "" {
+		code = market.NormalizeAsset(code)
+		if currency != "" && code != currency {
+			return decimal.Decimal{}, "", fmt.Errorf("%q has both %s and %s as its currency", s, currency, code)
+		}
+		currency = code
+	}
+
+	number, err := removeThousandsSeparators(number)
+	if err != nil {
+		return decimal.Decimal{}, "", fmt.Errorf("%q is not an amount, %w", s, err)
+	}
+	d, err := decimal.NewFromString(number)
+	if err != nil {
+		return decimal.Decimal{}, "", fmt.Errorf("%q is not an amount, %s", s, amountFormatHint)
+	}
+	if multiplier != "" {
+		d = trimScale(d.MulInt64(amountMultipliers[multiplier]))
+	}
+	return d, currency, nil
+}
+
+// trimScale returns d with as few decimal places as hold its value, so that
+// 1.5k is sent to the API as 1500 rather than 1500.0
+func trimScale(d decimal.Decimal) decimal.Decimal {
+	for scale := 0; ; scale++ {
+		if rounded := d.ToScale(scale); rounded.Cmp(d) == 0 {
+			return rounded
+		}
+	}
+}
+
+// removeThousandsSeparators removes commas, spaces and underscores between
+// groups of thousands. A separator anywhere else, as in the decimal comma of
+// "0,5", is an error rather than a guess.
+func removeThousandsSeparators(number string) (string, error) {
+	whole, fraction, hasFraction := strings.Cut(number, ".")
+	groups := strings.FieldsFunc(whole, func(r rune) bool { return r == ',' || r == '_' || r == ' ' })
+	if len(groups) > 1 {
+		for i, g := range groups {
+			digits := strings.TrimPrefix(g, "-")
+			if (i == 0 && len(digits) > 3) || (i > 0 && len(g) != 3) {
+				return "", errors.New(`separators must split the number into thousands, use "." for the decimal point`)
+			}
+		}
+	}
+	number = strings.Join(groups, "")
+	if hasFraction {
+		number += "." + fraction
+	}
+	return number, nil
+}
+
+// checkAmountCurrency checks that an amount given in a currency was given
+// in the one the market expects
+func checkAmountCurrency(name, currency, want, pair string) error {
+	if currency != "" && currency != want {
+		return fmt.Errorf("%s is given in %s but must be in %s for %s", name, currency, want, pair)
+	}
+	return nil
+}
+
+// orderValueParam is the create_order argument that gives the amount of the
+// counter currency to trade instead of a volume
+const orderValueParam = "value"
+
+// volumeForValue returns the volume that value buys or sells at price,
+// rounded down to the market's volume precision so the order never trades
+// more than value
+func volumeForValue(info *luno.MarketInfo, value, price decimal.Decimal) (decimal.Decimal, error) {
+	if value.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("value must be greater than zero, got %s", value.String())
+	}
+	if price.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("price must be greater than zero, got %s", price.String())
+	}
+	return value.Div(price, int(info.VolumeScale)), nil
+}
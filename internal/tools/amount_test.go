@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input            string
+		expected         string
+		expectedCurrency string
+		errorContains    string
+	}{
+		{input: "0.001", expected: "0.001"},
+		{input: " 5000 ", expected: "5000"},
+		{input: "5,000", expected: "5000"},
+		{input: "1,250,000.50", expected: "1250000.50"},
+		{input: "5 000", expected: "5000"},
+		{input: "R5,000", expected: "5000", expectedCurrency: "ZAR"},
+		{input: "R 5000", expected: "5000", expectedCurrency: "ZAR"},
+		{input: "RM200", expected: "200", expectedCurrency: "MYR"},
+		{input: "€1.5k", expected: "1500", expectedCurrency: "EUR"},
+		{input: "0.5 BTC", expected: "0.5", expectedCurrency: "XBT"},
+		{input: "0.5btc", expected: "0.5", expectedCurrency: "XBT"},
+		{input: "2 ethereum", expected: "2", expectedCurrency: "ETH"},
+		{input: "1m", expected: "1000000"},
+		{input: "1.5K", expected: "1500"},
+		{input: "2bn", expected: "2000000000"},
+		{input: "1.5m ZAR", expected: "1500000", expectedCurrency: "ZAR"},
+		{input: "5 MYR", expected: "5", expectedCurrency: "MYR"},
+		{input: ".5", expected: "0.5"},
+		{input: "R5000 USD", errorContains: "has both ZAR and USD as its currency"},
+		{input: "0,5", errorContains: `use "." for the decimal point`},
+		{input: "50,00", errorContains: `use "." for the decimal point`},
+		{input: "1.2.3", errorContains: "is not an amount"},
+		{input: "invalid_volume", errorContains: "is not an amount"},
+		{input: "", errorContains: "is not an amount"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			amount, currency, err := parseAmount(tc.input)
+			if tc.errorContains != "" {
+				require.ErrorContains(t, err, tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, amount.String())
+			require.Equal(t, tc.expectedCurrency, currency)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListAssetsToolID is the ID of the asset listing tool
+const ListAssetsToolID = "list_assets"
+
+// AssetInfo is an asset with whether it can currently be moved in and out
+// of the account
+type AssetInfo struct {
+	market.Asset
+	Deposits    Capability `json:"deposits"`
+	Withdrawals Capability `json:"withdrawals"`
+}
+
+// NewListAssetsTool creates a new tool for listing the supported assets
+func NewListAssetsTool() mcp.Tool {
+	return mcp.NewTool(
+		ListAssetsToolID,
+		mcp.WithDescription("List the assets Luno supports with their name, decimals, the Luno code for the common symbol "+
+			"(e.g. XBT for BTC), the aliases tools accept and whether deposits and withdrawals are available"),
+		mcp.WithString(
+			"assets",
+			mcp.Description("Comma separated assets to list, by code, symbol or name (e.g., BTC,ethereum). Lists every asset if empty."),
+		),
+		withFormat(),
+	)
+}
+
+// HandleListAssets handles the list_assets tool
+func HandleListAssets(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		assets := market.Assets
+		if requested := strings.TrimSpace(request.GetString("assets", "")); requested != "" {
+			assets = nil
+			for _, name := range strings.Split(requested, ",") {
+				asset, ok := market.FindAsset(name)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("unknown asset %q, call list_assets without assets to see them all", strings.TrimSpace(name))), nil
+				}
+				assets = append(assets, asset)
+			}
+		}
+
+		infos := make([]AssetInfo, len(assets))
+		_ = forEach(ctx, cfg, assets, func(ctx context.Context, i int, asset market.Asset) error {
+			infos[i] = AssetInfo{Asset: asset}
+			if asset.Fiat {
+				bank := Capability{Status: CapabilityUnknown, Detail: "bank transfers can't be checked through the API"}
+				infos[i].Deposits, infos[i].Withdrawals = bank, bank
+				return nil
+			}
+			infos[i].Deposits, infos[i].Withdrawals, _ = fundingCapabilities(ctx, cfg, asset.Code)
+			return nil
+		})
+
+		table := &Table{Headers: []string{"Code", "Symbol", "Name", "Decimals", "Deposits", "Withdrawals"}}
+		for _, a := range infos {
+			table.Rows = append(table.Rows, []string{
+				a.Code, a.Symbol, a.Name, strconv.Itoa(a.Decimals), a.Deposits.Status, a.Withdrawals.Status,
+			})
+		}
+
+		response := Response{
+			URI:     resultURI(ListAssetsToolID),
+			Summary: fmt.Sprintf("%d assets", len(infos)),
+			Data:    map[string]any{"assets": infos},
+			Table:   table,
+		}
+		result, err := response.Result(format)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal assets: %v", err)), nil
+		}
+
+		return result, nil
+	}
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListAssets(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expected      []AssetInfo
+		errorContains string
+	}{
+		{
+			name:   "requested assets",
+			params: map[string]any{"assets": "btc, litecoin,rand"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetFundingAddress(mock.Anything, &luno.GetFundingAddressRequest{Asset: "XBT"}).
+					Return(&luno.GetFundingAddressResponse{Asset: "XBT", Address: "bc1q"}, nil)
+				m.EXPECT().GetFundingAddress(mock.Anything, &luno.GetFundingAddressRequest{Asset: "LTC"}).
+					Return(nil, errors.New("invalid asset"))
+			},
+			expected: []AssetInfo{
+				{
+					Asset:       market.Assets[0],
+					Deposits:    Capability{Status: CapabilityEnabled, Detail: "receive address available"},
+					Withdrawals: Capability{Status: CapabilityEnabled, Detail: "withdraw by sending to an external address"},
+				},
+				{
+					Asset:       market.Assets[3],
+					Deposits:    unknownFunding("invalid asset"),
+					Withdrawals: unknownFunding("invalid asset"),
+				},
+				{
+					Asset:       market.Assets[13],
+					Deposits:    Capability{Status: CapabilityUnknown, Detail: "bank transfers can't be checked through the API"},
+					Withdrawals: Capability{Status: CapabilityUnknown, Detail: "bank transfers can't be checked through the API"},
+				},
+			},
+		},
+		{
+			name:          "unknown asset",
+			params:        map[string]any{"assets": "XBT,NOPE"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: `unknown asset "NOPE"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleListAssets(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var got struct {
+				Assets []AssetInfo `json:"assets"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+			assert.Equal(t, tc.expected, got.Assets)
+		})
+	}
+}
+
+func TestHandleListAssetsAll(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetFundingAddress(mock.Anything, mock.Anything).Return(&luno.GetFundingAddressResponse{}, nil)
+
+	result, err := HandleListAssets(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(map[string]any{"format": "summary"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, "20 assets", result.Content[0].(mcp.TextContent).Text)
+	// Only crypto assets have a receive address to check
+	mockClient.AssertNumberOfCalls(t, "GetFundingAddress", 13)
+}
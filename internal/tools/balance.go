@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+)
+
+// availableBalances sums the balance of each asset across its accounts, less
+// what open orders reserve
+func availableBalances(balances []luno.AccountBalance) map[string]decimal.Decimal {
+	available := make(map[string]decimal.Decimal)
+	for _, b := range balances {
+		available[b.Asset] = available[b.Asset].Add(b.Balance.Sub(b.Reserved))
+	}
+	return available
+}
+
+// checkOrderBalance fails fast when the account can't fund a limit order:
+// a buy needs its value plus the estimated taker fee in the counter currency
+// and a sell needs its volume in the base currency. released is what an
+// order cancelled to make way for this one frees up in the currency it
+// spends. The check is skipped if balances can't be read, leaving the API to
+// decide.
+func checkOrderBalance(ctx context.Context, cfg *config.Config, market *luno.MarketInfo, side OrderSide, volume, price, released decimal.Decimal) error {
+	var balances *luno.GetBalancesResponse
+	var fees *luno.GetFeeInfoResponse
+	var feeErr error
+	err := parallel(ctx, cfg,
+		func(ctx context.Context) error {
+			var err error
+			balances, err = cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+			return err
+		},
+		func(ctx context.Context) error {
+			// Without fees the check is still worth making
+			fees, feeErr = cfg.LunoClient.GetFeeInfo(ctx, &luno.GetFeeInfoRequest{Pair: market.MarketId})
+			return nil
+		},
+	)
+	if err != nil {
+		slog.Warn("Skipping balance check, could not get balances", "pair", market.MarketId, "error", err)
+		return nil
+	}
+	available := availableBalances(balances.Balance)
+
+	if side == OrderSideSell {
+		available[market.BaseCurrency] = available[market.BaseCurrency].Add(released)
+		if available[market.BaseCurrency].Cmp(volume) < 0 {
+			return lunoerr.New(lunoerr.InsufficientBalance, "insufficient %s balance: the order needs %s %s but only %s %s is available",
+				market.BaseCurrency, volume.String(), market.BaseCurrency, available[market.BaseCurrency].String(), market.BaseCurrency)
+		}
+		return nil
+	}
+
+	value := volume.Mul(price)
+	fee := decimal.Zero()
+	if rate, ok := takerFeeRate(market.MarketId, fees, feeErr); ok {
+		fee = value.Mul(rate)
+	}
+	needed := value.Add(fee)
+	available[market.CounterCurrency] = available[market.CounterCurrency].Add(released)
+	if available[market.CounterCurrency].Cmp(needed) < 0 {
+		return lunoerr.New(lunoerr.InsufficientBalance, "insufficient %s balance: the order needs %s %s (%s plus an estimated %s fee) but only %s %s is available",
+			market.CounterCurrency, needed.String(), market.CounterCurrency, value.String(), fee.String(),
+			available[market.CounterCurrency].String(), market.CounterCurrency)
+	}
+	return nil
+}
+
+// takerFeeRate reads the taker fee rate from a pair's fee info. ok is false
+// when the fee info couldn't be read, and balances are checked without fees.
+func takerFeeRate(pair string, fees *luno.GetFeeInfoResponse, err error) (rate decimal.Decimal, ok bool) {
+	if err != nil {
+		slog.Warn("Checking balance without fees, could not get fee info", "pair", pair, "error", err)
+		return decimal.Zero(), false
+	}
+	rate, err = decimal.NewFromString(fees.TakerFee)
+	return rate, err == nil
+}
@@ -0,0 +1,301 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetBriefingToolID is the ID of the briefing tool
+const GetBriefingToolID = "get_briefing"
+
+// briefingLookback is how far back price changes are measured, and how far
+// back fills are reported on the first briefing
+const briefingLookback = 24 * time.Hour
+
+// BriefingAsset is the value of one asset in the portfolio
+type BriefingAsset struct {
+	Asset   string          `json:"asset"`
+	Balance decimal.Decimal `json:"balance"`
+	Value   decimal.Decimal `json:"value"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// BriefingPortfolio is the value of all assets in the quote currency
+type BriefingPortfolio struct {
+	QuoteCurrency string          `json:"quote_currency"`
+	TotalValue    decimal.Decimal `json:"total_value"`
+	Assets        []BriefingAsset `json:"assets"`
+}
+
+// BriefingPrice is the price change of a watched pair over the lookback
+type BriefingPrice struct {
+	Pair          string          `json:"pair"`
+	LastTrade     decimal.Decimal `json:"last_trade"`
+	Open          decimal.Decimal `json:"open_24h"`
+	ChangePercent decimal.Decimal `json:"change_percent"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// BriefingOrder is an open order
+type BriefingOrder struct {
+	OrderID     string          `json:"order_id"`
+	Pair        string          `json:"pair"`
+	Type        luno.OrderType  `json:"type"`
+	LimitPrice  decimal.Decimal `json:"limit_price"`
+	LimitVolume decimal.Decimal `json:"limit_volume"`
+	Filled      decimal.Decimal `json:"filled"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// BriefingFill is one of the user's trades
+type BriefingFill struct {
+	OrderID   string          `json:"order_id"`
+	Pair      string          `json:"pair"`
+	Side      OrderSide       `json:"side"`
+	Price     decimal.Decimal `json:"price"`
+	Volume    decimal.Decimal `json:"volume"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Briefing combines the account overview most often asked for at the start of a day
+type Briefing struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	FillsSince  time.Time         `json:"fills_since"`
+	Portfolio   BriefingPortfolio `json:"portfolio"`
+	Prices      []BriefingPrice   `json:"prices"`
+	OpenOrders  []BriefingOrder   `json:"open_orders"`
+	Fills       []BriefingFill    `json:"fills"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+// NewGetBriefingTool creates a new tool for the daily briefing
+func NewGetBriefingTool() mcp.Tool {
+	return mcp.NewTool(
+		GetBriefingToolID,
+		mcp.WithDescription("Get a briefing of your Luno account in one call: portfolio value, 24 hour price changes "+
+			"for watched pairs, open orders and your fills since the last briefing (or the last 24 hours). "+
+			"Sections that fail are reported as warnings instead of failing the briefing."),
+		mcp.WithString(
+			"quote_currency",
+			mcp.Description("Currency to value the portfolio in (default: ZAR)"),
+		),
+		mcp.WithString(
+			"pairs",
+			mcp.Description("Comma separated pairs to watch (e.g., XBTZAR,ETHZAR). "+
+				"Defaults to the markets for the assets you hold against the quote currency."),
+		),
+	)
+}
+
+// HandleGetBriefing handles the get_briefing tool. The time of the last
+// briefing is kept in memory so fills are not repeated between briefings.
+func HandleGetBriefing(cfg *config.Config) server.ToolHandlerFunc {
+	var (
+		mu           sync.Mutex
+		lastBriefing time.Time
+	)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		quote := market.NormalizePair(strings.TrimSpace(request.GetString("quote_currency", "ZAR")))
+
+		now := time.Now()
+		mu.Lock()
+		since := lastBriefing
+		mu.Unlock()
+		if since.IsZero() {
+			since = now.Add(-briefingLookback)
+		}
+
+		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get balances: %v", err)), nil
+		}
+
+		markets, err := ListMarkets(ctx, cfg)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("finding markets", err), nil
+		}
+
+		briefing := &Briefing{GeneratedAt: now, FillsSince: since}
+		briefing.Portfolio = briefingPortfolio(ctx, cfg, markets, balances.Balance, quote)
+
+		pairs := briefingPairs(request.GetString("pairs", ""), markets, briefing.Portfolio.Assets, quote)
+		briefing.Prices = make([]BriefingPrice, len(pairs))
+		_ = forEach(ctx, cfg, pairs, func(ctx context.Context, i int, pair string) error {
+			briefing.Prices[i] = briefingPrice(ctx, cfg, pair, now)
+			return nil
+		})
+
+		orders, err := cfg.LunoClient.ListOrders(ctx, &luno.ListOrdersRequest{State: luno.OrderStatePending})
+		if err != nil {
+			briefing.Warnings = append(briefing.Warnings, fmt.Sprintf("could not list open orders: %v", err))
+		} else {
+			for _, o := range orders.Orders {
+				briefing.OpenOrders = append(briefing.OpenOrders, BriefingOrder{
+					OrderID:     o.OrderId,
+					Pair:        o.Pair,
+					Type:        o.Type,
+					LimitPrice:  o.LimitPrice,
+					LimitVolume: o.LimitVolume,
+					Filled:      o.Base,
+					CreatedAt:   time.Time(o.CreationTimestamp),
+				})
+			}
+		}
+
+		fills := make([]*luno.ListUserTradesResponse, len(pairs))
+		fillErrs := make([]error, len(pairs))
+		_ = forEach(ctx, cfg, pairs, func(ctx context.Context, i int, pair string) error {
+			fills[i], fillErrs[i] = cfg.LunoClient.ListUserTrades(ctx, &luno.ListUserTradesRequest{Pair: pair, Since: luno.Time(since)})
+			return nil
+		})
+
+		fillsOK := true
+		for i, pair := range pairs {
+			if fillErrs[i] != nil {
+				fillsOK = false
+				briefing.Warnings = append(briefing.Warnings, fmt.Sprintf("could not list fills for %s: %v", pair, fillErrs[i]))
+				continue
+			}
+			for _, t := range fills[i].Trades {
+				briefing.Fills = append(briefing.Fills, BriefingFill{
+					OrderID:   t.OrderId,
+					Pair:      t.Pair,
+					Side:      userTradeSide(t),
+					Price:     t.Price,
+					Volume:    t.Volume,
+					Timestamp: time.Time(t.Timestamp),
+				})
+			}
+		}
+
+		// Only move the window forward once fills have been reported
+		if fillsOK {
+			mu.Lock()
+			if now.After(lastBriefing) {
+				lastBriefing = now
+			}
+			mu.Unlock()
+		}
+
+		resultJSON, err := json.MarshalIndent(briefing, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal briefing: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// briefingPortfolio values each held asset in the quote currency
+func briefingPortfolio(ctx context.Context, cfg *config.Config, markets []luno.MarketInfo, balances []luno.AccountBalance, quote string) BriefingPortfolio {
+	portfolio := BriefingPortfolio{QuoteCurrency: quote, TotalValue: decimal.Zero()}
+
+	totals := make(map[string]decimal.Decimal)
+	var assets []string
+	for _, b := range balances {
+		if _, ok := totals[b.Asset]; !ok {
+			assets = append(assets, b.Asset)
+			totals[b.Asset] = decimal.Zero()
+		}
+		totals[b.Asset] = totals[b.Asset].Add(b.Balance)
+	}
+	slices.Sort(assets)
+
+	assets = slices.DeleteFunc(assets, func(asset string) bool { return totals[asset].Sign() == 0 })
+
+	items := make([]BriefingAsset, len(assets))
+	_ = forEach(ctx, cfg, assets, func(ctx context.Context, i int, asset string) error {
+		item := BriefingAsset{Asset: asset, Balance: totals[asset], Value: decimal.Zero()}
+		if asset == quote {
+			item.Value = item.Balance
+			items[i] = item
+			return nil
+		}
+		hops, err := findConversionPath(markets, asset, quote)
+		if err == nil {
+			var conversion *Conversion
+			conversion, err = convert(ctx, cfg, item.Balance, asset, quote, hops)
+			if err == nil {
+				item.Value = conversion.Result
+			}
+		}
+		if err != nil {
+			item.Error = err.Error()
+		}
+		items[i] = item
+		return nil
+	})
+
+	for _, item := range items {
+		portfolio.TotalValue = portfolio.TotalValue.Add(item.Value)
+		portfolio.Assets = append(portfolio.Assets, item)
+	}
+	return portfolio
+}
+
+// briefingPairs returns the requested pairs, or the active markets for the
+// held assets against the quote currency
+func briefingPairs(requested string, markets []luno.MarketInfo, assets []BriefingAsset, quote string) []string {
+	var pairs []string
+	if requested != "" {
+		for _, p := range strings.Split(requested, ",") {
+			if p = market.NormalizePair(strings.TrimSpace(p)); p != "" && !slices.Contains(pairs, p) {
+				pairs = append(pairs, p)
+			}
+		}
+		return pairs
+	}
+
+	for _, a := range assets {
+		if a.Asset == quote {
+			continue
+		}
+		if hop, ok := findHop(markets, a.Asset, quote); ok {
+			pairs = append(pairs, hop.pair)
+		}
+	}
+	return pairs
+}
+
+// briefingPrice reports the change in the last trade price over the lookback
+func briefingPrice(ctx context.Context, cfg *config.Config, pair string, now time.Time) BriefingPrice {
+	price := BriefingPrice{Pair: pair}
+
+	ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: pair})
+	if err != nil {
+		price.Error = fmt.Sprintf("getting ticker: %v", err)
+		return price
+	}
+	price.LastTrade = ticker.LastTrade
+
+	candles, err := cfg.LunoClient.GetCandles(ctx, &luno.GetCandlesRequest{
+		Pair:     pair,
+		Duration: int64(time.Hour / time.Second),
+		Since:    luno.Time(now.Add(-briefingLookback)),
+	})
+	if err != nil {
+		price.Error = fmt.Sprintf("getting candles: %v", err)
+		return price
+	}
+	if len(candles.Candles) == 0 || candles.Candles[0].Open.Sign() <= 0 {
+		price.Error = "no trades in the last 24 hours"
+		return price
+	}
+
+	price.Open = candles.Candles[0].Open
+	price.ChangePercent = price.LastTrade.Sub(price.Open).MulInt64(100).Div(price.Open, 2)
+	return price
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testBriefingBalances() *luno.GetBalancesResponse {
+	return &luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{Asset: "ZAR", Balance: decimal.NewFromInt64(1000)},
+		{Asset: "XBT", Balance: decimal.NewFromFloat64(0.25, 2)},
+		{Asset: "XBT", Balance: decimal.NewFromFloat64(0.25, 2)},
+		{Asset: "ETH", Balance: decimal.Zero()},
+	}}
+}
+
+func TestHandleGetBriefing(t *testing.T) {
+	xbtzar := &luno.GetTickerResponse{Pair: "XBTZAR", Bid: decimal.NewFromInt64(1000000), LastTrade: decimal.NewFromInt64(1000000)}
+	candles := &luno.GetCandlesResponse{Candles: []luno.Candle{{Open: decimal.NewFromInt64(800000)}, {Open: decimal.NewFromInt64(900000)}}}
+
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+		check         func(*testing.T, Briefing)
+	}{
+		{
+			name: "full briefing",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testBriefingBalances(), nil)
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).Return(xbtzar, nil)
+				m.EXPECT().GetCandles(mock.Anything, mock.MatchedBy(func(req *luno.GetCandlesRequest) bool {
+					return req.Pair == "XBTZAR" && req.Duration == 3600
+				})).Return(candles, nil)
+				m.EXPECT().ListOrders(mock.Anything, &luno.ListOrdersRequest{State: luno.OrderStatePending}).
+					Return(&luno.ListOrdersResponse{Orders: []luno.Order{{OrderId: "BXMC2CJ7HNB88U4", Pair: "XBTZAR", Type: luno.OrderTypeBid}}}, nil)
+				m.EXPECT().ListUserTrades(mock.Anything, mock.MatchedBy(func(req *luno.ListUserTradesRequest) bool {
+					return req.Pair == "XBTZAR"
+				})).Return(&luno.ListUserTradesResponse{Trades: []luno.TradeV2{
+					{OrderId: "BXMC2CJ7HNB88U5", Pair: "XBTZAR", Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(950000), Volume: decimal.NewFromFloat64(0.01, 2)},
+				}}, nil)
+			},
+			check: func(t *testing.T, b Briefing) {
+				assert.Equal(t, "ZAR", b.Portfolio.QuoteCurrency)
+				assert.Equal(t, "501000.00", b.Portfolio.TotalValue.String())
+				require.Len(t, b.Portfolio.Assets, 2)
+				assert.Equal(t, "XBT", b.Portfolio.Assets[0].Asset)
+				assert.Equal(t, "500000.00", b.Portfolio.Assets[0].Value.String())
+				assert.Equal(t, "ZAR", b.Portfolio.Assets[1].Asset)
+
+				require.Len(t, b.Prices, 1)
+				assert.Equal(t, "XBTZAR", b.Prices[0].Pair)
+				assert.Equal(t, "800000", b.Prices[0].Open.String())
+				assert.Equal(t, "25.00", b.Prices[0].ChangePercent.String())
+
+				require.Len(t, b.OpenOrders, 1)
+				assert.Equal(t, "BXMC2CJ7HNB88U4", b.OpenOrders[0].OrderID)
+				require.Len(t, b.Fills, 1)
+				assert.Equal(t, OrderSideBuy, b.Fills[0].Side)
+				assert.Empty(t, b.Warnings)
+				assert.WithinDuration(t, b.GeneratedAt.Add(-briefingLookback), b.FillsSince, time.Second)
+			},
+		},
+		{
+			name:   "requested pairs, two hop valuation and partial failures",
+			params: map[string]any{"pairs": "btc-eur", "quote_currency": "eur"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testBriefingBalances(), nil)
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTEUR"}).
+					Return(&luno.GetTickerResponse{Pair: "XBTEUR", Bid: decimal.NewFromInt64(50000), LastTrade: decimal.NewFromInt64(50000)}, nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
+					Return(&luno.GetTickerResponse{Pair: "XBTZAR", Ask: decimal.NewFromInt64(1000000)}, nil)
+				m.EXPECT().GetCandles(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+				m.EXPECT().ListOrders(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+				m.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(&luno.ListUserTradesResponse{}, nil)
+			},
+			check: func(t *testing.T, b Briefing) {
+				assert.Equal(t, "EUR", b.Portfolio.QuoteCurrency)
+				assert.Equal(t, "25050.00000000", b.Portfolio.TotalValue.String())
+				require.Len(t, b.Portfolio.Assets, 2)
+				assert.Equal(t, "50.00000000", b.Portfolio.Assets[1].Value.String())
+
+				require.Len(t, b.Prices, 1)
+				assert.Equal(t, "XBTEUR", b.Prices[0].Pair)
+				assert.Contains(t, b.Prices[0].Error, "getting candles")
+				assert.Equal(t, []string{"could not list open orders: " + apiErrorStr}, b.Warnings)
+			},
+		},
+		{
+			name: "balances API error",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "Failed to get balances",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleGetBriefing(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+
+			if tc.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			var got Briefing
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			tc.check(t, got)
+		})
+	}
+}
+
+func TestHandleGetBriefingFillsSinceLastBriefing(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).
+		Return(&luno.GetBalancesResponse{Balance: []luno.AccountBalance{{Asset: "ZAR", Balance: decimal.NewFromInt64(1000)}}}, nil)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	mockClient.EXPECT().ListOrders(mock.Anything, mock.Anything).Return(&luno.ListOrdersResponse{}, nil)
+	mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{LastTrade: decimal.NewFromInt64(1)}, nil)
+	mockClient.EXPECT().GetCandles(mock.Anything, mock.Anything).Return(&luno.GetCandlesResponse{}, nil)
+	mockClient.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(&luno.ListUserTradesResponse{}, nil)
+
+	handler := HandleGetBriefing(&config.Config{LunoClient: mockClient})
+	request := createMockRequest(map[string]any{"pairs": "XBTZAR"})
+
+	var briefings []Briefing
+	for range 2 {
+		result, err := handler(context.Background(), request)
+		require.NoError(t, err)
+		var b Briefing
+		require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &b))
+		briefings = append(briefings, b)
+	}
+
+	assert.Equal(t, "no trades in the last 24 hours", briefings[0].Prices[0].Error)
+	assert.True(t, briefings[1].FillsSince.Equal(briefings[0].GeneratedAt))
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetAssetCapabilitiesToolID is the ID of the asset capabilities tool
+const GetAssetCapabilitiesToolID = "get_asset_capabilities"
+
+// Capability statuses
+const (
+	CapabilityEnabled  = "enabled"
+	CapabilityDisabled = "disabled"
+	CapabilityUnknown  = "unknown"
+)
+
+// Capability describes whether an action is available for an asset
+type Capability struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// AssetCapabilities describes what can be done with an asset on Luno
+type AssetCapabilities struct {
+	Currency      string     `json:"currency"`
+	Trading       Capability `json:"trading"`
+	ActiveMarkets []string   `json:"active_markets,omitempty"`
+	Deposits      Capability `json:"deposits"`
+	Withdrawals   Capability `json:"withdrawals"`
+	Sends         Capability `json:"sends"`
+}
+
+// NewGetAssetCapabilitiesTool creates a new tool for reporting asset capabilities
+func NewGetAssetCapabilitiesTool() mcp.Tool {
+	return mcp.NewTool(
+		GetAssetCapabilitiesToolID,
+		mcp.WithDescription("Report, per currency, whether trading, deposits, withdrawals and sends are currently available "+
+			"on your Luno account. Check this before suggesting an action for an asset."),
+		mcp.WithString(
+			"currency",
+			mcp.Description("Currency to check (e.g., XBT). Checks every currency you hold an account for if empty."),
+		),
+	)
+}
+
+// HandleGetAssetCapabilities handles the get_asset_capabilities tool
+func HandleGetAssetCapabilities(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var currencies []string
+		if currency := strings.TrimSpace(request.GetString("currency", "")); currency != "" {
+			currencies = []string{market.NormalizePair(currency)}
+		} else {
+			balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get balances: %v", err)), nil
+			}
+			for _, b := range balances.Balance {
+				if !slices.Contains(currencies, b.Asset) {
+					currencies = append(currencies, b.Asset)
+				}
+			}
+			slices.Sort(currencies)
+		}
+
+		markets, err := ListMarkets(ctx, cfg)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("checking markets", err), nil
+		}
+
+		capabilities := make([]AssetCapabilities, 0, len(currencies))
+		for _, currency := range currencies {
+			caps := AssetCapabilities{Currency: currency}
+			caps.Trading, caps.ActiveMarkets = tradingCapability(markets, currency)
+			caps.Deposits, caps.Withdrawals, caps.Sends = fundingCapabilities(ctx, cfg, currency)
+			capabilities = append(capabilities, caps)
+		}
+
+		resultJSON, err := json.MarshalIndent(map[string]any{"assets": capabilities}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal capabilities: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// tradingCapability reports whether any market for the currency is open for trading
+func tradingCapability(markets []luno.MarketInfo, currency string) (Capability, []string) {
+	var active, inactive []string
+	for _, m := range markets {
+		if m.BaseCurrency != currency && m.CounterCurrency != currency {
+			continue
+		}
+		if m.TradingStatus == luno.TradingStatusActive {
+			active = append(active, m.MarketId)
+		} else {
+			inactive = append(inactive, fmt.Sprintf("%s (%s)", m.MarketId, m.TradingStatus))
+		}
+	}
+
+	switch {
+	case len(active) > 0:
+		return Capability{Status: CapabilityEnabled}, active
+	case len(inactive) > 0:
+		return Capability{Status: CapabilityDisabled, Detail: "no market is open for trading: " + strings.Join(inactive, ", ")}, nil
+	default:
+		return Capability{Status: CapabilityDisabled, Detail: "there are no Luno markets for this currency"}, nil
+	}
+}
+
+// fundingCapabilities reports deposits, withdrawals and sends for the currency.
+// A receive address means the asset is a cryptocurrency that can be moved
+// on-chain. Bank deposit and withdrawal availability is not exposed by the API.
+func fundingCapabilities(ctx context.Context, cfg *config.Config, currency string) (deposits, withdrawals, sends Capability) {
+	_, err := cfg.LunoClient.GetFundingAddress(ctx, &luno.GetFundingAddressRequest{Asset: currency})
+	if err != nil {
+		unknown := Capability{
+			Status: CapabilityUnknown,
+			Detail: fmt.Sprintf("no receive address available (%v); bank transfers for fiat currencies can't be checked through the API", err),
+		}
+		return unknown, unknown, unknown
+	}
+
+	enabled := Capability{Status: CapabilityEnabled, Detail: "receive address available"}
+	return enabled,
+		Capability{Status: CapabilityEnabled, Detail: "withdraw by sending to an external address"},
+		Capability{Status: CapabilityEnabled}
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetAssetCapabilities(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+		expected      []AssetCapabilities
+	}{
+		{
+			name:   "crypto currency with active markets",
+			params: map[string]any{"currency": "btc"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetFundingAddress(mock.Anything, &luno.GetFundingAddressRequest{Asset: "XBT"}).
+					Return(&luno.GetFundingAddressResponse{Asset: "XBT", Address: "bc1q"}, nil)
+			},
+			expected: []AssetCapabilities{{
+				Currency:      "XBT",
+				Trading:       Capability{Status: CapabilityEnabled},
+				ActiveMarkets: []string{"XBTZAR", "XBTEUR"},
+				Deposits:      Capability{Status: CapabilityEnabled, Detail: "receive address available"},
+				Withdrawals:   Capability{Status: CapabilityEnabled, Detail: "withdraw by sending to an external address"},
+				Sends:         Capability{Status: CapabilityEnabled},
+			}},
+		},
+		{
+			name: "all held currencies",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{
+					Balance: []luno.AccountBalance{{Asset: "ZAR"}, {Asset: "ETH"}, {Asset: "ZAR"}},
+				}, nil)
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetFundingAddress(mock.Anything, &luno.GetFundingAddressRequest{Asset: "ETH"}).
+					Return(&luno.GetFundingAddressResponse{Asset: "ETH"}, nil)
+				m.EXPECT().GetFundingAddress(mock.Anything, &luno.GetFundingAddressRequest{Asset: "ZAR"}).
+					Return(nil, errors.New("invalid asset"))
+			},
+			expected: []AssetCapabilities{
+				{
+					Currency:    "ETH",
+					Trading:     Capability{Status: CapabilityDisabled, Detail: "no market is open for trading: ETHZAR (SUSPENDED)"},
+					Deposits:    Capability{Status: CapabilityEnabled, Detail: "receive address available"},
+					Withdrawals: Capability{Status: CapabilityEnabled, Detail: "withdraw by sending to an external address"},
+					Sends:       Capability{Status: CapabilityEnabled},
+				},
+				{
+					Currency:      "ZAR",
+					Trading:       Capability{Status: CapabilityEnabled},
+					ActiveMarkets: []string{"XBTZAR"},
+					Deposits:      unknownFunding("invalid asset"),
+					Withdrawals:   unknownFunding("invalid asset"),
+					Sends:         unknownFunding("invalid asset"),
+				},
+			},
+		},
+		{
+			name:   "currency without markets",
+			params: map[string]any{"currency": "DOGE"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetFundingAddress(mock.Anything, mock.Anything).Return(nil, errors.New("invalid asset"))
+			},
+			expected: []AssetCapabilities{{
+				Currency:    "DOGE",
+				Trading:     Capability{Status: CapabilityDisabled, Detail: "there are no Luno markets for this currency"},
+				Deposits:    unknownFunding("invalid asset"),
+				Withdrawals: unknownFunding("invalid asset"),
+				Sends:       unknownFunding("invalid asset"),
+			}},
+		},
+		{
+			name: "balances API error",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "Failed to get balances",
+		},
+		{
+			name:   "markets API error",
+			params: map[string]any{"currency": "XBT"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "could not list markets",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleGetAssetCapabilities(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+
+			if tc.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			var got struct {
+				Assets []AssetCapabilities `json:"assets"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			assert.Equal(t, tc.expected, got.Assets)
+		})
+	}
+}
+
+func unknownFunding(err string) Capability {
+	return Capability{
+		Status: CapabilityUnknown,
+		Detail: "no receive address available (" + err + "); bank transfers for fiat currencies can't be checked through the API",
+	}
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/idempotency"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/internal/security"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// clientOrderIDParam is the argument that names an order for idempotent
+// submission and reconciliation
+const clientOrderIDParam = "client_order_id"
+
+// clientOrderIDPattern matches the client order IDs Luno accepts
+var clientOrderIDPattern = regexp.MustCompile(`^[0-9A-Za-z_;,.\-]{1,255}$`)
+
+// withClientOrderID adds the client_order_id argument to a tool
+func withClientOrderID() mcp.ToolOption {
+	return mcp.WithString(
+		clientOrderIDParam,
+		mcp.Description("Your own unique ID for the order, up to 255 letters, digits or _ ; , . - characters. "+
+			"A submission with an ID used in the last few minutes is rejected, so reuse the ID when retrying after a timeout. "+
+			"One is generated when omitted."),
+	)
+}
+
+// clientOrderID returns the client order ID of a request, or a new one if it
+// has none. given reports whether the caller chose the ID.
+func clientOrderID(request mcp.CallToolRequest) (id string, given bool, err error) {
+	if id := request.GetString(clientOrderIDParam, ""); id != "" {
+		if !clientOrderIDPattern.MatchString(id) {
+			return "", false, fmt.Errorf("%s must be 1 to 255 letters, digits or _ ; , . - characters", clientOrderIDParam)
+		}
+		return id, true, nil
+	}
+	token, err := security.NewToken()
+	if err != nil {
+		return "", false, err
+	}
+	return "mcp-" + token, false, nil
+}
+
+// A submissionKind is a kind of write that beginSubmission stops being
+// repeated
+type submissionKind struct {
+	// name prefixes the kind's keys, and describes it in errors
+	name string
+	// idName and idParam name the ID the caller can give a write
+	idName  string
+	idParam string
+	// made describes a write that went through, as in "placed as order"
+	made string
+	// again is what to do to make the write again on purpose
+	again string
+}
+
+var (
+	orderSubmission = submissionKind{
+		name: "order", idName: "client order ID", idParam: clientOrderIDParam, made: "placed as order", again: "place another order",
+	}
+	moveSubmission = submissionKind{
+		name: "move", idName: "client move ID", idParam: clientMoveIDParam, made: "made as move", again: "move funds again",
+	}
+)
+
+// beginSubmission records a submission so that a retry is rejected as a
+// duplicate. Submissions are keyed by their own ID when the caller gave one,
+// otherwise by the session and details. The returned function finishes the
+// submission with the ID of the order or move made, or with the error that
+// stopped it being made.
+func beginSubmission(ctx context.Context, cfg *config.Config, kind submissionKind, id string, given bool, details string) (func(madeID string, err error), error) {
+	if cfg.Submissions == nil {
+		return func(string, error) {}, nil
+	}
+	key := kind.name + " id\n" + id
+	if !given {
+		key = kind.name + "\n" + sessionID(ctx) + "\n" + details
+	}
+	if err := cfg.Submissions.Begin(key, id); err != nil {
+		return nil, lunoerr.New(lunoerr.DuplicateOrder, "%s. To %s on purpose, give it a new %s", kind.duplicate(err), kind.again, kind.idParam)
+	}
+	return func(madeID string, err error) {
+		switch {
+		case err == nil:
+			cfg.Submissions.Complete(key, madeID)
+		case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+			// It may still have been made, so a retry stays blocked
+		default:
+			cfg.Submissions.Forget(key)
+		}
+	}, nil
+}
+
+// duplicate describes the earlier submission a rejected one repeats
+func (k submissionKind) duplicate(err error) string {
+	var dup *idempotency.DuplicateError
+	if !errors.As(err, &dup) {
+		return err.Error()
+	}
+	earlier := fmt.Sprintf("duplicate %s submission: %s %s was submitted %s ago", k.name, k.idName, dup.ClientOrderID, dup.Age.Round(time.Second))
+	if dup.OrderID == "" {
+		return earlier + " and may still be in flight"
+	}
+	return earlier + " and " + k.made + " " + dup.OrderID
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/idempotency"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateOrderDuplicates(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	expectOrderBalance(mockClient)
+	mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR", Status: "ACTIVE"}, nil)
+	mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+	cfg := &config.Config{LunoClient: mockClient, Submissions: idempotency.NewStore(time.Minute)}
+
+	var sentIDs []string
+	expectPost := func(orderID string, err error) {
+		mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).RunAndReturn(
+			func(_ context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+				sentIDs = append(sentIDs, req.ClientOrderId)
+				if err != nil {
+					return nil, err
+				}
+				return &luno.PostLimitOrderResponse{OrderId: orderID}, nil
+			}).Once()
+	}
+	createOrder := func(params map[string]any) (string, bool) {
+		args := map[string]any{"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "1000000"}
+		for k, v := range params {
+			args[k] = v
+		}
+		result, err := HandleCreateOrder(cfg)(context.Background(), createMockRequest(args))
+		require.NoError(t, err)
+		return getTextContentFromResult(t, result), result.IsError
+	}
+
+	// Without an ID one is generated, and repeating the same order is a duplicate
+	expectPost("A", nil)
+	text, isErr := createOrder(nil)
+	require.False(t, isErr, text)
+	require.Len(t, sentIDs, 1)
+	assert.True(t, strings.HasPrefix(sentIDs[0], "mcp-"))
+	assert.Contains(t, text, `"client_order_id": "`+sentIDs[0]+`"`)
+
+	text, isErr = createOrder(nil)
+	assert.True(t, isErr)
+	assert.Contains(t, text, "duplicate order submission")
+	assert.Contains(t, text, "placed as order A")
+
+	// A new ID places the same order again on purpose
+	expectPost("B", nil)
+	text, isErr = createOrder(map[string]any{clientOrderIDParam: "ladder-1"})
+	require.False(t, isErr, text)
+	assert.Equal(t, "ladder-1", sentIDs[1])
+
+	text, isErr = createOrder(map[string]any{clientOrderIDParam: "ladder-1", "volume": "0.02"})
+	assert.True(t, isErr)
+	assert.Contains(t, text, "client order ID ladder-1 was submitted")
+
+	// An order the API rejected can be retried with the same ID
+	expectPost("", errors.New(apiErrorStr))
+	_, isErr = createOrder(map[string]any{clientOrderIDParam: "ladder-2"})
+	assert.True(t, isErr)
+	expectPost("C", nil)
+	text, isErr = createOrder(map[string]any{clientOrderIDParam: "ladder-2"})
+	require.False(t, isErr, text)
+
+	text, isErr = createOrder(map[string]any{clientOrderIDParam: "no spaces"})
+	assert.True(t, isErr)
+	assert.Contains(t, text, "invalid client order ID")
+}
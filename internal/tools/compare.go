@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CompareMarketsToolID is the ID of the market comparison tool
+const CompareMarketsToolID = "compare_markets"
+
+// MarketComparison is one market's row in a comparison
+type MarketComparison struct {
+	Pair          string           `json:"pair"`
+	LastTrade     decimal.Decimal  `json:"last_trade"`
+	Bid           decimal.Decimal  `json:"bid"`
+	Ask           decimal.Decimal  `json:"ask"`
+	SpreadPercent *decimal.Decimal `json:"spread_percent,omitempty"`
+	// Volume24h is in the base currency, Value24h is that volume at the
+	// last trade price in the counter currency
+	Volume24h decimal.Decimal `json:"volume_24h"`
+	Value24h  decimal.Decimal `json:"value_24h"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// NewCompareMarketsTool creates a new tool for comparing markets side by side
+func NewCompareMarketsTool() mcp.Tool {
+	return mcp.NewTool(
+		CompareMarketsToolID,
+		mcp.WithDescription("Compare the last price, spread and 24 hour volume of several markets in one call, "+
+			"e.g. to find the most liquid market. Give either pairs or a quote currency."),
+		mcp.WithString(
+			"pairs",
+			mcp.Description("Comma separated trading pairs to compare (e.g., XBTZAR,ETHZAR), in the order to show them"),
+		),
+		mcp.WithString(
+			"quote_currency",
+			mcp.Description("Compare every active market in this quote currency (e.g., ZAR), most traded first"),
+		),
+		withFormat(),
+	)
+}
+
+// HandleCompareMarkets handles the compare_markets tool
+func HandleCompareMarkets(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		var pairs []string
+		for _, p := range strings.Split(request.GetString("pairs", ""), ",") {
+			if p = market.NormalizePair(strings.TrimSpace(p)); p != "" && !slices.Contains(pairs, p) {
+				pairs = append(pairs, p)
+			}
+		}
+		quote := market.NormalizePair(strings.TrimSpace(request.GetString("quote_currency", "")))
+		switch {
+		case len(pairs) > 0 && quote != "":
+			return mcp.NewToolResultError("give either pairs or quote_currency, not both"), nil
+		case quote != "":
+			markets, err := ListMarkets(ctx, cfg)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("finding markets", err), nil
+			}
+			pairs = quoteMarkets(markets, quote)
+			if len(pairs) == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("no active markets quoted in %s", quote)), nil
+			}
+		case len(pairs) == 0:
+			return mcp.NewToolResultError("give pairs or quote_currency to compare"), nil
+		}
+
+		rows := make([]MarketComparison, len(pairs))
+		_ = forEach(ctx, cfg, pairs, func(ctx context.Context, i int, pair string) error {
+			rows[i] = compareMarket(ctx, cfg, pair)
+			return nil
+		})
+
+		// Volumes are only comparable in a common quote currency
+		if quote != "" {
+			slices.SortStableFunc(rows, func(a, b MarketComparison) int { return b.Value24h.Cmp(a.Value24h) })
+		}
+
+		failed := 0
+		table := &Table{Headers: []string{"Pair", "Last trade", "Spread %", "24h volume", "24h value"}}
+		for _, r := range rows {
+			if r.Error != "" {
+				failed++
+				table.Rows = append(table.Rows, []string{r.Pair, r.Error, "", "", ""})
+				continue
+			}
+			spread := "-"
+			if r.SpreadPercent != nil {
+				spread = r.SpreadPercent.String()
+			}
+			table.Rows = append(table.Rows, []string{
+				r.Pair, r.LastTrade.String(), spread, r.Volume24h.String(), r.Value24h.String(),
+			})
+		}
+
+		summary := fmt.Sprintf("%d markets compared", len(rows))
+		if quote != "" && failed < len(rows) {
+			summary += fmt.Sprintf(", %s is the most traded", rows[0].Pair)
+		}
+		if failed > 0 {
+			summary += fmt.Sprintf(", %d could not be fetched", failed)
+		}
+
+		response := Response{
+			URI:     resultURI(CompareMarketsToolID),
+			Summary: summary,
+			Data:    rows,
+			Table:   table,
+		}
+		result, err := response.Result(format)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal comparison: %v", err)), nil
+		}
+
+		return result, nil
+	}
+}
+
+// quoteMarkets returns the active markets quoted in quote
+func quoteMarkets(markets []luno.MarketInfo, quote string) []string {
+	var pairs []string
+	for _, m := range markets {
+		if m.CounterCurrency == quote && m.TradingStatus == luno.TradingStatusActive {
+			pairs = append(pairs, m.MarketId)
+		}
+	}
+	return pairs
+}
+
+// compareMarket fetches one market's ticker. A failure is recorded in the
+// row so the other markets are still compared.
+func compareMarket(ctx context.Context, cfg *config.Config, pair string) MarketComparison {
+	row := MarketComparison{Pair: pair}
+	ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: pair})
+	if err != nil {
+		row.Error = fmt.Sprintf("getting ticker: %v", err)
+		return row
+	}
+
+	row.LastTrade = ticker.LastTrade
+	row.Bid = ticker.Bid
+	row.Ask = ticker.Ask
+	if ticker.Bid.Sign() > 0 && ticker.Ask.Sign() > 0 {
+		row.SpreadPercent = &newTopOfBook(ticker.Bid, ticker.Ask).SpreadPercent
+	}
+	row.Volume24h = ticker.Rolling24HourVolume
+	row.Value24h = ticker.Rolling24HourVolume.Mul(ticker.LastTrade).ToScale(priceScale)
+	return row
+}
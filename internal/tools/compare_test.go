@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCompareMarkets(t *testing.T) {
+	tickers := map[string]*luno.GetTickerResponse{
+		"XBTZAR": {Pair: "XBTZAR", Bid: decimal.NewFromInt64(99), Ask: decimal.NewFromInt64(101), LastTrade: decimal.NewFromInt64(100), Rolling24HourVolume: decimal.NewFromInt64(10)},
+		"ETHZAR": {Pair: "ETHZAR", Bid: decimal.NewFromInt64(49), Ask: decimal.NewFromInt64(51), LastTrade: decimal.NewFromInt64(50), Rolling24HourVolume: decimal.NewFromInt64(40)},
+		"XRPZAR": {Pair: "XRPZAR", LastTrade: decimal.NewFromInt64(1), Rolling24HourVolume: decimal.NewFromInt64(5)},
+	}
+	spreads := map[string]string{"XBTZAR": "2", "ETHZAR": "4"}
+	markets := []luno.MarketInfo{
+		{MarketId: "XBTZAR", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive},
+		{MarketId: "ETHZAR", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive},
+		{MarketId: "XRPZAR", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive},
+		{MarketId: "LTCZAR", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusSuspended},
+		{MarketId: "XBTEUR", CounterCurrency: "EUR", TradingStatus: luno.TradingStatusActive},
+	}
+
+	tests := []struct {
+		name          string
+		params        map[string]any
+		listMarkets   bool
+		failing       string
+		expectedPairs []string
+		expectedError string
+	}{
+		{
+			name:          "pairs in the order given",
+			params:        map[string]any{"pairs": "xrpzar, BTC-ZAR,ETHZAR"},
+			expectedPairs: []string{"XRPZAR", "XBTZAR", "ETHZAR"},
+		},
+		{
+			name:          "quote currency most traded first",
+			params:        map[string]any{"quote_currency": "zar"},
+			listMarkets:   true,
+			expectedPairs: []string{"ETHZAR", "XBTZAR", "XRPZAR"},
+		},
+		{
+			name:          "failed market is reported in its row",
+			params:        map[string]any{"quote_currency": "ZAR"},
+			listMarkets:   true,
+			failing:       "ETHZAR",
+			expectedPairs: []string{"XBTZAR", "XRPZAR", "ETHZAR"},
+		},
+		{
+			name:          "no markets in the quote currency",
+			params:        map[string]any{"quote_currency": "NGN"},
+			listMarkets:   true,
+			expectedError: "no active markets quoted in NGN",
+		},
+		{
+			name:          "pairs and quote currency",
+			params:        map[string]any{"pairs": "XBTZAR", "quote_currency": "ZAR"},
+			expectedError: "not both",
+		},
+		{
+			name:          "nothing to compare",
+			params:        map[string]any{},
+			expectedError: "give pairs or quote_currency",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			if tc.listMarkets {
+				mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(&luno.MarketsResponse{Markets: markets}, nil)
+			}
+			for _, pair := range tc.expectedPairs {
+				call := mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: pair})
+				if pair == tc.failing {
+					call.Return(nil, errors.New("rate limited"))
+				} else {
+					call.Return(tickers[pair], nil)
+				}
+			}
+
+			result, err := HandleCompareMarkets(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			if tc.expectedError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tc.expectedError)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var rows []MarketComparison
+			require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &rows))
+			var pairs []string
+			for _, r := range rows {
+				pairs = append(pairs, r.Pair)
+				switch {
+				case r.Pair == tc.failing:
+					assert.Equal(t, "getting ticker: rate limited", r.Error)
+				case spreads[r.Pair] == "":
+					assert.Nil(t, r.SpreadPercent)
+				default:
+					require.NotNil(t, r.SpreadPercent)
+					assertDecimal(t, spreads[r.Pair], *r.SpreadPercent)
+				}
+			}
+			assert.Equal(t, tc.expectedPairs, pairs)
+		})
+	}
+}
+
+func TestHandleCompareMarketsSummary(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(&luno.MarketsResponse{Markets: []luno.MarketInfo{
+		{MarketId: "XBTZAR", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive},
+		{MarketId: "ETHZAR", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive},
+	}}, nil)
+	mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
+		Return(&luno.GetTickerResponse{LastTrade: decimal.NewFromInt64(100), Rolling24HourVolume: decimal.NewFromInt64(10)}, nil)
+	mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "ETHZAR"}).Return(nil, errors.New("rate limited"))
+
+	result, err := HandleCompareMarkets(&config.Config{LunoClient: mockClient})(context.Background(),
+		createMockRequest(map[string]any{"quote_currency": "ZAR", "format": "markdown"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "2 markets compared, XBTZAR is the most traded, 1 could not be fetched")
+	assert.Contains(t, text, "| XBTZAR | 100 | - | 10 | 1000.00000000 |")
+}
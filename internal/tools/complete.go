@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletions is the most values a completion may hold
+const maxCompletions = 100
+
+// Argument names that CompleteArgument suggests values for
+const (
+	// PairArgument is a trading pair, completed from the list of markets
+	PairArgument = "pair"
+	// PairsArgument is a comma separated list of trading pairs, whose last
+	// pair is completed
+	PairsArgument = "pairs"
+	// AccountIDArgument is an account ID, completed from the balances
+	AccountIDArgument = "account_id"
+	// CurrencyArgument is an asset code, completed from the known assets
+	CurrencyArgument = "currency"
+	// QuoteCurrencyArgument is an asset to value holdings in
+	QuoteCurrencyArgument = "quote_currency"
+	// AssetArgument is an asset whose account or wallet is wanted
+	AssetArgument = "asset"
+)
+
+// CompleteArgument suggests values for an argument with the given name that
+// start with value. Arguments it doesn't know get no suggestions.
+func CompleteArgument(ctx context.Context, cfg *config.Config, name, value string) (*mcp.Completion, error) {
+	var (
+		values []string
+		err    error
+	)
+	switch name {
+	case PairArgument:
+		values, err = completePairs(ctx, cfg, value)
+	case PairsArgument:
+		values, err = completePairList(ctx, cfg, value)
+	case AccountIDArgument:
+		values, err = completeAccountIDs(ctx, cfg, value)
+	case CurrencyArgument, QuoteCurrencyArgument, AssetArgument:
+		values = completeCurrencies(value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletions {
+		completion.Values = values[:maxCompletions]
+		completion.HasMore = true
+	}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	return completion, nil
+}
+
+// completePairs returns the markets whose pair starts with value, which may
+// use asset aliases, e.g. BTC for XBT
+func completePairs(ctx context.Context, cfg *config.Config, value string) ([]string, error) {
+	markets, err := ListMarkets(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := completionPrefixes(value)
+	var pairs []string
+	for _, info := range markets {
+		if hasAnyPrefix(info.MarketId, prefixes) {
+			pairs = append(pairs, info.MarketId)
+		}
+	}
+	slices.Sort(pairs)
+	return pairs, nil
+}
+
+// completePairList completes the last pair of a comma separated list,
+// leaving out pairs that are already in it
+func completePairList(ctx context.Context, cfg *config.Config, value string) ([]string, error) {
+	listed, last := "", value
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		listed, last = value[:i+1], value[i+1:]
+	}
+	pairs, err := completePairs(ctx, cfg, strings.TrimSpace(last))
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, pair := range pairs {
+		if !slices.Contains(strings.Split(strings.ToUpper(listed), ","), pair) {
+			values = append(values, listed+pair)
+		}
+	}
+	return values, nil
+}
+
+// completeAccountIDs returns the IDs of accounts whose ID or asset starts
+// with value. Without the read permission there are no suggestions.
+func completeAccountIDs(ctx context.Context, cfg *config.Config, value string) ([]string, error) {
+	if !cfg.Allows(config.PermissionRead) {
+		return nil, nil
+	}
+	res, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get balances: %w", err)
+	}
+
+	prefixes := completionPrefixes(value)
+	var ids []string
+	for _, balance := range res.Balance {
+		if strings.HasPrefix(balance.AccountId, value) || hasAnyPrefix(balance.Asset, prefixes) {
+			ids = append(ids, balance.AccountId)
+		}
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		x, _ := strconv.ParseInt(a, 10, 64)
+		y, _ := strconv.ParseInt(b, 10, 64)
+		return cmp.Compare(x, y)
+	})
+	return ids, nil
+}
+
+// completeCurrencies returns the codes of known assets whose code or one of
+// whose aliases starts with value
+func completeCurrencies(value string) []string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	var codes []string
+	for _, asset := range market.Assets {
+		if strings.HasPrefix(asset.Code, value) ||
+			slices.ContainsFunc(asset.Aliases, func(alias string) bool { return strings.HasPrefix(alias, value) }) {
+			codes = append(codes, asset.Code)
+		}
+	}
+	return codes
+}
+
+// completionPrefixes returns value in upper case, and with its aliases
+// replaced by Luno codes if that is different
+func completionPrefixes(value string) []string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	prefixes := []string{value}
+	if normalized := market.NormalizePair(value); normalized != value {
+		prefixes = append(prefixes, normalized)
+	}
+	return prefixes
+}
+
+// hasAnyPrefix reports whether s starts with any of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	return slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(s, prefix) })
+}
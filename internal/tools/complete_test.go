@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteArgument(t *testing.T) {
+	balances := &luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{AccountId: "1002", Asset: "ZAR"},
+		{AccountId: "1001", Asset: "XBT"},
+		{AccountId: "2001", Asset: "ETH"},
+	}}
+
+	tests := []struct {
+		name           string
+		argument       string
+		value          string
+		permissions    []config.Permission
+		setupMock      func(*sdk.MockLunoClient)
+		expectedValues []string
+		expectedError  string
+	}{
+		{
+			name:     "pair prefix",
+			argument: PairArgument,
+			value:    "xbt",
+			setupMock: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedValues: []string{"XBTEUR", "XBTZAR"},
+		},
+		{
+			name:     "pair alias",
+			argument: PairArgument,
+			value:    "BTC",
+			setupMock: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedValues: []string{"XBTEUR", "XBTZAR"},
+		},
+		{
+			name:     "markets error",
+			argument: PairArgument,
+			setupMock: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: "could not list markets",
+		},
+		{
+			name:     "last of a pair list",
+			argument: PairsArgument,
+			value:    "XBTZAR,",
+			setupMock: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedValues: []string{"XBTZAR,ETHZAR", "XBTZAR,XBTEUR"},
+		},
+		{
+			name:     "account ids",
+			argument: AccountIDArgument,
+			setupMock: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+			},
+			expectedValues: []string{"1001", "1002", "2001"},
+		},
+		{
+			name:     "account ids by asset",
+			argument: AccountIDArgument,
+			value:    "bitcoin",
+			setupMock: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+			},
+			expectedValues: []string{"1001"},
+		},
+		{
+			name:           "account ids without read permission",
+			argument:       AccountIDArgument,
+			permissions:    []config.Permission{config.PermissionTrade},
+			expectedValues: []string{},
+		},
+		{name: "currency", argument: CurrencyArgument, value: "us", expectedValues: []string{"USDT", "USDC"}},
+		{name: "currency alias", argument: QuoteCurrencyArgument, value: "rand", expectedValues: []string{"ZAR"}},
+		{name: "asset", argument: AssetArgument, value: "xb", expectedValues: []string{"XBT"}},
+		{name: "unknown argument", argument: "volume", value: "1", expectedValues: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockClient)
+			}
+			cfg := &config.Config{LunoClient: mockClient, Permissions: tc.permissions}
+
+			completion, err := CompleteArgument(context.Background(), cfg, tc.argument, tc.value)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValues, completion.Values)
+			assert.Equal(t, len(tc.expectedValues), completion.Total)
+			assert.False(t, completion.HasMore)
+		})
+	}
+}
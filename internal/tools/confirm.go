@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/security"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// confirmTokenParam is the argument that carries a confirmation token
+const confirmTokenParam = "confirm_token"
+
+// WritePreview is returned instead of executing a write when the server
+// requires confirmation
+type WritePreview struct {
+	Action       string    `json:"action"`
+	Details      any       `json:"details"`
+	ConfirmToken string    `json:"confirm_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Instructions string    `json:"instructions"`
+}
+
+// withConfirmToken adds the confirm_token parameter to write tools
+func withConfirmToken() mcp.ToolOption {
+	return mcp.WithString(
+		confirmTokenParam,
+		mcp.Description("Confirmation token from a preview of this exact call. "+
+			"Only needed when the server requires write confirmation: call once without it to get a preview, "+
+			"show the preview to the user, then repeat the same call with the token once they agree."),
+	)
+}
+
+// requireConfirmation checks a write against the server's confirmation mode.
+// It returns nil when the write may go ahead, either because confirmation is
+// disabled or because a valid token for the same call was given. Otherwise
+// it returns the result to send instead: a preview with a new token, or an
+// error for a bad token. binding must identify every parameter of the write,
+// so that a token can't approve different parameters from its preview.
+func requireConfirmation(cfg *config.Config, request mcp.CallToolRequest, toolID, binding string, details any) *mcp.CallToolResult {
+	if cfg.Confirmations == nil {
+		return nil
+	}
+	binding = toolID + "\n" + binding
+
+	if token := request.GetString(confirmTokenParam, ""); token != "" {
+		err := cfg.Confirmations.Consume(token, binding)
+		if err == nil {
+			return nil
+		}
+		msg := "the token does not match this call, or has already been used"
+		if errors.Is(err, security.ErrTokenExpired) {
+			msg = "the token has expired"
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Confirmation failed: %s. Nothing was executed. "+
+			"Call %s again without %s for a new preview.", msg, toolID, confirmTokenParam))
+	}
+
+	token, expires, err := cfg.Confirmations.Issue(binding)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("creating confirmation token", err)
+	}
+	preview := WritePreview{
+		Action:       toolID,
+		Details:      details,
+		ConfirmToken: token,
+		ExpiresAt:    expires,
+		Instructions: fmt.Sprintf("Nothing has been executed yet. Show these details to the user and, only if they confirm, "+
+			"call %s again with the same arguments and %s set to this token before it expires.", toolID, confirmTokenParam),
+	}
+	resultJSON, err := json.MarshalIndent(preview, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal preview: %v", err))
+	}
+	return mcp.NewToolResultText(string(resultJSON))
+}
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/security"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteConfirmation(t *testing.T) {
+	cancel := func(cfg *config.Config, params map[string]any) (string, bool) {
+		t.Helper()
+		result, err := HandleCancelOrder(cfg)(context.Background(), createMockRequest(params))
+		require.NoError(t, err)
+		return getTextContentFromResult(t, result), result.IsError
+	}
+	preview := func(t *testing.T, text string) WritePreview {
+		t.Helper()
+		var p WritePreview
+		require.NoError(t, json.Unmarshal([]byte(text), &p))
+		require.NotEmpty(t, p.ConfirmToken)
+		return p
+	}
+
+	t.Run("disabled executes immediately", func(t *testing.T) {
+		mockClient := sdk.NewMockLunoClient(t)
+		mockClient.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "123"}).
+			Return(&luno.StopOrderResponse{Success: true}, nil)
+
+		_, isErr := cancel(&config.Config{LunoClient: mockClient}, map[string]any{"order_id": "123"})
+		assert.False(t, isErr)
+	})
+
+	t.Run("preview then confirm", func(t *testing.T) {
+		mockClient := sdk.NewMockLunoClient(t)
+		mockClient.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "123"}).
+			Return(&luno.GetOrderV3Response{OrderId: "123", Pair: "XBTZAR"}, nil)
+		cfg := &config.Config{LunoClient: mockClient, Confirmations: security.NewTokenStore(0)}
+
+		text, isErr := cancel(cfg, map[string]any{"order_id": "123"})
+		require.False(t, isErr, text)
+		p := preview(t, text)
+		assert.Equal(t, CancelOrderToolID, p.Action)
+		assert.Contains(t, text, "XBTZAR")
+
+		mockClient.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "123"}).
+			Return(&luno.StopOrderResponse{Success: true}, nil).Once()
+		text, isErr = cancel(cfg, map[string]any{"order_id": "123", "confirm_token": p.ConfirmToken})
+		require.False(t, isErr, text)
+
+		// Tokens are single use
+		text, isErr = cancel(cfg, map[string]any{"order_id": "123", "confirm_token": p.ConfirmToken})
+		assert.True(t, isErr)
+		assert.Contains(t, text, "Nothing was executed")
+	})
+
+	t.Run("preview without order details", func(t *testing.T) {
+		mockClient := sdk.NewMockLunoClient(t)
+		mockClient.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+		cfg := &config.Config{LunoClient: mockClient, Confirmations: security.NewTokenStore(0)}
+
+		text, isErr := cancel(cfg, map[string]any{"order_id": "123"})
+		require.False(t, isErr, text)
+		preview(t, text)
+		assert.Contains(t, text, `"order_id": "123"`)
+	})
+
+	t.Run("token for different parameters", func(t *testing.T) {
+		mockClient := sdk.NewMockLunoClient(t)
+		mockClient.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+		cfg := &config.Config{LunoClient: mockClient, Confirmations: security.NewTokenStore(0)}
+
+		text, _ := cancel(cfg, map[string]any{"order_id": "123"})
+		p := preview(t, text)
+
+		text, isErr := cancel(cfg, map[string]any{"order_id": "456", "confirm_token": p.ConfirmToken})
+		assert.True(t, isErr)
+		assert.Contains(t, text, "does not match this call")
+	})
+
+	t.Run("token for a different tool", func(t *testing.T) {
+		mockClient := sdk.NewMockLunoClient(t)
+		cfg := &config.Config{LunoClient: mockClient, Confirmations: security.NewTokenStore(0)}
+
+		result, err := HandleCreateAccount(cfg)(context.Background(), createMockRequest(map[string]any{"currency": "XBT", "name": "123"}))
+		require.NoError(t, err)
+		p := preview(t, getTextContentFromResult(t, result))
+		assert.Equal(t, CreateAccountToolID, p.Action)
+
+		text, isErr := cancel(cfg, map[string]any{"order_id": "123", "confirm_token": p.ConfirmToken})
+		assert.True(t, isErr)
+		assert.Contains(t, text, "Confirmation failed")
+	})
+}
@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ConvertAmountToolID is the ID of the amount conversion tool
+const ConvertAmountToolID = "convert_amount"
+
+// Decimal places kept when dividing by a price. The rate keeps more so that
+// rates into high priced currencies, like ZAR to XBT, stay meaningful.
+const (
+	convertScale = 8
+	rateScale    = 16
+)
+
+// ConversionStep is one market hop in a conversion
+type ConversionStep struct {
+	Pair string `json:"pair"`
+	// Side is SELL when the step sells the pair's base currency at the bid,
+	// and BUY when it buys the base currency at the ask
+	Side   OrderSide       `json:"side"`
+	Price  decimal.Decimal `json:"price"`
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// Conversion is the result of converting an amount between currencies
+type Conversion struct {
+	From   string           `json:"from"`
+	To     string           `json:"to"`
+	Amount decimal.Decimal  `json:"amount"`
+	Result decimal.Decimal  `json:"result"`
+	Rate   decimal.Decimal  `json:"rate"`
+	Path   []ConversionStep `json:"path"`
+}
+
+// conversionHop is a market to trade on, before prices are known
+type conversionHop struct {
+	pair string
+	side OrderSide
+	from string
+	to   string
+}
+
+// NewConvertAmountTool creates a new tool for converting an amount between currencies
+func NewConvertAmountTool() mcp.Tool {
+	return mcp.NewTool(
+		ConvertAmountToolID,
+		mcp.WithDescription("Convert an amount from one currency to another at current Luno prices, "+
+			"e.g. how much ZAR 0.05 XBT is worth. Uses a direct market if there is one, otherwise two markets "+
+			"through a common currency. Returns the rate and the path used. Prices are indicative and ignore fees and order book depth."),
+		mcp.WithString(
+			"amount",
+			mcp.Required(),
+			mcp.Description("Amount to convert as a decimal string"),
+		),
+		mcp.WithString(
+			"from",
+			mcp.Required(),
+			mcp.Description("Currency of the amount (e.g., XBT or BTC)"),
+		),
+		mcp.WithString(
+			"to",
+			mcp.Required(),
+			mcp.Description("Currency to convert to (e.g., ZAR)"),
+		),
+	)
+}
+
+// HandleConvertAmount handles the convert_amount tool
+func HandleConvertAmount(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		amountStr, err := request.RequireString("amount")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting amount from request", err), nil
+		}
+		amount, err := decimal.NewFromString(amountStr)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid amount format", err), nil
+		}
+		if amount.Sign() < 0 {
+			return mcp.NewToolResultError("Amount must not be negative"), nil
+		}
+
+		from, err := request.RequireString("from")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting from currency from request", err), nil
+		}
+		to, err := request.RequireString("to")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting to currency from request", err), nil
+		}
+		from = market.NormalizePair(strings.TrimSpace(from))
+		to = market.NormalizePair(strings.TrimSpace(to))
+		if from == to {
+			return mcp.NewToolResultError(fmt.Sprintf("Nothing to convert, %s and %s are the same currency", from, to)), nil
+		}
+
+		markets, err := ListMarkets(ctx, cfg)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("finding markets", err), nil
+		}
+
+		hops, err := findConversionPath(markets, from, to)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		conversion, err := convert(ctx, cfg, amount, from, to, hops)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(conversion, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal conversion: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// findConversionPath returns the markets to trade through to convert from
+// one currency to another. A direct market is preferred, otherwise the first
+// intermediate currency with active markets to both is used.
+func findConversionPath(markets []luno.MarketInfo, from, to string) ([]conversionHop, error) {
+	if hop, ok := findHop(markets, from, to); ok {
+		return []conversionHop{hop}, nil
+	}
+
+	for _, m := range markets {
+		for _, via := range []string{m.BaseCurrency, m.CounterCurrency} {
+			if via == from || via == to {
+				continue
+			}
+			first, ok := findHop(markets, from, via)
+			if !ok {
+				continue
+			}
+			second, ok := findHop(markets, via, to)
+			if !ok {
+				continue
+			}
+			return []conversionHop{first, second}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no active market or two market path converts %s to %s, use list_markets to see available markets", from, to)
+}
+
+// findHop finds an active market trading from directly into to
+func findHop(markets []luno.MarketInfo, from, to string) (conversionHop, bool) {
+	for _, m := range markets {
+		if m.TradingStatus != luno.TradingStatusActive {
+			continue
+		}
+		switch {
+		case m.BaseCurrency == from && m.CounterCurrency == to:
+			return conversionHop{pair: m.MarketId, side: OrderSideSell, from: from, to: to}, true
+		case m.BaseCurrency == to && m.CounterCurrency == from:
+			return conversionHop{pair: m.MarketId, side: OrderSideBuy, from: from, to: to}, true
+		}
+	}
+	return conversionHop{}, false
+}
+
+// convert prices each hop from the live ticker and applies it to the amount
+func convert(ctx context.Context, cfg *config.Config, amount decimal.Decimal, from, to string, hops []conversionHop) (*Conversion, error) {
+	conversion := &Conversion{From: from, To: to, Amount: amount}
+	value := amount
+	rate := decimal.NewFromInt64(1)
+	for _, hop := range hops {
+		ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: hop.pair})
+		if err != nil {
+			return nil, fmt.Errorf("getting ticker for %s: %w", hop.pair, err)
+		}
+
+		step := ConversionStep{Pair: hop.pair, Side: hop.side, From: hop.from, To: hop.to}
+		if hop.side == OrderSideSell {
+			step.Price = ticker.Bid
+		} else {
+			step.Price = ticker.Ask
+		}
+		if step.Price.Sign() <= 0 {
+			return nil, fmt.Errorf("%s has no current %s price to convert with", hop.pair, strings.ToLower(string(hop.side)))
+		}
+
+		if hop.side == OrderSideSell {
+			value = value.Mul(step.Price)
+			rate = rate.Mul(step.Price)
+		} else {
+			value = value.Div(step.Price, convertScale)
+			rate = rate.Div(step.Price, rateScale)
+		}
+		step.Amount = value
+		conversion.Path = append(conversion.Path, step)
+	}
+	conversion.Result = value
+	conversion.Rate = rate
+	return conversion, nil
+}
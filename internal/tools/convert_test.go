@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleConvertAmount(t *testing.T) {
+	xbtzar := &luno.GetTickerResponse{Pair: "XBTZAR", Bid: decimal.NewFromInt64(1000000), Ask: decimal.NewFromInt64(1010000)}
+	xbteur := &luno.GetTickerResponse{Pair: "XBTEUR", Bid: decimal.NewFromInt64(49000), Ask: decimal.NewFromInt64(50000)}
+
+	type step struct {
+		pair, side, price, amount string
+	}
+	tests := []struct {
+		name           string
+		params         map[string]any
+		mockSetup      func(*sdk.MockLunoClient)
+		expectedError  bool
+		errorContains  string
+		expectedResult string
+		expectedRate   string
+		expectedPath   []step
+	}{
+		{
+			name:   "direct market selling the base currency",
+			params: map[string]any{"amount": "0.05", "from": "btc", "to": "zar"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).Return(xbtzar, nil)
+			},
+			expectedResult: "50000.00",
+			expectedRate:   "1000000",
+			expectedPath:   []step{{"XBTZAR", "SELL", "1000000", "50000.00"}},
+		},
+		{
+			name:   "direct market buying the base currency",
+			params: map[string]any{"amount": "10100", "from": "ZAR", "to": "XBT"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).Return(xbtzar, nil)
+			},
+			expectedResult: "0.01000000",
+			expectedRate:   "0.0000009900990099",
+			expectedPath:   []step{{"XBTZAR", "BUY", "1010000", "0.01000000"}},
+		},
+		{
+			name:   "two markets through a common currency",
+			params: map[string]any{"amount": "100", "from": "EUR", "to": "ZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTEUR"}).Return(xbteur, nil)
+				m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).Return(xbtzar, nil)
+			},
+			expectedResult: "2000.00000000",
+			expectedRate:   "20.0000000000000000",
+			expectedPath: []step{
+				{"XBTEUR", "BUY", "50000", "0.00200000"},
+				{"XBTZAR", "SELL", "1000000", "2000.00000000"},
+			},
+		},
+		{
+			name:   "suspended market is not used",
+			params: map[string]any{"amount": "1", "from": "ETH", "to": "ZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "no active market or two market path converts ETH to ZAR",
+		},
+		{
+			name:          "same currency",
+			params:        map[string]any{"amount": "1", "from": "BTC", "to": "XBT"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "Nothing to convert",
+		},
+		{
+			name:          "invalid amount",
+			params:        map[string]any{"amount": "abc", "from": "XBT", "to": "ZAR"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "invalid amount format",
+		},
+		{
+			name:          "missing currency",
+			params:        map[string]any{"amount": "1", "from": "XBT"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "getting to currency from request",
+		},
+		{
+			name:   "no price on the market",
+			params: map[string]any{"amount": "1", "from": "XBT", "to": "ZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+			},
+			expectedError: true,
+			errorContains: "XBTZAR has no current sell price",
+		},
+		{
+			name:   "ticker API error",
+			params: map[string]any{"amount": "1", "from": "XBT", "to": "ZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "getting ticker for XBTZAR",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleConvertAmount(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+
+			if tc.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			var got Conversion
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			assert.Equal(t, tc.expectedResult, got.Result.String())
+			assert.Equal(t, tc.expectedRate, got.Rate.String())
+			require.Len(t, got.Path, len(tc.expectedPath))
+			for i, s := range tc.expectedPath {
+				assert.Equal(t, s.pair, got.Path[i].Pair)
+				assert.Equal(t, s.side, string(got.Path[i].Side))
+				assert.Equal(t, s.price, got.Path[i].Price.String())
+				assert.Equal(t, s.amount, got.Path[i].Amount.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// EstimateOrderCostToolID is the ID of the order cost estimator tool
+const EstimateOrderCostToolID = "estimate_order_cost"
+
+// OrderCostEstimate is the expected cost of a market order including fees
+type OrderCostEstimate struct {
+	Pair     string            `json:"pair"`
+	MidPrice decimal.Decimal   `json:"mid_price"`
+	Fill     ExecutionEstimate `json:"fill"`
+	MakerFee decimal.Decimal   `json:"maker_fee_rate"`
+	TakerFee decimal.Decimal   `json:"taker_fee_rate"`
+	// Fee is the taker fee in the counter currency
+	Fee decimal.Decimal `json:"fee"`
+	// Total is what a buy costs or a sell returns in the counter currency, after fees
+	Total decimal.Decimal `json:"total"`
+	Note  string          `json:"note"`
+}
+
+// NewEstimateOrderCostTool creates a new tool for estimating the cost of an order
+func NewEstimateOrderCostTool() mcp.Tool {
+	return mcp.NewTool(
+		EstimateOrderCostToolID,
+		mcp.WithDescription("Estimate the cost of buying or the proceeds of selling a volume at market: "+
+			"walks the live order book for the average fill price and slippage against the mid price, "+
+			"and applies your taker fee. Your maker fee rate is included for comparison with a limit order."),
+		mcp.WithString(
+			"pair",
+			mcp.Required(),
+			mcp.Description(ErrTradingPairDesc),
+		),
+		mcp.WithString(
+			"side",
+			mcp.Required(),
+			mcp.Enum(orderTypeEnum...),
+			mcp.Description("Order side: BUY or SELL (BID and ASK are accepted as synonyms)"),
+		),
+		mcp.WithString(
+			"volume",
+			mcp.Required(),
+			mcp.Description("Order volume in the base currency"),
+		),
+	)
+}
+
+// HandleEstimateOrderCost handles the estimate_order_cost tool
+func HandleEstimateOrderCost(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		pair, err := request.RequireString("pair")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pair from request", err), nil
+		}
+		pair = market.NormalizePair(pair)
+
+		sideStr, err := request.RequireString("side")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting side from request", err), nil
+		}
+		side, err := ParseOrderSide(sideStr)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		volumeStr, err := request.RequireString("volume")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting volume from request", err), nil
+		}
+		volume, err := parsePositiveDecimal("volume", volumeStr)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		book, err := cfg.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: pair})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting order book", err), nil
+		}
+		analysis, err := analyzeOrderBook(pair, book)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		fees, err := cfg.LunoClient.GetFeeInfo(ctx, &luno.GetFeeInfoRequest{Pair: pair})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting fee info", err), nil
+		}
+		makerFee, err := decimal.NewFromString(fees.MakerFee)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid maker fee %q from API: %v", fees.MakerFee, err)), nil
+		}
+		takerFee, err := decimal.NewFromString(fees.TakerFee)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid taker fee %q from API: %v", fees.TakerFee, err)), nil
+		}
+
+		fill := estimateExecution(book, analysis.MidPrice, side, volume)
+		estimate := OrderCostEstimate{
+			Pair:     pair,
+			MidPrice: analysis.MidPrice,
+			Fill:     fill,
+			MakerFee: makerFee,
+			TakerFee: takerFee,
+			Fee:      fill.Counter.Mul(takerFee),
+			Note:     "Estimate from the current top of the order book. Fees are shown in the counter currency; Luno charges buy fees in the base currency.",
+		}
+		if side == OrderSideBuy {
+			estimate.Total = fill.Counter.Add(estimate.Fee)
+		} else {
+			estimate.Total = fill.Counter.Sub(estimate.Fee)
+		}
+		if !fill.FullyFilled {
+			estimate.Note = fmt.Sprintf("Only %s of %s could be filled from the visible order book. %s",
+				fill.FilledVolume, volume, estimate.Note)
+		}
+
+		resultJSON, err := json.MarshalIndent(estimate, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal cost estimate: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
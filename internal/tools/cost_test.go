@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleEstimateOrderCost(t *testing.T) {
+	fees := &luno.GetFeeInfoResponse{MakerFee: "0.0000", TakerFee: "0.0010"}
+
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+		check         func(*testing.T, OrderCostEstimate)
+	}{
+		{
+			name:   "buy includes taker fee",
+			params: map[string]any{"pair": "XBTZAR", "side": "BUY", "volume": "2"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).Return(testOrderBook(t), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(fees, nil)
+			},
+			check: func(t *testing.T, e OrderCostEstimate) {
+				assertDecimal(t, "100", e.MidPrice)
+				assertDecimal(t, "101.25", e.Fill.AveragePrice)
+				assertDecimal(t, "1.25", e.Fill.SlippagePercent)
+				assertDecimal(t, "0.001", e.TakerFee)
+				assertDecimal(t, "0", e.MakerFee)
+				assertDecimal(t, "0.2025", e.Fee)
+				assertDecimal(t, "202.7025", e.Total)
+				assert.NotContains(t, e.Note, "could be filled")
+			},
+		},
+		{
+			name:   "sell deducts taker fee and reports partial fill",
+			params: map[string]any{"pair": "XBTZAR", "side": "sell", "volume": "20"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(testOrderBook(t), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(fees, nil)
+			},
+			check: func(t *testing.T, e OrderCostEstimate) {
+				assert.False(t, e.Fill.FullyFilled)
+				assertDecimal(t, "1.057", e.Fee)
+				assertDecimal(t, "1055.943", e.Total)
+				assert.Contains(t, e.Note, "Only 11 of 20 could be filled")
+			},
+		},
+		{
+			name:          "invalid side",
+			params:        map[string]any{"pair": "XBTZAR", "side": "HOLD", "volume": "1"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "order type must be one of",
+		},
+		{
+			name:          "missing volume",
+			params:        map[string]any{"pair": "XBTZAR", "side": "BUY"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "getting volume from request",
+		},
+		{
+			name:   "fee info API error",
+			params: map[string]any{"pair": "XBTZAR", "side": "BUY", "volume": "1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(testOrderBook(t), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "getting fee info",
+		},
+		{
+			name:   "invalid fee from API",
+			params: map[string]any{"pair": "XBTZAR", "side": "BUY", "volume": "1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(testOrderBook(t), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(&luno.GetFeeInfoResponse{MakerFee: "0", TakerFee: "n/a"}, nil)
+			},
+			expectedError: true,
+			errorContains: "Invalid taker fee",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleEstimateOrderCost(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+
+			if tc.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			var got OrderCostEstimate
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			tc.check(t, got)
+		})
+	}
+}
@@ -2,24 +2,41 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/internal/market"
 )
 
 // GetMarketInfo returns a detailed description of the market situation
 func GetMarketInfo(ctx context.Context, cfg *config.Config, pair string) (string, error) {
-	// First check if the pair is valid by trying to get ticker info
-	ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: pair})
+	var (
+		ticker    *luno.GetTickerResponse
+		orderBook *luno.GetOrderBookResponse
+	)
+	err := parallel(ctx, cfg,
+		func(ctx context.Context) error {
+			var err error
+			if ticker, err = cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: pair}); err != nil {
+				return fmt.Errorf("could not get market info for %s: %w", pair, err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			var err error
+			if orderBook, err = cfg.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: pair}); err != nil {
+				return fmt.Errorf("could not get order book for %s: %w", pair, err)
+			}
+			return nil
+		},
+	)
 	if err != nil {
-		return "", fmt.Errorf("could not get market info for %s: %w", pair, err)
-	}
-
-	orderBook, err := cfg.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: pair})
-	if err != nil {
-		return "", fmt.Errorf("got ticker but could not get order book for %s: %w", pair, err)
+		return "", err
 	}
 
 	var marketInfo strings.Builder
@@ -52,3 +69,83 @@ func GetMarketInfo(ctx context.Context, cfg *config.Config, pair string) (string
 
 	return marketInfo.String(), nil
 }
+
+// ListMarkets returns market metadata from Luno's markets endpoint.
+// If no pairs are given, all markets are returned.
+func ListMarkets(ctx context.Context, cfg *config.Config, pairs ...string) ([]luno.MarketInfo, error) {
+	res, err := cfg.LunoClient.Markets(ctx, &luno.MarketsRequest{Pair: pairs})
+	if err != nil {
+		return nil, fmt.Errorf("could not list markets: %w", err)
+	}
+	return res.Markets, nil
+}
+
+// ValidatePair checks that the pair is a market on Luno that is open for trading,
+// returning its metadata. For unknown pairs the error suggests similar markets.
+func ValidatePair(ctx context.Context, cfg *config.Config, pair string) (*luno.MarketInfo, error) {
+	markets, err := ListMarkets(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return market.ValidatePair(markets, pair)
+}
+
+// ValidateOrderSize checks a limit order's volume and price against the market's
+// precision and size limits. The error explains what is wrong and, for precision
+// problems, suggests a value the market will accept.
+func ValidateOrderSize(info *luno.MarketInfo, volume, price decimal.Decimal) error {
+	if err := checkDecimal("volume", info.MarketId, volume, int(info.VolumeScale), info.MinVolume, info.MaxVolume); err != nil {
+		return err
+	}
+	return checkDecimal("price", info.MarketId, price, int(info.PriceScale), info.MinPrice, info.MaxPrice)
+}
+
+// reserveOrderValue checks an order's value against the configured risk
+// limits and counts it towards the session's total for today. The returned
+// function undoes the reservation and must be called if the order is not
+// placed.
+func reserveOrderValue(ctx context.Context, cfg *config.Config, info *luno.MarketInfo, volume, price decimal.Decimal) (func(), error) {
+	limits := cfg.Settings().Limits
+	if limits == nil {
+		return func() {}, nil
+	}
+	return limits.Reserve(sessionID(ctx), info.MarketId, info.CounterCurrency, volume.Mul(price))
+}
+
+// checkPairAllowed returns an error listing the permitted pairs when the
+// configuration restricts trading and the pair is not one of them
+func checkPairAllowed(cfg *config.Config, pair string) error {
+	allowedPairs := cfg.Settings().AllowedPairs
+	if len(allowedPairs) == 0 {
+		return nil
+	}
+	pair = market.NormalizePair(pair)
+	for _, allowed := range allowedPairs {
+		if market.NormalizePair(allowed) == pair {
+			return nil
+		}
+	}
+	return lunoerr.New(lunoerr.PermissionDenied, "trading %s is not allowed, this server only trades %s (set by %s)",
+		pair, strings.Join(allowedPairs, ", "), config.EnvLunoAllowedPairs)
+}
+
+// checkDecimal validates a single order value. Zero limits are treated as unset.
+func checkDecimal(field, pair string, value decimal.Decimal, scale int, minValue, maxValue decimal.Decimal) error {
+	if value.Sign() <= 0 {
+		return lunoerr.New(lunoerr.InvalidArgument, "%s must be greater than zero, got %s", field, value.String())
+	}
+	if rounded := value.ToScale(scale); rounded.Cmp(value) != 0 {
+		msg := fmt.Sprintf("%s %s has more than %d decimal places, which %s does not allow", field, value.String(), scale, pair)
+		if rounded.Sign() > 0 {
+			msg += fmt.Sprintf(", try %s instead", rounded.String())
+		}
+		return lunoerr.Wrap(lunoerr.InvalidArgument, errors.New(msg))
+	}
+	if minValue.Sign() > 0 && value.Cmp(minValue) < 0 {
+		return lunoerr.New(lunoerr.InvalidArgument, "%s %s is below the minimum of %s for %s", field, value.String(), minValue.String(), pair)
+	}
+	if maxValue.Sign() > 0 && value.Cmp(maxValue) > 0 {
+		return lunoerr.New(lunoerr.InvalidArgument, "%s %s is above the maximum of %s for %s", field, value.String(), maxValue.String(), pair)
+	}
+	return nil
+}
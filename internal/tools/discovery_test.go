@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePair(t *testing.T) {
+	tests := []struct {
+		name          string
+		pair          string
+		marketsErr    error
+		expectedError string
+		expectedCode  lunoerr.Code
+	}{
+		{name: "active market", pair: "XBTZAR"},
+		{name: "suspended market", pair: "ETHZAR", expectedError: "not open for trading", expectedCode: lunoerr.MarketUnavailable},
+		{name: "unknown pair with suggestions", pair: "XBTUSD", expectedError: "did you mean one of: XBTZAR, XBTEUR", expectedCode: lunoerr.InvalidPair},
+		{name: "unknown pair without suggestions", pair: "DOGEUSD", expectedError: "DOGEUSD is not a valid Luno market", expectedCode: lunoerr.InvalidPair},
+		{name: "markets API error", pair: "XBTZAR", marketsErr: errors.New(apiErrorStr), expectedError: "could not list markets", expectedCode: lunoerr.Unknown},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			if tc.marketsErr != nil {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(nil, tc.marketsErr)
+			} else {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			}
+
+			market, err := ValidatePair(context.Background(), &config.Config{LunoClient: mockClient}, tc.pair)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Equal(t, tc.expectedCode, lunoerr.Classify(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.pair, market.MarketId)
+		})
+	}
+}
+
+func TestValidateOrderSize(t *testing.T) {
+	xbtzar := &testMarketsResponse().Markets[0]
+	xbteur := &testMarketsResponse().Markets[2]
+
+	tests := []struct {
+		name          string
+		market        *luno.MarketInfo
+		volume        string
+		price         string
+		expectedError string
+		notContains   string
+	}{
+		{name: "valid order", market: xbtzar, volume: "0.01", price: "1000000"},
+		{name: "trailing zeros are allowed", market: xbtzar, volume: "0.010000000", price: "1000000.00"},
+		{name: "no limits on market", market: xbteur, volume: "1000", price: "0.01"},
+		{name: "volume too precise", market: xbtzar, volume: "0.0123456789", price: "1000000", expectedError: "volume 0.0123456789 has more than 6 decimal places, which XBTZAR does not allow, try 0.012345 instead"},
+		{name: "price too precise", market: xbtzar, volume: "0.01", price: "1000000.5", expectedError: "price 1000000.5 has more than 0 decimal places, which XBTZAR does not allow, try 1000000 instead"},
+		{name: "rounds to zero without suggestion", market: xbteur, volume: "0.01", price: "0.001", expectedError: "price 0.001 has more than 2 decimal places, which XBTEUR does not allow", notContains: "try"},
+		{name: "volume below minimum", market: xbtzar, volume: "0.0001", price: "1000000", expectedError: "volume 0.0001 is below the minimum of 0.0005 for XBTZAR"},
+		{name: "volume above maximum", market: xbtzar, volume: "101", price: "1000000", expectedError: "volume 101 is above the maximum of 100 for XBTZAR"},
+		{name: "price below minimum", market: xbtzar, volume: "0.01", price: "10", expectedError: "price 10 is below the minimum of 100 for XBTZAR"},
+		{name: "price above maximum", market: xbtzar, volume: "0.01", price: "20000000", expectedError: "price 20000000 is above the maximum of 10000000 for XBTZAR"},
+		{name: "zero volume", market: xbtzar, volume: "0", price: "1000000", expectedError: "volume must be greater than zero"},
+		{name: "negative price", market: xbtzar, volume: "0.01", price: "-1", expectedError: "price must be greater than zero"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateOrderSize(tc.market, NewFromString(t, tc.volume), NewFromString(t, tc.price))
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				if tc.notContains != "" {
+					assert.NotContains(t, err.Error(), tc.notContains)
+				}
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
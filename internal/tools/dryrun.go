@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// dryRunParam is the argument that requests a dry run of a single call
+const dryRunParam = "dry_run"
+
+// DryRunResult reports what a write tool would have submitted
+type DryRunResult struct {
+	DryRun      bool   `json:"dry_run"`
+	Action      string `json:"action"`
+	WouldSubmit any    `json:"would_submit"`
+	Note        string `json:"note"`
+}
+
+// withDryRun adds the dry_run parameter to write tools
+func withDryRun() mcp.ToolOption {
+	return mcp.WithBoolean(
+		dryRunParam,
+		mcp.Description("Validate the call and return what would be submitted, without submitting anything. "+
+			"Always true when the server runs in dry-run mode."),
+	)
+}
+
+// isDryRun reports whether a write should be validated but not submitted,
+// either because the server is in dry-run mode or the caller asked for it
+func isDryRun(cfg *config.Config, request mcp.CallToolRequest) bool {
+	return cfg.DryRun || request.GetBool(dryRunParam, false)
+}
+
+// dryRunResult reports what a write tool would have submitted
+func dryRunResult(toolID string, wouldSubmit any) *mcp.CallToolResult {
+	result := DryRunResult{
+		DryRun:      true,
+		Action:      toolID,
+		WouldSubmit: wouldSubmit,
+		Note:        "Dry run: the request was validated but nothing was submitted to Luno.",
+	}
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal dry run result: %v", err))
+	}
+	return mcp.NewToolResultText(string(resultJSON))
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	marketInfo := func(m *sdk.MockLunoClient) {
+		m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+		expectOrderBalance(m)
+		m.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR", Status: "ACTIVE"}, nil)
+		m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(testOrderBook(t), nil)
+	}
+	order := map[string]any{"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "1000000"}
+
+	tests := []struct {
+		name      string
+		handler   func(*config.Config) server.ToolHandlerFunc
+		params    map[string]any
+		serverDry bool
+		mockSetup func(*sdk.MockLunoClient)
+		action    string
+		contains  string
+	}{
+		{
+			name:      "create order in dry-run mode",
+			handler:   HandleCreateOrder,
+			params:    order,
+			serverDry: true,
+			mockSetup: marketInfo,
+			action:    CreateOrderToolID,
+			contains:  `"total": "10000.00 ZAR"`,
+		},
+		{
+			name:    "create order with dry_run argument",
+			handler: HandleCreateOrder,
+			params: map[string]any{
+				"pair": "XBTZAR", "type": "SELL", "volume": "0.01", "price": "1000000", "dry_run": true,
+			},
+			mockSetup: marketInfo,
+			action:    CreateOrderToolID,
+			contains:  `"side": "SELL"`,
+		},
+		{
+			name:    "cancel order",
+			handler: HandleCancelOrder,
+			params:  map[string]any{"order_id": "123", "dry_run": true},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "123"}).
+					Return(&luno.GetOrderV3Response{OrderId: "123", Pair: "XBTZAR"}, nil)
+			},
+			action:   CancelOrderToolID,
+			contains: `"pair": "XBTZAR"`,
+		},
+		{
+			name:    "place order set",
+			handler: HandlePlaceOrderSet,
+			params: map[string]any{
+				"orders":  []any{map[string]any{"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "1000000"}},
+				"dry_run": true,
+			},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(testOrderSetFees(), nil)
+			},
+			action:   PlaceOrderSetToolID,
+			contains: `"outcome": "not_placed"`,
+		},
+		{
+			name:      "create account",
+			handler:   HandleCreateAccount,
+			params:    map[string]any{"currency": "xbt", "name": "Savings"},
+			serverDry: true,
+			mockSetup: func(m *sdk.MockLunoClient) {},
+			action:    CreateAccountToolID,
+			contains:  `"currency": "XBT"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// The mock fails the test if anything is submitted
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+			cfg := &config.Config{LunoClient: mockClient, DryRun: tc.serverDry}
+
+			result, err := tc.handler(cfg)(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			require.False(t, result.IsError, text)
+
+			var got DryRunResult
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			assert.True(t, got.DryRun)
+			assert.Equal(t, tc.action, got.Action)
+			assert.Contains(t, text, tc.contains)
+		})
+	}
+}
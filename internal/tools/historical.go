@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetHistoricalPriceToolID is the ID of the historical price tool
+const GetHistoricalPriceToolID = "get_historical_price"
+
+// candleDurations are the candle durations supported by the API, in seconds
+var candleDurations = []int64{60, 300, 900, 1800, 3600, 10800, 14400, 28800, 86400, 259200, 604800}
+
+// defaultCandleDuration is an hour, fine enough for reporting while still
+// covering gaps in trading with few candles
+const defaultCandleDuration = 3600
+
+// candleSearchWindow is how many candle durations before the requested time
+// are searched when the market did not trade at that time
+const candleSearchWindow = 24
+
+// timestampLayouts are the accepted timestamp formats, besides Unix milliseconds
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// HistoricalPrice is the candle closest to a requested time
+type HistoricalPrice struct {
+	Pair            string          `json:"pair"`
+	RequestedTime   time.Time       `json:"requested_time"`
+	CandleStart     time.Time       `json:"candle_start"`
+	CandleEnd       time.Time       `json:"candle_end"`
+	DurationSeconds int64           `json:"duration_seconds"`
+	Close           decimal.Decimal `json:"close"`
+	Open            decimal.Decimal `json:"open"`
+	High            decimal.Decimal `json:"high"`
+	Low             decimal.Decimal `json:"low"`
+	Volume          decimal.Decimal `json:"volume"`
+	// Exact is false when there was no trading in the candle containing the
+	// requested time and the nearest candle was used instead
+	Exact bool `json:"exact"`
+}
+
+// NewGetHistoricalPriceTool creates a new tool for getting the price of a pair at a past time
+func NewGetHistoricalPriceTool() mcp.Tool {
+	return mcp.NewTool(
+		GetHistoricalPriceToolID,
+		mcp.WithDescription("Get the price of a trading pair at a past date or time, from the close of the nearest candle. "+
+			"Useful for tax and reporting questions such as the value of a trade on a given day."),
+		mcp.WithString(
+			"pair",
+			mcp.Required(),
+			mcp.Description(ErrTradingPairDesc),
+		),
+		mcp.WithString(
+			"timestamp",
+			mcp.Required(),
+			mcp.Description("Time to get the price at: a date (YYYY-MM-DD, uses the close at the end of that day in UTC), "+
+				"an RFC 3339 time (e.g., 2024-03-01T12:00:00Z, UTC if no offset is given) or Unix milliseconds"),
+		),
+		mcp.WithNumber(
+			"duration",
+			mcp.Description("Candle duration in seconds (default: 3600). One of 60, 300, 900, 1800, 3600, 10800, 14400, 28800, 86400, 259200 or 604800."),
+		),
+	)
+}
+
+// HandleGetHistoricalPrice handles the get_historical_price tool
+func HandleGetHistoricalPrice(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		pair, err := request.RequireString("pair")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pair from request", err), nil
+		}
+		pair = market.NormalizePair(pair)
+
+		timestampStr, err := request.RequireString("timestamp")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting timestamp from request", err), nil
+		}
+		target, err := parseTimestamp(timestampStr)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if target.After(time.Now()) {
+			return mcp.NewToolResultError(fmt.Sprintf("Timestamp %s is in the future, use get_ticker for the current price", target.Format(time.RFC3339))), nil
+		}
+
+		duration := int64(request.GetInt("duration", defaultCandleDuration))
+		if !slices.Contains(candleDurations, duration) {
+			return mcp.NewToolResultError(fmt.Sprintf("Unsupported candle duration %d, must be one of 60, 300, 900, 1800, 3600, 10800, 14400, 28800, 86400, 259200 or 604800", duration)), nil
+		}
+		step := time.Duration(duration) * time.Second
+
+		candles, err := cfg.LunoClient.GetCandles(ctx, &luno.GetCandlesRequest{
+			Pair:     pair,
+			Duration: duration,
+			Since:    luno.Time(target.Truncate(step).Add(-candleSearchWindow * step)),
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting candles", err), nil
+		}
+
+		candle, ok := closestCandle(candles.Candles, target, step)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("No trades on %s around %s", pair, target.Format(time.RFC3339))), nil
+		}
+
+		start := time.Time(candle.Timestamp).UTC()
+		price := HistoricalPrice{
+			Pair:            pair,
+			RequestedTime:   target,
+			CandleStart:     start,
+			CandleEnd:       start.Add(step),
+			DurationSeconds: duration,
+			Close:           candle.Close,
+			Open:            candle.Open,
+			High:            candle.High,
+			Low:             candle.Low,
+			Volume:          candle.Volume,
+			Exact:           !target.Before(start) && target.Before(start.Add(step)),
+		}
+
+		resultJSON, err := json.MarshalIndent(price, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal historical price: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// parseTimestamp parses a date, a time or Unix milliseconds. A date on its
+// own is the end of that day, so its price is the day's closing price.
+func parseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	if d, err := time.Parse(time.DateOnly, s); err == nil {
+		return d.Add(24*time.Hour - time.Millisecond), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q, use a date (YYYY-MM-DD), an RFC 3339 time or Unix milliseconds", s)
+}
+
+// closestCandle returns the candle containing target, or the candle whose
+// close is nearest to it. Candles that start after target are ignored.
+func closestCandle(candles []luno.Candle, target time.Time, step time.Duration) (luno.Candle, bool) {
+	var (
+		best     luno.Candle
+		bestDist time.Duration
+		found    bool
+	)
+	for _, c := range candles {
+		start := time.Time(c.Timestamp)
+		if start.After(target) {
+			continue
+		}
+		dist := target.Sub(start.Add(step))
+		if dist < 0 {
+			// The candle contains target
+			return c, true
+		}
+		if !found || dist < bestDist {
+			best, bestDist, found = c, dist, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      time.Time
+		expectedError bool
+	}{
+		{"date is end of day", "2024-03-01", time.Date(2024, 3, 1, 23, 59, 59, 999e6, time.UTC), false},
+		{"RFC 3339", "2024-03-01T12:30:00Z", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), false},
+		{"RFC 3339 with offset", "2024-03-01T14:30:00+02:00", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), false},
+		{"time without zone", "2024-03-01 12:30", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), false},
+		{"unix milliseconds", "1709296200000", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), false},
+		{"invalid", "yesterday", time.Time{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseTimestamp(tc.input)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(got), "expected %s, got %s", tc.expected, got)
+		})
+	}
+}
+
+func TestHandleGetHistoricalPrice(t *testing.T) {
+	hour := func(h int) luno.Time { return luno.Time(time.Date(2024, 3, 1, h, 0, 0, 0, time.UTC)) }
+	candle := func(h int, closePrice int64) luno.Candle {
+		return luno.Candle{Timestamp: hour(h), Close: decimal.NewFromInt64(closePrice)}
+	}
+
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+		expectedClose string
+		expectedStart time.Time
+		expectedExact bool
+	}{
+		{
+			name:   "candle containing the time",
+			params: map[string]any{"pair": "btc/zar", "timestamp": "2024-03-01T12:30:00Z"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetCandles(mock.Anything, &luno.GetCandlesRequest{
+					Pair: "XBTZAR", Duration: 3600, Since: luno.Time(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)),
+				}).Return(&luno.GetCandlesResponse{Candles: []luno.Candle{candle(11, 100), candle(12, 200), candle(13, 300)}}, nil)
+			},
+			expectedClose: "200",
+			expectedStart: time.Time(hour(12)),
+			expectedExact: true,
+		},
+		{
+			name:   "no trading at the time uses the latest earlier candle",
+			params: map[string]any{"pair": "XBTZAR", "timestamp": "2024-03-01T12:30:00Z"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetCandles(mock.Anything, mock.Anything).
+					Return(&luno.GetCandlesResponse{Candles: []luno.Candle{candle(9, 100), candle(10, 150), candle(14, 300)}}, nil)
+			},
+			expectedClose: "150",
+			expectedStart: time.Time(hour(10)),
+			expectedExact: false,
+		},
+		{
+			name:   "no candles",
+			params: map[string]any{"pair": "XBTZAR", "timestamp": "2024-03-01"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetCandles(mock.Anything, mock.Anything).Return(&luno.GetCandlesResponse{}, nil)
+			},
+			expectedError: true,
+			errorContains: "No trades on XBTZAR",
+		},
+		{
+			name:          "future timestamp",
+			params:        map[string]any{"pair": "XBTZAR", "timestamp": "2999-01-01"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "is in the future",
+		},
+		{
+			name:          "unsupported duration",
+			params:        map[string]any{"pair": "XBTZAR", "timestamp": "2024-03-01", "duration": float64(120)},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "Unsupported candle duration 120",
+		},
+		{
+			name:          "invalid timestamp",
+			params:        map[string]any{"pair": "XBTZAR", "timestamp": "last week"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "invalid timestamp",
+		},
+		{
+			name:   "API error",
+			params: map[string]any{"pair": "XBTZAR", "timestamp": "2024-03-01"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetCandles(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "getting candles",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleGetHistoricalPrice(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+
+			if tc.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			var got HistoricalPrice
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			assert.Equal(t, "XBTZAR", got.Pair)
+			assert.Equal(t, tc.expectedClose, got.Close.String())
+			assert.True(t, tc.expectedStart.Equal(got.CandleStart), "expected start %s, got %s", tc.expectedStart, got.CandleStart)
+			assert.Equal(t, tc.expectedExact, got.Exact)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/lunoerr"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/luno/luno-mcp/internal/security"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MoveFundsToolID is the ID of the tool that moves funds between accounts
+const MoveFundsToolID = "move_funds"
+
+// clientMoveIDParam is the argument that names a move so it is only made once
+const clientMoveIDParam = "client_move_id"
+
+// MoveResult is a move made, or found already made, by move_funds
+type MoveResult struct {
+	MoveID          string          `json:"move_id"`
+	ClientMoveID    string          `json:"client_move_id"`
+	Status          luno.Status     `json:"status"`
+	Amount          decimal.Decimal `json:"amount"`
+	Currency        string          `json:"currency,omitempty"`
+	DebitAccountID  string          `json:"debit_account_id"`
+	CreditAccountID string          `json:"credit_account_id"`
+	// AlreadyMade is set when the client move ID had been used, so the
+	// earlier move is returned instead of moving the funds again
+	AlreadyMade bool `json:"already_made,omitempty"`
+}
+
+// NewMoveFundsTool creates a new tool for moving funds between your own accounts
+func NewMoveFundsTool() mcp.Tool {
+	return mcp.NewTool(
+		MoveFundsToolID,
+		mcp.WithDescription("Move funds between two of your own Luno accounts in the same currency, "+
+			"e.g. from your main XBT account to a savings XBT account. Nothing leaves Luno. "+
+			"Get account IDs from get_balances."),
+		mcp.WithString(
+			"from_account_id",
+			mcp.Required(),
+			mcp.Description("ID of the account to move the funds out of"),
+		),
+		mcp.WithString(
+			"to_account_id",
+			mcp.Required(),
+			mcp.Description("ID of the account to move the funds into, in the same currency"),
+		),
+		mcp.WithString(
+			"amount",
+			mcp.Required(),
+			mcp.Description("Amount to move, in the accounts' currency. "+amountDesc),
+		),
+		mcp.WithString(
+			clientMoveIDParam,
+			mcp.Description("Your own unique ID for the move, up to 255 letters, digits or _ ; , . - characters. "+
+				"A move with an ID that was already used is not made again, so reuse the ID when retrying after a timeout. "+
+				"An ID already used for different accounts or a different amount is rejected. "+
+				"One is generated when omitted."),
+		),
+		withConfirmToken(),
+		withDryRun(),
+	)
+}
+
+// HandleMoveFunds handles the move_funds tool
+func HandleMoveFunds(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		from, err := requireAccountID(request, "from_account_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		to, err := requireAccountID(request, "to_account_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if from == to {
+			return mcp.NewToolResultError("from_account_id and to_account_id must be different accounts"), nil
+		}
+
+		amountStr, err := request.RequireString("amount")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting amount from request", err), nil
+		}
+		amount, amountCurrency, err := parseAmount(amountStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid amount format: %v", err)), nil
+		}
+		if amount.Sign() <= 0 {
+			return mcp.NewToolResultError("amount must be greater than zero"), nil
+		}
+
+		moveID, givenID, err := clientMoveID(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid client move ID", err), nil
+		}
+
+		// A retry of a move that was made returns that move rather than
+		// moving the funds twice, once it is previewed and confirmed like
+		// any other
+		var existing *luno.GetMoveResponse
+		if givenID {
+			if m, err := cfg.LunoClient.GetMove(ctx, &luno.GetMoveRequest{ClientMoveId: moveID}); err == nil {
+				if err := checkSameMove(m, from, to, amount); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Unable to move funds: %v", err)), nil
+				}
+				existing = m
+			}
+		}
+
+		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get balances: %v", err)), nil
+		}
+		// The funds of a move already made have left the debit account
+		check := checkMove
+		if existing != nil {
+			check = checkMoveAccounts
+		}
+		currency, err := check(balances.Balance, from, to, amount, amountCurrency)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to move funds: %v", err)), nil
+		}
+
+		preview := map[string]string{
+			"from_account_id": strconv.FormatInt(from, 10),
+			"to_account_id":   strconv.FormatInt(to, 10),
+			"amount":          amount.String() + " " + currency,
+		}
+		details := []string{preview["from_account_id"], preview["to_account_id"], amount.String()}
+		if givenID {
+			preview[clientMoveIDParam] = moveID
+			details = append(details, moveID)
+		}
+		if isDryRun(cfg, request) {
+			return dryRunResult(MoveFundsToolID, preview), nil
+		}
+		binding := strings.Join(details, "\n")
+		if res := requireConfirmation(cfg, request, MoveFundsToolID, binding, preview); res != nil {
+			return res, nil
+		}
+		if existing != nil {
+			return moveResult(MoveResult{
+				MoveID:          existing.Id,
+				ClientMoveID:    existing.ClientMoveId,
+				Status:          existing.Status,
+				Amount:          existing.Amount,
+				Currency:        currency,
+				DebitAccountID:  existing.DebitAccountId,
+				CreditAccountID: existing.CreditAccountId,
+				AlreadyMade:     true,
+			})
+		}
+
+		finish, err := beginSubmission(ctx, cfg, moveSubmission, moveID, givenID, binding)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to move funds: %v", err)), nil
+		}
+		move, err := cfg.LunoClient.Move(ctx, &luno.MoveRequest{
+			Amount:          amount,
+			DebitAccountId:  from,
+			CreditAccountId: to,
+			ClientMoveId:    moveID,
+		})
+		if err != nil {
+			finish("", err)
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to move funds: %v", err)), nil
+		}
+		finish(move.Id, nil)
+
+		return moveResult(MoveResult{
+			MoveID:          move.Id,
+			ClientMoveID:    moveID,
+			Status:          move.Status,
+			Amount:          amount,
+			Currency:        currency,
+			DebitAccountID:  preview["from_account_id"],
+			CreditAccountID: preview["to_account_id"],
+		})
+	}
+}
+
+// requireAccountID reads a numeric account ID argument
+func requireAccountID(request mcp.CallToolRequest, name string) (int64, error) {
+	s, err := request.RequireString(name)
+	if err != nil {
+		return 0, fmt.Errorf("getting %s from request: %w", name, err)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, lunoerr.New(lunoerr.InvalidArgument, "invalid %s %q, use a numeric account ID from get_balances", name, s)
+	}
+	return id, nil
+}
+
+// clientMoveID returns the client move ID of a request, or a new one if it
+// has none. given reports whether the caller chose the ID.
+func clientMoveID(request mcp.CallToolRequest) (id string, given bool, err error) {
+	if id := request.GetString(clientMoveIDParam, ""); id != "" {
+		if !clientOrderIDPattern.MatchString(id) {
+			return "", false, fmt.Errorf("%s must be 1 to 255 letters, digits or _ ; , . - characters", clientMoveIDParam)
+		}
+		return id, true, nil
+	}
+	token, err := security.NewToken()
+	if err != nil {
+		return "", false, err
+	}
+	return "mcp-" + token, false, nil
+}
+
+// checkSameMove checks that a move found by its client move ID is the one
+// being asked for, so that a reused ID isn't mistaken for a retry
+func checkSameMove(m *luno.GetMoveResponse, from, to int64, amount decimal.Decimal) error {
+	if m.DebitAccountId == strconv.FormatInt(from, 10) && m.CreditAccountId == strconv.FormatInt(to, 10) && m.Amount.Cmp(amount) == 0 {
+		return nil
+	}
+	return lunoerr.New(lunoerr.InvalidArgument, "%s %s was already used to move %s from account %s to account %s, give a new one for a different move",
+		clientMoveIDParam, m.ClientMoveId, m.Amount.String(), m.DebitAccountId, m.CreditAccountId)
+}
+
+// checkMove checks that both accounts are the user's, hold the same
+// currency and that the debit account can fund the move. It returns the
+// accounts' currency.
+func checkMove(balances []luno.AccountBalance, from, to int64, amount decimal.Decimal, amountCurrency string) (string, error) {
+	currency, err := checkMoveAccounts(balances, from, to, amount, amountCurrency)
+	if err != nil {
+		return "", err
+	}
+	debit := findAccount(balances, from)
+	if available := debit.Balance.Sub(debit.Reserved); available.Cmp(amount) < 0 {
+		return "", lunoerr.New(lunoerr.InsufficientBalance, "insufficient %s balance: account %d has %s %s available",
+			currency, from, available.String(), currency)
+	}
+	return currency, nil
+}
+
+// checkMoveAccounts is checkMove without the check that the debit account
+// can fund the move
+func checkMoveAccounts(balances []luno.AccountBalance, from, to int64, amount decimal.Decimal, amountCurrency string) (string, error) {
+	debit, credit := findAccount(balances, from), findAccount(balances, to)
+	if debit == nil {
+		return "", lunoerr.New(lunoerr.NotFound, "account %d not found, use an account ID from get_balances", from)
+	}
+	if credit == nil {
+		return "", lunoerr.New(lunoerr.NotFound, "account %d not found, use an account ID from get_balances", to)
+	}
+	currency := debit.Asset
+	if credit.Asset != currency {
+		return "", lunoerr.New(lunoerr.InvalidArgument, "funds can only be moved between accounts in the same currency, account %d holds %s and account %d holds %s",
+			from, currency, to, credit.Asset)
+	}
+	if amountCurrency != "" && amountCurrency != currency {
+		return "", lunoerr.New(lunoerr.InvalidArgument, "the amount is in %s but the accounts hold %s", amountCurrency, currency)
+	}
+	if asset, ok := market.FindAsset(currency); ok {
+		if err := checkDecimal("amount", currency, amount, asset.Decimals, decimal.Zero(), decimal.Zero()); err != nil {
+			return "", err
+		}
+	}
+	return currency, nil
+}
+
+// findAccount returns the balance of an account, or nil if it isn't listed
+func findAccount(balances []luno.AccountBalance, id int64) *luno.AccountBalance {
+	for i, b := range balances {
+		if b.AccountId == strconv.FormatInt(id, 10) {
+			return &balances[i]
+		}
+	}
+	return nil
+}
+
+// moveResult renders a move as JSON
+func moveResult(result MoveResult) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal move: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/idempotency"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testMoveBalances() *luno.GetBalancesResponse {
+	return &luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{AccountId: "100", Asset: "XBT", Balance: decimal.NewFromFloat64(1.5, 8), Reserved: decimal.NewFromFloat64(0.5, 8)},
+		{AccountId: "200", Asset: "XBT", Balance: decimal.Zero()},
+		{AccountId: "300", Asset: "ZAR", Balance: decimal.NewFromInt64(1000)},
+	}}
+}
+
+// testMadeMove is a completed move of 1.25 XBT from account 100 to 200
+func testMadeMove() *luno.GetMoveResponse {
+	return &luno.GetMoveResponse{
+		Id: "m1", ClientMoveId: "save-1", Status: luno.StatusComplete, Amount: decimal.NewFromFloat64(1.25, 2),
+		DebitAccountId: "100", CreditAccountId: "200",
+	}
+}
+
+func TestHandleMoveFunds(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expected      *MoveResult
+		errorContains string
+	}{
+		{
+			name:   "move",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "0.25 BTC", "client_move_id": "save-1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetMove(mock.Anything, &luno.GetMoveRequest{ClientMoveId: "save-1"}).Return(nil, errors.New("not found (ErrNotFound)"))
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+				m.EXPECT().Move(mock.Anything, &luno.MoveRequest{
+					Amount: decimal.NewFromFloat64(0.25, 2), DebitAccountId: 100, CreditAccountId: 200, ClientMoveId: "save-1",
+				}).Return(&luno.MoveResponse{Id: "m1", Status: luno.StatusCreated}, nil)
+			},
+			expected: &MoveResult{
+				MoveID: "m1", ClientMoveID: "save-1", Status: luno.StatusCreated, Amount: decimal.NewFromFloat64(0.25, 2),
+				Currency: "XBT", DebitAccountID: "100", CreditAccountID: "200",
+			},
+		},
+		{
+			name:   "retry returns the move already made",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "1.25", "client_move_id": "save-1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetMove(mock.Anything, &luno.GetMoveRequest{ClientMoveId: "save-1"}).Return(testMadeMove(), nil)
+				// The funds have already left account 100
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+			expected: &MoveResult{
+				MoveID: "m1", ClientMoveID: "save-1", Status: luno.StatusComplete, Amount: decimal.NewFromFloat64(1.25, 2),
+				Currency: "XBT", DebitAccountID: "100", CreditAccountID: "200", AlreadyMade: true,
+			},
+		},
+		{
+			name:   "reused move ID for a different amount",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "0.5", "client_move_id": "save-1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetMove(mock.Anything, mock.Anything).Return(testMadeMove(), nil)
+			},
+			errorContains: "client_move_id save-1 was already used to move 1.25 from account 100 to account 200",
+		},
+		{
+			name:   "reused move ID for different accounts",
+			params: map[string]any{"from_account_id": "200", "to_account_id": "100", "amount": "1.25", "client_move_id": "save-1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetMove(mock.Anything, mock.Anything).Return(testMadeMove(), nil)
+			},
+			errorContains: "give a new one for a different move",
+		},
+		{
+			name: "dry run of a move already made previews it",
+			params: map[string]any{
+				"from_account_id": "100", "to_account_id": "200", "amount": "1.25", "client_move_id": "save-1", "dry_run": true,
+			},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetMove(mock.Anything, mock.Anything).Return(testMadeMove(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+		},
+		{
+			name:   "insufficient balance",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "1.2"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+			errorContains: "insufficient XBT balance: account 100 has 1.00000000 XBT available",
+		},
+		{
+			name:   "different currencies",
+			params: map[string]any{"from_account_id": "300", "to_account_id": "200", "amount": "10"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+			errorContains: "same currency",
+		},
+		{
+			name:   "amount in another currency",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "R10"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+			errorContains: "the amount is in ZAR but the accounts hold XBT",
+		},
+		{
+			name:   "too many decimals",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "0.123456789"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+			errorContains: "more than 8 decimal places",
+		},
+		{
+			name:   "unknown account",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "999", "amount": "0.1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+			errorContains: "account 999 not found",
+		},
+		{
+			name:          "same account",
+			params:        map[string]any{"from_account_id": "100", "to_account_id": "100", "amount": "0.1"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "must be different accounts",
+		},
+		{
+			name:          "negative amount",
+			params:        map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "-1"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "amount must be greater than zero",
+		},
+		{
+			name:          "invalid account ID",
+			params:        map[string]any{"from_account_id": "main", "to_account_id": "200", "amount": "1"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: `invalid from_account_id "main"`,
+		},
+		{
+			name:          "invalid client move ID",
+			params:        map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "1", "client_move_id": "has spaces"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "client_move_id must be",
+		},
+		{
+			name:   "dry run",
+			params: map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "0.5", "dry_run": true},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleMoveFunds(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError, text)
+			if tc.expected == nil {
+				assert.Contains(t, text, `"dry_run": true`)
+				return
+			}
+
+			var got MoveResult
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			assert.Equal(t, tc.expected.MoveID, got.MoveID)
+			assert.Equal(t, tc.expected.Status, got.Status)
+			assertDecimal(t, tc.expected.Amount.String(), got.Amount)
+			got.Amount = tc.expected.Amount
+			assert.Equal(t, *tc.expected, got)
+		})
+	}
+}
+
+func TestHandleMoveFundsRejectsResubmission(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testMoveBalances(), nil)
+	mockClient.EXPECT().Move(mock.Anything, mock.Anything).Return(&luno.MoveResponse{Id: "m1"}, nil).Once()
+	cfg := &config.Config{LunoClient: mockClient, Submissions: idempotency.NewStore(time.Minute)}
+	params := map[string]any{"from_account_id": "100", "to_account_id": "200", "amount": "0.1"}
+
+	result, err := HandleMoveFunds(cfg)(context.Background(), createMockRequest(params))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	result, err = HandleMoveFunds(cfg)(context.Background(), createMockRequest(params))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getTextContentFromResult(t, result), "duplicate move submission")
+}
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AnalyzeOrderBookToolID is the ID of the order book analysis tool
+const AnalyzeOrderBookToolID = "analyze_order_book"
+
+// Decimal places kept for computed prices and percentages
+const (
+	priceScale   = 8
+	percentScale = 4
+)
+
+// depthBands are the distances from the mid price, in percent, at which
+// cumulative depth is reported
+var depthBands = []int64{1, 2, 5}
+
+// DepthBand is the volume available within a distance of the mid price
+type DepthBand struct {
+	Percent int64 `json:"percent"`
+	// BidVolume and BidValue are the base volume and counter value of bids
+	// priced at or above mid minus Percent
+	BidVolume decimal.Decimal `json:"bid_volume"`
+	BidValue  decimal.Decimal `json:"bid_value"`
+	// AskVolume and AskValue are the base volume and counter value of asks
+	// priced at or below mid plus Percent
+	AskVolume decimal.Decimal `json:"ask_volume"`
+	AskValue  decimal.Decimal `json:"ask_value"`
+}
+
+// ExecutionEstimate is the expected result of filling a market order
+// against the current order book
+type ExecutionEstimate struct {
+	Side            OrderSide       `json:"side"`
+	Volume          decimal.Decimal `json:"volume"`
+	FilledVolume    decimal.Decimal `json:"filled_volume"`
+	FullyFilled     bool            `json:"fully_filled"`
+	AveragePrice    decimal.Decimal `json:"average_price"`
+	WorstPrice      decimal.Decimal `json:"worst_price"`
+	Counter         decimal.Decimal `json:"counter"`
+	SlippagePercent decimal.Decimal `json:"slippage_percent"`
+}
+
+// TopOfBook is the best prices of an order book and the gap between them
+type TopOfBook struct {
+	BestBid       decimal.Decimal `json:"best_bid"`
+	BestAsk       decimal.Decimal `json:"best_ask"`
+	MidPrice      decimal.Decimal `json:"mid_price"`
+	Spread        decimal.Decimal `json:"spread"`
+	SpreadPercent decimal.Decimal `json:"spread_percent"`
+}
+
+// OrderBookAnalysis summarises an order book
+type OrderBookAnalysis struct {
+	Pair string `json:"pair"`
+	TopOfBook
+	Depth     []DepthBand        `json:"depth"`
+	Execution *ExecutionEstimate `json:"execution,omitempty"`
+}
+
+// OrderBook is the result of get_order_book: the price levels of a book,
+// best first, with its spread when it has both bids and asks
+type OrderBook struct {
+	Pair string `json:"pair"`
+	*TopOfBook
+	*luno.GetOrderBookResponse
+}
+
+// NewAnalyzeOrderBookTool creates a new tool for analysing the order book
+func NewAnalyzeOrderBookTool() mcp.Tool {
+	return mcp.NewTool(
+		AnalyzeOrderBookToolID,
+		mcp.WithDescription("Analyse the order book for a trading pair: spread, mid price and cumulative depth within 1%, 2% and 5% of the mid price. "+
+			"Given a side and volume, also estimates the average fill price and slippage of a market order of that size. "+
+			"Only the top of the book is considered, so very large orders may show as not fully filled."),
+		mcp.WithString(
+			"pair",
+			mcp.Required(),
+			mcp.Description(ErrTradingPairDesc),
+		),
+		mcp.WithString(
+			"side",
+			mcp.Enum(orderTypeEnum...),
+			mcp.Description("Side of the order to estimate slippage for: BUY or SELL (BID and ASK are accepted as synonyms)"),
+		),
+		mcp.WithString(
+			"volume",
+			mcp.Description("Order volume in the base currency to estimate slippage for"),
+		),
+	)
+}
+
+// HandleAnalyzeOrderBook handles the analyze_order_book tool
+func HandleAnalyzeOrderBook(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		pair, err := request.RequireString("pair")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pair from request", err), nil
+		}
+		pair = market.NormalizePair(pair)
+
+		var (
+			side   OrderSide
+			volume decimal.Decimal
+		)
+		sideStr := request.GetString("side", "")
+		volumeStr := request.GetString("volume", "")
+		if (sideStr == "") != (volumeStr == "") {
+			return mcp.NewToolResultError("Both side and volume are needed to estimate slippage"), nil
+		}
+		if sideStr != "" {
+			if side, err = ParseOrderSide(sideStr); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if volume, err = parsePositiveDecimal("volume", volumeStr); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		book, err := cfg.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: pair})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting order book", err), nil
+		}
+
+		analysis, err := analyzeOrderBook(pair, book)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if side != "" {
+			estimate := estimateExecution(book, analysis.MidPrice, side, volume)
+			analysis.Execution = &estimate
+		}
+
+		resultJSON, err := json.MarshalIndent(analysis, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal order book analysis: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// topOfBook computes the spread and mid price of a book, or returns nil
+// when it has no bids or no asks
+func topOfBook(book *luno.GetOrderBookResponse) *TopOfBook {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil
+	}
+	return newTopOfBook(book.Bids[0].Price, book.Asks[0].Price)
+}
+
+// newTopOfBook computes the spread and mid price between a bid and an ask
+func newTopOfBook(bid, ask decimal.Decimal) *TopOfBook {
+	t := &TopOfBook{BestBid: bid, BestAsk: ask}
+	t.MidPrice = t.BestBid.Add(t.BestAsk).Div(decimal.NewFromInt64(2), priceScale)
+	t.Spread = t.BestAsk.Sub(t.BestBid)
+	t.SpreadPercent = percentOf(t.Spread, t.MidPrice)
+	return t
+}
+
+// analyzeOrderBook computes the spread, mid price and depth of a book
+func analyzeOrderBook(pair string, book *luno.GetOrderBookResponse) (*OrderBookAnalysis, error) {
+	top := topOfBook(book)
+	if top == nil {
+		return nil, fmt.Errorf("the %s order book has no bids or no asks, so there is no spread to analyse", pair)
+	}
+
+	a := &OrderBookAnalysis{Pair: pair, TopOfBook: *top}
+
+	for _, p := range depthBands {
+		band := DepthBand{
+			Percent:   p,
+			BidVolume: decimal.Zero(),
+			BidValue:  decimal.Zero(),
+			AskVolume: decimal.Zero(),
+			AskValue:  decimal.Zero(),
+		}
+		// Compare price*100 against mid*(100±p) to avoid rounding the bounds
+		bidBound := a.MidPrice.MulInt64(100 - p)
+		for _, e := range book.Bids {
+			if e.Price.MulInt64(100).Cmp(bidBound) < 0 {
+				break
+			}
+			band.BidVolume = band.BidVolume.Add(e.Volume)
+			band.BidValue = band.BidValue.Add(e.Volume.Mul(e.Price))
+		}
+		askBound := a.MidPrice.MulInt64(100 + p)
+		for _, e := range book.Asks {
+			if e.Price.MulInt64(100).Cmp(askBound) > 0 {
+				break
+			}
+			band.AskVolume = band.AskVolume.Add(e.Volume)
+			band.AskValue = band.AskValue.Add(e.Volume.Mul(e.Price))
+		}
+		a.Depth = append(a.Depth, band)
+	}
+	return a, nil
+}
+
+// estimateExecution walks the book to fill volume on the given side. Buys
+// take asks and sells take bids. Slippage is relative to mid and positive
+// when the average price is worse than mid.
+func estimateExecution(book *luno.GetOrderBookResponse, mid decimal.Decimal, side OrderSide, volume decimal.Decimal) ExecutionEstimate {
+	levels := book.Bids
+	if side == OrderSideBuy {
+		levels = book.Asks
+	}
+
+	e := ExecutionEstimate{
+		Side:            side,
+		Volume:          volume,
+		FilledVolume:    decimal.Zero(),
+		AveragePrice:    decimal.Zero(),
+		WorstPrice:      decimal.Zero(),
+		Counter:         decimal.Zero(),
+		SlippagePercent: decimal.Zero(),
+	}
+	for _, level := range levels {
+		remaining := volume.Sub(e.FilledVolume)
+		if remaining.Sign() <= 0 {
+			break
+		}
+		take := level.Volume
+		if take.Cmp(remaining) > 0 {
+			take = remaining
+		}
+		e.FilledVolume = e.FilledVolume.Add(take)
+		e.Counter = e.Counter.Add(take.Mul(level.Price))
+		e.WorstPrice = level.Price
+	}
+	e.FullyFilled = e.FilledVolume.Cmp(volume) >= 0
+
+	if e.FilledVolume.Sign() > 0 {
+		e.AveragePrice = e.Counter.Div(e.FilledVolume, priceScale)
+		slippage := e.AveragePrice.Sub(mid)
+		if side == OrderSideSell {
+			slippage = slippage.Neg()
+		}
+		e.SlippagePercent = percentOf(slippage, mid)
+	}
+	return e
+}
+
+// percentOf returns x as a percentage of base
+func percentOf(x, base decimal.Decimal) decimal.Decimal {
+	return x.MulInt64(100).Div(base, percentScale)
+}
+
+// parsePositiveDecimal parses a decimal argument that must be greater than zero
+func parsePositiveDecimal(name, s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid %s format: %w", name, err)
+	}
+	if d.Sign() <= 0 {
+		return decimal.Decimal{}, fmt.Errorf("%s must be greater than zero", name)
+	}
+	return d, nil
+}
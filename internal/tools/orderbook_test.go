@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrderBook(t *testing.T) *luno.GetOrderBookResponse {
+	entry := func(price, volume string) luno.OrderBookEntry {
+		return luno.OrderBookEntry{Price: NewFromString(t, price), Volume: NewFromString(t, volume)}
+	}
+	return &luno.GetOrderBookResponse{
+		Bids: []luno.OrderBookEntry{entry("99", "1"), entry("98.5", "2"), entry("97", "3"), entry("94", "5")},
+		Asks: []luno.OrderBookEntry{entry("101", "1"), entry("101.5", "2"), entry("103", "3"), entry("106", "5")},
+	}
+}
+
+// assertDecimal compares decimals by value, ignoring their scale
+func assertDecimal(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.Zero(t, NewFromString(t, expected).Cmp(actual), "expected %s, got %s", expected, actual)
+}
+
+func TestHandleGetOrderBookDepth(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		book          *luno.GetOrderBookResponse
+		expectedBids  int
+		expectedAsks  int
+		expectedMid   string
+		errorContains string
+	}{
+		{name: "whole book", params: map[string]any{"pair": "XBTZAR"}, book: testOrderBook(t), expectedBids: 4, expectedAsks: 4, expectedMid: "100"},
+		{name: "top two levels", params: map[string]any{"pair": "XBTZAR", "depth": float64(2)}, book: testOrderBook(t), expectedBids: 2, expectedAsks: 2, expectedMid: "100"},
+		{name: "depth beyond the book", params: map[string]any{"pair": "XBTZAR", "depth": float64(50)}, book: testOrderBook(t), expectedBids: 4, expectedAsks: 4, expectedMid: "100"},
+		{name: "one sided book has no spread", params: map[string]any{"pair": "XBTZAR", "depth": float64(1)},
+			book: &luno.GetOrderBookResponse{Bids: testOrderBook(t).Bids, Asks: []luno.OrderBookEntry{}}, expectedBids: 1},
+		{name: "negative depth", params: map[string]any{"pair": "XBTZAR", "depth": float64(-1)}, errorContains: "depth must be a positive"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			if tc.book != nil {
+				mockClient.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).Return(tc.book, nil)
+			}
+			result, err := HandleGetOrderBook(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+			assert.Equal(t, "XBTZAR", got["pair"])
+			assert.Len(t, got["bids"], tc.expectedBids)
+			assert.Len(t, got["asks"], tc.expectedAsks)
+			if tc.expectedMid == "" {
+				assert.NotContains(t, got, "mid_price")
+				return
+			}
+			// The spread is of the whole book, so it is the same at any depth
+			assertDecimal(t, tc.expectedMid, NewFromString(t, got["mid_price"].(string)))
+			assertDecimal(t, "2", NewFromString(t, got["spread"].(string)))
+			assertDecimal(t, "99", NewFromString(t, got["best_bid"].(string)))
+		})
+	}
+}
+
+func TestHandleAnalyzeOrderBook(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		book          *luno.GetOrderBookResponse
+		bookErr       error
+		expectedError bool
+		errorContains string
+		check         func(*testing.T, OrderBookAnalysis)
+	}{
+		{
+			name:   "spread and depth",
+			params: map[string]any{"pair": "XBTZAR"},
+			book:   testOrderBook(t),
+			check: func(t *testing.T, a OrderBookAnalysis) {
+				assertDecimal(t, "100", a.MidPrice)
+				assertDecimal(t, "2", a.Spread)
+				assertDecimal(t, "2", a.SpreadPercent)
+				require.Len(t, a.Depth, 3)
+
+				expected := []struct{ bidVolume, bidValue, askVolume, askValue string }{
+					{"1", "99", "1", "101"},
+					{"3", "296", "3", "304"},
+					{"6", "587", "6", "613"},
+				}
+				for i, e := range expected {
+					assertDecimal(t, e.bidVolume, a.Depth[i].BidVolume)
+					assertDecimal(t, e.bidValue, a.Depth[i].BidValue)
+					assertDecimal(t, e.askVolume, a.Depth[i].AskVolume)
+					assertDecimal(t, e.askValue, a.Depth[i].AskValue)
+				}
+				assert.Nil(t, a.Execution)
+			},
+		},
+		{
+			name:   "buy slippage",
+			params: map[string]any{"pair": "XBTZAR", "side": "buy", "volume": "2"},
+			book:   testOrderBook(t),
+			check: func(t *testing.T, a OrderBookAnalysis) {
+				require.NotNil(t, a.Execution)
+				assert.Equal(t, OrderSideBuy, a.Execution.Side)
+				assert.True(t, a.Execution.FullyFilled)
+				assertDecimal(t, "202.5", a.Execution.Counter)
+				assertDecimal(t, "101.25", a.Execution.AveragePrice)
+				assertDecimal(t, "101.5", a.Execution.WorstPrice)
+				assertDecimal(t, "1.25", a.Execution.SlippagePercent)
+			},
+		},
+		{
+			name:   "sell larger than the book",
+			params: map[string]any{"pair": "XBTZAR", "side": "ASK", "volume": "20"},
+			book:   testOrderBook(t),
+			check: func(t *testing.T, a OrderBookAnalysis) {
+				require.NotNil(t, a.Execution)
+				assert.Equal(t, OrderSideSell, a.Execution.Side)
+				assert.False(t, a.Execution.FullyFilled)
+				assertDecimal(t, "11", a.Execution.FilledVolume)
+				assertDecimal(t, "1057", a.Execution.Counter)
+				assertDecimal(t, "96.09090909", a.Execution.AveragePrice)
+				assertDecimal(t, "3.909", a.Execution.SlippagePercent)
+			},
+		},
+		{
+			name:          "side without volume",
+			params:        map[string]any{"pair": "XBTZAR", "side": "BUY"},
+			expectedError: true,
+			errorContains: "Both side and volume are needed",
+		},
+		{
+			name:          "invalid volume",
+			params:        map[string]any{"pair": "XBTZAR", "side": "BUY", "volume": "-1"},
+			expectedError: true,
+			errorContains: "volume must be greater than zero",
+		},
+		{
+			name:          "empty book",
+			params:        map[string]any{"pair": "XBTZAR"},
+			book:          &luno.GetOrderBookResponse{Bids: testOrderBook(t).Bids},
+			expectedError: true,
+			errorContains: "has no bids or no asks",
+		},
+		{
+			name:          "API error",
+			params:        map[string]any{"pair": "XBTZAR"},
+			bookErr:       errors.New(apiErrorStr),
+			expectedError: true,
+			errorContains: "getting order book",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			if tc.book != nil || tc.bookErr != nil {
+				mockClient.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).Return(tc.book, tc.bookErr)
+			}
+
+			result, err := HandleAnalyzeOrderBook(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+
+			if tc.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+
+			require.False(t, result.IsError, text)
+			var got OrderBookAnalysis
+			require.NoError(t, json.Unmarshal([]byte(text), &got))
+			tc.check(t, got)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Arguments that control how a limit order executes
+const (
+	postOnlyParam      = "post_only"
+	timeInForceParam   = "time_in_force"
+	stopPriceParam     = "stop_price"
+	stopDirectionParam = "stop_direction"
+)
+
+// orderOptions are the optional execution settings of a limit order
+type orderOptions struct {
+	PostOnly      bool               `json:"post_only,omitempty"`
+	TimeInForce   luno.TimeInForce   `json:"time_in_force,omitempty"`
+	StopPrice     *decimal.Decimal   `json:"stop_price,omitempty"`
+	StopDirection luno.StopDirection `json:"stop_direction,omitempty"`
+	// stopCurrency is the currency the stop price was given in, if any
+	stopCurrency string
+}
+
+// withOrderOptions adds the execution settings to an order tool
+func withOrderOptions() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		for _, opt := range []mcp.ToolOption{
+			withExecutionOptions(),
+			mcp.WithString(
+				stopPriceParam,
+				mcp.Description("Trigger price that makes this a stop-limit order: the order is only placed once a trade crosses it. "+amountDesc),
+			),
+			mcp.WithString(
+				stopDirectionParam,
+				mcp.Description("Which side of stop_price triggers the order. RELATIVE_LAST_TRADE (default) works it out from the last trade price."),
+				mcp.Enum(string(luno.StopDirectionAbove), string(luno.StopDirectionBelow), string(luno.StopDirectionRelative_last_trade)),
+			),
+		} {
+			opt(t)
+		}
+	}
+}
+
+// withExecutionOptions adds post_only and time_in_force, the settings that
+// apply to every limit order, to an order tool
+func withExecutionOptions() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		for _, opt := range []mcp.ToolOption{
+			mcp.WithBoolean(
+				postOnlyParam,
+				mcp.Description("Cancel the order instead of trading if it would trade immediately, so it only ever pays maker fees"),
+			),
+			mcp.WithString(
+				timeInForceParam,
+				mcp.Description("GTC keeps the order open until filled or cancelled (default), IOC cancels whatever can't fill immediately, "+
+					"FOK cancels the order unless it fills completely and immediately. IOC and FOK can't be post-only."),
+				mcp.Enum(string(luno.TimeInForceGtc), string(luno.TimeInForceIoc), string(luno.TimeInForceFok)),
+			),
+		} {
+			opt(t)
+		}
+	}
+}
+
+// parseOrderOptions reads the execution settings of an order. The stop
+// price is checked against the market by check.
+func parseOrderOptions(request mcp.CallToolRequest) (orderOptions, error) {
+	opts, err := parseExecutionOptions(request)
+	if err != nil {
+		return orderOptions{}, err
+	}
+
+	direction := strings.ToUpper(strings.TrimSpace(request.GetString(stopDirectionParam, "")))
+	stopStr := strings.TrimSpace(request.GetString(stopPriceParam, ""))
+	if stopStr == "" {
+		if direction != "" {
+			return orderOptions{}, fmt.Errorf("%s needs a %s", stopDirectionParam, stopPriceParam)
+		}
+		return opts, nil
+	}
+	stop, currency, err := parseAmount(stopStr)
+	if err != nil {
+		return orderOptions{}, fmt.Errorf("invalid %s format: %w", stopPriceParam, err)
+	}
+	opts.StopPrice, opts.stopCurrency = &stop, currency
+	switch luno.StopDirection(direction) {
+	case "":
+		opts.StopDirection = luno.StopDirectionRelative_last_trade
+	case luno.StopDirectionAbove, luno.StopDirectionBelow, luno.StopDirectionRelative_last_trade:
+		opts.StopDirection = luno.StopDirection(direction)
+	default:
+		return orderOptions{}, fmt.Errorf("%s must be ABOVE, BELOW or RELATIVE_LAST_TRADE, got %q", stopDirectionParam, direction)
+	}
+	return opts, nil
+}
+
+// parseExecutionOptions reads post_only and time_in_force
+func parseExecutionOptions(request mcp.CallToolRequest) (orderOptions, error) {
+	opts := orderOptions{PostOnly: request.GetBool(postOnlyParam, false)}
+
+	if tif := strings.ToUpper(strings.TrimSpace(request.GetString(timeInForceParam, ""))); tif != "" {
+		switch luno.TimeInForce(tif) {
+		case luno.TimeInForceGtc, luno.TimeInForceIoc, luno.TimeInForceFok:
+			opts.TimeInForce = luno.TimeInForce(tif)
+		default:
+			return orderOptions{}, fmt.Errorf("%s must be GTC, IOC or FOK, got %q", timeInForceParam, tif)
+		}
+	}
+	if opts.PostOnly && (opts.TimeInForce == luno.TimeInForceIoc || opts.TimeInForce == luno.TimeInForceFok) {
+		return orderOptions{}, fmt.Errorf("a post-only order can't be %s, as it would never trade", opts.TimeInForce)
+	}
+	return opts, nil
+}
+
+// check validates the stop price against the market's counter currency,
+// price precision and limits
+func (o orderOptions) check(market *luno.MarketInfo) error {
+	if o.StopPrice == nil {
+		return nil
+	}
+	if err := checkAmountCurrency(stopPriceParam, o.stopCurrency, market.CounterCurrency, market.MarketId); err != nil {
+		return err
+	}
+	return checkDecimal(stopPriceParam, market.MarketId, *o.StopPrice, int(market.PriceScale), market.MinPrice, market.MaxPrice)
+}
+
+// apply sets the options on an order request
+func (o orderOptions) apply(req *luno.PostLimitOrderRequest) {
+	req.PostOnly = o.PostOnly
+	req.TimeInForce = o.TimeInForce
+	if o.StopPrice != nil {
+		req.StopPrice = *o.StopPrice
+		req.StopDirection = o.StopDirection
+	}
+}
+
+// describe adds the options that are set to an order preview and the
+// details its confirmation is bound to
+func (o orderOptions) describe(preview map[string]string, details []string) []string {
+	if o.PostOnly {
+		preview[postOnlyParam] = "true"
+		details = append(details, postOnlyParam)
+	}
+	if o.TimeInForce != "" {
+		preview[timeInForceParam] = string(o.TimeInForce)
+		details = append(details, string(o.TimeInForce))
+	}
+	if o.StopPrice != nil {
+		preview[stopPriceParam] = o.StopPrice.String()
+		preview[stopDirectionParam] = string(o.StopDirection)
+		details = append(details, o.StopPrice.String(), string(o.StopDirection))
+	}
+	return details
+}
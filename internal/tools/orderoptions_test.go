@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrderOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		expected orderOptions
+		// stopPrice is the expected stop price, compared separately
+		stopPrice string
+		errMsg    string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:     "post only",
+			params:   map[string]any{"post_only": true, "time_in_force": "gtc"},
+			expected: orderOptions{PostOnly: true, TimeInForce: luno.TimeInForceGtc},
+		},
+		{
+			name:      "stop price defaults to the last trade",
+			params:    map[string]any{"stop_price": "900000"},
+			expected:  orderOptions{StopDirection: luno.StopDirectionRelative_last_trade},
+			stopPrice: "900000",
+		},
+		{
+			name:      "stop direction",
+			params:    map[string]any{"stop_price": "R900k", "stop_direction": "below"},
+			expected:  orderOptions{StopDirection: luno.StopDirectionBelow, stopCurrency: "ZAR"},
+			stopPrice: "900000",
+		},
+		{
+			name:   "post only fill or kill",
+			params: map[string]any{"post_only": true, "time_in_force": "FOK"},
+			errMsg: "a post-only order can't be FOK",
+		},
+		{
+			name:   "unknown time in force",
+			params: map[string]any{"time_in_force": "DAY"},
+			errMsg: `time_in_force must be GTC, IOC or FOK, got "DAY"`,
+		},
+		{
+			name:   "stop direction without a stop price",
+			params: map[string]any{"stop_direction": "ABOVE"},
+			errMsg: "stop_direction needs a stop_price",
+		},
+		{
+			name:   "unknown stop direction",
+			params: map[string]any{"stop_price": "900000", "stop_direction": "UP"},
+			errMsg: "stop_direction must be ABOVE, BELOW or RELATIVE_LAST_TRADE",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseOrderOptions(createMockRequest(tc.params))
+			if tc.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			if tc.stopPrice != "" {
+				require.NotNil(t, opts.StopPrice)
+				assertDecimal(t, tc.stopPrice, *opts.StopPrice)
+				opts.StopPrice = nil
+			}
+			assert.Equal(t, tc.expected, opts)
+		})
+	}
+}
+
+func TestHandleCreateOrderOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		check         func(*testing.T, *luno.PostLimitOrderRequest)
+		errorContains string
+	}{
+		{
+			name:   "post only",
+			params: map[string]any{"post_only": true},
+			check: func(t *testing.T, req *luno.PostLimitOrderRequest) {
+				assert.True(t, req.PostOnly)
+				assert.Empty(t, req.StopDirection)
+			},
+		},
+		{
+			name:   "stop limit",
+			params: map[string]any{"stop_price": "950000", "stop_direction": "ABOVE", "time_in_force": "IOC"},
+			check: func(t *testing.T, req *luno.PostLimitOrderRequest) {
+				assert.False(t, req.PostOnly)
+				assert.Equal(t, luno.TimeInForceIoc, req.TimeInForce)
+				assertDecimal(t, "950000", req.StopPrice)
+				assert.Equal(t, luno.StopDirectionAbove, req.StopDirection)
+			},
+		},
+		{
+			name:          "stop price too precise",
+			params:        map[string]any{"stop_price": "950000.5"},
+			errorContains: "stop_price 950000.5 has more than 0 decimal places",
+		},
+		{
+			name:          "stop price in the wrong currency",
+			params:        map[string]any{"stop_price": "€950000"},
+			errorContains: "stop_price is given in EUR but must be in ZAR for XBTZAR",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			if tc.errorContains == "" {
+				expectOrderBalance(mockClient)
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR", Status: "ACTIVE"}, nil)
+				mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+				mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).RunAndReturn(
+					func(_ context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+						tc.check(t, req)
+						return &luno.PostLimitOrderResponse{OrderId: "BX1"}, nil
+					})
+			}
+
+			args := map[string]any{"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "1000000"}
+			for k, v := range tc.params {
+				args[k] = v
+			}
+			result, err := HandleCreateOrder(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(args))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError, text)
+		})
+	}
+}
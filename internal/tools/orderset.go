@@ -0,0 +1,447 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PlaceOrderSetToolID is the ID of the multi-order placement tool
+const PlaceOrderSetToolID = "place_order_set"
+
+// maxOrderSetSize is the largest number of orders accepted in one set
+const maxOrderSetSize = 10
+
+// maxOrderSetIDLength leaves room in a set's client order ID for the index
+// suffix of each order's ID
+const maxOrderSetIDLength = 255 - len("-9")
+
+// rollbackTimeout bounds the cancellations of a rollback, which carry on
+// after the call's own context is cancelled
+const rollbackTimeout = 10 * time.Second
+
+// rollbackParam turns off cancelling placed orders when a later order fails
+const rollbackParam = "rollback"
+
+// Order outcomes reported by place_order_set
+const (
+	OrderOutcomePlaced         = "placed"
+	OrderOutcomeInvalid        = "invalid"
+	OrderOutcomeFailed         = "failed"
+	OrderOutcomeNotPlaced      = "not_placed"
+	OrderOutcomeRolledBack     = "rolled_back"
+	OrderOutcomeRollbackFailed = "rollback_failed"
+)
+
+// OrderSetItem is a single limit order in a set
+type OrderSetItem struct {
+	Pair   string `json:"pair"`
+	Type   string `json:"type"`
+	Volume string `json:"volume"`
+	Price  string `json:"price"`
+}
+
+// OrderSetOutcome reports what happened to one order in a set
+type OrderSetOutcome struct {
+	Index   int       `json:"index"`
+	Pair    string    `json:"pair"`
+	Side    OrderSide `json:"side,omitempty"`
+	Volume  string    `json:"volume"`
+	Price   string    `json:"price"`
+	Outcome string    `json:"outcome"`
+	OrderID string    `json:"order_id,omitempty"`
+	// ClientOrderID is the set's client order ID followed by - and the index
+	ClientOrderID string `json:"client_order_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// preparedOrder is an order that passed validation
+type preparedOrder struct {
+	side OrderSide
+	req  *luno.PostLimitOrderRequest
+}
+
+// NewPlaceOrderSetTool creates a new tool for placing a set of limit orders together
+func NewPlaceOrderSetTool() mcp.Tool {
+	return mcp.NewTool(
+		PlaceOrderSetToolID,
+		mcp.WithDescription(fmt.Sprintf("Place up to %d limit orders as a set, e.g. a ladder of entries. "+
+			"All orders are validated first and nothing is placed if any is invalid. "+
+			"The available balances must cover all the orders together. "+
+			"If placing an order fails, orders already placed are cancelled unless rollback is false. Returns the outcome of every order.",
+			maxOrderSetSize)),
+		mcp.WithArray(
+			"orders",
+			mcp.Required(),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxOrderSetSize),
+			mcp.Description("Limit orders to place, in order"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"pair":   map[string]any{"type": "string", "description": ErrTradingPairDesc},
+					"type":   map[string]any{"type": "string", "enum": orderTypeEnum, "description": "Order type: BUY or SELL (BID and ASK are accepted as synonyms)"},
+					"volume": map[string]any{"type": "string", "description": "Order volume. " + amountDesc},
+					"price":  map[string]any{"type": "string", "description": "Limit price. " + amountDesc},
+				},
+				"required": []string{"pair", "type", "volume", "price"},
+			}),
+		),
+		mcp.WithBoolean(
+			rollbackParam,
+			mcp.Description("Cancel the orders already placed if placing a later order fails (default true)"),
+		),
+		mcp.WithString(
+			clientOrderIDParam,
+			mcp.Description(fmt.Sprintf("Your own unique ID for the set, up to %d letters, digits or _ ; , . - characters. "+
+				"Each order is sent with this ID followed by - and its index, e.g. ladder-0. "+
+				"A set with an ID used in the last few minutes is rejected, so reuse the ID when retrying after a timeout. "+
+				"One is generated when omitted.", maxOrderSetIDLength)),
+		),
+		withConfirmToken(),
+		withDryRun(),
+	)
+}
+
+// HandlePlaceOrderSet handles the place_order_set tool
+func HandlePlaceOrderSet(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		items, err := parseOrderSet(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting orders from request", err), nil
+		}
+
+		setID, givenID, err := clientOrderID(request)
+		if err == nil && len(setID) > maxOrderSetIDLength {
+			err = fmt.Errorf("%s of a set must be at most %d characters", clientOrderIDParam, maxOrderSetIDLength)
+		}
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid client order ID", err), nil
+		}
+
+		markets, err := ListMarkets(ctx, cfg)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("validating orders", err), nil
+		}
+
+		outcomes, prepared := prepareOrderSet(items, markets)
+		if prepared == nil {
+			return orderSetResult("No orders were placed because some orders are invalid", outcomes, true)
+		}
+
+		allowed := true
+		for i := range outcomes {
+			if err := checkPairAllowed(cfg, outcomes[i].Pair); err != nil {
+				outcomes[i].Outcome = OrderOutcomeInvalid
+				outcomes[i].Error = err.Error()
+				allowed = false
+			}
+		}
+		if !allowed {
+			return orderSetResult("No orders were placed because some pairs are not allowed", outcomes, true)
+		}
+
+		// Hold each order's value against the risk limits, releasing any that
+		// aren't placed or are cancelled again by the rollback
+		releases := make([]func(), 0, len(prepared))
+		defer func() {
+			for i, release := range releases {
+				if outcomes[i].OrderID == "" || outcomes[i].Outcome == OrderOutcomeRolledBack {
+					release()
+				}
+			}
+		}()
+		for i, order := range prepared {
+			info, err := market.ValidatePair(markets, order.req.Pair)
+			if err == nil {
+				var release func()
+				release, err = reserveOrderValue(ctx, cfg, info, order.req.Volume, order.req.Price)
+				if err == nil {
+					releases = append(releases, release)
+					continue
+				}
+			}
+			outcomes[i].Outcome = OrderOutcomeInvalid
+			outcomes[i].Error = err.Error()
+			return orderSetResult("No orders were placed because some orders exceed risk limits", outcomes, true)
+		}
+
+		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("checking balances", err), nil
+		}
+		rates := takerFeeRates(ctx, cfg, prepared)
+		if err := checkOrderSetBalances(balances.Balance, prepared, markets, rates); err != nil {
+			return orderSetResult(fmt.Sprintf("No orders were placed because %v", err), outcomes, true)
+		}
+
+		// A generated ID changes on every call, so only a given one is part of
+		// the preview and the confirmation binding
+		for i := range outcomes {
+			prepared[i].req.ClientOrderId = setID + "-" + strconv.Itoa(i)
+			if givenID {
+				outcomes[i].ClientOrderID = prepared[i].req.ClientOrderId
+			}
+		}
+		if isDryRun(cfg, request) {
+			return dryRunResult(PlaceOrderSetToolID, outcomes), nil
+		}
+		binding, err := json.Marshal(outcomes)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal orders: %v", err)), nil
+		}
+		if res := requireConfirmation(cfg, request, PlaceOrderSetToolID, string(binding), outcomes); res != nil {
+			return res, nil
+		}
+		for i := range outcomes {
+			outcomes[i].ClientOrderID = prepared[i].req.ClientOrderId
+		}
+
+		// Reject a retry of a set that was already submitted before placing
+		// any of its orders
+		finishers, err := beginOrderSetSubmissions(ctx, cfg, prepared, givenID, string(binding))
+		if err != nil {
+			return orderSetResult(fmt.Sprintf("No orders were placed because %v", err), outcomes, true)
+		}
+
+		for i, order := range prepared {
+			res, err := cfg.LunoClient.PostLimitOrder(ctx, order.req)
+			finishers[i](orderID(res), err)
+			if err != nil {
+				outcomes[i].Outcome = OrderOutcomeFailed
+				outcomes[i].Error = err.Error()
+				for j := i + 1; j < len(outcomes); j++ {
+					finishers[j]("", errOrderNotPlaced)
+					outcomes[j].Outcome = OrderOutcomeNotPlaced
+				}
+				if !request.GetBool(rollbackParam, true) {
+					return orderSetResult(fmt.Sprintf("Order %d failed, orders placed before it were kept", i), outcomes, true)
+				}
+				rollbackOrderSet(ctx, cfg, outcomes[:i])
+				return orderSetResult(fmt.Sprintf("Order %d failed, orders placed before it were cancelled", i), outcomes, true)
+			}
+			outcomes[i].Outcome = OrderOutcomePlaced
+			outcomes[i].OrderID = res.OrderId
+		}
+
+		return orderSetResult(fmt.Sprintf("All %d orders placed", len(outcomes)), outcomes, false)
+	}
+}
+
+// errOrderNotPlaced finishes the submission of an order in a set that was
+// never sent, so that it can be retried
+var errOrderNotPlaced = errors.New("order was not placed")
+
+// beginOrderSetSubmissions begins the submission of every order in a set,
+// keyed by each order's client order ID or, for a generated ID, by the set
+// and the order's index. If any order is a duplicate the submissions already
+// begun are forgotten again and the error is returned.
+func beginOrderSetSubmissions(ctx context.Context, cfg *config.Config, prepared []preparedOrder, givenID bool, binding string) ([]func(string, error), error) {
+	finishers := make([]func(string, error), 0, len(prepared))
+	for i, order := range prepared {
+		finish, err := beginSubmission(ctx, cfg, orderSubmission, order.req.ClientOrderId, givenID, binding+"\n"+strconv.Itoa(i))
+		if err != nil {
+			for _, finish := range finishers {
+				finish("", errOrderNotPlaced)
+			}
+			return nil, err
+		}
+		finishers = append(finishers, finish)
+	}
+	return finishers, nil
+}
+
+// orderID returns the ID of a placed order, or "" if it wasn't placed
+func orderID(res *luno.PostLimitOrderResponse) string {
+	if res == nil {
+		return ""
+	}
+	return res.OrderId
+}
+
+// parseOrderSet reads the orders argument
+func parseOrderSet(request mcp.CallToolRequest) ([]OrderSetItem, error) {
+	raw, ok := request.GetArguments()["orders"]
+	if !ok {
+		return nil, errors.New("required argument \"orders\" not found")
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var items []OrderSetItem
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, fmt.Errorf("orders must be a list of objects with pair, type, volume and price: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("at least one order is required")
+	}
+	if len(items) > maxOrderSetSize {
+		return nil, fmt.Errorf("at most %d orders can be placed in one set, got %d", maxOrderSetSize, len(items))
+	}
+	return items, nil
+}
+
+// prepareOrderSet validates every order. If any is invalid the returned
+// prepared orders are nil and the outcomes explain which orders were rejected.
+func prepareOrderSet(items []OrderSetItem, markets []luno.MarketInfo) ([]OrderSetOutcome, []preparedOrder) {
+	outcomes := make([]OrderSetOutcome, len(items))
+	prepared := make([]preparedOrder, len(items))
+	valid := true
+	for i, item := range items {
+		pair := market.NormalizePair(item.Pair)
+		outcomes[i] = OrderSetOutcome{Index: i, Pair: pair, Volume: item.Volume, Price: item.Price, Outcome: OrderOutcomeNotPlaced}
+
+		order, err := prepareOrder(pair, item, markets)
+		if err != nil {
+			outcomes[i].Outcome = OrderOutcomeInvalid
+			outcomes[i].Error = err.Error()
+			valid = false
+			continue
+		}
+		outcomes[i].Side = order.side
+		prepared[i] = order
+	}
+	if !valid {
+		return outcomes, nil
+	}
+	return outcomes, prepared
+}
+
+func prepareOrder(pair string, item OrderSetItem, markets []luno.MarketInfo) (preparedOrder, error) {
+	side, err := ParseOrderSide(item.Type)
+	if err != nil {
+		return preparedOrder{}, err
+	}
+	volume, volumeCurrency, err := parseAmount(item.Volume)
+	if err != nil {
+		return preparedOrder{}, fmt.Errorf("invalid volume format: %w", err)
+	}
+	price, priceCurrency, err := parseAmount(item.Price)
+	if err != nil {
+		return preparedOrder{}, fmt.Errorf("invalid price format: %w", err)
+	}
+	info, err := market.ValidatePair(markets, pair)
+	if err != nil {
+		return preparedOrder{}, err
+	}
+	if err := checkAmountCurrency("volume", volumeCurrency, info.BaseCurrency, pair); err != nil {
+		return preparedOrder{}, err
+	}
+	if err := checkAmountCurrency("price", priceCurrency, info.CounterCurrency, pair); err != nil {
+		return preparedOrder{}, err
+	}
+	if err := ValidateOrderSize(info, volume, price); err != nil {
+		return preparedOrder{}, err
+	}
+	return preparedOrder{
+		side: side,
+		req: &luno.PostLimitOrderRequest{
+			Pair:   pair,
+			Type:   side.LunoOrderType(),
+			Volume: volume,
+			Price:  price,
+		},
+	}, nil
+}
+
+// takerFeeRates gets the taker fee rate of each pair the set buys on. Pairs
+// whose fee info can't be read are left out and checked without fees.
+func takerFeeRates(ctx context.Context, cfg *config.Config, prepared []preparedOrder) map[string]decimal.Decimal {
+	rates := make(map[string]decimal.Decimal)
+	for _, order := range prepared {
+		if order.side != OrderSideBuy {
+			continue
+		}
+		if _, ok := rates[order.req.Pair]; ok {
+			continue
+		}
+		fees, err := cfg.LunoClient.GetFeeInfo(ctx, &luno.GetFeeInfoRequest{Pair: order.req.Pair})
+		if rate, ok := takerFeeRate(order.req.Pair, fees, err); ok {
+			rates[order.req.Pair] = rate
+		}
+	}
+	return rates
+}
+
+// checkOrderSetBalances checks that the available balances cover every order
+// in the set together, as checkOrderBalance does for a single order: the
+// counter currency spent by buys plus their estimated taker fees, and the
+// base currency sold by sells
+func checkOrderSetBalances(balances []luno.AccountBalance, prepared []preparedOrder, markets []luno.MarketInfo, rates map[string]decimal.Decimal) error {
+	values := make(map[string]decimal.Decimal)
+	fees := make(map[string]decimal.Decimal)
+	for _, order := range prepared {
+		info, err := market.ValidatePair(markets, order.req.Pair)
+		if err != nil {
+			return err
+		}
+		if order.side == OrderSideBuy {
+			value := order.req.Volume.Mul(order.req.Price)
+			values[info.CounterCurrency] = values[info.CounterCurrency].Add(value)
+			if rate, ok := rates[order.req.Pair]; ok {
+				fees[info.CounterCurrency] = fees[info.CounterCurrency].Add(value.Mul(rate))
+			}
+		} else {
+			values[info.BaseCurrency] = values[info.BaseCurrency].Add(order.req.Volume)
+		}
+	}
+
+	available := availableBalances(balances)
+	for _, currency := range slices.Sorted(maps.Keys(values)) {
+		fee, hasFee := fees[currency]
+		needed := values[currency].Add(fee)
+		if available[currency].Cmp(needed) >= 0 {
+			continue
+		}
+		if hasFee {
+			return fmt.Errorf("the orders need %s %s (%s plus an estimated %s fee) but only %s %s is available",
+				needed.String(), currency, values[currency].String(), fee.String(), available[currency].String(), currency)
+		}
+		return fmt.Errorf("the orders need %s %s but only %s %s is available",
+			needed.String(), currency, available[currency].String(), currency)
+	}
+	return nil
+}
+
+// rollbackOrderSet cancels orders that were already placed. It runs even if
+// the call was cancelled or timed out, since that is often why an order failed.
+func rollbackOrderSet(ctx context.Context, cfg *config.Config, placed []OrderSetOutcome) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+	for i := range placed {
+		_, err := cfg.LunoClient.StopOrder(ctx, &luno.StopOrderRequest{OrderId: placed[i].OrderID})
+		if err != nil {
+			slog.Error("Failed to cancel order during order set rollback", "order_id", placed[i].OrderID, "error", err)
+			placed[i].Outcome = OrderOutcomeRollbackFailed
+			placed[i].Error = fmt.Sprintf("order was placed but could not be cancelled: %v", err)
+			continue
+		}
+		placed[i].Outcome = OrderOutcomeRolledBack
+	}
+}
+
+func orderSetResult(summary string, outcomes []OrderSetOutcome, failed bool) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(map[string]any{"orders": outcomes}, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal order set result: %v", err)), nil
+	}
+	text := fmt.Sprintf("%s\n\n%s", summary, resultJSON)
+	if failed {
+		return mcp.NewToolResultError(text), nil
+	}
+	return mcp.NewToolResultText(text), nil
+}
@@ -0,0 +1,418 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/idempotency"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrderSet(n int) []any {
+	orders := make([]any, 0, n)
+	prices := []string{"900000", "890000", "880000"}
+	for i := 0; i < n; i++ {
+		orders = append(orders, map[string]any{
+			"pair":   "XBTZAR",
+			"type":   "BUY",
+			"volume": "0.01",
+			"price":  prices[i%len(prices)],
+		})
+	}
+	return orders
+}
+
+// testOrderSetBalances has enough ZAR for testOrderSet(3) and 0.1 XBT to sell
+func testOrderSetBalances() *luno.GetBalancesResponse {
+	return &luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{Asset: "ZAR", Balance: decimal.NewFromInt64(30000), Reserved: decimal.NewFromInt64(1000)},
+		{Asset: "XBT", Balance: decimal.NewFromFloat64(0.1, 8)},
+	}}
+}
+
+// testOrderSetFees has a taker fee of 0.1%
+func testOrderSetFees() *luno.GetFeeInfoResponse {
+	return &luno.GetFeeInfoResponse{MakerFee: "0", TakerFee: "0.001"}
+}
+
+func TestHandlePlaceOrderSet(t *testing.T) {
+	tests := []struct {
+		name             string
+		orders           any
+		mockSetup        func(*sdk.MockLunoClient)
+		dailyLimit       string
+		allowedPairs     []string
+		noRollback       bool
+		expectedError    bool
+		errorContains    string
+		expectedOutcomes []string
+	}{
+		{
+			name:   "all orders placed",
+			orders: testOrderSet(2),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "B"}, nil).Once()
+			},
+			expectedOutcomes: []string{OrderOutcomePlaced, OrderOutcomePlaced},
+		},
+		{
+			name: "invalid order places nothing",
+			orders: append(testOrderSet(1), map[string]any{
+				"pair": "XBTZAR", "type": "BUY", "volume": "0.0000001", "price": "900000",
+			}),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError:    true,
+			errorContains:    "No orders were placed",
+			expectedOutcomes: []string{OrderOutcomeNotPlaced, OrderOutcomeInvalid},
+		},
+		{
+			name:   "set over the daily limit places nothing",
+			orders: testOrderSet(3),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			dailyLimit:       "ZAR:20000",
+			expectedError:    true,
+			errorContains:    "exceed risk limits",
+			expectedOutcomes: []string{OrderOutcomeNotPlaced, OrderOutcomeNotPlaced, OrderOutcomeInvalid},
+		},
+		{
+			name: "pair outside the allow-list places nothing",
+			orders: append(testOrderSet(1), map[string]any{
+				"pair": "XBTEUR", "type": "SELL", "volume": "0.01", "price": "50000",
+			}),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			allowedPairs:     []string{"XBTZAR"},
+			expectedError:    true,
+			errorContains:    "this server only trades XBTZAR",
+			expectedOutcomes: []string{OrderOutcomeNotPlaced, OrderOutcomeInvalid},
+		},
+		{
+			name:   "failure rolls back placed orders",
+			orders: testOrderSet(3),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New("insufficient balance")).Once()
+				m.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "A"}).Return(&luno.StopOrderResponse{Success: true}, nil)
+			},
+			expectedError:    true,
+			errorContains:    "Order 1 failed",
+			expectedOutcomes: []string{OrderOutcomeRolledBack, OrderOutcomeFailed, OrderOutcomeNotPlaced},
+		},
+		{
+			name:   "rolled back orders don't count towards the daily limit",
+			orders: testOrderSet(2),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New("insufficient balance")).Once()
+				m.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "A"}).Return(&luno.StopOrderResponse{Success: true}, nil)
+			},
+			dailyLimit:       "ZAR:100000",
+			expectedError:    true,
+			errorContains:    "orders placed before it were cancelled",
+			expectedOutcomes: []string{OrderOutcomeRolledBack, OrderOutcomeFailed},
+		},
+		{
+			name:   "rollback failure is reported",
+			orders: testOrderSet(2),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr)).Once()
+				m.EXPECT().StopOrder(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError:    true,
+			errorContains:    "could not be cancelled",
+			expectedOutcomes: []string{OrderOutcomeRollbackFailed, OrderOutcomeFailed},
+		},
+		{
+			name:   "failure keeps placed orders without rollback",
+			orders: testOrderSet(3),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New("insufficient balance")).Once()
+			},
+			noRollback:       true,
+			expectedError:    true,
+			errorContains:    "orders placed before it were kept",
+			expectedOutcomes: []string{OrderOutcomePlaced, OrderOutcomeFailed, OrderOutcomeNotPlaced},
+		},
+		{
+			name: "set over the available balance places nothing",
+			orders: append(testOrderSet(3), map[string]any{
+				"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "900000",
+			}),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+			},
+			expectedError:    true,
+			errorContains:    "the orders need 35735.70000 ZAR (35700.00 plus an estimated 35.70000 fee) but only 29000 ZAR is available",
+			expectedOutcomes: []string{OrderOutcomeNotPlaced, OrderOutcomeNotPlaced, OrderOutcomeNotPlaced, OrderOutcomeNotPlaced},
+		},
+		{
+			name: "taker fee takes the set over the available balance",
+			orders: []any{map[string]any{
+				"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "2900000",
+			}},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+			},
+			expectedError:    true,
+			errorContains:    "the orders need 29029.00000 ZAR (29000.00 plus an estimated 29.00000 fee)",
+			expectedOutcomes: []string{OrderOutcomeNotPlaced},
+		},
+		{
+			name: "balances are checked without fees when fee info fails",
+			orders: []any{map[string]any{
+				"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "2900000",
+			}},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+				m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil).Once()
+			},
+			expectedOutcomes: []string{OrderOutcomePlaced},
+		},
+		{
+			name: "sell over the available balance places nothing",
+			orders: []any{map[string]any{
+				"pair": "XBTZAR", "type": "SELL", "volume": "0.2", "price": "900000",
+			}},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+			},
+			expectedError: true,
+			errorContains: "the orders need 0.2 XBT but only 0.10000000 XBT is available",
+		},
+		{
+			name:   "balances API error",
+			orders: testOrderSet(1),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "checking balances",
+		},
+		{
+			name:   "markets API error",
+			orders: testOrderSet(1),
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "could not list markets",
+		},
+		{
+			name:          "missing orders",
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "required argument \"orders\" not found",
+		},
+		{
+			name:          "too many orders",
+			orders:        testOrderSet(maxOrderSetSize + 1),
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "at most 10 orders",
+		},
+		{
+			name:          "orders is not a list",
+			orders:        "XBTZAR",
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "orders must be a list",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			params := map[string]any{}
+			if tc.orders != nil {
+				params["orders"] = tc.orders
+			}
+			if tc.noRollback {
+				params[rollbackParam] = false
+			}
+			settings := &config.Settings{AllowedPairs: tc.allowedPairs}
+			if tc.dailyLimit != "" {
+				limits, err := config.LoadRiskLimits(func(k string) string {
+					if k == config.EnvLunoMaxDailyTradeValue {
+						return tc.dailyLimit
+					}
+					return ""
+				})
+				require.NoError(t, err)
+				settings.Limits = limits
+				// Nothing was placed, so nothing should stay reserved
+				defer func() { assert.Zero(t, limits.PlacedToday("")["ZAR"].Sign()) }()
+			}
+			cfg := &config.Config{LunoClient: mockClient}
+			cfg.SetSettings(settings)
+			result, err := HandlePlaceOrderSet(cfg)(context.Background(), createMockRequest(params))
+			require.NoError(t, err)
+
+			text := getTextContentFromResult(t, result)
+			assert.Equal(t, tc.expectedError, result.IsError, text)
+			if tc.errorContains != "" {
+				assert.Contains(t, text, tc.errorContains)
+			}
+			if tc.expectedOutcomes == nil {
+				return
+			}
+
+			var report struct {
+				Orders []OrderSetOutcome `json:"orders"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(text[strings.Index(text, "{"):]), &report))
+			outcomes := make([]string, 0, len(report.Orders))
+			for _, o := range report.Orders {
+				outcomes = append(outcomes, o.Outcome)
+			}
+			assert.Equal(t, tc.expectedOutcomes, outcomes)
+		})
+	}
+}
+
+func TestHandlePlaceOrderSetDuplicates(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+	mockClient.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+	cfg := &config.Config{LunoClient: mockClient, Submissions: idempotency.NewStore(time.Minute)}
+
+	var sentIDs []string
+	expectPost := func(orderID string, err error) {
+		mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).RunAndReturn(
+			func(_ context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+				sentIDs = append(sentIDs, req.ClientOrderId)
+				if err != nil {
+					return nil, err
+				}
+				return &luno.PostLimitOrderResponse{OrderId: orderID}, nil
+			}).Once()
+	}
+	placeSet := func(params map[string]any) (string, bool) {
+		args := map[string]any{"orders": testOrderSet(2)}
+		for k, v := range params {
+			args[k] = v
+		}
+		result, err := HandlePlaceOrderSet(cfg)(context.Background(), createMockRequest(args))
+		require.NoError(t, err)
+		return getTextContentFromResult(t, result), result.IsError
+	}
+
+	// Without an ID one is generated for the set, and each order gets its index
+	expectPost("A", nil)
+	expectPost("B", nil)
+	text, isErr := placeSet(nil)
+	require.False(t, isErr, text)
+	require.Len(t, sentIDs, 2)
+	assert.True(t, strings.HasPrefix(sentIDs[0], "mcp-"))
+	assert.True(t, strings.HasSuffix(sentIDs[0], "-0"))
+	assert.Equal(t, strings.TrimSuffix(sentIDs[0], "-0")+"-1", sentIDs[1])
+	assert.Contains(t, text, `"client_order_id": "`+sentIDs[1]+`"`)
+
+	// Retrying the same set places nothing
+	text, isErr = placeSet(nil)
+	assert.True(t, isErr)
+	assert.Contains(t, text, "duplicate order submission")
+	assert.Contains(t, text, "placed as order A")
+
+	// A given ID names every order, and a retry is blocked by the orders it placed
+	expectPost("C", nil)
+	expectPost("", errors.New(apiErrorStr))
+	_, isErr = placeSet(map[string]any{clientOrderIDParam: "ladder", rollbackParam: false})
+	assert.True(t, isErr)
+	assert.Equal(t, []string{"ladder-0", "ladder-1"}, sentIDs[2:])
+
+	text, isErr = placeSet(map[string]any{clientOrderIDParam: "ladder"})
+	assert.True(t, isErr)
+	assert.Contains(t, text, "client order ID ladder-0 was submitted")
+	assert.Contains(t, text, "placed as order C")
+
+	text, isErr = placeSet(map[string]any{clientOrderIDParam: strings.Repeat("x", 254)})
+	assert.True(t, isErr)
+	assert.Contains(t, text, "at most 253 characters")
+}
+
+func TestHandlePlaceOrderSetRollbackAfterCancel(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(testOrderSetBalances(), nil)
+	mockClient.EXPECT().GetFeeInfo(mock.Anything, &luno.GetFeeInfoRequest{Pair: "XBTZAR"}).Return(testOrderSetFees(), nil)
+	cfg := &config.Config{LunoClient: mockClient}
+
+	// The call is cancelled once the first order is placed, so the second fails
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).RunAndReturn(
+		func(context.Context, *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+			cancel()
+			return &luno.PostLimitOrderResponse{OrderId: "A"}, nil
+		}).Once()
+	mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+			return nil, ctx.Err()
+		}).Once()
+	mockClient.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "A"}).RunAndReturn(
+		func(ctx context.Context, _ *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			return &luno.StopOrderResponse{Success: true}, nil
+		}).Once()
+
+	result, err := HandlePlaceOrderSet(cfg)(ctx, createMockRequest(map[string]any{"orders": testOrderSet(2)}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+
+	text := getTextContentFromResult(t, result)
+	var report struct {
+		Orders []OrderSetOutcome `json:"orders"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text[strings.Index(text, "{"):]), &report))
+	require.Len(t, report.Orders, 2)
+	assert.Equal(t, OrderOutcomeRolledBack, report.Orders[0].Outcome)
+	assert.Equal(t, OrderOutcomeFailed, report.Orders[1].Outcome)
+}
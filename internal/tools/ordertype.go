@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luno/luno-go"
+)
+
+// OrderSide is the canonical side of an order as exposed by the tools.
+// Luno's limit order API uses BID/ASK while most clients think in BUY/SELL,
+// so both are accepted as input and mapped to a single canonical value.
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+// orderSideSynonyms maps every accepted spelling to its canonical side
+var orderSideSynonyms = map[string]OrderSide{
+	"BUY":  OrderSideBuy,
+	"BID":  OrderSideBuy,
+	"SELL": OrderSideSell,
+	"ASK":  OrderSideSell,
+}
+
+// orderTypeEnum lists all accepted values for the order type parameter
+var orderTypeEnum = []string{"BUY", "SELL", "BID", "ASK"}
+
+// ParseOrderSide converts BUY/SELL or BID/ASK (case insensitive) into a canonical OrderSide
+func ParseOrderSide(s string) (OrderSide, error) {
+	side, ok := orderSideSynonyms[strings.ToUpper(strings.TrimSpace(s))]
+	if !ok {
+		return "", fmt.Errorf("order type must be one of BUY, SELL, BID or ASK, got %q", s)
+	}
+	return side, nil
+}
+
+// LunoOrderType returns the limit order type Luno expects for the side
+func (s OrderSide) LunoOrderType() luno.OrderType {
+	if s == OrderSideBuy {
+		return luno.OrderTypeBid
+	}
+	return luno.OrderTypeAsk
+}
+
+// userTradeSide returns the side of your order in one of your trades. IsBuy
+// on a trade is the side of the taker, which isn't always you.
+func userTradeSide(t luno.TradeV2) OrderSide {
+	if t.Type == luno.OrderTypeBid {
+		return OrderSideBuy
+	}
+	return OrderSideSell
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrderSide(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedSide  OrderSide
+		expectedType  luno.OrderType
+		expectedError bool
+	}{
+		{name: "BUY", input: "BUY", expectedSide: OrderSideBuy, expectedType: luno.OrderTypeBid},
+		{name: "SELL", input: "SELL", expectedSide: OrderSideSell, expectedType: luno.OrderTypeAsk},
+		{name: "BID", input: "BID", expectedSide: OrderSideBuy, expectedType: luno.OrderTypeBid},
+		{name: "ASK", input: "ASK", expectedSide: OrderSideSell, expectedType: luno.OrderTypeAsk},
+		{name: "lowercase buy", input: "buy", expectedSide: OrderSideBuy, expectedType: luno.OrderTypeBid},
+		{name: "lowercase sell", input: "sell", expectedSide: OrderSideSell, expectedType: luno.OrderTypeAsk},
+		{name: "lowercase bid", input: "bid", expectedSide: OrderSideBuy, expectedType: luno.OrderTypeBid},
+		{name: "lowercase ask", input: "ask", expectedSide: OrderSideSell, expectedType: luno.OrderTypeAsk},
+		{name: "mixed case with spaces", input: " Bid ", expectedSide: OrderSideBuy, expectedType: luno.OrderTypeBid},
+		{name: "empty", input: "", expectedError: true},
+		{name: "unknown", input: "HOLD", expectedError: true},
+		{name: "market order type is not a side", input: "MARKET", expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			side, err := ParseOrderSide(tc.input)
+			if tc.expectedError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "BUY, SELL, BID or ASK")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSide, side)
+			assert.Equal(t, tc.expectedType, side.LunoOrderType())
+		})
+	}
+}
+
+func TestOrderTypeEnumIsParseable(t *testing.T) {
+	for _, v := range orderTypeEnum {
+		t.Run(v, func(t *testing.T) {
+			_, err := ParseOrderSide(v)
+			assert.NoError(t, err)
+		})
+	}
+	assert.Len(t, orderTypeEnum, len(orderSideSynonyms))
+}
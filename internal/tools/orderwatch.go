@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Order watch tool IDs
+const (
+	WatchOrderToolID   = "watch_order"
+	UnwatchOrderToolID = "unwatch_order"
+)
+
+// watchParam is the create_order argument that watches the new order
+const watchParam = "watch"
+
+// NewWatchOrderTool creates a new tool for watching an order
+func NewWatchOrderTool() mcp.Tool {
+	return mcp.NewTool(
+		WatchOrderToolID,
+		mcp.WithDescription("Watch an order and notify this session when it is partially filled, filled or cancelled. "+
+			"Orders are checked every few seconds until they complete. "+
+			"Notifications arrive as MCP log messages while the session is connected."),
+		mcp.WithString(
+			"order_id",
+			mcp.Required(),
+			mcp.Description("Order ID to watch"),
+		),
+	)
+}
+
+// HandleWatchOrder handles the watch_order tool
+func HandleWatchOrder(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.OrderWatch == nil {
+			return mcp.NewToolResultError("Order watching is not enabled"), nil
+		}
+
+		orderID, err := request.RequireString("order_id")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting order_id from request", err), nil
+		}
+
+		// Make sure the order exists and is still open before watching it
+		order, err := cfg.LunoClient.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: orderID})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get order: %v", err)), nil
+		}
+		if order.Status == luno.StatusComplete {
+			return mcp.NewToolResultError(fmt.Sprintf("Order %s has already completed, see get_order for its outcome", orderID)), nil
+		}
+
+		watch, err := cfg.OrderWatch.Add(sessionID(ctx), orderID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to watch order: %v", err)), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(watch, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal watch: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// NewUnwatchOrderTool creates a new tool for no longer watching an order
+func NewUnwatchOrderTool() mcp.Tool {
+	return mcp.NewTool(
+		UnwatchOrderToolID,
+		mcp.WithDescription("Stop watching an order, without changing the order"),
+		mcp.WithString(
+			"order_id",
+			mcp.Required(),
+			mcp.Description("Order ID to stop watching"),
+		),
+	)
+}
+
+// HandleUnwatchOrder handles the unwatch_order tool
+func HandleUnwatchOrder(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.OrderWatch == nil {
+			return mcp.NewToolResultError("Order watching is not enabled"), nil
+		}
+
+		orderID, err := request.RequireString("order_id")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting order_id from request", err), nil
+		}
+		if !cfg.OrderWatch.Remove(sessionID(ctx), orderID) {
+			return mcp.NewToolResultError(fmt.Sprintf("Order %s is not being watched, it may have already completed", orderID)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Stopped watching order %s", orderID)), nil
+	}
+}
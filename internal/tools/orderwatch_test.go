@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/orderwatch"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleWatchOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockSetup     func(*sdk.MockLunoClient)
+		errorContains string
+	}{
+		{
+			name: "open order is watched",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "A"}).
+					Return(&luno.GetOrderV3Response{OrderId: "A", Status: luno.StatusPending}, nil)
+			},
+		},
+		{
+			name: "completed order",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).
+					Return(&luno.GetOrderV3Response{OrderId: "A", Status: luno.StatusComplete}, nil)
+			},
+			errorContains: "has already completed",
+		},
+		{
+			name: "lookup error",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			errorContains: "Failed to get order",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+			cfg := &config.Config{LunoClient: mockClient, OrderWatch: orderwatch.NewWatcher(orderwatch.DefaultMaxPerSession)}
+
+			result, err := HandleWatchOrder(cfg)(context.Background(), createMockRequest(map[string]any{"order_id": "A"}))
+			require.NoError(t, err)
+
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				assert.Empty(t, cfg.OrderWatch.List(""))
+				return
+			}
+			assert.False(t, result.IsError, text)
+			assert.Len(t, cfg.OrderWatch.List(""), 1)
+		})
+	}
+}
+
+func TestHandleUnwatchOrder(t *testing.T) {
+	cfg := &config.Config{OrderWatch: orderwatch.NewWatcher(orderwatch.DefaultMaxPerSession)}
+	_, err := cfg.OrderWatch.Add("", "A")
+	require.NoError(t, err)
+
+	unwatch := func() *mcp.CallToolResult {
+		result, err := HandleUnwatchOrder(cfg)(context.Background(), createMockRequest(map[string]any{"order_id": "A"}))
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.False(t, unwatch().IsError)
+	assert.Empty(t, cfg.OrderWatch.List(""))
+
+	result := unwatch()
+	assert.True(t, result.IsError)
+	assert.Contains(t, getTextContentFromResult(t, result), "is not being watched")
+}
+
+func TestHandleCreateOrderWatch(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	expectOrderBalance(mockClient)
+	mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR", Status: "ACTIVE"}, nil)
+	mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+	mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "A"}, nil)
+	cfg := &config.Config{LunoClient: mockClient, OrderWatch: orderwatch.NewWatcher(orderwatch.DefaultMaxPerSession)}
+
+	result, err := HandleCreateOrder(cfg)(context.Background(), createMockRequest(map[string]any{
+		"pair": "XBTZAR", "type": "BUY", "volume": "0.01", "price": "1000000", "watch": true,
+	}))
+	require.NoError(t, err)
+
+	text := getTextContentFromResult(t, result)
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, `"watching": true`)
+	require.Len(t, cfg.OrderWatch.List(""), 1)
+	assert.Equal(t, "A", cfg.OrderWatch.List("")[0].OrderID)
+}
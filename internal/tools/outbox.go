@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListUndeliveredEventsToolID is the ID of the list_undelivered_events tool
+const ListUndeliveredEventsToolID = "list_undelivered_events"
+
+// NewListUndeliveredEventsTool creates a new tool for listing notifications
+// that haven't been delivered
+func NewListUndeliveredEventsTool() mcp.Tool {
+	return mcp.NewTool(
+		ListUndeliveredEventsToolID,
+		mcp.WithDescription(fmt.Sprintf("List the notifications for this session, such as fired price alerts, watched order changes "+
+			"and scheduled buy outcomes, that couldn't be delivered yet. They are sent again every few seconds for %.0f hours, "+
+			"with the number of attempts and the last error shown here. A notification may arrive more than once, "+
+			"so use its event_id to ignore repeats.", outbox.Retention.Hours())),
+	)
+}
+
+// HandleListUndeliveredEvents handles the list_undelivered_events tool
+func HandleListUndeliveredEvents(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Outbox == nil {
+			return mcp.NewToolResultError("Notification delivery is not tracked"), nil
+		}
+
+		events := cfg.Outbox.Undelivered(sessionID(ctx))
+		if events == nil {
+			events = []outbox.Event{}
+		}
+		resultJSON, err := json.MarshalIndent(map[string]any{"events": events}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal events: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListUndeliveredEvents(t *testing.T) {
+	cfg := &config.Config{Outbox: outbox.New()}
+	ctx := context.Background()
+
+	list := func() []outbox.Event {
+		result, err := HandleListUndeliveredEvents(cfg)(ctx, createMockRequest(nil))
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		var got struct {
+			Events []outbox.Event `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+		return got.Events
+	}
+
+	assert.Empty(t, list())
+
+	_, err := cfg.Outbox.Add("", "price-alerts", "Price alert 1", "alert", map[string]any{"pair": "XBTZAR"})
+	require.NoError(t, err)
+	_, err = cfg.Outbox.Add("other", "price-alerts", "Price alert 2", "alert", nil)
+	require.NoError(t, err)
+	cfg.Outbox.Deliver(func(outbox.Event) error { return errors.New("session not found") })
+
+	events := list()
+	require.Len(t, events, 1)
+	assert.Equal(t, "Price alert 1", events[0].Message)
+	assert.Equal(t, 1, events[0].Attempts)
+	assert.Equal(t, "session not found", events[0].LastError)
+
+	result, err := HandleListUndeliveredEvents(&config.Config{})(ctx, createMockRequest(nil))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// PageMetaKey is the _meta key of a truncated result's page
+const PageMetaKey = "page"
+
+// Page describes the rows of a list that a tool result holds, when the list
+// was too long to return whole
+type Page struct {
+	// Total is the number of rows in the full list
+	Total int `json:"total"`
+	// Offset is the index of the first row returned
+	Offset int `json:"offset"`
+	// Returned is the number of rows returned
+	Returned int `json:"returned"`
+	// NextCursor fetches the following rows, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Unit names the rows, e.g. "orders"
+	Unit string `json:"-"`
+}
+
+// Truncated reports whether rows were left out of the result
+func (p *Page) Truncated() bool {
+	return p != nil && p.Returned < p.Total
+}
+
+// Note describes which rows were returned and how to get more
+func (p *Page) Note() string {
+	note := fmt.Sprintf("Showing %s %d-%d of %s.", p.Unit, p.Offset+1, p.Offset+p.Returned, formatCount(p.Total))
+	if p.NextCursor != "" {
+		note += fmt.Sprintf(" Use cursor %q for more.", p.NextCursor)
+	}
+	return note
+}
+
+// withPagination adds the optional cursor and max_results parameters to a
+// listing tool
+func withPagination() mcp.ToolOption {
+	cursor := mcp.WithString(
+		"cursor",
+		mcp.Description("Cursor from a previous truncated result, to fetch the rows that follow it. Use the same other arguments."),
+	)
+	maxResults := mcp.WithNumber(
+		"max_results",
+		mcp.Description("Maximum number of rows to return, up to the server's limit"),
+	)
+	return func(t *mcp.Tool) {
+		cursor(t)
+		maxResults(t)
+	}
+}
+
+// pageRequest is the window of rows a call asked for
+type pageRequest struct {
+	offset int
+	// limit is the most rows to return, zero for no limit
+	limit int
+}
+
+// parsePage reads the cursor and max_results parameters. maxRows is the
+// server's limit on rows per result, zero for no limit.
+func parsePage(request mcp.CallToolRequest, maxRows int) (pageRequest, error) {
+	p := pageRequest{limit: maxRows}
+	if cursor := strings.TrimSpace(request.GetString("cursor", "")); cursor != "" {
+		offset, err := strconv.Atoi(cursor)
+		if err != nil || offset < 0 {
+			return pageRequest{}, fmt.Errorf("invalid cursor %q, use the cursor from a truncated result", cursor)
+		}
+		p.offset = offset
+	}
+	if maxResults := request.GetInt("max_results", 0); maxResults != 0 {
+		if maxResults < 0 {
+			return pageRequest{}, errors.New("max_results must be positive")
+		}
+		if p.limit == 0 || maxResults < p.limit {
+			p.limit = maxResults
+		}
+	}
+	return p, nil
+}
+
+// paginate returns the requested window of rows, and the page it is when
+// rows are left out. The rows are resliced, not copied.
+func paginate[T any](rows []T, p pageRequest, unit string) ([]T, *Page) {
+	if p.offset == 0 && (p.limit == 0 || len(rows) <= p.limit) {
+		return rows, nil
+	}
+	start := min(p.offset, len(rows))
+	end := len(rows)
+	if p.limit > 0 {
+		end = min(start+p.limit, len(rows))
+	}
+	page := &Page{Total: len(rows), Offset: start, Returned: end - start, Unit: unit}
+	if end < len(rows) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return rows[start:end:end], page
+}
+
+// formatCount formats n with thousands separators, e.g. 1,243
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
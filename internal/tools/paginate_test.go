@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		maxRows       int
+		expected      pageRequest
+		errorContains string
+	}{
+		{name: "server limit", params: nil, maxRows: 100, expected: pageRequest{limit: 100}},
+		{name: "no limit", params: nil, maxRows: 0, expected: pageRequest{}},
+		{name: "cursor", params: map[string]any{"cursor": "50"}, maxRows: 100, expected: pageRequest{offset: 50, limit: 100}},
+		{name: "lower max_results", params: map[string]any{"max_results": float64(10)}, maxRows: 100, expected: pageRequest{limit: 10}},
+		{name: "max_results above the limit", params: map[string]any{"max_results": float64(500)}, maxRows: 100, expected: pageRequest{limit: 100}},
+		{name: "max_results without a limit", params: map[string]any{"max_results": float64(500)}, maxRows: 0, expected: pageRequest{limit: 500}},
+		{name: "invalid cursor", params: map[string]any{"cursor": "next"}, maxRows: 100, errorContains: `invalid cursor "next"`},
+		{name: "negative cursor", params: map[string]any{"cursor": "-5"}, maxRows: 100, errorContains: "invalid cursor"},
+		{name: "negative max_results", params: map[string]any{"max_results": float64(-1)}, maxRows: 100, errorContains: "max_results must be positive"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := parsePage(createMockRequest(tc.params), tc.maxRows)
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	rows := []int{0, 1, 2, 3, 4, 5, 6}
+	tests := []struct {
+		name         string
+		req          pageRequest
+		expectedRows []int
+		expectedPage *Page
+	}{
+		{name: "fits", req: pageRequest{limit: 10}, expectedRows: rows},
+		{name: "no limit", req: pageRequest{}, expectedRows: rows},
+		{name: "first page", req: pageRequest{limit: 3}, expectedRows: []int{0, 1, 2},
+			expectedPage: &Page{Total: 7, Offset: 0, Returned: 3, NextCursor: "3", Unit: "rows"}},
+		{name: "middle page", req: pageRequest{offset: 3, limit: 3}, expectedRows: []int{3, 4, 5},
+			expectedPage: &Page{Total: 7, Offset: 3, Returned: 3, NextCursor: "6", Unit: "rows"}},
+		{name: "last page", req: pageRequest{offset: 6, limit: 3}, expectedRows: []int{6},
+			expectedPage: &Page{Total: 7, Offset: 6, Returned: 1, Unit: "rows"}},
+		{name: "past the end", req: pageRequest{offset: 20, limit: 3}, expectedRows: []int{},
+			expectedPage: &Page{Total: 7, Offset: 7, Returned: 0, Unit: "rows"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, page := paginate(rows, tc.req, "rows")
+			assert.Equal(t, tc.expectedRows, got)
+			assert.Equal(t, tc.expectedPage, page)
+		})
+	}
+
+	// Appending to a page must not overwrite the rows after it
+	got, _ := paginate(rows, pageRequest{limit: 3}, "rows")
+	_ = append(got, 99)
+	assert.Equal(t, 3, rows[3])
+}
+
+func TestPageNote(t *testing.T) {
+	page := &Page{Total: 1243, Offset: 0, Returned: 50, NextCursor: "50", Unit: "transactions"}
+	assert.True(t, page.Truncated())
+	assert.Equal(t, `Showing transactions 1-50 of 1,243. Use cursor "50" for more.`, page.Note())
+
+	last := &Page{Total: 1243, Offset: 1200, Returned: 43, Unit: "transactions"}
+	assert.Equal(t, "Showing transactions 1201-1243 of 1,243.", last.Note())
+
+	var none *Page
+	assert.False(t, none.Truncated())
+}
+
+func TestFormatCount(t *testing.T) {
+	assert.Equal(t, "7", formatCount(7))
+	assert.Equal(t, "999", formatCount(999))
+	assert.Equal(t, "1,243", formatCount(1243))
+	assert.Equal(t, "1,000,000", formatCount(1000000))
+}
+
+func TestResponseResultWithPage(t *testing.T) {
+	page := &Page{Total: 3, Offset: 0, Returned: 2, NextCursor: "2", Unit: "orders"}
+	response := Response{
+		URI:     resultURI(ListOrdersToolID),
+		Summary: "3 orders",
+		Data:    map[string]any{"orders": []string{"a", "b"}},
+		Table:   &Table{Headers: []string{"Order"}, Rows: [][]string{{"a"}, {"b"}}},
+		Page:    page,
+	}
+
+	t.Run("json keeps the note apart", func(t *testing.T) {
+		result, err := response.Result(FormatJSON)
+		require.NoError(t, err)
+		require.Len(t, result.Content, 2)
+		var data map[string]any
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &data))
+		assert.Equal(t, page.Note(), result.Content[1].(mcp.TextContent).Text)
+		assert.Equal(t, page, result.Meta.AdditionalFields[PageMetaKey])
+	})
+
+	t.Run("summary includes the note", func(t *testing.T) {
+		result, err := response.Result(FormatSummary)
+		require.NoError(t, err)
+		assert.Equal(t, `3 orders. Showing orders 1-2 of 3. Use cursor "2" for more.`, result.Content[0].(mcp.TextContent).Text)
+		assert.Equal(t, page, result.Meta.AdditionalFields[PageMetaKey])
+	})
+
+	t.Run("whole list has no page", func(t *testing.T) {
+		whole := response
+		whole.Page = nil
+		result, err := whole.Result(FormatJSON)
+		require.NoError(t, err)
+		assert.Len(t, result.Content, 1)
+		assert.Nil(t, result.Meta)
+	})
+}
+
+func TestHandleListTradesPagination(t *testing.T) {
+	trades := make([]luno.PublicTrade, 5)
+	for i := range trades {
+		trades[i].Sequence = int64(i)
+	}
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().ListTrades(context.Background(), &luno.ListTradesRequest{Pair: "XBTZAR"}).
+		Return(&luno.ListTradesResponse{Trades: trades}, nil).Twice()
+	cfg := &config.Config{LunoClient: mockClient, MaxResultRows: 2}
+
+	result, err := HandleListTrades(cfg)(context.Background(), createMockRequest(map[string]any{"pair": "XBTZAR"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var first luno.ListTradesResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &first))
+	require.Len(t, first.Trades, 2)
+	assert.Equal(t, int64(1), first.Trades[1].Sequence)
+	assert.Equal(t, `Showing trades 1-2 of 5. Use cursor "2" for more.`, result.Content[1].(mcp.TextContent).Text)
+
+	result, err = HandleListTrades(cfg)(context.Background(), createMockRequest(map[string]any{"pair": "XBTZAR", "cursor": "2"}))
+	require.NoError(t, err)
+	var second luno.ListTradesResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &second))
+	require.Len(t, second.Trades, 2)
+	assert.Equal(t, []int64{2, 3}, []int64{second.Trades[0].Sequence, second.Trades[1].Sequence})
+}
+
+func TestHandleGetOrderBookPagination(t *testing.T) {
+	entry := func(price int64) luno.OrderBookEntry {
+		return luno.OrderBookEntry{Price: decimal.NewFromInt64(price), Volume: decimal.NewFromInt64(1)}
+	}
+	book := &luno.GetOrderBookResponse{
+		Bids:      []luno.OrderBookEntry{entry(100), entry(99), entry(98)},
+		Asks:      []luno.OrderBookEntry{entry(101), entry(102)},
+		Timestamp: 1,
+	}
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetOrderBook(context.Background(), &luno.GetOrderBookRequest{Pair: "XBTZAR"}).Return(book, nil)
+	cfg := &config.Config{LunoClient: mockClient, MaxResultRows: 1}
+
+	result, err := HandleGetOrderBook(cfg)(context.Background(), createMockRequest(map[string]any{"pair": "XBTZAR"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var got luno.GetOrderBookResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got))
+	assert.Len(t, got.Bids, 1)
+	assert.Len(t, got.Asks, 1)
+	assert.Equal(t, "100", got.Bids[0].Price.String())
+	assert.Equal(t, &Page{Total: 3, Offset: 0, Returned: 1, NextCursor: "1", Unit: "price levels"}, result.Meta.AdditionalFields[PageMetaKey])
+	// The cached response is left whole
+	assert.Len(t, book.Bids, 3)
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/toolmw"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxParallelCalls caps the Luno calls a single tool makes at once, keeping
+// aggregate tools well inside the API rate limits
+const maxParallelCalls = 4
+
+// parallel runs independent Luno calls concurrently, each with its own
+// cfg.CallTimeout. The first error cancels the calls still running and is
+// returned. Calls that should not fail the others must record their error
+// and return nil.
+func parallel(ctx context.Context, cfg *config.Config, calls ...func(context.Context) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxParallelCalls)
+	for _, call := range calls {
+		g.Go(func() error {
+			callCtx, cancel := withCallTimeout(ctx, cfg)
+			defer cancel()
+			// A panic here would escape the tool's recovery, which runs in another goroutine
+			_, err := toolmw.Recovered("parallel call", func() (struct{}, error) {
+				return struct{}{}, call(callCtx)
+			})
+			return err
+		})
+	}
+	return g.Wait()
+}
+
+// forEach runs fn concurrently for each item, like parallel. fn gets the
+// index of its item so results can be stored in order.
+func forEach[T any](ctx context.Context, cfg *config.Config, items []T, fn func(ctx context.Context, i int, item T) error) error {
+	calls := make([]func(context.Context) error, len(items))
+	for i, item := range items {
+		calls[i] = func(ctx context.Context) error { return fn(ctx, i, item) }
+	}
+	return parallel(ctx, cfg, calls...)
+}
+
+// withCallTimeout bounds a single Luno call by cfg.CallTimeout, a zero
+// timeout only uses the deadline of ctx
+func withCallTimeout(ctx context.Context, cfg *config.Config) (context.Context, context.CancelFunc) {
+	if cfg.CallTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cfg.CallTimeout)
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallel(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeout       time.Duration
+		calls         []func(context.Context) error
+		expectedError error
+	}{
+		{
+			name:    "all succeed",
+			timeout: time.Second,
+			calls: []func(context.Context) error{
+				func(context.Context) error { return nil },
+				func(context.Context) error { return nil },
+			},
+		},
+		{
+			name:    "call over its timeout",
+			timeout: 10 * time.Millisecond,
+			calls: []func(context.Context) error{
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			expectedError: context.DeadlineExceeded,
+		},
+		{
+			name: "first error cancels the others",
+			calls: []func(context.Context) error{
+				// Blocks until the failing call cancels it
+				func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				},
+				func(context.Context) error { return errors.New(apiErrorStr) },
+			},
+			expectedError: errors.New(apiErrorStr),
+		},
+		{
+			name: "panic is returned as an error",
+			calls: []func(context.Context) error{
+				func(context.Context) error { panic("nil map") },
+			},
+			expectedError: errors.New("parallel call panicked: nil map"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := parallel(context.Background(), &config.Config{CallTimeout: tc.timeout}, tc.calls...)
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError.Error())
+		})
+	}
+}
+
+func TestForEachKeepsOrder(t *testing.T) {
+	items := []int{5, 4, 3, 2, 1}
+	results := make([]int, len(items))
+	err := forEach(context.Background(), &config.Config{}, items, func(_ context.Context, i int, item int) error {
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		results[i] = item * 10
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{50, 40, 30, 20, 10}, results)
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/luno/luno-mcp/internal/pnl"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CalculatePnLToolID is the ID of the realized profit and loss tool
+const CalculatePnLToolID = "calculate_pnl"
+
+const (
+	// userTradesPageSize is the most trades the API returns per request
+	userTradesPageSize = 1000
+
+	// maxUserTradesPages caps the requests made per pair
+	maxUserTradesPages = 50
+)
+
+// PairPnL is the realized profit and loss of trading a pair
+type PairPnL struct {
+	Pair     string `json:"pair"`
+	Asset    string `json:"asset"`
+	Currency string `json:"currency"`
+	Trades   int    `json:"trades"`
+	// Complete is false when the trade history was too long to fetch in full
+	Complete bool `json:"complete"`
+	pnl.Report
+}
+
+// PnLReport is the realized profit and loss over a window
+type PnLReport struct {
+	Start time.Time `json:"start,omitzero"`
+	End   time.Time `json:"end"`
+	Pairs []PairPnL `json:"pairs"`
+	Note  string    `json:"note"`
+}
+
+// NewCalculatePnLTool creates a new tool for calculating realized profit and loss
+func NewCalculatePnLTool() mcp.Tool {
+	return mcp.NewTool(
+		CalculatePnLToolID,
+		mcp.WithDescription("Calculate the realized profit or loss of your sales per pair, in the counter currency, "+
+			"by matching them against your earlier purchases. Fees are included in the cost of purchases and taken "+
+			"off the proceeds of sales. Also returns what is still held and its cost."),
+		mcp.WithString(
+			"pairs",
+			mcp.Required(),
+			mcp.Description("Comma separated list of trading pairs (e.g., XBTZAR,ETHZAR)"),
+		),
+		mcp.WithString(
+			"method",
+			mcp.Enum(string(pnl.MethodFIFO), string(pnl.MethodAverage)),
+			mcp.Description("Cost basis method: fifo (default) matches sales against the oldest purchases first, "+
+				"average uses the average cost of the holding"),
+		),
+		mcp.WithString(
+			"start",
+			mcp.Description("Only count sales from this time: a date (YYYY-MM-DD, from the start of that day in UTC), "+
+				"an RFC 3339 time or Unix milliseconds. Earlier trades are still used for the cost basis."),
+		),
+		mcp.WithString(
+			"end",
+			mcp.Description("Only count trades up to this time: a date (YYYY-MM-DD, to the end of that day in UTC), "+
+				"an RFC 3339 time or Unix milliseconds. Defaults to now."),
+		),
+	)
+}
+
+// HandleCalculatePnL handles the calculate_pnl tool
+func HandleCalculatePnL(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		pairsStr, err := request.RequireString("pairs")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pairs from request", err), nil
+		}
+		var pairs []string
+		for _, p := range strings.Split(pairsStr, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				pairs = append(pairs, market.NormalizePair(p))
+			}
+		}
+		if len(pairs) == 0 {
+			return mcp.NewToolResultError("At least one trading pair is required"), nil
+		}
+
+		method, err := pnl.ParseMethod(request.GetString("method", string(pnl.MethodFIFO)))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var start time.Time
+		if startStr := request.GetString("start", ""); startStr != "" {
+			if start, err = parseStartTimestamp(startStr); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		end := time.Now().UTC()
+		if endStr := request.GetString("end", ""); endStr != "" {
+			if end, err = parseTimestamp(endStr); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		if !start.Before(end) {
+			return mcp.NewToolResultError("start must be before end"), nil
+		}
+
+		markets, err := ListMarkets(ctx, cfg)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting market info", err), nil
+		}
+
+		report := PnLReport{
+			Start: start,
+			End:   end,
+			Note: "Realized results only count sales matched against purchases on the same pair. " +
+				"Coins that were deposited or bought on another pair have no known cost and are reported as unmatched.",
+		}
+		report.Pairs = make([]PairPnL, len(pairs))
+		for i, pair := range pairs {
+			// Markets that are no longer open still have a trade history
+			info, err := market.ValidatePair(markets, pair)
+			if info == nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			report.Pairs[i] = PairPnL{Pair: pair, Asset: info.BaseCurrency, Currency: info.CounterCurrency}
+		}
+		err = forEach(ctx, cfg, report.Pairs, func(ctx context.Context, i int, p PairPnL) error {
+			trades, complete, err := listAllUserTrades(ctx, cfg.LunoClient, p.Pair, end)
+			if err != nil {
+				return fmt.Errorf("listing trades for %s: %w", p.Pair, err)
+			}
+			report.Pairs[i].Trades = len(trades)
+			report.Pairs[i].Complete = complete
+			report.Pairs[i].Report = pnl.Calculate(trades, method, start, end)
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal profit and loss: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// listAllUserTrades pages through your trades on a pair from the oldest up to
+// end. It reports false when the history was longer than maxUserTradesPages.
+func listAllUserTrades(ctx context.Context, client sdk.LunoClient, pair string, end time.Time) ([]pnl.Trade, bool, error) {
+	var trades []pnl.Trade
+	var afterSeq int64
+	for range maxUserTradesPages {
+		res, err := client.ListUserTrades(ctx, &luno.ListUserTradesRequest{Pair: pair, AfterSeq: afterSeq, Limit: userTradesPageSize})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, t := range res.Trades {
+			ts := time.Time(t.Timestamp)
+			if ts.After(end) {
+				return trades, true, nil
+			}
+			trades = append(trades, pnl.Trade{
+				Time:       ts,
+				Buy:        userTradeSide(t) == OrderSideBuy,
+				Base:       t.Base,
+				Counter:    t.Counter,
+				FeeBase:    t.FeeBase,
+				FeeCounter: t.FeeCounter,
+			})
+			afterSeq = t.Sequence + 1
+		}
+		if len(res.Trades) < userTradesPageSize {
+			return trades, true, nil
+		}
+	}
+	return trades, false, nil
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCalculatePnL(t *testing.T) {
+	day := func(d int) luno.Time { return luno.Time(time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)) }
+	trades := []luno.TradeV2{
+		// Taker flags are the opposite of our side, the order type decides
+		{Sequence: 1, Timestamp: day(1), Type: luno.OrderTypeBid, IsBuy: false, Base: decimal.NewFromInt64(1), Counter: decimal.NewFromInt64(100),
+			FeeBase: decimal.Zero(), FeeCounter: decimal.Zero()},
+		{Sequence: 2, Timestamp: day(2), Type: luno.OrderTypeBid, IsBuy: false, Base: decimal.NewFromInt64(1), Counter: decimal.NewFromInt64(200),
+			FeeBase: decimal.Zero(), FeeCounter: decimal.Zero()},
+		{Sequence: 3, Timestamp: day(3), Type: luno.OrderTypeAsk, IsBuy: true, Base: decimal.NewFromInt64(1), Counter: decimal.NewFromInt64(250),
+			FeeBase: decimal.Zero(), FeeCounter: decimal.Zero()},
+	}
+
+	tests := []struct {
+		name             string
+		params           map[string]any
+		mockSetup        func(*sdk.MockLunoClient)
+		expectedError    string
+		expectedRealized string
+		expectedHolding  string
+	}{
+		{
+			name:   "fifo",
+			params: map[string]any{"pairs": "xbtzar"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().ListUserTrades(mock.Anything, &luno.ListUserTradesRequest{Pair: "XBTZAR", Limit: userTradesPageSize}).
+					Return(&luno.ListUserTradesResponse{Trades: trades}, nil)
+			},
+			expectedRealized: "150",
+			expectedHolding:  "1",
+		},
+		{
+			name:   "weighted average",
+			params: map[string]any{"pairs": "XBTZAR", "method": "average"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(&luno.ListUserTradesResponse{Trades: trades}, nil)
+			},
+			expectedRealized: "100.00000000",
+			expectedHolding:  "1",
+		},
+		{
+			name:   "end excludes later trades",
+			params: map[string]any{"pairs": "XBTZAR", "end": "2024-03-02"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(&luno.ListUserTradesResponse{Trades: trades}, nil)
+			},
+			expectedRealized: "0",
+			expectedHolding:  "2",
+		},
+		{
+			name:   "suspended markets still report",
+			params: map[string]any{"pairs": "ETHZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(&luno.ListUserTradesResponse{}, nil)
+			},
+			expectedRealized: "0",
+			expectedHolding:  "0",
+		},
+		{
+			name:   "unknown pair",
+			params: map[string]any{"pairs": "DOGEZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError: "DOGEZAR is not a valid Luno market",
+		},
+		{
+			name:          "unknown method",
+			params:        map[string]any{"pairs": "XBTZAR", "method": "lifo"},
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			expectedError: "unknown method",
+		},
+		{
+			name:   "trades error",
+			params: map[string]any{"pairs": "XBTZAR"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: "listing trades for XBTZAR",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleCalculatePnL(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.expectedError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.expectedError)
+				return
+			}
+			require.False(t, result.IsError, text)
+
+			var report PnLReport
+			require.NoError(t, json.Unmarshal([]byte(text), &report))
+			require.Len(t, report.Pairs, 1)
+			assert.True(t, report.Pairs[0].Complete)
+			assert.Equal(t, tc.expectedRealized, report.Pairs[0].Realized.String())
+			assert.Equal(t, tc.expectedHolding, report.Pairs[0].Holding.String())
+		})
+	}
+}
+
+func TestListAllUserTradesPages(t *testing.T) {
+	page := make([]luno.TradeV2, userTradesPageSize)
+	for i := range page {
+		page[i] = luno.TradeV2{Sequence: int64(i + 1), Type: luno.OrderTypeBid, Timestamp: luno.Time(time.UnixMilli(int64(i)))}
+	}
+
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().ListUserTrades(mock.Anything, &luno.ListUserTradesRequest{Pair: "XBTZAR", Limit: userTradesPageSize}).
+		Return(&luno.ListUserTradesResponse{Trades: page}, nil)
+	mockClient.EXPECT().ListUserTrades(mock.Anything, &luno.ListUserTradesRequest{Pair: "XBTZAR", AfterSeq: userTradesPageSize + 1, Limit: userTradesPageSize}).
+		Return(&luno.ListUserTradesResponse{Trades: []luno.TradeV2{{Sequence: userTradesPageSize + 1, Timestamp: luno.Time(time.UnixMilli(userTradesPageSize))}}}, nil)
+
+	trades, complete, err := listAllUserTrades(context.Background(), mockClient, "XBTZAR", time.Now())
+	require.NoError(t, err)
+	assert.True(t, complete)
+	assert.Len(t, trades, userTradesPageSize+1)
+	assert.True(t, trades[0].Buy)
+	assert.False(t, trades[userTradesPageSize].Buy)
+}
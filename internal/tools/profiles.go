@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListProfilesToolID is the ID of the credential profile listing tool
+const ListProfilesToolID = "list_profiles"
+
+// NewListProfilesTool creates a new tool for listing credential profiles
+func NewListProfilesTool() mcp.Tool {
+	return mcp.NewTool(
+		ListProfilesToolID,
+		mcp.WithDescription("List the Luno credential profiles this server is configured with, e.g. separate trading and savings accounts. "+
+			"When there is more than one, pass a profile name as the profile argument of other tools to use that account."),
+	)
+}
+
+// HandleListProfiles handles the list_profiles tool
+func HandleListProfiles(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		profiles := cfg.Profiles
+		if profiles == nil {
+			profiles = []config.Profile{{Name: config.DefaultProfile, Default: true}}
+		}
+
+		resultJSON, err := json.MarshalIndent(map[string]any{"profiles": profiles}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal profiles: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []config.Profile
+		expected []string
+	}{
+		{
+			name:     "no profiles configured",
+			expected: []string{config.DefaultProfile},
+		},
+		{
+			name: "several profiles",
+			profiles: []config.Profile{
+				{Name: config.DefaultProfile, APIKeyID: "abcd****", Default: true},
+				{Name: "savings", APIKeyID: "efgh****"},
+			},
+			expected: []string{config.DefaultProfile, "savings"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := HandleListProfiles(&config.Config{Profiles: tc.profiles})(context.Background(), createMockRequest(nil))
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			var got struct {
+				Profiles []config.Profile `json:"profiles"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+			var names []string
+			for _, p := range got.Profiles {
+				names = append(names, p.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+			assert.True(t, got.Profiles[0].Default)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ReplaceOrderToolID is the ID of the tool that re-prices an open order
+const ReplaceOrderToolID = "replace_order"
+
+// replaceTimeout bounds placing the new order once the old one is cancelled,
+// which carries on after the call's own context is cancelled
+const replaceTimeout = 10 * time.Second
+
+// Outcomes reported by replace_order
+const (
+	ReplaceOutcomeReplaced = "replaced"
+	// ReplaceOutcomeNotCancelled means nothing changed
+	ReplaceOutcomeNotCancelled = "not_cancelled"
+	// ReplaceOutcomeCancelledNotReplaced means the old order is gone but no
+	// new order took its place
+	ReplaceOutcomeCancelledNotReplaced = "cancelled_not_replaced"
+)
+
+// ReplaceOrderResult reports both steps of replacing an order
+type ReplaceOrderResult struct {
+	Outcome    string `json:"outcome"`
+	OldOrderID string `json:"old_order_id"`
+	Cancelled  bool   `json:"cancelled"`
+	// FilledBeforeCancel is the volume of the old order that traded before
+	// it was cancelled
+	FilledBeforeCancel decimal.Decimal  `json:"filled_before_cancel"`
+	NewOrderID         string           `json:"new_order_id,omitempty"`
+	ClientOrderID      string           `json:"client_order_id,omitempty"`
+	Pair               string           `json:"pair"`
+	Side               OrderSide        `json:"side"`
+	Volume             decimal.Decimal  `json:"volume"`
+	Price              decimal.Decimal  `json:"price"`
+	PostOnly           bool             `json:"post_only,omitempty"`
+	TimeInForce        luno.TimeInForce `json:"time_in_force,omitempty"`
+	Error              string           `json:"error,omitempty"`
+}
+
+// NewReplaceOrderTool creates a new tool for replacing an open limit order
+func NewReplaceOrderTool() mcp.Tool {
+	return mcp.NewTool(
+		ReplaceOrderToolID,
+		mcp.WithDescription("Replace an open limit order with one at a new price or volume on the same pair and side: "+
+			"the order is cancelled, then the new order is placed. The new order is checked before anything is cancelled, "+
+			"and the outcome of both steps is reported. Luno doesn't report an order's post_only or time_in_force, "+
+			"so give them again to keep them on the new order."),
+		mcp.WithString(
+			"order_id",
+			mcp.Required(),
+			mcp.Description("ID of the open limit order to replace"),
+		),
+		mcp.WithString(
+			"price",
+			mcp.Description("New limit price. Keeps the order's price if empty. "+amountDesc),
+		),
+		mcp.WithString(
+			"volume",
+			mcp.Description("Volume of the new order. Keeps the volume left unfilled on the order if empty. "+amountDesc),
+		),
+		withExecutionOptions(),
+		withClientOrderID(),
+		withConfirmToken(),
+		withDryRun(),
+	)
+}
+
+// HandleReplaceOrder handles the replace_order tool
+func HandleReplaceOrder(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		orderID, err := request.RequireString("order_id")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting order_id from request", err), nil
+		}
+		priceStr := request.GetString("price", "")
+		volumeStr := request.GetString("volume", "")
+		if priceStr == "" && volumeStr == "" {
+			return mcp.NewToolResultError("give a new price, volume or both"), nil
+		}
+
+		opts, err := parseExecutionOptions(request)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+		clientOrderID, givenID, err := clientOrderID(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid client order ID", err), nil
+		}
+
+		order, err := cfg.LunoClient.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: orderID})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: could not look it up: %v", err)), nil
+		}
+		if err := checkReplaceable(order); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+		if err := checkPairAllowed(cfg, order.Pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+		market, err := ValidatePair(ctx, cfg, order.Pair)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+
+		side := OrderSide(order.Side)
+		remaining := order.LimitVolume.Sub(order.Base)
+		price, volume := order.LimitPrice, remaining
+		if priceStr != "" {
+			var priceCurrency string
+			price, priceCurrency, err = parseAmount(priceStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid price format: %v", err)), nil
+			}
+			if err := checkAmountCurrency("price", priceCurrency, market.CounterCurrency, order.Pair); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+			}
+		}
+		if volumeStr != "" {
+			var volumeCurrency string
+			volume, volumeCurrency, err = parseAmount(volumeStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid volume format: %v", err)), nil
+			}
+			if err := checkAmountCurrency("volume", volumeCurrency, market.BaseCurrency, order.Pair); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+			}
+		}
+		if err := ValidateOrderSize(market, volume, price); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+
+		// Cancelling the order frees up what it holds, so that counts
+		// towards funding the new one
+		released := remaining
+		if side == OrderSideBuy {
+			released = remaining.Mul(order.LimitPrice)
+		}
+		if err := checkOrderBalance(ctx, cfg, market, side, volume, price, released); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+
+		release, err := reserveOrderValue(ctx, cfg, market, volume, price)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to replace order: %v", err)), nil
+		}
+		placed := false
+		defer func() {
+			if !placed {
+				release()
+			}
+		}()
+
+		preview := map[string]string{
+			"order_id":  orderID,
+			"pair":      order.Pair,
+			"side":      string(side),
+			"old_order": fmt.Sprintf("%s at %s, %s filled", order.LimitVolume.String(), order.LimitPrice.String(), order.Base.String()),
+			"volume":    volume.String(),
+			"price":     price.String(),
+			"total":     volume.Mul(price).String() + " " + market.CounterCurrency,
+		}
+		details := []string{orderID, volume.String(), price.String()}
+		details = opts.describe(preview, details)
+		if givenID {
+			preview[clientOrderIDParam] = clientOrderID
+			details = append(details, clientOrderID)
+		}
+		if isDryRun(cfg, request) {
+			return dryRunResult(ReplaceOrderToolID, preview), nil
+		}
+		binding := strings.Join(details, "\n")
+		if res := requireConfirmation(cfg, request, ReplaceOrderToolID, binding, preview); res != nil {
+			return res, nil
+		}
+
+		result := ReplaceOrderResult{
+			Outcome:            ReplaceOutcomeNotCancelled,
+			OldOrderID:         orderID,
+			FilledBeforeCancel: order.Base,
+			Pair:               order.Pair,
+			Side:               side,
+			Volume:             volume,
+			Price:              price,
+			PostOnly:           opts.PostOnly,
+			TimeInForce:        opts.TimeInForce,
+		}
+
+		if _, err := cfg.LunoClient.StopOrder(ctx, &luno.StopOrderRequest{OrderId: orderID}); err != nil {
+			result.Error = fmt.Sprintf("could not cancel the order: %v", err)
+			return replaceOrderResult("The order was not cancelled and no new order was placed", result, true)
+		}
+		result.Cancelled = true
+		result.Outcome = ReplaceOutcomeCancelledNotReplaced
+
+		// The old order is gone, so a cancelled or timed out call must still
+		// get the new one placed
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), replaceTimeout)
+		defer cancel()
+
+		// The order may have traded between looking it up and cancelling it
+		if final, err := cfg.LunoClient.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: orderID}); err != nil {
+			slog.Warn("Could not look up the cancelled order", "order_id", orderID, "error", err)
+		} else if final.Base.Cmp(order.Base) != 0 {
+			result.FilledBeforeCancel = final.Base
+			if volumeStr == "" {
+				result.Volume = final.LimitVolume.Sub(final.Base)
+				if err := ValidateOrderSize(market, result.Volume, price); err != nil {
+					result.Error = fmt.Sprintf("the order traded before it was cancelled, leaving %s to place: %v", result.Volume.String(), err)
+					return replaceOrderResult(notReplacedSummary(orderID), result, true)
+				}
+			}
+		}
+
+		finishSubmission, err := beginSubmission(ctx, cfg, orderSubmission, clientOrderID, givenID, binding)
+		if err != nil {
+			result.Error = err.Error()
+			return replaceOrderResult(notReplacedSummary(orderID), result, true)
+		}
+		req := &luno.PostLimitOrderRequest{
+			Pair:          order.Pair,
+			Type:          side.LunoOrderType(),
+			Volume:        result.Volume,
+			Price:         price,
+			ClientOrderId: clientOrderID,
+		}
+		opts.apply(req)
+		newOrder, err := cfg.LunoClient.PostLimitOrder(ctx, req)
+		if err != nil {
+			finishSubmission("", err)
+			result.Error = fmt.Sprintf("could not place the new order: %v", err)
+			return replaceOrderResult(notReplacedSummary(orderID), result, true)
+		}
+		placed = true
+		finishSubmission(newOrder.OrderId, nil)
+		result.Outcome = ReplaceOutcomeReplaced
+		result.NewOrderID = newOrder.OrderId
+		result.ClientOrderID = clientOrderID
+
+		return replaceOrderResult(fmt.Sprintf("Order %s was replaced by order %s", orderID, newOrder.OrderId), result, false)
+	}
+}
+
+// checkReplaceable checks that an order is an open limit order
+func checkReplaceable(order *luno.GetOrderV3Response) error {
+	if order.Type != luno.TypeLimit {
+		return fmt.Errorf("order %s is a %s order, only limit orders can be replaced", order.OrderId, order.Type)
+	}
+	if order.Status != luno.StatusAwaiting && order.Status != luno.StatusPending {
+		return fmt.Errorf("order %s is %s and no longer open", order.OrderId, order.Status)
+	}
+	if order.Side != luno.SideBuy && order.Side != luno.SideSell {
+		return fmt.Errorf("order %s has unknown side %q", order.OrderId, order.Side)
+	}
+	return nil
+}
+
+// notReplacedSummary explains that the old order was cancelled without a new
+// order taking its place
+func notReplacedSummary(orderID string) string {
+	return fmt.Sprintf("Order %s was cancelled but the new order was NOT placed, so nothing is left on the book in its place. "+
+		"Place a new order to restore it.", orderID)
+}
+
+// replaceOrderResult renders the outcome of a replacement after a summary
+// line, like orderSetResult
+func replaceOrderResult(summary string, result ReplaceOrderResult, failed bool) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal replace order result: %v", err)), nil
+	}
+	text := fmt.Sprintf("%s\n\n%s", summary, resultJSON)
+	if failed {
+		return mcp.NewToolResultError(text), nil
+	}
+	return mcp.NewToolResultText(text), nil
+}
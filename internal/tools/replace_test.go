@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testOpenOrder is a sell of 0.5 XBT at 1000000 with 0.1 filled
+func testOpenOrder(filled float64) *luno.GetOrderV3Response {
+	return &luno.GetOrderV3Response{
+		OrderId:     "BXOLD",
+		Pair:        "XBTZAR",
+		Side:        luno.SideSell,
+		Type:        luno.TypeLimit,
+		Status:      luno.StatusPending,
+		LimitVolume: decimal.NewFromFloat64(0.5, 6),
+		LimitPrice:  decimal.NewFromInt64(1000000),
+		Base:        decimal.NewFromFloat64(filled, 6),
+	}
+}
+
+// expectReplaceChecks sets up the lookups made before the order is cancelled.
+// The XBT held by the old order is all reserved, so the new order is only
+// funded by cancelling it.
+func expectReplaceChecks(m *sdk.MockLunoClient) {
+	m.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "BXOLD"}).Return(testOpenOrder(0.1), nil).Once()
+	m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{Asset: "XBT", Balance: decimal.NewFromFloat64(0.4, 6), Reserved: decimal.NewFromFloat64(0.4, 6)},
+	}}, nil)
+	m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(&luno.GetFeeInfoResponse{TakerFee: "0.001"}, nil)
+}
+
+func TestHandleReplaceOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expected      *ReplaceOrderResult
+		errorContains string
+	}{
+		{
+			name:   "new price keeps the unfilled volume",
+			params: map[string]any{"order_id": "BXOLD", "price": "1100000", "client_order_id": "reprice-1"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+				m.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "BXOLD"}).Return(&luno.StopOrderResponse{Success: true}, nil)
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(testOpenOrder(0.1), nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.MatchedBy(func(req *luno.PostLimitOrderRequest) bool {
+					return req.Pair == "XBTZAR" && req.Type == luno.OrderTypeAsk && req.ClientOrderId == "reprice-1" &&
+						req.Volume.String() == "0.400000" && req.Price.String() == "1100000"
+				})).Return(&luno.PostLimitOrderResponse{OrderId: "BXNEW"}, nil)
+			},
+			expected: &ReplaceOrderResult{
+				Outcome: ReplaceOutcomeReplaced, OldOrderID: "BXOLD", Cancelled: true, FilledBeforeCancel: decimal.NewFromFloat64(0.1, 6),
+				NewOrderID: "BXNEW", ClientOrderID: "reprice-1", Pair: "XBTZAR", Side: OrderSideSell,
+				Volume: decimal.NewFromFloat64(0.4, 6), Price: decimal.NewFromInt64(1100000),
+			},
+		},
+		{
+			name:   "fills before the cancel shrink the new order",
+			params: map[string]any{"order_id": "BXOLD", "price": "1100000"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+				m.EXPECT().StopOrder(mock.Anything, mock.Anything).Return(&luno.StopOrderResponse{Success: true}, nil)
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(testOpenOrder(0.3), nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.MatchedBy(func(req *luno.PostLimitOrderRequest) bool {
+					return req.Volume.String() == "0.200000"
+				})).Return(&luno.PostLimitOrderResponse{OrderId: "BXNEW"}, nil)
+			},
+			expected: &ReplaceOrderResult{
+				Outcome: ReplaceOutcomeReplaced, OldOrderID: "BXOLD", Cancelled: true, FilledBeforeCancel: decimal.NewFromFloat64(0.3, 6),
+				NewOrderID: "BXNEW", Pair: "XBTZAR", Side: OrderSideSell,
+				Volume: decimal.NewFromFloat64(0.2, 6), Price: decimal.NewFromInt64(1100000),
+			},
+		},
+		{
+			name:   "post_only and time_in_force are kept",
+			params: map[string]any{"order_id": "BXOLD", "price": "1100000", "post_only": true, "time_in_force": "GTC"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+				m.EXPECT().StopOrder(mock.Anything, mock.Anything).Return(&luno.StopOrderResponse{Success: true}, nil)
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(testOpenOrder(0.1), nil).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.MatchedBy(func(req *luno.PostLimitOrderRequest) bool {
+					return req.PostOnly && req.TimeInForce == luno.TimeInForceGtc
+				})).Return(&luno.PostLimitOrderResponse{OrderId: "BXNEW"}, nil)
+			},
+			expected: &ReplaceOrderResult{
+				Outcome: ReplaceOutcomeReplaced, OldOrderID: "BXOLD", Cancelled: true, FilledBeforeCancel: decimal.NewFromFloat64(0.1, 6),
+				NewOrderID: "BXNEW", Pair: "XBTZAR", Side: OrderSideSell,
+				Volume: decimal.NewFromFloat64(0.4, 6), Price: decimal.NewFromInt64(1100000),
+				PostOnly: true, TimeInForce: luno.TimeInForceGtc,
+			},
+		},
+		{
+			name:          "post-only IOC",
+			params:        map[string]any{"order_id": "BXOLD", "price": "1100000", "post_only": true, "time_in_force": "IOC"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "a post-only order can't be IOC",
+		},
+		{
+			name:   "new order fails after the cancel",
+			params: map[string]any{"order_id": "BXOLD", "price": "1100000"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+				m.EXPECT().StopOrder(mock.Anything, mock.Anything).Return(&luno.StopOrderResponse{Success: true}, nil)
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(nil, errors.New("timeout")).Once()
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New("market closed"))
+			},
+			errorContains: "Order BXOLD was cancelled but the new order was NOT placed",
+		},
+		{
+			name:   "cancel fails",
+			params: map[string]any{"order_id": "BXOLD", "volume": "0.3"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+				m.EXPECT().StopOrder(mock.Anything, mock.Anything).Return(nil, errors.New("order not found"))
+			},
+			errorContains: "The order was not cancelled and no new order was placed",
+		},
+		{
+			name:   "more than the cancelled order frees up",
+			params: map[string]any{"order_id": "BXOLD", "volume": "0.5"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+			},
+			errorContains: "insufficient XBT balance",
+		},
+		{
+			name:   "closed order",
+			params: map[string]any{"order_id": "BXOLD", "price": "1100000"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				order := testOpenOrder(0.5)
+				order.Status = luno.StatusComplete
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(order, nil)
+			},
+			errorContains: "order BXOLD is COMPLETE and no longer open",
+		},
+		{
+			name:   "price in the wrong currency",
+			params: map[string]any{"order_id": "BXOLD", "price": "€1100000"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(testOpenOrder(0.1), nil)
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			errorContains: "price is given in EUR but must be in ZAR for XBTZAR",
+		},
+		{
+			name:          "nothing to change",
+			params:        map[string]any{"order_id": "BXOLD"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "give a new price, volume or both",
+		},
+		{
+			name:   "dry run",
+			params: map[string]any{"order_id": "BXOLD", "price": "1100000", "dry_run": true},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectReplaceChecks(m)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleReplaceOrder(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError, text)
+			if tc.expected == nil {
+				assert.Contains(t, text, `"dry_run": true`)
+				return
+			}
+
+			_, body, ok := strings.Cut(text, "\n\n")
+			require.True(t, ok, text)
+			var got ReplaceOrderResult
+			require.NoError(t, json.Unmarshal([]byte(body), &got))
+			assertDecimal(t, tc.expected.FilledBeforeCancel.String(), got.FilledBeforeCancel)
+			assertDecimal(t, tc.expected.Volume.String(), got.Volume)
+			assertDecimal(t, tc.expected.Price.String(), got.Price)
+			got.FilledBeforeCancel, got.Volume, got.Price = tc.expected.FilledBeforeCancel, tc.expected.Volume, tc.expected.Price
+			if tc.expected.ClientOrderID == "" {
+				assert.True(t, strings.HasPrefix(got.ClientOrderID, "mcp-"))
+				got.ClientOrderID = ""
+			}
+			assert.Equal(t, *tc.expected, got)
+		})
+	}
+}
+
+func TestHandleReplaceOrderAfterCancel(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	expectReplaceChecks(mockClient)
+
+	// The call is cancelled once the old order is, which must not stop the
+	// new order being placed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockClient.EXPECT().StopOrder(mock.Anything, mock.Anything).RunAndReturn(
+		func(context.Context, *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+			cancel()
+			return &luno.StopOrderResponse{Success: true}, nil
+		})
+	mockClient.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(testOpenOrder(0.1), nil).Once()
+	mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, _ *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &luno.PostLimitOrderResponse{OrderId: "BXNEW"}, nil
+		})
+
+	params := map[string]any{"order_id": "BXOLD", "price": "1100000"}
+	result, err := HandleReplaceOrder(&config.Config{LunoClient: mockClient})(ctx, createMockRequest(params))
+	require.NoError(t, err)
+	text := getTextContentFromResult(t, result)
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, "Order BXOLD was replaced by order BXNEW")
+}
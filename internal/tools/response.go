@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResponseFormat controls how a tool result is rendered
+type ResponseFormat string
+
+const (
+	// FormatJSON returns the full result as indented JSON text
+	FormatJSON ResponseFormat = "json"
+	// FormatSummary returns a short text summary with the full result embedded as a JSON resource
+	FormatSummary ResponseFormat = "summary"
+	// FormatTable returns a summary and a markdown table with the full result embedded as a JSON resource
+	FormatTable ResponseFormat = "table"
+	// FormatMarkdown returns a summary and a markdown table without the JSON result
+	FormatMarkdown ResponseFormat = "markdown"
+	// FormatCSV returns the table as CSV text, for pasting into a spreadsheet
+	FormatCSV ResponseFormat = "csv"
+)
+
+// responseFormats lists the accepted values of the format parameter
+var responseFormats = []string{
+	string(FormatJSON), string(FormatSummary), string(FormatTable), string(FormatMarkdown), string(FormatCSV),
+}
+
+// withFormat adds the optional format parameter to a tool
+func withFormat() mcp.ToolOption {
+	return mcp.WithString(
+		"format",
+		mcp.Enum(responseFormats...),
+		mcp.Description("Output format: json (default) for the full result, summary for a short description, "+
+			"table for a readable table, markdown for the table alone, or csv for a spreadsheet. "+
+			"summary and table also embed the full JSON result."),
+	)
+}
+
+// parseFormat reads the format parameter, defaulting to FormatJSON
+func parseFormat(request mcp.CallToolRequest) (ResponseFormat, error) {
+	format := ResponseFormat(strings.ToLower(strings.TrimSpace(request.GetString("format", string(FormatJSON)))))
+	switch format {
+	case FormatJSON, FormatSummary, FormatTable, FormatMarkdown, FormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("format must be one of %s", strings.Join(responseFormats, ", "))
+	}
+}
+
+// Table is a simple table rendered as markdown
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// Markdown renders the table as a markdown table
+func (t Table) Markdown() string {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, c := range cells {
+			b.WriteString(" ")
+			b.WriteString(strings.ReplaceAll(c, "|", "\\|"))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(t.Headers)
+	sep := make([]string, len(t.Headers))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	writeRow(sep)
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+// CSV renders the table as CSV with a header row
+func (t Table) CSV() (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(t.Headers); err != nil {
+		return "", err
+	}
+	if err := w.WriteAll(t.Rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Response is a tool result that can be rendered in any ResponseFormat
+type Response struct {
+	// URI identifies the embedded JSON resource, e.g. luno://results/get_balances
+	URI string
+	// Summary is a short human readable description of the result
+	Summary string
+	// Data is the full result, marshalled to JSON
+	Data any
+	// Table is an optional tabular view of the result
+	Table *Table
+	// Page describes the rows returned when Data and Table hold only part
+	// of a list, nil when they hold all of it
+	Page *Page
+}
+
+// Result renders the response in the requested format. A truncated result
+// also says which rows it holds and sets the page in its _meta.
+func (r Response) Result(format ResponseFormat) (*mcp.CallToolResult, error) {
+	summary := r.Summary
+	if r.Page != nil {
+		summary += ". " + r.Page.Note()
+	}
+	result, err := r.render(format, summary)
+	if err != nil || r.Page == nil {
+		return result, err
+	}
+	if format == FormatJSON || format == FormatCSV {
+		// Keep the note out of the JSON or CSV text so that it still parses
+		result.Content = append(result.Content, mcp.NewTextContent(r.Page.Note()))
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[PageMetaKey] = r.Page
+	return result, nil
+}
+
+// render renders the response in format with summary as its summary
+func (r Response) render(format ResponseFormat, summary string) (*mcp.CallToolResult, error) {
+	dataJSON, err := json.MarshalIndent(r.Data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		return mcp.NewToolResultText(string(dataJSON)), nil
+	case FormatMarkdown, FormatCSV:
+		if r.Table == nil {
+			return nil, fmt.Errorf("%s format is not available for this result", format)
+		}
+		if format == FormatMarkdown {
+			return mcp.NewToolResultText(summary + "\n\n" + r.Table.Markdown()), nil
+		}
+		text, err := r.Table.CSV()
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(text), nil
+	}
+
+	text := summary
+	if format == FormatTable && r.Table != nil {
+		text += "\n\n" + r.Table.Markdown()
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(text),
+			mcp.NewEmbeddedResource(mcp.TextResourceContents{
+				URI:      r.URI,
+				MIMEType: "application/json",
+				Text:     string(dataJSON),
+			}),
+		},
+	}, nil
+}
+
+// resultURI returns the URI of the embedded JSON resource for a tool
+func resultURI(toolID string) string {
+	return "luno://results/" + toolID
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		expected      ResponseFormat
+		expectedError bool
+	}{
+		{name: "defaults to json", params: nil, expected: FormatJSON},
+		{name: "summary", params: map[string]any{"format": "summary"}, expected: FormatSummary},
+		{name: "table is case insensitive", params: map[string]any{"format": "TABLE"}, expected: FormatTable},
+		{name: "markdown", params: map[string]any{"format": "markdown"}, expected: FormatMarkdown},
+		{name: "csv", params: map[string]any{"format": "csv"}, expected: FormatCSV},
+		{name: "unknown format", params: map[string]any{"format": "xml"}, expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := parseFormat(createMockRequest(tc.params))
+			if tc.expectedError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "format must be one of json, summary, table, markdown, csv")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+}
+
+func TestTableMarkdown(t *testing.T) {
+	table := Table{
+		Headers: []string{"Asset", "Name"},
+		Rows:    [][]string{{"XBT", "Main|Savings"}},
+	}
+	expected := "| Asset | Name |\n| --- | --- |\n| XBT | Main\\|Savings |\n"
+	assert.Equal(t, expected, table.Markdown())
+}
+
+func TestTableCSV(t *testing.T) {
+	table := Table{
+		Headers: []string{"Asset", "Name"},
+		Rows:    [][]string{{"XBT", "Main, \"Savings\""}},
+	}
+	text, err := table.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, "Asset,Name\nXBT,\"Main, \"\"Savings\"\"\"\n", text)
+}
+
+func TestResponseResult(t *testing.T) {
+	response := Response{
+		URI:     resultURI("test_tool"),
+		Summary: "1 item",
+		Data:    map[string]string{"asset": "XBT"},
+		Table:   &Table{Headers: []string{"Asset"}, Rows: [][]string{{"XBT"}}},
+	}
+
+	tests := []struct {
+		name          string
+		format        ResponseFormat
+		expectedText  string
+		expectedEmbed bool
+	}{
+		{name: "json", format: FormatJSON, expectedText: "{\n  \"asset\": \"XBT\"\n}"},
+		{name: "summary", format: FormatSummary, expectedText: "1 item", expectedEmbed: true},
+		{name: "table", format: FormatTable, expectedText: "1 item\n\n| Asset |\n| --- |\n| XBT |\n", expectedEmbed: true},
+		{name: "markdown", format: FormatMarkdown, expectedText: "1 item\n\n| Asset |\n| --- |\n| XBT |\n"},
+		{name: "csv", format: FormatCSV, expectedText: "Asset\nXBT\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := response.Result(tc.format)
+			require.NoError(t, err)
+			require.NotEmpty(t, result.Content)
+			text, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedText, text.Text)
+
+			if !tc.expectedEmbed {
+				require.Len(t, result.Content, 1)
+				return
+			}
+			require.Len(t, result.Content, 2)
+			embedded, ok := result.Content[1].(mcp.EmbeddedResource)
+			require.True(t, ok)
+			contents, ok := embedded.Resource.(mcp.TextResourceContents)
+			require.True(t, ok)
+			assert.Equal(t, "luno://results/test_tool", contents.URI)
+			assert.Equal(t, "application/json", contents.MIMEType)
+			assert.JSONEq(t, `{"asset": "XBT"}`, contents.Text)
+		})
+	}
+}
+
+func TestHandleGetBalancesTableFormat(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{
+		Balance: []luno.AccountBalance{
+			{AccountId: "123", Asset: "XBT", Balance: decimal.NewFromInt64(1), Reserved: decimal.Zero(), Unconfirmed: decimal.Zero(), Name: "Main"},
+		},
+	}, nil)
+
+	result, err := HandleGetBalances(&config.Config{LunoClient: mockClient})(context.Background(),
+		createMockRequest(map[string]any{"format": "table"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	require.Len(t, result.Content, 2)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "1 accounts")
+	assert.Contains(t, text.Text, "| 123 | XBT | 1 | 0 | 0 | Main |")
+}
+
+func TestResponseResultWithoutTable(t *testing.T) {
+	response := Response{URI: resultURI("test_tool"), Summary: "1 item", Data: map[string]string{"asset": "XBT"}}
+	_, err := response.Result(FormatCSV)
+	assert.ErrorContains(t, err, "csv format is not available")
+}
+
+func TestHandleListUserTradesCSVFormat(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(&luno.ListUserTradesResponse{
+		Trades: []luno.TradeV2{
+			{
+				OrderId: "BXMC2CJ7HNB88U4", Pair: "XBTZAR", Type: luno.OrderTypeBid, IsBuy: false, Timestamp: luno.Time(time.UnixMilli(1700000000000)),
+				Price: decimal.NewFromInt64(1000000), Volume: decimal.NewFromFloat64(0.01, 2), Counter: decimal.NewFromInt64(10000),
+				FeeBase: decimal.Zero(), FeeCounter: decimal.NewFromInt64(10),
+			},
+		},
+	}, nil)
+
+	result, err := HandleListUserTrades(&config.Config{LunoClient: mockClient})(context.Background(),
+		createMockRequest(map[string]any{"pair": "XBTZAR", "format": "csv"}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	require.Len(t, result.Content, 1)
+	expected := "Time,Order,Side,Price,Volume,Value,Fee (base),Fee (counter)\n" +
+		"2023-11-14T22:13:20Z,BXMC2CJ7HNB88U4,BUY,1000000,0.01,10000,0,10\n"
+	assert.Equal(t, expected, getTextContentFromResult(t, result))
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/schedule"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RestoreToolID is the ID of the tool that restores deleted alerts and schedules
+const RestoreToolID = "restore"
+
+// Kinds of things the restore tool brings back
+const (
+	restoreKindPriceAlert = "price_alert"
+	restoreKindSchedule   = "schedule"
+)
+
+// NewRestoreTool creates a new tool for restoring deleted price alerts and
+// cancelled schedules
+func NewRestoreTool() mcp.Tool {
+	return mcp.NewTool(
+		RestoreToolID,
+		mcp.WithDescription(fmt.Sprintf("Restore a price alert deleted with delete_price_alert or a schedule cancelled with cancel_schedule. "+
+			"They can be restored for %.0f hours, after which they are gone for good. "+
+			"Without an id, lists what can be restored and when each expires.", alerts.Retention.Hours())),
+		mcp.WithString(
+			"kind",
+			mcp.Required(),
+			mcp.Description("What to restore"),
+			mcp.Enum(restoreKindPriceAlert, restoreKindSchedule),
+		),
+		mcp.WithString(
+			"id",
+			mcp.Description("ID of the deleted alert or cancelled schedule. Omit to list them."),
+		),
+	)
+}
+
+// HandleRestore handles the restore tool
+func HandleRestore(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		kind, err := request.RequireString("kind")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting kind from request", err), nil
+		}
+		id := request.GetString("id", "")
+
+		switch kind {
+		case restoreKindPriceAlert:
+			if cfg.Alerts == nil {
+				return mcp.NewToolResultError("Price alerts are not enabled"), nil
+			}
+			if id == "" {
+				deleted := cfg.Alerts.Deleted(sessionID(ctx))
+				if deleted == nil {
+					deleted = []alerts.Deleted{}
+				}
+				return restoreListResult(map[string]any{"deleted_alerts": deleted})
+			}
+			a, err := cfg.Alerts.Restore(sessionID(ctx), id)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to restore price alert: %v", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Price alert %s restored: %s %s %s", a.ID, a.Pair, a.Condition, a.Price)), nil
+
+		case restoreKindSchedule:
+			if cfg.Schedules == nil {
+				return mcp.NewToolResultError(errSchedulesDisabled), nil
+			}
+			if id == "" {
+				cancelled := cfg.Schedules.Cancelled()
+				if cancelled == nil {
+					cancelled = []schedule.Cancelled{}
+				}
+				return restoreListResult(map[string]any{"cancelled_schedules": cancelled})
+			}
+			// A restored schedule places orders again, so it needs the same
+			// permission as creating one
+			if !cfg.Allows(config.PermissionTrade) {
+				return mcp.NewToolResultError(fmt.Sprintf("Restoring a schedule requires the %q permission, add it to %s to enable",
+					config.PermissionTrade, config.EnvLunoPermissions)), nil
+			}
+			if reason := cfg.WritesRefusedReason(config.PermissionTrade); reason != "" {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to restore schedule: %s", reason)), nil
+			}
+			sch, err := cfg.Schedules.Restore(id)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to restore schedule: %v", err)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Schedule %s restored, the next run is at %s", sch.ID, sch.NextRun.Format(time.RFC3339))), nil
+
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("Unknown kind %q, must be %q or %q", kind, restoreKindPriceAlert, restoreKindSchedule)), nil
+		}
+	}
+}
+
+func restoreListResult(v map[string]any) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal restorable items: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
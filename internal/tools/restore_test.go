@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/alerts"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRestore(t *testing.T) {
+	cfg := &config.Config{
+		Alerts:    alerts.NewRegistry(alerts.DefaultMaxPerSession),
+		Schedules: testScheduler(t),
+	}
+	ctx := context.Background()
+	restore := func(params map[string]any) (string, bool) {
+		result, err := HandleRestore(cfg)(ctx, createMockRequest(params))
+		require.NoError(t, err)
+		return getTextContentFromResult(t, result), result.IsError
+	}
+
+	alert, err := cfg.Alerts.Add("", "XBTZAR", alerts.ConditionBelow, NewFromString(t, "900000"))
+	require.NoError(t, err)
+	result, err := HandleDeletePriceAlert(cfg)(ctx, createMockRequest(map[string]any{"id": alert.ID}))
+	require.NoError(t, err)
+	assert.Contains(t, getTextContentFromResult(t, result), "Restore it with the restore tool within 24 hours")
+
+	sch, err := cfg.Schedules.Add("XBTZAR", decimal.NewFromInt64(1000), schedule.FrequencyDaily, decimal.Zero(), time.Time{})
+	require.NoError(t, err)
+	_, err = cfg.Schedules.Cancel(sch.ID)
+	require.NoError(t, err)
+
+	// Without an ID the deleted alerts and cancelled schedules are listed
+	text, isErr := restore(map[string]any{"kind": restoreKindPriceAlert})
+	require.False(t, isErr, text)
+	var deleted struct {
+		Alerts []alerts.Deleted `json:"deleted_alerts"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &deleted))
+	require.Len(t, deleted.Alerts, 1)
+	assert.Equal(t, alert.ID, deleted.Alerts[0].ID)
+
+	text, isErr = restore(map[string]any{"kind": restoreKindSchedule})
+	require.False(t, isErr, text)
+	var cancelled struct {
+		Schedules []schedule.Cancelled `json:"cancelled_schedules"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &cancelled))
+	require.Len(t, cancelled.Schedules, 1)
+
+	text, isErr = restore(map[string]any{"kind": restoreKindPriceAlert, "id": alert.ID})
+	require.False(t, isErr, text)
+	assert.Contains(t, text, "Price alert 1 restored")
+	assert.Len(t, cfg.Alerts.List(""), 1)
+
+	// Restoring a schedule needs the trade permission, as it places orders again
+	cfg.Permissions = []config.Permission{config.PermissionRead}
+	text, isErr = restore(map[string]any{"kind": restoreKindSchedule, "id": sch.ID})
+	assert.True(t, isErr)
+	assert.Contains(t, text, `requires the "trade" permission`)
+
+	cfg.Permissions = nil
+	text, isErr = restore(map[string]any{"kind": restoreKindSchedule, "id": sch.ID})
+	require.False(t, isErr, text)
+	assert.Contains(t, text, "Schedule 1 restored")
+	assert.Len(t, cfg.Schedules.List(), 1)
+
+	text, isErr = restore(map[string]any{"kind": restoreKindSchedule, "id": sch.ID})
+	assert.True(t, isErr)
+	assert.Contains(t, text, "no cancelled schedule with ID 1")
+
+	text, isErr = restore(map[string]any{"kind": "note"})
+	assert.True(t, isErr)
+	assert.Contains(t, text, `Unknown kind "note"`)
+}
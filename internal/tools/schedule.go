@@ -0,0 +1,356 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/audit"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/luno/luno-mcp/internal/schedule"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Scheduled order tool IDs
+const (
+	ScheduleRecurringBuyToolID = "schedule_recurring_buy"
+	ListSchedulesToolID        = "list_schedules"
+	CancelScheduleToolID       = "cancel_schedule"
+)
+
+const (
+	// defaultMaxSlippagePercent is how far above the best ask a scheduled
+	// buy is priced when no slippage is given
+	defaultMaxSlippagePercent = 1
+
+	// maxSlippagePercentLimit is the most slippage a schedule can allow
+	maxSlippagePercentLimit = 10
+)
+
+// errSchedulesDisabled is returned by the schedule tools when no state file
+// is configured
+const errSchedulesDisabled = "Scheduled orders are not enabled. Set " + config.EnvLunoStatePath + " to a file to keep schedules in."
+
+// errScheduleOwnCredentials is returned when a session that uses its own Luno
+// credentials tries to schedule a buy. Schedules outlive the session and run
+// with the server's credentials, so they would buy from the wrong account.
+const errScheduleOwnCredentials = "Scheduled buys are placed with the server's Luno account, so they can't be set up by a client that sends its own API credentials."
+
+// NewScheduleRecurringBuyTool creates a new tool for scheduling recurring buys
+func NewScheduleRecurringBuyTool() mcp.Tool {
+	return mcp.NewTool(
+		ScheduleRecurringBuyToolID,
+		mcp.WithDescription("Schedule a recurring buy of a fixed value on a pair, such as R500 of XBT every week. "+
+			"Each run places an immediate-or-cancel limit order priced at most max_slippage_percent above the best ask, "+
+			"so a run may buy less than the full value in a thin market. Schedules are kept by the server across restarts "+
+			"and run whether or not a client is connected. A run missed while the server was down is made once when it restarts."),
+		mcp.WithString(
+			"pair",
+			mcp.Required(),
+			mcp.Description("Trading pair to buy on (e.g., XBTZAR)"),
+		),
+		mcp.WithString(
+			"amount",
+			mcp.Required(),
+			mcp.Description("Value to spend each run, in the pair's counter currency. "+amountDesc),
+		),
+		mcp.WithString(
+			"frequency",
+			mcp.Required(),
+			mcp.Description("How often to buy"),
+			mcp.Enum(string(schedule.FrequencyDaily), string(schedule.FrequencyWeekly), string(schedule.FrequencyMonthly)),
+		),
+		mcp.WithString(
+			"max_slippage_percent",
+			mcp.Description(fmt.Sprintf("How far above the best ask each order may be priced, as a percentage. Defaults to %d, at most %d.",
+				defaultMaxSlippagePercent, maxSlippagePercentLimit)),
+		),
+		mcp.WithString(
+			"start",
+			mcp.Description("Time of the first run in RFC3339 format (e.g., 2025-01-06T08:00:00Z). Later runs are at the same time of day. "+
+				"Defaults to now."),
+		),
+		withConfirmToken(),
+		withDryRun(),
+	)
+}
+
+// HandleScheduleRecurringBuy handles the schedule_recurring_buy tool
+func HandleScheduleRecurringBuy(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Schedules == nil {
+			return mcp.NewToolResultError(errSchedulesDisabled), nil
+		}
+		if cfg.Sessions != nil && cfg.Sessions.Client(sessionID(ctx)) != nil {
+			return mcp.NewToolResultError(errScheduleOwnCredentials), nil
+		}
+
+		pair, err := request.RequireString("pair")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pair from request", err), nil
+		}
+		pair = market.NormalizePair(pair)
+
+		amountStr, err := request.RequireString("amount")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting amount from request", err), nil
+		}
+		amount, amountCurrency, err := parseAmount(amountStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid amount format: %v", err)), nil
+		}
+
+		frequencyStr, err := request.RequireString("frequency")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting frequency from request", err), nil
+		}
+		frequency, err := schedule.ParseFrequency(frequencyStr)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid frequency", err), nil
+		}
+
+		slippage := decimal.NewFromInt64(defaultMaxSlippagePercent)
+		if s := strings.TrimSpace(strings.TrimSuffix(request.GetString("max_slippage_percent", ""), "%")); s != "" {
+			if slippage, err = decimal.NewFromString(s); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid max_slippage_percent format: %v", err)), nil
+			}
+			if slippage.Sign() < 0 || slippage.Cmp(decimal.NewFromInt64(maxSlippagePercentLimit)) > 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("max_slippage_percent must be between 0 and %d, got %s",
+					maxSlippagePercentLimit, slippage.String())), nil
+			}
+		}
+
+		var start time.Time
+		if s := strings.TrimSpace(request.GetString("start", "")); s != "" {
+			if start, err = time.Parse(time.RFC3339, s); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid start, use RFC3339 format: %v", err)), nil
+			}
+		}
+
+		if err := checkPairAllowed(cfg, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to schedule buy: %v", err)), nil
+		}
+		info, err := ValidatePair(ctx, cfg, pair)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to schedule buy: %v", err)), nil
+		}
+		if err := checkAmountCurrency("amount", amountCurrency, info.CounterCurrency, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to schedule buy: %v", err)), nil
+		}
+
+		// Catch an amount too small to ever place an order now, rather than
+		// on every run
+		price, volume, err := scheduledBuyOrder(ctx, cfg, info, amount, slippage)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to schedule buy: %v", err)), nil
+		}
+
+		preview := map[string]string{
+			"pair":                 pair,
+			"amount":               amount.String() + " " + info.CounterCurrency,
+			"frequency":            string(frequency),
+			"max_slippage_percent": slippage.String(),
+			"order_now":            fmt.Sprintf("buy %s at up to %s", volume.String(), price.String()),
+		}
+		if !start.IsZero() {
+			preview["start"] = start.UTC().Format(time.RFC3339)
+		}
+		if isDryRun(cfg, request) {
+			return dryRunResult(ScheduleRecurringBuyToolID, preview), nil
+		}
+		binding := strings.Join([]string{pair, amount.String(), string(frequency), slippage.String(), preview["start"]}, "\n")
+		if res := requireConfirmation(cfg, request, ScheduleRecurringBuyToolID, binding, preview); res != nil {
+			return res, nil
+		}
+
+		sch, err := cfg.Schedules.Add(pair, amount, frequency, slippage, start)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to schedule buy: %v", err)), nil
+		}
+		resultJSON, err := json.MarshalIndent(sch, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal schedule: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// NewListSchedulesTool creates a new tool for listing recurring buys
+func NewListSchedulesTool() mcp.Tool {
+	return mcp.NewTool(
+		ListSchedulesToolID,
+		mcp.WithDescription("List the recurring buys, with when each next runs and the outcome of its last run"),
+	)
+}
+
+// HandleListSchedules handles the list_schedules tool
+func HandleListSchedules(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Schedules == nil {
+			return mcp.NewToolResultError(errSchedulesDisabled), nil
+		}
+
+		list := cfg.Schedules.List()
+		if list == nil {
+			list = []schedule.Schedule{}
+		}
+		resultJSON, err := json.MarshalIndent(map[string]any{"schedules": list}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal schedules: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
+
+// NewCancelScheduleTool creates a new tool for cancelling recurring buys
+func NewCancelScheduleTool() mcp.Tool {
+	return mcp.NewTool(
+		CancelScheduleToolID,
+		mcp.WithDescription(fmt.Sprintf("Cancel a recurring buy so it places no more orders. Orders it already placed are not affected. "+
+			"It can be brought back with restore for %.0f hours.", schedule.Retention.Hours())),
+		mcp.WithString(
+			"id",
+			mcp.Required(),
+			mcp.Description("Schedule ID from schedule_recurring_buy or list_schedules"),
+		),
+	)
+}
+
+// HandleCancelSchedule handles the cancel_schedule tool
+func HandleCancelSchedule(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Schedules == nil {
+			return mcp.NewToolResultError(errSchedulesDisabled), nil
+		}
+
+		id, err := request.RequireString("id")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting id from request", err), nil
+		}
+		found, err := cfg.Schedules.Cancel(id)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to cancel schedule: %v", err)), nil
+		}
+		if !found {
+			return mcp.NewToolResultError(fmt.Sprintf("No schedule with ID %s", id)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Schedule %s cancelled. Restore it with the restore tool within %.0f hours if this was a mistake.", id, schedule.Retention.Hours())), nil
+	}
+}
+
+// PlaceScheduledBuy returns the function the scheduler uses to place the
+// order for each run. It makes the same checks as create_order, and places
+// nothing in dry-run mode.
+func PlaceScheduledBuy(cfg *config.Config) schedule.PlaceFunc {
+	return func(ctx context.Context, sch schedule.Schedule) (string, error) {
+		// Schedules saved before writes were refused must not place orders either
+		if reason := cfg.WritesRefusedReason(config.PermissionTrade); reason != "" {
+			return "", errors.New(reason)
+		}
+		if err := checkPairAllowed(cfg, sch.Pair); err != nil {
+			return "", err
+		}
+		info, err := ValidatePair(ctx, cfg, sch.Pair)
+		if err != nil {
+			return "", err
+		}
+		price, volume, err := scheduledBuyOrder(ctx, cfg, info, sch.Amount, sch.MaxSlippagePercent)
+		if err != nil {
+			return "", err
+		}
+		if err := checkOrderBalance(ctx, cfg, info, OrderSideBuy, volume, price, decimal.Zero()); err != nil {
+			return "", err
+		}
+		release, err := reserveOrderValue(ctx, cfg, info, volume, price)
+		if err != nil {
+			return "", err
+		}
+		if cfg.DryRun {
+			release()
+			return "", errors.New("the server is in dry-run mode, so no order was placed")
+		}
+
+		// The ID is the same for every attempt at a run, so Luno rejects a
+		// second order for it
+		clientOrderID := fmt.Sprintf("mcp-schedule-%s-%d", sch.ID, sch.NextRun.Unix())
+		slog.Info("Placing scheduled buy",
+			"schedule_id", sch.ID,
+			"pair", sch.Pair,
+			"volume", volume.String(),
+			"price", price.String())
+		order, err := cfg.LunoClient.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+			Pair:          sch.Pair,
+			Type:          OrderSideBuy.LunoOrderType(),
+			Volume:        volume,
+			Price:         price,
+			TimeInForce:   luno.TimeInForceIoc,
+			ClientOrderId: clientOrderID,
+		})
+		recordScheduledBuy(cfg, sch, volume, price, clientOrderID, order, err)
+		if err != nil {
+			release()
+			return "", err
+		}
+		return order.OrderId, nil
+	}
+}
+
+// scheduledBuyOrder returns the price and volume of a buy of amount, priced
+// up to slippagePercent above the best ask
+func scheduledBuyOrder(ctx context.Context, cfg *config.Config, info *luno.MarketInfo, amount, slippagePercent decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{Pair: info.MarketId})
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("could not get the best ask: %w", err)
+	}
+	if ticker.Ask.Sign() <= 0 {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("%s has no asks to buy from", info.MarketId)
+	}
+
+	// Rounding down keeps the price within the slippage
+	hundred := decimal.NewFromInt64(100)
+	price := ticker.Ask.Mul(hundred.Add(slippagePercent)).Div(hundred, int(info.PriceScale))
+	volume, err := volumeForValue(info, amount, price)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	if err := ValidateOrderSize(info, volume, price); err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("%s %s at %s is a volume of %s: %w",
+			amount.String(), info.CounterCurrency, price.String(), volume.String(), err)
+	}
+	return price, volume, nil
+}
+
+// recordScheduledBuy adds a scheduled order to the audit log, as there is
+// no tool call for the audit middleware to record
+func recordScheduledBuy(cfg *config.Config, sch schedule.Schedule, volume, price decimal.Decimal, clientOrderID string, order *luno.PostLimitOrderResponse, orderErr error) {
+	if cfg.Audit == nil {
+		return
+	}
+	entry := audit.Entry{
+		Tool: ScheduleRecurringBuyToolID,
+		Arguments: map[string]any{
+			"schedule_id":     sch.ID,
+			"pair":            sch.Pair,
+			"volume":          volume.String(),
+			"price":           price.String(),
+			"client_order_id": clientOrderID,
+		},
+	}
+	if orderErr != nil {
+		entry.IsError = true
+		entry.Result = orderErr.Error()
+	} else {
+		entry.Result = "Placed order " + order.OrderId
+	}
+	if err := cfg.Audit.Record(entry); err != nil {
+		slog.Error("Failed to write audit log entry", slog.String("tool", ScheduleRecurringBuyToolID), slog.Any("error", err))
+	}
+}
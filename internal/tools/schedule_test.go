@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/schedule"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/internal/state"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testScheduler(t *testing.T) *schedule.Scheduler {
+	store, err := state.Open(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+	s, err := schedule.Open(store, schedule.DefaultMaxSchedules)
+	require.NoError(t, err)
+	return s
+}
+
+// expectAsk sets up the XBTZAR market with a best ask of 1000000
+func expectAsk(m *sdk.MockLunoClient) {
+	m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	m.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).Return(&luno.GetTickerResponse{
+		Pair: "XBTZAR", Ask: decimal.NewFromInt64(1000000), Status: "ACTIVE",
+	}, nil)
+}
+
+func TestHandleScheduleRecurringBuy(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		disabled      bool
+		expected      string
+		errorContains string
+	}{
+		{
+			name:      "weekly buy",
+			params:    map[string]any{"pair": "XBTZAR", "amount": "R1000", "frequency": "weekly", "start": "2030-01-06T08:00:00Z"},
+			mockSetup: expectAsk,
+			expected:  `"next_run": "2030-01-06T08:00:00Z"`,
+		},
+		{
+			name:      "dry run",
+			params:    map[string]any{"pair": "XBTZAR", "amount": "1000", "frequency": "daily", "max_slippage_percent": "2%", "dry_run": true},
+			mockSetup: expectAsk,
+			expected:  `"order_now": "buy 0.000980 at up to 1020000"`,
+		},
+		{
+			name:          "too small to place",
+			params:        map[string]any{"pair": "XBTZAR", "amount": "500", "frequency": "daily"},
+			mockSetup:     expectAsk,
+			errorContains: "500 ZAR at 1010000 is a volume of 0.000495",
+		},
+		{
+			name:   "amount in the wrong currency",
+			params: map[string]any{"pair": "XBTZAR", "amount": "$1000", "frequency": "daily"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			errorContains: "amount is given in USD but must be in ZAR for XBTZAR",
+		},
+		{
+			name:          "too much slippage",
+			params:        map[string]any{"pair": "XBTZAR", "amount": "1000", "frequency": "daily", "max_slippage_percent": "15"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "max_slippage_percent must be between 0 and 10, got 15",
+		},
+		{
+			name:          "unknown frequency",
+			params:        map[string]any{"pair": "XBTZAR", "amount": "1000", "frequency": "hourly"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: `unknown frequency "hourly"`,
+		},
+		{
+			name:          "not enabled",
+			params:        map[string]any{"pair": "XBTZAR", "amount": "1000", "frequency": "daily"},
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			disabled:      true,
+			errorContains: "Scheduled orders are not enabled",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+			cfg := &config.Config{LunoClient: mockClient}
+			if !tc.disabled {
+				cfg.Schedules = testScheduler(t)
+			}
+
+			result, err := HandleScheduleRecurringBuy(cfg)(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.errorContains)
+				return
+			}
+			require.False(t, result.IsError, text)
+			assert.Contains(t, text, tc.expected)
+
+			created := cfg.Schedules.List()
+			if tc.params["dry_run"] == true {
+				assert.Empty(t, created)
+				return
+			}
+			require.Len(t, created, 1)
+			assert.Equal(t, schedule.FrequencyWeekly, created[0].Frequency)
+			assertDecimal(t, "1000", created[0].Amount)
+			assertDecimal(t, "1", created[0].MaxSlippagePercent)
+		})
+	}
+}
+
+// testSession is a client session with a fixed ID
+type testSession string
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return string(s) }
+
+func TestHandleScheduleRecurringBuyOwnCredentials(t *testing.T) {
+	cfg := &config.Config{LunoClient: sdk.NewMockLunoClient(t), Schedules: testScheduler(t), Sessions: session.NewManager(0)}
+	cfg.Sessions.Get("s1").SetClient(sdk.NewMockLunoClient(t))
+	ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), testSession("s1"))
+
+	result, err := HandleScheduleRecurringBuy(cfg)(ctx, createMockRequest(map[string]any{"pair": "XBTZAR", "amount": "1000", "frequency": "daily"}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getTextContentFromResult(t, result), "can't be set up by a client that sends its own API credentials")
+	assert.Empty(t, cfg.Schedules.List())
+}
+
+func TestHandleListAndCancelSchedules(t *testing.T) {
+	cfg := &config.Config{Schedules: testScheduler(t)}
+	sch, err := cfg.Schedules.Add("XBTZAR", decimal.NewFromInt64(1000), schedule.FrequencyDaily, decimal.Zero(), time.Time{})
+	require.NoError(t, err)
+
+	result, err := HandleListSchedules(cfg)(context.Background(), createMockRequest(nil))
+	require.NoError(t, err)
+	var listed struct {
+		Schedules []schedule.Schedule `json:"schedules"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &listed))
+	require.Len(t, listed.Schedules, 1)
+	assert.Equal(t, sch.ID, listed.Schedules[0].ID)
+
+	result, err = HandleCancelSchedule(cfg)(context.Background(), createMockRequest(map[string]any{"id": sch.ID}))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Empty(t, cfg.Schedules.List())
+
+	result, err = HandleCancelSchedule(cfg)(context.Background(), createMockRequest(map[string]any{"id": sch.ID}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, getTextContentFromResult(t, result), "No schedule with ID "+sch.ID)
+}
+
+func TestPlaceScheduledBuy(t *testing.T) {
+	sch := schedule.Schedule{
+		ID:                 "7",
+		Pair:               "XBTZAR",
+		Amount:             decimal.NewFromInt64(1000),
+		Frequency:          schedule.FrequencyDaily,
+		MaxSlippagePercent: decimal.NewFromInt64(1),
+		NextRun:            time.Unix(1736496000, 0),
+	}
+
+	tests := []struct {
+		name          string
+		dryRun        bool
+		writesRefused bool
+		mockSetup     func(*sdk.MockLunoClient)
+		expected      string
+		errorContains string
+	}{
+		{
+			name: "places an immediate or cancel order",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectAsk(m)
+				expectOrderBalance(m)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.MatchedBy(func(req *luno.PostLimitOrderRequest) bool {
+					return req.Pair == "XBTZAR" && req.Type == luno.OrderTypeBid && req.TimeInForce == luno.TimeInForceIoc &&
+						req.Volume.String() == "0.000990" && req.Price.String() == "1010000" &&
+						req.ClientOrderId == "mcp-schedule-7-1736496000"
+				})).Return(&luno.PostLimitOrderResponse{OrderId: "BX1"}, nil)
+			},
+			expected: "BX1",
+		},
+		{
+			name: "order fails",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectAsk(m)
+				expectOrderBalance(m)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New("market closed"))
+			},
+			errorContains: "market closed",
+		},
+		{
+			name: "no asks",
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				m.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+			},
+			errorContains: "XBTZAR has no asks to buy from",
+		},
+		{
+			name:   "dry-run mode",
+			dryRun: true,
+			mockSetup: func(m *sdk.MockLunoClient) {
+				expectAsk(m)
+				expectOrderBalance(m)
+			},
+			errorContains: "dry-run mode",
+		},
+		{
+			name:          "writes refused in production",
+			writesRefused: true,
+			mockSetup:     func(m *sdk.MockLunoClient) {},
+			errorContains: "writes are refused when LUNO_ENV is production",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			orderID, err := PlaceScheduledBuy(&config.Config{LunoClient: mockClient, DryRun: tc.dryRun, WritesRefused: tc.writesRefused})(context.Background(), sch)
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, orderID)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ServerInfoToolID is the ID of the server info tool
+const ServerInfoToolID = "server_info"
+
+// Write modes, describing what happens to orders and other writes
+const (
+	// WriteModeLive sends writes to Luno
+	WriteModeLive = "live"
+	// WriteModeDryRun only validates writes and reports what would be sent
+	WriteModeDryRun = "dry_run"
+	// WriteModePaper simulates writes against an in-memory portfolio
+	WriteModePaper = "paper"
+	// WriteModeDisabled means tools that change the account aren't registered
+	WriteModeDisabled = "disabled"
+)
+
+// ServerInfo describes a server instance and what it is allowed to do
+type ServerInfo struct {
+	Name        string              `json:"name"`
+	Version     string              `json:"version"`
+	Transport   string              `json:"transport"`
+	Backend     string              `json:"backend"`
+	Environment string              `json:"environment,omitempty"`
+	Domain      string              `json:"domain,omitempty"`
+	Permissions []config.Permission `json:"permissions"`
+	Writes      WriteStatus         `json:"writes"`
+	Tools       []string            `json:"tools"`
+	RateLimits  RateLimits          `json:"rate_limits"`
+}
+
+// WriteStatus is whether and how tools that change the account run
+type WriteStatus struct {
+	Mode                 string `json:"mode"`
+	ConfirmationRequired bool   `json:"confirmation_required"`
+	Reason               string `json:"reason,omitempty"`
+}
+
+// RateLimits are the limits on calls to this server and from it to Luno
+type RateLimits struct {
+	// SessionCallsPerMinute limits the tool calls of each session, 0 is unlimited
+	SessionCallsPerMinute int `json:"session_calls_per_minute"`
+	// LunoAPI are the client side limits on Luno API calls, which offline
+	// backends don't have
+	LunoAPI []sdk.RateLimit `json:"luno_api,omitempty"`
+}
+
+// NewServerInfo describes the server with the given name and version, whose
+// tools have the given statuses
+func NewServerInfo(cfg *config.Config, name, version string, statuses []ToolStatus) ServerInfo {
+	backend := cfg.Backend
+	if backend == "" {
+		backend = config.BackendLive
+	}
+	permissions := cfg.Permissions
+	if permissions == nil {
+		permissions = config.DefaultPermissions
+	}
+	info := ServerInfo{
+		Name:        name,
+		Version:     version,
+		Transport:   cfg.Transport,
+		Backend:     string(backend),
+		Environment: string(cfg.Environment),
+		Domain:      cfg.Domain,
+		Permissions: permissions,
+		Writes:      writeStatus(cfg),
+		Tools:       []string{},
+	}
+	for _, s := range statuses {
+		if s.Registered {
+			info.Tools = append(info.Tools, s.Name)
+		}
+	}
+	if cfg.Sessions != nil {
+		info.RateLimits.SessionCallsPerMinute = cfg.Sessions.CallsPerMinute()
+	}
+	if backend == config.BackendLive {
+		info.RateLimits.LunoAPI = sdk.DefaultRateLimits()
+	}
+	return info
+}
+
+// writeStatus reports whether trading tools are available, and what their
+// writes do when they are
+func writeStatus(cfg *config.Config) WriteStatus {
+	status := WriteStatus{ConfirmationRequired: cfg.Confirmations != nil}
+	switch {
+	case !cfg.Allows(config.PermissionTrade):
+		status.Mode = WriteModeDisabled
+		status.Reason = fmt.Sprintf("the %q permission is not granted, add it to %s to enable", config.PermissionTrade, config.EnvLunoPermissions)
+	case cfg.WritesRefused:
+		status.Mode = WriteModeDisabled
+		status.Reason = cfg.WritesRefusedReason(config.PermissionTrade)
+	case cfg.DryRun:
+		status.Mode = WriteModeDryRun
+	case cfg.PaperTrading:
+		status.Mode = WriteModePaper
+	default:
+		status.Mode = WriteModeLive
+	}
+	return status
+}
+
+// NewServerInfoTool creates a new tool for describing this server
+func NewServerInfoTool() mcp.Tool {
+	return mcp.NewTool(
+		ServerInfoToolID,
+		mcp.WithDescription("Describe this server: its version, transport, backend and Luno environment, "+
+			"whether orders are placed for real, simulated or disabled, the tools it offers and its rate limits. "+
+			"Check this to find out what the server is allowed to do before planning actions."),
+	)
+}
+
+// HandleServerInfo handles the server_info tool
+func HandleServerInfo(info ServerInfo) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resultJSON, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal server info: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
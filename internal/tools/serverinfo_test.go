@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/security"
+	"github.com/luno/luno-mcp/internal/session"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServerInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            *config.Config
+		expectedMode   string
+		expectedReason string
+		expectLunoAPI  bool
+	}{
+		{name: "live", cfg: &config.Config{}, expectedMode: WriteModeLive, expectLunoAPI: true},
+		{name: "dry run", cfg: &config.Config{DryRun: true}, expectedMode: WriteModeDryRun, expectLunoAPI: true},
+		{
+			name:         "fake backend",
+			cfg:          &config.Config{Backend: config.BackendFake, PaperTrading: true},
+			expectedMode: WriteModePaper,
+		},
+		{
+			name:           "read only",
+			cfg:            &config.Config{Permissions: []config.Permission{config.PermissionRead}},
+			expectedMode:   WriteModeDisabled,
+			expectedReason: config.EnvLunoPermissions,
+			expectLunoAPI:  true,
+		},
+		{
+			name:           "production without writes",
+			cfg:            &config.Config{WritesRefused: true, Environment: config.EnvironmentProduction},
+			expectedMode:   WriteModeDisabled,
+			expectedReason: config.EnvLunoAllowProductionWrites,
+			expectLunoAPI:  true,
+		},
+	}
+
+	statuses := []ToolStatus{
+		{Name: GetBalancesToolID, Registered: true},
+		{Name: CreateOrderToolID, Reason: "requires the trade permission"},
+		{Name: ServerInfoToolID, Registered: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			info := NewServerInfo(tc.cfg, "luno-mcp", "1.2.3", statuses)
+
+			assert.Equal(t, "1.2.3", info.Version)
+			assert.Equal(t, tc.expectedMode, info.Writes.Mode)
+			if tc.expectedReason == "" {
+				assert.Empty(t, info.Writes.Reason)
+			} else {
+				assert.Contains(t, info.Writes.Reason, tc.expectedReason)
+			}
+			assert.Equal(t, []string{GetBalancesToolID, ServerInfoToolID}, info.Tools)
+			if tc.expectLunoAPI {
+				assert.Equal(t, string(config.BackendLive), info.Backend)
+				assert.Equal(t, sdk.DefaultRateLimits(), info.RateLimits.LunoAPI)
+			} else {
+				assert.Empty(t, info.RateLimits.LunoAPI)
+			}
+		})
+	}
+}
+
+func TestHandleServerInfo(t *testing.T) {
+	cfg := &config.Config{
+		Transport:     "sse",
+		Environment:   config.EnvironmentStaging,
+		Domain:        config.StagingLunoDomain,
+		Sessions:      session.NewManager(60),
+		Confirmations: security.NewTokenStore(security.DefaultTokenTTL),
+	}
+	handler := HandleServerInfo(NewServerInfo(cfg, "luno-mcp", "1.2.3", []ToolStatus{{Name: ServerInfoToolID, Registered: true}}))
+
+	result, err := handler(context.Background(), createMockRequest(nil))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+	assert.Equal(t, "sse", got["transport"])
+	assert.Equal(t, "staging", got["environment"])
+	assert.Equal(t, []any{"read", "trade"}, got["permissions"])
+	assert.Equal(t, map[string]any{"mode": "live", "confirmation_required": true}, got["writes"])
+	limits := got["rate_limits"].(map[string]any)
+	assert.Equal(t, float64(60), limits["session_calls_per_minute"])
+	assert.Len(t, limits["luno_api"], 4)
+}
@@ -0,0 +1,378 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GenerateStatementToolID is the ID of the statement tool
+const GenerateStatementToolID = "generate_statement"
+
+const (
+	// statementPageSize is the most rows the API returns per request
+	statementPageSize = 1000
+
+	// maxStatementPages caps the requests made per account, so that a window
+	// far in the past of a busy account can't page through its whole history
+	maxStatementPages = 20
+)
+
+// Statement categories
+const (
+	CategoryTrade      = "trade"
+	CategoryFee        = "fee"
+	CategoryDeposit    = "deposit"
+	CategoryWithdrawal = "withdrawal"
+	CategoryInterest   = "interest"
+	CategoryOther      = "other"
+)
+
+// Statement covers the transactions of accounts over a time window
+type Statement struct {
+	Start    time.Time          `json:"start"`
+	End      time.Time          `json:"end"`
+	Accounts []AccountStatement `json:"accounts"`
+	// Export is set when the statement has more line items than a result
+	// holds, and says where the full statement can be read
+	Export *StatementExport `json:"export,omitempty"`
+}
+
+// StatementExport is where a statement that was cut short can be read in full
+type StatementExport struct {
+	URI            string    `json:"uri"`
+	MIMEType       string    `json:"mime_type"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	LineItemsShown int       `json:"line_items_shown"`
+	LineItemsTotal int       `json:"line_items_total"`
+}
+
+// AccountStatement is the statement of a single account
+type AccountStatement struct {
+	AccountID      string                     `json:"account_id"`
+	Asset          string                     `json:"asset"`
+	OpeningBalance decimal.Decimal            `json:"opening_balance"`
+	ClosingBalance decimal.Decimal            `json:"closing_balance"`
+	Totals         map[string]decimal.Decimal `json:"totals"`
+	LineItems      []StatementLine            `json:"line_items"`
+	// Complete is false when the window starts further back than the
+	// transactions that were fetched, so early line items may be missing
+	Complete bool `json:"complete"`
+}
+
+// StatementLine is a transaction on a statement
+type StatementLine struct {
+	Row         int64           `json:"row"`
+	Time        time.Time       `json:"time"`
+	Category    string          `json:"category"`
+	Description string          `json:"description"`
+	Reference   string          `json:"reference,omitempty"`
+	Amount      decimal.Decimal `json:"amount"`
+	Balance     decimal.Decimal `json:"balance"`
+}
+
+// NewGenerateStatementTool creates a new tool for generating account statements
+func NewGenerateStatementTool() mcp.Tool {
+	return mcp.NewTool(
+		GenerateStatementToolID,
+		mcp.WithDescription("Generate a statement for one or more accounts over a date range: opening and closing balances, "+
+			"totals by category (trade, fee, deposit, withdrawal, interest) and every transaction in the range. "+
+			"Use format csv for a statement that can be pasted into a spreadsheet. "+
+			"A statement with more transactions than the server returns at once only lists the first of them, "+
+			"and the full statement can be read from the luno://exports resource it links to, for an hour."),
+		mcp.WithString(
+			"account_ids",
+			mcp.Required(),
+			mcp.Description("Comma separated list of account IDs (e.g., 1224342323,1234567890)"),
+		),
+		mcp.WithString(
+			"start",
+			mcp.Required(),
+			mcp.Description("Start of the statement: a date (YYYY-MM-DD, from the start of that day in UTC), "+
+				"an RFC 3339 time or Unix milliseconds"),
+		),
+		mcp.WithString(
+			"end",
+			mcp.Description("End of the statement: a date (YYYY-MM-DD, to the end of that day in UTC), "+
+				"an RFC 3339 time or Unix milliseconds. Defaults to now."),
+		),
+		withFormat(),
+	)
+}
+
+// HandleGenerateStatement handles the generate_statement tool
+func HandleGenerateStatement(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		idsStr, err := request.RequireString("account_ids")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting account_ids from request", err), nil
+		}
+		var ids []string
+		for _, id := range strings.Split(idsStr, ",") {
+			if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return mcp.NewToolResultError("At least one account ID is required"), nil
+		}
+
+		startStr, err := request.RequireString("start")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting start from request", err), nil
+		}
+		start, err := parseStartTimestamp(startStr)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		end := time.Now().UTC()
+		if endStr := request.GetString("end", ""); endStr != "" {
+			if end, err = parseTimestamp(endStr); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		if !start.Before(end) {
+			return mcp.NewToolResultError("start must be before end"), nil
+		}
+
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting balances", err), nil
+		}
+		assets := make(map[string]string, len(balances.Balance))
+		for _, b := range balances.Balance {
+			assets[b.AccountId] = b.Asset
+		}
+
+		accountIDs := make([]int64, len(ids))
+		for i, id := range ids {
+			if _, ok := assets[id]; !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("Account %s was not found, use get_balances to list your accounts", id)), nil
+			}
+			if accountIDs[i], err = strconv.ParseInt(id, 10, 64); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid account ID %q, account IDs are numeric", id)), nil
+			}
+		}
+
+		statement := Statement{Start: start, End: end, Accounts: make([]AccountStatement, len(ids))}
+		err = forEach(ctx, cfg, ids, func(ctx context.Context, i int, id string) error {
+			account, err := accountStatement(ctx, cfg.LunoClient, accountIDs[i], start, end)
+			if err != nil {
+				return fmt.Errorf("getting transactions of account %s: %w", id, err)
+			}
+			account.AccountID, account.Asset = id, assets[id]
+			statement.Accounts[i] = account
+			return nil
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		table := &Table{Headers: []string{"Account", "Asset", "Row", "Time", "Category", "Description", "Amount", "Balance"}}
+		var lines int
+		for _, a := range statement.Accounts {
+			lines += len(a.LineItems)
+			for _, l := range a.LineItems {
+				table.Rows = append(table.Rows, []string{
+					a.AccountID, a.Asset, strconv.FormatInt(l.Row, 10), l.Time.Format(time.RFC3339), l.Category,
+					l.Description, l.Amount.String(), l.Balance.String(),
+				})
+			}
+		}
+
+		summary := fmt.Sprintf("Statement of %d accounts from %s to %s with %d transactions",
+			len(statement.Accounts), start.Format(time.RFC3339), end.Format(time.RFC3339), lines)
+		if limit := cfg.MaxResultRows; limit > 0 && lines > limit && cfg.Exports != nil {
+			export, err := exportStatement(ctx, cfg, statement, table, format)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to export statement: %v", err)), nil
+			}
+			export.LineItemsShown, export.LineItemsTotal = limit, lines
+			statement = truncateStatement(statement, limit)
+			statement.Export = export
+			table.Rows = table.Rows[:limit]
+			summary += fmt.Sprintf(". Only the first %d are listed, read %s for the full statement before %s",
+				limit, export.URI, export.ExpiresAt.Format(time.RFC3339))
+		}
+
+		response := Response{
+			URI:     resultURI(GenerateStatementToolID),
+			Summary: summary,
+			Data:    statement,
+			Table:   table,
+		}
+		result, err := response.Result(format)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal statement: %v", err)), nil
+		}
+		if statement.Export != nil {
+			result.Content = append(result.Content, mcp.NewResourceLink(statement.Export.URI, "Full statement",
+				summary, statement.Export.MIMEType))
+		}
+
+		return result, nil
+	}
+}
+
+// exportStatement stores the full statement for the session to read as a
+// resource, as CSV when that is the format asked for and as JSON otherwise
+func exportStatement(ctx context.Context, cfg *config.Config, statement Statement, table *Table, format ResponseFormat) (*StatementExport, error) {
+	mimeType := "application/json"
+	var data []byte
+	var err error
+	if format == FormatCSV {
+		mimeType = "text/csv"
+		var text string
+		text, err = table.CSV()
+		data = []byte(text)
+	} else {
+		data, err = json.MarshalIndent(statement, "", "  ")
+	}
+	if err != nil {
+		return nil, err
+	}
+	export, err := cfg.Exports.Add(sessionID(ctx), mimeType, string(data))
+	if err != nil {
+		return nil, err
+	}
+	return &StatementExport{URI: export.URI, MIMEType: mimeType, ExpiresAt: export.ExpiresAt}, nil
+}
+
+// truncateStatement returns a copy of a statement with only its first limit
+// line items, taking each account's in turn. Balances and totals still cover
+// the whole window.
+func truncateStatement(statement Statement, limit int) Statement {
+	accounts := make([]AccountStatement, len(statement.Accounts))
+	for i, a := range statement.Accounts {
+		n := min(len(a.LineItems), limit)
+		a.LineItems = a.LineItems[:n:n]
+		limit -= n
+		accounts[i] = a
+	}
+	statement.Accounts = accounts
+	return statement
+}
+
+// parseStartTimestamp is parseTimestamp, except that a date is the start of that day
+func parseStartTimestamp(s string) (time.Time, error) {
+	if d, err := time.Parse(time.DateOnly, strings.TrimSpace(s)); err == nil {
+		return d, nil
+	}
+	return parseTimestamp(s)
+}
+
+// accountStatement pages back through an account's transactions from the
+// newest until it passes start, and builds the statement of [start, end].
+func accountStatement(ctx context.Context, client sdk.LunoClient, accountID int64, start, end time.Time) (AccountStatement, error) {
+	account := AccountStatement{Totals: make(map[string]decimal.Decimal), LineItems: []StatementLine{}}
+
+	latest, err := client.ListTransactions(ctx, &luno.ListTransactionsRequest{Id: accountID, MinRow: -1, MaxRow: 0})
+	if err != nil {
+		return AccountStatement{}, err
+	}
+	if len(latest.Transactions) == 0 {
+		account.OpeningBalance, account.ClosingBalance, account.Complete = decimal.Zero(), decimal.Zero(), true
+		return account, nil
+	}
+
+	var (
+		items         []luno.Transaction
+		before, after *luno.Transaction
+	)
+	maxRow := latest.Transactions[0].RowIndex + 1
+	for page := 0; page < maxStatementPages && maxRow > 1 && before == nil; page++ {
+		minRow := max(1, maxRow-statementPageSize)
+		res, err := client.ListTransactions(ctx, &luno.ListTransactionsRequest{Id: accountID, MinRow: minRow, MaxRow: maxRow})
+		if err != nil {
+			return AccountStatement{}, err
+		}
+		txns := res.Transactions
+		slices.SortFunc(txns, func(a, b luno.Transaction) int { return cmp.Compare(b.RowIndex, a.RowIndex) })
+		for i := range txns {
+			ts := time.Time(txns[i].Timestamp)
+			switch {
+			case ts.After(end):
+				after = &txns[i]
+			case ts.Before(start):
+				before = &txns[i]
+			default:
+				items = append(items, txns[i])
+			}
+			if before != nil {
+				break
+			}
+		}
+		maxRow = minRow
+	}
+	account.Complete = before != nil || maxRow <= 1
+	slices.Reverse(items)
+
+	switch {
+	case len(items) > 0:
+		account.OpeningBalance = items[0].Balance.Sub(items[0].BalanceDelta)
+	case before != nil:
+		account.OpeningBalance = before.Balance
+	case after != nil:
+		account.OpeningBalance = after.Balance.Sub(after.BalanceDelta)
+	default:
+		account.OpeningBalance = decimal.Zero()
+	}
+	account.ClosingBalance = account.OpeningBalance
+
+	for _, txn := range items {
+		category := statementCategory(txn)
+		total, ok := account.Totals[category]
+		if !ok {
+			total = decimal.Zero()
+		}
+		account.Totals[category] = total.Add(txn.BalanceDelta)
+		account.ClosingBalance = txn.Balance
+		account.LineItems = append(account.LineItems, StatementLine{
+			Row:         txn.RowIndex,
+			Time:        time.Time(txn.Timestamp).UTC(),
+			Category:    category,
+			Description: txn.Description,
+			Reference:   txn.Reference,
+			Amount:      txn.BalanceDelta,
+			Balance:     txn.Balance,
+		})
+	}
+	return account, nil
+}
+
+// statementCategory groups a transaction by its kind, splitting transfers
+// into deposits and withdrawals
+func statementCategory(txn luno.Transaction) string {
+	switch txn.Kind {
+	case luno.KindExchange:
+		return CategoryTrade
+	case luno.KindFee:
+		return CategoryFee
+	case luno.KindInterest:
+		return CategoryInterest
+	case luno.KindTransfer:
+		if txn.BalanceDelta.Sign() < 0 {
+			return CategoryWithdrawal
+		}
+		return CategoryDeposit
+	default:
+		return CategoryOther
+	}
+}
@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/exports"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// statementTxns is an account history of one transaction a day from 1 March 2024
+func statementTxns() []luno.Transaction {
+	day := func(d int) luno.Time { return luno.Time(time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)) }
+	return []luno.Transaction{
+		{RowIndex: 1, Timestamp: day(1), Kind: luno.KindTransfer, Description: "Deposit", BalanceDelta: decimal.NewFromInt64(1000), Balance: decimal.NewFromInt64(1000)},
+		{RowIndex: 2, Timestamp: day(2), Kind: luno.KindExchange, Description: "Bought BTC", BalanceDelta: decimal.NewFromInt64(-400), Balance: decimal.NewFromInt64(600)},
+		{RowIndex: 3, Timestamp: day(2), Kind: luno.KindFee, Description: "Trading fee", BalanceDelta: decimal.NewFromInt64(-4), Balance: decimal.NewFromInt64(596)},
+		{RowIndex: 4, Timestamp: day(3), Kind: luno.KindTransfer, Description: "Withdrawal", BalanceDelta: decimal.NewFromInt64(-96), Balance: decimal.NewFromInt64(500)},
+		{RowIndex: 5, Timestamp: day(5), Kind: luno.KindTransfer, Description: "Deposit", BalanceDelta: decimal.NewFromInt64(50), Balance: decimal.NewFromInt64(550)},
+	}
+}
+
+func TestHandleGenerateStatement(t *testing.T) {
+	balances := &luno.GetBalancesResponse{Balance: []luno.AccountBalance{{AccountId: "123", Asset: "ZAR"}}}
+
+	tests := []struct {
+		name            string
+		params          map[string]any
+		mockSetup       func(*sdk.MockLunoClient)
+		expectedError   string
+		expectedOpening int64
+		expectedClosing int64
+		expectedRows    []int64
+		expectedTotals  map[string]int64
+	}{
+		{
+			name:   "window in the middle of the history",
+			params: map[string]any{"account_ids": "123", "start": "2024-03-02", "end": "2024-03-04"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+				m.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: -1, MaxRow: 0}).
+					Return(&luno.ListTransactionsResponse{Transactions: statementTxns()[4:]}, nil)
+				m.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: 1, MaxRow: 6}).
+					Return(&luno.ListTransactionsResponse{Transactions: statementTxns()}, nil)
+			},
+			expectedOpening: 1000,
+			expectedClosing: 500,
+			expectedRows:    []int64{2, 3, 4},
+			expectedTotals:  map[string]int64{CategoryTrade: -400, CategoryFee: -4, CategoryWithdrawal: -96},
+		},
+		{
+			name:   "window without transactions",
+			params: map[string]any{"account_ids": "123", "start": "2024-03-04", "end": "2024-03-04"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+				m.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: -1, MaxRow: 0}).
+					Return(&luno.ListTransactionsResponse{Transactions: statementTxns()[4:]}, nil)
+				m.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: 1, MaxRow: 6}).
+					Return(&luno.ListTransactionsResponse{Transactions: statementTxns()}, nil)
+			},
+			expectedOpening: 500,
+			expectedClosing: 500,
+			expectedRows:    []int64{},
+			expectedTotals:  map[string]int64{},
+		},
+		{
+			name:   "account without transactions",
+			params: map[string]any{"account_ids": "123", "start": "2024-03-01"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+				m.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: -1, MaxRow: 0}).
+					Return(&luno.ListTransactionsResponse{}, nil)
+			},
+			expectedRows:   []int64{},
+			expectedTotals: map[string]int64{},
+		},
+		{
+			name:   "unknown account",
+			params: map[string]any{"account_ids": "123,456", "start": "2024-03-01"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+			},
+			expectedError: "Account 456 was not found",
+		},
+		{
+			name:          "start after end",
+			params:        map[string]any{"account_ids": "123", "start": "2024-03-05", "end": "2024-03-01"},
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			expectedError: "start must be before end",
+		},
+		{
+			name:          "invalid start",
+			params:        map[string]any{"account_ids": "123", "start": "last month"},
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			expectedError: "invalid timestamp",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+
+			result, err := HandleGenerateStatement(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tc.expectedError != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, text, tc.expectedError)
+				return
+			}
+			require.False(t, result.IsError, text)
+
+			var statement Statement
+			require.NoError(t, json.Unmarshal([]byte(text), &statement))
+			require.Len(t, statement.Accounts, 1)
+			account := statement.Accounts[0]
+			assert.Equal(t, "ZAR", account.Asset)
+			assert.True(t, account.Complete)
+			assert.Equal(t, decimal.NewFromInt64(tc.expectedOpening).String(), account.OpeningBalance.String())
+			assert.Equal(t, decimal.NewFromInt64(tc.expectedClosing).String(), account.ClosingBalance.String())
+
+			rows := []int64{}
+			for _, l := range account.LineItems {
+				rows = append(rows, l.Row)
+			}
+			assert.Equal(t, tc.expectedRows, rows)
+
+			totals := map[string]string{}
+			for category, total := range account.Totals {
+				totals[category] = total.String()
+			}
+			expectedTotals := map[string]string{}
+			for category, total := range tc.expectedTotals {
+				expectedTotals[category] = decimal.NewFromInt64(total).String()
+			}
+			assert.Equal(t, expectedTotals, totals)
+		})
+	}
+}
+
+func TestHandleGenerateStatementExport(t *testing.T) {
+	balances := &luno.GetBalancesResponse{Balance: []luno.AccountBalance{{AccountId: "123", Asset: "ZAR"}}}
+
+	tests := []struct {
+		name             string
+		format           string
+		expectedMIMEType string
+		expectedExport   []string
+	}{
+		{
+			name:             "json",
+			expectedMIMEType: "application/json",
+			expectedExport:   []string{`"row": 1`, `"row": 4`, `"row": 5`},
+		},
+		{
+			name:             "csv",
+			format:           "csv",
+			expectedMIMEType: "text/csv",
+			expectedExport:   []string{"Deposit", "Withdrawal"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(balances, nil)
+			mockClient.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: -1, MaxRow: 0}).
+				Return(&luno.ListTransactionsResponse{Transactions: statementTxns()[4:]}, nil)
+			mockClient.EXPECT().ListTransactions(mock.Anything, &luno.ListTransactionsRequest{Id: 123, MinRow: 1, MaxRow: 6}).
+				Return(&luno.ListTransactionsResponse{Transactions: statementTxns()}, nil)
+			cfg := &config.Config{LunoClient: mockClient, MaxResultRows: 2, Exports: exports.NewStore(0)}
+
+			params := map[string]any{"account_ids": "123", "start": "2024-03-01"}
+			if tc.format != "" {
+				params["format"] = tc.format
+			}
+			result, err := HandleGenerateStatement(cfg)(context.Background(), createMockRequest(params))
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			require.Len(t, result.Content, 2)
+			text, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+
+			var link mcp.ResourceLink
+			for _, c := range result.Content {
+				if l, ok := c.(mcp.ResourceLink); ok {
+					link = l
+				}
+			}
+			require.NotEmpty(t, link.URI)
+			assert.Equal(t, tc.expectedMIMEType, link.MIMEType)
+			assert.Contains(t, link.Description, "Only the first 2 are listed")
+
+			export, data, err := cfg.Exports.Get("", exports.ParseURI(link.URI))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMIMEType, export.MIMEType)
+			for _, s := range tc.expectedExport {
+				assert.Contains(t, data, s)
+			}
+
+			if tc.format != "" {
+				return
+			}
+			var statement Statement
+			require.NoError(t, json.Unmarshal([]byte(text.Text), &statement))
+			require.NotNil(t, statement.Export)
+			assert.Equal(t, link.URI, statement.Export.URI)
+			assert.Equal(t, 2, statement.Export.LineItemsShown)
+			assert.Equal(t, 5, statement.Export.LineItemsTotal)
+			require.Len(t, statement.Accounts, 1)
+			assert.Len(t, statement.Accounts[0].LineItems, 2)
+			assert.Equal(t, decimal.NewFromInt64(550).String(), statement.Accounts[0].ClosingBalance.String())
+		})
+	}
+}
+
+func TestStatementCategory(t *testing.T) {
+	tests := []struct {
+		kind     luno.Kind
+		delta    int64
+		expected string
+	}{
+		{luno.KindExchange, -1, CategoryTrade},
+		{luno.KindFee, -1, CategoryFee},
+		{luno.KindInterest, 1, CategoryInterest},
+		{luno.KindTransfer, 1, CategoryDeposit},
+		{luno.KindTransfer, -1, CategoryWithdrawal},
+		{"", 1, CategoryOther},
+	}
+
+	for _, tc := range tests {
+		txn := luno.Transaction{Kind: tc.kind, BalanceDelta: decimal.NewFromInt64(tc.delta)}
+		assert.Equal(t, tc.expected, statementCategory(txn), string(tc.kind))
+	}
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ListToolsStatusToolID is the ID of the tool status tool
+const ListToolsStatusToolID = "list_tools_status"
+
+// ToolStatus describes whether a known tool is registered, and if not, why
+type ToolStatus struct {
+	Name       string `json:"name"`
+	Registered bool   `json:"registered"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// NewListToolsStatusTool creates a new tool for listing the status of every known tool
+func NewListToolsStatusTool() mcp.Tool {
+	return mcp.NewTool(
+		ListToolsStatusToolID,
+		mcp.WithDescription("List every tool this server knows about, whether it is enabled, "+
+			"and the reason for any tool that is not available"),
+	)
+}
+
+// HandleListToolsStatus handles the list_tools_status tool
+func HandleListToolsStatus(statuses []ToolStatus) server.ToolHandlerFunc {
+	statuses = slices.Clone(statuses)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		resultJSON, err := json.MarshalIndent(map[string]any{"tools": statuses}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal tool status: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
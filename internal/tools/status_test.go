@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListToolsStatus(t *testing.T) {
+	statuses := []ToolStatus{
+		{Name: GetBalancesToolID, Registered: true},
+		{Name: CreateOrderToolID, Reason: "write operations are disabled"},
+	}
+	handler := HandleListToolsStatus(statuses)
+
+	// Changes made after the handler is created must not leak into its output
+	statuses[0].Registered = false
+
+	result, err := handler(context.Background(), createMockRequest(nil))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var got struct {
+		Tools []ToolStatus `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+	assert.Equal(t, []ToolStatus{
+		{Name: GetBalancesToolID, Registered: true},
+		{Name: CreateOrderToolID, Reason: "write operations are disabled"},
+	}, got.Tools)
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/support"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// CreateSupportBundleToolID is the ID of the support bundle tool
+const CreateSupportBundleToolID = "create_support_bundle"
+
+// NewCreateSupportBundleTool creates a new tool for generating support bundles
+func NewCreateSupportBundleTool() mcp.Tool {
+	return mcp.NewTool(
+		CreateSupportBundleToolID,
+		mcp.WithDescription("Create a support bundle (zip archive) with recent redacted logs, masked configuration, "+
+			"version info and recent tool call metadata, for attaching to GitHub issues"),
+	)
+}
+
+// HandleCreateSupportBundle handles the create_support_bundle tool
+func HandleCreateSupportBundle(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Support == nil {
+			return mcp.NewToolResultError("Support bundles are not available on this server"), nil
+		}
+
+		path, err := cfg.Support.WriteBundle(os.TempDir(), cfg.Redacted())
+		if errors.Is(err, support.ErrRateLimited) {
+			return mcp.NewToolResultError(fmt.Sprintf("Support bundle not created: %v", err)), nil
+		} else if err != nil {
+			return mcp.NewToolResultErrorFromErr("creating support bundle", err), nil
+		}
+
+		result := map[string]string{
+			"path":    path,
+			"message": "Support bundle created. Review it before attaching it to a GitHub issue.",
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal support bundle result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+}
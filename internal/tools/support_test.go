@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/support"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateSupportBundle(t *testing.T) {
+	tests := []struct {
+		name          string
+		recorder      func() *support.Recorder
+		calls         int
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name:     "creates bundle",
+			recorder: func() *support.Recorder { return support.NewRecorder("test", "1.0.0") },
+			calls:    1,
+		},
+		{
+			name:          "rate limited on second call",
+			recorder:      func() *support.Recorder { return support.NewRecorder("test", "1.0.0") },
+			calls:         2,
+			expectedError: true,
+			errorContains: "Support bundle not created",
+		},
+		{
+			name:          "support not configured",
+			recorder:      func() *support.Recorder { return nil },
+			calls:         1,
+			expectedError: true,
+			errorContains: "not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+			cfg := &config.Config{Support: tt.recorder()}
+			handler := HandleCreateSupportBundle(cfg)
+
+			var text string
+			var isError bool
+			for i := 0; i < tt.calls; i++ {
+				result, err := handler(context.Background(), createMockRequest(nil))
+				require.NoError(t, err)
+				text = getTextContentFromResult(t, result)
+				isError = result.IsError
+			}
+
+			if tt.expectedError {
+				assert.True(t, isError)
+				assert.Contains(t, text, tt.errorContains)
+				return
+			}
+
+			assert.False(t, isError)
+			var result map[string]string
+			require.NoError(t, json.Unmarshal([]byte(text), &result))
+			_, err := os.Stat(result["path"])
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+)
+
+// enrichParam asks get_ticker for the computed fields of TickerStats
+const enrichParam = "enrich"
+
+// TickerStats are the fields get_ticker computes when asked to enrich a ticker
+type TickerStats struct {
+	// MidPrice, Spread and SpreadPercent are left out when the market has
+	// no bid or no ask
+	MidPrice      *decimal.Decimal `json:"mid_price,omitempty"`
+	Spread        *decimal.Decimal `json:"spread,omitempty"`
+	SpreadPercent *decimal.Decimal `json:"spread_percent,omitempty"`
+	// Open24h is the price 24 hours ago, the open of the first hourly candle
+	// in the last 24 hours
+	Open24h          *decimal.Decimal `json:"open_24h,omitempty"`
+	Change24h        *decimal.Decimal `json:"change_24h,omitempty"`
+	ChangePercent24h *decimal.Decimal `json:"change_percent_24h,omitempty"`
+	// ChangeError says why the 24 hour change is missing
+	ChangeError string `json:"change_error,omitempty"`
+}
+
+// tickerStats computes the spread, mid price and 24 hour change of a ticker.
+// A failure to get candles is reported in the stats, not returned, so the
+// ticker itself is still returned.
+func tickerStats(ctx context.Context, cfg *config.Config, pair string, ticker *luno.GetTickerResponse, now time.Time) *TickerStats {
+	stats := &TickerStats{}
+	if ticker.Bid.Sign() > 0 && ticker.Ask.Sign() > 0 {
+		top := newTopOfBook(ticker.Bid, ticker.Ask)
+		stats.MidPrice = &top.MidPrice
+		stats.Spread = &top.Spread
+		stats.SpreadPercent = &top.SpreadPercent
+	}
+
+	candles, err := cfg.LunoClient.GetCandles(ctx, &luno.GetCandlesRequest{
+		Pair:     pair,
+		Duration: int64(time.Hour / time.Second),
+		Since:    luno.Time(now.Add(-briefingLookback)),
+	})
+	if err != nil {
+		stats.ChangeError = fmt.Sprintf("getting candles: %v", err)
+		return stats
+	}
+	if len(candles.Candles) == 0 || candles.Candles[0].Open.Sign() <= 0 {
+		stats.ChangeError = "no trades in the last 24 hours"
+		return stats
+	}
+
+	open := candles.Candles[0].Open
+	change := ticker.LastTrade.Sub(open)
+	changePercent := percentOf(change, open)
+	stats.Open24h = &open
+	stats.Change24h = &change
+	stats.ChangePercent24h = &changePercent
+	return stats
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTickerStats(t *testing.T) {
+	now := time.UnixMilli(testTimestamp)
+	ticker := &luno.GetTickerResponse{
+		Pair:      "XBTZAR",
+		Bid:       decimal.NewFromInt64(99),
+		Ask:       decimal.NewFromInt64(101),
+		LastTrade: decimal.NewFromInt64(110),
+	}
+	candlesRequest := &luno.GetCandlesRequest{
+		Pair:     "XBTZAR",
+		Duration: 3600,
+		Since:    luno.Time(now.Add(-24 * time.Hour)),
+	}
+
+	tests := []struct {
+		name                  string
+		ticker                *luno.GetTickerResponse
+		candles               []luno.Candle
+		candlesErr            error
+		expectedMid           string
+		expectedChange        string
+		expectedChangePercent string
+		expectedChangeError   string
+	}{
+		{
+			name:                  "spread and change",
+			ticker:                ticker,
+			candles:               []luno.Candle{{Open: decimal.NewFromInt64(100)}, {Open: decimal.NewFromInt64(105)}},
+			expectedMid:           "100",
+			expectedChange:        "10",
+			expectedChangePercent: "10",
+		},
+		{
+			name:                  "no ask",
+			ticker:                &luno.GetTickerResponse{Bid: ticker.Bid, LastTrade: ticker.LastTrade},
+			candles:               []luno.Candle{{Open: decimal.NewFromInt64(220)}},
+			expectedChange:        "-110",
+			expectedChangePercent: "-50",
+		},
+		{
+			name:                "no trades",
+			ticker:              ticker,
+			expectedMid:         "100",
+			expectedChangeError: "no trades in the last 24 hours",
+		},
+		{
+			name:                "candles error",
+			ticker:              ticker,
+			candlesErr:          errors.New("rate limited"),
+			expectedMid:         "100",
+			expectedChangeError: "getting candles: rate limited",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			if tc.candlesErr != nil {
+				mockClient.EXPECT().GetCandles(mock.Anything, candlesRequest).Return(nil, tc.candlesErr)
+			} else {
+				mockClient.EXPECT().GetCandles(mock.Anything, candlesRequest).Return(&luno.GetCandlesResponse{Candles: tc.candles}, nil)
+			}
+
+			stats := tickerStats(context.Background(), &config.Config{LunoClient: mockClient}, "XBTZAR", tc.ticker, now)
+
+			if tc.expectedMid == "" {
+				assert.Nil(t, stats.MidPrice)
+				assert.Nil(t, stats.Spread)
+			} else {
+				require.NotNil(t, stats.MidPrice)
+				assertDecimal(t, tc.expectedMid, *stats.MidPrice)
+				assertDecimal(t, "2", *stats.Spread)
+				assertDecimal(t, "2", *stats.SpreadPercent)
+			}
+			assert.Equal(t, tc.expectedChangeError, stats.ChangeError)
+			if tc.expectedChange == "" {
+				assert.Nil(t, stats.Change24h)
+				return
+			}
+			require.NotNil(t, stats.Change24h)
+			assertDecimal(t, tc.expectedChange, *stats.Change24h)
+			assertDecimal(t, tc.expectedChangePercent, *stats.ChangePercent24h)
+		})
+	}
+}
+
+func TestHandleGetTickerEnrich(t *testing.T) {
+	ticker := &luno.GetTickerResponse{
+		Pair:      "XBTZAR",
+		Bid:       decimal.NewFromInt64(99),
+		Ask:       decimal.NewFromInt64(101),
+		LastTrade: decimal.NewFromInt64(110),
+	}
+
+	tests := []struct {
+		name     string
+		params   map[string]any
+		enriched bool
+	}{
+		{name: "raw by default", params: map[string]any{"pair": "XBTZAR"}},
+		{name: "enrich false", params: map[string]any{"pair": "XBTZAR", "enrich": false}},
+		{name: "enrich", params: map[string]any{"pair": "XBTZAR", "enrich": true}, enriched: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).Return(ticker, nil)
+			if tc.enriched {
+				mockClient.EXPECT().GetCandles(mock.Anything, mock.Anything).
+					Return(&luno.GetCandlesResponse{Candles: []luno.Candle{{Open: decimal.NewFromInt64(100)}}}, nil)
+			}
+
+			result, err := HandleGetTicker(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			var got map[string]any
+			require.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &got))
+			assert.Equal(t, "XBTZAR", got["pair"])
+			if !tc.enriched {
+				assert.NotContains(t, got, "mid_price")
+				assert.NotContains(t, got, "change_24h")
+				return
+			}
+			assertDecimal(t, "100", NewFromString(t, got["mid_price"].(string)))
+			assertDecimal(t, "10", NewFromString(t, got["change_percent_24h"].(string)))
+		})
+	}
+}
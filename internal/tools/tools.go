@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"github.com/luno/luno-go"
 	"github.com/luno/luno-go/decimal"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/market"
+	"github.com/luno/luno-mcp/internal/notes"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -25,15 +28,29 @@ const (
 
 // Tool IDs
 const (
-	GetBalancesToolID      = "get_balances"
-	GetTickerToolID        = "get_ticker"
-	GetOrderBookToolID     = "get_order_book"
-	CreateOrderToolID      = "create_order"
-	CancelOrderToolID      = "cancel_order"
-	ListOrdersToolID       = "list_orders"
-	ListTransactionsToolID = "list_transactions"
-	GetTransactionToolID   = "get_transaction"
-	ListTradesToolID       = "list_trades"
+	GetBalancesToolID             = "get_balances"
+	CreateAccountToolID           = "create_account"
+	GetTickerToolID               = "get_ticker"
+	GetAllTickersToolID           = "get_all_tickers"
+	GetOrderBookToolID            = "get_order_book"
+	ListMarketsToolID             = "list_markets"
+	CreateOrderToolID             = "create_order"
+	CancelOrderToolID             = "cancel_order"
+	ListOrdersToolID              = "list_orders"
+	GetOrderToolID                = "get_order"
+	ListTransactionsToolID        = "list_transactions"
+	GetTransactionToolID          = "get_transaction"
+	ListPendingTransactionsToolID = "list_pending_transactions"
+	ListTradesToolID              = "list_trades"
+	ListUserTradesToolID          = "list_user_trades"
+	SetNoteToolID                 = "set_note"
+)
+
+// Paging limits for transaction lookups. The API returns at most 1000 rows per request.
+const (
+	transactionPageSize     = 1000
+	defaultTransactionPages = 10
+	maxTransactionPages     = 100
 )
 
 // ===== Balance Tools =====
@@ -43,6 +60,7 @@ func NewGetBalancesTool() mcp.Tool {
 	return mcp.NewTool(
 		GetBalancesToolID,
 		mcp.WithDescription("Get balances for all Luno accounts"),
+		withFormat(),
 	)
 }
 
@@ -51,6 +69,11 @@ func HandleGetBalances(cfg *config.Config) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Since we're using a private API endpoint, authentication errors will be handled by the API call
 
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
 		balances, err := cfg.LunoClient.GetBalances(ctx, &luno.GetBalancesRequest{})
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to get balances: %v", err)), nil
@@ -64,6 +87,7 @@ func HandleGetBalances(cfg *config.Config) server.ToolHandlerFunc {
 			Reserved    string `json:"reserved"`
 			Unconfirmed string `json:"unconfirmed"`
 			Name        string `json:"name"`
+			Note        string `json:"note,omitempty"`
 		}
 
 		enhancedBalances := make([]EnhancedBalance, 0, len(balances.Balance))
@@ -75,14 +99,90 @@ func HandleGetBalances(cfg *config.Config) server.ToolHandlerFunc {
 				Reserved:    balance.Reserved.String(),
 				Unconfirmed: balance.Unconfirmed.String(),
 				Name:        balance.Name,
+				Note:        cfg.Notes.Get(notes.KindAccount, balance.AccountId),
 			})
 		}
 
-		resultJSON, err := json.MarshalIndent(enhancedBalances, "", "  ")
+		table := &Table{Headers: []string{"Account", "Asset", "Balance", "Reserved", "Unconfirmed", "Name"}}
+		for _, b := range enhancedBalances {
+			table.Rows = append(table.Rows, []string{b.AccountID, b.Asset, b.Balance, b.Reserved, b.Unconfirmed, b.Name})
+		}
+
+		response := Response{
+			URI:     resultURI(GetBalancesToolID),
+			Summary: fmt.Sprintf("%d accounts", len(enhancedBalances)),
+			Data:    enhancedBalances,
+			Table:   table,
+		}
+		result, err := response.Result(format)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal balances: %v", err)), nil
 		}
 
+		return result, nil
+	}
+}
+
+// NewCreateAccountTool creates a new tool for creating an account
+func NewCreateAccountTool() mcp.Tool {
+	return mcp.NewTool(
+		CreateAccountToolID,
+		mcp.WithDescription("Create a new Luno account (wallet) for a currency. "+
+			"You must be verified to trade the currency, and there is a limit of 10 accounts per currency."),
+		mcp.WithString(
+			"currency",
+			mcp.Required(),
+			mcp.Description("Currency of the new account (e.g., XBT)"),
+		),
+		mcp.WithString(
+			"name",
+			mcp.Required(),
+			mcp.Description("Label for the new account"),
+		),
+		withConfirmToken(),
+		withDryRun(),
+	)
+}
+
+// HandleCreateAccount handles the create_account tool
+func HandleCreateAccount(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		currency, err := request.RequireString("currency")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting currency from request", err), nil
+		}
+		currency = market.NormalizePair(strings.TrimSpace(currency))
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting name from request", err), nil
+		}
+		name = strings.TrimSpace(name)
+		if currency == "" || name == "" {
+			return mcp.NewToolResultError("Currency and name must not be empty"), nil
+		}
+
+		preview := map[string]string{"currency": currency, "name": name}
+		if isDryRun(cfg, request) {
+			return dryRunResult(CreateAccountToolID, preview), nil
+		}
+		if res := requireConfirmation(cfg, request, CreateAccountToolID, currency+"\n"+name, preview); res != nil {
+			return res, nil
+		}
+
+		account, err := cfg.LunoClient.CreateAccount(ctx, &luno.CreateAccountRequest{
+			Currency: currency,
+			Name:     name,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to create account: %v", err)), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(account, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal account: %v", err)), nil
+		}
+
 		return mcp.NewToolResultText(string(resultJSON)), nil
 	}
 }
@@ -99,6 +199,10 @@ func NewGetTickerTool() mcp.Tool {
 			mcp.Required(),
 			mcp.Description(ErrTradingPairDesc),
 		),
+		mcp.WithBoolean(
+			enrichParam,
+			mcp.Description("Also compute the spread, mid price and 24 hour change, which costs an extra call for candles (default: false)"),
+		),
 	)
 }
 
@@ -111,7 +215,7 @@ func HandleGetTicker(cfg *config.Config) server.ToolHandlerFunc {
 		}
 
 		// Normalize currency pair
-		pair = normalizeCurrencyPair(pair)
+		pair = market.NormalizePair(pair)
 
 		ticker, err := cfg.LunoClient.GetTicker(ctx, &luno.GetTickerRequest{
 			Pair: pair,
@@ -120,7 +224,19 @@ func HandleGetTicker(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("getting ticker", err), nil
 		}
 
-		resultJSON, err := json.MarshalIndent(ticker, "", "  ")
+		result := struct {
+			*luno.GetTickerResponse
+			*TickerStats
+			Note string `json:"note,omitempty"`
+		}{
+			GetTickerResponse: ticker,
+			Note:              cfg.Notes.Get(notes.KindPair, pair),
+		}
+		if request.GetBool(enrichParam, false) {
+			result.TickerStats = tickerStats(ctx, cfg, pair, ticker, time.Now())
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal ticker: %v", err)), nil
 		}
@@ -129,16 +245,84 @@ func HandleGetTicker(cfg *config.Config) server.ToolHandlerFunc {
 	}
 }
 
+// NewGetAllTickersTool creates a new tool for getting tickers for many markets at once
+func NewGetAllTickersTool() mcp.Tool {
+	return mcp.NewTool(
+		GetAllTickersToolID,
+		mcp.WithDescription("Get ticker information for all markets in one call, for comparing prices across markets"),
+		mcp.WithString(
+			"pairs",
+			mcp.Description("Comma separated list of trading pairs to return (e.g., XBTZAR,ETHZAR). Returns all markets if empty."),
+		),
+		withFormat(),
+		withPagination(),
+	)
+}
+
+// HandleGetAllTickers handles the get_all_tickers tool
+func HandleGetAllTickers(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var pairs []string
+		for _, p := range strings.Split(request.GetString("pairs", ""), ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				pairs = append(pairs, market.NormalizePair(p))
+			}
+		}
+
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
+
+		tickers, err := cfg.LunoClient.GetTickers(ctx, &luno.GetTickersRequest{Pair: pairs})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting tickers", err), nil
+		}
+		rows, page := paginate(tickers.Tickers, pageReq, "tickers")
+
+		table := &Table{Headers: []string{"Pair", "Status", "Last trade", "Bid", "Ask", "24h volume"}}
+		for _, t := range rows {
+			table.Rows = append(table.Rows, []string{
+				t.Pair, string(t.Status), t.LastTrade.String(), t.Bid.String(), t.Ask.String(), t.Rolling24HourVolume.String(),
+			})
+		}
+
+		response := Response{
+			URI:     resultURI(GetAllTickersToolID),
+			Summary: fmt.Sprintf("%d tickers", len(tickers.Tickers)),
+			Data:    &luno.GetTickersResponse{Tickers: rows},
+			Table:   table,
+			Page:    page,
+		}
+		result, err := response.Result(format)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal tickers: %v", err)), nil
+		}
+
+		return result, nil
+	}
+}
+
 // NewGetOrderBookTool creates a new tool for getting the order book
 func NewGetOrderBookTool() mcp.Tool {
 	return mcp.NewTool(
 		GetOrderBookToolID,
-		mcp.WithDescription("Get order book for a trading pair"),
+		mcp.WithDescription("Get order book for a trading pair, best prices first, with the best bid and ask, spread and mid price"),
 		mcp.WithString(
 			"pair",
 			mcp.Required(),
 			mcp.Description(ErrTradingPairDesc),
 		),
+		mcp.WithNumber(
+			"depth",
+			mcp.Description("Number of price levels to return on each side, from the best price (default: the whole book)"),
+		),
+		withPagination(),
 	)
 }
 
@@ -151,7 +335,17 @@ func HandleGetOrderBook(cfg *config.Config) server.ToolHandlerFunc {
 		}
 
 		// Normalize currency pair
-		pair = normalizeCurrencyPair(pair)
+		pair = market.NormalizePair(pair)
+
+		depth := request.GetInt("depth", 0)
+		if depth < 0 {
+			return mcp.NewToolResultError("depth must be a positive number of price levels"), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
 
 		orderBook, err := cfg.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{
 			Pair: pair,
@@ -160,12 +354,109 @@ func HandleGetOrderBook(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("getting order book", err), nil
 		}
 
-		resultJSON, err := json.MarshalIndent(orderBook, "", "  ")
+		// Each side is cut to depth and paged separately, so a page has the
+		// same number of price levels on both sides
+		book := *orderBook
+		if depth > 0 {
+			book.Bids = slices.Clip(book.Bids[:min(depth, len(book.Bids))])
+			book.Asks = slices.Clip(book.Asks[:min(depth, len(book.Asks))])
+		}
+		var page, askPage *Page
+		book.Bids, page = paginate(book.Bids, pageReq, "price levels")
+		book.Asks, askPage = paginate(book.Asks, pageReq, "price levels")
+		if askPage != nil && (page == nil || askPage.Total > page.Total) {
+			page = askPage
+		}
+
+		response := Response{
+			Data: &OrderBook{Pair: pair, TopOfBook: topOfBook(orderBook), GetOrderBookResponse: &book},
+			Page: page,
+		}
+		result, err := response.Result(FormatJSON)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal order book: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+		return result, nil
+	}
+}
+
+// NewListMarketsTool creates a new tool for listing markets
+func NewListMarketsTool() mcp.Tool {
+	return mcp.NewTool(
+		ListMarketsToolID,
+		mcp.WithDescription("List Luno markets with their trading status, price/volume precision and minimum/maximum order sizes"),
+		mcp.WithString(
+			"pairs",
+			mcp.Description("Comma separated list of trading pairs to return (e.g., XBTZAR,ETHZAR). Returns all markets if empty."),
+		),
+		mcp.WithString(
+			"currency",
+			mcp.Description("Only return markets where this currency is the base or counter currency (e.g., ZAR)"),
+		),
+		withFormat(),
+		withPagination(),
+	)
+}
+
+// HandleListMarkets handles the list_markets tool
+func HandleListMarkets(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var pairs []string
+		for _, p := range strings.Split(request.GetString("pairs", ""), ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				pairs = append(pairs, market.NormalizePair(p))
+			}
+		}
+		currency := market.NormalizePair(strings.TrimSpace(request.GetString("currency", "")))
+
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
+
+		markets, err := ListMarkets(ctx, cfg, pairs...)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("listing markets", err), nil
+		}
+
+		if currency != "" {
+			filtered := make([]luno.MarketInfo, 0, len(markets))
+			for _, m := range markets {
+				if m.BaseCurrency == currency || m.CounterCurrency == currency {
+					filtered = append(filtered, m)
+				}
+			}
+			markets = filtered
+		}
+		rows, page := paginate(markets, pageReq, "markets")
+
+		table := &Table{Headers: []string{"Market", "Status", "Min volume", "Max volume", "Volume decimals", "Price decimals"}}
+		for _, m := range rows {
+			table.Rows = append(table.Rows, []string{
+				m.MarketId, string(m.TradingStatus), m.MinVolume.String(), m.MaxVolume.String(),
+				strconv.FormatInt(m.VolumeScale, 10), strconv.FormatInt(m.PriceScale, 10),
+			})
+		}
+
+		response := Response{
+			URI:     resultURI(ListMarketsToolID),
+			Summary: fmt.Sprintf("%d markets", len(markets)),
+			Data:    map[string]any{"markets": rows},
+			Table:   table,
+			Page:    page,
+		}
+		result, err := response.Result(format)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal markets: %v", err)), nil
+		}
+
+		return result, nil
 	}
 }
 
@@ -175,7 +466,7 @@ func HandleGetOrderBook(cfg *config.Config) server.ToolHandlerFunc {
 func NewCreateOrderTool() mcp.Tool {
 	return mcp.NewTool(
 		CreateOrderToolID,
-		mcp.WithDescription("Create a new limit order"),
+		mcp.WithDescription("Create a new limit order, optionally post-only, immediate-or-cancel, fill-or-kill or stop-limit"),
 		mcp.WithString(
 			"pair",
 			mcp.Required(),
@@ -184,19 +475,32 @@ func NewCreateOrderTool() mcp.Tool {
 		mcp.WithString(
 			"type",
 			mcp.Required(),
-			mcp.Description("Order type (BUY or SELL)"),
-			mcp.Enum("BUY", "SELL"),
+			mcp.Description("Order type: BUY or SELL (BID and ASK are accepted as synonyms)"),
+			mcp.Enum(orderTypeEnum...),
 		),
 		mcp.WithString(
 			"volume",
-			mcp.Required(),
-			mcp.Description("Order volume (amount of cryptocurrency to buy or sell)"),
+			mcp.Description("Order volume (amount of cryptocurrency to buy or sell), within the market's volume precision and limits (see list_markets). "+
+				"Either volume or value is required. "+amountDesc),
+		),
+		mcp.WithString(
+			orderValueParam,
+			mcp.Description("Amount of the counter currency to spend or receive instead of a volume, e.g. 1000 to buy ZAR 1000 of XBT on XBTZAR. "+
+				"The volume is worked out from the price and rounded down to the market's volume precision. "+amountDesc),
 		),
 		mcp.WithString(
 			"price",
 			mcp.Required(),
-			mcp.Description("Limit price as a decimal string"),
+			mcp.Description("Limit price, within the market's price precision and limits (see list_markets). "+amountDesc),
+		),
+		mcp.WithBoolean(
+			watchParam,
+			mcp.Description("Notify this session when the order is partially filled, filled or cancelled (see watch_order)"),
 		),
+		withOrderOptions(),
+		withClientOrderID(),
+		withConfirmToken(),
+		withDryRun(),
 	)
 }
 
@@ -213,20 +517,22 @@ func HandleCreateOrder(cfg *config.Config) server.ToolHandlerFunc {
 		slog.Debug("Processing trading pair", "originalPair", pair)
 
 		// Normalize the pair - this should handle BTC->XBT conversion automatically
-		pair = normalizeCurrencyPair(pair)
+		pair = market.NormalizePair(pair)
 		slog.Debug("Normalized trading pair", "originalPair", pair, "normalizedPair", pair)
 
 		orderType, err := request.RequireString("type")
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("getting type from request", err), nil
 		}
-		if orderType != "BUY" && orderType != "SELL" {
-			return mcp.NewToolResultError("Order type must be 'BUY' or 'SELL'"), nil
+		side, err := ParseOrderSide(orderType)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid order type", err), nil
 		}
 
-		volumeStr, err := request.RequireString("volume")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("getting volume from request", err), nil
+		volumeStr := request.GetString("volume", "")
+		valueStr := request.GetString(orderValueParam, "")
+		if (volumeStr == "") == (valueStr == "") {
+			return mcp.NewToolResultError("Either volume or value is required, but not both"), nil
 		}
 
 		priceStr, err := request.RequireString("price")
@@ -234,25 +540,97 @@ func HandleCreateOrder(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("getting price from request", err), nil
 		}
 
-		// Validate numeric values
-		volumeDec, err := decimal.NewFromString(volumeStr)
+		clientOrderID, givenID, err := clientOrderID(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid client order ID", err), nil
+		}
+
+		opts, err := parseOrderOptions(request)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid volume format: %v", err)), nil
+			return mcp.NewToolResultErrorFromErr("invalid order options", err), nil
+		}
+
+		// Validate numeric values. An order by value gets its volume once the market is known.
+		var volumeDec, valueDec decimal.Decimal
+		var volumeCurrency, valueCurrency string
+		if volumeStr != "" {
+			volumeDec, volumeCurrency, err = parseAmount(volumeStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid volume format: %v", err)), nil
+			}
+		} else {
+			valueDec, valueCurrency, err = parseAmount(valueStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid value format: %v", err)), nil
+			}
 		}
 
-		priceDec, err := decimal.NewFromString(priceStr)
+		priceDec, priceCurrency, err := parseAmount(priceStr)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid price format: %v", err)), nil
 		}
 
 		// Map BUY/SELL to BID/ASK for limit orders
-		var lunoOrderType luno.OrderType
-		if orderType == "BUY" {
-			lunoOrderType = luno.OrderTypeBid
-		} else { // SELL
-			lunoOrderType = luno.OrderTypeAsk
+		lunoOrderType := side.LunoOrderType()
+
+		if err := checkPairAllowed(cfg, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+
+		// Make sure the pair is a real market that is open for trading
+		info, err := ValidatePair(ctx, cfg, pair)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+
+		// An amount with a currency must be in the one the market trades in
+		if err := checkAmountCurrency("volume", volumeCurrency, info.BaseCurrency, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+		if err := checkAmountCurrency("price", priceCurrency, info.CounterCurrency, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+		if err := checkAmountCurrency("value", valueCurrency, info.CounterCurrency, pair); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
 		}
 
+		byValue := volumeStr == ""
+		if byValue {
+			volumeDec, err = volumeForValue(info, valueDec, priceDec)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+			}
+		}
+
+		// Catch precision and size problems before the API returns a less helpful error
+		if err := ValidateOrderSize(info, volumeDec, priceDec); err != nil {
+			if byValue {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: a value of %s %s at %s is a volume of %s: %v",
+					valueDec.String(), info.CounterCurrency, priceDec.String(), volumeDec.String(), err)), nil
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+		if err := opts.check(info); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+
+		// Fail fast on a missing balance rather than after a round trip to post the order
+		if err := checkOrderBalance(ctx, cfg, info, side, volumeDec, priceDec, decimal.Zero()); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+
+		// Hold the order's value against the risk limits until we know whether it was placed
+		release, err := reserveOrderValue(ctx, cfg, info, volumeDec, priceDec)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+		placed := false
+		defer func() {
+			if !placed {
+				release()
+			}
+		}()
+
 		// Get market info - we already validated the pair, but this provides additional info
 		marketInfoString, err := GetMarketInfo(ctx, cfg, pair)
 		if err != nil {
@@ -260,6 +638,37 @@ func HandleCreateOrder(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: Failed to retrieve market information for pair %s. Details: %v", pair, err)), nil
 		}
 
+		preview := map[string]string{
+			"pair":        pair,
+			"side":        string(side),
+			"volume":      volumeDec.String(),
+			"price":       priceDec.String(),
+			"total":       volumeDec.Mul(priceDec).String() + " " + info.CounterCurrency,
+			"market_info": marketInfoString,
+		}
+		if byValue {
+			preview["value"] = valueDec.String() + " " + info.CounterCurrency
+		}
+		details := []string{pair, string(side), volumeDec.String(), priceDec.String()}
+		details = opts.describe(preview, details)
+		if givenID {
+			preview[clientOrderIDParam] = clientOrderID
+			details = append(details, clientOrderID)
+		}
+		if isDryRun(cfg, request) {
+			return dryRunResult(CreateOrderToolID, preview), nil
+		}
+		binding := strings.Join(details, "\n")
+		if res := requireConfirmation(cfg, request, CreateOrderToolID, binding, preview); res != nil {
+			return res, nil
+		}
+
+		// Reject a retry of an order that was already submitted
+		finishSubmission, err := beginSubmission(ctx, cfg, orderSubmission, clientOrderID, givenID, binding)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Unable to create order: %v", err)), nil
+		}
+
 		// Log the request parameters for debugging
 		slog.Info("Creating order",
 			"pair", pair,
@@ -269,14 +678,17 @@ func HandleCreateOrder(cfg *config.Config) server.ToolHandlerFunc {
 
 		// Create the limit order
 		createReq := &luno.PostLimitOrderRequest{
-			Pair:   pair,
-			Type:   lunoOrderType,
-			Volume: volumeDec,
-			Price:  priceDec,
+			Pair:          pair,
+			Type:          lunoOrderType,
+			Volume:        volumeDec,
+			Price:         priceDec,
+			ClientOrderId: clientOrderID,
 		}
+		opts.apply(createReq)
 
 		order, err := cfg.LunoClient.PostLimitOrder(ctx, createReq)
 		if err != nil {
+			finishSubmission("", err)
 			// If the order fails despite our validation, provide detailed error information
 			errorMsg := fmt.Sprintf("Failed to create limit order: %v\\n\\n"+
 				"Here's what we know about this market:\\n%s\\n\\n"+
@@ -285,9 +697,38 @@ func HandleCreateOrder(cfg *config.Config) server.ToolHandlerFunc {
 
 			return mcp.NewToolResultError(errorMsg), nil
 		}
-
-		// Order succeeded
-		resultJSON, err := json.MarshalIndent(order, "", "  ")
+		placed = true
+		finishSubmission(order.OrderId, nil)
+
+		// Order succeeded, report the canonical side alongside Luno's order type
+		result := struct {
+			*luno.PostLimitOrderResponse
+			ClientOrderID string         `json:"client_order_id"`
+			Pair          string         `json:"pair"`
+			Side          OrderSide      `json:"side"`
+			Type          luno.OrderType `json:"type"`
+			orderOptions
+			Watching bool   `json:"watching,omitempty"`
+			WatchErr string `json:"watch_error,omitempty"`
+		}{
+			PostLimitOrderResponse: order,
+			ClientOrderID:          clientOrderID,
+			Pair:                   pair,
+			Side:                   side,
+			Type:                   lunoOrderType,
+			orderOptions:           opts,
+		}
+		if request.GetBool(watchParam, false) {
+			// The order was placed, so a failure to watch it is only reported
+			if cfg.OrderWatch == nil {
+				result.WatchErr = "order watching is not enabled"
+			} else if _, err := cfg.OrderWatch.Add(sessionID(ctx), order.OrderId); err != nil {
+				result.WatchErr = err.Error()
+			} else {
+				result.Watching = true
+			}
+		}
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal order result: %v", err)), nil
 		}
@@ -308,6 +749,8 @@ func NewCancelOrderTool() mcp.Tool {
 			mcp.Required(),
 			mcp.Description("Order ID to cancel"),
 		),
+		withConfirmToken(),
+		withDryRun(),
 	)
 }
 
@@ -321,6 +764,31 @@ func HandleCancelOrder(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultErrorFromErr("getting order_id from request", err), nil
 		}
 
+		dryRun := isDryRun(cfg, request)
+		var preview any = map[string]string{"order_id": orderID}
+		if len(cfg.Settings().AllowedPairs) > 0 {
+			// The order's pair must be known to enforce the allow-list
+			order, err := cfg.LunoClient.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: orderID})
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to cancel order: could not look up its pair: %v", err)), nil
+			}
+			if err := checkPairAllowed(cfg, order.Pair); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unable to cancel order: %v", err)), nil
+			}
+			preview = order
+		} else if dryRun || (cfg.Confirmations != nil && request.GetString(confirmTokenParam, "") == "") {
+			// Show what is being cancelled, but a failed lookup shouldn't block the preview
+			if order, err := cfg.LunoClient.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: orderID}); err == nil {
+				preview = order
+			}
+		}
+		if dryRun {
+			return dryRunResult(CancelOrderToolID, preview), nil
+		}
+		if res := requireConfirmation(cfg, request, CancelOrderToolID, orderID, preview); res != nil {
+			return res, nil
+		}
+
 		result, err := cfg.LunoClient.StopOrder(ctx, &luno.StopOrderRequest{
 			OrderId: orderID,
 		})
@@ -350,6 +818,8 @@ func NewListOrdersTool() mcp.Tool {
 			"limit",
 			mcp.Description("Maximum number of orders to return (default: 100)"),
 		),
+		withFormat(),
+		withPagination(),
 	)
 }
 
@@ -361,10 +831,23 @@ func HandleListOrders(cfg *config.Config) server.ToolHandlerFunc {
 		// Get the pair if provided, otherwise it will be an empty string.
 		// An empty pair string will result in fetching orders for all pairs.
 		pair := request.GetString("pair", "")
+		if pair != "" {
+			pair = market.NormalizePair(pair)
+		}
 
 		// Default to 100 if not present
 		limit := request.GetFloat("limit", 100)
 
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
+
 		listReq := &luno.ListOrdersRequest{
 			Pair:  pair,
 			Limit: int64(limit),
@@ -374,12 +857,76 @@ func HandleListOrders(cfg *config.Config) server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to list orders: %v", err)), nil
 		}
+		rows, page := paginate(orders.Orders, pageReq, "orders")
+
+		table := &Table{Headers: []string{"Order", "Pair", "Type", "State", "Limit price", "Limit volume", "Filled"}}
+		for _, o := range rows {
+			table.Rows = append(table.Rows, []string{
+				o.OrderId, o.Pair, string(o.Type), string(o.State), o.LimitPrice.String(), o.LimitVolume.String(), o.Base.String(),
+			})
+		}
 
-		resultJSON, err := json.MarshalIndent(orders, "", "  ")
+		response := Response{
+			URI:     resultURI(ListOrdersToolID),
+			Summary: fmt.Sprintf("%d orders", len(orders.Orders)),
+			Data:    &luno.ListOrdersResponse{Orders: rows},
+			Table:   table,
+			Page:    page,
+		}
+		result, err := response.Result(format)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal orders: %v", err)), nil
 		}
 
+		return result, nil
+	}
+}
+
+// NewGetOrderTool creates a new tool for getting a single order
+func NewGetOrderTool() mcp.Tool {
+	return mcp.NewTool(
+		GetOrderToolID,
+		mcp.WithDescription("Get the status of a single order, including fill amounts and fees. "+
+			"Provide either order_id or client_order_id."),
+		mcp.WithString(
+			"order_id",
+			mcp.Description("Order ID returned when the order was created"),
+		),
+		mcp.WithString(
+			"client_order_id",
+			mcp.Description("Client order ID supplied when the order was created"),
+		),
+	)
+}
+
+// HandleGetOrder handles the get_order tool
+func HandleGetOrder(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Since we're using a private API endpoint, authentication errors will be handled by the API call
+
+		orderID := strings.TrimSpace(request.GetString("order_id", ""))
+		clientOrderID := strings.TrimSpace(request.GetString("client_order_id", ""))
+
+		switch {
+		case orderID == "" && clientOrderID == "":
+			return mcp.NewToolResultError("Either order_id or client_order_id is required"), nil
+		case orderID != "" && clientOrderID != "":
+			return mcp.NewToolResultError("Provide only one of order_id or client_order_id"), nil
+		}
+
+		order, err := cfg.LunoClient.GetOrderV3(ctx, &luno.GetOrderV3Request{
+			Id:            orderID,
+			ClientOrderId: clientOrderID,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get order: %v", err)), nil
+		}
+
+		resultJSON, err := json.MarshalIndent(order, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal order: %v", err)), nil
+		}
+
 		return mcp.NewToolResultText(string(resultJSON)), nil
 	}
 }
@@ -390,12 +937,8 @@ func HandleListOrders(cfg *config.Config) server.ToolHandlerFunc {
 func NewListTransactionsTool() mcp.Tool {
 	return mcp.NewTool(
 		ListTransactionsToolID,
-		mcp.WithDescription("List transactions for an account"),
-		mcp.WithString(
-			"account_id",
-			mcp.Required(),
-			mcp.Description("Account ID"),
-		),
+		mcp.WithDescription("List transactions for an account, given by its ID or by its asset"),
+		withAccount(),
 		mcp.WithNumber(
 			"min_row",
 			mcp.Description("Minimum row ID to return (for pagination, inclusive)"),
@@ -404,6 +947,8 @@ func NewListTransactionsTool() mcp.Tool {
 			"max_row",
 			mcp.Description("Maximum row ID to return (for pagination, exclusive)"),
 		),
+		withFormat(),
+		withPagination(),
 	)
 }
 
@@ -412,15 +957,20 @@ func HandleListTransactions(cfg *config.Config) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Since we're using a private API endpoint, authentication errors will be handled by the API call
 
-		accountIDStr, err := request.RequireString("account_id")
+		accountID, err := resolveAccountID(ctx, cfg, request)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("getting account_id from request", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
+		accountIDStr := strconv.FormatInt(accountID, 10)
 
-		// Convert account ID from string to int64
-		accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+		format, err := parseFormat(request)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid account ID format: %v. Please provide a valid numeric account ID.", err)), nil
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
 		}
 
 		listReq := &luno.ListTransactionsRequest{
@@ -440,12 +990,36 @@ func HandleListTransactions(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to list transactions: %v", err)), nil
 		}
 
-		resultJSON, err := json.MarshalIndent(transactions, "", "  ")
+		rows, page := paginate(transactions.Transactions, pageReq, "transactions")
+		result := struct {
+			*luno.ListTransactionsResponse
+			Note string `json:"note,omitempty"`
+		}{
+			ListTransactionsResponse: &luno.ListTransactionsResponse{Id: transactions.Id, Transactions: rows},
+			Note:                     cfg.Notes.Get(notes.KindAccount, accountIDStr),
+		}
+
+		table := &Table{Headers: []string{"Row", "Time", "Description", "Currency", "Balance change", "Available change", "Balance"}}
+		for _, txn := range rows {
+			table.Rows = append(table.Rows, []string{
+				strconv.FormatInt(txn.RowIndex, 10), time.Time(txn.Timestamp).UTC().Format(time.RFC3339), txn.Description,
+				txn.Currency, txn.BalanceDelta.String(), txn.AvailableDelta.String(), txn.Balance.String(),
+			})
+		}
+
+		response := Response{
+			URI:     resultURI(ListTransactionsToolID),
+			Summary: fmt.Sprintf("%d transactions for account %s", len(transactions.Transactions), accountIDStr),
+			Data:    result,
+			Table:   table,
+			Page:    page,
+		}
+		toolResult, err := response.Result(format)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal transactions: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+		return toolResult, nil
 	}
 }
 
@@ -454,16 +1028,17 @@ func NewGetTransactionTool() mcp.Tool {
 	return mcp.NewTool(
 		GetTransactionToolID,
 		mcp.WithDescription("Get details of a specific transaction"),
-		mcp.WithString(
-			"account_id",
-			mcp.Required(),
-			mcp.Description("Account ID"),
-		),
+		withAccount(),
 		mcp.WithString(
 			"transaction_id",
 			mcp.Required(),
 			mcp.Description("Transaction ID"),
 		),
+		mcp.WithNumber(
+			"max_pages",
+			mcp.Description(fmt.Sprintf("Maximum number of pages of %d transactions to search (default: %d, max: %d)",
+				transactionPageSize, defaultTransactionPages, maxTransactionPages)),
+		),
 	)
 }
 
@@ -472,15 +1047,9 @@ func HandleGetTransaction(cfg *config.Config) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Since we're using a private API endpoint, authentication errors will be handled by the API call
 
-		accountIDStr, err := request.RequireString("account_id")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("getting account_id from request", err), nil
-		}
-
-		// Convert account ID from string to int64
-		accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+		accountID, err := resolveAccountID(ctx, cfg, request)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Invalid account ID format: %v. Please provide a valid numeric account ID.", err)), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		transactionIDStr, err := request.RequireString("transaction_id")
@@ -494,29 +1063,22 @@ func HandleGetTransaction(cfg *config.Config) server.ToolHandlerFunc {
 			return mcp.NewToolResultError(fmt.Sprintf("Invalid transaction ID format: %v. Please provide a valid numeric transaction ID.", err)), nil
 		}
 
-		// Get the list of transactions with MinRow and MaxRow
-		listReq := &luno.ListTransactionsRequest{
-			Id:     accountID,
-			MinRow: 0,    // Start from the beginning
-			MaxRow: 1000, // Use a reasonable max to find the transaction
+		maxPages := request.GetInt("max_pages", defaultTransactionPages)
+		if maxPages < 1 || maxPages > maxTransactionPages {
+			return mcp.NewToolResultError(fmt.Sprintf("max_pages must be between 1 and %d", maxTransactionPages)), nil
 		}
 
-		transactions, err := cfg.LunoClient.ListTransactions(ctx, listReq)
+		transaction, searched, err := findTransaction(ctx, cfg, accountID, transactionID, maxPages)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to get transactions: %v", err)), nil
 		}
 
-		// Find the specific transaction
-		var transaction *luno.Transaction
-		for _, txn := range transactions.Transactions {
-			if txn.RowIndex == transactionID {
-				transaction = &txn
-				break
-			}
-		}
-
 		if transaction == nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Transaction not found: %s", transactionIDStr)), nil
+			if searched {
+				return mcp.NewToolResultError(fmt.Sprintf("Transaction not found: %s", transactionIDStr)), nil
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("Transaction not found: %s in the first %d transactions, increase max_pages to search further",
+				transactionIDStr, maxPages*transactionPageSize)), nil
 		}
 
 		resultJSON, err := json.MarshalIndent(transaction, "", "  ")
@@ -528,6 +1090,80 @@ func HandleGetTransaction(cfg *config.Config) server.ToolHandlerFunc {
 	}
 }
 
+// findTransaction pages through an account's transactions, oldest first,
+// until it finds the row or runs out of history. searched is false if
+// maxPages ran out before the history was exhausted. Rate limiting between
+// pages is left to the client.
+func findTransaction(ctx context.Context, cfg *config.Config, accountID, rowIndex int64, maxPages int) (txn *luno.Transaction, searched bool, err error) {
+	for page := range int64(maxPages) {
+		transactions, err := cfg.LunoClient.ListTransactions(ctx, &luno.ListTransactionsRequest{
+			Id: accountID,
+			// Rows are numbered from 1 and max_row is exclusive. A min_row
+			// below 1 counts back from the latest row instead.
+			MinRow: page*transactionPageSize + 1,
+			MaxRow: (page+1)*transactionPageSize + 1,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		for i, t := range transactions.Transactions {
+			if t.RowIndex == rowIndex {
+				return &transactions.Transactions[i], true, nil
+			}
+		}
+		if len(transactions.Transactions) < transactionPageSize {
+			return nil, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// NewListPendingTransactionsTool creates a new tool for listing pending transactions
+func NewListPendingTransactionsTool() mcp.Tool {
+	return mcp.NewTool(
+		ListPendingTransactionsToolID,
+		mcp.WithDescription("List pending (unconfirmed) transactions for an account, such as deposits and withdrawals that have not completed yet"),
+		withAccount(),
+		withPagination(),
+	)
+}
+
+// HandleListPendingTransactions handles the list_pending_transactions tool
+func HandleListPendingTransactions(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Since we're using a private API endpoint, authentication errors will be handled by the API call
+
+		accountID, err := resolveAccountID(ctx, cfg, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
+
+		pending, err := cfg.LunoClient.ListPendingTransactions(ctx, &luno.ListPendingTransactionsRequest{
+			Id: accountID,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list pending transactions: %v", err)), nil
+		}
+		result := *pending
+		var page *Page
+		result.Pending, page = paginate(pending.Pending, pageReq, "pending transactions")
+
+		response := Response{Data: &result, Page: page}
+		toolResult, err := response.Result(FormatJSON)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal pending transactions: %v", err)), nil
+		}
+
+		return toolResult, nil
+	}
+}
+
 // ===== Trades Tools =====
 
 // NewListTradesTool creates a new tool for listing trades
@@ -544,6 +1180,8 @@ func NewListTradesTool() mcp.Tool {
 			"since",
 			mcp.Description("Fetch trades executed after this timestamp (Unix milliseconds)"),
 		),
+		withFormat(),
+		withPagination(),
 	)
 }
 
@@ -556,7 +1194,17 @@ func HandleListTrades(cfg *config.Config) server.ToolHandlerFunc {
 		}
 
 		// Normalize currency pair
-		pair = normalizeCurrencyPair(pair)
+		pair = market.NormalizePair(pair)
+
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
 
 		req := &luno.ListTradesRequest{
 			Pair: pair,
@@ -576,44 +1224,201 @@ func HandleListTrades(cfg *config.Config) server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("listing trades", err), nil
 		}
+		rows, page := paginate(trades.Trades, pageReq, "trades")
 
-		resultJSON, err := json.MarshalIndent(trades, "", "  ")
+		table := &Table{Headers: []string{"Time", "Side", "Price", "Volume"}}
+		for _, tr := range rows {
+			side := "SELL"
+			if tr.IsBuy {
+				side = "BUY"
+			}
+			table.Rows = append(table.Rows, []string{
+				time.Time(tr.Timestamp).UTC().Format(time.RFC3339), side, tr.Price.String(), tr.Volume.String(),
+			})
+		}
+
+		response := Response{
+			URI:     resultURI(ListTradesToolID),
+			Summary: fmt.Sprintf("%d recent trades for %s", len(trades.Trades), pair),
+			Data:    &luno.ListTradesResponse{Trades: rows},
+			Table:   table,
+			Page:    page,
+		}
+		result, err := response.Result(format)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal trades: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(string(resultJSON)), nil
+		return result, nil
 	}
 }
 
-// ===== Helper Functions =====
+// NewListUserTradesTool creates a new tool for listing the user's own trades
+func NewListUserTradesTool() mcp.Tool {
+	return mcp.NewTool(
+		ListUserTradesToolID,
+		mcp.WithDescription("List your own trades (private trade history) for a currency pair"),
+		mcp.WithString(
+			"pair",
+			mcp.Required(),
+			mcp.Description(ErrTradingPairDesc),
+		),
+		mcp.WithString(
+			"since",
+			mcp.Description("Only return trades at or after this timestamp (Unix milliseconds)"),
+		),
+		mcp.WithString(
+			"before",
+			mcp.Description("Only return trades before this timestamp (Unix milliseconds)"),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of trades to return (default: 100, max: 1000)"),
+		),
+		withFormat(),
+		withPagination(),
+	)
+}
 
-// normalizeCurrencyPair converts common currency pair formats to Luno's expected format
-func normalizeCurrencyPair(pair string) string {
-	// Log input for debugging
-	originalPair := pair
+// HandleListUserTrades handles the list_user_trades tool
+func HandleListUserTrades(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Since we're using a private API endpoint, authentication errors will be handled by the API call
 
-	// Remove any separators that might be in the pair
-	pair = strings.Replace(pair, "-", "", -1)
-	pair = strings.Replace(pair, "_", "", -1)
-	pair = strings.Replace(pair, "/", "", -1)
-	pair = strings.ToUpper(pair)
+		pair, err := request.RequireString("pair")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting pair from request", err), nil
+		}
 
-	// Apply currency code standardization
-	// Known mappings between common symbols and Luno's expected format
-	currencyMappings := map[string]string{
-		"BTC":     "XBT", // Bitcoin is XBT on Luno
-		"BITCOIN": "XBT",
-		// Add other mappings if needed in the future
-	}
+		// Normalize currency pair
+		pair = market.NormalizePair(pair)
+
+		format, err := parseFormat(request)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid format", err), nil
+		}
+
+		pageReq, err := parsePage(request, cfg.MaxResultRows)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid pagination", err), nil
+		}
+
+		req := &luno.ListUserTradesRequest{
+			Pair:  pair,
+			Limit: int64(request.GetInt("limit", 100)),
+		}
+
+		if sinceStr := request.GetString("since", ""); sinceStr != "" {
+			sinceInt, err := strconv.ParseInt(sinceStr, 10, 64)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid 'since' timestamp format: %v. Please provide a valid Unix millisecond timestamp.", err)), nil
+			}
+			req.Since = luno.Time(time.UnixMilli(sinceInt))
+		}
+
+		if beforeStr := request.GetString("before", ""); beforeStr != "" {
+			beforeInt, err := strconv.ParseInt(beforeStr, 10, 64)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid 'before' timestamp format: %v. Please provide a valid Unix millisecond timestamp.", err)), nil
+			}
+			req.Before = luno.Time(time.UnixMilli(beforeInt))
+		}
+
+		trades, err := cfg.LunoClient.ListUserTrades(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("listing user trades", err), nil
+		}
+		rows, page := paginate(trades.Trades, pageReq, "trades")
+
+		table := &Table{Headers: []string{"Time", "Order", "Side", "Price", "Volume", "Value", "Fee (base)", "Fee (counter)"}}
+		for _, tr := range rows {
+			table.Rows = append(table.Rows, []string{
+				time.Time(tr.Timestamp).UTC().Format(time.RFC3339), tr.OrderId, string(userTradeSide(tr)), tr.Price.String(),
+				tr.Volume.String(), tr.Counter.String(), tr.FeeBase.String(), tr.FeeCounter.String(),
+			})
+		}
+
+		response := Response{
+			URI:     resultURI(ListUserTradesToolID),
+			Summary: fmt.Sprintf("%d of your trades on %s", len(trades.Trades), pair),
+			Data:    &luno.ListUserTradesResponse{Trades: rows},
+			Table:   table,
+			Page:    page,
+		}
+		result, err := response.Result(format)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal user trades: %v", err)), nil
+		}
 
-	// Apply all mappings
-	for common, luno := range currencyMappings {
-		pair = strings.Replace(pair, common, luno, -1)
+		return result, nil
 	}
+}
 
-	// Log the normalization for debugging
-	slog.Debug("Currency pair normalization", "original", originalPair, "normalized", pair)
+// ===== Note Tools =====
 
-	return pair
+// NewSetNoteTool creates a new tool for attaching notes to accounts and pairs
+func NewSetNoteTool() mcp.Tool {
+	return mcp.NewTool(
+		SetNoteToolID,
+		mcp.WithDescription("Attach a note to an account or trading pair (e.g. \"this account is for tax savings\"). "+
+			"Notes are included in the output of related tools. An empty note removes the existing one."),
+		mcp.WithString(
+			"target_type",
+			mcp.Required(),
+			mcp.Description("What the note is attached to"),
+			mcp.Enum(string(notes.KindAccount), string(notes.KindPair)),
+		),
+		mcp.WithString(
+			"target",
+			mcp.Required(),
+			mcp.Description("Account ID or trading pair (e.g., XBTZAR)"),
+		),
+		mcp.WithString(
+			"note",
+			mcp.Description("Note text, leave empty to remove the note"),
+		),
+	)
+}
+
+// HandleSetNote handles the set_note tool
+func HandleSetNote(cfg *config.Config) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg.Notes == nil {
+			return mcp.NewToolResultError("Notes are not available on this server"), nil
+		}
+
+		targetType, err := request.RequireString("target_type")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting target_type from request", err), nil
+		}
+		kind, err := notes.ParseKind(targetType)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("parsing target_type", err), nil
+		}
+
+		target, err := request.RequireString("target")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("getting target from request", err), nil
+		}
+		target = strings.TrimSpace(target)
+		if kind == notes.KindPair {
+			target = market.NormalizePair(target)
+		}
+
+		note := request.GetString("note", "")
+		cfg.Notes.Set(kind, target, note)
+
+		result := map[string]string{
+			"target_type": string(kind),
+			"target":      target,
+			"note":        cfg.Notes.Get(kind, target),
+		}
+
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal note: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
 }
@@ -11,10 +11,13 @@ import (
 	"github.com/luno/luno-go"
 	"github.com/luno/luno-go/decimal"
 	"github.com/luno/luno-mcp/internal/config"
+	"github.com/luno/luno-mcp/internal/notes"
 	"github.com/luno/luno-mcp/sdk"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // NewFromString is a test helper that creates a decimal from a string, failing the test on error.
@@ -42,38 +45,6 @@ const (
 	testTimestamp             = 1640995200000 // January 1, 2022 00:00:00 UTC
 )
 
-func TestNormalizeCurrencyPair(t *testing.T) {
-	testCases := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{"Simple BTC to XBT", "BTC", "XBT"},
-		{"BTC in pair", "BTCGBP", "XBTGBP"},
-		{"BTC with hyphen separator", "BTC-GBP", "XBTGBP"},
-		{"BTC with slash separator", "BTC/GBP", "XBTGBP"},
-		{"BTC with underscore separator", "BTC_GBP", "XBTGBP"},
-		{"Lowercase input", "btcgbp", "XBTGBP"},
-		{"Mixed case input", "xbTGbP", "XBTGBP"},
-		{"Non-BTC pair", "ETHZAR", "ETHZAR"},
-		{"Non-BTC pair with separator", "ETH-ZAR", "ETHZAR"},
-		{"BITCOIN text conversion", "BITCOIN", "XBT"},
-		{"BITCOIN in pair", "BITCOINUSD", "XBTUSD"},
-		{"Multiple separators", "BTC-_/GBP", "XBTGBP"},
-		{"Combo of mappings", "BITCOIN/GBP", "XBTGBP"},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := normalizeCurrencyPair(tc.input)
-			if result != tc.expected {
-				t.Errorf("normalizeCurrencyPair(%q) = %q, want %q",
-					tc.input, result, tc.expected)
-			}
-		})
-	}
-}
-
 func TestToolCreation(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -85,7 +56,7 @@ func TestToolCreation(t *testing.T) {
 			name:     "GetBalances tool",
 			toolFunc: NewGetBalancesTool,
 			toolName: GetBalancesToolID,
-			params:   []string{},
+			params:   []string{"format"},
 		},
 		{
 			name:     "GetTicker tool",
@@ -93,29 +64,77 @@ func TestToolCreation(t *testing.T) {
 			toolName: GetTickerToolID,
 			params:   []string{"pair"},
 		},
+		{
+			name:     "CreateAccount tool",
+			toolFunc: NewCreateAccountTool,
+			toolName: CreateAccountToolID,
+			params:   []string{"currency", "name", "confirm_token", "dry_run"},
+		},
+		{
+			name:     "GetAllTickers tool",
+			toolFunc: NewGetAllTickersTool,
+			toolName: GetAllTickersToolID,
+			params:   []string{"pairs", "format"},
+		},
+		{
+			name:     "CompareMarkets tool",
+			toolFunc: NewCompareMarketsTool,
+			toolName: CompareMarketsToolID,
+			params:   []string{"pairs", "quote_currency", "format"},
+		},
+		{
+			name:     "ListAssets tool",
+			toolFunc: NewListAssetsTool,
+			toolName: ListAssetsToolID,
+			params:   []string{"assets", "format"},
+		},
+		{
+			name:     "MoveFunds tool",
+			toolFunc: NewMoveFundsTool,
+			toolName: MoveFundsToolID,
+			params:   []string{"from_account_id", "to_account_id", "amount", "client_move_id", "confirm_token", "dry_run"},
+		},
 		{
 			name:     "GetOrderBook tool",
 			toolFunc: NewGetOrderBookTool,
 			toolName: GetOrderBookToolID,
 			params:   []string{"pair"},
 		},
+		{
+			name:     "ListMarkets tool",
+			toolFunc: NewListMarketsTool,
+			toolName: ListMarketsToolID,
+			params:   []string{"pairs", "currency", "format"},
+		},
 		{
 			name:     "CreateOrder tool",
 			toolFunc: NewCreateOrderTool,
 			toolName: CreateOrderToolID,
-			params:   []string{"pair", "type", "volume", "price"},
+			params:   []string{"pair", "type", "volume", "price", "watch", "post_only", "time_in_force", "stop_price", "stop_direction", "confirm_token", "dry_run"},
 		},
 		{
 			name:     "CancelOrder tool",
 			toolFunc: NewCancelOrderTool,
 			toolName: CancelOrderToolID,
-			params:   []string{"order_id"},
+			params:   []string{"order_id", "confirm_token", "dry_run"},
+		},
+		{
+			name:     "ReplaceOrder tool",
+			toolFunc: NewReplaceOrderTool,
+			toolName: ReplaceOrderToolID,
+			params:   []string{"order_id", "price", "volume", "client_order_id", "confirm_token", "dry_run"},
 		},
 		{
 			name:     "ListOrders tool",
 			toolFunc: NewListOrdersTool,
 			toolName: ListOrdersToolID,
-			params:   []string{"pair", "limit"},
+			params:   []string{"pair", "limit", "format"},
+		},
+		{
+			name:     "GetOrder tool",
+			toolFunc: NewGetOrderTool,
+			toolName: GetOrderToolID,
+			params:   []string{"order_id", "client_order_id"},
 		},
 		{
 			name:     "ListTransactions tool",
@@ -129,11 +148,155 @@ func TestToolCreation(t *testing.T) {
 			toolName: GetTransactionToolID,
 			params:   []string{"account_id", "transaction_id"},
 		},
+		{
+			name:     "ListPendingTransactions tool",
+			toolFunc: NewListPendingTransactionsTool,
+			toolName: ListPendingTransactionsToolID,
+			params:   []string{"account_id"},
+		},
 		{
 			name:     "ListTrades tool",
 			toolFunc: NewListTradesTool,
 			toolName: ListTradesToolID,
-			params:   []string{"pair", "since"},
+			params:   []string{"pair", "since", "format"},
+		},
+		{
+			name:     "ListUserTrades tool",
+			toolFunc: NewListUserTradesTool,
+			toolName: ListUserTradesToolID,
+			params:   []string{"pair", "since", "before", "limit"},
+		},
+		{
+			name:     "SetNote tool",
+			toolFunc: NewSetNoteTool,
+			toolName: SetNoteToolID,
+			params:   []string{"target_type", "target", "note"},
+		},
+		{
+			name:     "CreateSupportBundle tool",
+			toolFunc: NewCreateSupportBundleTool,
+			toolName: CreateSupportBundleToolID,
+			params:   []string{},
+		},
+		{
+			name:     "WatchOrder tool",
+			toolFunc: NewWatchOrderTool,
+			toolName: WatchOrderToolID,
+			params:   []string{"order_id"},
+		},
+		{
+			name:     "UnwatchOrder tool",
+			toolFunc: NewUnwatchOrderTool,
+			toolName: UnwatchOrderToolID,
+			params:   []string{"order_id"},
+		},
+		{
+			name:     "ScheduleRecurringBuy tool",
+			toolFunc: NewScheduleRecurringBuyTool,
+			toolName: ScheduleRecurringBuyToolID,
+			params:   []string{"pair", "amount", "frequency", "max_slippage_percent", "start", "confirm_token", "dry_run"},
+		},
+		{
+			name:     "ListSchedules tool",
+			toolFunc: NewListSchedulesTool,
+			toolName: ListSchedulesToolID,
+			params:   []string{},
+		},
+		{
+			name:     "CancelSchedule tool",
+			toolFunc: NewCancelScheduleTool,
+			toolName: CancelScheduleToolID,
+			params:   []string{"id"},
+		},
+		{
+			name:     "CreatePriceAlert tool",
+			toolFunc: NewCreatePriceAlertTool,
+			toolName: CreatePriceAlertToolID,
+			params:   []string{"pair", "condition", "price"},
+		},
+		{
+			name:     "ListPriceAlerts tool",
+			toolFunc: NewListPriceAlertsTool,
+			toolName: ListPriceAlertsToolID,
+			params:   []string{},
+		},
+		{
+			name:     "DeletePriceAlert tool",
+			toolFunc: NewDeletePriceAlertTool,
+			toolName: DeletePriceAlertToolID,
+			params:   []string{"id"},
+		},
+		{
+			name:     "ListProfiles tool",
+			toolFunc: NewListProfilesTool,
+			toolName: ListProfilesToolID,
+			params:   []string{},
+		},
+		{
+			name:     "PlaceOrderSet tool",
+			toolFunc: NewPlaceOrderSetTool,
+			toolName: PlaceOrderSetToolID,
+			params:   []string{"orders", "confirm_token", "dry_run"},
+		},
+		{
+			name:     "GetAssetCapabilities tool",
+			toolFunc: NewGetAssetCapabilitiesTool,
+			toolName: GetAssetCapabilitiesToolID,
+			params:   []string{"currency"},
+		},
+		{
+			name:     "ValidateAddress tool",
+			toolFunc: NewValidateAddressTool,
+			toolName: ValidateAddressToolID,
+			params:   []string{"currency", "address", "destination_tag", "memo", "is_self_send", "is_private_wallet"},
+		},
+		{
+			name:     "ConvertAmount tool",
+			toolFunc: NewConvertAmountTool,
+			toolName: ConvertAmountToolID,
+			params:   []string{"amount", "from", "to"},
+		},
+		{
+			name:     "GetBriefing tool",
+			toolFunc: NewGetBriefingTool,
+			toolName: GetBriefingToolID,
+			params:   []string{"quote_currency", "pairs"},
+		},
+		{
+			name:     "GenerateStatement tool",
+			toolFunc: NewGenerateStatementTool,
+			toolName: GenerateStatementToolID,
+			params:   []string{"account_ids", "start", "end", "format"},
+		},
+		{
+			name:     "CalculatePnL tool",
+			toolFunc: NewCalculatePnLTool,
+			toolName: CalculatePnLToolID,
+			params:   []string{"pairs", "method", "start", "end"},
+		},
+		{
+			name:     "GetHistoricalPrice tool",
+			toolFunc: NewGetHistoricalPriceTool,
+			toolName: GetHistoricalPriceToolID,
+			params:   []string{"pair", "timestamp", "duration"},
+		},
+		{
+			name:     "AnalyzeOrderBook tool",
+			toolFunc: NewAnalyzeOrderBookTool,
+			toolName: AnalyzeOrderBookToolID,
+			params:   []string{"pair", "side", "volume"},
+		},
+		{
+			name:     "EstimateOrderCost tool",
+			toolFunc: NewEstimateOrderCostTool,
+			toolName: EstimateOrderCostToolID,
+			params:   []string{"pair", "side", "volume"},
+		},
+		{
+			name:     "ListToolsStatus tool",
+			toolFunc: NewListToolsStatusTool,
+			toolName: ListToolsStatusToolID,
+			params:   []string{},
 		},
 	}
 
@@ -278,6 +441,68 @@ func TestHandleGetBalances(t *testing.T) {
 	}
 }
 
+func TestHandleCreateAccount(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestParams map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name:          "successful create account",
+			requestParams: map[string]any{"currency": "btc", "name": " Savings "},
+			mockSetup: func(mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().CreateAccount(context.Background(), &luno.CreateAccountRequest{Currency: "XBT", Name: "Savings"}).
+					Return(&luno.CreateAccountResponse{Id: "12345", Currency: "XBT", Name: "Savings"}, nil)
+			},
+		},
+		{
+			name:          "missing name",
+			requestParams: map[string]any{"currency": "XBT"},
+			mockSetup:     func(mockClient *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "getting name from request",
+		},
+		{
+			name:          "blank name",
+			requestParams: map[string]any{"currency": "XBT", "name": "  "},
+			mockSetup:     func(mockClient *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "must not be empty",
+		},
+		{
+			name:          "CreateAccount API error",
+			requestParams: map[string]any{"currency": "XBT", "name": "Savings"},
+			mockSetup: func(mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().CreateAccount(context.Background(), mock.Anything).Return(nil, errors.New("ErrAccountLimitExceeded"))
+			},
+			expectedError: true,
+			errorContains: "Failed to create account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tt.mockSetup(mockClient)
+
+			result, err := HandleCreateAccount(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+			text := getTextContentFromResult(t, result)
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.errorContains)
+				return
+			}
+
+			var account luno.CreateAccountResponse
+			assert.NoError(t, json.Unmarshal([]byte(text), &account))
+			assert.Equal(t, "12345", account.Id)
+		})
+	}
+}
+
 func TestHandleGetTicker(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -382,6 +607,72 @@ func TestHandleGetTicker(t *testing.T) {
 	}
 }
 
+func TestHandleGetAllTickers(t *testing.T) {
+	tickers := &luno.GetTickersResponse{Tickers: []luno.Ticker{
+		{Pair: "XBTZAR", Status: "ACTIVE", Bid: decimal.NewFromInt64(800000), Ask: decimal.NewFromInt64(800100), LastTrade: decimal.NewFromInt64(800050)},
+		{Pair: "ETHZAR", Status: "ACTIVE", Bid: decimal.NewFromInt64(40000), Ask: decimal.NewFromInt64(40100), LastTrade: decimal.NewFromInt64(40050)},
+	}}
+
+	tests := []struct {
+		name          string
+		requestParams map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+		contains      string
+	}{
+		{
+			name: "all tickers",
+			mockSetup: func(mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetTickers(context.Background(), &luno.GetTickersRequest{}).Return(tickers, nil)
+			},
+			contains: `"pair": "ETHZAR"`,
+		},
+		{
+			name:          "selected pairs as a table",
+			requestParams: map[string]any{"pairs": "btc-zar, ETHZAR", "format": "table"},
+			mockSetup: func(mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetTickers(context.Background(), &luno.GetTickersRequest{Pair: []string{"XBTZAR", "ETHZAR"}}).Return(tickers, nil)
+			},
+			contains: "| XBTZAR | ACTIVE | 800050 | 800000 | 800100 |",
+		},
+		{
+			name:          "invalid format",
+			requestParams: map[string]any{"format": "xml"},
+			mockSetup:     func(mockClient *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "invalid format",
+		},
+		{
+			name: "API error",
+			mockSetup: func(mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetTickers(context.Background(), mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "getting tickers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tt.mockSetup(mockClient)
+
+			result, err := HandleGetAllTickers(&config.Config{LunoClient: mockClient})(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+
+			text := result.Content[0].(mcp.TextContent).Text
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, text, tt.errorContains)
+				return
+			}
+			assert.False(t, result.IsError)
+			assert.Contains(t, text, tt.contains)
+		})
+	}
+}
+
 func TestHandleGetOrderBook(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -468,6 +759,81 @@ func TestHandleGetOrderBook(t *testing.T) {
 	}
 }
 
+func TestHandleListMarkets(t *testing.T) {
+	tests := []struct {
+		name            string
+		requestParams   map[string]any
+		mockSetup       func(*testing.T, *sdk.MockLunoClient)
+		expectedError   bool
+		errorContains   string
+		expectedMarkets []string
+	}{
+		{
+			name:          "all markets",
+			requestParams: map[string]any{},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(context.Background(), &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+			},
+			expectedMarkets: []string{"XBTZAR", "ETHZAR", "XBTEUR"},
+		},
+		{
+			name:          "normalized pairs are requested",
+			requestParams: map[string]any{"pairs": "btc-zar, ETH/ZAR"},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(context.Background(), &luno.MarketsRequest{Pair: []string{"XBTZAR", "ETHZAR"}}).
+					Return(&luno.MarketsResponse{Markets: testMarketsResponse().Markets[:2]}, nil)
+			},
+			expectedMarkets: []string{"XBTZAR", "ETHZAR"},
+		},
+		{
+			name:          "filter by currency",
+			requestParams: map[string]any{"currency": "eur"},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(context.Background(), &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+			},
+			expectedMarkets: []string{"XBTEUR"},
+		},
+		{
+			name:          "Markets API error",
+			requestParams: map[string]any{},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "listing markets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tt.mockSetup(t, mockClient)
+
+			cfg := &config.Config{
+				LunoClient: mockClient,
+			}
+
+			handler := HandleListMarkets(cfg)
+			result, err := handler(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tt.errorContains)
+				return
+			}
+
+			assert.False(t, result.IsError)
+			var marketsResponse luno.MarketsResponse
+			assert.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &marketsResponse))
+			var ids []string
+			for _, m := range marketsResponse.Markets {
+				ids = append(ids, m.MarketId)
+			}
+			assert.Equal(t, tt.expectedMarkets, ids)
+		})
+	}
+}
+
 func TestHandleCancelOrder(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -645,6 +1011,98 @@ func TestHandleListOrders(t *testing.T) {
 	}
 }
 
+func TestHandleGetOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestParams map[string]any
+		mockSetup     func(*testing.T, *sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name: "successful get order by id",
+			requestParams: map[string]any{
+				"order_id": "BXMC2SEAS4KF5S2",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetOrderV3(context.Background(), &luno.GetOrderV3Request{Id: "BXMC2SEAS4KF5S2"}).
+					Return(&luno.GetOrderV3Response{
+						OrderId:    "BXMC2SEAS4KF5S2",
+						Pair:       "XBTZAR",
+						Status:     luno.StatusComplete,
+						Base:       NewFromString(t, "0.01"),
+						Counter:    NewFromString(t, "8000"),
+						FeeBase:    NewFromString(t, "0.00001"),
+						FeeCounter: NewFromString(t, "0"),
+					}, nil)
+			},
+		},
+		{
+			name: "successful get order by client order id",
+			requestParams: map[string]any{
+				"client_order_id": "my-order-1",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetOrderV3(context.Background(), &luno.GetOrderV3Request{ClientOrderId: "my-order-1"}).
+					Return(&luno.GetOrderV3Response{OrderId: "BXMC2SEAS4KF5S2", ClientOrderId: "my-order-1"}, nil)
+			},
+		},
+		{
+			name:          "missing both ids",
+			requestParams: map[string]any{},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "Either order_id or client_order_id is required",
+		},
+		{
+			name: "both ids provided",
+			requestParams: map[string]any{
+				"order_id":        "BXMC2SEAS4KF5S2",
+				"client_order_id": "my-order-1",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "Provide only one of",
+		},
+		{
+			name: "GetOrderV3 API error",
+			requestParams: map[string]any{
+				"order_id": "BXMC2SEAS4KF5S2",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "Failed to get order",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tt.mockSetup(t, mockClient)
+
+			cfg := &config.Config{
+				LunoClient: mockClient,
+			}
+
+			handler := HandleGetOrder(cfg)
+			result, err := handler(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tt.errorContains)
+				return
+			}
+
+			assert.False(t, result.IsError)
+			var orderResponse map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &orderResponse))
+			assert.Equal(t, "BXMC2SEAS4KF5S2", orderResponse["order_id"])
+		})
+	}
+}
+
 func TestHandleListTransactions(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -687,11 +1145,11 @@ func TestHandleListTransactions(t *testing.T) {
 			expectedError: false,
 		},
 		{
-			name:          "missing account_id parameter",
+			name:          "missing account_id and asset parameters",
 			requestParams: map[string]any{},
 			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed for this case */ },
 			expectedError: true,
-			errorContains: "getting account_id from request",
+			errorContains: "either account_id or asset is required",
 		},
 		{
 			name: "invalid account_id format",
@@ -703,7 +1161,37 @@ func TestHandleListTransactions(t *testing.T) {
 			errorContains: "Invalid account ID format",
 		},
 		{
-			name: "ListTransactions API error",
+			name: "account by asset",
+			requestParams: map[string]any{
+				"asset": "bitcoin",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetBalances(context.Background(), &luno.GetBalancesRequest{}).Return(&luno.GetBalancesResponse{
+					Balance: []luno.AccountBalance{{AccountId: "123456", Asset: "XBT"}, {AccountId: "654321", Asset: "ZAR"}},
+				}, nil)
+				mockClient.EXPECT().ListTransactions(context.Background(), &luno.ListTransactionsRequest{
+					Id:     123456,
+					MinRow: 1,
+					MaxRow: 100,
+				}).Return(&luno.ListTransactionsResponse{Id: "123456"}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "ambiguous asset",
+			requestParams: map[string]any{
+				"asset": "ZAR",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().GetBalances(context.Background(), &luno.GetBalancesRequest{}).Return(&luno.GetBalancesResponse{
+					Balance: []luno.AccountBalance{{AccountId: "1", Asset: "ZAR"}, {AccountId: "2", Asset: "ZAR", Name: "Savings"}},
+				}, nil)
+			},
+			expectedError: true,
+			errorContains: "you have 2 ZAR accounts, give one of their IDs as account_id: 1, 2 (Savings)",
+		},
+		{
+			name: "ListTransactions API error",
 			requestParams: map[string]any{
 				"account_id": "999999",
 			},
@@ -757,11 +1245,12 @@ func TestHandleListTransactions(t *testing.T) {
 
 func TestHandleGetTransaction(t *testing.T) {
 	tests := []struct {
-		name          string
-		requestParams map[string]any
-		mockSetup     func(*testing.T, *sdk.MockLunoClient)
-		expectedError bool
-		errorContains string
+		name             string
+		requestParams    map[string]any
+		mockSetup        func(*testing.T, *sdk.MockLunoClient)
+		expectedError    bool
+		errorContains    string
+		expectedRowIndex int64
 	}{
 		{
 			name: "successful get transaction",
@@ -798,11 +1287,12 @@ func TestHandleGetTransaction(t *testing.T) {
 				accountIdInt, _ := strconv.ParseInt("123456", 10, 64)
 				mockClient.EXPECT().ListTransactions(context.Background(), &luno.ListTransactionsRequest{
 					Id:     accountIdInt,
-					MinRow: 0,    // Default min_row for GetTransaction
-					MaxRow: 1000, // Default max_row for GetTransaction
+					MinRow: 1,    // Rows are numbered from 1
+					MaxRow: 1001, // and max_row is exclusive
 				}).Return(mockResponse, nil)
 			},
-			expectedError: false,
+			expectedError:    false,
+			expectedRowIndex: 5,
 		},
 		{
 			name: "transaction not found",
@@ -818,13 +1308,70 @@ func TestHandleGetTransaction(t *testing.T) {
 				accountIdInt, _ := strconv.ParseInt("123456", 10, 64)
 				mockClient.EXPECT().ListTransactions(context.Background(), &luno.ListTransactionsRequest{
 					Id:     accountIdInt,
-					MinRow: 0,
-					MaxRow: 1000,
+					MinRow: 1,
+					MaxRow: 1001,
 				}).Return(mockResponse, nil)
 			},
 			expectedError: true,
 			errorContains: "Transaction not found",
 		},
+		{
+			name: "transaction on a later page",
+			requestParams: map[string]any{
+				"account_id":     "123456",
+				"transaction_id": "1005",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				fullPage := &luno.ListTransactionsResponse{Transactions: make([]luno.Transaction, 1000)}
+				for i := range fullPage.Transactions {
+					fullPage.Transactions[i].RowIndex = int64(i + 1)
+				}
+				mockClient.EXPECT().ListTransactions(context.Background(), &luno.ListTransactionsRequest{
+					Id: 123456, MinRow: 1, MaxRow: 1001,
+				}).Return(fullPage, nil)
+				mockClient.EXPECT().ListTransactions(context.Background(), &luno.ListTransactionsRequest{
+					Id: 123456, MinRow: 1001, MaxRow: 2001,
+				}).Return(&luno.ListTransactionsResponse{Transactions: []luno.Transaction{{RowIndex: 1005}}}, nil)
+			},
+			expectedRowIndex: 1005,
+		},
+		{
+			name: "max_pages reached",
+			requestParams: map[string]any{
+				"account_id":     "123456",
+				"transaction_id": "5000",
+				"max_pages":      float64(1),
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListTransactions(context.Background(), mock.Anything).
+					Return(&luno.ListTransactionsResponse{Transactions: make([]luno.Transaction, 1000)}, nil).Once()
+			},
+			expectedError: true,
+			errorContains: "in the first 1000 transactions, increase max_pages",
+		},
+		{
+			name: "invalid max_pages",
+			requestParams: map[string]any{
+				"account_id":     "123456",
+				"transaction_id": "5",
+				"max_pages":      float64(0),
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) {},
+			expectedError: true,
+			errorContains: "max_pages must be between 1 and 100",
+		},
+		{
+			name: "ListTransactions API error",
+			requestParams: map[string]any{
+				"account_id":     "123456",
+				"transaction_id": "5",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListTransactions(context.Background(), mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "Failed to get transactions",
+		},
 		{
 			name: "missing account_id parameter",
 			requestParams: map[string]any{
@@ -832,7 +1379,7 @@ func TestHandleGetTransaction(t *testing.T) {
 			},
 			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
 			expectedError: true,
-			errorContains: "getting account_id from request",
+			errorContains: "either account_id or asset is required",
 		},
 		{
 			name: "missing transaction_id parameter",
@@ -894,12 +1441,97 @@ func TestHandleGetTransaction(t *testing.T) {
 				var transaction map[string]any
 				err := json.Unmarshal([]byte(textContent), &transaction)
 				assert.NoError(t, err)
-				assert.Equal(t, float64(5), transaction["row_index"]) // Ensure correct transaction is returned
+				assert.Equal(t, float64(tt.expectedRowIndex), transaction["row_index"]) // Ensure correct transaction is returned
 			}
 		})
 	}
 }
 
+func TestHandleListPendingTransactions(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestParams map[string]any
+		mockSetup     func(*testing.T, *sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name: "successful list pending transactions",
+			requestParams: map[string]any{
+				"account_id": "123456",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListPendingTransactions(context.Background(), &luno.ListPendingTransactionsRequest{
+					Id: 123456,
+				}).Return(&luno.ListPendingTransactionsResponse{
+					Id: "123456",
+					Pending: []luno.Transaction{
+						{
+							Timestamp:   luno.Time(time.UnixMilli(testTimestamp)),
+							Currency:    "XBT",
+							Description: "Unconfirmed deposit",
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:          "missing account_id and asset parameters",
+			requestParams: map[string]any{},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed for this case */ },
+			expectedError: true,
+			errorContains: "either account_id or asset is required",
+		},
+		{
+			name: "invalid account_id format",
+			requestParams: map[string]any{
+				"account_id": "not_a_number",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed for this case */ },
+			expectedError: true,
+			errorContains: "Invalid account ID format",
+		},
+		{
+			name: "ListPendingTransactions API error",
+			requestParams: map[string]any{
+				"account_id": "999999",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListPendingTransactions(context.Background(), &luno.ListPendingTransactionsRequest{
+					Id: 999999,
+				}).Return(nil, errors.New("Account not found"))
+			},
+			expectedError: true,
+			errorContains: "Failed to list pending transactions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tt.mockSetup(t, mockClient)
+
+			cfg := &config.Config{
+				LunoClient: mockClient,
+			}
+
+			handler := HandleListPendingTransactions(cfg)
+			result, err := handler(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tt.errorContains)
+				return
+			}
+
+			assert.False(t, result.IsError)
+			var pendingResponse map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &pendingResponse))
+			assert.Contains(t, pendingResponse, "pending")
+		})
+	}
+}
+
 func TestHandleListTrades(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -1024,6 +1656,157 @@ func TestHandleListTrades(t *testing.T) {
 	}
 }
 
+func TestHandleListUserTrades(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestParams map[string]any
+		mockSetup     func(*testing.T, *sdk.MockLunoClient)
+		expectedError bool
+		errorContains string
+	}{
+		{
+			name: "successful list user trades with defaults",
+			requestParams: map[string]any{
+				"pair": "btc-zar",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListUserTrades(context.Background(), &luno.ListUserTradesRequest{
+					Pair:  "XBTZAR",
+					Limit: 100,
+				}).Return(&luno.ListUserTradesResponse{
+					Trades: []luno.TradeV2{{Pair: "XBTZAR", OrderId: "BXMC2SEAS4KF5S2"}},
+				}, nil)
+			},
+		},
+		{
+			name: "successful list user trades with since, before and limit",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"since":  strconv.FormatInt(testTimestamp, 10),
+				"before": strconv.FormatInt(testTimestamp+1000, 10),
+				"limit":  float64(10),
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListUserTrades(context.Background(), &luno.ListUserTradesRequest{
+					Pair:   "XBTZAR",
+					Limit:  10,
+					Since:  luno.Time(time.UnixMilli(testTimestamp)),
+					Before: luno.Time(time.UnixMilli(testTimestamp + 1000)),
+				}).Return(&luno.ListUserTradesResponse{}, nil)
+			},
+		},
+		{
+			name:          missingPairParameterStr,
+			requestParams: map[string]any{},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: gettingPairFromRequestStr,
+		},
+		{
+			name: "invalid since format",
+			requestParams: map[string]any{
+				"pair":  "XBTZAR",
+				"since": "yesterday",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "Invalid 'since' timestamp format",
+		},
+		{
+			name: "invalid before format",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"before": "tomorrow",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "Invalid 'before' timestamp format",
+		},
+		{
+			name: "ListUserTrades API error",
+			requestParams: map[string]any{
+				"pair": "XBTZAR",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().ListUserTrades(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "listing user trades",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tt.mockSetup(t, mockClient)
+
+			cfg := &config.Config{
+				LunoClient: mockClient,
+			}
+
+			handler := HandleListUserTrades(cfg)
+			result, err := handler(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tt.errorContains)
+				return
+			}
+
+			assert.False(t, result.IsError)
+			var tradesResponse map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &tradesResponse))
+			assert.Contains(t, tradesResponse, "trades")
+		})
+	}
+}
+
+// testMarketsResponse returns market metadata used by tests that validate pairs
+func testMarketsResponse() *luno.MarketsResponse {
+	return &luno.MarketsResponse{
+		Markets: []luno.MarketInfo{
+			{
+				MarketId:        "XBTZAR",
+				BaseCurrency:    "XBT",
+				CounterCurrency: "ZAR",
+				TradingStatus:   luno.TradingStatusActive,
+				VolumeScale:     6,
+				PriceScale:      0,
+				MinVolume:       decimal.NewFromFloat64(0.0005, 4),
+				MaxVolume:       decimal.NewFromInt64(100),
+				MinPrice:        decimal.NewFromInt64(100),
+				MaxPrice:        decimal.NewFromInt64(10000000),
+			},
+			{
+				MarketId:        "ETHZAR",
+				BaseCurrency:    "ETH",
+				CounterCurrency: "ZAR",
+				TradingStatus:   luno.TradingStatusSuspended,
+				VolumeScale:     4,
+				PriceScale:      0,
+			},
+			{
+				MarketId:        "XBTEUR",
+				BaseCurrency:    "XBT",
+				CounterCurrency: "EUR",
+				TradingStatus:   luno.TradingStatusActive,
+				VolumeScale:     6,
+				PriceScale:      2,
+			},
+		},
+	}
+}
+
+// expectOrderBalance expects the balance check of create_order, with enough of
+// every currency for the test orders
+func expectOrderBalance(m *sdk.MockLunoClient) {
+	m.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{Balance: []luno.AccountBalance{
+		{Asset: "ZAR", Balance: decimal.NewFromInt64(1000000)},
+		{Asset: "XBT", Balance: decimal.NewFromInt64(10)},
+	}}, nil)
+	m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(&luno.GetFeeInfoResponse{MakerFee: "0", TakerFee: "0.001"}, nil)
+}
+
 // Helper function to create mock MCP requests
 func createMockRequest(params map[string]any) mcp.CallToolRequest {
 	arguments := make(map[string]any)
@@ -1050,15 +1833,18 @@ func TestHandleCreateOrder(t *testing.T) {
 		{
 			name: "successful create order",
 			requestParams: map[string]any{
-				"pair":   "XBTZAR",
-				"type":   "BUY",
-				"volume": "0.01",
-				"price":  "1000000",
+				"client_order_id": "test-order-1",
+				"pair":            "XBTZAR",
+				"type":            "BUY",
+				"volume":          "0.01",
+				"price":           "1000000",
 			},
 			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
 				vol := NewFromString(t, "0.01")
 				price := NewFromString(t, "1000000")
 
+				mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+
 				// Mock GetTicker call from GetMarketInfo
 				mockTickerResponse := &luno.GetTickerResponse{
 					Pair:                "XBTZAR",
@@ -1069,7 +1855,8 @@ func TestHandleCreateOrder(t *testing.T) {
 					Rolling24HourVolume: decimal.NewFromFloat64(100.5, -1),
 					Status:              "ACTIVE",
 				}
-				mockClient.EXPECT().GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"}).
+				expectOrderBalance(mockClient)
+				mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
 					Return(mockTickerResponse, nil)
 
 				// Mock GetOrderBook call from GetMarketInfo
@@ -1082,7 +1869,7 @@ func TestHandleCreateOrder(t *testing.T) {
 						{Price: decimal.NewFromInt64(800100), Volume: decimal.NewFromFloat64(0.8, -1)},
 					},
 				}
-				mockClient.EXPECT().GetOrderBook(context.Background(), &luno.GetOrderBookRequest{Pair: "XBTZAR"}).
+				mockClient.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).
 					Return(mockOrderBookResponse, nil)
 
 				// Mock PostLimitOrder call
@@ -1090,10 +1877,11 @@ func TestHandleCreateOrder(t *testing.T) {
 					OrderId: "BXMC2SEAS4KF5S2",
 				}
 				mockClient.EXPECT().PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{
-					Pair:   "XBTZAR",
-					Type:   luno.OrderTypeBid,
-					Volume: vol,
-					Price:  price,
+					Pair:          "XBTZAR",
+					Type:          luno.OrderTypeBid,
+					Volume:        vol,
+					Price:         price,
+					ClientOrderId: "test-order-1",
 				}).Return(mockResponse, nil)
 			},
 			expectedError: false,
@@ -1101,15 +1889,18 @@ func TestHandleCreateOrder(t *testing.T) {
 		{
 			name: "CreateOrder PostLimitOrder API error",
 			requestParams: map[string]any{
-				"pair":   "XBTZAR",
-				"type":   "BUY",
-				"volume": "0.01",
-				"price":  "1000000",
+				"client_order_id": "test-order-2",
+				"pair":            "XBTZAR",
+				"type":            "BUY",
+				"volume":          "0.01",
+				"price":           "1000000",
 			},
 			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
 				vol := NewFromString(t, "0.01")
 				price := NewFromString(t, "1000000")
 
+				mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+
 				// Mock GetTicker call from GetMarketInfo
 				mockTickerResponse := &luno.GetTickerResponse{
 					Pair:                "XBTZAR",
@@ -1120,7 +1911,8 @@ func TestHandleCreateOrder(t *testing.T) {
 					Rolling24HourVolume: decimal.NewFromFloat64(100.5, -1),
 					Status:              "ACTIVE",
 				}
-				mockClient.EXPECT().GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"}).
+				expectOrderBalance(mockClient)
+				mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
 					Return(mockTickerResponse, nil)
 
 				// Mock GetOrderBook call from GetMarketInfo
@@ -1133,15 +1925,16 @@ func TestHandleCreateOrder(t *testing.T) {
 						{Price: decimal.NewFromInt64(800100), Volume: decimal.NewFromFloat64(0.8, -1)},
 					},
 				}
-				mockClient.EXPECT().GetOrderBook(context.Background(), &luno.GetOrderBookRequest{Pair: "XBTZAR"}).
+				mockClient.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).
 					Return(mockOrderBookResponse, nil)
 
 				// Mock PostLimitOrder call that returns error
 				mockClient.EXPECT().PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{
-					Pair:   "XBTZAR",
-					Type:   luno.OrderTypeBid,
-					Volume: vol,
-					Price:  price,
+					Pair:          "XBTZAR",
+					Type:          luno.OrderTypeBid,
+					Volume:        vol,
+					Price:         price,
+					ClientOrderId: "test-order-2",
 				}).Return(nil, errors.New(apiErrorStr))
 			},
 			expectedError: true,
@@ -1156,7 +1949,11 @@ func TestHandleCreateOrder(t *testing.T) {
 				"price":  "1000000",
 			},
 			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(mockClient)
 				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(nil, errors.New("API error"))
+				// The order book is fetched alongside the ticker
+				mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil).Maybe()
 			},
 			expectedError: true,
 			errorContains: "Unable to create order: Failed to retrieve market information for pair XBTZAR",
@@ -1170,12 +1967,258 @@ func TestHandleCreateOrder(t *testing.T) {
 				"price":  "1000000",
 			},
 			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
-				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(mockClient)
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil).Maybe()
 				mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(nil, errors.New("API error"))
 			},
 			expectedError: true,
 			errorContains: "Unable to create order: Failed to retrieve market information for pair XBTZAR",
 		},
+		{
+			name: "ASK synonym creates sell order",
+			requestParams: map[string]any{
+				"client_order_id": "test-order-3",
+				"pair":            "XBTZAR",
+				"type":            "ask",
+				"volume":          "0.01",
+				"price":           "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(mockClient)
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+				mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+				mockClient.EXPECT().PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{
+					Pair:          "XBTZAR",
+					Type:          luno.OrderTypeAsk,
+					Volume:        NewFromString(t, "0.01"),
+					Price:         NewFromString(t, "1000000"),
+					ClientOrderId: "test-order-3",
+				}).Return(&luno.PostLimitOrderResponse{OrderId: "BXMC2SEAS4KF5S2"}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "buy by value rounds the volume down",
+			requestParams: map[string]any{
+				"client_order_id": "test-order-4",
+				"pair":            "XBTZAR",
+				"type":            "BUY",
+				"value":           "R1,000",
+				"price":           "1234567",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(mockClient)
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+				mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+				mockClient.EXPECT().PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{
+					Pair:          "XBTZAR",
+					Type:          luno.OrderTypeBid,
+					Volume:        NewFromString(t, "0.000810"),
+					Price:         NewFromString(t, "1234567"),
+					ClientOrderId: "test-order-4",
+				}).Return(&luno.PostLimitOrderResponse{OrderId: "BXMC2SEAS4KF5S2"}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "value too small for the minimum volume",
+			requestParams: map[string]any{
+				"pair":  "XBTZAR",
+				"type":  "BUY",
+				"value": "100",
+				"price": "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "a value of 100 ZAR at 1000000 is a volume of 0.000100: volume 0.000100 is below the minimum",
+		},
+		{
+			name: "value in the base currency",
+			requestParams: map[string]any{
+				"pair":  "XBTZAR",
+				"type":  "BUY",
+				"value": "0.01 BTC",
+				"price": "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "value is given in XBT but must be in ZAR for XBTZAR",
+		},
+		{
+			name: "buy over the available balance with fees",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "BUY",
+				"volume": "1",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(mockClient)
+			},
+			expectedError: true,
+			errorContains: "insufficient ZAR balance: the order needs 1001000.000 ZAR (1000000 plus an estimated 1000.000 fee) but only 1000000 ZAR is available",
+		},
+		{
+			name: "sell over the available balance",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "SELL",
+				"volume": "10.5",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(mockClient)
+			},
+			expectedError: true,
+			errorContains: "insufficient XBT balance: the order needs 10.5 XBT but only 10 XBT is available",
+		},
+		{
+			name: "balance check is skipped when balances can't be read",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "BUY",
+				"volume": "0.01",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+				mockClient.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr)).Maybe()
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+				mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+				mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "BXMC2SEAS4KF5S2"}, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "both volume and value",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "BUY",
+				"volume": "0.01",
+				"value":  "1000",
+				"price":  "1000000",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "Either volume or value is required, but not both",
+		},
+		{
+			name: "neither volume nor value",
+			requestParams: map[string]any{
+				"pair":  "XBTZAR",
+				"type":  "BUY",
+				"price": "1000000",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "Either volume or value is required, but not both",
+		},
+		{
+			name: "invalid order type for create order",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "HOLD",
+				"volume": "0.01",
+				"price":  "1000000",
+			},
+			mockSetup:     func(t *testing.T, mockClient *sdk.MockLunoClient) { /* No mock setup needed */ },
+			expectedError: true,
+			errorContains: "order type must be one of BUY, SELL, BID or ASK",
+		},
+		{
+			name: "unknown pair for create order",
+			requestParams: map[string]any{
+				"pair":   "XBTZAT",
+				"type":   "BUY",
+				"volume": "0.01",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "XBTZAT is not a valid Luno market, did you mean one of: XBTZAR",
+		},
+		{
+			name: "suspended market for create order",
+			requestParams: map[string]any{
+				"pair":   "ETHZAR",
+				"type":   "BUY",
+				"volume": "0.01",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "market ETHZAR is not open for trading, current status is SUSPENDED",
+		},
+		{
+			name: "Markets API error for create order",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "BUY",
+				"volume": "0.01",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			expectedError: true,
+			errorContains: "could not list markets",
+		},
+		{
+			name: "volume too precise for create order",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "BUY",
+				"volume": "0.0123456789",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "try 0.012345 instead",
+		},
+		{
+			name: "volume below minimum for create order",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "SELL",
+				"volume": "0.0001",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "below the minimum of 0.0005",
+		},
+		{
+			name: "volume in the counter currency for create order",
+			requestParams: map[string]any{
+				"pair":   "XBTZAR",
+				"type":   "BUY",
+				"volume": "R5,000",
+				"price":  "1000000",
+			},
+			mockSetup: func(t *testing.T, mockClient *sdk.MockLunoClient) {
+				mockClient.EXPECT().Markets(mock.Anything, &luno.MarketsRequest{}).Return(testMarketsResponse(), nil)
+			},
+			expectedError: true,
+			errorContains: "volume is given in ZAR but must be in XBT for XBTZAR",
+		},
 		{
 			name: "no pair for create order",
 			requestParams: map[string]any{
@@ -1227,7 +2270,249 @@ func TestHandleCreateOrder(t *testing.T) {
 				assert.NotEmpty(t, textContent)
 				assert.Contains(t, textContent, "Order created successfully!")
 				assert.Contains(t, textContent, "BXMC2SEAS4KF5S2")
+				assert.Contains(t, textContent, `"side"`)
 			}
 		})
 	}
 }
+
+func TestHandleCreateOrderRiskLimits(t *testing.T) {
+	limits, err := config.LoadRiskLimits(func(k string) string {
+		return map[string]string{
+			config.EnvLunoMaxOrderValue:      "ZAR:15000",
+			config.EnvLunoMaxDailyTradeValue: "ZAR:25000",
+		}[k]
+	})
+	require.NoError(t, err)
+
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+	expectOrderBalance(mockClient)
+	mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR", Status: "ACTIVE"}, nil)
+	mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+	cfg := &config.Config{LunoClient: mockClient}
+	cfg.SetSettings(&config.Settings{Limits: limits})
+
+	createOrder := func(volume string) (string, bool) {
+		result, err := HandleCreateOrder(cfg)(context.Background(), createMockRequest(map[string]any{
+			"pair": "XBTZAR", "type": "BUY", "volume": volume, "price": "1000000",
+		}))
+		require.NoError(t, err)
+		return getTextContentFromResult(t, result), result.IsError
+	}
+
+	text, isErr := createOrder("0.02")
+	assert.True(t, isErr)
+	assert.Contains(t, text, "exceeds the maximum order value of 15000 ZAR")
+
+	// A failed order doesn't count towards the daily limit
+	mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr)).Once()
+	_, isErr = createOrder("0.01")
+	assert.True(t, isErr)
+	assert.Zero(t, limits.PlacedToday("")["ZAR"].Sign())
+
+	mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "1"}, nil).Twice()
+	for range 2 {
+		text, isErr = createOrder("0.01")
+		require.False(t, isErr, text)
+	}
+	text, isErr = createOrder("0.01")
+	assert.True(t, isErr)
+	assert.Contains(t, text, "over the daily limit of 25000 ZAR")
+}
+
+func TestTradingPairAllowList(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       func(*config.Config) server.ToolHandlerFunc
+		params        map[string]any
+		mockSetup     func(*sdk.MockLunoClient)
+		errorContains string
+	}{
+		{
+			name:          "create order for a pair that is not allowed",
+			handler:       HandleCreateOrder,
+			params:        map[string]any{"pair": "ETHZAR", "type": "BUY", "volume": "0.1", "price": "40000"},
+			mockSetup:     func(*sdk.MockLunoClient) {},
+			errorContains: "trading ETHZAR is not allowed, this server only trades XBTZAR, XBTEUR",
+		},
+		{
+			name:    "create order matches normalized pairs",
+			handler: HandleCreateOrder,
+			params:  map[string]any{"pair": "btc-zar", "type": "BUY", "volume": "0.01", "price": "1000000"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().Markets(mock.Anything, mock.Anything).Return(testMarketsResponse(), nil)
+				expectOrderBalance(m)
+				m.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR", Status: "ACTIVE"}, nil)
+				m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil)
+				m.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(&luno.PostLimitOrderResponse{OrderId: "1"}, nil)
+			},
+		},
+		{
+			name:    "cancel order for a pair that is not allowed",
+			handler: HandleCancelOrder,
+			params:  map[string]any{"order_id": "12345"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, &luno.GetOrderV3Request{Id: "12345"}).
+					Return(&luno.GetOrderV3Response{OrderId: "12345", Pair: "ETHZAR"}, nil)
+			},
+			errorContains: "Unable to cancel order: trading ETHZAR is not allowed",
+		},
+		{
+			name:    "cancel order when the lookup fails",
+			handler: HandleCancelOrder,
+			params:  map[string]any{"order_id": "12345"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).Return(nil, errors.New(apiErrorStr))
+			},
+			errorContains: "could not look up its pair",
+		},
+		{
+			name:    "cancel order for an allowed pair",
+			handler: HandleCancelOrder,
+			params:  map[string]any{"order_id": "12345"},
+			mockSetup: func(m *sdk.MockLunoClient) {
+				m.EXPECT().GetOrderV3(mock.Anything, mock.Anything).
+					Return(&luno.GetOrderV3Response{OrderId: "12345", Pair: "XBTZAR"}, nil)
+				m.EXPECT().StopOrder(mock.Anything, &luno.StopOrderRequest{OrderId: "12345"}).
+					Return(&luno.StopOrderResponse{Success: true}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := sdk.NewMockLunoClient(t)
+			tc.mockSetup(mockClient)
+			cfg := &config.Config{LunoClient: mockClient}
+			cfg.SetSettings(&config.Settings{AllowedPairs: []string{"XBTZAR", "XBTEUR"}})
+
+			result, err := tc.handler(cfg)(context.Background(), createMockRequest(tc.params))
+			require.NoError(t, err)
+
+			text := getTextContentFromResult(t, result)
+			if tc.errorContains == "" {
+				assert.False(t, result.IsError, text)
+				return
+			}
+			assert.True(t, result.IsError)
+			assert.Contains(t, text, tc.errorContains)
+		})
+	}
+}
+
+func TestHandleSetNote(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestParams  map[string]any
+		nilStore       bool
+		expectedError  bool
+		errorContains  string
+		expectedKind   notes.Kind
+		expectedTarget string
+		expectedNote   string
+	}{
+		{
+			name: "set account note",
+			requestParams: map[string]any{
+				"target_type": "account",
+				"target":      "123456",
+				"note":        "tax savings",
+			},
+			expectedKind:   notes.KindAccount,
+			expectedTarget: "123456",
+			expectedNote:   "tax savings",
+		},
+		{
+			name: "set pair note normalizes pair",
+			requestParams: map[string]any{
+				"target_type": "pair",
+				"target":      "btc-zar",
+				"note":        "long term only",
+			},
+			expectedKind:   notes.KindPair,
+			expectedTarget: "XBTZAR",
+			expectedNote:   "long term only",
+		},
+		{
+			name: "invalid target type",
+			requestParams: map[string]any{
+				"target_type": "wallet",
+				"target":      "123456",
+				"note":        "tax savings",
+			},
+			expectedError: true,
+			errorContains: "unknown note target type",
+		},
+		{
+			name: "missing target",
+			requestParams: map[string]any{
+				"target_type": "account",
+			},
+			expectedError: true,
+			errorContains: "getting target from request",
+		},
+		{
+			name: "notes unavailable",
+			requestParams: map[string]any{
+				"target_type": "account",
+				"target":      "123456",
+			},
+			nilStore:      true,
+			expectedError: true,
+			errorContains: "Notes are not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{LunoClient: sdk.NewMockLunoClient(t)}
+			if !tt.nilStore {
+				cfg.Notes = notes.NewStore()
+			}
+
+			handler := HandleSetNote(cfg)
+			result, err := handler(context.Background(), createMockRequest(tt.requestParams))
+			assert.NoError(t, err)
+
+			if tt.expectedError {
+				assert.True(t, result.IsError)
+				assert.Contains(t, getTextContentFromResult(t, result), tt.errorContains)
+				return
+			}
+
+			assert.False(t, result.IsError)
+			assert.Equal(t, tt.expectedNote, cfg.Notes.Get(tt.expectedKind, tt.expectedTarget))
+		})
+	}
+}
+
+func TestNotesIncludedInReadTools(t *testing.T) {
+	mockClient := sdk.NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{
+		Balance: []luno.AccountBalance{
+			{AccountId: "123", Asset: "XBT"},
+			{AccountId: "456", Asset: "ZAR"},
+		},
+	}, nil)
+	mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
+		Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+
+	cfg := &config.Config{LunoClient: mockClient, Notes: notes.NewStore()}
+	cfg.Notes.Set(notes.KindAccount, "123", "tax savings")
+	cfg.Notes.Set(notes.KindPair, "XBTZAR", "long term only")
+
+	result, err := HandleGetBalances(cfg)(context.Background(), createMockRequest(nil))
+	assert.NoError(t, err)
+	var balances []map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &balances))
+	assert.Equal(t, "tax savings", balances[0]["note"])
+	assert.NotContains(t, balances[1], "note")
+
+	result, err = HandleGetTicker(cfg)(context.Background(), createMockRequest(map[string]any{"pair": "XBTZAR"}))
+	assert.NoError(t, err)
+	var ticker map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(getTextContentFromResult(t, result)), &ticker))
+	assert.Equal(t, "long term only", ticker["note"])
+	assert.Equal(t, "XBTZAR", ticker["pair"])
+}
@@ -0,0 +1,81 @@
+// Package tracing exports OpenTelemetry traces of tool calls and Luno API
+// calls. Traces are only exported when an OTLP endpoint is configured, with
+// the standard OpenTelemetry environment variables.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Standard OpenTelemetry environment variables that configure tracing
+const (
+	// EnvOTLPEndpoint is the OTLP endpoint for all signals
+	EnvOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// EnvOTLPTracesEndpoint is the OTLP endpoint for traces only
+	EnvOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	// EnvSDKDisabled turns OpenTelemetry off when "true"
+	EnvSDKDisabled = "OTEL_SDK_DISABLED"
+)
+
+// Name is the instrumentation name of the server's tracer
+const Name = "github.com/luno/luno-mcp"
+
+// serviceName is the default service.name, which OTEL_SERVICE_NAME overrides
+const serviceName = "luno-mcp"
+
+// Enabled reports whether traces should be exported, which needs an OTLP
+// endpoint and OpenTelemetry not to be disabled
+func Enabled(getenv func(string) string) bool {
+	if strings.EqualFold(strings.TrimSpace(getenv(EnvSDKDisabled)), "true") {
+		return false
+	}
+	return getenv(EnvOTLPEndpoint) != "" || getenv(EnvOTLPTracesEndpoint) != ""
+}
+
+// Setup exports traces over OTLP/HTTP, configured by the standard
+// OTEL_EXPORTER_OTLP_* variables, and continues traces from W3C trace
+// context headers. It returns a function that flushes remaining spans.
+func Setup(ctx context.Context, version string) (shutdown func(context.Context) error, err error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+	// Resource attributes from the environment take precedence
+	res, err := resource.Merge(
+		resource.NewSchemaless(attribute.String("service.name", serviceName), attribute.String("service.version", version)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the server's tracer. It is a no-op until Setup is called.
+func Tracer() trace.Tracer {
+	return otel.Tracer(Name)
+}
+
+// Handler continues the trace in the headers of each request, so that tool
+// calls over HTTP join the trace of the agent that made them
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{name: "not configured", env: map[string]string{}},
+		{name: "endpoint", env: map[string]string{EnvOTLPEndpoint: "http://localhost:4318"}, expected: true},
+		{name: "traces endpoint", env: map[string]string{EnvOTLPTracesEndpoint: "http://localhost:4318/v1/traces"}, expected: true},
+		{name: "disabled", env: map[string]string{EnvOTLPEndpoint: "http://localhost:4318", EnvSDKDisabled: "TRUE"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			getenv := func(k string) string { return tc.env[k] }
+			assert.Equal(t, tc.expected, Enabled(getenv))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	var got trace.SpanContext
+	handler := Handler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = trace.SpanContextFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/message", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, got.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", got.SpanID().String())
+}
+
+func TestSetup(t *testing.T) {
+	var paths []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+	t.Setenv(EnvOTLPEndpoint, collector.URL)
+
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	shutdown, err := Setup(context.Background(), "1.2.3")
+	require.NoError(t, err)
+	_, span := Tracer().Start(context.Background(), "tool get_ticker")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Equal(t, []string{"/v1/traces"}, paths, "spans are flushed on shutdown")
+}
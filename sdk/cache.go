@@ -0,0 +1,187 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+)
+
+// compile-time check that *CachingClient implements our interface
+var _ LunoClient = (*CachingClient)(nil)
+
+// maxCacheEntries is the size above which expired entries are pruned
+const maxCacheEntries = 256
+
+// CacheTTLs are how long responses of each cached method are reused.
+// The Ticker TTL applies to both GetTicker and GetTickers.
+// A zero TTL disables caching for that method.
+type CacheTTLs struct {
+	Ticker    time.Duration
+	OrderBook time.Duration
+	Trades    time.Duration
+	// Markets is how long the list of markets used to validate pairs is
+	// reused. Markets are rarely added or suspended, so it is much longer.
+	Markets time.Duration
+}
+
+// DefaultCacheTTLs are short enough that market data stays fresh for agents
+// while absorbing rapid repeated tool calls
+var DefaultCacheTTLs = CacheTTLs{
+	Ticker:    2 * time.Second,
+	OrderBook: 2 * time.Second,
+	Trades:    5 * time.Second,
+	Markets:   time.Minute,
+}
+
+// CacheStats counts cache lookups
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// Cache stores recent market data responses
+type Cache struct {
+	ttls CacheTTLs
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	stats   map[string]CacheStats
+}
+
+// NewCache creates an empty cache with the given TTLs
+func NewCache(ttls CacheTTLs) *Cache {
+	return &Cache{
+		ttls:    ttls,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+		stats:   make(map[string]CacheStats),
+	}
+}
+
+// TTLs returns the configured TTLs
+func (c *Cache) TTLs() CacheTTLs {
+	return c.ttls
+}
+
+// Stats returns hit and miss counts per cached method
+func (c *Cache) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make(map[string]CacheStats, len(c.stats))
+	for k, v := range c.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+func (c *Cache) get(method, key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats[method]
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		stats.Misses++
+		c.stats[method] = stats
+		return nil, false
+	}
+	stats.Hits++
+	c.stats[method] = stats
+	return e.value, true
+}
+
+func (c *Cache) set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(ttl)}
+}
+
+// cached returns a cached response for the request if there is a fresh one,
+// otherwise it calls fn and caches a successful response
+func cached[T any](c *Cache, method string, ttl time.Duration, req any, fn func() (T, error)) (T, error) {
+	if c == nil || ttl <= 0 {
+		return fn()
+	}
+	key := fmt.Sprintf("%s:%+v", method, req)
+	if v, ok := c.get(method, key); ok {
+		return v.(T), nil
+	}
+	res, err := fn()
+	if err != nil {
+		return res, err
+	}
+	c.set(key, res, ttl)
+	return res, nil
+}
+
+// CachingClient wraps a LunoClient, reusing recent public market data
+// responses. Cached responses are shared between callers and must not be
+// modified. All other methods are passed through.
+type CachingClient struct {
+	LunoClient
+	cache *Cache
+}
+
+// NewCachingClient wraps next with the cache. A nil cache disables caching.
+func NewCachingClient(next LunoClient, cache *Cache) *CachingClient {
+	return &CachingClient{LunoClient: next, cache: cache}
+}
+
+// GetTicker implements LunoClient
+func (c *CachingClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	return cached(c.cache, "GetTicker", c.ttls().Ticker, req, func() (*luno.GetTickerResponse, error) {
+		return c.LunoClient.GetTicker(ctx, req)
+	})
+}
+
+// GetTickers implements LunoClient. It shares the ticker TTL.
+func (c *CachingClient) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	return cached(c.cache, "GetTickers", c.ttls().Ticker, req, func() (*luno.GetTickersResponse, error) {
+		return c.LunoClient.GetTickers(ctx, req)
+	})
+}
+
+// GetOrderBook implements LunoClient
+func (c *CachingClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	return cached(c.cache, "GetOrderBook", c.ttls().OrderBook, req, func() (*luno.GetOrderBookResponse, error) {
+		return c.LunoClient.GetOrderBook(ctx, req)
+	})
+}
+
+// ListTrades implements LunoClient
+func (c *CachingClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	return cached(c.cache, "ListTrades", c.ttls().Trades, req, func() (*luno.ListTradesResponse, error) {
+		return c.LunoClient.ListTrades(ctx, req)
+	})
+}
+
+// Markets implements LunoClient. Pair validation calls it for most tools, so
+// the market list is refreshed once its TTL has passed rather than every call.
+func (c *CachingClient) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	return cached(c.cache, "Markets", c.ttls().Markets, req, func() (*luno.MarketsResponse, error) {
+		return c.LunoClient.Markets(ctx, req)
+	})
+}
+
+func (c *CachingClient) ttls() CacheTTLs {
+	if c.cache == nil {
+		return CacheTTLs{}
+	}
+	return c.cache.ttls
+}
@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingClientGetTicker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		ttl             time.Duration
+		secondPair      string
+		advance         time.Duration
+		firstErr        error
+		expectedFetches int
+		expectedHits    uint64
+		expectedMisses  uint64
+	}{
+		{name: "second call is cached", ttl: 2 * time.Second, secondPair: "XBTZAR", expectedFetches: 1, expectedHits: 1, expectedMisses: 1},
+		{name: "expired entry is refetched", ttl: 2 * time.Second, secondPair: "XBTZAR", advance: 2 * time.Second, expectedFetches: 2, expectedMisses: 2},
+		{name: "different request is not shared", ttl: 2 * time.Second, secondPair: "ETHZAR", expectedFetches: 2, expectedMisses: 2},
+		{name: "errors are not cached", ttl: 2 * time.Second, secondPair: "XBTZAR", firstErr: errors.New("boom"), expectedFetches: 1, expectedMisses: 2},
+		{name: "zero ttl disables caching", ttl: 0, secondPair: "XBTZAR", expectedFetches: 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := NewMockLunoClient(t)
+			if tc.firstErr != nil {
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(nil, tc.firstErr).Once()
+			}
+			mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).
+				RunAndReturn(func(_ context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+					return &luno.GetTickerResponse{Pair: req.Pair}, nil
+				}).Times(tc.expectedFetches)
+
+			cache := NewCache(CacheTTLs{Ticker: tc.ttl})
+			now := start
+			cache.now = func() time.Time { return now }
+			c := NewCachingClient(mockClient, cache)
+
+			_, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+			if tc.firstErr != nil {
+				require.ErrorIs(t, err, tc.firstErr)
+			}
+			now = now.Add(tc.advance)
+			res, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: tc.secondPair})
+			require.NoError(t, err)
+			assert.Equal(t, tc.secondPair, res.Pair)
+
+			stats := cache.Stats()["GetTicker"]
+			assert.Equal(t, tc.expectedHits, stats.Hits)
+			assert.Equal(t, tc.expectedMisses, stats.Misses)
+		})
+	}
+}
+
+func TestCachingClientPassesThrough(t *testing.T) {
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{}, nil).Times(2)
+
+	c := NewCachingClient(mockClient, NewCache(DefaultCacheTTLs))
+	for i := 0; i < 2; i++ {
+		_, err := c.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+		require.NoError(t, err)
+	}
+}
+
+func TestCachingClientTickersOrderBookAndTrades(t *testing.T) {
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().GetTickers(mock.Anything, mock.Anything).Return(&luno.GetTickersResponse{}, nil).Once()
+	mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{}, nil).Once()
+	mockClient.EXPECT().ListTrades(mock.Anything, mock.Anything).Return(&luno.ListTradesResponse{}, nil).Once()
+
+	cache := NewCache(DefaultCacheTTLs)
+	c := NewCachingClient(mockClient, cache)
+	for i := 0; i < 3; i++ {
+		_, err := c.GetTickers(context.Background(), &luno.GetTickersRequest{})
+		require.NoError(t, err)
+		_, err = c.GetOrderBook(context.Background(), &luno.GetOrderBookRequest{Pair: "XBTZAR"})
+		require.NoError(t, err)
+		_, err = c.ListTrades(context.Background(), &luno.ListTradesRequest{Pair: "XBTZAR"})
+		require.NoError(t, err)
+	}
+
+	stats := cache.Stats()
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, stats["GetTickers"])
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, stats["GetOrderBook"])
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, stats["ListTrades"])
+}
+
+func TestCachingClientMarketsRefresh(t *testing.T) {
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().Markets(mock.Anything, mock.Anything).Return(&luno.MarketsResponse{}, nil).Times(2)
+
+	cache := NewCache(DefaultCacheTTLs)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	c := NewCachingClient(mockClient, cache)
+
+	// Pair validation within the TTL reuses the list, after it the list is fetched again
+	for _, advance := range []time.Duration{0, 30 * time.Second, DefaultCacheTTLs.Markets} {
+		now = now.Add(advance)
+		_, err := c.Markets(context.Background(), &luno.MarketsRequest{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, cache.Stats()["Markets"])
+}
@@ -0,0 +1,85 @@
+package sdk
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ClockSkewThreshold is the skew above which authentication problems are
+// likely to be caused by the local clock. Date headers only have second
+// precision, so smaller differences are not reliable.
+const ClockSkewThreshold = 30 * time.Second
+
+// ClockSkewTracker is an http.RoundTripper that compares the Date header of
+// every Luno API response against the local clock
+type ClockSkewTracker struct {
+	next http.RoundTripper
+	now  func() time.Time
+
+	mu   sync.Mutex
+	skew time.Duration
+	seen bool
+}
+
+// NewClockSkewTracker wraps next, or http.DefaultTransport if next is nil
+func NewClockSkewTracker(next http.RoundTripper) *ClockSkewTracker {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &ClockSkewTracker{next: next, now: time.Now}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ClockSkewTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := t.now()
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	serverTime, parseErr := http.ParseTime(res.Header.Get("Date"))
+	if parseErr != nil {
+		return res, nil
+	}
+
+	// Compare against the midpoint of the request to discount network latency
+	received := t.now()
+	local := sent.Add(received.Sub(sent) / 2)
+
+	t.mu.Lock()
+	t.skew = serverTime.Sub(local)
+	t.seen = true
+	t.mu.Unlock()
+
+	return res, nil
+}
+
+// Skew returns how far the Luno server clock is ahead of the local clock,
+// as of the most recent response. ok is false if no response has been seen.
+func (t *ClockSkewTracker) Skew() (skew time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.skew, t.seen
+}
+
+// Hint returns guidance for the user if the local clock is significantly off,
+// or an empty string otherwise
+func (t *ClockSkewTracker) Hint() string {
+	skew, ok := t.Skew()
+	if !ok {
+		return ""
+	}
+	direction := "behind"
+	if skew < 0 {
+		skew = -skew
+		direction = "ahead of"
+	}
+	if skew < ClockSkewThreshold {
+		return ""
+	}
+	return fmt.Sprintf("Your system clock is off by %ds (%s Luno's servers). "+
+		"Authentication can fail when the clock is wrong, please sync your system clock and try again.",
+		int(skew.Round(time.Second).Seconds()), direction)
+}
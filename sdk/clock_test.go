@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClockSkewTracker(t *testing.T) {
+	local := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		dateHeader   string
+		roundTripErr error
+		expectedSeen bool
+		expectedSkew time.Duration
+		hintContains string
+	}{
+		{
+			name:         "clock in sync",
+			dateHeader:   local.Format(http.TimeFormat),
+			expectedSeen: true,
+		},
+		{
+			name:         "small skew has no hint",
+			dateHeader:   local.Add(5 * time.Second).Format(http.TimeFormat),
+			expectedSeen: true,
+			expectedSkew: 5 * time.Second,
+		},
+		{
+			name:         "local clock behind",
+			dateHeader:   local.Add(2 * time.Minute).Format(http.TimeFormat),
+			expectedSeen: true,
+			expectedSkew: 2 * time.Minute,
+			hintContains: "off by 120s (behind Luno's servers)",
+		},
+		{
+			name:         "local clock ahead",
+			dateHeader:   local.Add(-45 * time.Second).Format(http.TimeFormat),
+			expectedSeen: true,
+			expectedSkew: -45 * time.Second,
+			hintContains: "off by 45s (ahead of Luno's servers)",
+		},
+		{
+			name:       "missing date header",
+			dateHeader: "",
+		},
+		{
+			name:         "transport error",
+			roundTripErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := NewClockSkewTracker(roundTripFunc(func(*http.Request) (*http.Response, error) {
+				if tc.roundTripErr != nil {
+					return nil, tc.roundTripErr
+				}
+				res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+				if tc.dateHeader != "" {
+					res.Header.Set("Date", tc.dateHeader)
+				}
+				return res, nil
+			}))
+			tracker.now = func() time.Time { return local }
+
+			req, err := http.NewRequest(http.MethodGet, "https://api.luno.com/api/1/tickers", nil)
+			require.NoError(t, err)
+			_, err = tracker.RoundTrip(req)
+			if tc.roundTripErr != nil {
+				require.ErrorIs(t, err, tc.roundTripErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			skew, seen := tracker.Skew()
+			assert.Equal(t, tc.expectedSeen, seen)
+			assert.Equal(t, tc.expectedSkew, skew)
+			if tc.hintContains == "" {
+				assert.Empty(t, tracker.Hint())
+				return
+			}
+			assert.Contains(t, tracker.Hint(), tc.hintContains)
+		})
+	}
+}
@@ -0,0 +1,334 @@
+// Package fake is an offline Luno backend for integration tests, demos and
+// client development. Market data is generated from a seed, so the same
+// seed gives the same prices at the same time, and trading is simulated
+// against that data by an sdk.PaperClient.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/luno/luno-mcp/sdk"
+)
+
+// compile-time check that *Client implements our interface
+var _ sdk.LunoClient = (*Client)(nil)
+
+// ErrNotSupported is returned by calls the fake backend doesn't implement
+var ErrNotSupported = errors.New("not supported by the fake backend")
+
+// DefaultSeed is the seed used when none is given
+const DefaultSeed = 1
+
+// DefaultBalances are the starting balances when none are given
+var DefaultBalances = map[string]decimal.Decimal{
+	"ZAR": decimal.NewFromInt64(100000),
+	"EUR": decimal.NewFromInt64(5000),
+	"XBT": decimal.NewFromFloat64(0.1, 8),
+	"ETH": decimal.NewFromInt64(2),
+}
+
+const (
+	// bookDepth is how many levels each side of an order book has
+	bookDepth = 20
+	// tradeInterval is how often a fake market trades
+	tradeInterval = time.Minute
+	// maxTrades is the most public trades returned at once, as by Luno
+	maxTrades = 100
+	// maxCandles is the most candles returned at once, as by Luno
+	maxCandles = 1000
+)
+
+// market is a fake market and the price it moves around
+type market struct {
+	info  luno.MarketInfo
+	price float64
+}
+
+var markets = []market{
+	{
+		info: luno.MarketInfo{
+			MarketId: "XBTZAR", BaseCurrency: "XBT", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive,
+			PriceScale: 0, VolumeScale: 6, FeeScale: 8,
+			MinPrice: decimal.NewFromInt64(100), MaxPrice: decimal.NewFromInt64(100000000),
+			MinVolume: decimal.NewFromFloat64(0.0005, 4), MaxVolume: decimal.NewFromInt64(100),
+		},
+		price: 1200000,
+	},
+	{
+		info: luno.MarketInfo{
+			MarketId: "ETHZAR", BaseCurrency: "ETH", CounterCurrency: "ZAR", TradingStatus: luno.TradingStatusActive,
+			PriceScale: 0, VolumeScale: 4, FeeScale: 8,
+			MinPrice: decimal.NewFromInt64(10), MaxPrice: decimal.NewFromInt64(10000000),
+			MinVolume: decimal.NewFromFloat64(0.005, 3), MaxVolume: decimal.NewFromInt64(1000),
+		},
+		price: 60000,
+	},
+	{
+		info: luno.MarketInfo{
+			MarketId: "XBTEUR", BaseCurrency: "XBT", CounterCurrency: "EUR", TradingStatus: luno.TradingStatusActive,
+			PriceScale: 2, VolumeScale: 6, FeeScale: 8,
+			MinPrice: decimal.NewFromInt64(10), MaxPrice: decimal.NewFromInt64(10000000),
+			MinVolume: decimal.NewFromFloat64(0.0005, 4), MaxVolume: decimal.NewFromInt64(100),
+		},
+		price: 60000,
+	},
+}
+
+// Client is an offline LunoClient. Balances, orders and the user's trades
+// are kept by a paper trading portfolio that fills against the fake order
+// books. Funds can't be moved and no new accounts can be created.
+type Client struct {
+	*sdk.PaperClient
+}
+
+// New creates a fake backend whose market data is generated from seed and
+// whose portfolio starts with balances
+func New(seed int64, balances map[string]decimal.Decimal) *Client {
+	data := &marketData{seed: uint64(seed), now: time.Now}
+	return &Client{PaperClient: sdk.NewPaperClient(data, balances)}
+}
+
+// CreateAccount implements LunoClient
+func (c *Client) CreateAccount(context.Context, *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return nil, fmt.Errorf("creating accounts is %w", ErrNotSupported)
+}
+
+// Move implements LunoClient
+func (c *Client) Move(context.Context, *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return nil, fmt.Errorf("moving funds is %w", ErrNotSupported)
+}
+
+// GetMove implements LunoClient
+func (c *Client) GetMove(context.Context, *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return nil, fmt.Errorf("moving funds is %w", ErrNotSupported)
+}
+
+// GetFundingAddress implements LunoClient with a made up address that is
+// the same for every call
+func (c *Client) GetFundingAddress(_ context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	asset := strings.ToUpper(req.Asset)
+	return &luno.GetFundingAddressResponse{
+		Address:          "fake-" + strings.ToLower(asset) + "-address",
+		Asset:            asset,
+		Name:             "Fake " + asset,
+		ReceiveFee:       decimal.Zero(),
+		TotalReceived:    decimal.Zero(),
+		TotalUnconfirmed: decimal.Zero(),
+	}, nil
+}
+
+// marketData generates market data. The account calls of LunoClient are
+// never made on it, since the paper client and Client answer them, so it
+// embeds a nil LunoClient for them.
+type marketData struct {
+	sdk.LunoClient
+	seed uint64
+	now  func() time.Time
+}
+
+// Markets implements LunoClient
+func (d *marketData) Markets(_ context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	res := &luno.MarketsResponse{}
+	for _, m := range markets {
+		if len(req.Pair) == 0 || slices.Contains(req.Pair, m.info.MarketId) {
+			res.Markets = append(res.Markets, m.info)
+		}
+	}
+	return res, nil
+}
+
+// GetTicker implements LunoClient
+func (d *marketData) GetTicker(_ context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	m, err := findMarket(req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	t := d.ticker(m)
+	return &luno.GetTickerResponse{
+		Ask:                 t.Ask,
+		Bid:                 t.Bid,
+		LastTrade:           t.LastTrade,
+		Pair:                t.Pair,
+		Rolling24HourVolume: t.Rolling24HourVolume,
+		Status:              t.Status,
+		Timestamp:           t.Timestamp,
+	}, nil
+}
+
+// GetTickers implements LunoClient
+func (d *marketData) GetTickers(_ context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	res := &luno.GetTickersResponse{}
+	for _, m := range markets {
+		if len(req.Pair) == 0 || slices.Contains(req.Pair, m.info.MarketId) {
+			res.Tickers = append(res.Tickers, d.ticker(m))
+		}
+	}
+	return res, nil
+}
+
+// GetOrderBook implements LunoClient. The book changes once a minute, as
+// the price moves.
+func (d *marketData) GetOrderBook(_ context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	m, err := findMarket(req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	at := d.now().Truncate(tradeInterval)
+	mid := d.priceAt(m, at)
+	rng := d.rand(m.info.MarketId, "book", at.Unix())
+
+	res := &luno.GetOrderBookResponse{Timestamp: at.UnixMilli()}
+	for i := range bookDepth {
+		// Levels are 0.05% apart, starting 0.05% either side of the price
+		offset := 0.0005 * float64(i+1)
+		res.Asks = append(res.Asks, luno.OrderBookEntry{
+			Price:  priceDecimal(m, mid*(1+offset)),
+			Volume: volumeDecimal(m, d.levelVolume(m, rng)),
+		})
+		res.Bids = append(res.Bids, luno.OrderBookEntry{
+			Price:  priceDecimal(m, mid*(1-offset)),
+			Volume: volumeDecimal(m, d.levelVolume(m, rng)),
+		})
+	}
+	return res, nil
+}
+
+// ListTrades implements LunoClient, newest first. The market trades once a
+// minute, and trades from the last day are returned.
+func (d *marketData) ListTrades(_ context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	m, err := findMarket(req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	now := d.now()
+	since := now.Add(-24 * time.Hour)
+	if s := time.Time(req.Since); s.After(since) {
+		since = s
+	}
+
+	res := &luno.ListTradesResponse{}
+	for at := now.Truncate(tradeInterval); !at.Before(since) && len(res.Trades) < maxTrades; at = at.Add(-tradeInterval) {
+		rng := d.rand(m.info.MarketId, "trade", at.Unix())
+		res.Trades = append(res.Trades, luno.PublicTrade{
+			IsBuy:     rng.IntN(2) == 0,
+			Price:     priceDecimal(m, d.priceAt(m, at)),
+			Sequence:  at.Unix() / int64(tradeInterval/time.Second),
+			Timestamp: luno.Time(at),
+			Volume:    volumeDecimal(m, d.levelVolume(m, rng)/4),
+		})
+	}
+	return res, nil
+}
+
+// GetCandles implements LunoClient
+func (d *marketData) GetCandles(_ context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	m, err := findMarket(req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	if req.Duration <= 0 {
+		return nil, fmt.Errorf("invalid candle duration %d", req.Duration)
+	}
+	duration := time.Duration(req.Duration) * time.Second
+	now := d.now()
+
+	res := &luno.GetCandlesResponse{Pair: m.info.MarketId, Duration: req.Duration}
+	for start := time.Time(req.Since).Truncate(duration); start.Before(now) && len(res.Candles) < maxCandles; start = start.Add(duration) {
+		open, closing := d.priceAt(m, start), d.priceAt(m, start.Add(duration))
+		rng := d.rand(m.info.MarketId, "candle", start.Unix())
+		high := max(open, closing) * (1 + rng.Float64()*0.002)
+		low := min(open, closing) * (1 - rng.Float64()*0.002)
+		res.Candles = append(res.Candles, luno.Candle{
+			Open:      priceDecimal(m, open),
+			Close:     priceDecimal(m, closing),
+			High:      priceDecimal(m, high),
+			Low:       priceDecimal(m, low),
+			Timestamp: luno.Time(start),
+			Volume:    volumeDecimal(m, d.levelVolume(m, rng)*duration.Hours()),
+		})
+	}
+	return res, nil
+}
+
+// GetFeeInfo implements LunoClient with Luno's standard fees
+func (d *marketData) GetFeeInfo(_ context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	if _, err := findMarket(req.Pair); err != nil {
+		return nil, err
+	}
+	return &luno.GetFeeInfoResponse{MakerFee: "0.0000", TakerFee: "0.0010", ThirtyDayVolume: "0"}, nil
+}
+
+// Validate implements LunoClient, accepting every address
+func (d *marketData) Validate(context.Context, *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	return &luno.ValidateResponse{Success: true}, nil
+}
+
+// ticker is the current ticker of a market
+func (d *marketData) ticker(m market) luno.Ticker {
+	at := d.now().Truncate(tradeInterval)
+	mid := d.priceAt(m, at)
+	rng := d.rand(m.info.MarketId, "volume", at.Unix())
+	return luno.Ticker{
+		Ask:                 priceDecimal(m, mid*1.0005),
+		Bid:                 priceDecimal(m, mid*0.9995),
+		LastTrade:           priceDecimal(m, mid),
+		Pair:                m.info.MarketId,
+		Rolling24HourVolume: volumeDecimal(m, d.levelVolume(m, rng)*24),
+		Status:              luno.StatusActive,
+		Timestamp:           luno.Time(at),
+	}
+}
+
+// priceAt is the price of a market at a time: a slow swing of up to 5%
+// over three days, plus up to 0.2% of noise that changes every minute
+func (d *marketData) priceAt(m market, t time.Time) float64 {
+	t = t.Truncate(tradeInterval)
+	days := float64(t.Unix()) / (24 * 60 * 60)
+	noise := d.rand(m.info.MarketId, "price", t.Unix()).Float64()*0.004 - 0.002
+	return m.price * (1 + 0.05*math.Sin(2*math.Pi*days/3+float64(d.seed%7))) * (1 + noise)
+}
+
+// levelVolume is a random volume worth between 500 and 10500 of the
+// market's counter currency
+func (d *marketData) levelVolume(m market, rng *rand.Rand) float64 {
+	return (500 + rng.Float64()*10000) / m.price
+}
+
+// rand returns a generator that gives the same numbers for the same seed,
+// pair, purpose and time
+func (d *marketData) rand(pair, purpose string, unix int64) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%s/%d", pair, purpose, unix)
+	return rand.New(rand.NewPCG(d.seed, h.Sum64()))
+}
+
+func findMarket(pair string) (market, error) {
+	for _, m := range markets {
+		if strings.EqualFold(m.info.MarketId, pair) {
+			return m, nil
+		}
+	}
+	return market{}, fmt.Errorf("unknown pair %s", pair)
+}
+
+func priceDecimal(m market, f float64) decimal.Decimal {
+	return decimal.NewFromFloat64(f, int(m.info.PriceScale))
+}
+
+func volumeDecimal(m market, f float64) decimal.Decimal {
+	v := decimal.NewFromFloat64(f, int(m.info.VolumeScale))
+	if v.Cmp(m.info.MinVolume) < 0 {
+		return m.info.MinVolume.ToScale(int(m.info.VolumeScale))
+	}
+	return v
+}
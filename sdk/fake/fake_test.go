@@ -0,0 +1,102 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2025, 3, 14, 12, 30, 15, 0, time.UTC)
+
+func newTestClient(seed int64) *Client {
+	c := New(seed, DefaultBalances)
+	c.LunoClient.(*marketData).now = func() time.Time { return testNow }
+	return c
+}
+
+func TestMarketDataIsDeterministic(t *testing.T) {
+	ctx := context.Background()
+	req := &luno.GetOrderBookRequest{Pair: "XBTZAR"}
+
+	first, err := newTestClient(7).GetOrderBook(ctx, req)
+	require.NoError(t, err)
+	second, err := newTestClient(7).GetOrderBook(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "the same seed gives the same book")
+
+	other, err := newTestClient(8).GetOrderBook(ctx, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other, "another seed gives another book")
+
+	require.Len(t, first.Asks, bookDepth)
+	require.Len(t, first.Bids, bookDepth)
+	assert.Positive(t, first.Asks[0].Price.Cmp(first.Bids[0].Price), "the book isn't crossed")
+	assert.Positive(t, first.Asks[1].Price.Cmp(first.Asks[0].Price), "asks are best first")
+
+	ticker, err := newTestClient(7).GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+	require.NoError(t, err)
+	assert.Equal(t, luno.StatusActive, ticker.Status)
+	assert.Positive(t, ticker.Ask.Cmp(ticker.Bid))
+}
+
+func TestMarketDataHistory(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(DefaultSeed)
+
+	trades, err := c.ListTrades(ctx, &luno.ListTradesRequest{Pair: "ETHZAR", Since: luno.Time(testNow.Add(-10 * time.Minute))})
+	require.NoError(t, err)
+	require.Len(t, trades.Trades, 10)
+	assert.Greater(t, trades.Trades[0].Sequence, trades.Trades[1].Sequence, "newest first")
+
+	trades, err = c.ListTrades(ctx, &luno.ListTradesRequest{Pair: "ETHZAR"})
+	require.NoError(t, err)
+	assert.Len(t, trades.Trades, maxTrades)
+
+	candles, err := c.GetCandles(ctx, &luno.GetCandlesRequest{Pair: "XBTEUR", Duration: 3600, Since: luno.Time(testNow.Add(-24 * time.Hour))})
+	require.NoError(t, err)
+	require.Len(t, candles.Candles, 25)
+	for _, candle := range candles.Candles {
+		assert.LessOrEqual(t, candle.Low.Cmp(candle.Open), 0)
+		assert.GreaterOrEqual(t, candle.High.Cmp(candle.Close), 0)
+	}
+
+	_, err = c.GetTicker(ctx, &luno.GetTickerRequest{Pair: "DOGEZAR"})
+	assert.ErrorContains(t, err, "unknown pair DOGEZAR")
+}
+
+func TestClientTrades(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(DefaultSeed)
+
+	book, err := c.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: "XBTZAR"})
+	require.NoError(t, err)
+	res, err := c.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{
+		Pair:   "XBTZAR",
+		Type:   luno.OrderTypeBid,
+		Price:  book.Asks[0].Price,
+		Volume: book.Asks[0].Volume,
+	})
+	require.NoError(t, err)
+
+	order, err := c.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: res.OrderId})
+	require.NoError(t, err)
+	assert.Equal(t, luno.StatusComplete, order.Status)
+	assert.Zero(t, order.Base.Cmp(book.Asks[0].Volume))
+
+	balances, err := c.GetBalances(ctx, &luno.GetBalancesRequest{Assets: []string{"XBT"}})
+	require.NoError(t, err)
+	require.Len(t, balances.Balance, 1)
+	assert.Positive(t, balances.Balance[0].Balance.Cmp(decimal.NewFromFloat64(0.1, 8)))
+
+	_, err = c.Move(ctx, &luno.MoveRequest{})
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	address, err := c.GetFundingAddress(ctx, &luno.GetFundingAddressRequest{Asset: "xbt"})
+	require.NoError(t, err)
+	assert.Equal(t, "fake-xbt-address", address.Address)
+}
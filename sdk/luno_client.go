@@ -13,11 +13,23 @@ var _ LunoClient = (*luno.Client)(nil)
 // This interface allows us to mock the Luno client for testing
 type LunoClient interface {
 	GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error)
+	CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error)
+	Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error)
+	GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error)
 	GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error)
+	GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error)
 	GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error)
+	GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error)
+	GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error)
+	GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error)
+	Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error)
+	GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error)
 	PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error)
 	StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error)
 	ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error)
 	ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error)
+	ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error)
 	ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error)
+	ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error)
+	Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error)
 }
@@ -39,27 +39,775 @@ func (_m *MockLunoClient) EXPECT() *MockLunoClient_Expecter {
 	return &MockLunoClient_Expecter{mock: &_m.Mock}
 }
 
+// CreateAccount provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccount")
+	}
+
+	var r0 *luno.CreateAccountResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.CreateAccountRequest) *luno.CreateAccountResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.CreateAccountResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.CreateAccountRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_CreateAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAccount'
+type MockLunoClient_CreateAccount_Call struct {
+	*mock.Call
+}
+
+// CreateAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.CreateAccountRequest
+func (_e *MockLunoClient_Expecter) CreateAccount(ctx interface{}, req interface{}) *MockLunoClient_CreateAccount_Call {
+	return &MockLunoClient_CreateAccount_Call{Call: _e.mock.On("CreateAccount", ctx, req)}
+}
+
+func (_c *MockLunoClient_CreateAccount_Call) Run(run func(ctx context.Context, req *luno.CreateAccountRequest)) *MockLunoClient_CreateAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.CreateAccountRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.CreateAccountRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_CreateAccount_Call) Return(createAccountResponse *luno.CreateAccountResponse, err error) *MockLunoClient_CreateAccount_Call {
+	_c.Call.Return(createAccountResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_CreateAccount_Call) RunAndReturn(run func(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error)) *MockLunoClient_CreateAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBalances provides a mock function for the type MockLunoClient
 func (_mock *MockLunoClient) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBalances")
+		panic("no return value specified for GetBalances")
+	}
+
+	var r0 *luno.GetBalancesResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetBalancesRequest) *luno.GetBalancesResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetBalancesResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetBalancesRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalances'
+type MockLunoClient_GetBalances_Call struct {
+	*mock.Call
+}
+
+// GetBalances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetBalancesRequest
+func (_e *MockLunoClient_Expecter) GetBalances(ctx interface{}, req interface{}) *MockLunoClient_GetBalances_Call {
+	return &MockLunoClient_GetBalances_Call{Call: _e.mock.On("GetBalances", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetBalances_Call) Run(run func(ctx context.Context, req *luno.GetBalancesRequest)) *MockLunoClient_GetBalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetBalancesRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetBalancesRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetBalances_Call) Return(getBalancesResponse *luno.GetBalancesResponse, err error) *MockLunoClient_GetBalances_Call {
+	_c.Call.Return(getBalancesResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetBalances_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error)) *MockLunoClient_GetBalances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCandles provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCandles")
+	}
+
+	var r0 *luno.GetCandlesResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetCandlesRequest) *luno.GetCandlesResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetCandlesResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetCandlesRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetCandles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCandles'
+type MockLunoClient_GetCandles_Call struct {
+	*mock.Call
+}
+
+// GetCandles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetCandlesRequest
+func (_e *MockLunoClient_Expecter) GetCandles(ctx interface{}, req interface{}) *MockLunoClient_GetCandles_Call {
+	return &MockLunoClient_GetCandles_Call{Call: _e.mock.On("GetCandles", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetCandles_Call) Run(run func(ctx context.Context, req *luno.GetCandlesRequest)) *MockLunoClient_GetCandles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetCandlesRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetCandlesRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetCandles_Call) Return(getCandlesResponse *luno.GetCandlesResponse, err error) *MockLunoClient_GetCandles_Call {
+	_c.Call.Return(getCandlesResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetCandles_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error)) *MockLunoClient_GetCandles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFeeInfo provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeeInfo")
+	}
+
+	var r0 *luno.GetFeeInfoResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetFeeInfoRequest) *luno.GetFeeInfoResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetFeeInfoResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetFeeInfoRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetFeeInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFeeInfo'
+type MockLunoClient_GetFeeInfo_Call struct {
+	*mock.Call
+}
+
+// GetFeeInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetFeeInfoRequest
+func (_e *MockLunoClient_Expecter) GetFeeInfo(ctx interface{}, req interface{}) *MockLunoClient_GetFeeInfo_Call {
+	return &MockLunoClient_GetFeeInfo_Call{Call: _e.mock.On("GetFeeInfo", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetFeeInfo_Call) Run(run func(ctx context.Context, req *luno.GetFeeInfoRequest)) *MockLunoClient_GetFeeInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetFeeInfoRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetFeeInfoRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetFeeInfo_Call) Return(getFeeInfoResponse *luno.GetFeeInfoResponse, err error) *MockLunoClient_GetFeeInfo_Call {
+	_c.Call.Return(getFeeInfoResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetFeeInfo_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error)) *MockLunoClient_GetFeeInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFundingAddress provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFundingAddress")
+	}
+
+	var r0 *luno.GetFundingAddressResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetFundingAddressRequest) *luno.GetFundingAddressResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetFundingAddressResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetFundingAddressRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetFundingAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFundingAddress'
+type MockLunoClient_GetFundingAddress_Call struct {
+	*mock.Call
+}
+
+// GetFundingAddress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetFundingAddressRequest
+func (_e *MockLunoClient_Expecter) GetFundingAddress(ctx interface{}, req interface{}) *MockLunoClient_GetFundingAddress_Call {
+	return &MockLunoClient_GetFundingAddress_Call{Call: _e.mock.On("GetFundingAddress", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetFundingAddress_Call) Run(run func(ctx context.Context, req *luno.GetFundingAddressRequest)) *MockLunoClient_GetFundingAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetFundingAddressRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetFundingAddressRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetFundingAddress_Call) Return(getFundingAddressResponse *luno.GetFundingAddressResponse, err error) *MockLunoClient_GetFundingAddress_Call {
+	_c.Call.Return(getFundingAddressResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetFundingAddress_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error)) *MockLunoClient_GetFundingAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMove provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMove")
+	}
+
+	var r0 *luno.GetMoveResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetMoveRequest) (*luno.GetMoveResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetMoveRequest) *luno.GetMoveResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetMoveResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetMoveRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetMove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMove'
+type MockLunoClient_GetMove_Call struct {
+	*mock.Call
+}
+
+// GetMove is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetMoveRequest
+func (_e *MockLunoClient_Expecter) GetMove(ctx interface{}, req interface{}) *MockLunoClient_GetMove_Call {
+	return &MockLunoClient_GetMove_Call{Call: _e.mock.On("GetMove", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetMove_Call) Run(run func(ctx context.Context, req *luno.GetMoveRequest)) *MockLunoClient_GetMove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetMoveRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetMoveRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetMove_Call) Return(getMoveResponse *luno.GetMoveResponse, err error) *MockLunoClient_GetMove_Call {
+	_c.Call.Return(getMoveResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetMove_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error)) *MockLunoClient_GetMove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrderBook provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderBook")
+	}
+
+	var r0 *luno.GetOrderBookResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetOrderBookRequest) *luno.GetOrderBookResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetOrderBookResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetOrderBookRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetOrderBook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderBook'
+type MockLunoClient_GetOrderBook_Call struct {
+	*mock.Call
+}
+
+// GetOrderBook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetOrderBookRequest
+func (_e *MockLunoClient_Expecter) GetOrderBook(ctx interface{}, req interface{}) *MockLunoClient_GetOrderBook_Call {
+	return &MockLunoClient_GetOrderBook_Call{Call: _e.mock.On("GetOrderBook", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetOrderBook_Call) Run(run func(ctx context.Context, req *luno.GetOrderBookRequest)) *MockLunoClient_GetOrderBook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetOrderBookRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetOrderBookRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetOrderBook_Call) Return(getOrderBookResponse *luno.GetOrderBookResponse, err error) *MockLunoClient_GetOrderBook_Call {
+	_c.Call.Return(getOrderBookResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetOrderBook_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error)) *MockLunoClient_GetOrderBook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrderV3 provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderV3")
+	}
+
+	var r0 *luno.GetOrderV3Response
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetOrderV3Request) *luno.GetOrderV3Response); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetOrderV3Response)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetOrderV3Request) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetOrderV3_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderV3'
+type MockLunoClient_GetOrderV3_Call struct {
+	*mock.Call
+}
+
+// GetOrderV3 is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetOrderV3Request
+func (_e *MockLunoClient_Expecter) GetOrderV3(ctx interface{}, req interface{}) *MockLunoClient_GetOrderV3_Call {
+	return &MockLunoClient_GetOrderV3_Call{Call: _e.mock.On("GetOrderV3", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetOrderV3_Call) Run(run func(ctx context.Context, req *luno.GetOrderV3Request)) *MockLunoClient_GetOrderV3_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetOrderV3Request
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetOrderV3Request)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetOrderV3_Call) Return(getOrderV3Response *luno.GetOrderV3Response, err error) *MockLunoClient_GetOrderV3_Call {
+	_c.Call.Return(getOrderV3Response, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetOrderV3_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error)) *MockLunoClient_GetOrderV3_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTicker provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTicker")
+	}
+
+	var r0 *luno.GetTickerResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetTickerRequest) (*luno.GetTickerResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetTickerRequest) *luno.GetTickerResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetTickerResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetTickerRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicker'
+type MockLunoClient_GetTicker_Call struct {
+	*mock.Call
+}
+
+// GetTicker is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetTickerRequest
+func (_e *MockLunoClient_Expecter) GetTicker(ctx interface{}, req interface{}) *MockLunoClient_GetTicker_Call {
+	return &MockLunoClient_GetTicker_Call{Call: _e.mock.On("GetTicker", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetTicker_Call) Run(run func(ctx context.Context, req *luno.GetTickerRequest)) *MockLunoClient_GetTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetTickerRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetTickerRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetTicker_Call) Return(getTickerResponse *luno.GetTickerResponse, err error) *MockLunoClient_GetTicker_Call {
+	_c.Call.Return(getTickerResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetTicker_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error)) *MockLunoClient_GetTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTickers provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTickers")
+	}
+
+	var r0 *luno.GetTickersResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetTickersRequest) (*luno.GetTickersResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetTickersRequest) *luno.GetTickersResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.GetTickersResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetTickersRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_GetTickers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTickers'
+type MockLunoClient_GetTickers_Call struct {
+	*mock.Call
+}
+
+// GetTickers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.GetTickersRequest
+func (_e *MockLunoClient_Expecter) GetTickers(ctx interface{}, req interface{}) *MockLunoClient_GetTickers_Call {
+	return &MockLunoClient_GetTickers_Call{Call: _e.mock.On("GetTickers", ctx, req)}
+}
+
+func (_c *MockLunoClient_GetTickers_Call) Run(run func(ctx context.Context, req *luno.GetTickersRequest)) *MockLunoClient_GetTickers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.GetTickersRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.GetTickersRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_GetTickers_Call) Return(getTickersResponse *luno.GetTickersResponse, err error) *MockLunoClient_GetTickers_Call {
+	_c.Call.Return(getTickersResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_GetTickers_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error)) *MockLunoClient_GetTickers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOrders provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrders")
+	}
+
+	var r0 *luno.ListOrdersResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListOrdersRequest) *luno.ListOrdersResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.ListOrdersResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListOrdersRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_ListOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrders'
+type MockLunoClient_ListOrders_Call struct {
+	*mock.Call
+}
+
+// ListOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.ListOrdersRequest
+func (_e *MockLunoClient_Expecter) ListOrders(ctx interface{}, req interface{}) *MockLunoClient_ListOrders_Call {
+	return &MockLunoClient_ListOrders_Call{Call: _e.mock.On("ListOrders", ctx, req)}
+}
+
+func (_c *MockLunoClient_ListOrders_Call) Run(run func(ctx context.Context, req *luno.ListOrdersRequest)) *MockLunoClient_ListOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.ListOrdersRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.ListOrdersRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_ListOrders_Call) Return(listOrdersResponse *luno.ListOrdersResponse, err error) *MockLunoClient_ListOrders_Call {
+	_c.Call.Return(listOrdersResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_ListOrders_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error)) *MockLunoClient_ListOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPendingTransactions provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingTransactions")
 	}
 
-	var r0 *luno.GetBalancesResponse
+	var r0 *luno.ListPendingTransactionsResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetBalancesRequest) *luno.GetBalancesResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListPendingTransactionsRequest) *luno.ListPendingTransactionsResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*luno.GetBalancesResponse)
+			r0 = ret.Get(0).(*luno.ListPendingTransactionsResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetBalancesRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListPendingTransactionsRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -67,27 +815,27 @@ func (_mock *MockLunoClient) GetBalances(ctx context.Context, req *luno.GetBalan
 	return r0, r1
 }
 
-// MockLunoClient_GetBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalances'
-type MockLunoClient_GetBalances_Call struct {
+// MockLunoClient_ListPendingTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPendingTransactions'
+type MockLunoClient_ListPendingTransactions_Call struct {
 	*mock.Call
 }
 
-// GetBalances is a helper method to define mock.On call
+// ListPendingTransactions is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req *luno.GetBalancesRequest
-func (_e *MockLunoClient_Expecter) GetBalances(ctx interface{}, req interface{}) *MockLunoClient_GetBalances_Call {
-	return &MockLunoClient_GetBalances_Call{Call: _e.mock.On("GetBalances", ctx, req)}
+//   - req *luno.ListPendingTransactionsRequest
+func (_e *MockLunoClient_Expecter) ListPendingTransactions(ctx interface{}, req interface{}) *MockLunoClient_ListPendingTransactions_Call {
+	return &MockLunoClient_ListPendingTransactions_Call{Call: _e.mock.On("ListPendingTransactions", ctx, req)}
 }
 
-func (_c *MockLunoClient_GetBalances_Call) Run(run func(ctx context.Context, req *luno.GetBalancesRequest)) *MockLunoClient_GetBalances_Call {
+func (_c *MockLunoClient_ListPendingTransactions_Call) Run(run func(ctx context.Context, req *luno.ListPendingTransactionsRequest)) *MockLunoClient_ListPendingTransactions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *luno.GetBalancesRequest
+		var arg1 *luno.ListPendingTransactionsRequest
 		if args[1] != nil {
-			arg1 = args[1].(*luno.GetBalancesRequest)
+			arg1 = args[1].(*luno.ListPendingTransactionsRequest)
 		}
 		run(
 			arg0,
@@ -97,37 +845,37 @@ func (_c *MockLunoClient_GetBalances_Call) Run(run func(ctx context.Context, req
 	return _c
 }
 
-func (_c *MockLunoClient_GetBalances_Call) Return(getBalancesResponse *luno.GetBalancesResponse, err error) *MockLunoClient_GetBalances_Call {
-	_c.Call.Return(getBalancesResponse, err)
+func (_c *MockLunoClient_ListPendingTransactions_Call) Return(listPendingTransactionsResponse *luno.ListPendingTransactionsResponse, err error) *MockLunoClient_ListPendingTransactions_Call {
+	_c.Call.Return(listPendingTransactionsResponse, err)
 	return _c
 }
 
-func (_c *MockLunoClient_GetBalances_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error)) *MockLunoClient_GetBalances_Call {
+func (_c *MockLunoClient_ListPendingTransactions_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error)) *MockLunoClient_ListPendingTransactions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOrderBook provides a mock function for the type MockLunoClient
-func (_mock *MockLunoClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+// ListTrades provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOrderBook")
+		panic("no return value specified for ListTrades")
 	}
 
-	var r0 *luno.GetOrderBookResponse
+	var r0 *luno.ListTradesResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTradesRequest) (*luno.ListTradesResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetOrderBookRequest) *luno.GetOrderBookResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTradesRequest) *luno.ListTradesResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*luno.GetOrderBookResponse)
+			r0 = ret.Get(0).(*luno.ListTradesResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetOrderBookRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListTradesRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -135,27 +883,27 @@ func (_mock *MockLunoClient) GetOrderBook(ctx context.Context, req *luno.GetOrde
 	return r0, r1
 }
 
-// MockLunoClient_GetOrderBook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderBook'
-type MockLunoClient_GetOrderBook_Call struct {
+// MockLunoClient_ListTrades_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTrades'
+type MockLunoClient_ListTrades_Call struct {
 	*mock.Call
 }
 
-// GetOrderBook is a helper method to define mock.On call
+// ListTrades is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req *luno.GetOrderBookRequest
-func (_e *MockLunoClient_Expecter) GetOrderBook(ctx interface{}, req interface{}) *MockLunoClient_GetOrderBook_Call {
-	return &MockLunoClient_GetOrderBook_Call{Call: _e.mock.On("GetOrderBook", ctx, req)}
+//   - req *luno.ListTradesRequest
+func (_e *MockLunoClient_Expecter) ListTrades(ctx interface{}, req interface{}) *MockLunoClient_ListTrades_Call {
+	return &MockLunoClient_ListTrades_Call{Call: _e.mock.On("ListTrades", ctx, req)}
 }
 
-func (_c *MockLunoClient_GetOrderBook_Call) Run(run func(ctx context.Context, req *luno.GetOrderBookRequest)) *MockLunoClient_GetOrderBook_Call {
+func (_c *MockLunoClient_ListTrades_Call) Run(run func(ctx context.Context, req *luno.ListTradesRequest)) *MockLunoClient_ListTrades_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *luno.GetOrderBookRequest
+		var arg1 *luno.ListTradesRequest
 		if args[1] != nil {
-			arg1 = args[1].(*luno.GetOrderBookRequest)
+			arg1 = args[1].(*luno.ListTradesRequest)
 		}
 		run(
 			arg0,
@@ -165,37 +913,37 @@ func (_c *MockLunoClient_GetOrderBook_Call) Run(run func(ctx context.Context, re
 	return _c
 }
 
-func (_c *MockLunoClient_GetOrderBook_Call) Return(getOrderBookResponse *luno.GetOrderBookResponse, err error) *MockLunoClient_GetOrderBook_Call {
-	_c.Call.Return(getOrderBookResponse, err)
+func (_c *MockLunoClient_ListTrades_Call) Return(listTradesResponse *luno.ListTradesResponse, err error) *MockLunoClient_ListTrades_Call {
+	_c.Call.Return(listTradesResponse, err)
 	return _c
 }
 
-func (_c *MockLunoClient_GetOrderBook_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error)) *MockLunoClient_GetOrderBook_Call {
+func (_c *MockLunoClient_ListTrades_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error)) *MockLunoClient_ListTrades_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTicker provides a mock function for the type MockLunoClient
-func (_mock *MockLunoClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+// ListTransactions provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTicker")
+		panic("no return value specified for ListTransactions")
 	}
 
-	var r0 *luno.GetTickerResponse
+	var r0 *luno.ListTransactionsResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetTickerRequest) (*luno.GetTickerResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.GetTickerRequest) *luno.GetTickerResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTransactionsRequest) *luno.ListTransactionsResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*luno.GetTickerResponse)
+			r0 = ret.Get(0).(*luno.ListTransactionsResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.GetTickerRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListTransactionsRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -203,27 +951,27 @@ func (_mock *MockLunoClient) GetTicker(ctx context.Context, req *luno.GetTickerR
 	return r0, r1
 }
 
-// MockLunoClient_GetTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTicker'
-type MockLunoClient_GetTicker_Call struct {
+// MockLunoClient_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
+type MockLunoClient_ListTransactions_Call struct {
 	*mock.Call
 }
 
-// GetTicker is a helper method to define mock.On call
+// ListTransactions is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req *luno.GetTickerRequest
-func (_e *MockLunoClient_Expecter) GetTicker(ctx interface{}, req interface{}) *MockLunoClient_GetTicker_Call {
-	return &MockLunoClient_GetTicker_Call{Call: _e.mock.On("GetTicker", ctx, req)}
+//   - req *luno.ListTransactionsRequest
+func (_e *MockLunoClient_Expecter) ListTransactions(ctx interface{}, req interface{}) *MockLunoClient_ListTransactions_Call {
+	return &MockLunoClient_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, req)}
 }
 
-func (_c *MockLunoClient_GetTicker_Call) Run(run func(ctx context.Context, req *luno.GetTickerRequest)) *MockLunoClient_GetTicker_Call {
+func (_c *MockLunoClient_ListTransactions_Call) Run(run func(ctx context.Context, req *luno.ListTransactionsRequest)) *MockLunoClient_ListTransactions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *luno.GetTickerRequest
+		var arg1 *luno.ListTransactionsRequest
 		if args[1] != nil {
-			arg1 = args[1].(*luno.GetTickerRequest)
+			arg1 = args[1].(*luno.ListTransactionsRequest)
 		}
 		run(
 			arg0,
@@ -233,37 +981,37 @@ func (_c *MockLunoClient_GetTicker_Call) Run(run func(ctx context.Context, req *
 	return _c
 }
 
-func (_c *MockLunoClient_GetTicker_Call) Return(getTickerResponse *luno.GetTickerResponse, err error) *MockLunoClient_GetTicker_Call {
-	_c.Call.Return(getTickerResponse, err)
+func (_c *MockLunoClient_ListTransactions_Call) Return(listTransactionsResponse *luno.ListTransactionsResponse, err error) *MockLunoClient_ListTransactions_Call {
+	_c.Call.Return(listTransactionsResponse, err)
 	return _c
 }
 
-func (_c *MockLunoClient_GetTicker_Call) RunAndReturn(run func(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error)) *MockLunoClient_GetTicker_Call {
+func (_c *MockLunoClient_ListTransactions_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error)) *MockLunoClient_ListTransactions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListOrders provides a mock function for the type MockLunoClient
-func (_mock *MockLunoClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+// ListUserTrades provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListOrders")
+		panic("no return value specified for ListUserTrades")
 	}
 
-	var r0 *luno.ListOrdersResponse
+	var r0 *luno.ListUserTradesResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListOrdersRequest) *luno.ListOrdersResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListUserTradesRequest) *luno.ListUserTradesResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*luno.ListOrdersResponse)
+			r0 = ret.Get(0).(*luno.ListUserTradesResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListOrdersRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListUserTradesRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -271,27 +1019,27 @@ func (_mock *MockLunoClient) ListOrders(ctx context.Context, req *luno.ListOrder
 	return r0, r1
 }
 
-// MockLunoClient_ListOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrders'
-type MockLunoClient_ListOrders_Call struct {
+// MockLunoClient_ListUserTrades_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUserTrades'
+type MockLunoClient_ListUserTrades_Call struct {
 	*mock.Call
 }
 
-// ListOrders is a helper method to define mock.On call
+// ListUserTrades is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req *luno.ListOrdersRequest
-func (_e *MockLunoClient_Expecter) ListOrders(ctx interface{}, req interface{}) *MockLunoClient_ListOrders_Call {
-	return &MockLunoClient_ListOrders_Call{Call: _e.mock.On("ListOrders", ctx, req)}
+//   - req *luno.ListUserTradesRequest
+func (_e *MockLunoClient_Expecter) ListUserTrades(ctx interface{}, req interface{}) *MockLunoClient_ListUserTrades_Call {
+	return &MockLunoClient_ListUserTrades_Call{Call: _e.mock.On("ListUserTrades", ctx, req)}
 }
 
-func (_c *MockLunoClient_ListOrders_Call) Run(run func(ctx context.Context, req *luno.ListOrdersRequest)) *MockLunoClient_ListOrders_Call {
+func (_c *MockLunoClient_ListUserTrades_Call) Run(run func(ctx context.Context, req *luno.ListUserTradesRequest)) *MockLunoClient_ListUserTrades_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *luno.ListOrdersRequest
+		var arg1 *luno.ListUserTradesRequest
 		if args[1] != nil {
-			arg1 = args[1].(*luno.ListOrdersRequest)
+			arg1 = args[1].(*luno.ListUserTradesRequest)
 		}
 		run(
 			arg0,
@@ -301,37 +1049,37 @@ func (_c *MockLunoClient_ListOrders_Call) Run(run func(ctx context.Context, req
 	return _c
 }
 
-func (_c *MockLunoClient_ListOrders_Call) Return(listOrdersResponse *luno.ListOrdersResponse, err error) *MockLunoClient_ListOrders_Call {
-	_c.Call.Return(listOrdersResponse, err)
+func (_c *MockLunoClient_ListUserTrades_Call) Return(listUserTradesResponse *luno.ListUserTradesResponse, err error) *MockLunoClient_ListUserTrades_Call {
+	_c.Call.Return(listUserTradesResponse, err)
 	return _c
 }
 
-func (_c *MockLunoClient_ListOrders_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error)) *MockLunoClient_ListOrders_Call {
+func (_c *MockLunoClient_ListUserTrades_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error)) *MockLunoClient_ListUserTrades_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListTrades provides a mock function for the type MockLunoClient
-func (_mock *MockLunoClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+// Markets provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListTrades")
+		panic("no return value specified for Markets")
 	}
 
-	var r0 *luno.ListTradesResponse
+	var r0 *luno.MarketsResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTradesRequest) (*luno.ListTradesResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.MarketsRequest) (*luno.MarketsResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTradesRequest) *luno.ListTradesResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.MarketsRequest) *luno.MarketsResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*luno.ListTradesResponse)
+			r0 = ret.Get(0).(*luno.MarketsResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListTradesRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.MarketsRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -339,27 +1087,27 @@ func (_mock *MockLunoClient) ListTrades(ctx context.Context, req *luno.ListTrade
 	return r0, r1
 }
 
-// MockLunoClient_ListTrades_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTrades'
-type MockLunoClient_ListTrades_Call struct {
+// MockLunoClient_Markets_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Markets'
+type MockLunoClient_Markets_Call struct {
 	*mock.Call
 }
 
-// ListTrades is a helper method to define mock.On call
+// Markets is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req *luno.ListTradesRequest
-func (_e *MockLunoClient_Expecter) ListTrades(ctx interface{}, req interface{}) *MockLunoClient_ListTrades_Call {
-	return &MockLunoClient_ListTrades_Call{Call: _e.mock.On("ListTrades", ctx, req)}
+//   - req *luno.MarketsRequest
+func (_e *MockLunoClient_Expecter) Markets(ctx interface{}, req interface{}) *MockLunoClient_Markets_Call {
+	return &MockLunoClient_Markets_Call{Call: _e.mock.On("Markets", ctx, req)}
 }
 
-func (_c *MockLunoClient_ListTrades_Call) Run(run func(ctx context.Context, req *luno.ListTradesRequest)) *MockLunoClient_ListTrades_Call {
+func (_c *MockLunoClient_Markets_Call) Run(run func(ctx context.Context, req *luno.MarketsRequest)) *MockLunoClient_Markets_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *luno.ListTradesRequest
+		var arg1 *luno.MarketsRequest
 		if args[1] != nil {
-			arg1 = args[1].(*luno.ListTradesRequest)
+			arg1 = args[1].(*luno.MarketsRequest)
 		}
 		run(
 			arg0,
@@ -369,37 +1117,37 @@ func (_c *MockLunoClient_ListTrades_Call) Run(run func(ctx context.Context, req
 	return _c
 }
 
-func (_c *MockLunoClient_ListTrades_Call) Return(listTradesResponse *luno.ListTradesResponse, err error) *MockLunoClient_ListTrades_Call {
-	_c.Call.Return(listTradesResponse, err)
+func (_c *MockLunoClient_Markets_Call) Return(marketsResponse *luno.MarketsResponse, err error) *MockLunoClient_Markets_Call {
+	_c.Call.Return(marketsResponse, err)
 	return _c
 }
 
-func (_c *MockLunoClient_ListTrades_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error)) *MockLunoClient_ListTrades_Call {
+func (_c *MockLunoClient_Markets_Call) RunAndReturn(run func(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error)) *MockLunoClient_Markets_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListTransactions provides a mock function for the type MockLunoClient
-func (_mock *MockLunoClient) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+// Move provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListTransactions")
+		panic("no return value specified for Move")
 	}
 
-	var r0 *luno.ListTransactionsResponse
+	var r0 *luno.MoveResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.MoveRequest) (*luno.MoveResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ListTransactionsRequest) *luno.ListTransactionsResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.MoveRequest) *luno.MoveResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*luno.ListTransactionsResponse)
+			r0 = ret.Get(0).(*luno.MoveResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ListTransactionsRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.MoveRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -407,27 +1155,27 @@ func (_mock *MockLunoClient) ListTransactions(ctx context.Context, req *luno.Lis
 	return r0, r1
 }
 
-// MockLunoClient_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
-type MockLunoClient_ListTransactions_Call struct {
+// MockLunoClient_Move_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Move'
+type MockLunoClient_Move_Call struct {
 	*mock.Call
 }
 
-// ListTransactions is a helper method to define mock.On call
+// Move is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req *luno.ListTransactionsRequest
-func (_e *MockLunoClient_Expecter) ListTransactions(ctx interface{}, req interface{}) *MockLunoClient_ListTransactions_Call {
-	return &MockLunoClient_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, req)}
+//   - req *luno.MoveRequest
+func (_e *MockLunoClient_Expecter) Move(ctx interface{}, req interface{}) *MockLunoClient_Move_Call {
+	return &MockLunoClient_Move_Call{Call: _e.mock.On("Move", ctx, req)}
 }
 
-func (_c *MockLunoClient_ListTransactions_Call) Run(run func(ctx context.Context, req *luno.ListTransactionsRequest)) *MockLunoClient_ListTransactions_Call {
+func (_c *MockLunoClient_Move_Call) Run(run func(ctx context.Context, req *luno.MoveRequest)) *MockLunoClient_Move_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *luno.ListTransactionsRequest
+		var arg1 *luno.MoveRequest
 		if args[1] != nil {
-			arg1 = args[1].(*luno.ListTransactionsRequest)
+			arg1 = args[1].(*luno.MoveRequest)
 		}
 		run(
 			arg0,
@@ -437,12 +1185,12 @@ func (_c *MockLunoClient_ListTransactions_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockLunoClient_ListTransactions_Call) Return(listTransactionsResponse *luno.ListTransactionsResponse, err error) *MockLunoClient_ListTransactions_Call {
-	_c.Call.Return(listTransactionsResponse, err)
+func (_c *MockLunoClient_Move_Call) Return(moveResponse *luno.MoveResponse, err error) *MockLunoClient_Move_Call {
+	_c.Call.Return(moveResponse, err)
 	return _c
 }
 
-func (_c *MockLunoClient_ListTransactions_Call) RunAndReturn(run func(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error)) *MockLunoClient_ListTransactions_Call {
+func (_c *MockLunoClient_Move_Call) RunAndReturn(run func(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error)) *MockLunoClient_Move_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -582,3 +1330,71 @@ func (_c *MockLunoClient_StopOrder_Call) RunAndReturn(run func(ctx context.Conte
 	_c.Call.Return(run)
 	return _c
 }
+
+// Validate provides a mock function for the type MockLunoClient
+func (_mock *MockLunoClient) Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 *luno.ValidateResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ValidateRequest) (*luno.ValidateResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *luno.ValidateRequest) *luno.ValidateResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*luno.ValidateResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *luno.ValidateRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockLunoClient_Validate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Validate'
+type MockLunoClient_Validate_Call struct {
+	*mock.Call
+}
+
+// Validate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *luno.ValidateRequest
+func (_e *MockLunoClient_Expecter) Validate(ctx interface{}, req interface{}) *MockLunoClient_Validate_Call {
+	return &MockLunoClient_Validate_Call{Call: _e.mock.On("Validate", ctx, req)}
+}
+
+func (_c *MockLunoClient_Validate_Call) Run(run func(ctx context.Context, req *luno.ValidateRequest)) *MockLunoClient_Validate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *luno.ValidateRequest
+		if args[1] != nil {
+			arg1 = args[1].(*luno.ValidateRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockLunoClient_Validate_Call) Return(validateResponse *luno.ValidateResponse, err error) *MockLunoClient_Validate_Call {
+	_c.Call.Return(validateResponse, err)
+	return _c
+}
+
+func (_c *MockLunoClient_Validate_Call) RunAndReturn(run func(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error)) *MockLunoClient_Validate_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,555 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+)
+
+// compile-time check that *PaperClient implements our interface
+var _ LunoClient = (*PaperClient)(nil)
+
+// ErrNotSimulated is returned by calls that would move real funds, which
+// paper trading does not simulate
+var ErrNotSimulated = errors.New("not available in paper trading mode")
+
+// paperOrderPrefix starts the ID of every simulated order, so they can't be
+// mistaken for real ones
+const paperOrderPrefix = "PAPER-"
+
+type paperBalance struct {
+	id       int64
+	balance  decimal.Decimal
+	reserved decimal.Decimal
+}
+
+type paperOrder struct {
+	luno.GetOrderV3Response
+
+	// base and counter are the currencies of the order's pair
+	base, counter string
+	// remaining is the volume still open
+	remaining decimal.Decimal
+	// makerFee is charged on fills after the order starts resting
+	makerFee decimal.Decimal
+	feeScale int
+	// bookTime is the timestamp of the last order book the order was
+	// matched against, so a cached book doesn't fill it twice
+	bookTime int64
+}
+
+// paperFill is a simulated trade against one order book level
+type paperFill struct {
+	price, volume decimal.Decimal
+}
+
+// PaperClient simulates trading against an in-memory portfolio. Market data
+// comes from the wrapped client, but orders, balances and the user's trades
+// are simulated: orders fill against the live order book at the time they
+// are placed, and resting orders fill once the book crosses their price.
+// Simulated orders never move the real market.
+type PaperClient struct {
+	LunoClient
+	now func() time.Time
+
+	mu       sync.Mutex
+	balances map[string]*paperBalance
+	orders   []*paperOrder
+	trades   []luno.TradeV2
+	nextID   int64
+}
+
+// NewPaperClient creates a paper trading client whose portfolio starts with
+// the given balance of each asset
+func NewPaperClient(next LunoClient, balances map[string]decimal.Decimal) *PaperClient {
+	c := &PaperClient{
+		LunoClient: next,
+		now:        time.Now,
+		balances:   make(map[string]*paperBalance),
+	}
+	assets := make([]string, 0, len(balances))
+	for asset := range balances {
+		assets = append(assets, asset)
+	}
+	slices.Sort(assets)
+	for _, asset := range assets {
+		c.balanceLocked(asset).balance = balances[asset]
+	}
+	return c
+}
+
+// GetBalances implements LunoClient
+func (c *PaperClient) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	c.settle(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	assets := make([]string, 0, len(c.balances))
+	for asset := range c.balances {
+		if len(req.Assets) == 0 || slices.Contains(req.Assets, asset) {
+			assets = append(assets, asset)
+		}
+	}
+	slices.Sort(assets)
+
+	res := &luno.GetBalancesResponse{Balance: make([]luno.AccountBalance, 0, len(assets))}
+	for _, asset := range assets {
+		b := c.balances[asset]
+		res.Balance = append(res.Balance, luno.AccountBalance{
+			AccountId:   strconv.FormatInt(b.id, 10),
+			Asset:       asset,
+			Balance:     b.balance,
+			Reserved:    b.reserved,
+			Unconfirmed: decimal.Zero(),
+			Name:        "Paper " + asset,
+		})
+	}
+	return res, nil
+}
+
+// PostLimitOrder implements LunoClient. The order fills at once against the
+// levels of the live order book it crosses, paying the taker fee, and the
+// rest of a good 'til cancelled order rests until the book reaches it.
+func (c *PaperClient) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	if req.StopDirection != "" || req.StopPrice.Sign() != 0 {
+		return nil, fmt.Errorf("stop-limit orders are %w", ErrNotSimulated)
+	}
+	if req.Type != luno.OrderTypeBid && req.Type != luno.OrderTypeAsk {
+		return nil, fmt.Errorf("unknown order type %q", req.Type)
+	}
+	if req.Price.Sign() <= 0 || req.Volume.Sign() <= 0 {
+		return nil, errors.New("price and volume must be positive")
+	}
+
+	market, err := c.market(ctx, req.Pair)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := c.LunoClient.GetFeeInfo(ctx, &luno.GetFeeInfoRequest{Pair: req.Pair})
+	if err != nil {
+		return nil, err
+	}
+	takerFee, err := decimal.NewFromString(fees.TakerFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid taker fee %q: %w", fees.TakerFee, err)
+	}
+	makerFee, err := decimal.NewFromString(fees.MakerFee)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maker fee %q: %w", fees.MakerFee, err)
+	}
+	book, err := c.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: req.Pair})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if req.ClientOrderId != "" && c.findLocked("", req.ClientOrderId) != nil {
+		return nil, fmt.Errorf("client order ID %q has already been used", req.ClientOrderId)
+	}
+
+	o := &paperOrder{
+		GetOrderV3Response: luno.GetOrderV3Response{
+			Base:              decimal.Zero(),
+			ClientOrderId:     req.ClientOrderId,
+			Counter:           decimal.Zero(),
+			CreationTimestamp: luno.Time(c.now()),
+			FeeBase:           decimal.Zero(),
+			FeeCounter:        decimal.Zero(),
+			LimitPrice:        req.Price,
+			LimitVolume:       req.Volume,
+			Pair:              req.Pair,
+			Side:              luno.SideBuy,
+			Status:            luno.StatusPending,
+			TimeInForce:       string(req.TimeInForce),
+			Type:              luno.TypeLimit,
+		},
+		base:      market.BaseCurrency,
+		counter:   market.CounterCurrency,
+		remaining: req.Volume,
+		makerFee:  makerFee,
+		feeScale:  int(market.FeeScale),
+		bookTime:  book.Timestamp,
+	}
+	levels := book.Asks
+	if req.Type == luno.OrderTypeAsk {
+		o.Side = luno.SideSell
+		levels = book.Bids
+	}
+	if o.TimeInForce == "" {
+		o.TimeInForce = string(luno.TimeInForceGtc)
+	}
+
+	// The whole order must be covered up front, as on the exchange
+	asset, need := o.reservation(req.Volume)
+	if available := c.availableLocked(asset); available.Cmp(need) < 0 {
+		return nil, fmt.Errorf("insufficient %s in paper portfolio: need %s, %s available", asset, need, available)
+	}
+
+	fills := matchLevels(levels, o.Side, o.LimitPrice, o.remaining)
+	filled := decimal.Zero()
+	for _, f := range fills {
+		filled = filled.Add(f.volume)
+	}
+	c.nextID++
+	o.OrderId = paperOrderPrefix + strconv.FormatInt(c.nextID, 10)
+	c.orders = append(c.orders, o)
+
+	// A post-only order that would take liquidity is cancelled instead, as
+	// is a fill or kill order that can't fill completely
+	cancelled := req.PostOnly && len(fills) > 0 ||
+		req.TimeInForce == luno.TimeInForceFok && filled.Cmp(o.remaining) < 0
+	if cancelled {
+		fills = nil
+	}
+	for _, f := range fills {
+		c.fillLocked(o, f, takerFee)
+	}
+	if cancelled || o.remaining.Sign() == 0 || o.TimeInForce != string(luno.TimeInForceGtc) {
+		c.closeLocked(o)
+	} else {
+		asset, amount := o.reservation(o.remaining)
+		c.balanceLocked(asset).reserved = c.balanceLocked(asset).reserved.Add(amount)
+	}
+	return &luno.PostLimitOrderResponse{OrderId: o.OrderId}, nil
+}
+
+// StopOrder implements LunoClient
+func (c *PaperClient) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	c.settle(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o := c.findLocked(req.OrderId, "")
+	if o == nil {
+		return nil, fmt.Errorf("paper order %q not found", req.OrderId)
+	}
+	if o.Status != luno.StatusPending {
+		return nil, fmt.Errorf("paper order %s is no longer open", req.OrderId)
+	}
+	asset, amount := o.reservation(o.remaining)
+	c.balanceLocked(asset).reserved = c.balanceLocked(asset).reserved.Sub(amount)
+	c.closeLocked(o)
+	return &luno.StopOrderResponse{Success: true}, nil
+}
+
+// GetOrderV3 implements LunoClient
+func (c *PaperClient) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	c.settle(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	o := c.findLocked(req.Id, req.ClientOrderId)
+	if o == nil {
+		return nil, fmt.Errorf("paper order %q not found", req.Id+req.ClientOrderId)
+	}
+	res := o.GetOrderV3Response
+	return &res, nil
+}
+
+// ListOrders implements LunoClient, newest first
+func (c *PaperClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	c.settle(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := &luno.ListOrdersResponse{}
+	for _, o := range slices.Backward(c.orders) {
+		state := luno.OrderStatePending
+		if o.Status == luno.StatusComplete {
+			state = luno.OrderStateComplete
+		}
+		switch {
+		case req.Pair != "" && o.Pair != req.Pair,
+			req.State != "" && state != req.State,
+			req.CreatedBefore > 0 && time.Time(o.CreationTimestamp).UnixMilli() >= req.CreatedBefore:
+			continue
+		}
+		orderType := luno.OrderTypeBid
+		if o.Side == luno.SideSell {
+			orderType = luno.OrderTypeAsk
+		}
+		res.Orders = append(res.Orders, luno.Order{
+			Base:               o.Base,
+			CompletedTimestamp: o.CompletedTimestamp,
+			Counter:            o.Counter,
+			CreationTimestamp:  o.CreationTimestamp,
+			FeeBase:            o.FeeBase,
+			FeeCounter:         o.FeeCounter,
+			LimitPrice:         o.LimitPrice,
+			LimitVolume:        o.LimitVolume,
+			OrderId:            o.OrderId,
+			Pair:               o.Pair,
+			State:              state,
+			TimeInForce:        o.TimeInForce,
+			Type:               orderType,
+		})
+		if req.Limit > 0 && int64(len(res.Orders)) >= req.Limit {
+			break
+		}
+	}
+	return res, nil
+}
+
+// ListUserTrades implements LunoClient
+func (c *PaperClient) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	c.settle(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	since, before := time.Time(req.Since), time.Time(req.Before)
+	res := &luno.ListUserTradesResponse{}
+	for _, t := range c.trades {
+		at := time.Time(t.Timestamp)
+		switch {
+		case t.Pair != req.Pair,
+			req.AfterSeq > 0 && t.Sequence <= req.AfterSeq,
+			req.BeforeSeq > 0 && t.Sequence >= req.BeforeSeq,
+			!since.IsZero() && at.Before(since),
+			!before.IsZero() && !at.Before(before):
+			continue
+		}
+		res.Trades = append(res.Trades, t)
+	}
+	if req.SortDesc {
+		slices.Reverse(res.Trades)
+	}
+	if req.Limit > 0 && int64(len(res.Trades)) > req.Limit {
+		res.Trades = res.Trades[:req.Limit]
+	}
+	return res, nil
+}
+
+// ListTransactions implements LunoClient. Paper accounts have no
+// transaction history.
+func (c *PaperClient) ListTransactions(_ context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	return &luno.ListTransactionsResponse{Id: strconv.FormatInt(req.Id, 10)}, nil
+}
+
+// ListPendingTransactions implements LunoClient. Paper accounts have no
+// pending transactions.
+func (c *PaperClient) ListPendingTransactions(_ context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	return &luno.ListPendingTransactionsResponse{Id: strconv.FormatInt(req.Id, 10)}, nil
+}
+
+// CreateAccount implements LunoClient
+func (c *PaperClient) CreateAccount(context.Context, *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return nil, fmt.Errorf("creating accounts is %w", ErrNotSimulated)
+}
+
+// Move implements LunoClient
+func (c *PaperClient) Move(context.Context, *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return nil, fmt.Errorf("moving funds is %w", ErrNotSimulated)
+}
+
+// GetMove implements LunoClient
+func (c *PaperClient) GetMove(context.Context, *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return nil, fmt.Errorf("moving funds is %w", ErrNotSimulated)
+}
+
+// GetFundingAddress implements LunoClient
+func (c *PaperClient) GetFundingAddress(context.Context, *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	return nil, fmt.Errorf("funding addresses are %w", ErrNotSimulated)
+}
+
+// settle fills resting orders against the current order books, paying the
+// maker fee. Only a book newer than the one an order last saw can fill it,
+// and each book is shared by the orders on its pair in the order they were
+// placed. A book that can't be fetched leaves its orders as they are until
+// the next call.
+func (c *PaperClient) settle(ctx context.Context) {
+	c.mu.Lock()
+	var pairs []string
+	for _, o := range c.orders {
+		if o.Status == luno.StatusPending && !slices.Contains(pairs, o.Pair) {
+			pairs = append(pairs, o.Pair)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, pair := range pairs {
+		book, err := c.LunoClient.GetOrderBook(ctx, &luno.GetOrderBookRequest{Pair: pair})
+		if err != nil {
+			continue
+		}
+		asks, bids := slices.Clone(book.Asks), slices.Clone(book.Bids)
+
+		c.mu.Lock()
+		for _, o := range c.orders {
+			if o.Pair != pair || o.Status != luno.StatusPending || book.Timestamp <= o.bookTime {
+				continue
+			}
+			o.bookTime = book.Timestamp
+			levels := &asks
+			if o.Side == luno.SideSell {
+				levels = &bids
+			}
+			filled := decimal.Zero()
+			for _, f := range matchLevels(*levels, o.Side, o.LimitPrice, o.remaining) {
+				asset, amount := o.reservation(f.volume)
+				c.balanceLocked(asset).reserved = c.balanceLocked(asset).reserved.Sub(amount)
+				c.fillLocked(o, f, o.makerFee)
+				filled = filled.Add(f.volume)
+			}
+			*levels = consumeLevels(*levels, o.Side, o.LimitPrice, filled)
+			if o.remaining.Sign() == 0 {
+				c.closeLocked(o)
+			}
+		}
+		c.mu.Unlock()
+	}
+}
+
+// matchLevels returns the fills of up to volume against the levels that
+// cross limit, best price first
+func matchLevels(levels []luno.OrderBookEntry, side luno.Side, limit, volume decimal.Decimal) []paperFill {
+	var fills []paperFill
+	for _, l := range levels {
+		if volume.Sign() <= 0 || !crosses(side, limit, l.Price) {
+			break
+		}
+		v := l.Volume
+		if v.Cmp(volume) > 0 {
+			v = volume
+		}
+		if v.Sign() > 0 {
+			fills = append(fills, paperFill{price: l.Price, volume: v})
+			volume = volume.Sub(v)
+		}
+	}
+	return fills
+}
+
+// consumeLevels removes up to volume from the levels that cross limit, so
+// that the next order on the same book doesn't fill against it again
+func consumeLevels(levels []luno.OrderBookEntry, side luno.Side, limit, volume decimal.Decimal) []luno.OrderBookEntry {
+	for len(levels) > 0 && volume.Sign() > 0 && crosses(side, limit, levels[0].Price) {
+		if levels[0].Volume.Cmp(volume) > 0 {
+			levels[0].Volume = levels[0].Volume.Sub(volume)
+			break
+		}
+		volume = volume.Sub(levels[0].Volume)
+		levels = levels[1:]
+	}
+	return levels
+}
+
+// crosses reports whether an order at limit trades with a level at price
+func crosses(side luno.Side, limit, price decimal.Decimal) bool {
+	if side == luno.SideBuy {
+		return price.Cmp(limit) <= 0
+	}
+	return price.Cmp(limit) >= 0
+}
+
+// fillLocked applies a fill to an order and the portfolio, charging the fee
+// in the currency received
+func (c *PaperClient) fillLocked(o *paperOrder, f paperFill, feeRate decimal.Decimal) {
+	value := f.price.Mul(f.volume)
+	base, counter := c.balanceLocked(o.base), c.balanceLocked(o.counter)
+	trade := luno.TradeV2{
+		Base:          f.volume,
+		ClientOrderId: o.ClientOrderId,
+		Counter:       value,
+		FeeBase:       decimal.Zero(),
+		FeeCounter:    decimal.Zero(),
+		IsBuy:         o.Side == luno.SideBuy,
+		OrderId:       o.OrderId,
+		Pair:          o.Pair,
+		Price:         f.price,
+		Sequence:      int64(len(c.trades)) + 1,
+		Timestamp:     luno.Time(c.now()),
+		Type:          luno.OrderTypeBid,
+		Volume:        f.volume,
+	}
+	if o.Side == luno.SideBuy {
+		trade.FeeBase = f.volume.Mul(feeRate).ToScale(o.feeScale)
+		base.balance = base.balance.Add(f.volume).Sub(trade.FeeBase)
+		counter.balance = counter.balance.Sub(value)
+	} else {
+		trade.Type = luno.OrderTypeAsk
+		trade.FeeCounter = value.Mul(feeRate).ToScale(o.feeScale)
+		base.balance = base.balance.Sub(f.volume)
+		counter.balance = counter.balance.Add(value).Sub(trade.FeeCounter)
+	}
+	c.trades = append(c.trades, trade)
+
+	o.Base = o.Base.Add(f.volume)
+	o.Counter = o.Counter.Add(value)
+	o.FeeBase = o.FeeBase.Add(trade.FeeBase)
+	o.FeeCounter = o.FeeCounter.Add(trade.FeeCounter)
+	o.remaining = o.remaining.Sub(f.volume)
+}
+
+// closeLocked marks an order complete, whether it filled or was cancelled
+func (c *PaperClient) closeLocked(o *paperOrder) {
+	o.Status = luno.StatusComplete
+	o.CompletedTimestamp = luno.Time(c.now())
+	o.remaining = decimal.Zero()
+}
+
+// reservation is the asset and amount an order reserves for volume: the
+// counter value at the limit price for a buy, or the base volume for a sell
+func (o *paperOrder) reservation(volume decimal.Decimal) (string, decimal.Decimal) {
+	if o.Side == luno.SideBuy {
+		return o.counter, volume.Mul(o.LimitPrice)
+	}
+	return o.base, volume
+}
+
+func (c *PaperClient) findLocked(id, clientOrderID string) *paperOrder {
+	for _, o := range c.orders {
+		if id != "" && o.OrderId == id || clientOrderID != "" && o.ClientOrderId == clientOrderID {
+			return o
+		}
+	}
+	return nil
+}
+
+// availableLocked is the balance of an asset that isn't reserved
+func (c *PaperClient) availableLocked(asset string) decimal.Decimal {
+	b, ok := c.balances[asset]
+	if !ok {
+		return decimal.Zero()
+	}
+	return b.balance.Sub(b.reserved)
+}
+
+// balanceLocked returns an asset's balance, opening an account for it the
+// first time it is needed
+func (c *PaperClient) balanceLocked(asset string) *paperBalance {
+	b, ok := c.balances[asset]
+	if !ok {
+		b = &paperBalance{id: int64(len(c.balances)) + 1, balance: decimal.Zero(), reserved: decimal.Zero()}
+		c.balances[asset] = b
+	}
+	return b
+}
+
+// market looks up the currencies and fee scale of a pair
+func (c *PaperClient) market(ctx context.Context, pair string) (luno.MarketInfo, error) {
+	res, err := c.LunoClient.Markets(ctx, &luno.MarketsRequest{})
+	if err != nil {
+		return luno.MarketInfo{}, err
+	}
+	for _, m := range res.Markets {
+		if strings.EqualFold(m.MarketId, pair) {
+			return m, nil
+		}
+	}
+	return luno.MarketInfo{}, fmt.Errorf("unknown pair %s", pair)
+}
@@ -0,0 +1,255 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func dec(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	require.NoError(t, err)
+	return d
+}
+
+func assertDec(t *testing.T, expected string, actual decimal.Decimal) {
+	t.Helper()
+	assert.Zero(t, dec(t, expected).Cmp(actual), "expected %s, got %s", expected, actual)
+}
+
+// expectPaperMarket sets up XBTZAR with asks of 0.5 at 1000 and 1 at 1010,
+// bids of 0.5 at 990 and 1 at 980, a taker fee of 0.1% and no maker fee
+func expectPaperMarket(t *testing.T, m *MockLunoClient) {
+	m.EXPECT().Markets(mock.Anything, mock.Anything).Return(&luno.MarketsResponse{
+		Markets: []luno.MarketInfo{{MarketId: "XBTZAR", BaseCurrency: "XBT", CounterCurrency: "ZAR", FeeScale: 8}},
+	}, nil).Maybe()
+	m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(&luno.GetFeeInfoResponse{TakerFee: "0.001", MakerFee: "0"}, nil).Maybe()
+	m.EXPECT().GetOrderBook(mock.Anything, &luno.GetOrderBookRequest{Pair: "XBTZAR"}).Return(&luno.GetOrderBookResponse{
+		Asks:      []luno.OrderBookEntry{{Price: dec(t, "1000"), Volume: dec(t, "0.5")}, {Price: dec(t, "1010"), Volume: dec(t, "1")}},
+		Bids:      []luno.OrderBookEntry{{Price: dec(t, "990"), Volume: dec(t, "0.5")}, {Price: dec(t, "980"), Volume: dec(t, "1")}},
+		Timestamp: 1,
+	}, nil).Maybe()
+}
+
+func newTestPaperClient(t *testing.T) *PaperClient {
+	m := NewMockLunoClient(t)
+	expectPaperMarket(t, m)
+	return NewPaperClient(m, map[string]decimal.Decimal{"ZAR": dec(t, "10000"), "XBT": dec(t, "1")})
+}
+
+func paperBalances(t *testing.T, c *PaperClient) map[string]luno.AccountBalance {
+	res, err := c.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+	require.NoError(t, err)
+	balances := make(map[string]luno.AccountBalance)
+	for _, b := range res.Balance {
+		balances[b.Asset] = b
+	}
+	return balances
+}
+
+func TestPaperClientPostLimitOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            luno.PostLimitOrderRequest
+		errorContains  string
+		expectedStatus luno.Status
+		expectedBase   string
+		expectedZAR    string
+		expectedXBT    string
+		reservedZAR    string
+		reservedXBT    string
+	}{
+		{
+			name:           "buy fills across levels",
+			req:            luno.PostLimitOrderRequest{Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1010), Volume: decimal.NewFromInt64(1)},
+			expectedStatus: luno.StatusComplete,
+			expectedBase:   "1",
+			expectedZAR:    "8995",
+			expectedXBT:    "1.999",
+			reservedZAR:    "0",
+			reservedXBT:    "0",
+		},
+		{
+			name:           "rest of a buy rests",
+			req:            luno.PostLimitOrderRequest{Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1000), Volume: decimal.NewFromInt64(2)},
+			expectedStatus: luno.StatusPending,
+			expectedBase:   "0.5",
+			expectedZAR:    "9500",
+			expectedXBT:    "1.4995",
+			reservedZAR:    "1500",
+			reservedXBT:    "0",
+		},
+		{
+			name:           "sell pays the fee in the counter currency",
+			req:            luno.PostLimitOrderRequest{Type: luno.OrderTypeAsk, Price: decimal.NewFromInt64(985), Volume: decimal.NewFromInt64(1)},
+			expectedStatus: luno.StatusPending,
+			expectedBase:   "0.5",
+			expectedZAR:    "10494.505",
+			expectedXBT:    "0.5",
+			reservedZAR:    "0",
+			reservedXBT:    "0.5",
+		},
+		{
+			name: "immediate or cancel drops the rest",
+			req: luno.PostLimitOrderRequest{
+				Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1000), Volume: decimal.NewFromInt64(2), TimeInForce: luno.TimeInForceIoc,
+			},
+			expectedStatus: luno.StatusComplete,
+			expectedBase:   "0.5",
+			expectedZAR:    "9500",
+			expectedXBT:    "1.4995",
+			reservedZAR:    "0",
+			reservedXBT:    "0",
+		},
+		{
+			name: "fill or kill that can't fill",
+			req: luno.PostLimitOrderRequest{
+				Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1000), Volume: decimal.NewFromInt64(2), TimeInForce: luno.TimeInForceFok,
+			},
+			expectedStatus: luno.StatusComplete,
+			expectedBase:   "0",
+			expectedZAR:    "10000",
+			expectedXBT:    "1",
+			reservedZAR:    "0",
+			reservedXBT:    "0",
+		},
+		{
+			name: "post-only that would trade is cancelled",
+			req: luno.PostLimitOrderRequest{
+				Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1000), Volume: decimal.NewFromInt64(1), PostOnly: true,
+			},
+			expectedStatus: luno.StatusComplete,
+			expectedBase:   "0",
+			expectedZAR:    "10000",
+			expectedXBT:    "1",
+			reservedZAR:    "0",
+			reservedXBT:    "0",
+		},
+		{
+			name:          "insufficient funds",
+			req:           luno.PostLimitOrderRequest{Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1000), Volume: decimal.NewFromInt64(20)},
+			errorContains: "insufficient ZAR in paper portfolio",
+		},
+		{
+			name: "stop-limit order",
+			req: luno.PostLimitOrderRequest{
+				Type: luno.OrderTypeBid, Price: decimal.NewFromInt64(1000), Volume: decimal.NewFromInt64(1),
+				StopPrice: decimal.NewFromInt64(1000), StopDirection: luno.StopDirectionAbove,
+			},
+			errorContains: "stop-limit orders are not available in paper trading mode",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestPaperClient(t)
+			tc.req.Pair = "XBTZAR"
+
+			res, err := c.PostLimitOrder(context.Background(), &tc.req)
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "PAPER-1", res.OrderId)
+
+			order, err := c.GetOrderV3(context.Background(), &luno.GetOrderV3Request{Id: res.OrderId})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, order.Status)
+			assertDec(t, tc.expectedBase, order.Base)
+
+			balances := paperBalances(t, c)
+			assertDec(t, tc.expectedZAR, balances["ZAR"].Balance)
+			assertDec(t, tc.expectedXBT, balances["XBT"].Balance)
+			assertDec(t, tc.reservedZAR, balances["ZAR"].Reserved)
+			assertDec(t, tc.reservedXBT, balances["XBT"].Reserved)
+		})
+	}
+}
+
+func TestPaperClientRestingOrders(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockLunoClient(t)
+	m.EXPECT().Markets(mock.Anything, mock.Anything).Return(&luno.MarketsResponse{
+		Markets: []luno.MarketInfo{{MarketId: "XBTZAR", BaseCurrency: "XBT", CounterCurrency: "ZAR", FeeScale: 8}},
+	}, nil)
+	m.EXPECT().GetFeeInfo(mock.Anything, mock.Anything).Return(&luno.GetFeeInfoResponse{TakerFee: "0.001", MakerFee: "0.0005"}, nil)
+	m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{
+		Asks:      []luno.OrderBookEntry{{Price: dec(t, "1000"), Volume: dec(t, "1")}},
+		Timestamp: 1,
+	}, nil).Times(3)
+	c := NewPaperClient(m, map[string]decimal.Decimal{"ZAR": dec(t, "10000")})
+
+	first, err := c.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{Pair: "XBTZAR", Type: luno.OrderTypeBid, Price: dec(t, "950"), Volume: dec(t, "1")})
+	require.NoError(t, err)
+	second, err := c.PostLimitOrder(ctx, &luno.PostLimitOrderRequest{Pair: "XBTZAR", Type: luno.OrderTypeBid, Price: dec(t, "900"), Volume: dec(t, "1")})
+	require.NoError(t, err)
+
+	orders, err := c.ListOrders(ctx, &luno.ListOrdersRequest{State: luno.OrderStatePending})
+	require.NoError(t, err)
+	require.Len(t, orders.Orders, 2)
+	assert.Equal(t, second.OrderId, orders.Orders[0].OrderId, "newest first")
+
+	// The ask falls to 900, filling the first order and half of the second
+	m.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(&luno.GetOrderBookResponse{
+		Asks:      []luno.OrderBookEntry{{Price: dec(t, "900"), Volume: dec(t, "1.5")}},
+		Timestamp: 2,
+	}, nil)
+
+	balances := paperBalances(t, c)
+	assertDec(t, "8650", balances["ZAR"].Balance)
+	assertDec(t, "450", balances["ZAR"].Reserved)
+	assertDec(t, "1.49925", balances["XBT"].Balance)
+
+	order, err := c.GetOrderV3(ctx, &luno.GetOrderV3Request{Id: first.OrderId})
+	require.NoError(t, err)
+	assert.Equal(t, luno.StatusComplete, order.Status)
+	assertDec(t, "0.0005", order.FeeBase)
+
+	trades, err := c.ListUserTrades(ctx, &luno.ListUserTradesRequest{Pair: "XBTZAR"})
+	require.NoError(t, err)
+	require.Len(t, trades.Trades, 2)
+	assert.Equal(t, first.OrderId, trades.Trades[0].OrderId)
+	assert.Equal(t, second.OrderId, trades.Trades[1].OrderId)
+	assertDec(t, "0.5", trades.Trades[1].Volume)
+	assert.True(t, trades.Trades[1].IsBuy)
+
+	// Cancelling releases what the rest of the order reserved
+	stopped, err := c.StopOrder(ctx, &luno.StopOrderRequest{OrderId: second.OrderId})
+	require.NoError(t, err)
+	assert.True(t, stopped.Success)
+	balances = paperBalances(t, c)
+	assertDec(t, "0", balances["ZAR"].Reserved)
+
+	_, err = c.StopOrder(ctx, &luno.StopOrderRequest{OrderId: second.OrderId})
+	assert.ErrorContains(t, err, "is no longer open")
+}
+
+func TestPaperClientDoesNotMoveFunds(t *testing.T) {
+	m := NewMockLunoClient(t)
+	m.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{Pair: "XBTZAR"}, nil)
+	c := NewPaperClient(m, nil)
+
+	// Market data comes from the wrapped client
+	ticker, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	require.NoError(t, err)
+	assert.Equal(t, "XBTZAR", ticker.Pair)
+
+	_, err = c.Move(context.Background(), &luno.MoveRequest{})
+	assert.ErrorIs(t, err, ErrNotSimulated)
+	_, err = c.CreateAccount(context.Background(), &luno.CreateAccountRequest{})
+	assert.ErrorIs(t, err, ErrNotSimulated)
+	_, err = c.GetFundingAddress(context.Background(), &luno.GetFundingAddressRequest{})
+	assert.ErrorIs(t, err, ErrNotSimulated)
+
+	txns, err := c.ListTransactions(context.Background(), &luno.ListTransactionsRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Empty(t, txns.Transactions)
+}
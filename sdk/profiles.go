@@ -0,0 +1,231 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/luno/luno-go"
+)
+
+// compile-time check that *ProfileClient implements our interface
+var _ LunoClient = (*ProfileClient)(nil)
+
+type profileKey struct{}
+
+// WithProfile returns a context whose Luno API calls use the named profile
+func WithProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, profileKey{}, name)
+}
+
+// ProfileFromContext returns the profile set by WithProfile, or an empty
+// string for the default profile
+func ProfileFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(profileKey{}).(string)
+	return name
+}
+
+type clientKey struct{}
+
+// WithClient returns a context whose calls go to client rather than to a
+// profile, for a session that brought its own credentials
+func WithClient(ctx context.Context, client LunoClient) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client set by WithClient, or nil
+func ClientFromContext(ctx context.Context) LunoClient {
+	client, _ := ctx.Value(clientKey{}).(LunoClient)
+	return client
+}
+
+// ProfileClient sends each call to the client of the profile in the call's
+// context, so that one server can use several sets of credentials. A client
+// set on the context with WithClient takes precedence over profiles.
+type ProfileClient struct {
+	clients     map[string]LunoClient
+	defaultName string
+}
+
+// NewProfileClient routes calls between clients by profile name. Calls
+// without a profile in their context go to defaultName.
+func NewProfileClient(defaultName string, clients map[string]LunoClient) *ProfileClient {
+	return &ProfileClient{clients: clients, defaultName: defaultName}
+}
+
+// Profiles returns the profile names in sorted order
+func (c *ProfileClient) Profiles() []string {
+	names := make([]string, 0, len(c.clients))
+	for name := range c.clients {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// client returns the client for the context's profile. Unknown profiles are
+// an error rather than falling back, so a call never reaches the wrong account.
+func (c *ProfileClient) client(ctx context.Context) (LunoClient, error) {
+	if cl := ClientFromContext(ctx); cl != nil {
+		return cl, nil
+	}
+	name := ProfileFromContext(ctx)
+	if name == "" {
+		name = c.defaultName
+	}
+	if cl, ok := c.clients[name]; ok {
+		return cl, nil
+	}
+	return nil, fmt.Errorf("unknown profile %q, must be one of %s", name, strings.Join(c.Profiles(), ", "))
+}
+
+// route calls fn with the client for the context's profile
+func route[T any](ctx context.Context, c *ProfileClient, fn func(LunoClient) (T, error)) (T, error) {
+	cl, err := c.client(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return fn(cl)
+}
+
+// GetBalances implements LunoClient
+func (c *ProfileClient) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetBalancesResponse, error) {
+		return cl.GetBalances(ctx, req)
+	})
+}
+
+// CreateAccount implements LunoClient
+func (c *ProfileClient) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.CreateAccountResponse, error) {
+		return cl.CreateAccount(ctx, req)
+	})
+}
+
+// Move implements LunoClient
+func (c *ProfileClient) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.MoveResponse, error) {
+		return cl.Move(ctx, req)
+	})
+}
+
+// GetMove implements LunoClient
+func (c *ProfileClient) GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetMoveResponse, error) {
+		return cl.GetMove(ctx, req)
+	})
+}
+
+// GetTicker implements LunoClient
+func (c *ProfileClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetTickerResponse, error) {
+		return cl.GetTicker(ctx, req)
+	})
+}
+
+// GetTickers implements LunoClient
+func (c *ProfileClient) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetTickersResponse, error) {
+		return cl.GetTickers(ctx, req)
+	})
+}
+
+// GetOrderBook implements LunoClient
+func (c *ProfileClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetOrderBookResponse, error) {
+		return cl.GetOrderBook(ctx, req)
+	})
+}
+
+// GetCandles implements LunoClient
+func (c *ProfileClient) GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetCandlesResponse, error) {
+		return cl.GetCandles(ctx, req)
+	})
+}
+
+// GetFeeInfo implements LunoClient
+func (c *ProfileClient) GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetFeeInfoResponse, error) {
+		return cl.GetFeeInfo(ctx, req)
+	})
+}
+
+// GetFundingAddress implements LunoClient
+func (c *ProfileClient) GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetFundingAddressResponse, error) {
+		return cl.GetFundingAddress(ctx, req)
+	})
+}
+
+// Validate implements LunoClient
+func (c *ProfileClient) Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.ValidateResponse, error) {
+		return cl.Validate(ctx, req)
+	})
+}
+
+// GetOrderV3 implements LunoClient
+func (c *ProfileClient) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.GetOrderV3Response, error) {
+		return cl.GetOrderV3(ctx, req)
+	})
+}
+
+// PostLimitOrder implements LunoClient
+func (c *ProfileClient) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.PostLimitOrderResponse, error) {
+		return cl.PostLimitOrder(ctx, req)
+	})
+}
+
+// StopOrder implements LunoClient
+func (c *ProfileClient) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.StopOrderResponse, error) {
+		return cl.StopOrder(ctx, req)
+	})
+}
+
+// ListOrders implements LunoClient
+func (c *ProfileClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.ListOrdersResponse, error) {
+		return cl.ListOrders(ctx, req)
+	})
+}
+
+// ListTransactions implements LunoClient
+func (c *ProfileClient) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.ListTransactionsResponse, error) {
+		return cl.ListTransactions(ctx, req)
+	})
+}
+
+// ListPendingTransactions implements LunoClient
+func (c *ProfileClient) ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.ListPendingTransactionsResponse, error) {
+		return cl.ListPendingTransactions(ctx, req)
+	})
+}
+
+// ListTrades implements LunoClient
+func (c *ProfileClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.ListTradesResponse, error) {
+		return cl.ListTrades(ctx, req)
+	})
+}
+
+// ListUserTrades implements LunoClient
+func (c *ProfileClient) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.ListUserTradesResponse, error) {
+		return cl.ListUserTrades(ctx, req)
+	})
+}
+
+// Markets implements LunoClient
+func (c *ProfileClient) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	return route(ctx, c, func(cl LunoClient) (*luno.MarketsResponse, error) {
+		return cl.Markets(ctx, req)
+	})
+}
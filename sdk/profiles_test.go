@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileClient(t *testing.T) {
+	tests := []struct {
+		name          string
+		profile       string
+		ownClient     bool
+		expectedID    string
+		errorContains string
+	}{
+		{name: "no profile uses the default", expectedID: "trading-account"},
+		{name: "named profile", profile: "savings", expectedID: "savings-account"},
+		{name: "default by name", profile: "default", expectedID: "trading-account"},
+		{name: "session client", ownClient: true, expectedID: "session-account"},
+		{name: "session client overrides profile", profile: "savings", ownClient: true, expectedID: "session-account"},
+		{name: "unknown profile is an error", profile: "corporate", errorContains: `unknown profile "corporate", must be one of default, savings`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trading := NewMockLunoClient(t)
+			savings := NewMockLunoClient(t)
+			session := NewMockLunoClient(t)
+			for _, c := range []struct {
+				client *MockLunoClient
+				id     string
+			}{{trading, "trading-account"}, {savings, "savings-account"}, {session, "session-account"}} {
+				c.client.EXPECT().GetBalances(mock.Anything, mock.Anything).
+					Return(&luno.GetBalancesResponse{Balance: []luno.AccountBalance{{AccountId: c.id}}}, nil).Maybe()
+			}
+			client := NewProfileClient("default", map[string]LunoClient{"default": trading, "savings": savings})
+
+			ctx := context.Background()
+			if tc.profile != "" {
+				ctx = WithProfile(ctx, tc.profile)
+			}
+			if tc.ownClient {
+				ctx = WithClient(ctx, session)
+			}
+			res, err := client.GetBalances(ctx, &luno.GetBalancesRequest{})
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedID, res.Balance[0].AccountId)
+		})
+	}
+}
+
+func TestProfileFromContext(t *testing.T) {
+	assert.Empty(t, ProfileFromContext(context.Background()))
+	assert.Equal(t, "savings", ProfileFromContext(WithProfile(context.Background(), "savings")))
+}
@@ -0,0 +1,486 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/luno/luno-go"
+)
+
+// compile-time checks that the recording clients implement our interface
+var (
+	_ LunoClient = (*RecordingClient)(nil)
+	_ LunoClient = (*ReplayClient)(nil)
+)
+
+// ErrNotRecorded is returned by a ReplayClient for a call that isn't in
+// its recording
+var ErrNotRecorded = errors.New("call not in the recording")
+
+// interaction is one recorded call, stored as a line of JSON
+type interaction struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *recordedError  `json:"error,omitempty"`
+}
+
+// recordedError is a failed call. Luno API errors keep their code, so that
+// replayed errors are handled like the original ones.
+type recordedError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *recordedError) err() error {
+	if e.Code != "" {
+		return luno.Error{Code: e.Code, Message: e.Message}
+	}
+	return errors.New(e.Message)
+}
+
+// RecordingClient passes calls to the next client and appends each call
+// with its response or error to a file, which a ReplayClient can serve later
+type RecordingClient struct {
+	next LunoClient
+
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewRecordingClient records calls to next in a new file at path,
+// replacing any file already there
+func NewRecordingClient(next LunoClient, path string) (*RecordingClient, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordingClient{next: next, f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Path returns the location of the recording
+func (c *RecordingClient) Path() string {
+	return c.f.Name()
+}
+
+// Close closes the recording file
+func (c *RecordingClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.f.Close()
+}
+
+// record calls fn and records the call. Cancelled calls never reached Luno,
+// so they aren't recorded. A failure to write is logged rather than failing
+// the call.
+func record[T any](ctx context.Context, c *RecordingClient, method string, req any, fn func() (T, error)) (T, error) {
+	res, err := fn()
+	if ctx.Err() != nil {
+		return res, err
+	}
+
+	in := interaction{Method: method}
+	var werr error
+	if in.Request, werr = json.Marshal(req); werr == nil && err == nil {
+		in.Response, werr = json.Marshal(res)
+	}
+	if err != nil {
+		in.Error = &recordedError{Message: err.Error()}
+		var lunoErr luno.Error
+		if errors.As(err, &lunoErr) {
+			in.Error = &recordedError{Code: lunoErr.Code, Message: lunoErr.Message}
+		}
+	}
+	if werr == nil {
+		c.mu.Lock()
+		werr = c.enc.Encode(in)
+		c.mu.Unlock()
+	}
+	if werr != nil {
+		slog.Warn("Failed to record Luno API call", slog.String("method", method), slog.String("error", werr.Error()))
+	}
+	return res, err
+}
+
+// ReplayClient serves calls from a recording made by a RecordingClient,
+// without calling Luno. A call gets the recorded responses to the same
+// method and request in the order they were recorded, and the last one
+// again once they run out. Times in requests are ignored when matching,
+// since they are usually relative to when the call was made.
+type ReplayClient struct {
+	mu      sync.Mutex
+	calls   map[string][]*interaction
+	replays map[string]int
+}
+
+// NewReplayClient loads the recording at path
+func NewReplayClient(path string) (*ReplayClient, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &ReplayClient{calls: make(map[string][]*interaction), replays: make(map[string]int)}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var in interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		key, err := requestKey(in.Method, in.Request)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		c.calls[key] = append(c.calls[key], &in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Len returns the number of recorded calls
+func (c *ReplayClient) Len() int {
+	n := 0
+	for _, calls := range c.calls {
+		n += len(calls)
+	}
+	return n
+}
+
+// requestKey identifies a recorded request, with its luno.Time fields cleared
+func requestKey(method string, req json.RawMessage) (string, error) {
+	typ, ok := requestTypes[method]
+	if !ok {
+		return "", fmt.Errorf("unknown method %q", method)
+	}
+	v := reflect.New(typ)
+	if len(req) > 0 && string(req) != "null" {
+		if err := json.Unmarshal(req, v.Interface()); err != nil {
+			return "", fmt.Errorf("%s request: %w", method, err)
+		}
+	}
+	timeType := reflect.TypeFor[luno.Time]()
+	for i := range typ.NumField() {
+		if f := v.Elem().Field(i); f.Type() == timeType && f.CanSet() {
+			f.SetZero()
+		}
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return "", err
+	}
+	return method + " " + string(b), nil
+}
+
+// replay returns the next recorded response to the request
+func replay[T any](c *ReplayClient, method string, req any) (T, error) {
+	var res T
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return res, err
+	}
+	key, err := requestKey(method, raw)
+	if err != nil {
+		return res, err
+	}
+
+	c.mu.Lock()
+	calls := c.calls[key]
+	i := c.replays[key]
+	if i < len(calls) {
+		c.replays[key] = i + 1
+	} else {
+		i = len(calls) - 1
+	}
+	c.mu.Unlock()
+	if i < 0 {
+		return res, fmt.Errorf("%s %s: %w", method, raw, ErrNotRecorded)
+	}
+
+	in := calls[i]
+	if in.Error != nil {
+		return res, in.Error.err()
+	}
+	if err := json.Unmarshal(in.Response, &res); err != nil {
+		return res, fmt.Errorf("%s response: %w", method, err)
+	}
+	return res, nil
+}
+
+// requestTypes are the request types of each method, for decoding recorded requests
+var requestTypes = map[string]reflect.Type{
+	"GetBalances":             reflect.TypeFor[luno.GetBalancesRequest](),
+	"CreateAccount":           reflect.TypeFor[luno.CreateAccountRequest](),
+	"Move":                    reflect.TypeFor[luno.MoveRequest](),
+	"GetMove":                 reflect.TypeFor[luno.GetMoveRequest](),
+	"GetTicker":               reflect.TypeFor[luno.GetTickerRequest](),
+	"GetTickers":              reflect.TypeFor[luno.GetTickersRequest](),
+	"GetOrderBook":            reflect.TypeFor[luno.GetOrderBookRequest](),
+	"GetCandles":              reflect.TypeFor[luno.GetCandlesRequest](),
+	"GetFeeInfo":              reflect.TypeFor[luno.GetFeeInfoRequest](),
+	"GetFundingAddress":       reflect.TypeFor[luno.GetFundingAddressRequest](),
+	"Validate":                reflect.TypeFor[luno.ValidateRequest](),
+	"GetOrderV3":              reflect.TypeFor[luno.GetOrderV3Request](),
+	"PostLimitOrder":          reflect.TypeFor[luno.PostLimitOrderRequest](),
+	"StopOrder":               reflect.TypeFor[luno.StopOrderRequest](),
+	"ListOrders":              reflect.TypeFor[luno.ListOrdersRequest](),
+	"ListTransactions":        reflect.TypeFor[luno.ListTransactionsRequest](),
+	"ListPendingTransactions": reflect.TypeFor[luno.ListPendingTransactionsRequest](),
+	"ListTrades":              reflect.TypeFor[luno.ListTradesRequest](),
+	"ListUserTrades":          reflect.TypeFor[luno.ListUserTradesRequest](),
+	"Markets":                 reflect.TypeFor[luno.MarketsRequest](),
+}
+
+// GetBalances implements LunoClient
+func (c *RecordingClient) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	return record(ctx, c, "GetBalances", req, func() (*luno.GetBalancesResponse, error) {
+		return c.next.GetBalances(ctx, req)
+	})
+}
+
+// CreateAccount implements LunoClient
+func (c *RecordingClient) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return record(ctx, c, "CreateAccount", req, func() (*luno.CreateAccountResponse, error) {
+		return c.next.CreateAccount(ctx, req)
+	})
+}
+
+// Move implements LunoClient
+func (c *RecordingClient) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return record(ctx, c, "Move", req, func() (*luno.MoveResponse, error) {
+		return c.next.Move(ctx, req)
+	})
+}
+
+// GetMove implements LunoClient
+func (c *RecordingClient) GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return record(ctx, c, "GetMove", req, func() (*luno.GetMoveResponse, error) {
+		return c.next.GetMove(ctx, req)
+	})
+}
+
+// GetTicker implements LunoClient
+func (c *RecordingClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	return record(ctx, c, "GetTicker", req, func() (*luno.GetTickerResponse, error) {
+		return c.next.GetTicker(ctx, req)
+	})
+}
+
+// GetTickers implements LunoClient
+func (c *RecordingClient) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	return record(ctx, c, "GetTickers", req, func() (*luno.GetTickersResponse, error) {
+		return c.next.GetTickers(ctx, req)
+	})
+}
+
+// GetOrderBook implements LunoClient
+func (c *RecordingClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	return record(ctx, c, "GetOrderBook", req, func() (*luno.GetOrderBookResponse, error) {
+		return c.next.GetOrderBook(ctx, req)
+	})
+}
+
+// GetCandles implements LunoClient
+func (c *RecordingClient) GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	return record(ctx, c, "GetCandles", req, func() (*luno.GetCandlesResponse, error) {
+		return c.next.GetCandles(ctx, req)
+	})
+}
+
+// GetFeeInfo implements LunoClient
+func (c *RecordingClient) GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	return record(ctx, c, "GetFeeInfo", req, func() (*luno.GetFeeInfoResponse, error) {
+		return c.next.GetFeeInfo(ctx, req)
+	})
+}
+
+// GetFundingAddress implements LunoClient
+func (c *RecordingClient) GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	return record(ctx, c, "GetFundingAddress", req, func() (*luno.GetFundingAddressResponse, error) {
+		return c.next.GetFundingAddress(ctx, req)
+	})
+}
+
+// Validate implements LunoClient
+func (c *RecordingClient) Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	return record(ctx, c, "Validate", req, func() (*luno.ValidateResponse, error) {
+		return c.next.Validate(ctx, req)
+	})
+}
+
+// GetOrderV3 implements LunoClient
+func (c *RecordingClient) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	return record(ctx, c, "GetOrderV3", req, func() (*luno.GetOrderV3Response, error) {
+		return c.next.GetOrderV3(ctx, req)
+	})
+}
+
+// PostLimitOrder implements LunoClient
+func (c *RecordingClient) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	return record(ctx, c, "PostLimitOrder", req, func() (*luno.PostLimitOrderResponse, error) {
+		return c.next.PostLimitOrder(ctx, req)
+	})
+}
+
+// StopOrder implements LunoClient
+func (c *RecordingClient) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	return record(ctx, c, "StopOrder", req, func() (*luno.StopOrderResponse, error) {
+		return c.next.StopOrder(ctx, req)
+	})
+}
+
+// ListOrders implements LunoClient
+func (c *RecordingClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	return record(ctx, c, "ListOrders", req, func() (*luno.ListOrdersResponse, error) {
+		return c.next.ListOrders(ctx, req)
+	})
+}
+
+// ListTransactions implements LunoClient
+func (c *RecordingClient) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	return record(ctx, c, "ListTransactions", req, func() (*luno.ListTransactionsResponse, error) {
+		return c.next.ListTransactions(ctx, req)
+	})
+}
+
+// ListPendingTransactions implements LunoClient
+func (c *RecordingClient) ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	return record(ctx, c, "ListPendingTransactions", req, func() (*luno.ListPendingTransactionsResponse, error) {
+		return c.next.ListPendingTransactions(ctx, req)
+	})
+}
+
+// ListTrades implements LunoClient
+func (c *RecordingClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	return record(ctx, c, "ListTrades", req, func() (*luno.ListTradesResponse, error) {
+		return c.next.ListTrades(ctx, req)
+	})
+}
+
+// ListUserTrades implements LunoClient
+func (c *RecordingClient) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	return record(ctx, c, "ListUserTrades", req, func() (*luno.ListUserTradesResponse, error) {
+		return c.next.ListUserTrades(ctx, req)
+	})
+}
+
+// Markets implements LunoClient
+func (c *RecordingClient) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	return record(ctx, c, "Markets", req, func() (*luno.MarketsResponse, error) {
+		return c.next.Markets(ctx, req)
+	})
+}
+
+// GetBalances implements LunoClient
+func (c *ReplayClient) GetBalances(_ context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	return replay[*luno.GetBalancesResponse](c, "GetBalances", req)
+}
+
+// CreateAccount implements LunoClient
+func (c *ReplayClient) CreateAccount(_ context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return replay[*luno.CreateAccountResponse](c, "CreateAccount", req)
+}
+
+// Move implements LunoClient
+func (c *ReplayClient) Move(_ context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return replay[*luno.MoveResponse](c, "Move", req)
+}
+
+// GetMove implements LunoClient
+func (c *ReplayClient) GetMove(_ context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return replay[*luno.GetMoveResponse](c, "GetMove", req)
+}
+
+// GetTicker implements LunoClient
+func (c *ReplayClient) GetTicker(_ context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	return replay[*luno.GetTickerResponse](c, "GetTicker", req)
+}
+
+// GetTickers implements LunoClient
+func (c *ReplayClient) GetTickers(_ context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	return replay[*luno.GetTickersResponse](c, "GetTickers", req)
+}
+
+// GetOrderBook implements LunoClient
+func (c *ReplayClient) GetOrderBook(_ context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	return replay[*luno.GetOrderBookResponse](c, "GetOrderBook", req)
+}
+
+// GetCandles implements LunoClient
+func (c *ReplayClient) GetCandles(_ context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	return replay[*luno.GetCandlesResponse](c, "GetCandles", req)
+}
+
+// GetFeeInfo implements LunoClient
+func (c *ReplayClient) GetFeeInfo(_ context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	return replay[*luno.GetFeeInfoResponse](c, "GetFeeInfo", req)
+}
+
+// GetFundingAddress implements LunoClient
+func (c *ReplayClient) GetFundingAddress(_ context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	return replay[*luno.GetFundingAddressResponse](c, "GetFundingAddress", req)
+}
+
+// Validate implements LunoClient
+func (c *ReplayClient) Validate(_ context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	return replay[*luno.ValidateResponse](c, "Validate", req)
+}
+
+// GetOrderV3 implements LunoClient
+func (c *ReplayClient) GetOrderV3(_ context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	return replay[*luno.GetOrderV3Response](c, "GetOrderV3", req)
+}
+
+// PostLimitOrder implements LunoClient
+func (c *ReplayClient) PostLimitOrder(_ context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	return replay[*luno.PostLimitOrderResponse](c, "PostLimitOrder", req)
+}
+
+// StopOrder implements LunoClient
+func (c *ReplayClient) StopOrder(_ context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	return replay[*luno.StopOrderResponse](c, "StopOrder", req)
+}
+
+// ListOrders implements LunoClient
+func (c *ReplayClient) ListOrders(_ context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	return replay[*luno.ListOrdersResponse](c, "ListOrders", req)
+}
+
+// ListTransactions implements LunoClient
+func (c *ReplayClient) ListTransactions(_ context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	return replay[*luno.ListTransactionsResponse](c, "ListTransactions", req)
+}
+
+// ListPendingTransactions implements LunoClient
+func (c *ReplayClient) ListPendingTransactions(_ context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	return replay[*luno.ListPendingTransactionsResponse](c, "ListPendingTransactions", req)
+}
+
+// ListTrades implements LunoClient
+func (c *ReplayClient) ListTrades(_ context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	return replay[*luno.ListTradesResponse](c, "ListTrades", req)
+}
+
+// ListUserTrades implements LunoClient
+func (c *ReplayClient) ListUserTrades(_ context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	return replay[*luno.ListUserTradesResponse](c, "ListUserTrades", req)
+}
+
+// Markets implements LunoClient
+func (c *ReplayClient) Markets(_ context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	return replay[*luno.MarketsResponse](c, "Markets", req)
+}
@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/luno/luno-go/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	marketErr := luno.Error{Code: "ErrMarketUnavailable", Message: "market unavailable"}
+
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
+		Return(&luno.GetTickerResponse{Pair: "XBTZAR", LastTrade: decimal.NewFromInt64(1000)}, nil).Once()
+	mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "XBTZAR"}).
+		Return(&luno.GetTickerResponse{Pair: "XBTZAR", LastTrade: decimal.NewFromInt64(1001)}, nil).Once()
+	mockClient.EXPECT().GetTicker(mock.Anything, &luno.GetTickerRequest{Pair: "ETHZAR"}).
+		Return(nil, marketErr).Once()
+	mockClient.EXPECT().GetCandles(mock.Anything, mock.Anything).
+		Return(&luno.GetCandlesResponse{Pair: "XBTZAR", Duration: 300}, nil).Once()
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection reset")).Once()
+
+	recorder, err := NewRecordingClient(mockClient, path)
+	require.NoError(t, err)
+	for _, last := range []int64{1000, 1001} {
+		res, err := recorder.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+		require.NoError(t, err)
+		assert.Zero(t, res.LastTrade.Cmp(decimal.NewFromInt64(last)))
+	}
+	_, err = recorder.GetTicker(ctx, &luno.GetTickerRequest{Pair: "ETHZAR"})
+	assert.Equal(t, marketErr, err)
+	_, err = recorder.GetCandles(ctx, &luno.GetCandlesRequest{Pair: "XBTZAR", Duration: 300, Since: luno.Time(time.Now().Add(-time.Hour))})
+	require.NoError(t, err)
+	_, err = recorder.GetBalances(ctx, &luno.GetBalancesRequest{})
+	require.Error(t, err)
+	require.NoError(t, recorder.Close())
+
+	replayer, err := NewReplayClient(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5, replayer.Len())
+
+	// Responses to the same request come back in order, then the last repeats
+	for _, last := range []int64{1000, 1001, 1001} {
+		res, err := replayer.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+		require.NoError(t, err)
+		assert.Equal(t, "XBTZAR", res.Pair)
+		assert.Zero(t, res.LastTrade.Cmp(decimal.NewFromInt64(last)))
+	}
+
+	_, err = replayer.GetTicker(ctx, &luno.GetTickerRequest{Pair: "ETHZAR"})
+	assert.Equal(t, marketErr, err, "Luno errors keep their code")
+	_, err = replayer.GetBalances(ctx, &luno.GetBalancesRequest{})
+	assert.EqualError(t, err, "connection reset")
+
+	// Times are ignored, since they move on between runs
+	candles, err := replayer.GetCandles(ctx, &luno.GetCandlesRequest{Pair: "XBTZAR", Duration: 300, Since: luno.Time(time.Now())})
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), candles.Duration)
+
+	_, err = replayer.GetCandles(ctx, &luno.GetCandlesRequest{Pair: "XBTZAR", Duration: 60})
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	_, err = replayer.Markets(ctx, &luno.MarketsRequest{})
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestRecordingSkipsCancelledCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(nil, context.Canceled).Once()
+
+	recorder, err := NewRecordingClient(mockClient, path)
+	require.NoError(t, err)
+	_, err = recorder.GetTicker(ctx, &luno.GetTickerRequest{Pair: "XBTZAR"})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NoError(t, recorder.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestNewReplayClientErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name          string
+		data          string
+		expectedError string
+	}{
+		{name: "invalid json", data: "{\n", expectedError: "calls.jsonl:1"},
+		{name: "unknown method", data: `{"method":"Withdraw","request":{}}`, expectedError: `unknown method "Withdraw"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "calls.jsonl")
+			require.NoError(t, os.WriteFile(path, []byte(tc.data), 0o600))
+			_, err := NewReplayClient(path)
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+
+	_, err := NewReplayClient(filepath.Join(dir, "missing.jsonl"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
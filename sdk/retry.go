@@ -0,0 +1,343 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/luno/luno-go"
+	"golang.org/x/time/rate"
+)
+
+// compile-time check that *RetryingClient implements our interface
+var _ LunoClient = (*RetryingClient)(nil)
+
+// endpointClass groups Luno API endpoints that share a rate limit
+type endpointClass int
+
+const (
+	// classMarket covers public market data endpoints
+	classMarket endpointClass = iota
+	// classAccount covers private read endpoints
+	classAccount
+	// classTrading covers endpoints that place or stop orders, create accounts or move funds
+	classTrading
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed call is retried
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay is the delay before the first retry, doubled for each retry after that
+	DefaultRetryBaseDelay = 250 * time.Millisecond
+	// DefaultRetryMaxDelay caps the delay between retries
+	DefaultRetryMaxDelay = 5 * time.Second
+)
+
+// defaultLimits are the token bucket rates per endpoint class, so that one
+// class of calls can't use up the whole budget of the others. Their sum is
+// more than Luno allows, so every call also takes a token from keyLimit.
+var defaultLimits = map[endpointClass]struct {
+	every time.Duration
+	burst int
+}{
+	classMarket:  {every: time.Minute / 240, burst: 5},
+	classAccount: {every: time.Minute / 240, burst: 5},
+	classTrading: {every: time.Minute / 120, burst: 2},
+}
+
+// keyLimit is the token bucket rate shared by every call made with one API
+// key, kept below Luno's published limit of 300 requests per minute per key
+var keyLimit = struct {
+	every time.Duration
+	burst int
+}{every: time.Minute / 270, burst: 10}
+
+// endpointClassNames name the endpoint classes for RateLimit
+var endpointClassNames = map[endpointClass]string{
+	classMarket:  "market",
+	classAccount: "account",
+	classTrading: "trading",
+}
+
+// RateLimit is the client side rate limit of a class of Luno API endpoints
+type RateLimit struct {
+	Endpoints string `json:"endpoints"`
+	PerMinute int    `json:"per_minute"`
+	Burst     int    `json:"burst"`
+}
+
+// DefaultRateLimits returns the rate limits a RetryingClient enforces, for
+// market data, account reads and trading endpoints in that order, followed
+// by the limit all endpoints share
+func DefaultRateLimits() []RateLimit {
+	limits := make([]RateLimit, 0, len(defaultLimits)+1)
+	for _, class := range []endpointClass{classMarket, classAccount, classTrading} {
+		l := defaultLimits[class]
+		limits = append(limits, RateLimit{
+			Endpoints: endpointClassNames[class],
+			PerMinute: int(time.Minute / l.every),
+			Burst:     l.burst,
+		})
+	}
+	return append(limits, RateLimit{
+		Endpoints: "all",
+		PerMinute: int(time.Minute / keyLimit.every),
+		Burst:     keyLimit.burst,
+	})
+}
+
+// RetryingClient wraps a LunoClient, enforcing client side rate limits and
+// retrying transient failures with exponential backoff and jitter.
+//
+// Reads are retried on rate limiting, server errors and network errors.
+// Order placement and stopping, and account creation, are only retried on rate
+// limiting, where the API has rejected the request, so that an order or
+// account is never created twice.
+type RetryingClient struct {
+	next       LunoClient
+	limiters   map[endpointClass]*rate.Limiter
+	keyLimiter *rate.Limiter
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingClient wraps next with the default rate limits and retry policy
+func NewRetryingClient(next LunoClient) *RetryingClient {
+	limiters := make(map[endpointClass]*rate.Limiter, len(defaultLimits))
+	for class, l := range defaultLimits {
+		limiters[class] = rate.NewLimiter(rate.Every(l.every), l.burst)
+	}
+	return &RetryingClient{
+		next:       next,
+		limiters:   limiters,
+		keyLimiter: rate.NewLimiter(rate.Every(keyLimit.every), keyLimit.burst),
+		maxRetries: DefaultMaxRetries,
+		baseDelay:  DefaultRetryBaseDelay,
+		maxDelay:   DefaultRetryMaxDelay,
+		sleep:      sleepContext,
+	}
+}
+
+// call runs fn with rate limiting and retries. idempotent calls are also
+// retried on server and network errors.
+func call[T any](ctx context.Context, c *RetryingClient, class endpointClass, name string, idempotent bool, fn func() (T, error)) (T, error) {
+	var zero T
+	for attempt := 0; ; attempt++ {
+		if err := c.limiters[class].Wait(ctx); err != nil {
+			return zero, err
+		}
+		if err := c.keyLimiter.Wait(ctx); err != nil {
+			return zero, err
+		}
+
+		res, err := fn()
+		if err == nil {
+			return res, nil
+		}
+		if attempt >= c.maxRetries || !isRetryable(err, idempotent) {
+			return zero, err
+		}
+
+		delay := c.backoff(attempt)
+		slog.Warn("Retrying Luno API call",
+			slog.String("method", name),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()))
+		if err := c.sleep(ctx, delay); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// backoff returns a random delay up to the exponential backoff for the attempt
+func (c *RetryingClient) backoff(attempt int) time.Duration {
+	d := c.baseDelay << attempt
+	if d <= 0 || d > c.maxDelay {
+		d = c.maxDelay
+	}
+	// Full jitter, keeping at least half the delay so retries are not immediate
+	return d/2 + rand.N(d/2+1)
+}
+
+// isRetryable reports whether err is a transient failure worth retrying
+func isRetryable(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "too many requests") {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+	// Structured API errors, like invalid parameters, fail the same way again
+	var lunoErr luno.Error
+	if errors.As(err, &lunoErr) {
+		return false
+	}
+	// luno-go reports non-JSON error responses as "error decoding response (<status> ...)"
+	if strings.Contains(msg, "error decoding response (5") {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// GetBalances implements LunoClient
+func (c *RetryingClient) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	return call(ctx, c, classAccount, "GetBalances", true, func() (*luno.GetBalancesResponse, error) {
+		return c.next.GetBalances(ctx, req)
+	})
+}
+
+// CreateAccount implements LunoClient
+func (c *RetryingClient) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return call(ctx, c, classTrading, "CreateAccount", false, func() (*luno.CreateAccountResponse, error) {
+		return c.next.CreateAccount(ctx, req)
+	})
+}
+
+// Move implements LunoClient
+func (c *RetryingClient) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return call(ctx, c, classTrading, "Move", false, func() (*luno.MoveResponse, error) {
+		return c.next.Move(ctx, req)
+	})
+}
+
+// GetMove implements LunoClient
+func (c *RetryingClient) GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return call(ctx, c, classAccount, "GetMove", true, func() (*luno.GetMoveResponse, error) {
+		return c.next.GetMove(ctx, req)
+	})
+}
+
+// GetTicker implements LunoClient
+func (c *RetryingClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	return call(ctx, c, classMarket, "GetTicker", true, func() (*luno.GetTickerResponse, error) {
+		return c.next.GetTicker(ctx, req)
+	})
+}
+
+// GetTickers implements LunoClient
+func (c *RetryingClient) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	return call(ctx, c, classMarket, "GetTickers", true, func() (*luno.GetTickersResponse, error) {
+		return c.next.GetTickers(ctx, req)
+	})
+}
+
+// GetOrderBook implements LunoClient
+func (c *RetryingClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	return call(ctx, c, classMarket, "GetOrderBook", true, func() (*luno.GetOrderBookResponse, error) {
+		return c.next.GetOrderBook(ctx, req)
+	})
+}
+
+// GetCandles implements LunoClient
+func (c *RetryingClient) GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	return call(ctx, c, classMarket, "GetCandles", true, func() (*luno.GetCandlesResponse, error) {
+		return c.next.GetCandles(ctx, req)
+	})
+}
+
+// GetFeeInfo implements LunoClient
+func (c *RetryingClient) GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	return call(ctx, c, classAccount, "GetFeeInfo", true, func() (*luno.GetFeeInfoResponse, error) {
+		return c.next.GetFeeInfo(ctx, req)
+	})
+}
+
+// GetFundingAddress implements LunoClient
+func (c *RetryingClient) GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	return call(ctx, c, classAccount, "GetFundingAddress", true, func() (*luno.GetFundingAddressResponse, error) {
+		return c.next.GetFundingAddress(ctx, req)
+	})
+}
+
+// Validate implements LunoClient
+func (c *RetryingClient) Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	return call(ctx, c, classAccount, "Validate", true, func() (*luno.ValidateResponse, error) {
+		return c.next.Validate(ctx, req)
+	})
+}
+
+// GetOrderV3 implements LunoClient
+func (c *RetryingClient) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	return call(ctx, c, classAccount, "GetOrderV3", true, func() (*luno.GetOrderV3Response, error) {
+		return c.next.GetOrderV3(ctx, req)
+	})
+}
+
+// PostLimitOrder implements LunoClient
+func (c *RetryingClient) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	return call(ctx, c, classTrading, "PostLimitOrder", false, func() (*luno.PostLimitOrderResponse, error) {
+		return c.next.PostLimitOrder(ctx, req)
+	})
+}
+
+// StopOrder implements LunoClient
+func (c *RetryingClient) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	return call(ctx, c, classTrading, "StopOrder", false, func() (*luno.StopOrderResponse, error) {
+		return c.next.StopOrder(ctx, req)
+	})
+}
+
+// ListOrders implements LunoClient
+func (c *RetryingClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	return call(ctx, c, classAccount, "ListOrders", true, func() (*luno.ListOrdersResponse, error) {
+		return c.next.ListOrders(ctx, req)
+	})
+}
+
+// ListTransactions implements LunoClient
+func (c *RetryingClient) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	return call(ctx, c, classAccount, "ListTransactions", true, func() (*luno.ListTransactionsResponse, error) {
+		return c.next.ListTransactions(ctx, req)
+	})
+}
+
+// ListPendingTransactions implements LunoClient
+func (c *RetryingClient) ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	return call(ctx, c, classAccount, "ListPendingTransactions", true, func() (*luno.ListPendingTransactionsResponse, error) {
+		return c.next.ListPendingTransactions(ctx, req)
+	})
+}
+
+// ListTrades implements LunoClient
+func (c *RetryingClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	return call(ctx, c, classMarket, "ListTrades", true, func() (*luno.ListTradesResponse, error) {
+		return c.next.ListTrades(ctx, req)
+	})
+}
+
+// ListUserTrades implements LunoClient
+func (c *RetryingClient) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	return call(ctx, c, classAccount, "ListUserTrades", true, func() (*luno.ListUserTradesResponse, error) {
+		return c.next.ListUserTrades(ctx, req)
+	})
+}
+
+// Markets implements LunoClient
+func (c *RetryingClient) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	return call(ctx, c, classMarket, "Markets", true, func() (*luno.MarketsResponse, error) {
+		return c.next.Markets(ctx, req)
+	})
+}
@@ -0,0 +1,170 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/luno/luno-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+var (
+	errTooManyRequests = errors.New("luno: too many requests")
+	errServer          = errors.New("luno: error decoding response (502 Bad Gateway)")
+	errNetwork         = &net.OpError{Op: "read", Err: errors.New("connection reset")}
+)
+
+func newTestRetryingClient(next LunoClient) (*RetryingClient, *[]time.Duration) {
+	c := NewRetryingClient(next)
+	for class := range c.limiters {
+		c.limiters[class] = rate.NewLimiter(rate.Inf, 1)
+	}
+	c.keyLimiter = rate.NewLimiter(rate.Inf, 1)
+	var sleeps []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return c, &sleeps
+}
+
+func TestRetryingClientReads(t *testing.T) {
+	tests := []struct {
+		name            string
+		errs            []error
+		expectedCalls   int
+		expectedErr     error
+		expectedRetries int
+	}{
+		{name: "success", errs: []error{nil}, expectedCalls: 1},
+		{name: "retries rate limiting", errs: []error{errTooManyRequests, nil}, expectedCalls: 2, expectedRetries: 1},
+		{name: "retries server errors", errs: []error{errServer, errServer, nil}, expectedCalls: 3, expectedRetries: 2},
+		{name: "retries network errors", errs: []error{errNetwork, nil}, expectedCalls: 2, expectedRetries: 1},
+		{
+			name:            "gives up after max retries",
+			errs:            []error{errServer, errServer, errServer, errServer},
+			expectedCalls:   4,
+			expectedErr:     errServer,
+			expectedRetries: 3,
+		},
+		{
+			name:          "does not retry api errors",
+			errs:          []error{luno.Error{Code: "ErrMarketUnavailable", Message: "market unavailable"}},
+			expectedCalls: 1,
+			expectedErr:   luno.Error{Code: "ErrMarketUnavailable", Message: "market unavailable"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := NewMockLunoClient(t)
+			for _, err := range tc.errs[:tc.expectedCalls] {
+				var res *luno.GetTickerResponse
+				if err == nil {
+					res = &luno.GetTickerResponse{Pair: "XBTZAR"}
+				}
+				mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(res, err).Once()
+			}
+
+			c, sleeps := newTestRetryingClient(mockClient)
+			res, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+			assert.Len(t, *sleeps, tc.expectedRetries)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "XBTZAR", res.Pair)
+		})
+	}
+}
+
+func TestRetryingClientOrderPlacement(t *testing.T) {
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{name: "retries rate limiting", errs: []error{errTooManyRequests, nil}, expectedCalls: 2},
+		{name: "does not retry server errors", errs: []error{errServer}, expectedCalls: 1, expectedErr: errServer},
+		{name: "does not retry network errors", errs: []error{errNetwork}, expectedCalls: 1, expectedErr: errNetwork},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := NewMockLunoClient(t)
+			for _, err := range tc.errs {
+				var res *luno.PostLimitOrderResponse
+				if err == nil {
+					res = &luno.PostLimitOrderResponse{OrderId: "BXMC2CJ7HNB88U4"}
+				}
+				mockClient.EXPECT().PostLimitOrder(mock.Anything, mock.Anything).Return(res, err).Once()
+			}
+
+			c, _ := newTestRetryingClient(mockClient)
+			res, err := c.PostLimitOrder(context.Background(), &luno.PostLimitOrderRequest{Pair: "XBTZAR"})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "BXMC2CJ7HNB88U4", res.OrderId)
+		})
+	}
+}
+
+func TestRetryingClientContextCancelled(t *testing.T) {
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(nil, errServer).Once()
+
+	c, _ := newTestRetryingClient(mockClient)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	_, err := c.GetBalances(ctx, &luno.GetBalancesRequest{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRetryingClientBackoff(t *testing.T) {
+	c := NewRetryingClient(nil)
+	for attempt := 0; attempt < 10; attempt++ {
+		expected := min(DefaultRetryBaseDelay<<attempt, DefaultRetryMaxDelay)
+		d := c.backoff(attempt)
+		assert.GreaterOrEqual(t, d, expected/2)
+		assert.LessOrEqual(t, d, expected)
+	}
+}
+
+func TestDefaultRateLimits(t *testing.T) {
+	assert.Equal(t, []RateLimit{
+		{Endpoints: "market", PerMinute: 240, Burst: 5},
+		{Endpoints: "account", PerMinute: 240, Burst: 5},
+		{Endpoints: "trading", PerMinute: 120, Burst: 2},
+		{Endpoints: "all", PerMinute: 270, Burst: 10},
+	}, DefaultRateLimits())
+}
+
+func TestRetryingClientSharesKeyLimit(t *testing.T) {
+	mockClient := NewMockLunoClient(t)
+	mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{}, nil)
+	c, _ := newTestRetryingClient(mockClient)
+	c.keyLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	// The classes have tokens left, but the key's budget is used up
+	_, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetBalances(ctx, &luno.GetBalancesRequest{})
+	assert.Error(t, err, "GetBalances should wait for the key's budget")
+}
@@ -0,0 +1,212 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/luno/luno-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// compile-time check that *TracingClient implements our interface
+var _ LunoClient = (*TracingClient)(nil)
+
+// Span attributes of traced Luno API calls
+const (
+	// TraceMethodKey is the LunoClient method called
+	TraceMethodKey = attribute.Key("luno.method")
+	// TracePairKey is the market pair of the request, if it has one
+	TracePairKey = attribute.Key("luno.pair")
+	// TraceErrorCodeKey is the code of a Luno API error
+	TraceErrorCodeKey = attribute.Key("luno.error_code")
+)
+
+// TracingClient records each call to the next client as an OpenTelemetry
+// span, with the method, the pair and how the call failed
+type TracingClient struct {
+	next   LunoClient
+	tracer trace.Tracer
+}
+
+// NewTracingClient traces calls to next with tracer
+func NewTracingClient(next LunoClient, tracer trace.Tracer) *TracingClient {
+	return &TracingClient{next: next, tracer: tracer}
+}
+
+// traced calls fn in a span named after the method
+func traced[T any](ctx context.Context, c *TracingClient, method string, req any, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := c.tracer.Start(ctx, "luno."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(TraceMethodKey.String(method)))
+	defer span.End()
+	if pair := requestPair(req); pair != "" {
+		span.SetAttributes(TracePairKey.String(pair))
+	}
+
+	res, err := fn(ctx)
+	if err != nil {
+		var lunoErr luno.Error
+		if errors.As(err, &lunoErr) {
+			span.SetAttributes(TraceErrorCodeKey.String(lunoErr.Code))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
+
+// requestPair returns the Pair field of a request, or an empty string for
+// requests without one
+func requestPair(req any) string {
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("Pair"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// GetBalances implements LunoClient
+func (c *TracingClient) GetBalances(ctx context.Context, req *luno.GetBalancesRequest) (*luno.GetBalancesResponse, error) {
+	return traced(ctx, c, "GetBalances", req, func(ctx context.Context) (*luno.GetBalancesResponse, error) {
+		return c.next.GetBalances(ctx, req)
+	})
+}
+
+// CreateAccount implements LunoClient
+func (c *TracingClient) CreateAccount(ctx context.Context, req *luno.CreateAccountRequest) (*luno.CreateAccountResponse, error) {
+	return traced(ctx, c, "CreateAccount", req, func(ctx context.Context) (*luno.CreateAccountResponse, error) {
+		return c.next.CreateAccount(ctx, req)
+	})
+}
+
+// Move implements LunoClient
+func (c *TracingClient) Move(ctx context.Context, req *luno.MoveRequest) (*luno.MoveResponse, error) {
+	return traced(ctx, c, "Move", req, func(ctx context.Context) (*luno.MoveResponse, error) {
+		return c.next.Move(ctx, req)
+	})
+}
+
+// GetMove implements LunoClient
+func (c *TracingClient) GetMove(ctx context.Context, req *luno.GetMoveRequest) (*luno.GetMoveResponse, error) {
+	return traced(ctx, c, "GetMove", req, func(ctx context.Context) (*luno.GetMoveResponse, error) {
+		return c.next.GetMove(ctx, req)
+	})
+}
+
+// GetTicker implements LunoClient
+func (c *TracingClient) GetTicker(ctx context.Context, req *luno.GetTickerRequest) (*luno.GetTickerResponse, error) {
+	return traced(ctx, c, "GetTicker", req, func(ctx context.Context) (*luno.GetTickerResponse, error) {
+		return c.next.GetTicker(ctx, req)
+	})
+}
+
+// GetTickers implements LunoClient
+func (c *TracingClient) GetTickers(ctx context.Context, req *luno.GetTickersRequest) (*luno.GetTickersResponse, error) {
+	return traced(ctx, c, "GetTickers", req, func(ctx context.Context) (*luno.GetTickersResponse, error) {
+		return c.next.GetTickers(ctx, req)
+	})
+}
+
+// GetOrderBook implements LunoClient
+func (c *TracingClient) GetOrderBook(ctx context.Context, req *luno.GetOrderBookRequest) (*luno.GetOrderBookResponse, error) {
+	return traced(ctx, c, "GetOrderBook", req, func(ctx context.Context) (*luno.GetOrderBookResponse, error) {
+		return c.next.GetOrderBook(ctx, req)
+	})
+}
+
+// GetCandles implements LunoClient
+func (c *TracingClient) GetCandles(ctx context.Context, req *luno.GetCandlesRequest) (*luno.GetCandlesResponse, error) {
+	return traced(ctx, c, "GetCandles", req, func(ctx context.Context) (*luno.GetCandlesResponse, error) {
+		return c.next.GetCandles(ctx, req)
+	})
+}
+
+// GetFeeInfo implements LunoClient
+func (c *TracingClient) GetFeeInfo(ctx context.Context, req *luno.GetFeeInfoRequest) (*luno.GetFeeInfoResponse, error) {
+	return traced(ctx, c, "GetFeeInfo", req, func(ctx context.Context) (*luno.GetFeeInfoResponse, error) {
+		return c.next.GetFeeInfo(ctx, req)
+	})
+}
+
+// GetFundingAddress implements LunoClient
+func (c *TracingClient) GetFundingAddress(ctx context.Context, req *luno.GetFundingAddressRequest) (*luno.GetFundingAddressResponse, error) {
+	return traced(ctx, c, "GetFundingAddress", req, func(ctx context.Context) (*luno.GetFundingAddressResponse, error) {
+		return c.next.GetFundingAddress(ctx, req)
+	})
+}
+
+// Validate implements LunoClient
+func (c *TracingClient) Validate(ctx context.Context, req *luno.ValidateRequest) (*luno.ValidateResponse, error) {
+	return traced(ctx, c, "Validate", req, func(ctx context.Context) (*luno.ValidateResponse, error) {
+		return c.next.Validate(ctx, req)
+	})
+}
+
+// GetOrderV3 implements LunoClient
+func (c *TracingClient) GetOrderV3(ctx context.Context, req *luno.GetOrderV3Request) (*luno.GetOrderV3Response, error) {
+	return traced(ctx, c, "GetOrderV3", req, func(ctx context.Context) (*luno.GetOrderV3Response, error) {
+		return c.next.GetOrderV3(ctx, req)
+	})
+}
+
+// PostLimitOrder implements LunoClient
+func (c *TracingClient) PostLimitOrder(ctx context.Context, req *luno.PostLimitOrderRequest) (*luno.PostLimitOrderResponse, error) {
+	return traced(ctx, c, "PostLimitOrder", req, func(ctx context.Context) (*luno.PostLimitOrderResponse, error) {
+		return c.next.PostLimitOrder(ctx, req)
+	})
+}
+
+// StopOrder implements LunoClient
+func (c *TracingClient) StopOrder(ctx context.Context, req *luno.StopOrderRequest) (*luno.StopOrderResponse, error) {
+	return traced(ctx, c, "StopOrder", req, func(ctx context.Context) (*luno.StopOrderResponse, error) {
+		return c.next.StopOrder(ctx, req)
+	})
+}
+
+// ListOrders implements LunoClient
+func (c *TracingClient) ListOrders(ctx context.Context, req *luno.ListOrdersRequest) (*luno.ListOrdersResponse, error) {
+	return traced(ctx, c, "ListOrders", req, func(ctx context.Context) (*luno.ListOrdersResponse, error) {
+		return c.next.ListOrders(ctx, req)
+	})
+}
+
+// ListTransactions implements LunoClient
+func (c *TracingClient) ListTransactions(ctx context.Context, req *luno.ListTransactionsRequest) (*luno.ListTransactionsResponse, error) {
+	return traced(ctx, c, "ListTransactions", req, func(ctx context.Context) (*luno.ListTransactionsResponse, error) {
+		return c.next.ListTransactions(ctx, req)
+	})
+}
+
+// ListPendingTransactions implements LunoClient
+func (c *TracingClient) ListPendingTransactions(ctx context.Context, req *luno.ListPendingTransactionsRequest) (*luno.ListPendingTransactionsResponse, error) {
+	return traced(ctx, c, "ListPendingTransactions", req, func(ctx context.Context) (*luno.ListPendingTransactionsResponse, error) {
+		return c.next.ListPendingTransactions(ctx, req)
+	})
+}
+
+// ListTrades implements LunoClient
+func (c *TracingClient) ListTrades(ctx context.Context, req *luno.ListTradesRequest) (*luno.ListTradesResponse, error) {
+	return traced(ctx, c, "ListTrades", req, func(ctx context.Context) (*luno.ListTradesResponse, error) {
+		return c.next.ListTrades(ctx, req)
+	})
+}
+
+// ListUserTrades implements LunoClient
+func (c *TracingClient) ListUserTrades(ctx context.Context, req *luno.ListUserTradesRequest) (*luno.ListUserTradesResponse, error) {
+	return traced(ctx, c, "ListUserTrades", req, func(ctx context.Context) (*luno.ListUserTradesResponse, error) {
+		return c.next.ListUserTrades(ctx, req)
+	})
+}
+
+// Markets implements LunoClient
+func (c *TracingClient) Markets(ctx context.Context, req *luno.MarketsRequest) (*luno.MarketsResponse, error) {
+	return traced(ctx, c, "Markets", req, func(ctx context.Context) (*luno.MarketsResponse, error) {
+		return c.next.Markets(ctx, req)
+	})
+}
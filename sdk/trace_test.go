@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luno/luno-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingClient(t *testing.T) {
+	marketErr := luno.Error{Code: "ErrMarketUnavailable", Message: "market unavailable"}
+	tests := []struct {
+		name          string
+		call          func(c LunoClient) error
+		expectedName  string
+		expectedAttrs []attribute.KeyValue
+		expectedCode  codes.Code
+	}{
+		{
+			name: "pair is recorded",
+			call: func(c LunoClient) error {
+				_, err := c.GetTicker(context.Background(), &luno.GetTickerRequest{Pair: "XBTZAR"})
+				return err
+			},
+			expectedName:  "luno.GetTicker",
+			expectedAttrs: []attribute.KeyValue{TraceMethodKey.String("GetTicker"), TracePairKey.String("XBTZAR")},
+		},
+		{
+			name: "request without a pair",
+			call: func(c LunoClient) error {
+				_, err := c.GetBalances(context.Background(), &luno.GetBalancesRequest{})
+				return err
+			},
+			expectedName:  "luno.GetBalances",
+			expectedAttrs: []attribute.KeyValue{TraceMethodKey.String("GetBalances")},
+		},
+		{
+			name: "luno errors are recorded with their code",
+			call: func(c LunoClient) error {
+				_, err := c.GetOrderBook(context.Background(), &luno.GetOrderBookRequest{Pair: "ETHZAR"})
+				return err
+			},
+			expectedName: "luno.GetOrderBook",
+			expectedAttrs: []attribute.KeyValue{
+				TraceMethodKey.String("GetOrderBook"), TracePairKey.String("ETHZAR"), TraceErrorCodeKey.String("ErrMarketUnavailable"),
+			},
+			expectedCode: codes.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := NewMockLunoClient(t)
+			mockClient.EXPECT().GetTicker(mock.Anything, mock.Anything).Return(&luno.GetTickerResponse{}, nil).Maybe()
+			mockClient.EXPECT().GetBalances(mock.Anything, mock.Anything).Return(&luno.GetBalancesResponse{}, nil).Maybe()
+			mockClient.EXPECT().GetOrderBook(mock.Anything, mock.Anything).Return(nil, marketErr).Maybe()
+
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			c := NewTracingClient(mockClient, provider.Tracer("test"))
+
+			err := tc.call(c)
+			if tc.expectedCode == codes.Error {
+				assert.Equal(t, marketErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, tc.expectedName, spans[0].Name())
+			assert.ElementsMatch(t, tc.expectedAttrs, spans[0].Attributes())
+			assert.Equal(t, tc.expectedCode, spans[0].Status().Code)
+		})
+	}
+}